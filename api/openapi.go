@@ -0,0 +1,31 @@
+// Package api holds the public contracts of the service
+package api
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// openAPISpec is the OpenAPI 3 document describing the HTTP API
+//
+//go:embed openapi.yaml
+var openAPISpec []byte
+
+// LoadOpenAPI parses and validates the embedded OpenAPI 3 document
+func LoadOpenAPI() (*openapi3.T, error) {
+	loader := openapi3.NewLoader()
+
+	doc, err := loader.LoadFromData(openAPISpec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse OpenAPI spec: %w", err)
+	}
+
+	if err := doc.Validate(context.Background()); err != nil {
+		return nil, fmt.Errorf("invalid OpenAPI spec: %w", err)
+	}
+
+	return doc, nil
+}
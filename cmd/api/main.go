@@ -17,6 +17,19 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Run migrations instead of the server: api migrate up|down|status|redo
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if len(os.Args) != 3 {
+			fmt.Fprintf(os.Stderr, "Usage: %s migrate %v\n", os.Args[0], app.MigrateCommands)
+			os.Exit(2)
+		}
+		if err := app.RunMigrate(cfg, os.Args[2], os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "Migration failed: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	// Create and initialize application
 	application, err := app.NewApp(cfg)
 	if err != nil {
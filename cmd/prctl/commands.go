@@ -0,0 +1,438 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"avito-backend-trainee-assignment-autumn-2025/pkg/client"
+	"avito-backend-trainee-assignment-autumn-2025/pkg/dto/request"
+	"avito-backend-trainee-assignment-autumn-2025/pkg/dto/response"
+)
+
+// command is a single prctl subcommand
+type command struct {
+	name  string
+	depth int
+	flags func(fs *flag.FlagSet)
+	run   func(ctx context.Context, api *client.Client, out *printer, args []string) error
+}
+
+// lookupCommand finds the command addressed by the leading arguments
+func lookupCommand(args []string) (*command, bool) {
+	for _, cmd := range commands() {
+		words := strings.Fields(cmd.name)
+		if len(args) < len(words) {
+			continue
+		}
+		matched := true
+		for i, word := range words {
+			if args[i] != word {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			cmd.depth = len(words)
+			return cmd, true
+		}
+	}
+	return nil, false
+}
+
+// commands returns all commands with freshly bound flag variables
+func commands() []*command {
+	var (
+		teamName    string
+		teamFile    string
+		teamMembers memberFlag
+
+		prID     string
+		prName   string
+		prAuthor string
+
+		listUser   string
+		listTeam   string
+		listStatus string
+	)
+
+	return []*command{
+		{
+			name: "team create",
+			flags: func(fs *flag.FlagSet) {
+				fs.StringVar(&teamName, "name", "", "team name")
+				fs.StringVar(&teamFile, "f", "", "read the request from a JSON file (- for stdin)")
+				fs.Var(&teamMembers, "member", "member as user_id:username[:inactive] (repeatable)")
+			},
+			run: func(ctx context.Context, api *client.Client, out *printer, args []string) error {
+				req, err := buildCreateTeamRequest(teamName, teamFile, teamMembers)
+				if err != nil {
+					return err
+				}
+				resp, err := api.CreateTeam(ctx, req)
+				if err != nil {
+					return err
+				}
+				return out.print(resp, teamTable(&resp.Team))
+			},
+		},
+		{
+			name: "team get",
+			run: func(ctx context.Context, api *client.Client, out *printer, args []string) error {
+				name, err := exactlyOneArg(args, "team name")
+				if err != nil {
+					return err
+				}
+				resp, err := api.GetTeam(ctx, name)
+				if err != nil {
+					return err
+				}
+				return out.print(resp, teamTable(resp))
+			},
+		},
+		{
+			name: "user activate",
+			run: func(ctx context.Context, api *client.Client, out *printer, args []string) error {
+				return setUserActive(ctx, api, out, args, true)
+			},
+		},
+		{
+			name: "user deactivate",
+			run: func(ctx context.Context, api *client.Client, out *printer, args []string) error {
+				return setUserActive(ctx, api, out, args, false)
+			},
+		},
+		{
+			name: "pr create",
+			flags: func(fs *flag.FlagSet) {
+				fs.StringVar(&prID, "id", "", "pull request ID")
+				fs.StringVar(&prName, "name", "", "pull request title")
+				fs.StringVar(&prAuthor, "author", "", "author user ID")
+			},
+			run: func(ctx context.Context, api *client.Client, out *printer, args []string) error {
+				if prID == "" || prName == "" || prAuthor == "" {
+					return fmt.Errorf("%w: -id, -name and -author are required", errUsage)
+				}
+				resp, err := api.CreatePR(ctx, &request.CreatePRRequest{
+					PullRequestID:   prID,
+					PullRequestName: prName,
+					AuthorID:        prAuthor,
+				})
+				if err != nil {
+					return err
+				}
+				return out.print(resp, pullRequestTable(&resp.PR))
+			},
+		},
+		{
+			name: "pr merge",
+			run: func(ctx context.Context, api *client.Client, out *printer, args []string) error {
+				id, err := exactlyOneArg(args, "pull request ID")
+				if err != nil {
+					return err
+				}
+				resp, err := api.MergePR(ctx, &request.MergePRRequest{PullRequestID: id})
+				if err != nil {
+					return err
+				}
+				return out.print(resp, pullRequestTable(&resp.PR))
+			},
+		},
+		{
+			name: "pr reassign",
+			run: func(ctx context.Context, api *client.Client, out *printer, args []string) error {
+				if len(args) != 2 {
+					return fmt.Errorf("%w: expected <pr_id> <old_reviewer_id>", errUsage)
+				}
+				resp, err := api.ReassignReviewer(ctx, &request.ReassignReviewerRequest{
+					PullRequestID: args[0],
+					OldUserID:     args[1],
+				})
+				if err != nil {
+					return err
+				}
+				tbl := pullRequestTable(&resp.PR)
+				tbl.rows = append(tbl.rows, []string{"replaced_by", resp.ReplacedBy})
+				return out.print(resp, tbl)
+			},
+		},
+		{
+			name: "pr list",
+			flags: func(fs *flag.FlagSet) {
+				fs.StringVar(&listUser, "user", "", "list pull requests assigned to this reviewer")
+				fs.StringVar(&listTeam, "team", "", "list pull requests reviewed by members of this team")
+				fs.StringVar(&listStatus, "status", "", "filter by status: OPEN or MERGED")
+			},
+			run: func(ctx context.Context, api *client.Client, out *printer, args []string) error {
+				if (listUser == "") == (listTeam == "") {
+					return fmt.Errorf("%w: exactly one of -user or -team is required", errUsage)
+				}
+				status := strings.ToUpper(listStatus)
+				if status != "" && status != "OPEN" && status != "MERGED" {
+					return fmt.Errorf("%w: unknown status %q", errUsage, listStatus)
+				}
+
+				var items []pullRequestListItem
+				var err error
+				if listUser != "" {
+					items, err = listUserPullRequests(ctx, api, listUser)
+				} else {
+					items, err = listTeamPullRequests(ctx, api, listTeam)
+				}
+				if err != nil {
+					return err
+				}
+
+				items = filterByStatus(items, status)
+				return out.print(items, pullRequestListTable(items))
+			},
+		},
+		{
+			name: "stats",
+			run: func(ctx context.Context, api *client.Client, out *printer, args []string) error {
+				name, err := exactlyOneArg(args, "team name")
+				if err != nil {
+					return err
+				}
+				stats, err := collectTeamStats(ctx, api, name)
+				if err != nil {
+					return err
+				}
+				return out.print(stats, statsTable(stats))
+			},
+		},
+	}
+}
+
+// setUserActive toggles the review rotation flag of a user
+func setUserActive(ctx context.Context, api *client.Client, out *printer, args []string, isActive bool) error {
+	userID, err := exactlyOneArg(args, "user ID")
+	if err != nil {
+		return err
+	}
+	resp, err := api.SetUserActive(ctx, &request.SetUserActiveRequest{UserID: userID, IsActive: isActive})
+	if err != nil {
+		return err
+	}
+	return out.print(resp, &table{
+		headers: []string{"USER_ID", "USERNAME", "TEAM", "ACTIVE"},
+		rows: [][]string{{
+			resp.User.UserID, resp.User.Username, resp.User.TeamName, strconv.FormatBool(resp.User.IsActive),
+		}},
+	})
+}
+
+// exactlyOneArg returns the single positional argument
+func exactlyOneArg(args []string, what string) (string, error) {
+	if len(args) != 1 {
+		return "", fmt.Errorf("%w: expected exactly one argument: %s", errUsage, what)
+	}
+	return args[0], nil
+}
+
+// memberFlag collects repeated -member flags
+type memberFlag []request.TeamMemberRequest
+
+// String implements flag.Value
+func (m *memberFlag) String() string {
+	parts := make([]string, 0, len(*m))
+	for _, member := range *m {
+		parts = append(parts, member.UserID)
+	}
+	return strings.Join(parts, ",")
+}
+
+// Set parses user_id:username[:inactive]
+func (m *memberFlag) Set(value string) error {
+	parts := strings.Split(value, ":")
+	if len(parts) < 2 || len(parts) > 3 || parts[0] == "" || parts[1] == "" {
+		return fmt.Errorf("expected user_id:username[:inactive], got %q", value)
+	}
+
+	member := request.TeamMemberRequest{UserID: parts[0], Username: parts[1], IsActive: true}
+	if len(parts) == 3 {
+		switch parts[2] {
+		case "inactive":
+			member.IsActive = false
+		case "active":
+		default:
+			return fmt.Errorf("expected active or inactive, got %q", parts[2])
+		}
+	}
+
+	*m = append(*m, member)
+	return nil
+}
+
+// buildCreateTeamRequest builds the request from flags or a JSON file
+func buildCreateTeamRequest(name, file string, members memberFlag) (*request.CreateTeamRequest, error) {
+	if file == "" {
+		if name == "" {
+			return nil, fmt.Errorf("%w: -name or -f is required", errUsage)
+		}
+		return &request.CreateTeamRequest{TeamName: name, Members: members}, nil
+	}
+
+	if name != "" || len(members) > 0 {
+		return nil, fmt.Errorf("%w: -f cannot be combined with -name or -member", errUsage)
+	}
+
+	var reader io.Reader = os.Stdin
+	if file != "-" {
+		f, err := os.Open(file)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		reader = f
+	}
+
+	var req request.CreateTeamRequest
+	decoder := json.NewDecoder(reader)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&req); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", file, err)
+	}
+	return &req, nil
+}
+
+// pullRequestListItem is a pull request together with the known reviewers
+type pullRequestListItem struct {
+	response.PullRequestShortResponse
+	Reviewers []string `json:"reviewers"`
+}
+
+// listUserPullRequests lists pull requests assigned to a single reviewer
+func listUserPullRequests(ctx context.Context, api *client.Client, userID string) ([]pullRequestListItem, error) {
+	resp, err := api.GetUserReviews(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]pullRequestListItem, 0, len(resp.PullRequests))
+	for _, pr := range resp.PullRequests {
+		items = append(items, pullRequestListItem{PullRequestShortResponse: pr, Reviewers: []string{userID}})
+	}
+	return items, nil
+}
+
+// listTeamPullRequests lists pull requests reviewed by any member of the team
+// Reviewers outside the team are not visible through the API and are not listed
+func listTeamPullRequests(ctx context.Context, api *client.Client, teamName string) ([]pullRequestListItem, error) {
+	team, err := api.GetTeam(ctx, teamName)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[string]*pullRequestListItem)
+	var order []string
+	for _, member := range team.Members {
+		reviews, err := api.GetUserReviews(ctx, member.UserID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get reviews of %s: %w", member.UserID, err)
+		}
+		for _, pr := range reviews.PullRequests {
+			item, ok := byID[pr.PullRequestID]
+			if !ok {
+				item = &pullRequestListItem{PullRequestShortResponse: pr}
+				byID[pr.PullRequestID] = item
+				order = append(order, pr.PullRequestID)
+			}
+			item.Reviewers = append(item.Reviewers, member.UserID)
+		}
+	}
+
+	sort.Strings(order)
+	items := make([]pullRequestListItem, 0, len(order))
+	for _, id := range order {
+		items = append(items, *byID[id])
+	}
+	return items, nil
+}
+
+// filterByStatus keeps pull requests with the given status (all if status is empty)
+func filterByStatus(items []pullRequestListItem, status string) []pullRequestListItem {
+	if status == "" {
+		return items
+	}
+	filtered := make([]pullRequestListItem, 0, len(items))
+	for _, item := range items {
+		if item.Status == status {
+			filtered = append(filtered, item)
+		}
+	}
+	return filtered
+}
+
+// teamStats is the review load of a team
+type teamStats struct {
+	TeamName           string        `json:"team_name"`
+	ActiveMembers      int           `json:"active_members"`
+	OpenPullRequests   int           `json:"open_pull_requests"`
+	MergedPullRequests int           `json:"merged_pull_requests"`
+	Members            []memberStats `json:"members"`
+}
+
+// memberStats is the review load of a single team member
+type memberStats struct {
+	UserID        string `json:"user_id"`
+	Username      string `json:"username"`
+	IsActive      bool   `json:"is_active"`
+	OpenReviews   int    `json:"open_reviews"`
+	MergedReviews int    `json:"merged_reviews"`
+}
+
+// collectTeamStats aggregates review assignments of all team members
+func collectTeamStats(ctx context.Context, api *client.Client, teamName string) (*teamStats, error) {
+	team, err := api.GetTeam(ctx, teamName)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &teamStats{TeamName: team.TeamName, Members: make([]memberStats, 0, len(team.Members))}
+	seen := make(map[string]bool)
+
+	for _, member := range team.Members {
+		reviews, err := api.GetUserReviews(ctx, member.UserID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get reviews of %s: %w", member.UserID, err)
+		}
+
+		ms := memberStats{UserID: member.UserID, Username: member.Username, IsActive: member.IsActive}
+		for _, pr := range reviews.PullRequests {
+			isNew := !seen[pr.PullRequestID]
+			seen[pr.PullRequestID] = true
+
+			switch pr.Status {
+			case "OPEN":
+				ms.OpenReviews++
+				if isNew {
+					stats.OpenPullRequests++
+				}
+			case "MERGED":
+				ms.MergedReviews++
+				if isNew {
+					stats.MergedPullRequests++
+				}
+			}
+		}
+
+		if member.IsActive {
+			stats.ActiveMembers++
+		}
+		stats.Members = append(stats.Members, ms)
+	}
+
+	// Busiest reviewers first
+	sort.SliceStable(stats.Members, func(i, j int) bool {
+		return stats.Members[i].OpenReviews > stats.Members[j].OpenReviews
+	})
+
+	return stats, nil
+}
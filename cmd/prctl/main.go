@@ -0,0 +1,150 @@
+// Command prctl is an admin CLI for the PR Reviewer Assignment Service
+//
+// It talks to the HTTP API through pkg/client and is meant for operators
+// fixing assignments by hand, e.g. during incidents.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"avito-backend-trainee-assignment-autumn-2025/pkg/client"
+)
+
+const (
+	defaultAPIURL  = "http://localhost:8080"
+	defaultTimeout = 10 * time.Second
+	retryAttempts  = 3
+	retryBackoff   = 200 * time.Millisecond
+)
+
+const usageText = `prctl - admin CLI for the PR Reviewer Assignment Service
+
+Usage:
+  prctl <command> <subcommand> [flags] [args]
+
+Commands:
+  team create -name <team> [-member <user_id:username[:inactive]>]... [-f file.json]
+  team get <team>
+  user activate <user_id>
+  user deactivate <user_id>
+  pr create -id <pr_id> -name <title> -author <user_id>
+  pr merge <pr_id>
+  pr reassign <pr_id> <old_reviewer_id>
+  pr list (-user <user_id> | -team <team>) [-status OPEN|MERGED]
+  stats <team>
+
+Common flags (accepted by every command):
+  -addr     API base URL (default $PRCTL_API_URL or ` + defaultAPIURL + `)
+  -o        output format: table, json, yaml (default table)
+  -timeout  overall request timeout (default 10s)
+`
+
+// errUsage marks errors caused by invalid command line arguments
+var errUsage = errors.New("invalid usage")
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run executes the command line and returns the process exit code
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 || args[0] == "-h" || args[0] == "--help" || args[0] == "help" {
+		fmt.Fprint(stderr, usageText)
+		if len(args) == 0 {
+			return 2
+		}
+		return 0
+	}
+
+	cmd, ok := lookupCommand(args)
+	if !ok {
+		fmt.Fprintf(stderr, "prctl: unknown command %q\n\n%s", strings.Join(args[:min(len(args), 2)], " "), usageText)
+		return 2
+	}
+
+	opts := &globalOptions{}
+	fs := flag.NewFlagSet("prctl "+cmd.name, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	opts.register(fs)
+	if cmd.flags != nil {
+		cmd.flags(fs)
+	}
+
+	positional, err := parseInterspersed(fs, args[cmd.depth:])
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+
+	out, err := newPrinter(opts.output, stdout)
+	if err != nil {
+		fmt.Fprintf(stderr, "prctl: %v\n", err)
+		return 2
+	}
+
+	api, err := client.New(opts.addr,
+		client.WithTimeout(opts.timeout),
+		client.WithRetry(retryAttempts, retryBackoff),
+	)
+	if err != nil {
+		fmt.Fprintf(stderr, "prctl: %v\n", err)
+		return 2
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), opts.timeout)
+	defer cancel()
+
+	if err := cmd.run(ctx, api, out, positional); err != nil {
+		fmt.Fprintf(stderr, "prctl: %v\n", err)
+		if errors.Is(err, errUsage) {
+			return 2
+		}
+		return 1
+	}
+
+	return 0
+}
+
+// globalOptions are flags shared by all commands
+type globalOptions struct {
+	addr    string
+	output  string
+	timeout time.Duration
+}
+
+// register binds the shared flags to the flag set
+func (o *globalOptions) register(fs *flag.FlagSet) {
+	addr := os.Getenv("PRCTL_API_URL")
+	if addr == "" {
+		addr = defaultAPIURL
+	}
+
+	fs.StringVar(&o.addr, "addr", addr, "API base URL")
+	fs.StringVar(&o.output, "o", string(formatTable), "output format: table, json, yaml")
+	fs.DurationVar(&o.timeout, "timeout", defaultTimeout, "overall request timeout")
+}
+
+// parseInterspersed parses flags that may appear before, between or after positional arguments
+func parseInterspersed(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
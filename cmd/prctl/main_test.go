@@ -0,0 +1,204 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+
+	"avito-backend-trainee-assignment-autumn-2025/pkg/dto/request"
+	"avito-backend-trainee-assignment-autumn-2025/pkg/dto/response"
+)
+
+// newFakeAPI serves a fixed team of three members and their reviews
+func newFakeAPI(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	team := response.TeamResponse{
+		TeamName: "backend",
+		Members: []response.TeamMemberResponse{
+			{UserID: "u1", Username: "Alice", IsActive: true},
+			{UserID: "u2", Username: "Bob", IsActive: true},
+			{UserID: "u3", Username: "Carol", IsActive: false},
+		},
+	}
+	reviews := map[string][]response.PullRequestShortResponse{
+		"u1": {
+			{PullRequestID: "pr-1", PullRequestName: "Search", AuthorID: "u3", Status: "OPEN"},
+			{PullRequestID: "pr-2", PullRequestName: "Cache", AuthorID: "u3", Status: "MERGED"},
+		},
+		"u2": {
+			{PullRequestID: "pr-1", PullRequestName: "Search", AuthorID: "u3", Status: "OPEN"},
+		},
+	}
+
+	writeJSON := func(w http.ResponseWriter, status int, v interface{}) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_ = json.NewEncoder(w).Encode(v)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /team/get", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("team_name") != team.TeamName {
+			writeJSON(w, http.StatusNotFound, response.NewErrorResponse(response.ErrorCodeNotFound, "team not found"))
+			return
+		}
+		writeJSON(w, http.StatusOK, team)
+	})
+	mux.HandleFunc("GET /users/getReview", func(w http.ResponseWriter, r *http.Request) {
+		userID := r.URL.Query().Get("user_id")
+		prs := reviews[userID]
+		if prs == nil {
+			prs = []response.PullRequestShortResponse{}
+		}
+		writeJSON(w, http.StatusOK, response.GetUserReviewsResponse{UserID: userID, PullRequests: prs})
+	})
+	mux.HandleFunc("POST /users/setIsActive", func(w http.ResponseWriter, r *http.Request) {
+		var req request.SetUserActiveRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		writeJSON(w, http.StatusOK, response.SetUserActiveResponse{User: response.UserResponse{
+			UserID: req.UserID, Username: "Bob", TeamName: "backend", IsActive: req.IsActive,
+		}})
+	})
+	mux.HandleFunc("POST /team/add", func(w http.ResponseWriter, r *http.Request) {
+		var req request.CreateTeamRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		resp := response.CreateTeamResponse{Team: response.TeamResponse{TeamName: req.TeamName}}
+		for _, m := range req.Members {
+			resp.Team.Members = append(resp.Team.Members, response.TeamMemberResponse{
+				UserID: m.UserID, Username: m.Username, IsActive: m.IsActive, MentionHandle: m.MentionHandle, Email: m.Email,
+			})
+		}
+		writeJSON(w, http.StatusCreated, resp)
+	})
+	mux.HandleFunc("POST /pullRequest/reassign", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusConflict, response.NewErrorResponse(response.ErrorCodePRMerged, "cannot modify merged pull request"))
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+// runCLI runs prctl against the fake API
+func runCLI(t *testing.T, server *httptest.Server, args ...string) (int, string, string) {
+	t.Helper()
+	var stdout, stderr bytes.Buffer
+	code := run(append(args, "-addr", server.URL), &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+func TestStats_JSON(t *testing.T) {
+	server := newFakeAPI(t)
+
+	code, stdout, stderr := runCLI(t, server, "stats", "backend", "-o", "json")
+	if code != 0 {
+		t.Fatalf("exit code %d, stderr: %s", code, stderr)
+	}
+
+	var stats teamStats
+	if err := json.Unmarshal([]byte(stdout), &stats); err != nil {
+		t.Fatalf("invalid JSON output: %v\n%s", err, stdout)
+	}
+	if stats.OpenPullRequests != 1 || stats.MergedPullRequests != 1 || stats.ActiveMembers != 2 {
+		t.Errorf("unexpected totals: %+v", stats)
+	}
+	if stats.Members[0].UserID != "u1" || stats.Members[0].OpenReviews != 1 || stats.Members[0].MergedReviews != 1 {
+		t.Errorf("unexpected busiest member: %+v", stats.Members[0])
+	}
+}
+
+func TestPRList_TeamTable(t *testing.T) {
+	server := newFakeAPI(t)
+
+	code, stdout, stderr := runCLI(t, server, "pr", "list", "-team", "backend", "-status", "open")
+	if code != 0 {
+		t.Fatalf("exit code %d, stderr: %s", code, stderr)
+	}
+
+	lines := strings.Split(strings.TrimSpace(stdout), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected header and one row, got:\n%s", stdout)
+	}
+	if fields := strings.Fields(lines[1]); fields[0] != "pr-1" || fields[len(fields)-1] != "u1,u2" {
+		t.Errorf("unexpected row: %q", lines[1])
+	}
+}
+
+func TestUserDeactivate_YAML(t *testing.T) {
+	server := newFakeAPI(t)
+
+	code, stdout, stderr := runCLI(t, server, "user", "deactivate", "-o", "yaml", "u2")
+	if code != 0 {
+		t.Fatalf("exit code %d, stderr: %s", code, stderr)
+	}
+
+	var resp struct {
+		User struct {
+			UserID   string `yaml:"user_id"`
+			IsActive bool   `yaml:"is_active"`
+		} `yaml:"user"`
+	}
+	if err := yaml.Unmarshal([]byte(stdout), &resp); err != nil {
+		t.Fatalf("invalid YAML output: %v\n%s", err, stdout)
+	}
+	if resp.User.UserID != "u2" || resp.User.IsActive {
+		t.Errorf("unexpected user: %+v", resp.User)
+	}
+	if strings.Contains(stdout, "{") {
+		t.Errorf("expected block style YAML, got:\n%s", stdout)
+	}
+}
+
+func TestTeamCreate_Members(t *testing.T) {
+	server := newFakeAPI(t)
+
+	code, stdout, stderr := runCLI(t, server, "team", "create", "-name", "infra",
+		"-member", "u7:Dave", "-member", "u8:Erin:inactive", "-o", "json")
+	if code != 0 {
+		t.Fatalf("exit code %d, stderr: %s", code, stderr)
+	}
+
+	var resp response.CreateTeamResponse
+	if err := json.Unmarshal([]byte(stdout), &resp); err != nil {
+		t.Fatalf("invalid JSON output: %v", err)
+	}
+	if len(resp.Team.Members) != 2 || resp.Team.Members[1].IsActive {
+		t.Errorf("unexpected members: %+v", resp.Team.Members)
+	}
+}
+
+func TestAPIErrorExitCode(t *testing.T) {
+	server := newFakeAPI(t)
+
+	code, _, stderr := runCLI(t, server, "pr", "reassign", "pr-2", "u1")
+	if code != 1 {
+		t.Errorf("exit code = %d, want 1", code)
+	}
+	if !strings.Contains(stderr, "PR_MERGED") {
+		t.Errorf("stderr should contain the error code, got: %s", stderr)
+	}
+}
+
+func TestUsageErrors(t *testing.T) {
+	server := newFakeAPI(t)
+
+	cases := [][]string{
+		{"unknown"},
+		{"pr", "merge"},
+		{"pr", "list"},
+		{"pr", "create", "-id", "pr-1"},
+		{"team", "get", "backend", "-o", "xml"},
+		{"team", "create", "-member", "broken"},
+	}
+	for _, args := range cases {
+		if code, _, _ := runCLI(t, server, args...); code != 2 {
+			t.Errorf("%v: exit code = %d, want 2", args, code)
+		}
+	}
+}
@@ -0,0 +1,187 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"gopkg.in/yaml.v3"
+
+	"avito-backend-trainee-assignment-autumn-2025/pkg/dto/response"
+)
+
+// outputFormat is the format used to print command results
+type outputFormat string
+
+const (
+	formatTable outputFormat = "table"
+	formatJSON  outputFormat = "json"
+	formatYAML  outputFormat = "yaml"
+)
+
+// printer writes command results in the selected format
+type printer struct {
+	format outputFormat
+	w      io.Writer
+}
+
+// newPrinter creates a printer for the given format name
+func newPrinter(format string, w io.Writer) (*printer, error) {
+	switch f := outputFormat(strings.ToLower(format)); f {
+	case formatTable, formatJSON, formatYAML:
+		return &printer{format: f, w: w}, nil
+	default:
+		return nil, fmt.Errorf("unknown output format %q (expected table, json or yaml)", format)
+	}
+}
+
+// table is the tabular representation of a result
+type table struct {
+	headers []string
+	rows    [][]string
+}
+
+// print writes v as JSON or YAML, or tbl in table mode
+// JSON and YAML use the API field names
+func (p *printer) print(v interface{}, tbl *table) error {
+	switch p.format {
+	case formatJSON:
+		encoder := json.NewEncoder(p.w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(v)
+	case formatYAML:
+		return p.printYAML(v)
+	default:
+		return p.printTable(tbl)
+	}
+}
+
+// printYAML converts v through JSON so YAML keys match the API field names and order
+func (p *printer) printYAML(v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return err
+	}
+	resetStyle(&node)
+
+	encoder := yaml.NewEncoder(p.w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&node); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
+
+// resetStyle switches nodes parsed from JSON to block style
+func resetStyle(node *yaml.Node) {
+	node.Style = 0
+	if node.Kind == yaml.ScalarNode && node.Tag == "!!str" && needsQuoting(node.Value) {
+		node.Style = yaml.DoubleQuotedStyle
+	}
+	for _, child := range node.Content {
+		resetStyle(child)
+	}
+}
+
+// needsQuoting reports whether a string would be read back as another type
+func needsQuoting(value string) bool {
+	var decoded interface{}
+	if err := yaml.Unmarshal([]byte(value), &decoded); err != nil {
+		return true
+	}
+	_, isString := decoded.(string)
+	return !isString
+}
+
+// printTable writes an aligned table
+func (p *printer) printTable(tbl *table) error {
+	tw := tabwriter.NewWriter(p.w, 0, 0, 2, ' ', 0)
+	if len(tbl.headers) > 0 {
+		fmt.Fprintln(tw, strings.Join(tbl.headers, "\t"))
+	}
+	for _, row := range tbl.rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	return tw.Flush()
+}
+
+// teamTable renders a team with its members
+func teamTable(team *response.TeamResponse) *table {
+	tbl := &table{headers: []string{"TEAM", "USER_ID", "USERNAME", "ACTIVE"}}
+	for _, member := range team.Members {
+		tbl.rows = append(tbl.rows, []string{
+			team.TeamName, member.UserID, member.Username, strconv.FormatBool(member.IsActive),
+		})
+	}
+	if len(team.Members) == 0 {
+		tbl.rows = append(tbl.rows, []string{team.TeamName, "-", "-", "-"})
+	}
+	return tbl
+}
+
+// pullRequestTable renders a single pull request as key/value pairs
+func pullRequestTable(pr *response.PullRequestResponse) *table {
+	tbl := &table{rows: [][]string{
+		{"pull_request_id", pr.PullRequestID},
+		{"pull_request_name", pr.PullRequestName},
+		{"author_id", pr.AuthorID},
+		{"status", pr.Status},
+		{"assigned_reviewers", joinOrDash(pr.AssignedReviewers)},
+	}}
+	if pr.CreatedAt != nil {
+		tbl.rows = append(tbl.rows, []string{"createdAt", pr.CreatedAt.Format("2006-01-02 15:04:05Z07:00")})
+	}
+	if pr.MergedAt != nil {
+		tbl.rows = append(tbl.rows, []string{"mergedAt", pr.MergedAt.Format("2006-01-02 15:04:05Z07:00")})
+	}
+	return tbl
+}
+
+// pullRequestListTable renders a list of pull requests
+func pullRequestListTable(items []pullRequestListItem) *table {
+	tbl := &table{headers: []string{"PR_ID", "NAME", "AUTHOR", "STATUS", "REVIEWERS"}}
+	for _, item := range items {
+		tbl.rows = append(tbl.rows, []string{
+			item.PullRequestID, item.PullRequestName, item.AuthorID, item.Status, joinOrDash(item.Reviewers),
+		})
+	}
+	return tbl
+}
+
+// statsTable renders the review load of a team
+func statsTable(stats *teamStats) *table {
+	tbl := &table{headers: []string{"USER_ID", "USERNAME", "ACTIVE", "OPEN_REVIEWS", "MERGED_REVIEWS"}}
+	for _, member := range stats.Members {
+		tbl.rows = append(tbl.rows, []string{
+			member.UserID,
+			member.Username,
+			strconv.FormatBool(member.IsActive),
+			strconv.Itoa(member.OpenReviews),
+			strconv.Itoa(member.MergedReviews),
+		})
+	}
+	tbl.rows = append(tbl.rows, []string{
+		"TOTAL",
+		stats.TeamName,
+		fmt.Sprintf("%d/%d", stats.ActiveMembers, len(stats.Members)),
+		strconv.Itoa(stats.OpenPullRequests),
+		strconv.Itoa(stats.MergedPullRequests),
+	})
+	return tbl
+}
+
+// joinOrDash joins values with commas or returns "-" for an empty list
+func joinOrDash(values []string) string {
+	if len(values) == 0 {
+		return "-"
+	}
+	return strings.Join(values, ",")
+}
@@ -3,85 +3,180 @@ package app
 import (
 	"context"
 	"fmt"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"sync"
 	"syscall"
 	"time"
 
 	"github.com/gorilla/mux"
-	"github.com/jackc/pgx/v5/pgxpool"
+	"google.golang.org/grpc"
 
+	"avito-backend-trainee-assignment-autumn-2025/api"
 	"avito-backend-trainee-assignment-autumn-2025/internal/config"
+	"avito-backend-trainee-assignment-autumn-2025/internal/domain/models"
+	"avito-backend-trainee-assignment-autumn-2025/internal/grpchandler"
 	"avito-backend-trainee-assignment-autumn-2025/internal/handler"
+	"avito-backend-trainee-assignment-autumn-2025/internal/integration"
+	"avito-backend-trainee-assignment-autumn-2025/internal/integration/github"
+	"avito-backend-trainee-assignment-autumn-2025/internal/jobs"
 	"avito-backend-trainee-assignment-autumn-2025/internal/middleware"
-	"avito-backend-trainee-assignment-autumn-2025/internal/repository"
-	"avito-backend-trainee-assignment-autumn-2025/internal/repository/postgres"
+	"avito-backend-trainee-assignment-autumn-2025/internal/notifier"
+	"avito-backend-trainee-assignment-autumn-2025/internal/outbox"
 	"avito-backend-trainee-assignment-autumn-2025/internal/service"
-	"avito-backend-trainee-assignment-autumn-2025/pkg/database"
+	"avito-backend-trainee-assignment-autumn-2025/internal/webhook"
 	"avito-backend-trainee-assignment-autumn-2025/pkg/logger"
 )
 
 // App represents the application with all its dependencies
 type App struct {
-	config *config.Config
-	db     *pgxpool.Pool
-	router *mux.Router
-	server *http.Server
+	config     *config.Config
+	storage    *storage
+	router     *mux.Router
+	server     *http.Server
+	grpcServer *grpc.Server
+
+	// workers run in the background while the servers are up
+	workers []func(ctx context.Context)
+	// closeSinks releases resources held by outbox sinks
+	closeSinks func()
 }
 
 // NewApp creates and initializes a new application instance
-func NewApp(cfg *config.Config) (*App, error) {
+func NewApp(cfg *config.Config) (_ *App, err error) {
 	// Initialize logger
 	logger.Init(cfg.App.LogLevel)
 	logger.Info("Initializing PR Reviewer Assignment Service...")
 
-	// Initialize database connection
-	dbConfig := database.Config{
-		Host:            cfg.Database.Host,
-		Port:            cfg.Database.Port,
-		User:            cfg.Database.User,
-		Password:        cfg.Database.Password,
-		DBName:          cfg.Database.DBName,
-		SSLMode:         cfg.Database.SSLMode,
-		MaxConns:        cfg.Database.MaxConns,
-		MinConns:        cfg.Database.MinConns,
-		MaxConnLifetime: cfg.Database.MaxConnLifetime,
-		MaxConnIdleTime: cfg.Database.MaxConnIdleTime,
-	}
-
-	pool, err := database.NewPostgresDB(dbConfig)
+	// Initialize storage and repositories
+	store, err := newStorage(cfg)
 	if err != nil {
-		return nil, fmt.Errorf("failed to connect to database: %w", err)
+		return nil, err
 	}
-
-	// Initialize transaction manager
-	txManager := repository.NewPgxTransactionManager(pool)
-
-	// Initialize repositories
-	teamRepo := postgres.NewTeamRepository(pool)
-	userRepo := postgres.NewUserRepository(pool)
-	prRepo := postgres.NewPRRepository(pool)
+	// Release the database if the rest of the initialization fails
+	defer func() {
+		if err != nil {
+			store.close()
+		}
+	}()
 
 	logger.Info("Repositories initialized")
 
 	// Initialize services
-	teamService := service.NewTeamService(teamRepo, userRepo, txManager)
-	userService := service.NewUserService(userRepo, prRepo)
-	prService := service.NewPRService(prRepo, userRepo, teamRepo, txManager)
+	teamService := service.NewTeamService(store.teamRepo, store.userRepo, store.absenceRepo, store.txManager, store.outboxRepo)
+	userService := service.NewUserService(store.userRepo, store.teamRepo, store.prRepo, store.absenceRepo, store.affinityRepo, store.txManager, store.outboxRepo)
+	prService := service.NewPRService(store.prRepo, store.userRepo, store.teamRepo, store.absenceRepo, store.affinityRepo, store.txManager, store.outboxRepo)
+	webhookService := service.NewWebhookService(store.webhookRepo, service.WebhookServiceConfig{
+		AllowPrivateNetworks: cfg.Webhook.AllowPrivateNetworks,
+	})
+	integrationService := service.NewIntegrationService(store.accountRepo, prService)
+	reviewSLAService := service.NewReviewSLAService(store.teamRepo, store.prRepo, store.txManager, store.outboxRepo, prService, service.ReviewSLAConfig{
+		DefaultSLA: cfg.Jobs.ReviewSLA,
+		BatchSize:  cfg.Jobs.ReminderBatchSize,
+	})
 
 	logger.Info("Services initialized")
 
+	// Initialize event delivery: services record events in the outbox,
+	// the outbox dispatcher hands them to the configured sinks
+	webhookDispatcher := webhook.NewDispatcher(store.webhookRepo, webhook.Config{
+		MaxAttempts:          cfg.Webhook.MaxAttempts,
+		InitialBackoff:       cfg.Webhook.InitialBackoff,
+		MaxBackoff:           cfg.Webhook.MaxBackoff,
+		Timeout:              cfg.Webhook.Timeout,
+		PollInterval:         cfg.Webhook.PollInterval,
+		AllowPrivateNetworks: cfg.Webhook.AllowPrivateNetworks,
+	})
+
+	// Reviewer changes on PRs that came from a code host are pushed back to it
+	codeHostClients := map[models.CodeHost]integration.CodeHostClient{}
+	if cfg.Integrations.GitHubToken != "" {
+		codeHostClients[models.CodeHostGitHub] = github.NewClient(
+			cfg.Integrations.GitHubAPIURL, cfg.Integrations.GitHubToken, cfg.Integrations.GitHubTimeout)
+	}
+	reviewerSyncer := integration.NewReviewerSyncer(store.syncRepo, store.accountRepo, codeHostClients, integration.Config{
+		MaxAttempts:    cfg.ReviewerSync.MaxAttempts,
+		InitialBackoff: cfg.ReviewerSync.InitialBackoff,
+		MaxBackoff:     cfg.ReviewerSync.MaxBackoff,
+		PollInterval:   cfg.ReviewerSync.PollInterval,
+	})
+
+	slackNotifier := notifier.NewSlackNotifier(store.teamRepo, store.userRepo, notifier.SlackConfig{
+		QueueSize:   cfg.Slack.QueueSize,
+		MinInterval: cfg.Slack.MinInterval,
+		Timeout:     cfg.Slack.Timeout,
+	})
+
+	emailNotifier, err := notifier.NewEmailNotifier(store.userRepo, notifier.EmailConfig{
+		Addr:      cfg.SMTP.Addr,
+		Username:  cfg.SMTP.Username,
+		Password:  cfg.SMTP.Password,
+		From:      cfg.SMTP.From,
+		QueueSize: cfg.SMTP.QueueSize,
+		Senders:   cfg.SMTP.Senders,
+		Timeout:   cfg.SMTP.Timeout,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sinks, closeSinks, err := newOutboxSinks(cfg, webhookDispatcher, reviewerSyncer, slackNotifier, emailNotifier)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			closeSinks()
+		}
+	}()
+
+	outboxDispatcher := outbox.NewDispatcher(store.outboxRepo, store.txManager, sinks, outbox.Config{
+		PollInterval:   cfg.Outbox.PollInterval,
+		BatchSize:      cfg.Outbox.BatchSize,
+		Retention:      cfg.Outbox.Retention,
+		MaxAttempts:    cfg.Outbox.MaxAttempts,
+		InitialBackoff: cfg.Outbox.InitialBackoff,
+		MaxBackoff:     cfg.Outbox.MaxBackoff,
+	})
+
+	logger.Info("Event delivery initialized")
+
+	// Load OpenAPI specification
+	openAPIDoc, err := api.LoadOpenAPI()
+	if err != nil {
+		return nil, err
+	}
+
+	validationMode, err := middleware.ParseValidationMode(cfg.Server.OpenAPIValidation)
+	if err != nil {
+		return nil, err
+	}
+
+	openAPIValidator, err := middleware.OpenAPIValidator(openAPIDoc, validationMode)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("OpenAPI spec loaded (validation mode: %s)", validationMode)
+
 	// Initialize handlers
 	healthHandler := handler.NewHealthHandler()
+	openAPIHandler, err := handler.NewOpenAPIHandler(openAPIDoc)
+	if err != nil {
+		return nil, err
+	}
 	teamHandler := handler.NewTeamHandler(teamService)
 	userHandler := handler.NewUserHandler(userService)
 	prHandler := handler.NewPRHandler(prService)
+	webhookHandler := handler.NewWebhookHandler(webhookService)
+	integrationHandler := handler.NewIntegrationHandler(integrationService, cfg.Integrations.GitHubWebhookSecret, cfg.Integrations.GitLabWebhookToken)
 
 	logger.Info("Handlers initialized")
 
 	// Initialize router
-	router := NewRouter(healthHandler, teamHandler, userHandler, prHandler)
+	router := NewRouter(openAPIValidator, healthHandler, openAPIHandler, teamHandler, userHandler, prHandler, webhookHandler, integrationHandler)
 
 	logger.Info("Router initialized with all endpoints")
 
@@ -94,54 +189,135 @@ func NewApp(cfg *config.Config) (*App, error) {
 		IdleTimeout:  60 * time.Second,
 	}
 
+	// Create gRPC server sharing the same services
+	grpcServer := grpchandler.NewServer(teamService, userService, prService)
+
+	logger.Info("gRPC server initialized")
+
+	// Background workers: outbox publishing and the deliveries queued by its sinks
+	workers := []func(ctx context.Context){
+		outboxDispatcher.Run, webhookDispatcher.Run, reviewerSyncer.Run, slackNotifier.Run, emailNotifier.Run,
+	}
+
+	// Periodic jobs run on a single replica at a time
+	if cfg.Jobs.Enabled {
+		scheduler := jobs.NewScheduler(store.elector, []jobs.Job{
+			{
+				Name:     "review-sla",
+				Interval: cfg.Jobs.ReminderInterval,
+				Run: func(ctx context.Context) error {
+					_, err := reviewSLAService.EnforceReviewSLA(ctx, time.Now())
+					return err
+				},
+			},
+		}, jobs.Config{ElectionInterval: cfg.Jobs.ElectionInterval})
+		workers = append(workers, scheduler.Run)
+	}
+
 	return &App{
-		config: cfg,
-		db:     pool,
-		router: router,
-		server: server,
+		config:     cfg,
+		storage:    store,
+		router:     router,
+		server:     server,
+		grpcServer: grpcServer,
+		workers:    workers,
+		closeSinks: closeSinks,
 	}, nil
 }
 
 // NewRouter creates and configures the HTTP router with all endpoints and middleware
 func NewRouter(
+	openAPIValidator func(http.Handler) http.Handler,
 	healthHandler *handler.HealthHandler,
+	openAPIHandler *handler.OpenAPIHandler,
 	teamHandler *handler.TeamHandler,
 	userHandler *handler.UserHandler,
 	prHandler *handler.PRHandler,
+	webhookHandler *handler.WebhookHandler,
+	integrationHandler *handler.IntegrationHandler,
 ) *mux.Router {
 	router := mux.NewRouter()
 
-	// Apply middleware (order matters: Recovery -> Logger)
+	// Apply middleware (order matters: Recovery -> Logger -> OpenAPI validation)
 	router.Use(middleware.Recovery)
 	router.Use(middleware.Logger)
+	router.Use(openAPIValidator)
 
 	// Health endpoint
 	router.HandleFunc("/health", healthHandler.Check).Methods(http.MethodGet)
 
+	// API specification
+	router.HandleFunc("/openapi.json", openAPIHandler.GetSpec).Methods(http.MethodGet)
+
 	// Team endpoints
 	router.HandleFunc("/team/add", teamHandler.CreateTeam).Methods(http.MethodPost)
 	router.HandleFunc("/team/get", teamHandler.GetTeam).Methods(http.MethodGet)
+	router.HandleFunc("/team/setChatWebhook", teamHandler.SetChatWebhook).Methods(http.MethodPost)
+	router.HandleFunc("/team/setReviewSLA", teamHandler.SetReviewSLA).Methods(http.MethodPost)
+	router.HandleFunc("/team/setLead", teamHandler.SetLead).Methods(http.MethodPost)
+	router.HandleFunc("/team/setCalendar", teamHandler.SetCalendar).Methods(http.MethodPost)
+	router.HandleFunc("/team/setWorkingHoursPreference", teamHandler.SetWorkingHoursPreference).Methods(http.MethodPost)
+	router.HandleFunc("/team/setSeniorityPolicy", teamHandler.SetSeniorityPolicy).Methods(http.MethodPost)
+	router.HandleFunc("/team/codeowners", teamHandler.GetCodeOwners).Methods(http.MethodGet)
+	router.HandleFunc("/team/codeowners/upload", teamHandler.UploadCodeOwners).Methods(http.MethodPost)
 
 	// User endpoints
 	router.HandleFunc("/users/setIsActive", userHandler.SetIsActive).Methods(http.MethodPost)
 	router.HandleFunc("/users/getReview", userHandler.GetUserReviews).Methods(http.MethodGet)
+	router.HandleFunc("/users/setMentionHandle", userHandler.SetMentionHandle).Methods(http.MethodPost)
+	router.HandleFunc("/users/setEmail", userHandler.SetEmail).Methods(http.MethodPost)
+	router.HandleFunc("/users/setWorkingHours", userHandler.SetWorkingHours).Methods(http.MethodPost)
+	router.HandleFunc("/users/setMaxOpenReviews", userHandler.SetMaxOpenReviews).Methods(http.MethodPost)
+	router.HandleFunc("/users/setSeniority", userHandler.SetSeniority).Methods(http.MethodPost)
+	router.HandleFunc("/users/tags/add", userHandler.AddSkillTags).Methods(http.MethodPost)
+	router.HandleFunc("/users/tags/remove", userHandler.RemoveSkillTags).Methods(http.MethodPost)
+	router.HandleFunc("/users/tags/set", userHandler.SetSkillTags).Methods(http.MethodPost)
+	router.HandleFunc("/users/tags/list", userHandler.ListSkillTags).Methods(http.MethodGet)
+	router.HandleFunc("/users/absences/add", userHandler.AddAbsence).Methods(http.MethodPost)
+	router.HandleFunc("/users/absences/list", userHandler.ListAbsences).Methods(http.MethodGet)
+	router.HandleFunc("/users/absences/import", userHandler.ImportAbsences).Methods(http.MethodPost)
+	router.HandleFunc("/users/absences/delete", userHandler.DeleteAbsence).Methods(http.MethodPost)
+	router.HandleFunc("/users/affinities/set", userHandler.SetAffinity).Methods(http.MethodPost)
+	router.HandleFunc("/users/affinities/list", userHandler.ListAffinities).Methods(http.MethodGet)
+	router.HandleFunc("/users/affinities/delete", userHandler.DeleteAffinity).Methods(http.MethodPost)
 
 	// Pull Request endpoints
 	router.HandleFunc("/pullRequest/create", prHandler.CreatePR).Methods(http.MethodPost)
 	router.HandleFunc("/pullRequest/merge", prHandler.MergePR).Methods(http.MethodPost)
 	router.HandleFunc("/pullRequest/reassign", prHandler.ReassignReviewer).Methods(http.MethodPost)
+	router.HandleFunc("/pullRequest/history", prHandler.GetPRHistory).Methods(http.MethodGet)
+
+	// Webhook endpoints
+	router.HandleFunc("/webhooks/add", webhookHandler.RegisterWebhook).Methods(http.MethodPost)
+	router.HandleFunc("/webhooks/list", webhookHandler.ListWebhooks).Methods(http.MethodGet)
+	router.HandleFunc("/webhooks/deliveries", webhookHandler.ListDeliveries).Methods(http.MethodGet)
+	router.HandleFunc("/webhooks/redeliver", webhookHandler.Redeliver).Methods(http.MethodPost)
+
+	// Code host integration endpoints
+	router.HandleFunc("/integrations/github/webhook", integrationHandler.GitHubWebhook).Methods(http.MethodPost)
+	router.HandleFunc("/integrations/gitlab/webhook", integrationHandler.GitLabWebhook).Methods(http.MethodPost)
+	router.HandleFunc("/integrations/accounts/link", integrationHandler.LinkAccount).Methods(http.MethodPost)
+	router.HandleFunc("/integrations/accounts/list", integrationHandler.ListAccounts).Methods(http.MethodGet)
 
 	return router
 }
 
-// Run starts the HTTP server and handles graceful shutdown
+// Run starts the HTTP and gRPC servers and handles graceful shutdown
 func (a *App) Run() error {
 	// Channel to listen for interrupt signals
 	stop := make(chan os.Signal, 1)
 	signal.Notify(stop, os.Interrupt, syscall.SIGTERM, syscall.SIGINT)
 
 	// Channel to listen for server errors
-	serverErrors := make(chan error, 1)
+	serverErrors := make(chan error, 2)
+
+	grpcListener, err := net.Listen("tcp", ":"+a.config.Server.GRPCPort)
+	if err != nil {
+		return fmt.Errorf("failed to listen on gRPC port %s: %w", a.config.Server.GRPCPort, err)
+	}
+
+	// Start background workers; they are stopped after the servers
+	stopWorkers := a.startWorkers()
 
 	// Start HTTP server in a goroutine
 	go func() {
@@ -152,9 +328,19 @@ func (a *App) Run() error {
 		}
 	}()
 
+	// Start gRPC server in a goroutine
+	go func() {
+		logger.Info("Starting gRPC server on port %s", a.config.Server.GRPCPort)
+		if err := a.grpcServer.Serve(grpcListener); err != nil && err != grpc.ErrServerStopped {
+			serverErrors <- fmt.Errorf("gRPC: %w", err)
+		}
+	}()
+
 	// Wait for interrupt signal or server error
 	select {
 	case err := <-serverErrors:
+		a.grpcServer.Stop()
+		stopWorkers()
 		return fmt.Errorf("server error: %w", err)
 	case sig := <-stop:
 		logger.Info("Received signal: %v. Starting graceful shutdown...", sig)
@@ -167,14 +353,60 @@ func (a *App) Run() error {
 	logger.Info("Shutting down HTTP server...")
 	if err := a.server.Shutdown(ctx); err != nil {
 		logger.Error("Server forced to shutdown: %v", err)
+		a.grpcServer.Stop()
+		stopWorkers()
 		return err
 	}
 
 	logger.Info("HTTP server stopped")
 
-	// Close database connection
-	database.Close(a.db)
+	logger.Info("Shutting down gRPC server...")
+	a.stopGRPCServer(ctx)
+	logger.Info("gRPC server stopped")
+
+	logger.Info("Stopping background workers...")
+	stopWorkers()
+	a.closeSinks()
+
+	// Close storage (database connection)
+	a.storage.close()
 
 	logger.Info("Application shutdown complete")
 	return nil
 }
+
+// startWorkers runs every background worker in its own goroutine
+// The returned function cancels them and waits until all have returned
+func (a *App) startWorkers() func() {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	var wg sync.WaitGroup
+	for _, worker := range a.workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			worker(ctx)
+		}()
+	}
+
+	return func() {
+		cancel()
+		wg.Wait()
+	}
+}
+
+// stopGRPCServer stops the gRPC server gracefully, forcing it down if ctx expires first
+func (a *App) stopGRPCServer(ctx context.Context) {
+	stopped := make(chan struct{})
+	go func() {
+		a.grpcServer.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		logger.Error("gRPC server forced to shutdown: %v", ctx.Err())
+		a.grpcServer.Stop()
+	}
+}
@@ -0,0 +1,125 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"avito-backend-trainee-assignment-autumn-2025/internal/config"
+	"avito-backend-trainee-assignment-autumn-2025/internal/migrator"
+	"avito-backend-trainee-assignment-autumn-2025/pkg/database"
+	"avito-backend-trainee-assignment-autumn-2025/pkg/logger"
+)
+
+// migrationTimeout bounds a single migrate command, including waiting for the advisory lock
+const migrationTimeout = 5 * time.Minute
+
+// MigrateCommands lists the supported `migrate` subcommands
+var MigrateCommands = []string{"up", "down", "status", "redo"}
+
+// RunMigrate executes a `migrate` subcommand against the configured database
+func RunMigrate(cfg *config.Config, command string, out io.Writer) error {
+	logger.Init(cfg.App.LogLevel)
+
+	m, closeDB, err := openMigrator(cfg)
+	if err != nil {
+		return err
+	}
+	defer closeDB()
+	defer m.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), migrationTimeout)
+	defer cancel()
+
+	switch command {
+	case "up":
+		results, err := m.Up(ctx)
+		for _, result := range results {
+			fmt.Fprintln(out, result)
+		}
+		if err != nil {
+			return err
+		}
+		if len(results) == 0 {
+			fmt.Fprintln(out, "no migrations to apply")
+		}
+
+	case "down":
+		result, err := m.Down(ctx)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(out, result)
+
+	case "redo":
+		results, err := m.Redo(ctx)
+		for _, result := range results {
+			fmt.Fprintln(out, result)
+		}
+		if err != nil {
+			return err
+		}
+
+	case "status":
+		statuses, err := m.Status(ctx)
+		if err != nil {
+			return err
+		}
+		migrator.PrintStatus(out, statuses)
+
+	default:
+		return fmt.Errorf("unknown migrate command %q (expected one of %v)", command, MigrateCommands)
+	}
+
+	return nil
+}
+
+// openMigrator connects to the configured SQL storage and creates a migrator for it
+// The returned function closes the database connection
+func openMigrator(cfg *config.Config) (*migrator.Migrator, func(), error) {
+	switch cfg.Storage.Type {
+	case config.StoragePostgres:
+		pool, err := database.NewPostgresDB(newDatabaseConfig(cfg))
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to connect to database: %w", err)
+		}
+		m, err := migrator.New(pool)
+		if err != nil {
+			database.Close(pool)
+			return nil, nil, err
+		}
+		return m, func() { database.Close(pool) }, nil
+
+	case config.StorageSQLite:
+		db, err := database.NewSQLiteDB(cfg.Storage.SQLitePath)
+		if err != nil {
+			return nil, nil, err
+		}
+		m, err := migrator.NewSQLite(db)
+		if err != nil {
+			database.CloseSQLite(db)
+			return nil, nil, err
+		}
+		return m, func() { database.CloseSQLite(db) }, nil
+
+	default:
+		return nil, nil, fmt.Errorf("migrations are not supported for storage %q", cfg.Storage.Type)
+	}
+}
+
+// newDatabaseConfig converts the application config to the database package config
+func newDatabaseConfig(cfg *config.Config) database.Config {
+	return database.Config{
+		Host:            cfg.Database.Host,
+		Port:            cfg.Database.Port,
+		User:            cfg.Database.User,
+		Password:        cfg.Database.Password,
+		DBName:          cfg.Database.DBName,
+		SSLMode:         cfg.Database.SSLMode,
+		MaxConns:        cfg.Database.MaxConns,
+		MinConns:        cfg.Database.MinConns,
+		MaxConnLifetime: cfg.Database.MaxConnLifetime,
+		MaxConnIdleTime: cfg.Database.MaxConnIdleTime,
+	}
+}
@@ -0,0 +1,60 @@
+package app
+
+import (
+	"avito-backend-trainee-assignment-autumn-2025/internal/config"
+	"avito-backend-trainee-assignment-autumn-2025/internal/integration"
+	"avito-backend-trainee-assignment-autumn-2025/internal/notifier"
+	"avito-backend-trainee-assignment-autumn-2025/internal/outbox"
+	"avito-backend-trainee-assignment-autumn-2025/internal/webhook"
+	"avito-backend-trainee-assignment-autumn-2025/pkg/logger"
+)
+
+// newOutboxSinks creates the sinks listed in OUTBOX_SINKS
+// The returned function closes the sinks that hold resources
+func newOutboxSinks(
+	cfg *config.Config,
+	webhookDispatcher *webhook.Dispatcher,
+	reviewerSyncer *integration.ReviewerSyncer,
+	slackNotifier *notifier.SlackNotifier,
+	emailNotifier *notifier.EmailNotifier,
+) ([]outbox.Sink, func(), error) {
+	var sinks []outbox.Sink
+	var closers []func() error
+
+	closeAll := func() {
+		for _, closeSink := range closers {
+			if err := closeSink(); err != nil {
+				logger.Error("Failed to close outbox sink: %v", err)
+			}
+		}
+	}
+
+	for _, name := range cfg.Outbox.Sinks {
+		switch name {
+		case config.OutboxSinkWebhook:
+			sinks = append(sinks, webhookDispatcher)
+		case config.OutboxSinkCodeHost:
+			sinks = append(sinks, reviewerSyncer)
+		case config.OutboxSinkSlack:
+			sinks = append(sinks, slackNotifier)
+		case config.OutboxSinkEmail:
+			sinks = append(sinks, emailNotifier)
+		case config.OutboxSinkStdout:
+			sinks = append(sinks, outbox.NewStdoutSink())
+		case config.OutboxSinkFile:
+			fileSink, err := outbox.NewFileSink(cfg.Outbox.FilePath)
+			if err != nil {
+				closeAll()
+				return nil, nil, err
+			}
+			sinks = append(sinks, fileSink)
+			closers = append(closers, fileSink.Close)
+		}
+	}
+
+	if len(sinks) == 0 {
+		logger.Warn("No outbox sinks configured: events are marked published without being sent anywhere")
+	}
+
+	return sinks, closeAll, nil
+}
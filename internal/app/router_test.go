@@ -0,0 +1,57 @@
+package app
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gorilla/mux"
+
+	"avito-backend-trainee-assignment-autumn-2025/api"
+	"avito-backend-trainee-assignment-autumn-2025/internal/handler"
+)
+
+// TestRouterRoutesAreDocumented makes sure every registered route is described in the OpenAPI spec
+func TestRouterRoutesAreDocumented(t *testing.T) {
+	doc, err := api.LoadOpenAPI()
+	if err != nil {
+		t.Fatalf("Failed to load OpenAPI spec: %v", err)
+	}
+
+	passthrough := func(next http.Handler) http.Handler { return next }
+	router := NewRouter(
+		passthrough,
+		&handler.HealthHandler{},
+		&handler.OpenAPIHandler{},
+		&handler.TeamHandler{},
+		&handler.UserHandler{},
+		&handler.PRHandler{},
+		&handler.WebhookHandler{},
+		&handler.IntegrationHandler{},
+	)
+
+	err = router.Walk(func(route *mux.Route, _ *mux.Router, _ []*mux.Route) error {
+		path, err := route.GetPathTemplate()
+		if err != nil {
+			return err
+		}
+		methods, err := route.GetMethods()
+		if err != nil {
+			return err
+		}
+
+		pathItem := doc.Paths.Value(path)
+		if pathItem == nil {
+			t.Errorf("Route %s is not described in the OpenAPI spec", path)
+			return nil
+		}
+		for _, method := range methods {
+			if pathItem.GetOperation(method) == nil {
+				t.Errorf("Route %s %s is not described in the OpenAPI spec", method, path)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Failed to walk router: %v", err)
+	}
+}
@@ -0,0 +1,153 @@
+package app
+
+import (
+	"context"
+	"fmt"
+
+	"avito-backend-trainee-assignment-autumn-2025/internal/config"
+	"avito-backend-trainee-assignment-autumn-2025/internal/jobs"
+	"avito-backend-trainee-assignment-autumn-2025/internal/migrator"
+	"avito-backend-trainee-assignment-autumn-2025/internal/repository"
+	"avito-backend-trainee-assignment-autumn-2025/internal/repository/memory"
+	"avito-backend-trainee-assignment-autumn-2025/internal/repository/postgres"
+	"avito-backend-trainee-assignment-autumn-2025/internal/repository/sqlite"
+	"avito-backend-trainee-assignment-autumn-2025/pkg/database"
+	"avito-backend-trainee-assignment-autumn-2025/pkg/logger"
+)
+
+// storage groups the repositories of the configured backend
+type storage struct {
+	teamRepo     repository.TeamRepository
+	userRepo     repository.UserRepository
+	absenceRepo  repository.AbsenceRepository
+	affinityRepo repository.AffinityRepository
+	prRepo       repository.PRRepository
+	webhookRepo  repository.WebhookRepository
+	outboxRepo   repository.OutboxRepository
+	accountRepo  repository.CodeHostAccountRepository
+	syncRepo     repository.ReviewerSyncRepository
+	txManager    repository.TransactionManager
+
+	// elector picks the replica that runs background jobs
+	elector jobs.Elector
+
+	// close releases backend resources
+	close func()
+}
+
+// jobsLockKey identifies the Postgres advisory lock held by the job leader
+const jobsLockKey int64 = 0x70725f6a6f6273 // "pr_jobs"
+
+// newStorage initializes repositories for the storage backend selected in config
+func newStorage(cfg *config.Config) (*storage, error) {
+	switch cfg.Storage.Type {
+	case config.StorageMemory:
+		return newMemoryStorage(), nil
+	case config.StoragePostgres:
+		return newPostgresStorage(cfg)
+	case config.StorageSQLite:
+		return newSQLiteStorage(cfg)
+	default:
+		return nil, fmt.Errorf("unknown storage type: %s", cfg.Storage.Type)
+	}
+}
+
+// newPostgresStorage connects to PostgreSQL and checks (or applies) migrations
+func newPostgresStorage(cfg *config.Config) (*storage, error) {
+	// Initialize database connection
+	pool, err := database.NewPostgresDB(newDatabaseConfig(cfg))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+
+	// Check (or apply) database migrations
+	migrateCtx, cancel := context.WithTimeout(context.Background(), migrationTimeout)
+	defer cancel()
+
+	m, err := migrator.New(pool)
+	if err != nil {
+		database.Close(pool)
+		return nil, err
+	}
+	defer m.Close()
+
+	if err := migrator.EnsureSchema(migrateCtx, m, cfg.Database.AutoMigrate); err != nil {
+		database.Close(pool)
+		return nil, err
+	}
+
+	return &storage{
+		teamRepo:     postgres.NewTeamRepository(pool),
+		userRepo:     postgres.NewUserRepository(pool),
+		absenceRepo:  postgres.NewAbsenceRepository(pool),
+		affinityRepo: postgres.NewAffinityRepository(pool),
+		prRepo:       postgres.NewPRRepository(pool),
+		webhookRepo:  postgres.NewWebhookRepository(pool),
+		outboxRepo:   postgres.NewOutboxRepository(pool),
+		accountRepo:  postgres.NewCodeHostAccountRepository(pool),
+		syncRepo:     postgres.NewReviewerSyncRepository(pool),
+		txManager:    repository.NewPgxTransactionManager(pool),
+		elector:      postgres.NewAdvisoryLock(pool, jobsLockKey),
+		close:        func() { database.Close(pool) },
+	}, nil
+}
+
+// newSQLiteStorage opens the SQLite database file and checks (or applies) migrations
+func newSQLiteStorage(cfg *config.Config) (*storage, error) {
+	db, err := database.NewSQLiteDB(cfg.Storage.SQLitePath)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check (or apply) database migrations
+	migrateCtx, cancel := context.WithTimeout(context.Background(), migrationTimeout)
+	defer cancel()
+
+	m, err := migrator.NewSQLite(db)
+	if err != nil {
+		database.CloseSQLite(db)
+		return nil, err
+	}
+	defer m.Close()
+
+	if err := migrator.EnsureSchema(migrateCtx, m, cfg.Database.AutoMigrate); err != nil {
+		database.CloseSQLite(db)
+		return nil, err
+	}
+
+	return &storage{
+		teamRepo:     sqlite.NewTeamRepository(db),
+		userRepo:     sqlite.NewUserRepository(db),
+		absenceRepo:  sqlite.NewAbsenceRepository(db),
+		affinityRepo: sqlite.NewAffinityRepository(db),
+		prRepo:       sqlite.NewPRRepository(db),
+		webhookRepo:  sqlite.NewWebhookRepository(db),
+		outboxRepo:   sqlite.NewOutboxRepository(db),
+		accountRepo:  sqlite.NewCodeHostAccountRepository(db),
+		syncRepo:     sqlite.NewReviewerSyncRepository(db),
+		txManager:    sqlite.NewTransactionManager(db),
+		elector:      jobs.LocalElector{},
+		close:        func() { database.CloseSQLite(db) },
+	}, nil
+}
+
+// newMemoryStorage creates an empty in-memory storage
+func newMemoryStorage() *storage {
+	logger.Warn("Using in-memory storage: all data will be lost on restart")
+
+	store := memory.NewStore()
+	return &storage{
+		teamRepo:     memory.NewTeamRepository(store),
+		userRepo:     memory.NewUserRepository(store),
+		absenceRepo:  memory.NewAbsenceRepository(store),
+		affinityRepo: memory.NewAffinityRepository(store),
+		prRepo:       memory.NewPRRepository(store),
+		webhookRepo:  memory.NewWebhookRepository(store),
+		outboxRepo:   memory.NewOutboxRepository(store),
+		accountRepo:  memory.NewCodeHostAccountRepository(store),
+		syncRepo:     memory.NewReviewerSyncRepository(store),
+		txManager:    memory.NewTransactionManager(store),
+		elector:      jobs.LocalElector{},
+		close:        func() {},
+	}
+}
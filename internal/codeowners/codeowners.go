@@ -0,0 +1,102 @@
+// Package codeowners reads CODEOWNERS files and finds the owners of changed paths
+//
+// Patterns follow the GitHub subset of gitignore rules: a pattern without a slash
+// matches at any depth, a leading or inner slash anchors it to the repository root,
+// a trailing slash matches directories only, "*" and "?" stay within one path segment
+// and "**" spans any number of segments. Negation ("!") and character ranges are not
+// supported. Owners are written as @<user_id> or @team/<team_name>.
+package codeowners
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strings"
+
+	"avito-backend-trainee-assignment-autumn-2025/internal/domain/models"
+	pkgerrors "avito-backend-trainee-assignment-autumn-2025/pkg/errors"
+)
+
+// MaxRules limits the number of rules in a single file
+const MaxRules = 1000
+
+// maxLineLength limits a single line of the file
+const maxLineLength = 4096
+
+// Parse returns the rules of a CODEOWNERS file in file order
+// Blank lines and comments are skipped; an empty file yields no rules
+func Parse(data []byte) ([]models.CodeOwnerRule, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(bytes.TrimPrefix(data, []byte("\ufeff"))))
+	scanner.Buffer(make([]byte, 0, 1024), maxLineLength)
+
+	var rules []models.CodeOwnerRule
+	line := 0
+	for scanner.Scan() {
+		line++
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		for i, field := range fields {
+			if strings.HasPrefix(field, "#") {
+				fields = fields[:i]
+				break
+			}
+		}
+
+		if _, err := Compile(fields[0]); err != nil {
+			return nil, ruleError(line, err.Error())
+		}
+		owners := make([]models.CodeOwner, 0, len(fields)-1)
+		for _, token := range fields[1:] {
+			owner, err := models.ParseCodeOwner(token)
+			if err != nil {
+				return nil, ruleError(line, err.Error())
+			}
+			owners = append(owners, owner)
+		}
+
+		if len(rules) == MaxRules {
+			return nil, pkgerrors.NewValidationError("codeowners", fmt.Sprintf("has more than %d rules", MaxRules))
+		}
+		rules = append(rules, models.CodeOwnerRule{Line: line, Pattern: fields[0], Owners: owners})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, pkgerrors.NewBadRequestError("failed to read CODEOWNERS", err)
+	}
+
+	return rules, nil
+}
+
+func ruleError(line int, message string) error {
+	return pkgerrors.NewValidationError("codeowners", fmt.Sprintf("line %d: %s", line, message))
+}
+
+// Matcher finds the owners of a path by the last matching rule
+type Matcher struct {
+	patterns []*Pattern
+	rules    []models.CodeOwnerRule
+}
+
+// NewMatcher compiles the patterns of rules that are already validated by Parse
+func NewMatcher(rules []models.CodeOwnerRule) (*Matcher, error) {
+	patterns := make([]*Pattern, len(rules))
+	for i, rule := range rules {
+		pattern, err := Compile(rule.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", rule.Line, err)
+		}
+		patterns[i] = pattern
+	}
+	return &Matcher{patterns: patterns, rules: rules}, nil
+}
+
+// Owners returns the owners of filePath; nil when no rule matches or the matching rule has no owners
+func (m *Matcher) Owners(filePath string) []models.CodeOwner {
+	for i := len(m.patterns) - 1; i >= 0; i-- {
+		if m.patterns[i].Match(filePath) {
+			return m.rules[i].Owners
+		}
+	}
+	return nil
+}
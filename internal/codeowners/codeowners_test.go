@@ -0,0 +1,206 @@
+package codeowners
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"avito-backend-trainee-assignment-autumn-2025/internal/domain/models"
+	pkgerrors "avito-backend-trainee-assignment-autumn-2025/pkg/errors"
+)
+
+// loadFixture reads a CODEOWNERS file from testdata
+func loadFixture(t *testing.T, name string) []byte {
+	t.Helper()
+	body, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatalf("Failed to read fixture %s: %v", name, err)
+	}
+	return body
+}
+
+func TestParse(t *testing.T) {
+	rules, err := Parse(loadFixture(t, "CODEOWNERS"))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	want := []models.CodeOwnerRule{
+		{Line: 2, Pattern: "*", Owners: []models.CodeOwner{{TeamName: "backend"}}},
+		{Line: 5, Pattern: "/internal/handlers/", Owners: []models.CodeOwner{{UserID: "u1"}, {UserID: "u2"}}},
+		{Line: 6, Pattern: "*.sql", Owners: []models.CodeOwner{{UserID: "u3"}}},
+		{Line: 9, Pattern: "web/", Owners: []models.CodeOwner{{TeamName: "frontend"}}},
+		{Line: 10, Pattern: "docs/*", Owners: []models.CodeOwner{{UserID: "u4"}}},
+		{Line: 13, Pattern: "/api/gen/**", Owners: []models.CodeOwner{}},
+	}
+	if !reflect.DeepEqual(rules, want) {
+		t.Errorf("Parse() = %+v, want %+v", rules, want)
+	}
+}
+
+func TestMatcherOwners(t *testing.T) {
+	rules, err := Parse(loadFixture(t, "CODEOWNERS"))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	matcher, err := NewMatcher(rules)
+	if err != nil {
+		t.Fatalf("NewMatcher: %v", err)
+	}
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		{path: "main.go", want: "@team/backend"},
+		{path: "internal/handlers/pr.go", want: "@u1 @u2"},
+		{path: "/internal/handlers/v2/team.go", want: "@u1 @u2"},
+		{path: "cmd/internal/handlers/pr.go", want: "@team/backend"},
+		{path: "internal/handlers/queries.sql", want: "@u3"},
+		{path: "migrations/00001_init.sql", want: "@u3"},
+		{path: "web/app.tsx", want: "@team/frontend"},
+		{path: "client/web/index.html", want: "@team/frontend"},
+		{path: "docs/README.md", want: "@u4"},
+		{path: "docs/api/README.md", want: "@team/backend"},
+		{path: "api/gen/server.go", want: ""},
+		{path: "api/gen/v1/types.go", want: ""},
+		{path: "./api/openapi.yaml", want: "@team/backend"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			got := models.FormatCodeOwners(matcher.Owners(tt.path))
+			if got != tt.want {
+				t.Errorf("Owners(%q) = %q, want %q", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPatternMatch(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		want    bool
+	}{
+		{pattern: "*.js", path: "src/app.js", want: true},
+		{pattern: "*.js", path: "src/app.jsx", want: false},
+		{pattern: "/build/logs/", path: "build/logs/today/app.log", want: true},
+		{pattern: "/build/logs/", path: "src/build/logs/app.log", want: false},
+		{pattern: "apps/", path: "src/apps/main.go", want: true},
+		{pattern: "apps/", path: "apps", want: false},
+		{pattern: "docs/*", path: "docs/intro.md", want: true},
+		{pattern: "docs/*", path: "docs/guides/intro.md", want: false},
+		{pattern: "docs", path: "docs/guides/intro.md", want: true},
+		{pattern: "**/logs", path: "deeply/nested/logs/app.log", want: true},
+		{pattern: "/docs/**/*.md", path: "docs/intro.md", want: true},
+		{pattern: "/docs/**/*.md", path: "docs/a/b/intro.md", want: true},
+		{pattern: "/docs/**", path: "docs", want: false},
+		{pattern: "/**/a/**/a/**/a/**/a/**/a/**/a/**/b", path: strings.Repeat("a/", 60) + "c", want: false},
+		{pattern: "/**/a/**/a/**/a/**/a/**/a/**/a/**/b", path: strings.Repeat("a/", 60) + "b", want: true},
+		{pattern: "app?.go", path: "cmd/app1.go", want: true},
+		{pattern: `\#notes`, path: "#notes", want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern+" "+tt.path, func(t *testing.T) {
+			pattern, err := Compile(tt.pattern)
+			if err != nil {
+				t.Fatalf("Compile(%q): %v", tt.pattern, err)
+			}
+			if got := pattern.Match(tt.path); got != tt.want {
+				t.Errorf("Compile(%q).Match(%q) = %v, want %v", tt.pattern, tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		data       string
+		badRequest bool
+		message    string
+	}{
+		{
+			name:    "Negated pattern",
+			data:    "* @u1\n!vendor/ @u2\n",
+			message: "line 2: negated patterns are not supported",
+		},
+		{
+			name:    "Character range",
+			data:    "*.[ch] @u1\n",
+			message: "line 1: character ranges are not supported",
+		},
+		{
+			name:    "Partial double star",
+			data:    "docs/**.md @u1\n",
+			message: `line 1: "**" must be a whole path segment`,
+		},
+		{
+			name:    "Empty segment",
+			data:    "docs//intro.md @u1\n",
+			message: "line 1: pattern has an empty path segment",
+		},
+		{
+			name:    "Root only",
+			data:    "/ @u1\n",
+			message: "line 1: pattern matches no path",
+		},
+		{
+			name:    "Email owner",
+			data:    "* dev@example.com\n",
+			message: `line 1: owner "dev@example.com" must be @<user_id> or @team/<team_name>`,
+		},
+		{
+			name:    "Team without name",
+			data:    "* @team/\n",
+			message: `line 1: owner "@team/" has no team name`,
+		},
+		{
+			name:    "Too many rules",
+			data:    strings.Repeat("* @u1\n", MaxRules+1),
+			message: "has more than 1000 rules",
+		},
+		{
+			name:       "Line too long",
+			data:       "* " + strings.Repeat("@u1 ", maxLineLength),
+			badRequest: true,
+			message:    "failed to read CODEOWNERS",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]byte(tt.data))
+			if err == nil {
+				t.Fatal("Expected an error, got nil")
+			}
+
+			var badRequestErr *pkgerrors.BadRequestError
+			var validationErr *pkgerrors.ValidationError
+			if tt.badRequest && !errors.As(err, &badRequestErr) {
+				t.Errorf("Expected a bad request error, got %T: %v", err, err)
+			}
+			if !tt.badRequest && !errors.As(err, &validationErr) {
+				t.Errorf("Expected a validation error, got %T: %v", err, err)
+			}
+			if !strings.Contains(err.Error(), tt.message) {
+				t.Errorf("Error %q does not mention %q", err.Error(), tt.message)
+			}
+		})
+	}
+}
+
+func TestParseEmpty(t *testing.T) {
+	rules, err := Parse([]byte("# no rules yet\n\n"))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if len(rules) != 0 {
+		t.Errorf("Expected no rules, got %+v", rules)
+	}
+}
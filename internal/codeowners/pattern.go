@@ -0,0 +1,118 @@
+package codeowners
+
+import (
+	"errors"
+	"path"
+	"strings"
+)
+
+// anySegments matches zero or more path segments
+const anySegments = "**"
+
+// Pattern is a compiled CODEOWNERS path pattern
+type Pattern struct {
+	segments []string
+	// dirOnly is set by a trailing slash: the pattern matches only what is inside a directory
+	dirOnly bool
+	// literalLast allows the pattern to match a directory and everything inside it
+	literalLast bool
+}
+
+// Compile validates a pattern and prepares it for matching
+func Compile(pattern string) (*Pattern, error) {
+	if strings.HasPrefix(pattern, "!") {
+		return nil, errors.New("negated patterns are not supported")
+	}
+	if strings.ContainsAny(pattern, "[]") {
+		return nil, errors.New("character ranges are not supported")
+	}
+
+	// "\#" starts a pattern with a literal "#" instead of a comment
+	p := pattern
+	if strings.HasPrefix(p, `\#`) {
+		p = p[1:]
+	}
+	dirOnly := strings.HasSuffix(p, "/")
+	p = strings.TrimSuffix(p, "/")
+	anchored := strings.Contains(p, "/")
+	p = strings.TrimPrefix(p, "/")
+	if p == "" {
+		return nil, errors.New("pattern matches no path")
+	}
+
+	segments := strings.Split(p, "/")
+	for _, segment := range segments {
+		if segment == "" {
+			return nil, errors.New("pattern has an empty path segment")
+		}
+		if segment != anySegments && strings.Contains(segment, anySegments) {
+			return nil, errors.New(`"**" must be a whole path segment`)
+		}
+		if _, err := path.Match(segment, ""); err != nil {
+			return nil, errors.New("malformed pattern")
+		}
+	}
+	if !anchored {
+		segments = append([]string{anySegments}, segments...)
+	}
+	// A trailing "**" matches what is inside the directory, not the directory itself
+	if segments[len(segments)-1] == anySegments {
+		segments = append(segments, "*")
+	}
+
+	last := segments[len(segments)-1]
+	return &Pattern{
+		segments:    segments,
+		dirOnly:     dirOnly,
+		literalLast: !strings.ContainsAny(last, `*?\`),
+	}, nil
+}
+
+// Match reports whether the pattern owns filePath, a path relative to the repository root
+func (p *Pattern) Match(filePath string) bool {
+	filePath = strings.TrimPrefix(path.Clean("/"+filePath), "/")
+	if filePath == "" {
+		return false
+	}
+	parts := strings.Split(filePath, "/")
+	matched := matchPrefixes(p.segments, parts)
+
+	if !p.dirOnly && matched[len(parts)] {
+		return true
+	}
+	if !p.dirOnly && !p.literalLast {
+		return false
+	}
+	for end := len(parts) - 1; end > 0; end-- {
+		if matched[end] {
+			return true
+		}
+	}
+	return false
+}
+
+// matchPrefixes reports for every end whether the segments match parts[:end]
+// It takes one pass per segment, so patterns with several "**" stay linear in the path length
+func matchPrefixes(segments, parts []string) []bool {
+	matched := make([]bool, len(parts)+1)
+	matched[0] = true
+	for _, segment := range segments {
+		next := make([]bool, len(parts)+1)
+		if segment == anySegments {
+			// "**" extends every match by zero or more parts
+			reached := false
+			for end := range matched {
+				reached = reached || matched[end]
+				next[end] = reached
+			}
+		} else {
+			for end := 1; end <= len(parts); end++ {
+				if matched[end-1] {
+					next[end], _ = path.Match(segment, parts[end-1])
+				}
+			}
+		}
+		matched = next
+	}
+	return matched
+}
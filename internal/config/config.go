@@ -2,21 +2,52 @@ package config
 
 import (
 	"fmt"
+	"net"
+	"net/mail"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/joho/godotenv"
 )
 
 type Config struct {
-	Server   ServerConfig
-	Database DatabaseConfig
-	App      AppConfig
+	Server       ServerConfig
+	Storage      StorageConfig
+	Database     DatabaseConfig
+	Webhook      WebhookConfig
+	Outbox       OutboxConfig
+	Integrations IntegrationsConfig
+	ReviewerSync ReviewerSyncConfig
+	Slack        SlackConfig
+	SMTP         SMTPConfig
+	Jobs         JobsConfig
+	App          AppConfig
 }
 
 type ServerConfig struct {
-	Port string
+	Port     string
+	GRPCPort string
+
+	// OpenAPIValidation is one of: off, request, full
+	OpenAPIValidation string
+}
+
+// Storage backends
+const (
+	StoragePostgres = "postgres"
+	StorageMemory   = "memory"
+	StorageSQLite   = "sqlite"
+)
+
+type StorageConfig struct {
+	// Type is one of: postgres, memory, sqlite
+	// memory keeps all data in process and loses it on restart (tests and demos only)
+	Type string
+
+	// SQLitePath is the database file used by the sqlite backend
+	SQLitePath string
 }
 
 type DatabaseConfig struct {
@@ -31,6 +62,109 @@ type DatabaseConfig struct {
 	MinConns        int32
 	MaxConnLifetime time.Duration
 	MaxConnIdleTime time.Duration
+
+	// AutoMigrate applies pending migrations on startup instead of refusing to start
+	AutoMigrate bool
+}
+
+type WebhookConfig struct {
+	// MaxAttempts is the number of delivery attempts before a delivery is dead-lettered
+	MaxAttempts int
+
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	Timeout        time.Duration
+	PollInterval   time.Duration
+
+	// AllowPrivateNetworks accepts webhook URLs on loopback, private and link-local addresses
+	AllowPrivateNetworks bool
+}
+
+// Outbox sinks
+const (
+	OutboxSinkWebhook  = "webhook"
+	OutboxSinkStdout   = "stdout"
+	OutboxSinkFile     = "file"
+	OutboxSinkCodeHost = "codehost"
+	OutboxSinkSlack    = "slack"
+	OutboxSinkEmail    = "email"
+)
+
+type OutboxConfig struct {
+	// Sinks lists where outbox events go: webhook, stdout, file, codehost, slack, email
+	Sinks []string
+
+	// FilePath is the JSON Lines file used by the file sink
+	FilePath string
+
+	PollInterval time.Duration
+	BatchSize    int
+
+	// Retention is how long published messages are kept; 0 keeps them forever
+	Retention time.Duration
+
+	// MaxAttempts is the number of publish attempts before a message is dead-lettered
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
+
+type IntegrationsConfig struct {
+	// GitHubWebhookSecret verifies X-Hub-Signature-256; the GitHub webhook is disabled when empty
+	GitHubWebhookSecret string
+
+	// GitLabWebhookToken is compared with X-Gitlab-Token; the GitLab webhook is disabled when empty
+	GitLabWebhookToken string
+
+	// GitHubAPIURL is the base URL of the GitHub REST API (differs for GitHub Enterprise)
+	GitHubAPIURL string
+	// GitHubToken authenticates reviewer requests; required by the codehost outbox sink
+	GitHubToken   string
+	GitHubTimeout time.Duration
+}
+
+type ReviewerSyncConfig struct {
+	// MaxAttempts is the number of attempts before a reviewer sync is given up
+	MaxAttempts int
+
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	PollInterval   time.Duration
+}
+
+type SlackConfig struct {
+	// QueueSize bounds chat messages waiting to be sent; further messages are dropped
+	QueueSize int
+	// MinInterval is the minimum time between messages to the same team webhook
+	MinInterval time.Duration
+	Timeout     time.Duration
+}
+
+type SMTPConfig struct {
+	// Addr is the SMTP server as host:port
+	Addr     string
+	Username string
+	Password string
+	// From is the sender address, optionally with a display name
+	From string
+	// QueueSize bounds emails waiting to be sent; further emails are dropped
+	QueueSize int
+	// Senders is the number of SMTP sessions run in parallel
+	Senders int
+	Timeout time.Duration
+}
+
+type JobsConfig struct {
+	// Enabled runs background jobs on the replica that holds the job lock
+	Enabled bool
+	// ElectionInterval is how often replicas try to take or confirm job leadership
+	ElectionInterval time.Duration
+
+	// ReminderInterval is how often stale reviews are looked up
+	ReminderInterval time.Duration
+	// ReviewSLA is how long a reviewer may keep a review before a reminder, unless the team sets its own
+	ReviewSLA         time.Duration
+	ReminderBatchSize int
 }
 
 type AppConfig struct {
@@ -45,7 +179,13 @@ func Load() (*Config, error) {
 
 	cfg := &Config{
 		Server: ServerConfig{
-			Port: getEnv("SERVER_PORT", "8080"),
+			Port:              getEnv("SERVER_PORT", "8080"),
+			GRPCPort:          getEnv("GRPC_PORT", "9090"),
+			OpenAPIValidation: getEnv("OPENAPI_VALIDATION", "request"),
+		},
+		Storage: StorageConfig{
+			Type:       getEnv("STORAGE", StoragePostgres),
+			SQLitePath: getEnv("SQLITE_PATH", "pr_reviewer.db"),
 		},
 		Database: DatabaseConfig{
 			Host:            getEnv("DB_HOST", "localhost"),
@@ -58,6 +198,59 @@ func Load() (*Config, error) {
 			MinConns:        int32(getEnvAsInt("DB_MIN_CONNS", 5)),
 			MaxConnLifetime: getEnvAsDuration("DB_MAX_CONN_LIFETIME", "1h"),
 			MaxConnIdleTime: getEnvAsDuration("DB_MAX_CONN_IDLE_TIME", "30m"),
+			AutoMigrate:     getEnvAsBool("AUTO_MIGRATE", false),
+		},
+		Webhook: WebhookConfig{
+			MaxAttempts:          getEnvAsInt("WEBHOOK_MAX_ATTEMPTS", 6),
+			InitialBackoff:       getEnvAsDuration("WEBHOOK_INITIAL_BACKOFF", "1s"),
+			MaxBackoff:           getEnvAsDuration("WEBHOOK_MAX_BACKOFF", "5m"),
+			Timeout:              getEnvAsDuration("WEBHOOK_TIMEOUT", "10s"),
+			PollInterval:         getEnvAsDuration("WEBHOOK_POLL_INTERVAL", "1s"),
+			AllowPrivateNetworks: getEnvAsBool("WEBHOOK_ALLOW_PRIVATE_NETWORKS", false),
+		},
+		Outbox: OutboxConfig{
+			Sinks:          getEnvAsList("OUTBOX_SINKS", OutboxSinkWebhook),
+			FilePath:       getEnv("OUTBOX_FILE_PATH", "outbox.jsonl"),
+			PollInterval:   getEnvAsDuration("OUTBOX_POLL_INTERVAL", "1s"),
+			BatchSize:      getEnvAsInt("OUTBOX_BATCH_SIZE", 100),
+			Retention:      getEnvAsDuration("OUTBOX_RETENTION", "168h"),
+			MaxAttempts:    getEnvAsInt("OUTBOX_MAX_ATTEMPTS", 10),
+			InitialBackoff: getEnvAsDuration("OUTBOX_INITIAL_BACKOFF", "1s"),
+			MaxBackoff:     getEnvAsDuration("OUTBOX_MAX_BACKOFF", "5m"),
+		},
+		Integrations: IntegrationsConfig{
+			GitHubWebhookSecret: getEnv("GITHUB_WEBHOOK_SECRET", ""),
+			GitLabWebhookToken:  getEnv("GITLAB_WEBHOOK_TOKEN", ""),
+			GitHubAPIURL:        getEnv("GITHUB_API_URL", "https://api.github.com"),
+			GitHubToken:         getEnv("GITHUB_TOKEN", ""),
+			GitHubTimeout:       getEnvAsDuration("GITHUB_TIMEOUT", "10s"),
+		},
+		ReviewerSync: ReviewerSyncConfig{
+			MaxAttempts:    getEnvAsInt("REVIEWER_SYNC_MAX_ATTEMPTS", 6),
+			InitialBackoff: getEnvAsDuration("REVIEWER_SYNC_INITIAL_BACKOFF", "1s"),
+			MaxBackoff:     getEnvAsDuration("REVIEWER_SYNC_MAX_BACKOFF", "5m"),
+			PollInterval:   getEnvAsDuration("REVIEWER_SYNC_POLL_INTERVAL", "1s"),
+		},
+		Slack: SlackConfig{
+			QueueSize:   getEnvAsInt("SLACK_QUEUE_SIZE", 1000),
+			MinInterval: getEnvAsDuration("SLACK_MIN_INTERVAL", "1s"),
+			Timeout:     getEnvAsDuration("SLACK_TIMEOUT", "10s"),
+		},
+		SMTP: SMTPConfig{
+			Addr:      getEnv("SMTP_ADDR", "localhost:25"),
+			Username:  getEnv("SMTP_USERNAME", ""),
+			Password:  getEnv("SMTP_PASSWORD", ""),
+			From:      getEnv("SMTP_FROM", "PR Reviewer <noreply@localhost>"),
+			QueueSize: getEnvAsInt("SMTP_QUEUE_SIZE", 1000),
+			Senders:   getEnvAsInt("SMTP_SENDERS", 4),
+			Timeout:   getEnvAsDuration("SMTP_TIMEOUT", "30s"),
+		},
+		Jobs: JobsConfig{
+			Enabled:           getEnvAsBool("JOBS_ENABLED", true),
+			ElectionInterval:  getEnvAsDuration("JOBS_ELECTION_INTERVAL", "10s"),
+			ReminderInterval:  getEnvAsDuration("REMINDER_INTERVAL", "5m"),
+			ReviewSLA:         getEnvAsDuration("REVIEW_SLA", "24h"),
+			ReminderBatchSize: getEnvAsInt("REMINDER_BATCH_SIZE", 100),
 		},
 		App: AppConfig{
 			Env:      getEnv("APP_ENV", "development"),
@@ -77,6 +270,97 @@ func (c *Config) Validate() error {
 	if c.Server.Port == "" {
 		return fmt.Errorf("SERVER_PORT is required")
 	}
+	if c.Server.GRPCPort == "" {
+		return fmt.Errorf("GRPC_PORT is required")
+	}
+	if c.Server.GRPCPort == c.Server.Port {
+		return fmt.Errorf("GRPC_PORT must differ from SERVER_PORT")
+	}
+	switch c.Server.OpenAPIValidation {
+	case "off", "request", "full":
+	default:
+		return fmt.Errorf("OPENAPI_VALIDATION must be one of: off, request, full")
+	}
+	if c.Webhook.MaxAttempts < 1 {
+		return fmt.Errorf("WEBHOOK_MAX_ATTEMPTS must be at least 1")
+	}
+	if c.Webhook.PollInterval <= 0 {
+		return fmt.Errorf("WEBHOOK_POLL_INTERVAL must be positive")
+	}
+	for _, sink := range c.Outbox.Sinks {
+		switch sink {
+		case OutboxSinkWebhook, OutboxSinkStdout, OutboxSinkSlack, OutboxSinkEmail:
+		case OutboxSinkFile:
+			if c.Outbox.FilePath == "" {
+				return fmt.Errorf("OUTBOX_FILE_PATH is required for the file sink")
+			}
+		case OutboxSinkCodeHost:
+			if c.Integrations.GitHubToken == "" {
+				return fmt.Errorf("GITHUB_TOKEN is required for the codehost sink")
+			}
+		default:
+			return fmt.Errorf("OUTBOX_SINKS must contain only: %s, %s, %s, %s, %s, %s",
+				OutboxSinkWebhook, OutboxSinkStdout, OutboxSinkFile, OutboxSinkCodeHost, OutboxSinkSlack, OutboxSinkEmail)
+		}
+	}
+	if c.ReviewerSync.MaxAttempts < 1 {
+		return fmt.Errorf("REVIEWER_SYNC_MAX_ATTEMPTS must be at least 1")
+	}
+	if c.ReviewerSync.PollInterval <= 0 {
+		return fmt.Errorf("REVIEWER_SYNC_POLL_INTERVAL must be positive")
+	}
+	if c.Slack.QueueSize < 1 {
+		return fmt.Errorf("SLACK_QUEUE_SIZE must be at least 1")
+	}
+	if c.Slack.MinInterval < 0 {
+		return fmt.Errorf("SLACK_MIN_INTERVAL must not be negative")
+	}
+	if _, _, err := net.SplitHostPort(c.SMTP.Addr); err != nil {
+		return fmt.Errorf("SMTP_ADDR must be host:port: %w", err)
+	}
+	if _, err := mail.ParseAddress(c.SMTP.From); err != nil {
+		return fmt.Errorf("SMTP_FROM must be an email address: %w", err)
+	}
+	if c.SMTP.QueueSize < 1 {
+		return fmt.Errorf("SMTP_QUEUE_SIZE must be at least 1")
+	}
+	if c.SMTP.Senders < 1 {
+		return fmt.Errorf("SMTP_SENDERS must be at least 1")
+	}
+	if c.Jobs.ElectionInterval <= 0 {
+		return fmt.Errorf("JOBS_ELECTION_INTERVAL must be positive")
+	}
+	if c.Jobs.ReminderInterval <= 0 {
+		return fmt.Errorf("REMINDER_INTERVAL must be positive")
+	}
+	if c.Jobs.ReviewSLA < time.Minute {
+		return fmt.Errorf("REVIEW_SLA must be at least 1m")
+	}
+	if c.Jobs.ReminderBatchSize < 1 {
+		return fmt.Errorf("REMINDER_BATCH_SIZE must be at least 1")
+	}
+	if c.Outbox.PollInterval <= 0 {
+		return fmt.Errorf("OUTBOX_POLL_INTERVAL must be positive")
+	}
+	if c.Outbox.BatchSize < 1 {
+		return fmt.Errorf("OUTBOX_BATCH_SIZE must be at least 1")
+	}
+	if c.Outbox.MaxAttempts < 1 {
+		return fmt.Errorf("OUTBOX_MAX_ATTEMPTS must be at least 1")
+	}
+	switch c.Storage.Type {
+	case StoragePostgres:
+	case StorageMemory:
+		// No database settings needed
+		return nil
+	case StorageSQLite:
+		if c.Storage.SQLitePath == "" {
+			return fmt.Errorf("SQLITE_PATH is required")
+		}
+		return nil
+	default:
+		return fmt.Errorf("STORAGE must be one of: %s, %s, %s", StoragePostgres, StorageMemory, StorageSQLite)
+	}
 	if c.Database.Host == "" {
 		return fmt.Errorf("DB_HOST is required")
 	}
@@ -113,6 +397,35 @@ func getEnvAsInt(key string, defaultValue int) int {
 	return value
 }
 
+// getEnvAsList gets a comma-separated environment variable as a list or returns a default value
+// Empty items are dropped, so "" yields an empty list
+func getEnvAsList(key string, defaultValue string) []string {
+	valueStr, ok := os.LookupEnv(key)
+	if !ok {
+		valueStr = defaultValue
+	}
+	var values []string
+	for _, item := range strings.Split(valueStr, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			values = append(values, item)
+		}
+	}
+	return values
+}
+
+// getEnvAsBool gets an environment variable as bool or returns a default value
+func getEnvAsBool(key string, defaultValue bool) bool {
+	valueStr := os.Getenv(key)
+	if valueStr == "" {
+		return defaultValue
+	}
+	value, err := strconv.ParseBool(valueStr)
+	if err != nil {
+		return defaultValue
+	}
+	return value
+}
+
 // getEnvAsDuration gets an environment variable as duration or returns a default value
 func getEnvAsDuration(key string, defaultValue string) time.Duration {
 	valueStr := os.Getenv(key)
@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+// DateLayout формат календарной даты в API и в SQLite
+const DateLayout = "2006-01-02"
+
+// Absence период отсутствия пользователя (отпуск, больничный); обе даты входят в период
+// Пока пользователь отсутствует, он не назначается ревьюером, is_active при этом не меняется
+type Absence struct {
+	ID     string `json:"absence_id" db:"id"`
+	UserID string `json:"user_id" db:"user_id"`
+	// StartDate и EndDate — календарные даты, хранятся как полночь UTC
+	StartDate time.Time `json:"start_date" db:"start_date"`
+	EndDate   time.Time `json:"end_date" db:"end_date"`
+	Reason    string    `json:"reason,omitempty" db:"reason"`
+	// SourceUID — UID события календаря, из которого импортировано отсутствие; пусто у добавленных вручную
+	SourceUID string    `json:"source_uid,omitempty" db:"source_uid"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// Covers проверяет, что дата попадает в период отсутствия
+func (a *Absence) Covers(date time.Time) bool {
+	return !date.Before(a.StartDate) && !date.After(a.EndDate)
+}
+
+// DateIn возвращает календарную дату момента t в часовом поясе loc (полночь UTC)
+func DateIn(t time.Time, loc *time.Location) time.Time {
+	year, month, day := t.In(loc).Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,40 @@
+package models
+
+// Границы веса предпочтения автора к ревьюеру
+const (
+	MinAffinityWeight = -10
+	MaxAffinityWeight = 10
+)
+
+// affinityScale делится на 1+|w| для любого допустимого веса w, поэтому SelectionWeight всегда целый
+const affinityScale = 27720
+
+// Affinity отношение автора PR к возможному ревьюеру
+// Conflict — конфликт интересов: пара не ревьюит друг друга ни в одну сторону, вес при этом должен быть нулевым
+// Положительный вес w делает ревьюера в 1+w раз вероятнее нейтрального при выборе, отрицательный —
+// в 1+|w| раз менее вероятным; нулевой вес нейтрален, как и отсутствие записи
+type Affinity struct {
+	AuthorID   string `json:"author_id" db:"author_id"`
+	ReviewerID string `json:"reviewer_id" db:"reviewer_id"`
+	Weight     int    `json:"weight" db:"weight"`
+	Conflict   bool   `json:"conflict" db:"conflict"`
+	Reason     string `json:"reason,omitempty" db:"reason"`
+}
+
+// IsConflict проверяет, что пара не должна ревьюить друг друга
+func (a *Affinity) IsConflict() bool {
+	return a.Conflict
+}
+
+// SelectionWeight относительный вес ревьюера с весом предпочтения weight при случайном выборе;
+// у нейтрального ревьюера он равен affinityScale
+func SelectionWeight(weight int) int {
+	switch {
+	case weight > 0:
+		return (1 + weight) * affinityScale
+	case weight < 0:
+		return affinityScale / (1 - weight)
+	default:
+		return affinityScale
+	}
+}
@@ -0,0 +1,123 @@
+package models
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CodeHost внешний хостинг кода, из которого приходят события о PR
+type CodeHost string
+
+const (
+	CodeHostGitHub CodeHost = "github"
+	CodeHostGitLab CodeHost = "gitlab"
+)
+
+// IsValid проверяет, что хостинг известен
+func (h CodeHost) IsValid() bool {
+	return h == CodeHostGitHub || h == CodeHostGitLab
+}
+
+// CodeHostAccount связь логина на хостинге кода с пользователем сервиса
+type CodeHostAccount struct {
+	Provider  CodeHost  `json:"provider" db:"provider"`
+	Login     string    `json:"login" db:"login"`
+	UserID    string    `json:"user_id" db:"user_id"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// PRAction что произошло с PR на хостинге кода
+type PRAction string
+
+const (
+	PRActionOpened   PRAction = "opened"
+	PRActionReopened PRAction = "reopened"
+	PRActionMerged   PRAction = "merged"
+	// PRActionClosed PR закрыт без мержа
+	PRActionClosed PRAction = "closed"
+)
+
+// CodeHostPR ссылка на PR (merge request) во внешнем хостинге кода
+type CodeHostPR struct {
+	Provider CodeHost
+	// Repository полное имя репозитория (owner/name или group/project)
+	Repository string
+	Number     int
+}
+
+// ID возвращает идентификатор PR в сервисе в нотации хостинга,
+// например github:octo-org/api#42 или gitlab:platform/api!7
+func (pr CodeHostPR) ID() string {
+	return fmt.Sprintf("%s:%s%s%d", pr.Provider, pr.Repository, pr.Provider.numberSeparator(), pr.Number)
+}
+
+// ParseCodeHostPR разбирает идентификатор PR, созданного из события хостинга
+// Возвращает false для PR, созданных через API вручную
+func ParseCodeHostPR(id string) (CodeHostPR, bool) {
+	provider, rest, found := strings.Cut(id, ":")
+	codeHost := CodeHost(provider)
+	if !found || !codeHost.IsValid() {
+		return CodeHostPR{}, false
+	}
+
+	separator := strings.LastIndex(rest, codeHost.numberSeparator())
+	if separator <= 0 {
+		return CodeHostPR{}, false
+	}
+	number, err := strconv.Atoi(rest[separator+1:])
+	if err != nil || number <= 0 {
+		return CodeHostPR{}, false
+	}
+
+	return CodeHostPR{Provider: codeHost, Repository: rest[:separator], Number: number}, true
+}
+
+// numberSeparator отделяет номер PR от репозитория: #42 в GitHub, !7 в GitLab
+func (h CodeHost) numberSeparator() string {
+	if h == CodeHostGitLab {
+		return "!"
+	}
+	return "#"
+}
+
+// CodeHostPREvent событие о PR, приведенное к общему для всех хостингов виду
+type CodeHostPREvent struct {
+	Provider CodeHost
+	Action   PRAction
+	// Repository полное имя репозитория (owner/name или group/project)
+	Repository  string
+	Number      int
+	Title       string
+	AuthorLogin string
+}
+
+// PullRequestID возвращает идентификатор PR в сервисе
+func (e *CodeHostPREvent) PullRequestID() string {
+	return CodeHostPR{Provider: e.Provider, Repository: e.Repository, Number: e.Number}.ID()
+}
+
+// ReviewerSyncAction что нужно сделать с ревьюером на стороне хостинга
+type ReviewerSyncAction string
+
+const (
+	ReviewerSyncRequest ReviewerSyncAction = "request"
+	ReviewerSyncRemove  ReviewerSyncAction = "remove"
+)
+
+// ReviewerSync задача синхронизации ревьюера с PR во внешнем хостинге
+// Статусы те же, что у доставок вебхуков: pending, delivered (выполнено), failed
+type ReviewerSync struct {
+	ID            string             `json:"sync_id" db:"id"`
+	EventID       string             `json:"event_id" db:"event_id"`
+	PullRequestID string             `json:"pull_request_id" db:"pull_request_id"`
+	Action        ReviewerSyncAction `json:"action" db:"action"`
+	Login         string             `json:"login" db:"login"`
+	Status        DeliveryStatus     `json:"status" db:"status"`
+	Attempts      int                `json:"attempts" db:"attempts"`
+	LastError     string             `json:"last_error,omitempty" db:"last_error"`
+	NextAttemptAt time.Time          `json:"next_attempt_at" db:"next_attempt_at"`
+	CreatedAt     time.Time          `json:"created_at" db:"created_at"`
+	CompletedAt   *time.Time         `json:"completed_at,omitempty" db:"completed_at"`
+}
@@ -0,0 +1,70 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+// codeOwnerTeamPrefix отличает владельца-команду от владельца-пользователя в файле CODEOWNERS
+const codeOwnerTeamPrefix = "@team/"
+
+// CodeOwner владелец путей из CODEOWNERS: либо пользователь, либо вся команда
+type CodeOwner struct {
+	UserID   string `json:"user_id,omitempty"`
+	TeamName string `json:"team_name,omitempty"`
+}
+
+// ParseCodeOwner разбирает владельца в записи @<user_id> или @team/<team_name>
+func ParseCodeOwner(token string) (CodeOwner, error) {
+	if name, ok := strings.CutPrefix(token, codeOwnerTeamPrefix); ok {
+		if name == "" {
+			return CodeOwner{}, fmt.Errorf("owner %q has no team name", token)
+		}
+		return CodeOwner{TeamName: name}, nil
+	}
+	id, ok := strings.CutPrefix(token, "@")
+	if !ok || id == "" {
+		return CodeOwner{}, fmt.Errorf("owner %q must be @<user_id> or @team/<team_name>", token)
+	}
+	return CodeOwner{UserID: id}, nil
+}
+
+// String возвращает владельца в записи CODEOWNERS
+func (o CodeOwner) String() string {
+	if o.TeamName != "" {
+		return codeOwnerTeamPrefix + o.TeamName
+	}
+	return "@" + o.UserID
+}
+
+// CodeOwnerRule правило CODEOWNERS команды: шаблон пути и его владельцы
+// Правила применяются по порядку, для файла действует последнее подходящее; правило без владельцев снимает владение
+type CodeOwnerRule struct {
+	// Line — номер строки правила в загруженном файле
+	Line    int         `json:"line" db:"line"`
+	Pattern string      `json:"pattern" db:"pattern"`
+	Owners  []CodeOwner `json:"owners" db:"owners"`
+}
+
+// FormatCodeOwners записывает владельцев через пробел, как в строке CODEOWNERS
+func FormatCodeOwners(owners []CodeOwner) string {
+	tokens := make([]string, len(owners))
+	for i, owner := range owners {
+		tokens[i] = owner.String()
+	}
+	return strings.Join(tokens, " ")
+}
+
+// ParseCodeOwners разбирает владельцев, записанных через пробел
+func ParseCodeOwners(value string) ([]CodeOwner, error) {
+	tokens := strings.Fields(value)
+	owners := make([]CodeOwner, 0, len(tokens))
+	for _, token := range tokens {
+		owner, err := ParseCodeOwner(token)
+		if err != nil {
+			return nil, err
+		}
+		owners = append(owners, owner)
+	}
+	return owners, nil
+}
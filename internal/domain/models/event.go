@@ -0,0 +1,110 @@
+package models
+
+import "time"
+
+// EventType тип доменного события
+type EventType string
+
+const (
+	EventReviewerAssigned   EventType = "reviewer.assigned"
+	EventReviewerUnassigned EventType = "reviewer.unassigned"
+	EventPRCreated          EventType = "pr.created"
+	EventPRMerged           EventType = "pr.merged"
+	EventUserCreated        EventType = "user.created"
+	EventUserUpdated        EventType = "user.updated"
+	EventUserActivated      EventType = "user.activated"
+	EventUserDeactivated    EventType = "user.deactivated"
+	EventReviewReminder     EventType = "review.reminder"
+)
+
+// EventTypes все известные типы событий
+var EventTypes = []EventType{
+	EventReviewerAssigned,
+	EventReviewerUnassigned,
+	EventPRCreated,
+	EventPRMerged,
+	EventUserCreated,
+	EventUserUpdated,
+	EventUserActivated,
+	EventUserDeactivated,
+	EventReviewReminder,
+}
+
+// IsValid проверяет, что тип события известен
+func (t EventType) IsValid() bool {
+	for _, known := range EventTypes {
+		if t == known {
+			return true
+		}
+	}
+	return false
+}
+
+// Event доменное событие, которое рассылается подписчикам
+type Event struct {
+	ID         string    `json:"id"`
+	Type       EventType `json:"type"`
+	OccurredAt time.Time `json:"occurred_at"`
+	Data       any       `json:"data"`
+}
+
+// NewEvent создает событие с новым идентификатором и текущим временем
+func NewEvent(eventType EventType, data any) Event {
+	return Event{
+		ID:         NewID("evt"),
+		Type:       eventType,
+		OccurredAt: time.Now().UTC(),
+		Data:       data,
+	}
+}
+
+// ReviewerEventData данные событий reviewer.assigned и reviewer.unassigned
+type ReviewerEventData struct {
+	PullRequestID   string `json:"pull_request_id"`
+	PullRequestName string `json:"pull_request_name"`
+	AuthorID        string `json:"author_id"`
+	ReviewerID      string `json:"reviewer_id"`
+	// ReplacedReviewerID ревьюер, которого заменил ReviewerID при reassign (только в reviewer.assigned)
+	ReplacedReviewerID string `json:"replaced_reviewer_id,omitempty"`
+	// Reason причина замены (только в reviewer.assigned при reassign)
+	Reason ReassignReason `json:"reason,omitempty"`
+}
+
+// PRCreatedEventData данные события pr.created
+type PRCreatedEventData struct {
+	PullRequestID     string   `json:"pull_request_id"`
+	PullRequestName   string   `json:"pull_request_name"`
+	AuthorID          string   `json:"author_id"`
+	AssignedReviewers []string `json:"assigned_reviewers"`
+	RequiredTags      []string `json:"required_tags,omitempty"`
+}
+
+// PRMergedEventData данные события pr.merged
+type PRMergedEventData struct {
+	PullRequestID     string     `json:"pull_request_id"`
+	PullRequestName   string     `json:"pull_request_name"`
+	AuthorID          string     `json:"author_id"`
+	AssignedReviewers []string   `json:"assigned_reviewers"`
+	MergedAt          *time.Time `json:"merged_at,omitempty"`
+}
+
+// UserEventData данные событий user.created, user.updated, user.activated и user.deactivated
+type UserEventData struct {
+	UserID   string `json:"user_id"`
+	Username string `json:"username"`
+	TeamName string `json:"team_name"`
+	IsActive bool   `json:"is_active"`
+	// Fields поля профиля, заданные запросом (только в user.updated)
+	Fields []string `json:"fields,omitempty"`
+}
+
+// ReviewReminderEventData данные события review.reminder
+type ReviewReminderEventData struct {
+	PullRequestID   string    `json:"pull_request_id"`
+	PullRequestName string    `json:"pull_request_name"`
+	AuthorID        string    `json:"author_id"`
+	ReviewerID      string    `json:"reviewer_id"`
+	AssignedAt      time.Time `json:"assigned_at"`
+	// ReviewSLAMinutes превышенный SLA команды ревьюера (с учётом значения по умолчанию)
+	ReviewSLAMinutes int `json:"review_sla_minutes"`
+}
@@ -0,0 +1,61 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// OutboxStatus статус сообщения outbox
+type OutboxStatus string
+
+const (
+	// OutboxStatusPending сообщение ожидает (повторной) публикации
+	OutboxStatusPending OutboxStatus = "pending"
+	// OutboxStatusPublished все sink'и обработали сообщение
+	OutboxStatusPublished OutboxStatus = "published"
+	// OutboxStatusFailed попытки исчерпаны (dead letter)
+	OutboxStatusFailed OutboxStatus = "failed"
+)
+
+// OutboxMessage событие, сохраненное в outbox в той же транзакции, что и изменение, которое его породило
+// PublishedSinks перечисляет sink'и, уже обработавшие сообщение: при повторной попытке они пропускаются
+type OutboxMessage struct {
+	ID             string       `json:"id" db:"id"`
+	EventType      EventType    `json:"event_type" db:"event_type"`
+	Payload        []byte       `json:"-" db:"payload"`
+	Status         OutboxStatus `json:"status" db:"status"`
+	Attempts       int          `json:"attempts" db:"attempts"`
+	LastError      string       `json:"last_error,omitempty" db:"last_error"`
+	NextAttemptAt  time.Time    `json:"next_attempt_at" db:"next_attempt_at"`
+	PublishedSinks []string     `json:"published_sinks,omitempty" db:"published_sinks"`
+	CreatedAt      time.Time    `json:"created_at" db:"created_at"`
+	PublishedAt    *time.Time   `json:"published_at,omitempty" db:"published_at"`
+}
+
+// HasPublishedTo сообщает, обработал ли sink сообщение при одной из прошлых попыток
+func (m *OutboxMessage) HasPublishedTo(sink string) bool {
+	for _, name := range m.PublishedSinks {
+		if name == sink {
+			return true
+		}
+	}
+	return false
+}
+
+// NewOutboxMessage сериализует событие для записи в outbox; ID сообщения совпадает с ID события
+func NewOutboxMessage(event Event) (*OutboxMessage, error) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode event %s: %w", event.Type, err)
+	}
+
+	return &OutboxMessage{
+		ID:            event.ID,
+		EventType:     event.Type,
+		Payload:       payload,
+		Status:        OutboxStatusPending,
+		NextAttemptAt: event.OccurredAt,
+		CreatedAt:     event.OccurredAt,
+	}, nil
+}
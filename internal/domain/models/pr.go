@@ -19,13 +19,15 @@ func (s PRStatus) IsValid() bool {
 }
 
 type PullRequest struct {
-	ID                string     `json:"pull_request_id" db:"id"`
-	Name              string     `json:"pull_request_name" db:"name"`
-	AuthorID          string     `json:"author_id" db:"author_id"`
-	Status            PRStatus   `json:"status" db:"status"`
-	AssignedReviewers []string   `json:"assigned_reviewers"`
-	CreatedAt         time.Time  `json:"createdAt,omitempty" db:"created_at"`
-	MergedAt          *time.Time `json:"mergedAt,omitempty" db:"merged_at"`
+	ID                string   `json:"pull_request_id" db:"id"`
+	Name              string   `json:"pull_request_name" db:"name"`
+	AuthorID          string   `json:"author_id" db:"author_id"`
+	Status            PRStatus `json:"status" db:"status"`
+	AssignedReviewers []string `json:"assigned_reviewers"`
+	// RequiredTags навыки, которые нужны от ревьюеров; кандидаты с большим пересечением выбираются первыми
+	RequiredTags []string   `json:"required_tags,omitempty" db:"required_tags"`
+	CreatedAt    time.Time  `json:"createdAt,omitempty" db:"created_at"`
+	MergedAt     *time.Time `json:"mergedAt,omitempty" db:"merged_at"`
 }
 
 // IsMerged проверяет, является ли PR merged
@@ -48,3 +50,34 @@ func (pr *PullRequest) String() string {
 	return fmt.Sprintf("PullRequest{ID: %s, Name: %s, AuthorID: %s, Status: %s, Reviewers: %v}",
 		pr.ID, pr.Name, pr.AuthorID, pr.Status, pr.AssignedReviewers)
 }
+
+// StaleReview назначение ревьюера на открытый PR, которое ждёт ревью дольше SLA команды ревьюера
+type StaleReview struct {
+	PullRequestID   string
+	PullRequestName string
+	AuthorID        string
+	ReviewerID      string
+	AssignedAt      time.Time
+	// RemindedAt время последнего напоминания; nil — напоминаний ещё не было
+	RemindedAt *time.Time
+}
+
+// ReassignReason причина замены ревьюера
+type ReassignReason string
+
+const (
+	// ReassignReasonManual замена через /pullRequest/reassign
+	ReassignReasonManual ReassignReason = "manual"
+	// ReassignReasonSLATimeout автоматическая замена ревьюера, не закрывшего ревью за SLA команды
+	ReassignReasonSLATimeout ReassignReason = "sla_timeout"
+)
+
+// Reassignment запись истории замены ревьюера на PR
+type Reassignment struct {
+	ID            string         `json:"id" db:"id"`
+	PullRequestID string         `json:"pull_request_id" db:"pr_id"`
+	OldReviewerID string         `json:"old_reviewer_id" db:"old_reviewer_id"`
+	NewReviewerID string         `json:"new_reviewer_id" db:"new_reviewer_id"`
+	Reason        ReassignReason `json:"reason" db:"reason"`
+	CreatedAt     time.Time      `json:"created_at" db:"created_at"`
+}
@@ -1,10 +1,133 @@
 package models
 
-import "fmt"
+import (
+	"fmt"
+	"time"
+)
 
 type Team struct {
 	Name    string `json:"team_name" db:"name"`
 	Members []User `json:"members"`
+	// ChatWebhookURL адрес incoming webhook чата команды; пустой — уведомления не отправляются
+	ChatWebhookURL string `json:"-" db:"chat_webhook_url"`
+	// ReviewSLAMinutes время на ревью, после которого ревьюеру приходит напоминание; 0 — значение по умолчанию из конфигурации
+	ReviewSLAMinutes int `json:"review_sla_minutes,omitempty" db:"review_sla_minutes"`
+	// SLAAction что происходит с ревью, которое не закрыто за SLA
+	SLAAction SLAAction `json:"sla_action" db:"sla_action"`
+	// LeadID лид команды, которому эскалируются просроченные ревью; пустой — лид не назначен
+	LeadID string `json:"lead_id,omitempty" db:"lead_id"`
+	// Calendar рабочий календарь команды: выходные не входят в SLA
+	Calendar TeamCalendar `json:"calendar"`
+	// PreferWorkingHours при выборе ревьюеров сначала берутся участники, у которых идёт рабочее время
+	// или оно начнётся не позже чем через PreferWithinHours часов
+	PreferWorkingHours bool `json:"prefer_working_hours" db:"prefer_working_hours"`
+	PreferWithinHours  int  `json:"prefer_within_hours" db:"prefer_within_hours"`
+	// MinReviewerSeniority хотя бы один ревьюер PR должен быть не ниже этого уровня; пустой — требования нет
+	MinReviewerSeniority Seniority `json:"min_reviewer_seniority,omitempty" db:"min_reviewer_seniority"`
+}
+
+// SLAAction действие при нарушении SLA ревью
+type SLAAction string
+
+const (
+	// SLAActionRemind напомнить ревьюеру (событие review.reminder)
+	SLAActionRemind SLAAction = "remind"
+	// SLAActionReassign передать ревью другому участнику команды, как при reassign
+	SLAActionReassign SLAAction = "reassign"
+	// SLAActionEscalate передать ревью лиду команды
+	SLAActionEscalate SLAAction = "escalate"
+)
+
+// IsValid проверяет корректность действия
+func (a SLAAction) IsValid() bool {
+	return a == SLAActionRemind || a == SLAActionReassign || a == SLAActionEscalate
+}
+
+// WeekdaySet множество дней недели: бит i соответствует time.Weekday(i)
+type WeekdaySet uint8
+
+// DefaultWeekend выходные по умолчанию — суббота и воскресенье
+const DefaultWeekend WeekdaySet = 1<<time.Saturday | 1<<time.Sunday
+
+// allWeekdays множество из всех семи дней
+const allWeekdays WeekdaySet = 1<<7 - 1
+
+// NewWeekdaySet собирает множество из дней недели
+func NewWeekdaySet(days ...time.Weekday) WeekdaySet {
+	var set WeekdaySet
+	for _, day := range days {
+		set |= 1 << day
+	}
+	return set
+}
+
+// Has проверяет, входит ли день в множество
+func (s WeekdaySet) Has(day time.Weekday) bool {
+	return s&(1<<day) != 0
+}
+
+// Days возвращает дни множества, начиная с воскресенья
+func (s WeekdaySet) Days() []time.Weekday {
+	days := make([]time.Weekday, 0, 7)
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		if s.Has(day) {
+			days = append(days, day)
+		}
+	}
+	return days
+}
+
+// TeamCalendar рабочий календарь команды
+type TeamCalendar struct {
+	// Timezone часовой пояс IANA, в котором считаются дни недели
+	Timezone string `json:"timezone" db:"timezone"`
+	// Weekend выходные дни; хотя бы один день недели должен быть рабочим
+	Weekend WeekdaySet `json:"weekend" db:"weekend_days"`
+}
+
+// DefaultCalendar календарь по умолчанию: UTC, выходные в субботу и воскресенье
+func DefaultCalendar() TeamCalendar {
+	return TeamCalendar{Timezone: "UTC", Weekend: DefaultWeekend}
+}
+
+// IsValid проверяет, что в неделе остаётся рабочий день
+func (c TeamCalendar) IsValid() bool {
+	return c.Weekend&allWeekdays != allWeekdays
+}
+
+// Location возвращает часовой пояс календаря; неизвестный пояс считается UTC
+func (c TeamCalendar) Location() *time.Location {
+	loc, err := time.LoadLocation(c.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// SubtractWorkingTime возвращает момент, от которого до t прошло d рабочего времени:
+// время выходных дней (по часовому поясу календаря) не считается
+func (c TeamCalendar) SubtractWorkingTime(t time.Time, d time.Duration) time.Time {
+	if !c.IsValid() {
+		return t.Add(-d)
+	}
+
+	loc := c.Location()
+	t = t.In(loc)
+	for d > 0 {
+		// Start of the day that the instant just before t belongs to
+		before := t.Add(-time.Nanosecond)
+		dayStart := time.Date(before.Year(), before.Month(), before.Day(), 0, 0, 0, 0, loc)
+
+		if !c.Weekend.Has(dayStart.Weekday()) {
+			if worked := t.Sub(dayStart); worked < d {
+				d -= worked
+			} else {
+				return t.Add(-d)
+			}
+		}
+		t = dayStart
+	}
+	return t
 }
 
 // GetActiveMembers возвращает только активных участников команды
@@ -1,12 +1,138 @@
 package models
 
-import "fmt"
+import (
+	"fmt"
+	"time"
+)
 
 type User struct {
 	ID       string `json:"user_id" db:"id"`
 	Username string `json:"username" db:"username"`
 	TeamName string `json:"team_name" db:"team_name"`
 	IsActive bool   `json:"is_active" db:"is_active"`
+	// MentionHandle идентификатор пользователя в чате (Slack member ID) для упоминаний; пустой — без упоминания
+	MentionHandle string `json:"mention_handle,omitempty" db:"mention_handle"`
+	// Email адрес для уведомлений по почте; пустой — письма не отправляются
+	Email string `json:"email,omitempty" db:"email"`
+	// Timezone часовой пояс IANA пользователя; пустой — часовой пояс календаря команды
+	Timezone string `json:"timezone,omitempty" db:"timezone"`
+	// WorkingHours рабочие часы в часовом поясе пользователя; не заданы — пользователь доступен в любое время
+	WorkingHours WorkingHours `json:"working_hours"`
+	// MaxOpenReviews сколько открытых PR пользователь может ревьюить одновременно; 0 — без ограничения
+	MaxOpenReviews int `json:"max_open_reviews,omitempty" db:"max_open_reviews"`
+	// SkillTags навыки пользователя (go, sql, frontend); в нижнем регистре, отсортированы
+	SkillTags []string `json:"skill_tags,omitempty" db:"skill_tags"`
+	// Seniority уровень пользователя как ревьюера; пустой — уровень не задан
+	Seniority Seniority `json:"seniority,omitempty" db:"seniority"`
+}
+
+// Seniority уровень ревьюера; уровни упорядочены: junior < middle < senior
+type Seniority string
+
+const (
+	SeniorityJunior Seniority = "junior"
+	SeniorityMiddle Seniority = "middle"
+	SenioritySenior Seniority = "senior"
+)
+
+// Rank возвращает место уровня по возрастанию начиная с 1; 0 — уровень не задан или неизвестен
+func (s Seniority) Rank() int {
+	switch s {
+	case SeniorityJunior:
+		return 1
+	case SeniorityMiddle:
+		return 2
+	case SenioritySenior:
+		return 3
+	default:
+		return 0
+	}
+}
+
+// IsValid проверяет корректность уровня; пустой уровень допустим
+func (s Seniority) IsValid() bool {
+	return s == "" || s.Rank() > 0
+}
+
+// AtLeast проверяет, что уровень пользователя не ниже level; пустой level ничего не требует
+func (u *User) AtLeast(level Seniority) bool {
+	return level == "" || u.Seniority.Rank() >= level.Rank()
+}
+
+// HasCapacity проверяет, может ли пользователь взять ещё одно ревью при open открытых ревью
+func (u *User) HasCapacity(open int) bool {
+	return u.MaxOpenReviews == 0 || open < u.MaxOpenReviews
+}
+
+// TagOverlap возвращает, сколько из тегов tags есть среди навыков пользователя
+func (u *User) TagOverlap(tags []string) int {
+	overlap := 0
+	for _, tag := range tags {
+		for _, skill := range u.SkillTags {
+			if skill == tag {
+				overlap++
+				break
+			}
+		}
+	}
+	return overlap
+}
+
+// WorkingHours рабочие часы в минутах от полуночи; конец не входит в интервал
+// Start == End — часы не заданы; End < Start — рабочий день переходит через полночь
+type WorkingHours struct {
+	Start int `json:"start" db:"work_start_minute"`
+	End   int `json:"end" db:"work_end_minute"`
+}
+
+// MinutesPerDay количество минут в сутках, граница значений WorkingHours
+const MinutesPerDay = 24 * 60
+
+// IsSet проверяет, что рабочие часы заданы
+func (h WorkingHours) IsSet() bool {
+	return h.Start != h.End
+}
+
+// Location возвращает часовой пояс пользователя; пустой или неизвестный пояс заменяется на fallback
+func (u *User) Location(fallback *time.Location) *time.Location {
+	if u.Timezone == "" {
+		return fallback
+	}
+	loc, err := time.LoadLocation(u.Timezone)
+	if err != nil {
+		return fallback
+	}
+	return loc
+}
+
+// WorkingWithin проверяет, что у пользователя идёт рабочее время в момент now или начнётся не позже now+d
+// Выходные дни календаря команды считаются в часовом поясе пользователя; без рабочих часов — всегда true
+func (u *User) WorkingWithin(now time.Time, d time.Duration, calendar TeamCalendar) bool {
+	if !u.WorkingHours.IsSet() {
+		return true
+	}
+
+	local := now.In(u.Location(calendar.Location()))
+	year, month, day := local.Date()
+	// Рабочий день, начавшийся вчера, может ещё идти; неделя вперёд покрывает любые выходные
+	for offset := -1; offset <= 8; offset++ {
+		date := time.Date(year, month, day+offset, 0, 0, 0, 0, local.Location())
+		if calendar.Weekend.Has(date.Weekday()) {
+			continue
+		}
+
+		start := time.Date(year, month, day+offset, 0, u.WorkingHours.Start, 0, 0, local.Location())
+		endDay := day + offset
+		if u.WorkingHours.End < u.WorkingHours.Start {
+			endDay++
+		}
+		end := time.Date(year, month, endDay, 0, u.WorkingHours.End, 0, 0, local.Location())
+
+		if now.Before(end) && !start.After(now.Add(d)) {
+			return true
+		}
+	}
+	return false
 }
 
 // String возвращает строковое представление пользователя для логирования
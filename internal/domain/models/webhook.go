@@ -0,0 +1,54 @@
+package models
+
+import "time"
+
+// Webhook зарегистрированный получатель событий
+type Webhook struct {
+	ID        string      `json:"webhook_id" db:"id"`
+	URL       string      `json:"url" db:"url"`
+	Secret    string      `json:"-" db:"secret"`
+	Events    []EventType `json:"events" db:"events"`
+	CreatedAt time.Time   `json:"created_at" db:"created_at"`
+}
+
+// Subscribes проверяет, подписан ли вебхук на событие данного типа
+func (w *Webhook) Subscribes(eventType EventType) bool {
+	for _, subscribed := range w.Events {
+		if subscribed == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// DeliveryStatus статус доставки события вебхуку
+type DeliveryStatus string
+
+const (
+	// DeliveryStatusPending доставка ожидает (повторной) отправки
+	DeliveryStatusPending DeliveryStatus = "pending"
+	// DeliveryStatusDelivered получатель ответил 2xx
+	DeliveryStatusDelivered DeliveryStatus = "delivered"
+	// DeliveryStatusFailed попытки исчерпаны (dead letter), возможна ручная переотправка
+	DeliveryStatusFailed DeliveryStatus = "failed"
+)
+
+// IsValid проверяет корректность статуса
+func (s DeliveryStatus) IsValid() bool {
+	return s == DeliveryStatusPending || s == DeliveryStatusDelivered || s == DeliveryStatusFailed
+}
+
+// WebhookDelivery одна доставка события одному вебхуку
+type WebhookDelivery struct {
+	ID            string         `json:"delivery_id" db:"id"`
+	WebhookID     string         `json:"webhook_id" db:"webhook_id"`
+	EventID       string         `json:"event_id" db:"event_id"`
+	EventType     EventType      `json:"event_type" db:"event_type"`
+	Payload       []byte         `json:"-" db:"payload"`
+	Status        DeliveryStatus `json:"status" db:"status"`
+	Attempts      int            `json:"attempts" db:"attempts"`
+	LastError     string         `json:"last_error,omitempty" db:"last_error"`
+	NextAttemptAt time.Time      `json:"next_attempt_at" db:"next_attempt_at"`
+	CreatedAt     time.Time      `json:"created_at" db:"created_at"`
+	DeliveredAt   *time.Time     `json:"delivered_at,omitempty" db:"delivered_at"`
+}
@@ -0,0 +1,112 @@
+package grpchandler
+
+import (
+	"context"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	reviewerv1 "avito-backend-trainee-assignment-autumn-2025/api/gen/reviewer/v1"
+	"avito-backend-trainee-assignment-autumn-2025/internal/domain/models"
+	"avito-backend-trainee-assignment-autumn-2025/internal/service"
+	"avito-backend-trainee-assignment-autumn-2025/pkg/dto/request"
+	"avito-backend-trainee-assignment-autumn-2025/pkg/dto/response"
+	"avito-backend-trainee-assignment-autumn-2025/pkg/logger"
+)
+
+// PRHandler implements reviewerv1.PRServiceServer
+type PRHandler struct {
+	reviewerv1.UnimplementedPRServiceServer
+	prService service.PRService
+}
+
+// NewPRHandler creates a new gRPC PR handler
+func NewPRHandler(prService service.PRService) *PRHandler {
+	return &PRHandler{
+		prService: prService,
+	}
+}
+
+// CreatePullRequest handles reviewer.v1.PRService/CreatePullRequest
+func (h *PRHandler) CreatePullRequest(ctx context.Context, req *reviewerv1.CreatePullRequestRequest) (*reviewerv1.CreatePullRequestResponse, error) {
+	logger.Info("Creating PR via gRPC: %s (author: %s)", req.GetPullRequestId(), req.GetAuthorId())
+
+	resp, err := h.prService.CreatePR(ctx, &request.CreatePRRequest{
+		PullRequestID:   req.GetPullRequestId(),
+		PullRequestName: req.GetPullRequestName(),
+		AuthorID:        req.GetAuthorId(),
+	})
+	if err != nil {
+		logger.Error("Failed to create PR %s: %v", req.GetPullRequestId(), err)
+		return nil, err
+	}
+
+	return &reviewerv1.CreatePullRequestResponse{
+		Pr: convertPR(&resp.PR),
+	}, nil
+}
+
+// MergePullRequest handles reviewer.v1.PRService/MergePullRequest
+func (h *PRHandler) MergePullRequest(ctx context.Context, req *reviewerv1.MergePullRequestRequest) (*reviewerv1.MergePullRequestResponse, error) {
+	logger.Info("Merging PR via gRPC: %s", req.GetPullRequestId())
+
+	resp, err := h.prService.MergePR(ctx, &request.MergePRRequest{
+		PullRequestID: req.GetPullRequestId(),
+	})
+	if err != nil {
+		logger.Error("Failed to merge PR %s: %v", req.GetPullRequestId(), err)
+		return nil, err
+	}
+
+	return &reviewerv1.MergePullRequestResponse{
+		Pr: convertPR(&resp.PR),
+	}, nil
+}
+
+// ReassignReviewer handles reviewer.v1.PRService/ReassignReviewer
+func (h *PRHandler) ReassignReviewer(ctx context.Context, req *reviewerv1.ReassignReviewerRequest) (*reviewerv1.ReassignReviewerResponse, error) {
+	logger.Info("Reassigning reviewer via gRPC for PR %s: replacing %s", req.GetPullRequestId(), req.GetOldUserId())
+
+	resp, err := h.prService.ReassignReviewer(ctx, &request.ReassignReviewerRequest{
+		PullRequestID: req.GetPullRequestId(),
+		OldUserID:     req.GetOldUserId(),
+	})
+	if err != nil {
+		logger.Error("Failed to reassign reviewer for PR %s: %v", req.GetPullRequestId(), err)
+		return nil, err
+	}
+
+	return &reviewerv1.ReassignReviewerResponse{
+		Pr:         convertPR(&resp.PR),
+		ReplacedBy: resp.ReplacedBy,
+	}, nil
+}
+
+// convertPR converts a PullRequestResponse DTO to its protobuf representation
+func convertPR(pr *response.PullRequestResponse) *reviewerv1.PullRequest {
+	result := &reviewerv1.PullRequest{
+		PullRequestId:     pr.PullRequestID,
+		PullRequestName:   pr.PullRequestName,
+		AuthorId:          pr.AuthorID,
+		Status:            convertPRStatus(pr.Status),
+		AssignedReviewers: pr.AssignedReviewers,
+	}
+	if pr.CreatedAt != nil {
+		result.CreatedAt = timestamppb.New(*pr.CreatedAt)
+	}
+	if pr.MergedAt != nil {
+		result.MergedAt = timestamppb.New(*pr.MergedAt)
+	}
+	return result
+}
+
+// convertPRStatus converts a PR status string to the protobuf enum
+func convertPRStatus(status string) reviewerv1.PullRequestStatus {
+	switch models.PRStatus(status) {
+	case models.PRStatusOpen:
+		return reviewerv1.PullRequestStatus_PULL_REQUEST_STATUS_OPEN
+	case models.PRStatusMerged:
+		return reviewerv1.PullRequestStatus_PULL_REQUEST_STATUS_MERGED
+	default:
+		return reviewerv1.PullRequestStatus_PULL_REQUEST_STATUS_UNSPECIFIED
+	}
+}
@@ -0,0 +1,107 @@
+// Package grpchandler exposes the service layer over gRPC
+package grpchandler
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+
+	reviewerv1 "avito-backend-trainee-assignment-autumn-2025/api/gen/reviewer/v1"
+	"avito-backend-trainee-assignment-autumn-2025/internal/service"
+	pkgerrors "avito-backend-trainee-assignment-autumn-2025/pkg/errors"
+	"avito-backend-trainee-assignment-autumn-2025/pkg/logger"
+)
+
+// NewServer creates a gRPC server with all services registered
+func NewServer(
+	teamService service.TeamService,
+	userService service.UserService,
+	prService service.PRService,
+) *grpc.Server {
+	// Interceptors order: Recovery -> Logger -> error mapping
+	server := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(
+			recoveryInterceptor,
+			loggerInterceptor,
+			errorInterceptor,
+		),
+	)
+
+	reviewerv1.RegisterTeamServiceServer(server, NewTeamHandler(teamService))
+	reviewerv1.RegisterUserServiceServer(server, NewUserHandler(userService))
+	reviewerv1.RegisterPRServiceServer(server, NewPRHandler(prService))
+
+	// Allow grpcurl and similar tools to discover the API
+	reflection.Register(server)
+
+	return server
+}
+
+// recoveryInterceptor recovers from panics and returns codes.Internal
+func recoveryInterceptor(
+	ctx context.Context,
+	req interface{},
+	info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (resp interface{}, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Error(
+				"PANIC recovered in %s: %v\nStack trace:\n%s",
+				info.FullMethod,
+				r,
+				string(debug.Stack()),
+			)
+			err = pkgerrors.MapErrorToGRPCStatus(fmt.Errorf("panic: %v", r)).Err()
+		}
+	}()
+
+	return handler(ctx, req)
+}
+
+// loggerInterceptor logs gRPC calls
+func loggerInterceptor(
+	ctx context.Context,
+	req interface{},
+	info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (interface{}, error) {
+	start := time.Now()
+
+	resp, err := handler(ctx, req)
+
+	logger.Info(
+		"gRPC %s - Code: %s - Duration: %v",
+		info.FullMethod,
+		status.Code(err),
+		time.Since(start),
+	)
+	return resp, err
+}
+
+// errorInterceptor converts domain errors returned by handlers into gRPC statuses
+func errorInterceptor(
+	ctx context.Context,
+	req interface{},
+	info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (interface{}, error) {
+	resp, err := handler(ctx, req)
+	if err == nil {
+		return resp, nil
+	}
+
+	// Already a gRPC status (e.g. produced by grpc-go itself)
+	if _, ok := status.FromError(err); ok {
+		return nil, err
+	}
+
+	st := pkgerrors.MapErrorToGRPCStatus(err)
+	logger.Debug("Responding with gRPC error: code=%s, message=%s", st.Code(), err.Error())
+	return nil, st.Err()
+}
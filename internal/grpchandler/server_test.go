@@ -0,0 +1,205 @@
+package grpchandler
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	reviewerv1 "avito-backend-trainee-assignment-autumn-2025/api/gen/reviewer/v1"
+	"avito-backend-trainee-assignment-autumn-2025/pkg/dto/request"
+	"avito-backend-trainee-assignment-autumn-2025/pkg/dto/response"
+	pkgerrors "avito-backend-trainee-assignment-autumn-2025/pkg/errors"
+)
+
+// fakePRService returns preconfigured results
+type fakePRService struct {
+	createErr error
+}
+
+func (f *fakePRService) CreatePR(ctx context.Context, req *request.CreatePRRequest) (*response.CreatePRResponse, error) {
+	if f.createErr != nil {
+		return nil, f.createErr
+	}
+	createdAt := time.Date(2025, 11, 1, 12, 0, 0, 0, time.UTC)
+	return &response.CreatePRResponse{
+		PR: response.PullRequestResponse{
+			PullRequestID:     req.PullRequestID,
+			PullRequestName:   req.PullRequestName,
+			AuthorID:          req.AuthorID,
+			Status:            "OPEN",
+			AssignedReviewers: []string{"u2", "u3"},
+			CreatedAt:         &createdAt,
+		},
+	}, nil
+}
+
+func (f *fakePRService) MergePR(ctx context.Context, req *request.MergePRRequest) (*response.MergePRResponse, error) {
+	panic("unexpected call")
+}
+
+func (f *fakePRService) ReassignReviewer(ctx context.Context, req *request.ReassignReviewerRequest) (*response.ReassignReviewerResponse, error) {
+	return nil, pkgerrors.ErrPRMerged
+}
+
+func (f *fakePRService) GetPRHistory(ctx context.Context, prID string) (*response.GetPRHistoryResponse, error) {
+	panic("unexpected call")
+}
+
+func newTestClient(t *testing.T, prService *fakePRService) reviewerv1.PRServiceClient {
+	t.Helper()
+
+	listener := bufconn.Listen(1024 * 1024)
+	server := NewServer(nil, nil, prService)
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient(
+		"passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return reviewerv1.NewPRServiceClient(conn)
+}
+
+func errorReason(t *testing.T, err error) (string, map[string]string) {
+	t.Helper()
+
+	for _, detail := range status.Convert(err).Details() {
+		if info, ok := detail.(*errdetails.ErrorInfo); ok {
+			return info.GetReason(), info.GetMetadata()
+		}
+	}
+	t.Fatalf("ErrorInfo detail missing in %v", err)
+	return "", nil
+}
+
+func TestPRService_CreatePullRequest(t *testing.T) {
+	client := newTestClient(t, &fakePRService{})
+
+	resp, err := client.CreatePullRequest(context.Background(), &reviewerv1.CreatePullRequestRequest{
+		PullRequestId:   "pr-1",
+		PullRequestName: "Feature",
+		AuthorId:        "u1",
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if resp.GetPr().GetStatus() != reviewerv1.PullRequestStatus_PULL_REQUEST_STATUS_OPEN {
+		t.Errorf("Expected OPEN status, got %s", resp.GetPr().GetStatus())
+	}
+	if len(resp.GetPr().GetAssignedReviewers()) != 2 {
+		t.Errorf("Expected 2 reviewers, got %d", len(resp.GetPr().GetAssignedReviewers()))
+	}
+	if resp.GetPr().GetCreatedAt() == nil || resp.GetPr().GetMergedAt() != nil {
+		t.Errorf("Unexpected timestamps: created=%v merged=%v", resp.GetPr().GetCreatedAt(), resp.GetPr().GetMergedAt())
+	}
+}
+
+func TestErrorMapping(t *testing.T) {
+	tests := []struct {
+		name            string
+		err             error
+		expectedCode    codes.Code
+		expectedReason  string
+		expectedMessage string
+		expectedField   string
+	}{
+		{
+			name:            "Validation error",
+			err:             pkgerrors.NewRequiredFieldError("author_id"),
+			expectedCode:    codes.InvalidArgument,
+			expectedReason:  "VALIDATION_ERROR",
+			expectedMessage: "author_id is required",
+			expectedField:   "author_id",
+		},
+		{
+			name:            "Already exists",
+			err:             pkgerrors.ErrPRExists,
+			expectedCode:    codes.AlreadyExists,
+			expectedReason:  "PR_EXISTS",
+			expectedMessage: "pull request already exists",
+		},
+		{
+			name:            "Not found",
+			err:             pkgerrors.ErrUserNotFound,
+			expectedCode:    codes.NotFound,
+			expectedReason:  "NOT_FOUND",
+			expectedMessage: "user not found",
+		},
+		{
+			name:            "Internal error is hidden",
+			err:             errors.New("connection reset by peer"),
+			expectedCode:    codes.Internal,
+			expectedReason:  "INTERNAL",
+			expectedMessage: "internal server error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestClient(t, &fakePRService{createErr: tt.err})
+
+			_, err := client.CreatePullRequest(context.Background(), &reviewerv1.CreatePullRequestRequest{
+				PullRequestId: "pr-1",
+			})
+
+			st := status.Convert(err)
+			if st.Code() != tt.expectedCode {
+				t.Errorf("Expected code %s, got %s", tt.expectedCode, st.Code())
+			}
+			if st.Message() != tt.expectedMessage {
+				t.Errorf("Expected message %q, got %q", tt.expectedMessage, st.Message())
+			}
+
+			reason, metadata := errorReason(t, err)
+			if reason != tt.expectedReason {
+				t.Errorf("Expected reason %s, got %s", tt.expectedReason, reason)
+			}
+			if metadata["field"] != tt.expectedField {
+				t.Errorf("Expected field %q, got %q", tt.expectedField, metadata["field"])
+			}
+		})
+	}
+
+	t.Run("Conflict", func(t *testing.T) {
+		client := newTestClient(t, &fakePRService{})
+
+		_, err := client.ReassignReviewer(context.Background(), &reviewerv1.ReassignReviewerRequest{
+			PullRequestId: "pr-1",
+			OldUserId:     "u2",
+		})
+		if status.Code(err) != codes.FailedPrecondition {
+			t.Errorf("Expected FailedPrecondition, got %s", status.Code(err))
+		}
+	})
+
+	t.Run("Panic is recovered", func(t *testing.T) {
+		client := newTestClient(t, &fakePRService{})
+
+		_, err := client.MergePullRequest(context.Background(), &reviewerv1.MergePullRequestRequest{
+			PullRequestId: "pr-1",
+		})
+		if status.Code(err) != codes.Internal {
+			t.Errorf("Expected Internal, got %s", status.Code(err))
+		}
+	})
+}
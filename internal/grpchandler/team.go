@@ -0,0 +1,83 @@
+package grpchandler
+
+import (
+	"context"
+
+	reviewerv1 "avito-backend-trainee-assignment-autumn-2025/api/gen/reviewer/v1"
+	"avito-backend-trainee-assignment-autumn-2025/internal/service"
+	"avito-backend-trainee-assignment-autumn-2025/pkg/dto/request"
+	"avito-backend-trainee-assignment-autumn-2025/pkg/dto/response"
+	"avito-backend-trainee-assignment-autumn-2025/pkg/logger"
+)
+
+// TeamHandler implements reviewerv1.TeamServiceServer
+type TeamHandler struct {
+	reviewerv1.UnimplementedTeamServiceServer
+	teamService service.TeamService
+}
+
+// NewTeamHandler creates a new gRPC team handler
+func NewTeamHandler(teamService service.TeamService) *TeamHandler {
+	return &TeamHandler{
+		teamService: teamService,
+	}
+}
+
+// CreateTeam handles reviewer.v1.TeamService/CreateTeam
+func (h *TeamHandler) CreateTeam(ctx context.Context, req *reviewerv1.CreateTeamRequest) (*reviewerv1.CreateTeamResponse, error) {
+	members := make([]request.TeamMemberRequest, 0, len(req.GetMembers()))
+	for _, member := range req.GetMembers() {
+		members = append(members, request.TeamMemberRequest{
+			UserID:   member.GetUserId(),
+			Username: member.GetUsername(),
+			IsActive: member.GetIsActive(),
+		})
+	}
+
+	logger.Info("Creating team via gRPC: %s", req.GetTeamName())
+
+	resp, err := h.teamService.CreateTeam(ctx, &request.CreateTeamRequest{
+		TeamName: req.GetTeamName(),
+		Members:  members,
+	})
+	if err != nil {
+		logger.Error("Failed to create team %s: %v", req.GetTeamName(), err)
+		return nil, err
+	}
+
+	return &reviewerv1.CreateTeamResponse{
+		Team: convertTeam(&resp.Team),
+	}, nil
+}
+
+// GetTeam handles reviewer.v1.TeamService/GetTeam
+func (h *TeamHandler) GetTeam(ctx context.Context, req *reviewerv1.GetTeamRequest) (*reviewerv1.GetTeamResponse, error) {
+	logger.Info("Getting team via gRPC: %s", req.GetTeamName())
+
+	resp, err := h.teamService.GetTeam(ctx, req.GetTeamName())
+	if err != nil {
+		logger.Error("Failed to get team %s: %v", req.GetTeamName(), err)
+		return nil, err
+	}
+
+	return &reviewerv1.GetTeamResponse{
+		Team: convertTeam(resp),
+	}, nil
+}
+
+// convertTeam converts a TeamResponse DTO to its protobuf representation
+func convertTeam(team *response.TeamResponse) *reviewerv1.Team {
+	members := make([]*reviewerv1.TeamMember, 0, len(team.Members))
+	for _, member := range team.Members {
+		members = append(members, &reviewerv1.TeamMember{
+			UserId:   member.UserID,
+			Username: member.Username,
+			IsActive: member.IsActive,
+		})
+	}
+
+	return &reviewerv1.Team{
+		TeamName: team.TeamName,
+		Members:  members,
+	}
+}
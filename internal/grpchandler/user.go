@@ -0,0 +1,72 @@
+package grpchandler
+
+import (
+	"context"
+
+	reviewerv1 "avito-backend-trainee-assignment-autumn-2025/api/gen/reviewer/v1"
+	"avito-backend-trainee-assignment-autumn-2025/internal/service"
+	"avito-backend-trainee-assignment-autumn-2025/pkg/dto/request"
+	"avito-backend-trainee-assignment-autumn-2025/pkg/logger"
+)
+
+// UserHandler implements reviewerv1.UserServiceServer
+type UserHandler struct {
+	reviewerv1.UnimplementedUserServiceServer
+	userService service.UserService
+}
+
+// NewUserHandler creates a new gRPC user handler
+func NewUserHandler(userService service.UserService) *UserHandler {
+	return &UserHandler{
+		userService: userService,
+	}
+}
+
+// SetIsActive handles reviewer.v1.UserService/SetIsActive
+func (h *UserHandler) SetIsActive(ctx context.Context, req *reviewerv1.SetIsActiveRequest) (*reviewerv1.SetIsActiveResponse, error) {
+	logger.Info("Setting user %s active status to %t via gRPC", req.GetUserId(), req.GetIsActive())
+
+	resp, err := h.userService.SetUserActive(ctx, &request.SetUserActiveRequest{
+		UserID:   req.GetUserId(),
+		IsActive: req.GetIsActive(),
+	})
+	if err != nil {
+		logger.Error("Failed to set active status for user %s: %v", req.GetUserId(), err)
+		return nil, err
+	}
+
+	return &reviewerv1.SetIsActiveResponse{
+		User: &reviewerv1.User{
+			UserId:   resp.User.UserID,
+			Username: resp.User.Username,
+			TeamName: resp.User.TeamName,
+			IsActive: resp.User.IsActive,
+		},
+	}, nil
+}
+
+// GetReview handles reviewer.v1.UserService/GetReview
+func (h *UserHandler) GetReview(ctx context.Context, req *reviewerv1.GetReviewRequest) (*reviewerv1.GetReviewResponse, error) {
+	logger.Info("Getting reviews for user via gRPC: %s", req.GetUserId())
+
+	resp, err := h.userService.GetUserReviews(ctx, req.GetUserId())
+	if err != nil {
+		logger.Error("Failed to get reviews for user %s: %v", req.GetUserId(), err)
+		return nil, err
+	}
+
+	prs := make([]*reviewerv1.PullRequestShort, 0, len(resp.PullRequests))
+	for _, pr := range resp.PullRequests {
+		prs = append(prs, &reviewerv1.PullRequestShort{
+			PullRequestId:   pr.PullRequestID,
+			PullRequestName: pr.PullRequestName,
+			AuthorId:        pr.AuthorID,
+			Status:          convertPRStatus(pr.Status),
+		})
+	}
+
+	return &reviewerv1.GetReviewResponse{
+		UserId:       resp.UserID,
+		PullRequests: prs,
+	}, nil
+}
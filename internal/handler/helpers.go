@@ -4,7 +4,6 @@ import (
 	"encoding/json"
 	"net/http"
 
-	"avito-backend-trainee-assignment-autumn-2025/internal/dto/response"
 	pkgerrors "avito-backend-trainee-assignment-autumn-2025/pkg/errors"
 	"avito-backend-trainee-assignment-autumn-2025/pkg/logger"
 )
@@ -24,27 +23,21 @@ func respondWithJSON(w http.ResponseWriter, statusCode int, payload interface{})
 // respondWithError sends an error response in the standard API format
 func respondWithError(w http.ResponseWriter, err error) {
 	statusCode := pkgerrors.MapErrorToHTTPStatus(err)
-	errorCode := pkgerrors.MapErrorToErrorCode(err)
+	errorResponse := pkgerrors.MapErrorToResponse(err)
 
-	errorResponse := response.ErrorResponse{
-		Error: response.ErrorDetail{
-			Code:    errorCode,
-			Message: err.Error(),
-		},
-	}
-
-	logger.Debug("Responding with error: status=%d, code=%s, message=%s", statusCode, errorCode, err.Error())
+	logger.Debug("Responding with error: status=%d, code=%s, message=%s", statusCode, errorResponse.Error.Code, err.Error())
 	respondWithJSON(w, statusCode, errorResponse)
 }
 
 // decodeJSONBody decodes JSON request body into the given struct
+// Returns a BadRequestError if the body is not valid JSON for dst
 func decodeJSONBody(w http.ResponseWriter, r *http.Request, dst interface{}) error {
 	decoder := json.NewDecoder(r.Body)
 	decoder.DisallowUnknownFields()
 
 	if err := decoder.Decode(dst); err != nil {
 		logger.Error("Failed to decode JSON body: %v", err)
-		return err
+		return pkgerrors.NewBadRequestError("invalid request body", err)
 	}
 
 	return nil
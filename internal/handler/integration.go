@@ -0,0 +1,181 @@
+package handler
+
+import (
+	"io"
+	"net/http"
+
+	"avito-backend-trainee-assignment-autumn-2025/internal/domain/models"
+	"avito-backend-trainee-assignment-autumn-2025/internal/integration/github"
+	"avito-backend-trainee-assignment-autumn-2025/internal/integration/gitlab"
+	"avito-backend-trainee-assignment-autumn-2025/internal/service"
+	"avito-backend-trainee-assignment-autumn-2025/pkg/dto/request"
+	"avito-backend-trainee-assignment-autumn-2025/pkg/dto/response"
+	pkgerrors "avito-backend-trainee-assignment-autumn-2025/pkg/errors"
+	"avito-backend-trainee-assignment-autumn-2025/pkg/logger"
+)
+
+// maxWebhookBodySize limits inbound code host webhook payloads
+const maxWebhookBodySize = 1 << 20
+
+// IntegrationHandler handles code host integration HTTP requests
+type IntegrationHandler struct {
+	integrationService service.IntegrationService
+
+	// githubSecret verifies GitHub deliveries; the GitHub webhook is disabled when empty
+	githubSecret string
+	// gitlabToken verifies GitLab deliveries; the GitLab webhook is disabled when empty
+	gitlabToken string
+}
+
+// NewIntegrationHandler creates a new integration handler
+func NewIntegrationHandler(integrationService service.IntegrationService, githubSecret, gitlabToken string) *IntegrationHandler {
+	return &IntegrationHandler{
+		integrationService: integrationService,
+		githubSecret:       githubSecret,
+		gitlabToken:        gitlabToken,
+	}
+}
+
+// GitHubWebhook handles POST /integrations/github/webhook
+func (h *IntegrationHandler) GitHubWebhook(w http.ResponseWriter, r *http.Request) {
+	if h.githubSecret == "" {
+		respondWithError(w, pkgerrors.ErrIntegrationDisabled)
+		return
+	}
+
+	// Read the raw body: the signature covers the exact bytes GitHub sent
+	body, err := readWebhookBody(w, r)
+	if err != nil {
+		respondWithError(w, err)
+		return
+	}
+
+	// Verify signature
+	if err := github.VerifySignature(h.githubSecret, body, r.Header.Get(github.HeaderSignature)); err != nil {
+		logger.Warn("Rejected GitHub delivery %s: %v", r.Header.Get(github.HeaderDelivery), err)
+		respondWithError(w, err)
+		return
+	}
+
+	eventName := r.Header.Get(github.HeaderEvent)
+	logger.Info("Received GitHub %s delivery %s", eventName, r.Header.Get(github.HeaderDelivery))
+
+	event, err := github.ParseEvent(eventName, body)
+	if err != nil {
+		respondWithError(w, err)
+		return
+	}
+
+	h.handlePullRequestEvent(w, r, event)
+}
+
+// GitLabWebhook handles POST /integrations/gitlab/webhook
+func (h *IntegrationHandler) GitLabWebhook(w http.ResponseWriter, r *http.Request) {
+	if h.gitlabToken == "" {
+		respondWithError(w, pkgerrors.ErrIntegrationDisabled)
+		return
+	}
+
+	// Verify token
+	if err := gitlab.VerifyToken(h.gitlabToken, r.Header.Get(gitlab.HeaderToken)); err != nil {
+		logger.Warn("Rejected GitLab delivery %s: %v", r.Header.Get(gitlab.HeaderEventUUID), err)
+		respondWithError(w, err)
+		return
+	}
+
+	body, err := readWebhookBody(w, r)
+	if err != nil {
+		respondWithError(w, err)
+		return
+	}
+
+	eventName := r.Header.Get(gitlab.HeaderEvent)
+	logger.Info("Received GitLab %s delivery %s", eventName, r.Header.Get(gitlab.HeaderEventUUID))
+
+	event, err := gitlab.ParseEvent(eventName, body)
+	if err != nil {
+		respondWithError(w, err)
+		return
+	}
+
+	h.handlePullRequestEvent(w, r, event)
+}
+
+// handlePullRequestEvent passes a parsed code host event to the service
+// A nil event (ping, events the service doesn't track) is acknowledged as ignored
+func (h *IntegrationHandler) handlePullRequestEvent(w http.ResponseWriter, r *http.Request, event *models.CodeHostPREvent) {
+	if event == nil {
+		respondWithJSON(w, http.StatusOK, response.PullRequestEventResponse{Result: response.EventResultIgnored})
+		return
+	}
+
+	// Call service
+	resp, err := h.integrationService.HandlePullRequestEvent(r.Context(), event)
+	if err != nil {
+		logger.Error("Failed to handle %s %s event for %s: %v", event.Provider, event.Action, event.PullRequestID(), err)
+		respondWithError(w, err)
+		return
+	}
+
+	// Send response
+	respondWithJSON(w, http.StatusOK, resp)
+}
+
+// LinkAccount handles POST /integrations/accounts/link
+func (h *IntegrationHandler) LinkAccount(w http.ResponseWriter, r *http.Request) {
+	// Parse request body
+	var req request.LinkAccountRequest
+	if err := decodeJSONBody(w, r, &req); err != nil {
+		respondWithError(w, err)
+		return
+	}
+
+	// Validate input
+	if req.Login == "" {
+		respondWithError(w, pkgerrors.NewRequiredFieldError("login"))
+		return
+	}
+	if req.UserID == "" {
+		respondWithError(w, pkgerrors.NewRequiredFieldError("user_id"))
+		return
+	}
+
+	logger.Info("Linking %s account %s to user %s", req.Provider, req.Login, req.UserID)
+
+	// Call service
+	resp, err := h.integrationService.LinkAccount(r.Context(), &req)
+	if err != nil {
+		logger.Error("Failed to link %s account %s: %v", req.Provider, req.Login, err)
+		respondWithError(w, err)
+		return
+	}
+
+	// Send response
+	respondWithJSON(w, http.StatusOK, resp)
+}
+
+// ListAccounts handles GET /integrations/accounts/list?provider=...
+func (h *IntegrationHandler) ListAccounts(w http.ResponseWriter, r *http.Request) {
+	// provider is optional
+	provider := r.URL.Query().Get("provider")
+
+	// Call service
+	resp, err := h.integrationService.ListAccounts(r.Context(), provider)
+	if err != nil {
+		logger.Error("Failed to list accounts: %v", err)
+		respondWithError(w, err)
+		return
+	}
+
+	// Send response
+	respondWithJSON(w, http.StatusOK, resp)
+}
+
+// readWebhookBody reads a size-limited webhook payload
+func readWebhookBody(w http.ResponseWriter, r *http.Request) ([]byte, error) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxWebhookBodySize))
+	if err != nil {
+		return nil, pkgerrors.NewBadRequestError("failed to read webhook payload", err)
+	}
+	return body, nil
+}
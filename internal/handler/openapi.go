@@ -0,0 +1,38 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/getkin/kin-openapi/openapi3"
+
+	"avito-backend-trainee-assignment-autumn-2025/pkg/logger"
+)
+
+// OpenAPIHandler serves the OpenAPI specification of the service
+type OpenAPIHandler struct {
+	spec []byte
+}
+
+// NewOpenAPIHandler creates a new OpenAPI handler
+func NewOpenAPIHandler(doc *openapi3.T) (*OpenAPIHandler, error) {
+	spec, err := doc.MarshalJSON()
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode OpenAPI spec: %w", err)
+	}
+
+	return &OpenAPIHandler{
+		spec: spec,
+	}, nil
+}
+
+// GetSpec handles GET /openapi.json
+func (h *OpenAPIHandler) GetSpec(w http.ResponseWriter, r *http.Request) {
+	logger.Debug("OpenAPI spec requested")
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(h.spec); err != nil {
+		logger.Error("Failed to write OpenAPI spec: %v", err)
+	}
+}
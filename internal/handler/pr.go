@@ -1,11 +1,11 @@
 package handler
 
 import (
-	"fmt"
 	"net/http"
 
-	"avito-backend-trainee-assignment-autumn-2025/internal/dto/request"
 	"avito-backend-trainee-assignment-autumn-2025/internal/service"
+	"avito-backend-trainee-assignment-autumn-2025/pkg/dto/request"
+	pkgerrors "avito-backend-trainee-assignment-autumn-2025/pkg/errors"
 	"avito-backend-trainee-assignment-autumn-2025/pkg/logger"
 )
 
@@ -26,21 +26,21 @@ func (h *PRHandler) CreatePR(w http.ResponseWriter, r *http.Request) {
 	// Parse request body
 	var req request.CreatePRRequest
 	if err := decodeJSONBody(w, r, &req); err != nil {
-		respondWithError(w, fmt.Errorf("invalid request body: %w", err))
+		respondWithError(w, err)
 		return
 	}
 
 	// Validate input
 	if req.PullRequestID == "" {
-		respondWithError(w, fmt.Errorf("pull_request_id is required"))
+		respondWithError(w, pkgerrors.NewRequiredFieldError("pull_request_id"))
 		return
 	}
 	if req.PullRequestName == "" {
-		respondWithError(w, fmt.Errorf("pull_request_name is required"))
+		respondWithError(w, pkgerrors.NewRequiredFieldError("pull_request_name"))
 		return
 	}
 	if req.AuthorID == "" {
-		respondWithError(w, fmt.Errorf("author_id is required"))
+		respondWithError(w, pkgerrors.NewRequiredFieldError("author_id"))
 		return
 	}
 
@@ -63,13 +63,13 @@ func (h *PRHandler) MergePR(w http.ResponseWriter, r *http.Request) {
 	// Parse request body
 	var req request.MergePRRequest
 	if err := decodeJSONBody(w, r, &req); err != nil {
-		respondWithError(w, fmt.Errorf("invalid request body: %w", err))
+		respondWithError(w, err)
 		return
 	}
 
 	// Validate input
 	if req.PullRequestID == "" {
-		respondWithError(w, fmt.Errorf("pull_request_id is required"))
+		respondWithError(w, pkgerrors.NewRequiredFieldError("pull_request_id"))
 		return
 	}
 
@@ -92,17 +92,17 @@ func (h *PRHandler) ReassignReviewer(w http.ResponseWriter, r *http.Request) {
 	// Parse request body
 	var req request.ReassignReviewerRequest
 	if err := decodeJSONBody(w, r, &req); err != nil {
-		respondWithError(w, fmt.Errorf("invalid request body: %w", err))
+		respondWithError(w, err)
 		return
 	}
 
 	// Validate input
 	if req.PullRequestID == "" {
-		respondWithError(w, fmt.Errorf("pull_request_id is required"))
+		respondWithError(w, pkgerrors.NewRequiredFieldError("pull_request_id"))
 		return
 	}
 	if req.OldUserID == "" {
-		respondWithError(w, fmt.Errorf("old_user_id is required"))
+		respondWithError(w, pkgerrors.NewRequiredFieldError("old_user_id"))
 		return
 	}
 
@@ -119,3 +119,24 @@ func (h *PRHandler) ReassignReviewer(w http.ResponseWriter, r *http.Request) {
 	// Send response
 	respondWithJSON(w, http.StatusOK, resp)
 }
+
+// GetPRHistory handles GET /pullRequest/history?pull_request_id=...
+func (h *PRHandler) GetPRHistory(w http.ResponseWriter, r *http.Request) {
+	// Get pull_request_id from query parameters
+	prID := r.URL.Query().Get("pull_request_id")
+	if prID == "" {
+		respondWithError(w, pkgerrors.NewRequiredFieldError("pull_request_id"))
+		return
+	}
+
+	// Call service
+	resp, err := h.prService.GetPRHistory(r.Context(), prID)
+	if err != nil {
+		logger.Error("Failed to get history of PR %s: %v", prID, err)
+		respondWithError(w, err)
+		return
+	}
+
+	// Send response
+	respondWithJSON(w, http.StatusOK, resp)
+}
@@ -1,14 +1,19 @@
 package handler
 
 import (
-	"fmt"
+	"io"
 	"net/http"
+	"strconv"
 
-	"avito-backend-trainee-assignment-autumn-2025/internal/dto/request"
 	"avito-backend-trainee-assignment-autumn-2025/internal/service"
+	"avito-backend-trainee-assignment-autumn-2025/pkg/dto/request"
+	pkgerrors "avito-backend-trainee-assignment-autumn-2025/pkg/errors"
 	"avito-backend-trainee-assignment-autumn-2025/pkg/logger"
 )
 
+// maxCodeOwnersSize limits uploaded CODEOWNERS files
+const maxCodeOwnersSize = 1 << 20
+
 // TeamHandler handles team-related HTTP requests
 type TeamHandler struct {
 	teamService service.TeamService
@@ -26,13 +31,13 @@ func (h *TeamHandler) CreateTeam(w http.ResponseWriter, r *http.Request) {
 	// Parse request body
 	var req request.CreateTeamRequest
 	if err := decodeJSONBody(w, r, &req); err != nil {
-		respondWithError(w, fmt.Errorf("invalid request body: %w", err))
+		respondWithError(w, err)
 		return
 	}
 
 	// Validate input
 	if req.TeamName == "" {
-		respondWithError(w, fmt.Errorf("team_name is required"))
+		respondWithError(w, pkgerrors.NewRequiredFieldError("team_name"))
 		return
 	}
 
@@ -55,7 +60,7 @@ func (h *TeamHandler) GetTeam(w http.ResponseWriter, r *http.Request) {
 	// Get team_name from query parameters
 	teamName := r.URL.Query().Get("team_name")
 	if teamName == "" {
-		respondWithError(w, fmt.Errorf("team_name query parameter is required"))
+		respondWithError(w, pkgerrors.NewRequiredFieldError("team_name"))
 		return
 	}
 
@@ -72,3 +77,224 @@ func (h *TeamHandler) GetTeam(w http.ResponseWriter, r *http.Request) {
 	// Send response
 	respondWithJSON(w, http.StatusOK, resp)
 }
+
+// SetChatWebhook handles POST /team/setChatWebhook
+func (h *TeamHandler) SetChatWebhook(w http.ResponseWriter, r *http.Request) {
+	// Parse request body
+	var req request.SetChatWebhookRequest
+	if err := decodeJSONBody(w, r, &req); err != nil {
+		respondWithError(w, err)
+		return
+	}
+
+	// Validate input
+	if req.TeamName == "" {
+		respondWithError(w, pkgerrors.NewRequiredFieldError("team_name"))
+		return
+	}
+
+	// Call service
+	resp, err := h.teamService.SetChatWebhook(r.Context(), &req)
+	if err != nil {
+		logger.Error("Failed to set chat webhook for team %s: %v", req.TeamName, err)
+		respondWithError(w, err)
+		return
+	}
+
+	// Send response
+	respondWithJSON(w, http.StatusOK, resp)
+}
+
+// SetReviewSLA handles POST /team/setReviewSLA
+func (h *TeamHandler) SetReviewSLA(w http.ResponseWriter, r *http.Request) {
+	// Parse request body
+	var req request.SetReviewSLARequest
+	if err := decodeJSONBody(w, r, &req); err != nil {
+		respondWithError(w, err)
+		return
+	}
+
+	// Validate input
+	if req.TeamName == "" {
+		respondWithError(w, pkgerrors.NewRequiredFieldError("team_name"))
+		return
+	}
+
+	// Call service
+	resp, err := h.teamService.SetReviewSLA(r.Context(), &req)
+	if err != nil {
+		logger.Error("Failed to set review SLA for team %s: %v", req.TeamName, err)
+		respondWithError(w, err)
+		return
+	}
+
+	// Send response
+	respondWithJSON(w, http.StatusOK, resp)
+}
+
+// SetLead handles POST /team/setLead
+func (h *TeamHandler) SetLead(w http.ResponseWriter, r *http.Request) {
+	// Parse request body
+	var req request.SetLeadRequest
+	if err := decodeJSONBody(w, r, &req); err != nil {
+		respondWithError(w, err)
+		return
+	}
+
+	// Validate input
+	if req.TeamName == "" {
+		respondWithError(w, pkgerrors.NewRequiredFieldError("team_name"))
+		return
+	}
+
+	// Call service
+	resp, err := h.teamService.SetLead(r.Context(), &req)
+	if err != nil {
+		logger.Error("Failed to set lead for team %s: %v", req.TeamName, err)
+		respondWithError(w, err)
+		return
+	}
+
+	// Send response
+	respondWithJSON(w, http.StatusOK, resp)
+}
+
+// SetCalendar handles POST /team/setCalendar
+func (h *TeamHandler) SetCalendar(w http.ResponseWriter, r *http.Request) {
+	// Parse request body
+	var req request.SetCalendarRequest
+	if err := decodeJSONBody(w, r, &req); err != nil {
+		respondWithError(w, err)
+		return
+	}
+
+	// Validate input
+	if req.TeamName == "" {
+		respondWithError(w, pkgerrors.NewRequiredFieldError("team_name"))
+		return
+	}
+
+	// Call service
+	resp, err := h.teamService.SetCalendar(r.Context(), &req)
+	if err != nil {
+		logger.Error("Failed to set calendar for team %s: %v", req.TeamName, err)
+		respondWithError(w, err)
+		return
+	}
+
+	// Send response
+	respondWithJSON(w, http.StatusOK, resp)
+}
+
+// SetWorkingHoursPreference handles POST /team/setWorkingHoursPreference
+func (h *TeamHandler) SetWorkingHoursPreference(w http.ResponseWriter, r *http.Request) {
+	// Parse request body
+	var req request.SetWorkingHoursPreferenceRequest
+	if err := decodeJSONBody(w, r, &req); err != nil {
+		respondWithError(w, err)
+		return
+	}
+
+	// Validate input
+	if req.TeamName == "" {
+		respondWithError(w, pkgerrors.NewRequiredFieldError("team_name"))
+		return
+	}
+
+	// Call service
+	resp, err := h.teamService.SetWorkingHoursPreference(r.Context(), &req)
+	if err != nil {
+		logger.Error("Failed to set working hours preference for team %s: %v", req.TeamName, err)
+		respondWithError(w, err)
+		return
+	}
+
+	// Send response
+	respondWithJSON(w, http.StatusOK, resp)
+}
+
+// SetSeniorityPolicy handles POST /team/setSeniorityPolicy
+func (h *TeamHandler) SetSeniorityPolicy(w http.ResponseWriter, r *http.Request) {
+	// Parse request body
+	var req request.SetSeniorityPolicyRequest
+	if err := decodeJSONBody(w, r, &req); err != nil {
+		respondWithError(w, err)
+		return
+	}
+
+	// Validate input
+	if req.TeamName == "" {
+		respondWithError(w, pkgerrors.NewRequiredFieldError("team_name"))
+		return
+	}
+
+	// Call service
+	resp, err := h.teamService.SetSeniorityPolicy(r.Context(), &req)
+	if err != nil {
+		logger.Error("Failed to set seniority policy for team %s: %v", req.TeamName, err)
+		respondWithError(w, err)
+		return
+	}
+
+	// Send response
+	respondWithJSON(w, http.StatusOK, resp)
+}
+
+// UploadCodeOwners handles POST /team/codeowners/upload?team_name=...&dry_run=... with a CODEOWNERS file as the body
+func (h *TeamHandler) UploadCodeOwners(w http.ResponseWriter, r *http.Request) {
+	// Get team_name and dry_run from query parameters
+	query := r.URL.Query()
+	teamName := query.Get("team_name")
+	if teamName == "" {
+		respondWithError(w, pkgerrors.NewRequiredFieldError("team_name"))
+		return
+	}
+	dryRun := false
+	if value := query.Get("dry_run"); value != "" {
+		var err error
+		if dryRun, err = strconv.ParseBool(value); err != nil {
+			respondWithError(w, pkgerrors.NewValidationError("dry_run", "must be true or false"))
+			return
+		}
+	}
+
+	file, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxCodeOwnersSize))
+	if err != nil {
+		respondWithError(w, pkgerrors.NewBadRequestError("failed to read CODEOWNERS", err))
+		return
+	}
+
+	logger.Info("Uploading a %d-byte CODEOWNERS for team %s (dry run: %t)", len(file), teamName, dryRun)
+
+	// Call service
+	resp, err := h.teamService.UploadCodeOwners(r.Context(), teamName, dryRun, file)
+	if err != nil {
+		logger.Error("Failed to upload CODEOWNERS for team %s: %v", teamName, err)
+		respondWithError(w, err)
+		return
+	}
+
+	// Send response
+	respondWithJSON(w, http.StatusOK, resp)
+}
+
+// GetCodeOwners handles GET /team/codeowners?team_name=...
+func (h *TeamHandler) GetCodeOwners(w http.ResponseWriter, r *http.Request) {
+	// Get team_name from query parameters
+	teamName := r.URL.Query().Get("team_name")
+	if teamName == "" {
+		respondWithError(w, pkgerrors.NewRequiredFieldError("team_name"))
+		return
+	}
+
+	// Call service
+	resp, err := h.teamService.GetCodeOwners(r.Context(), teamName)
+	if err != nil {
+		logger.Error("Failed to get CODEOWNERS of team %s: %v", teamName, err)
+		respondWithError(w, err)
+		return
+	}
+
+	// Send response
+	respondWithJSON(w, http.StatusOK, resp)
+}
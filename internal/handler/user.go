@@ -1,14 +1,18 @@
 package handler
 
 import (
-	"fmt"
+	"io"
 	"net/http"
 
-	"avito-backend-trainee-assignment-autumn-2025/internal/dto/request"
 	"avito-backend-trainee-assignment-autumn-2025/internal/service"
+	"avito-backend-trainee-assignment-autumn-2025/pkg/dto/request"
+	pkgerrors "avito-backend-trainee-assignment-autumn-2025/pkg/errors"
 	"avito-backend-trainee-assignment-autumn-2025/pkg/logger"
 )
 
+// maxCalendarSize limits uploaded iCalendar files
+const maxCalendarSize = 1 << 20
+
 // UserHandler handles user-related HTTP requests
 type UserHandler struct {
 	userService service.UserService
@@ -26,13 +30,13 @@ func (h *UserHandler) SetIsActive(w http.ResponseWriter, r *http.Request) {
 	// Parse request body
 	var req request.SetUserActiveRequest
 	if err := decodeJSONBody(w, r, &req); err != nil {
-		respondWithError(w, fmt.Errorf("invalid request body: %w", err))
+		respondWithError(w, err)
 		return
 	}
 
 	// Validate input
 	if req.UserID == "" {
-		respondWithError(w, fmt.Errorf("user_id is required"))
+		respondWithError(w, pkgerrors.NewRequiredFieldError("user_id"))
 		return
 	}
 
@@ -50,12 +54,120 @@ func (h *UserHandler) SetIsActive(w http.ResponseWriter, r *http.Request) {
 	respondWithJSON(w, http.StatusOK, resp)
 }
 
+// SetMentionHandle handles POST /users/setMentionHandle
+func (h *UserHandler) SetMentionHandle(w http.ResponseWriter, r *http.Request) {
+	// Parse request body
+	var req request.SetMentionHandleRequest
+	if err := decodeJSONBody(w, r, &req); err != nil {
+		respondWithError(w, err)
+		return
+	}
+
+	// Validate input
+	if req.UserID == "" {
+		respondWithError(w, pkgerrors.NewRequiredFieldError("user_id"))
+		return
+	}
+
+	// Call service
+	resp, err := h.userService.SetMentionHandle(r.Context(), &req)
+	if err != nil {
+		logger.Error("Failed to set mention handle for user %s: %v", req.UserID, err)
+		respondWithError(w, err)
+		return
+	}
+
+	// Send response
+	respondWithJSON(w, http.StatusOK, resp)
+}
+
+// SetEmail handles POST /users/setEmail
+func (h *UserHandler) SetEmail(w http.ResponseWriter, r *http.Request) {
+	// Parse request body
+	var req request.SetEmailRequest
+	if err := decodeJSONBody(w, r, &req); err != nil {
+		respondWithError(w, err)
+		return
+	}
+
+	// Validate input
+	if req.UserID == "" {
+		respondWithError(w, pkgerrors.NewRequiredFieldError("user_id"))
+		return
+	}
+
+	// Call service
+	resp, err := h.userService.SetEmail(r.Context(), &req)
+	if err != nil {
+		logger.Error("Failed to set email for user %s: %v", req.UserID, err)
+		respondWithError(w, err)
+		return
+	}
+
+	// Send response
+	respondWithJSON(w, http.StatusOK, resp)
+}
+
+// SetWorkingHours handles POST /users/setWorkingHours
+func (h *UserHandler) SetWorkingHours(w http.ResponseWriter, r *http.Request) {
+	// Parse request body
+	var req request.SetWorkingHoursRequest
+	if err := decodeJSONBody(w, r, &req); err != nil {
+		respondWithError(w, err)
+		return
+	}
+
+	// Validate input
+	if req.UserID == "" {
+		respondWithError(w, pkgerrors.NewRequiredFieldError("user_id"))
+		return
+	}
+
+	// Call service
+	resp, err := h.userService.SetWorkingHours(r.Context(), &req)
+	if err != nil {
+		logger.Error("Failed to set working hours for user %s: %v", req.UserID, err)
+		respondWithError(w, err)
+		return
+	}
+
+	// Send response
+	respondWithJSON(w, http.StatusOK, resp)
+}
+
+// SetMaxOpenReviews handles POST /users/setMaxOpenReviews
+func (h *UserHandler) SetMaxOpenReviews(w http.ResponseWriter, r *http.Request) {
+	// Parse request body
+	var req request.SetMaxOpenReviewsRequest
+	if err := decodeJSONBody(w, r, &req); err != nil {
+		respondWithError(w, err)
+		return
+	}
+
+	// Validate input
+	if req.UserID == "" {
+		respondWithError(w, pkgerrors.NewRequiredFieldError("user_id"))
+		return
+	}
+
+	// Call service
+	resp, err := h.userService.SetMaxOpenReviews(r.Context(), &req)
+	if err != nil {
+		logger.Error("Failed to set max open reviews for user %s: %v", req.UserID, err)
+		respondWithError(w, err)
+		return
+	}
+
+	// Send response
+	respondWithJSON(w, http.StatusOK, resp)
+}
+
 // GetUserReviews handles GET /users/getReview?user_id=...
 func (h *UserHandler) GetUserReviews(w http.ResponseWriter, r *http.Request) {
 	// Get user_id from query parameters
 	userID := r.URL.Query().Get("user_id")
 	if userID == "" {
-		respondWithError(w, fmt.Errorf("user_id query parameter is required"))
+		respondWithError(w, pkgerrors.NewRequiredFieldError("user_id"))
 		return
 	}
 
@@ -72,3 +184,319 @@ func (h *UserHandler) GetUserReviews(w http.ResponseWriter, r *http.Request) {
 	// Send response
 	respondWithJSON(w, http.StatusOK, resp)
 }
+
+// SetSeniority handles POST /users/setSeniority
+func (h *UserHandler) SetSeniority(w http.ResponseWriter, r *http.Request) {
+	// Parse request body
+	var req request.SetSeniorityRequest
+	if err := decodeJSONBody(w, r, &req); err != nil {
+		respondWithError(w, err)
+		return
+	}
+
+	// Validate input
+	if req.UserID == "" {
+		respondWithError(w, pkgerrors.NewRequiredFieldError("user_id"))
+		return
+	}
+
+	// Call service
+	resp, err := h.userService.SetSeniority(r.Context(), &req)
+	if err != nil {
+		logger.Error("Failed to set seniority for user %s: %v", req.UserID, err)
+		respondWithError(w, err)
+		return
+	}
+
+	// Send response
+	respondWithJSON(w, http.StatusOK, resp)
+}
+
+// AddSkillTags handles POST /users/tags/add
+func (h *UserHandler) AddSkillTags(w http.ResponseWriter, r *http.Request) {
+	// Parse request body
+	var req request.AddSkillTagsRequest
+	if err := decodeJSONBody(w, r, &req); err != nil {
+		respondWithError(w, err)
+		return
+	}
+
+	// Validate input
+	if req.UserID == "" {
+		respondWithError(w, pkgerrors.NewRequiredFieldError("user_id"))
+		return
+	}
+
+	// Call service
+	resp, err := h.userService.AddSkillTags(r.Context(), &req)
+	if err != nil {
+		logger.Error("Failed to add skill tags of user %s: %v", req.UserID, err)
+		respondWithError(w, err)
+		return
+	}
+
+	// Send response
+	respondWithJSON(w, http.StatusOK, resp)
+}
+
+// RemoveSkillTags handles POST /users/tags/remove
+func (h *UserHandler) RemoveSkillTags(w http.ResponseWriter, r *http.Request) {
+	// Parse request body
+	var req request.RemoveSkillTagsRequest
+	if err := decodeJSONBody(w, r, &req); err != nil {
+		respondWithError(w, err)
+		return
+	}
+
+	// Validate input
+	if req.UserID == "" {
+		respondWithError(w, pkgerrors.NewRequiredFieldError("user_id"))
+		return
+	}
+
+	// Call service
+	resp, err := h.userService.RemoveSkillTags(r.Context(), &req)
+	if err != nil {
+		logger.Error("Failed to remove skill tags of user %s: %v", req.UserID, err)
+		respondWithError(w, err)
+		return
+	}
+
+	// Send response
+	respondWithJSON(w, http.StatusOK, resp)
+}
+
+// SetSkillTags handles POST /users/tags/set
+func (h *UserHandler) SetSkillTags(w http.ResponseWriter, r *http.Request) {
+	// Parse request body
+	var req request.SetSkillTagsRequest
+	if err := decodeJSONBody(w, r, &req); err != nil {
+		respondWithError(w, err)
+		return
+	}
+
+	// Validate input
+	if req.UserID == "" {
+		respondWithError(w, pkgerrors.NewRequiredFieldError("user_id"))
+		return
+	}
+
+	// Call service
+	resp, err := h.userService.SetSkillTags(r.Context(), &req)
+	if err != nil {
+		logger.Error("Failed to set skill tags of user %s: %v", req.UserID, err)
+		respondWithError(w, err)
+		return
+	}
+
+	// Send response
+	respondWithJSON(w, http.StatusOK, resp)
+}
+
+// ListSkillTags handles GET /users/tags/list?user_id=...
+func (h *UserHandler) ListSkillTags(w http.ResponseWriter, r *http.Request) {
+	// Get user_id from query parameters
+	userID := r.URL.Query().Get("user_id")
+	if userID == "" {
+		respondWithError(w, pkgerrors.NewRequiredFieldError("user_id"))
+		return
+	}
+
+	// Call service
+	resp, err := h.userService.GetSkillTags(r.Context(), userID)
+	if err != nil {
+		logger.Error("Failed to get skill tags of user %s: %v", userID, err)
+		respondWithError(w, err)
+		return
+	}
+
+	// Send response
+	respondWithJSON(w, http.StatusOK, resp)
+}
+
+// AddAbsence handles POST /users/absences/add
+func (h *UserHandler) AddAbsence(w http.ResponseWriter, r *http.Request) {
+	// Parse request body
+	var req request.AddAbsenceRequest
+	if err := decodeJSONBody(w, r, &req); err != nil {
+		respondWithError(w, err)
+		return
+	}
+
+	// Validate input
+	if req.UserID == "" {
+		respondWithError(w, pkgerrors.NewRequiredFieldError("user_id"))
+		return
+	}
+
+	// Call service
+	resp, err := h.userService.AddAbsence(r.Context(), &req)
+	if err != nil {
+		logger.Error("Failed to add absence for user %s: %v", req.UserID, err)
+		respondWithError(w, err)
+		return
+	}
+
+	// Send response
+	respondWithJSON(w, http.StatusCreated, resp)
+}
+
+// ListAbsences handles GET /users/absences/list?user_id=...
+func (h *UserHandler) ListAbsences(w http.ResponseWriter, r *http.Request) {
+	// Get user_id from query parameters
+	userID := r.URL.Query().Get("user_id")
+	if userID == "" {
+		respondWithError(w, pkgerrors.NewRequiredFieldError("user_id"))
+		return
+	}
+
+	// Call service
+	resp, err := h.userService.ListAbsences(r.Context(), userID)
+	if err != nil {
+		logger.Error("Failed to list absences of user %s: %v", userID, err)
+		respondWithError(w, err)
+		return
+	}
+
+	// Send response
+	respondWithJSON(w, http.StatusOK, resp)
+}
+
+// ImportAbsences handles POST /users/absences/import?user_id=... with an iCalendar file as the body
+func (h *UserHandler) ImportAbsences(w http.ResponseWriter, r *http.Request) {
+	// Get user_id from query parameters
+	userID := r.URL.Query().Get("user_id")
+	if userID == "" {
+		respondWithError(w, pkgerrors.NewRequiredFieldError("user_id"))
+		return
+	}
+
+	calendar, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxCalendarSize))
+	if err != nil {
+		respondWithError(w, pkgerrors.NewBadRequestError("failed to read calendar", err))
+		return
+	}
+
+	logger.Info("Importing absences of user %s from a %d-byte calendar", userID, len(calendar))
+
+	// Call service
+	resp, err := h.userService.ImportAbsences(r.Context(), userID, calendar)
+	if err != nil {
+		logger.Error("Failed to import absences of user %s: %v", userID, err)
+		respondWithError(w, err)
+		return
+	}
+
+	// Send response
+	respondWithJSON(w, http.StatusOK, resp)
+}
+
+// DeleteAbsence handles POST /users/absences/delete
+func (h *UserHandler) DeleteAbsence(w http.ResponseWriter, r *http.Request) {
+	// Parse request body
+	var req request.DeleteAbsenceRequest
+	if err := decodeJSONBody(w, r, &req); err != nil {
+		respondWithError(w, err)
+		return
+	}
+
+	// Validate input
+	if req.AbsenceID == "" {
+		respondWithError(w, pkgerrors.NewRequiredFieldError("absence_id"))
+		return
+	}
+
+	// Call service
+	resp, err := h.userService.DeleteAbsence(r.Context(), &req)
+	if err != nil {
+		logger.Error("Failed to delete absence %s: %v", req.AbsenceID, err)
+		respondWithError(w, err)
+		return
+	}
+
+	// Send response
+	respondWithJSON(w, http.StatusOK, resp)
+}
+
+// SetAffinity handles POST /users/affinities/set
+func (h *UserHandler) SetAffinity(w http.ResponseWriter, r *http.Request) {
+	// Parse request body
+	var req request.SetAffinityRequest
+	if err := decodeJSONBody(w, r, &req); err != nil {
+		respondWithError(w, err)
+		return
+	}
+
+	// Validate input
+	if req.AuthorID == "" {
+		respondWithError(w, pkgerrors.NewRequiredFieldError("author_id"))
+		return
+	}
+	if req.ReviewerID == "" {
+		respondWithError(w, pkgerrors.NewRequiredFieldError("reviewer_id"))
+		return
+	}
+
+	// Call service
+	resp, err := h.userService.SetAffinity(r.Context(), &req)
+	if err != nil {
+		logger.Error("Failed to set affinity of %s to %s: %v", req.AuthorID, req.ReviewerID, err)
+		respondWithError(w, err)
+		return
+	}
+
+	// Send response
+	respondWithJSON(w, http.StatusOK, resp)
+}
+
+// ListAffinities handles GET /users/affinities/list
+func (h *UserHandler) ListAffinities(w http.ResponseWriter, r *http.Request) {
+	// Get user_id from query parameters
+	userID := r.URL.Query().Get("user_id")
+	if userID == "" {
+		respondWithError(w, pkgerrors.NewRequiredFieldError("user_id"))
+		return
+	}
+
+	// Call service
+	resp, err := h.userService.ListAffinities(r.Context(), userID)
+	if err != nil {
+		logger.Error("Failed to list affinities of user %s: %v", userID, err)
+		respondWithError(w, err)
+		return
+	}
+
+	// Send response
+	respondWithJSON(w, http.StatusOK, resp)
+}
+
+// DeleteAffinity handles POST /users/affinities/delete
+func (h *UserHandler) DeleteAffinity(w http.ResponseWriter, r *http.Request) {
+	// Parse request body
+	var req request.DeleteAffinityRequest
+	if err := decodeJSONBody(w, r, &req); err != nil {
+		respondWithError(w, err)
+		return
+	}
+
+	// Validate input
+	if req.AuthorID == "" {
+		respondWithError(w, pkgerrors.NewRequiredFieldError("author_id"))
+		return
+	}
+	if req.ReviewerID == "" {
+		respondWithError(w, pkgerrors.NewRequiredFieldError("reviewer_id"))
+		return
+	}
+
+	// Call service
+	resp, err := h.userService.DeleteAffinity(r.Context(), &req)
+	if err != nil {
+		logger.Error("Failed to delete affinity of %s to %s: %v", req.AuthorID, req.ReviewerID, err)
+		respondWithError(w, err)
+		return
+	}
+
+	// Send response
+	respondWithJSON(w, http.StatusOK, resp)
+}
@@ -0,0 +1,111 @@
+package handler
+
+import (
+	"net/http"
+
+	"avito-backend-trainee-assignment-autumn-2025/internal/service"
+	"avito-backend-trainee-assignment-autumn-2025/pkg/dto/request"
+	pkgerrors "avito-backend-trainee-assignment-autumn-2025/pkg/errors"
+	"avito-backend-trainee-assignment-autumn-2025/pkg/logger"
+)
+
+// WebhookHandler handles webhook management HTTP requests
+type WebhookHandler struct {
+	webhookService service.WebhookService
+}
+
+// NewWebhookHandler creates a new webhook handler
+func NewWebhookHandler(webhookService service.WebhookService) *WebhookHandler {
+	return &WebhookHandler{
+		webhookService: webhookService,
+	}
+}
+
+// RegisterWebhook handles POST /webhooks/add
+func (h *WebhookHandler) RegisterWebhook(w http.ResponseWriter, r *http.Request) {
+	// Parse request body
+	var req request.RegisterWebhookRequest
+	if err := decodeJSONBody(w, r, &req); err != nil {
+		respondWithError(w, err)
+		return
+	}
+
+	// Validate input
+	if req.URL == "" {
+		respondWithError(w, pkgerrors.NewRequiredFieldError("url"))
+		return
+	}
+
+	logger.Info("Registering webhook: %s", req.URL)
+
+	// Call service
+	resp, err := h.webhookService.RegisterWebhook(r.Context(), &req)
+	if err != nil {
+		logger.Error("Failed to register webhook %s: %v", req.URL, err)
+		respondWithError(w, err)
+		return
+	}
+
+	// Send response
+	respondWithJSON(w, http.StatusCreated, resp)
+}
+
+// ListWebhooks handles GET /webhooks/list
+func (h *WebhookHandler) ListWebhooks(w http.ResponseWriter, r *http.Request) {
+	// Call service
+	resp, err := h.webhookService.ListWebhooks(r.Context())
+	if err != nil {
+		logger.Error("Failed to list webhooks: %v", err)
+		respondWithError(w, err)
+		return
+	}
+
+	// Send response
+	respondWithJSON(w, http.StatusOK, resp)
+}
+
+// ListDeliveries handles GET /webhooks/deliveries?status=...
+func (h *WebhookHandler) ListDeliveries(w http.ResponseWriter, r *http.Request) {
+	// status is optional; "failed" lists the dead letters
+	status := r.URL.Query().Get("status")
+
+	// Call service
+	resp, err := h.webhookService.ListDeliveries(r.Context(), status)
+	if err != nil {
+		logger.Error("Failed to list webhook deliveries: %v", err)
+		respondWithError(w, err)
+		return
+	}
+
+	// Send response
+	respondWithJSON(w, http.StatusOK, resp)
+}
+
+// Redeliver handles POST /webhooks/redeliver
+func (h *WebhookHandler) Redeliver(w http.ResponseWriter, r *http.Request) {
+	// Parse request body
+	var req request.RedeliverWebhookRequest
+	if err := decodeJSONBody(w, r, &req); err != nil {
+		respondWithError(w, err)
+		return
+	}
+
+	// Validate input
+	if req.DeliveryID == "" {
+		respondWithError(w, pkgerrors.NewRequiredFieldError("delivery_id"))
+		return
+	}
+
+	logger.Info("Redelivering webhook delivery: %s", req.DeliveryID)
+
+	// Call service
+	resp, err := h.webhookService.RedeliverDelivery(r.Context(), &req)
+	if err != nil {
+		logger.Error("Failed to redeliver %s: %v", req.DeliveryID, err)
+		respondWithError(w, err)
+		return
+	}
+
+	// Send response
+	respondWithJSON(w, http.StatusOK, resp)
+}
@@ -0,0 +1,559 @@
+// Package ical reads out-of-office periods from iCalendar files (RFC 5545)
+//
+// Only what an absence needs is supported: VEVENT dates and durations, RRULE with
+// FREQ, INTERVAL, COUNT, UNTIL, BYDAY and WKST, RDATE, EXDATE and RECURRENCE-ID overrides.
+// Time zones are resolved by their IANA names; VTIMEZONE definitions are not read.
+package ical
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"avito-backend-trainee-assignment-autumn-2025/internal/domain/models"
+	pkgerrors "avito-backend-trainee-assignment-autumn-2025/pkg/errors"
+)
+
+// MaxPeriods limits the number of periods a single file may produce
+const MaxPeriods = 1000
+
+// maxOccurrences limits the periods a recurrence rule may step through before reaching the window end
+const maxOccurrences = 100000
+
+// statusCancelled marks events and overridden occurrences that did not happen
+const statusCancelled = "CANCELLED"
+
+// Period is one occurrence of an event as a range of calendar dates; both dates are included
+type Period struct {
+	UID     string
+	Summary string
+	// StartDate and EndDate are calendar dates stored as midnight UTC, like models.Absence
+	StartDate time.Time
+	EndDate   time.Time
+}
+
+// Window limits the periods returned by Parse
+type Window struct {
+	// From drops occurrences that end before this date
+	From time.Time
+	// Until drops occurrences that start after this date and bounds endless recurrences
+	Until time.Time
+	// Location resolves floating and UTC times to calendar dates; defaults to UTC
+	Location *time.Location
+}
+
+// Parse returns the periods of the VEVENTs in data that overlap the window, ordered by start date
+func Parse(data []byte, window Window) ([]Period, error) {
+	if window.Location == nil {
+		window.Location = time.UTC
+	}
+
+	properties, err := readProperties(data)
+	if err != nil {
+		return nil, err
+	}
+
+	events, err := collectEvents(properties, window.Location)
+	if err != nil {
+		return nil, err
+	}
+
+	// Occurrences moved or cancelled by RECURRENCE-ID are replaced by their override
+	overridden := make(map[string]map[int64]bool)
+	for _, e := range events {
+		if e.recurrenceID != nil {
+			if overridden[e.uid] == nil {
+				overridden[e.uid] = make(map[int64]bool)
+			}
+			overridden[e.uid][e.recurrenceID.Unix()] = true
+		}
+	}
+
+	periods := make([]Period, 0)
+	for _, e := range events {
+		if e.status == statusCancelled {
+			continue
+		}
+
+		starts := []time.Time{e.start.t}
+		if e.recurrenceID == nil {
+			if starts, err = e.occurrences(window); err != nil {
+				return nil, err
+			}
+		}
+
+		for _, start := range starts {
+			if e.recurrenceID == nil && overridden[e.uid][start.Unix()] {
+				continue
+			}
+			period := e.period(start)
+			if period.EndDate.Before(window.From) || period.StartDate.After(window.Until) {
+				continue
+			}
+			if len(periods) == MaxPeriods {
+				return nil, pkgerrors.NewValidationError("calendar", fmt.Sprintf("produces more than %d absences", MaxPeriods))
+			}
+			periods = append(periods, period)
+		}
+	}
+
+	sort.SliceStable(periods, func(i, j int) bool {
+		if !periods[i].StartDate.Equal(periods[j].StartDate) {
+			return periods[i].StartDate.Before(periods[j].StartDate)
+		}
+		if !periods[i].EndDate.Equal(periods[j].EndDate) {
+			return periods[i].EndDate.Before(periods[j].EndDate)
+		}
+		return periods[i].UID < periods[j].UID
+	})
+	return periods, nil
+}
+
+// property is one unfolded content line
+type property struct {
+	name   string
+	params map[string]string
+	value  string
+	line   int
+}
+
+// readProperties unfolds the content lines of data and splits them into properties
+func readProperties(data []byte) ([]property, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), len(data)+1)
+
+	var (
+		properties []property
+		current    strings.Builder
+		startLine  int
+	)
+	flush := func() error {
+		if current.Len() == 0 {
+			return nil
+		}
+		prop, err := parseContentLine(current.String(), startLine)
+		if err != nil {
+			return err
+		}
+		properties = append(properties, prop)
+		current.Reset()
+		return nil
+	}
+
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSuffix(scanner.Text(), "\r")
+		if lineNumber == 1 {
+			line = strings.TrimPrefix(line, "\ufeff")
+		}
+
+		// A line starting with a space or a tab continues the previous one
+		if strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t") {
+			if current.Len() == 0 {
+				return nil, syntaxError(lineNumber, "continuation line without a property")
+			}
+			current.WriteString(line[1:])
+			continue
+		}
+
+		if err := flush(); err != nil {
+			return nil, err
+		}
+		if strings.TrimSpace(line) != "" {
+			current.WriteString(line)
+			startLine = lineNumber
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, pkgerrors.NewBadRequestError("failed to read calendar", err)
+	}
+	if err := flush(); err != nil {
+		return nil, err
+	}
+
+	if len(properties) == 0 || properties[0].name != "BEGIN" || !strings.EqualFold(properties[0].value, "VCALENDAR") {
+		return nil, pkgerrors.NewValidationError("calendar", "must start with BEGIN:VCALENDAR")
+	}
+	return properties, nil
+}
+
+// parseContentLine splits "NAME;PARAM=value:VALUE" into its parts
+// Colons and semicolons inside quoted parameter values do not count as delimiters
+func parseContentLine(line string, lineNumber int) (property, error) {
+	prop := property{params: make(map[string]string), line: lineNumber}
+
+	quoted := false
+	valueStart := -1
+	var segments []string
+	segmentStart := 0
+	for i, r := range line {
+		switch {
+		case r == '"':
+			quoted = !quoted
+		case quoted:
+		case r == ';':
+			segments = append(segments, line[segmentStart:i])
+			segmentStart = i + 1
+		case r == ':':
+			segments = append(segments, line[segmentStart:i])
+			valueStart = i + 1
+		}
+		if valueStart >= 0 {
+			break
+		}
+	}
+	if valueStart < 0 || segments[0] == "" {
+		return prop, syntaxError(lineNumber, fmt.Sprintf("malformed content line %q", line))
+	}
+
+	prop.name = strings.ToUpper(segments[0])
+	prop.value = line[valueStart:]
+	for _, segment := range segments[1:] {
+		name, value, ok := strings.Cut(segment, "=")
+		if !ok {
+			return prop, syntaxError(lineNumber, fmt.Sprintf("malformed parameter %q", segment))
+		}
+		prop.params[strings.ToUpper(name)] = strings.Trim(value, `"`)
+	}
+	return prop, nil
+}
+
+// syntaxError reports a malformed calendar file
+func syntaxError(line int, message string) error {
+	return pkgerrors.NewBadRequestError("invalid calendar", fmt.Errorf("line %d: %s", line, message))
+}
+
+// eventError reports an event the service cannot turn into absences
+func eventError(line int, message string) error {
+	return pkgerrors.NewValidationError("calendar", fmt.Sprintf("VEVENT at line %d: %s", line, message))
+}
+
+// collectEvents builds events from the properties of top-level VEVENT components
+// Nested components such as VALARM and other top-level components are skipped
+func collectEvents(properties []property, loc *time.Location) ([]*event, error) {
+	var (
+		events  []*event
+		stack   []string
+		current *event
+	)
+
+	for _, prop := range properties {
+		switch prop.name {
+		case "BEGIN":
+			component := strings.ToUpper(prop.value)
+			stack = append(stack, component)
+			if component == "VEVENT" && len(stack) == 2 {
+				current = &event{line: prop.line}
+			}
+			continue
+		case "END":
+			component := strings.ToUpper(prop.value)
+			if len(stack) == 0 || stack[len(stack)-1] != component {
+				return nil, syntaxError(prop.line, fmt.Sprintf("unexpected END:%s", prop.value))
+			}
+			stack = stack[:len(stack)-1]
+			if component == "VEVENT" && len(stack) == 1 {
+				if err := current.validate(); err != nil {
+					return nil, err
+				}
+				events = append(events, current)
+				current = nil
+			}
+			continue
+		}
+
+		if current == nil || len(stack) != 2 {
+			continue
+		}
+		if err := current.set(prop, loc); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(stack) != 0 {
+		return nil, pkgerrors.NewBadRequestError("invalid calendar", fmt.Errorf("missing END:%s", stack[len(stack)-1]))
+	}
+	return events, nil
+}
+
+// event is a VEVENT reduced to what an absence needs
+type event struct {
+	uid     string
+	summary string
+	status  string
+
+	start       *dateValue
+	end         *dateValue
+	duration    *time.Duration
+	durationDay int
+
+	rule         *recurrenceRule
+	rdates       []dateValue
+	exdates      []dateValue
+	recurrenceID *time.Time
+
+	line int
+}
+
+// set applies a VEVENT property
+func (e *event) set(prop property, loc *time.Location) error {
+	var err error
+	switch prop.name {
+	case "UID":
+		e.uid = prop.value
+	case "SUMMARY":
+		e.summary = unescapeText(prop.value)
+	case "STATUS":
+		e.status = strings.ToUpper(prop.value)
+	case "DTSTART":
+		var value dateValue
+		value, err = parseDateValue(prop, loc)
+		e.start = &value
+	case "DTEND":
+		var value dateValue
+		value, err = parseDateValue(prop, loc)
+		e.end = &value
+	case "DURATION":
+		var duration time.Duration
+		var days int
+		duration, days, err = parseDuration(prop.value)
+		e.duration, e.durationDay = &duration, days
+	case "RRULE":
+		e.rule, err = parseRecurrenceRule(prop.value, loc)
+	case "RDATE":
+		if strings.EqualFold(prop.params["VALUE"], "PERIOD") {
+			return eventError(prop.line, "RDATE periods are not supported")
+		}
+		var values []dateValue
+		values, err = parseDateList(prop, loc)
+		e.rdates = append(e.rdates, values...)
+	case "EXDATE":
+		var values []dateValue
+		values, err = parseDateList(prop, loc)
+		e.exdates = append(e.exdates, values...)
+	case "RECURRENCE-ID":
+		var value dateValue
+		value, err = parseDateValue(prop, loc)
+		e.recurrenceID = &value.t
+	}
+	if err != nil {
+		return eventError(prop.line, fmt.Sprintf("%s: %v", prop.name, err))
+	}
+	return nil
+}
+
+// validate checks that the event has everything needed to compute its periods
+func (e *event) validate() error {
+	if e.uid == "" {
+		return eventError(e.line, "UID is required")
+	}
+	if e.start == nil {
+		return eventError(e.line, "DTSTART is required")
+	}
+	if e.end != nil && e.duration != nil {
+		return eventError(e.line, "DTEND and DURATION are mutually exclusive")
+	}
+	if e.end != nil && e.end.allDay != e.start.allDay {
+		return eventError(e.line, "DTSTART and DTEND must both be dates or both be date-times")
+	}
+	if e.end != nil && e.end.t.Before(e.start.t) {
+		return eventError(e.line, "DTEND is before DTSTART")
+	}
+	return nil
+}
+
+// endOf returns the end of the occurrence starting at start
+func (e *event) endOf(start time.Time) time.Time {
+	switch {
+	case e.end != nil:
+		if e.start.allDay {
+			return start.AddDate(0, 0, daysBetween(e.start.t, e.end.t))
+		}
+		return start.Add(e.end.t.Sub(e.start.t))
+	case e.duration != nil:
+		return start.AddDate(0, 0, e.durationDay).Add(*e.duration)
+	case e.start.allDay:
+		// An all-day event without an end lasts one day
+		return start.AddDate(0, 0, 1)
+	default:
+		return start
+	}
+}
+
+// period converts the occurrence starting at start into calendar dates
+// The end of an event is exclusive: an all-day event ending on the 15th lasts until the 14th
+func (e *event) period(start time.Time) Period {
+	end := e.endOf(start)
+	if end.After(start) {
+		end = end.Add(-time.Nanosecond)
+	}
+	return Period{
+		UID:       e.uid,
+		Summary:   e.summary,
+		StartDate: models.DateIn(start, start.Location()),
+		EndDate:   models.DateIn(end, end.Location()),
+	}
+}
+
+// occurrences returns the start times of the event within the window
+func (e *event) occurrences(window Window) ([]time.Time, error) {
+	var starts []time.Time
+	if e.rule == nil {
+		starts = []time.Time{e.start.t}
+	} else {
+		var err error
+		if starts, err = e.rule.expand(e.start.t, window.Until.AddDate(0, 0, 1)); err != nil {
+			return nil, eventError(e.line, err.Error())
+		}
+	}
+
+	for _, rdate := range e.rdates {
+		starts = append(starts, rdate.t)
+	}
+
+	excluded := make(map[int64]bool, len(e.exdates))
+	for _, exdate := range e.exdates {
+		excluded[exdate.t.Unix()] = true
+	}
+
+	seen := make(map[int64]bool, len(starts))
+	result := make([]time.Time, 0, len(starts))
+	for _, start := range starts {
+		key := start.Unix()
+		if excluded[key] || seen[key] {
+			continue
+		}
+		seen[key] = true
+		result = append(result, start)
+	}
+	return result, nil
+}
+
+// dateValue is a DATE or DATE-TIME property value
+// Dates are midnight UTC; date-times are in the location the calendar dates are read in
+type dateValue struct {
+	t      time.Time
+	allDay bool
+}
+
+// parseDateValue parses a single DATE or DATE-TIME value honouring VALUE and TZID
+func parseDateValue(prop property, loc *time.Location) (dateValue, error) {
+	values, err := parseDateList(prop, loc)
+	if err != nil {
+		return dateValue{}, err
+	}
+	if len(values) != 1 {
+		return dateValue{}, fmt.Errorf("expected a single value, got %q", prop.value)
+	}
+	return values[0], nil
+}
+
+// parseDateList parses a comma-separated list of DATE or DATE-TIME values
+func parseDateList(prop property, loc *time.Location) ([]dateValue, error) {
+	zone := loc
+	if tzid := prop.params["TZID"]; tzid != "" {
+		var err error
+		if zone, err = time.LoadLocation(strings.TrimPrefix(tzid, "/")); err != nil {
+			return nil, fmt.Errorf("unknown time zone %q", tzid)
+		}
+	}
+
+	valueType := strings.ToUpper(prop.params["VALUE"])
+	var values []dateValue
+	for _, raw := range strings.Split(prop.value, ",") {
+		value, err := parseDateTime(raw, valueType == "DATE", zone, loc)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	return values, nil
+}
+
+// parseDateTime parses 20251110, 20251110T090000 (in zone) or 20251110T090000Z (UTC, shown in loc)
+func parseDateTime(raw string, isDate bool, zone, loc *time.Location) (dateValue, error) {
+	if isDate || len(raw) == len("20060102") {
+		t, err := time.Parse("20060102", raw)
+		if err != nil {
+			return dateValue{}, fmt.Errorf("invalid date %q", raw)
+		}
+		return dateValue{t: t, allDay: true}, nil
+	}
+
+	if strings.HasSuffix(raw, "Z") {
+		t, err := time.Parse("20060102T150405Z", raw)
+		if err != nil {
+			return dateValue{}, fmt.Errorf("invalid date-time %q", raw)
+		}
+		return dateValue{t: t.In(loc)}, nil
+	}
+
+	t, err := time.ParseInLocation("20060102T150405", raw, zone)
+	if err != nil {
+		return dateValue{}, fmt.Errorf("invalid date-time %q", raw)
+	}
+	return dateValue{t: t}, nil
+}
+
+// parseDuration parses an RFC 5545 duration such as P1W, P2D or PT4H30M
+// Weeks and days are returned separately so that they follow the calendar across DST changes
+func parseDuration(raw string) (time.Duration, int, error) {
+	value := strings.TrimPrefix(raw, "+")
+	if strings.HasPrefix(value, "-") {
+		return 0, 0, fmt.Errorf("negative duration %q", raw)
+	}
+	if !strings.HasPrefix(value, "P") || len(value) < 3 {
+		return 0, 0, fmt.Errorf("invalid duration %q", raw)
+	}
+
+	var (
+		duration time.Duration
+		days     int
+		number   int
+		digits   bool
+		inTime   bool
+	)
+	for _, r := range value[1:] {
+		switch {
+		case r >= '0' && r <= '9':
+			number = number*10 + int(r-'0')
+			digits = true
+			continue
+		case r == 'T' && !inTime && !digits:
+			inTime = true
+			continue
+		case !digits:
+			return 0, 0, fmt.Errorf("invalid duration %q", raw)
+		case r == 'W' && !inTime:
+			days += 7 * number
+		case r == 'D' && !inTime:
+			days += number
+		case r == 'H' && inTime:
+			duration += time.Duration(number) * time.Hour
+		case r == 'M' && inTime:
+			duration += time.Duration(number) * time.Minute
+		case r == 'S' && inTime:
+			duration += time.Duration(number) * time.Second
+		default:
+			return 0, 0, fmt.Errorf("invalid duration %q", raw)
+		}
+		number, digits = 0, false
+	}
+	if digits {
+		return 0, 0, fmt.Errorf("invalid duration %q", raw)
+	}
+	return duration, days, nil
+}
+
+// unescapeText decodes the TEXT escapes \n, \, \; and \\
+func unescapeText(value string) string {
+	replacer := strings.NewReplacer(`\n`, "\n", `\N`, "\n", `\,`, ",", `\;`, ";", `\\`, `\`)
+	return replacer.Replace(value)
+}
+
+// daysBetween returns the number of calendar days from a to b
+func daysBetween(a, b time.Time) int {
+	return int(models.DateIn(b, b.Location()).Sub(models.DateIn(a, a.Location())) / (24 * time.Hour))
+}
@@ -0,0 +1,204 @@
+package ical
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	pkgerrors "avito-backend-trainee-assignment-autumn-2025/pkg/errors"
+)
+
+// loadFixture reads a calendar export from testdata
+func loadFixture(t *testing.T, name string) []byte {
+	t.Helper()
+	body, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatalf("Failed to read fixture %s: %v", name, err)
+	}
+	return body
+}
+
+// testWindow covers November 2025 to the end of 2026 in Moscow time
+func testWindow(t *testing.T) Window {
+	t.Helper()
+	moscow, err := time.LoadLocation("Europe/Moscow")
+	if err != nil {
+		t.Fatalf("LoadLocation: %v", err)
+	}
+	return Window{
+		From:     time.Date(2025, 11, 1, 0, 0, 0, 0, time.UTC),
+		Until:    time.Date(2026, 12, 31, 0, 0, 0, 0, time.UTC),
+		Location: moscow,
+	}
+}
+
+// formatPeriods renders periods as "start..end summary" for comparison
+func formatPeriods(periods []Period) []string {
+	result := make([]string, len(periods))
+	for i, p := range periods {
+		result[i] = fmt.Sprintf("%s..%s %s", p.StartDate.Format("2006-01-02"), p.EndDate.Format("2006-01-02"), p.Summary)
+	}
+	return result
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name     string
+		fixture  string
+		expected []string
+	}{
+		{
+			// All-day end is exclusive, date-times use their TZID, UTC times the window location;
+			// cancelled and past events are dropped, VALARM and VTIMEZONE are skipped
+			name:    "Single events",
+			fixture: "vacation.ics",
+			expected: []string{
+				"2025-11-10..2025-11-14 Vacation, Bali",
+				"2025-11-20..2025-11-21 Night release",
+				"2025-11-25..2025-11-26 Conference",
+			},
+		},
+		{
+			// COUNT includes the EXDATE, UNTIL bounds the rule, RECURRENCE-ID moves or cancels
+			// an occurrence, missing days of a month are skipped, an RDATE lasts as long as the event,
+			// an endless rule stops at the window end
+			name:    "Recurring events",
+			fixture: "recurring.ics",
+			expected: []string{
+				"2025-11-03..2025-11-03 Training",
+				"2025-11-05..2025-11-05 Training",
+				"2025-11-07..2025-11-07 Day off",
+				"2025-11-14..2025-11-14 Day off",
+				"2025-11-18..2025-11-18 Training (moved)",
+				"2025-11-28..2025-11-28 Day off",
+				"2025-12-01..2025-12-01 Training",
+				"2025-12-03..2025-12-03 Training",
+				"2025-12-31..2025-12-31 Month-end closing",
+				"2026-01-01..2026-04-02 Sabbatical",
+				"2026-01-31..2026-01-31 Month-end closing",
+				"2026-03-31..2026-03-31 Month-end closing",
+				"2026-06-01..2026-08-31 Sabbatical",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			periods, err := Parse(loadFixture(t, tt.fixture), testWindow(t))
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			got := formatPeriods(periods)
+			if strings.Join(got, "\n") != strings.Join(tt.expected, "\n") {
+				t.Errorf("Periods:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(tt.expected, "\n"))
+			}
+		})
+	}
+}
+
+func TestParseKeepsUIDs(t *testing.T) {
+	periods, err := Parse(loadFixture(t, "recurring.ics"), testWindow(t))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	uids := make(map[string]int)
+	for _, p := range periods {
+		uids[p.UID]++
+	}
+	if uids["training@example.com"] != 5 || uids["fridays@example.com"] != 3 {
+		t.Errorf("Periods per UID = %v", uids)
+	}
+}
+
+// calendar wraps VEVENT lines into a minimal VCALENDAR
+func calendar(lines ...string) []byte {
+	return []byte("BEGIN:VCALENDAR\r\nVERSION:2.0\r\n" + strings.Join(lines, "\r\n") + "\r\nEND:VCALENDAR\r\n")
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		data       []byte
+		badRequest bool
+		message    string
+	}{
+		{name: "Not a calendar", data: []byte(`{"user_id": "u1"}`), message: "must start with BEGIN:VCALENDAR"},
+		{name: "Empty", data: nil, message: "must start with BEGIN:VCALENDAR"},
+		{name: "Malformed line", data: []byte("BEGIN:VCALENDAR\r\nVERSION 2.0\r\n"), badRequest: true, message: `line 2: malformed content line "VERSION 2.0"`},
+		{name: "Dangling continuation", data: []byte(" BEGIN:VCALENDAR\r\n"), badRequest: true, message: "line 1: continuation line without a property"},
+		{name: "Unbalanced components", data: []byte("BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\nEND:VCALENDAR\r\n"), badRequest: true, message: "unexpected END:VCALENDAR"},
+		{name: "Missing END", data: []byte("BEGIN:VCALENDAR\r\nVERSION:2.0\r\n"), badRequest: true, message: "missing END:VCALENDAR"},
+		{
+			name:    "Missing DTSTART",
+			data:    calendar("BEGIN:VEVENT", "UID:a", "END:VEVENT"),
+			message: "VEVENT at line 3: DTSTART is required",
+		},
+		{
+			name:    "Missing UID",
+			data:    calendar("BEGIN:VEVENT", "DTSTART;VALUE=DATE:20251110", "END:VEVENT"),
+			message: "UID is required",
+		},
+		{
+			name:    "Unknown time zone",
+			data:    calendar("BEGIN:VEVENT", "UID:a", "DTSTART;TZID=W. Europe Standard Time:20251110T090000", "END:VEVENT"),
+			message: `unknown time zone "W. Europe Standard Time"`,
+		},
+		{
+			name:    "End before start",
+			data:    calendar("BEGIN:VEVENT", "UID:a", "DTSTART;VALUE=DATE:20251110", "DTEND;VALUE=DATE:20251109", "END:VEVENT"),
+			message: "DTEND is before DTSTART",
+		},
+		{
+			name:    "Invalid date",
+			data:    calendar("BEGIN:VEVENT", "UID:a", "DTSTART;VALUE=DATE:2025-11-10", "END:VEVENT"),
+			message: `invalid date "2025-11-10"`,
+		},
+		{
+			name:    "Invalid duration",
+			data:    calendar("BEGIN:VEVENT", "UID:a", "DTSTART;VALUE=DATE:20251110", "DURATION:P1Y", "END:VEVENT"),
+			message: `invalid duration "P1Y"`,
+		},
+		{
+			name:    "Unsupported RRULE part",
+			data:    calendar("BEGIN:VEVENT", "UID:a", "DTSTART;VALUE=DATE:20251110", "RRULE:FREQ=MONTHLY;BYMONTHDAY=-1", "END:VEVENT"),
+			message: "unsupported part BYMONTHDAY",
+		},
+		{
+			name:    "Ordinal BYDAY",
+			data:    calendar("BEGIN:VEVENT", "UID:a", "DTSTART;VALUE=DATE:20251110", "RRULE:FREQ=WEEKLY;BYDAY=1MO", "END:VEVENT"),
+			message: `unsupported BYDAY "1MO"`,
+		},
+		{
+			name:    "Too many absences",
+			data:    calendar("BEGIN:VEVENT", "UID:a", "DTSTART;VALUE=DATE:20251101", "RRULE:FREQ=DAILY", "END:VEVENT", "BEGIN:VEVENT", "UID:b", "DTSTART;VALUE=DATE:20251101", "RRULE:FREQ=DAILY", "END:VEVENT", "BEGIN:VEVENT", "UID:c", "DTSTART;VALUE=DATE:20251101", "RRULE:FREQ=DAILY", "END:VEVENT"),
+			message: "produces more than 1000 absences",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(tt.data, testWindow(t))
+			if err == nil {
+				t.Fatal("Expected an error, got nil")
+			}
+
+			var badRequestErr *pkgerrors.BadRequestError
+			var validationErr *pkgerrors.ValidationError
+			if tt.badRequest && !errors.As(err, &badRequestErr) {
+				t.Errorf("Expected a bad request error, got %T: %v", err, err)
+			}
+			if !tt.badRequest && !errors.As(err, &validationErr) {
+				t.Errorf("Expected a validation error, got %T: %v", err, err)
+			}
+			if !strings.Contains(err.Error(), tt.message) {
+				t.Errorf("Error %q does not mention %q", err.Error(), tt.message)
+			}
+		})
+	}
+}
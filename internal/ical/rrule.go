@@ -0,0 +1,189 @@
+package ical
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Recurrence frequencies supported in RRULE
+const (
+	freqDaily   = "DAILY"
+	freqWeekly  = "WEEKLY"
+	freqMonthly = "MONTHLY"
+	freqYearly  = "YEARLY"
+)
+
+// weekdays maps RFC 5545 weekday codes to time.Weekday
+var weekdays = map[string]time.Weekday{
+	"SU": time.Sunday,
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+}
+
+// recurrenceRule is a parsed RRULE
+type recurrenceRule struct {
+	freq      string
+	interval  int
+	count     int
+	until     *dateValue
+	byDay     map[time.Weekday]bool
+	weekStart time.Weekday
+}
+
+// parseRecurrenceRule parses an RRULE value such as FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,FR;UNTIL=20251231
+// Parts that would change the meaning of the rule but are not supported are rejected
+func parseRecurrenceRule(value string, loc *time.Location) (*recurrenceRule, error) {
+	rule := &recurrenceRule{interval: 1, weekStart: time.Monday}
+
+	for _, part := range strings.Split(value, ";") {
+		name, partValue, ok := strings.Cut(part, "=")
+		if !ok {
+			return nil, fmt.Errorf("malformed part %q", part)
+		}
+
+		switch strings.ToUpper(name) {
+		case "FREQ":
+			rule.freq = strings.ToUpper(partValue)
+			switch rule.freq {
+			case freqDaily, freqWeekly, freqMonthly, freqYearly:
+			default:
+				return nil, fmt.Errorf("unsupported FREQ %q", partValue)
+			}
+		case "INTERVAL":
+			interval, err := strconv.Atoi(partValue)
+			if err != nil || interval < 1 {
+				return nil, fmt.Errorf("invalid INTERVAL %q", partValue)
+			}
+			rule.interval = interval
+		case "COUNT":
+			count, err := strconv.Atoi(partValue)
+			if err != nil || count < 1 {
+				return nil, fmt.Errorf("invalid COUNT %q", partValue)
+			}
+			rule.count = count
+		case "UNTIL":
+			until, err := parseDateTime(partValue, false, loc, loc)
+			if err != nil {
+				return nil, fmt.Errorf("invalid UNTIL: %w", err)
+			}
+			rule.until = &until
+		case "BYDAY":
+			rule.byDay = make(map[time.Weekday]bool)
+			for _, code := range strings.Split(strings.ToUpper(partValue), ",") {
+				weekday, ok := weekdays[code]
+				if !ok {
+					return nil, fmt.Errorf("unsupported BYDAY %q: only plain weekdays are supported", code)
+				}
+				rule.byDay[weekday] = true
+			}
+		case "WKST":
+			weekday, ok := weekdays[strings.ToUpper(partValue)]
+			if !ok {
+				return nil, fmt.Errorf("invalid WKST %q", partValue)
+			}
+			rule.weekStart = weekday
+		default:
+			return nil, fmt.Errorf("unsupported part %s", strings.ToUpper(name))
+		}
+	}
+
+	if rule.freq == "" {
+		return nil, errors.New("FREQ is required")
+	}
+	if rule.count > 0 && rule.until != nil {
+		return nil, errors.New("COUNT and UNTIL are mutually exclusive")
+	}
+	if rule.byDay != nil && (rule.freq == freqMonthly || rule.freq == freqYearly) {
+		return nil, fmt.Errorf("BYDAY is not supported with FREQ=%s", rule.freq)
+	}
+	return rule, nil
+}
+
+// expand returns the occurrence start times of the rule from dtstart until COUNT, UNTIL or limit
+// DTSTART is always the first occurrence, as RFC 5545 requires
+func (r *recurrenceRule) expand(dtstart, limit time.Time) ([]time.Time, error) {
+	starts := []time.Time{dtstart}
+	for step := 0; ; step++ {
+		if step == maxOccurrences {
+			return nil, fmt.Errorf("RRULE produces more than %d occurrences", maxOccurrences)
+		}
+
+		candidates := r.candidates(dtstart, step)
+		if len(candidates) == 0 {
+			continue
+		}
+		for _, start := range candidates {
+			if !start.After(dtstart) {
+				continue
+			}
+			if r.count > 0 && len(starts) >= r.count {
+				return starts, nil
+			}
+			if !r.beforeUntil(start) || !start.Before(limit) {
+				return starts, nil
+			}
+			starts = append(starts, start)
+		}
+	}
+}
+
+// candidates returns the starts of the step-th period of the rule in chronological order
+// Candidates that do not exist (the 31st of a short month, 29 February) are skipped
+func (r *recurrenceRule) candidates(dtstart time.Time, step int) []time.Time {
+	n := step * r.interval
+	year, month, day := dtstart.Date()
+	hour, minute, second := dtstart.Clock()
+	loc := dtstart.Location()
+
+	switch r.freq {
+	case freqDaily:
+		start := dtstart.AddDate(0, 0, n)
+		if r.byDay != nil && !r.byDay[start.Weekday()] {
+			return nil
+		}
+		return []time.Time{start}
+	case freqWeekly:
+		offset := (int(dtstart.Weekday()) - int(r.weekStart) + 7) % 7
+		weekStart := dtstart.AddDate(0, 0, 7*n-offset)
+		var starts []time.Time
+		for i := 0; i < 7; i++ {
+			start := weekStart.AddDate(0, 0, i)
+			if (r.byDay == nil && start.Weekday() == dtstart.Weekday()) || r.byDay[start.Weekday()] {
+				starts = append(starts, start)
+			}
+		}
+		return starts
+	case freqMonthly:
+		start := time.Date(year, month+time.Month(n), day, hour, minute, second, 0, loc)
+		if start.Day() != day {
+			return nil
+		}
+		return []time.Time{start}
+	default:
+		start := time.Date(year+n, month, day, hour, minute, second, 0, loc)
+		if start.Day() != day {
+			return nil
+		}
+		return []time.Time{start}
+	}
+}
+
+// beforeUntil reports whether start is not later than UNTIL
+// A date UNTIL includes the whole day in the event's location
+func (r *recurrenceRule) beforeUntil(start time.Time) bool {
+	if r.until == nil {
+		return true
+	}
+	if r.until.allDay {
+		year, month, day := r.until.t.Date()
+		return start.Before(time.Date(year, month, day+1, 0, 0, 0, 0, start.Location()))
+	}
+	return !start.After(r.until.t)
+}
@@ -0,0 +1,21 @@
+// Package integration keeps pull requests on external code hosts in sync with the service
+package integration
+
+import (
+	"context"
+	"errors"
+
+	"avito-backend-trainee-assignment-autumn-2025/internal/domain/models"
+)
+
+// ErrRejected is wrapped by CodeHostClient errors that retrying won't fix
+// (unknown PR, reviewer who is not a collaborator, ...)
+var ErrRejected = errors.New("rejected by code host")
+
+// CodeHostClient changes reviewers of pull requests on a code host
+type CodeHostClient interface {
+	// RequestReviewers asks the users with the given logins to review the PR
+	RequestReviewers(ctx context.Context, pr models.CodeHostPR, logins []string) error
+	// RemoveReviewers withdraws review requests from the users with the given logins
+	RemoveReviewers(ctx context.Context, pr models.CodeHostPR, logins []string) error
+}
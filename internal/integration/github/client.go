@@ -0,0 +1,99 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"avito-backend-trainee-assignment-autumn-2025/internal/domain/models"
+	"avito-backend-trainee-assignment-autumn-2025/internal/integration"
+)
+
+// DefaultAPIURL is the base URL of the public GitHub REST API
+const DefaultAPIURL = "https://api.github.com"
+
+// apiVersion is the REST API version the client is written against
+const apiVersion = "2022-11-28"
+
+// maxErrorBodySize limits how much of a failed response ends up in the error
+const maxErrorBodySize = 256
+
+// Client changes pull request reviewers through the GitHub REST API
+type Client struct {
+	baseURL string
+	token   string
+	client  *http.Client
+}
+
+var _ integration.CodeHostClient = (*Client)(nil)
+
+// NewClient creates a GitHub REST API client authenticated with token
+func NewClient(baseURL, token string, timeout time.Duration) *Client {
+	return &Client{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		token:   token,
+		client:  &http.Client{Timeout: timeout},
+	}
+}
+
+// reviewersRequest is the body of the requested_reviewers endpoints
+type reviewersRequest struct {
+	Reviewers []string `json:"reviewers"`
+}
+
+// RequestReviewers requests reviews from logins on the pull request
+func (c *Client) RequestReviewers(ctx context.Context, pr models.CodeHostPR, logins []string) error {
+	return c.do(ctx, http.MethodPost, pr, logins)
+}
+
+// RemoveReviewers removes review requests for logins from the pull request
+func (c *Client) RemoveReviewers(ctx context.Context, pr models.CodeHostPR, logins []string) error {
+	return c.do(ctx, http.MethodDelete, pr, logins)
+}
+
+// do calls the requested_reviewers endpoint of the pull request
+// 400, 404 and 422 mean the request will never succeed and wrap integration.ErrRejected
+func (c *Client) do(ctx context.Context, method string, pr models.CodeHostPR, logins []string) error {
+	body, err := json.Marshal(reviewersRequest{Reviewers: logins})
+	if err != nil {
+		return err
+	}
+
+	url := fmt.Sprintf("%s/repos/%s/pulls/%d/requested_reviewers", c.baseURL, pr.Repository, pr.Number)
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("invalid request: %w", err)
+	}
+
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "pr-reviewer-service")
+	req.Header.Set("X-GitHub-Api-Version", apiVersion)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		// Drain the body so the connection can be reused
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+
+	msg, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+	err = fmt.Errorf("unexpected status %d: %s", resp.StatusCode, bytes.TrimSpace(msg))
+	switch resp.StatusCode {
+	case http.StatusBadRequest, http.StatusNotFound, http.StatusUnprocessableEntity:
+		return fmt.Errorf("%w: %w", integration.ErrRejected, err)
+	default:
+		return err
+	}
+}
@@ -0,0 +1,98 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"avito-backend-trainee-assignment-autumn-2025/internal/domain/models"
+	"avito-backend-trainee-assignment-autumn-2025/internal/integration"
+)
+
+func TestClientReviewers(t *testing.T) {
+	pr := models.CodeHostPR{Provider: models.CodeHostGitHub, Repository: "octo-org/api", Number: 42}
+
+	tests := []struct {
+		name   string
+		call   func(c *Client) error
+		method string
+	}{
+		{
+			name:   "request",
+			call:   func(c *Client) error { return c.RequestReviewers(context.Background(), pr, []string{"alice", "bob"}) },
+			method: http.MethodPost,
+		},
+		{
+			name:   "remove",
+			call:   func(c *Client) error { return c.RemoveReviewers(context.Background(), pr, []string{"alice", "bob"}) },
+			method: http.MethodDelete,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != tt.method {
+					t.Errorf("method = %s, want %s", r.Method, tt.method)
+				}
+				if r.URL.Path != "/repos/octo-org/api/pulls/42/requested_reviewers" {
+					t.Errorf("path = %s", r.URL.Path)
+				}
+				if got := r.Header.Get("Authorization"); got != "Bearer s3cret" {
+					t.Errorf("Authorization = %q", got)
+				}
+				if got := r.Header.Get("Accept"); got != "application/vnd.github+json" {
+					t.Errorf("Accept = %q", got)
+				}
+				body, _ := io.ReadAll(r.Body)
+				if string(body) != `{"reviewers":["alice","bob"]}` {
+					t.Errorf("body = %s", body)
+				}
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer server.Close()
+
+			// A trailing slash in the configured URL must not break the path
+			if err := tt.call(NewClient(server.URL+"/", "s3cret", 5*time.Second)); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestClientErrors(t *testing.T) {
+	pr := models.CodeHostPR{Provider: models.CodeHostGitHub, Repository: "octo-org/api", Number: 42}
+
+	tests := []struct {
+		name     string
+		status   int
+		rejected bool
+	}{
+		{name: "not a collaborator", status: http.StatusUnprocessableEntity, rejected: true},
+		{name: "unknown pull request", status: http.StatusNotFound, rejected: true},
+		{name: "rate limited", status: http.StatusForbidden, rejected: false},
+		{name: "server error", status: http.StatusBadGateway, rejected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(`{"message":"nope"}`))
+			}))
+			defer server.Close()
+
+			err := NewClient(server.URL, "s3cret", 5*time.Second).RequestReviewers(context.Background(), pr, []string{"alice"})
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			if errors.Is(err, integration.ErrRejected) != tt.rejected {
+				t.Errorf("errors.Is(err, ErrRejected) = %v, want %v (err: %v)", !tt.rejected, tt.rejected, err)
+			}
+		})
+	}
+}
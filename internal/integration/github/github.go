@@ -0,0 +1,122 @@
+// Package github parses GitHub webhook deliveries and updates pull request reviewers through the REST API
+package github
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"avito-backend-trainee-assignment-autumn-2025/internal/domain/models"
+	pkgerrors "avito-backend-trainee-assignment-autumn-2025/pkg/errors"
+)
+
+// Headers set by GitHub on every webhook delivery
+const (
+	HeaderEvent     = "X-GitHub-Event"
+	HeaderDelivery  = "X-GitHub-Delivery"
+	HeaderSignature = "X-Hub-Signature-256"
+)
+
+// eventPullRequest is the X-GitHub-Event value of pull request deliveries
+const eventPullRequest = "pull_request"
+
+// signaturePrefix precedes the hex HMAC in X-Hub-Signature-256
+const signaturePrefix = "sha256="
+
+// pullRequestPayload is the subset of the pull_request event payload used by the service
+type pullRequestPayload struct {
+	Action      string `json:"action"`
+	Number      int    `json:"number"`
+	PullRequest *struct {
+		Number int    `json:"number"`
+		Title  string `json:"title"`
+		Merged bool   `json:"merged"`
+		User   struct {
+			Login string `json:"login"`
+		} `json:"user"`
+	} `json:"pull_request"`
+	Repository struct {
+		FullName string `json:"full_name"`
+	} `json:"repository"`
+}
+
+// VerifySignature checks the X-Hub-Signature-256 header against the HMAC-SHA256 of body
+func VerifySignature(secret string, body []byte, signature string) error {
+	if !strings.HasPrefix(signature, signaturePrefix) {
+		return pkgerrors.ErrInvalidSignature
+	}
+
+	got, err := hex.DecodeString(strings.TrimPrefix(signature, signaturePrefix))
+	if err != nil {
+		return pkgerrors.ErrInvalidSignature
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	if !hmac.Equal(got, mac.Sum(nil)) {
+		return pkgerrors.ErrInvalidSignature
+	}
+	return nil
+}
+
+// Sign returns the X-Hub-Signature-256 value GitHub sends for body
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// ParseEvent converts a delivery into a pull request event
+// It returns nil for events and actions the service does not handle (ping, labeled, synchronize...)
+func ParseEvent(eventName string, body []byte) (*models.CodeHostPREvent, error) {
+	if eventName != eventPullRequest {
+		return nil, nil
+	}
+
+	var payload pullRequestPayload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, pkgerrors.NewBadRequestError("invalid pull_request payload", err)
+	}
+
+	var action models.PRAction
+	switch payload.Action {
+	case "opened":
+		action = models.PRActionOpened
+	case "reopened":
+		action = models.PRActionReopened
+	case "closed":
+		action = models.PRActionClosed
+		if payload.PullRequest != nil && payload.PullRequest.Merged {
+			action = models.PRActionMerged
+		}
+	default:
+		return nil, nil
+	}
+
+	if payload.PullRequest == nil {
+		return nil, pkgerrors.NewRequiredFieldError("pull_request")
+	}
+	if payload.Repository.FullName == "" {
+		return nil, pkgerrors.NewRequiredFieldError("repository.full_name")
+	}
+
+	number := payload.PullRequest.Number
+	if number == 0 {
+		number = payload.Number
+	}
+	if number <= 0 {
+		return nil, pkgerrors.NewValidationError("pull_request.number", fmt.Sprintf("must be positive, got %d", number))
+	}
+
+	return &models.CodeHostPREvent{
+		Provider:    models.CodeHostGitHub,
+		Action:      action,
+		Repository:  payload.Repository.FullName,
+		Number:      number,
+		Title:       payload.PullRequest.Title,
+		AuthorLogin: payload.PullRequest.User.Login,
+	}, nil
+}
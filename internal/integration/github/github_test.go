@@ -0,0 +1,122 @@
+package github
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"avito-backend-trainee-assignment-autumn-2025/internal/domain/models"
+	pkgerrors "avito-backend-trainee-assignment-autumn-2025/pkg/errors"
+)
+
+// loadFixture reads a recorded GitHub delivery from testdata
+func loadFixture(t *testing.T, name string) []byte {
+	t.Helper()
+	body, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatalf("Failed to read fixture %s: %v", name, err)
+	}
+	return body
+}
+
+func TestParseEvent(t *testing.T) {
+	tests := []struct {
+		name           string
+		eventName      string
+		fixture        string
+		expectedAction models.PRAction
+		ignored        bool
+	}{
+		{name: "Opened", eventName: "pull_request", fixture: "pull_request_opened.json", expectedAction: models.PRActionOpened},
+		{name: "Reopened", eventName: "pull_request", fixture: "pull_request_reopened.json", expectedAction: models.PRActionReopened},
+		{name: "Closed and merged", eventName: "pull_request", fixture: "pull_request_closed_merged.json", expectedAction: models.PRActionMerged},
+		{name: "Closed without merge", eventName: "pull_request", fixture: "pull_request_closed.json", expectedAction: models.PRActionClosed},
+		{name: "Synchronize is ignored", eventName: "pull_request", fixture: "pull_request_synchronize.json", ignored: true},
+		{name: "Ping is ignored", eventName: "ping", fixture: "ping.json", ignored: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event, err := ParseEvent(tt.eventName, loadFixture(t, tt.fixture))
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if tt.ignored {
+				if event != nil {
+					t.Errorf("Expected event to be ignored, got %+v", event)
+				}
+				return
+			}
+
+			if event == nil {
+				t.Fatal("Expected an event, got nil")
+			}
+			if event.Action != tt.expectedAction {
+				t.Errorf("Expected action %s, got %s", tt.expectedAction, event.Action)
+			}
+			if event.Provider != models.CodeHostGitHub {
+				t.Errorf("Expected provider github, got %s", event.Provider)
+			}
+			if event.AuthorLogin != "Octo-Dev" {
+				t.Errorf("Expected author Octo-Dev, got %s", event.AuthorLogin)
+			}
+			if event.Title != "Add reviewer load balancing" {
+				t.Errorf("Unexpected title %q", event.Title)
+			}
+			if id := event.PullRequestID(); id != "github:octo-org/reviewer-api#42" {
+				t.Errorf("Unexpected pull request ID %s", id)
+			}
+		})
+	}
+}
+
+func TestParseEvent_Malformed(t *testing.T) {
+	_, err := ParseEvent("pull_request", []byte(`{"action":`))
+	var badRequest *pkgerrors.BadRequestError
+	if !errors.As(err, &badRequest) {
+		t.Errorf("Expected BadRequestError, got %v", err)
+	}
+
+	_, err = ParseEvent("pull_request", []byte(`{"action":"opened","repository":{"full_name":"octo-org/api"}}`))
+	var validation *pkgerrors.ValidationError
+	if !errors.As(err, &validation) || validation.Field != "pull_request" {
+		t.Errorf("Expected validation error on pull_request, got %v", err)
+	}
+}
+
+func TestVerifySignature(t *testing.T) {
+	body := loadFixture(t, "pull_request_opened.json")
+	const secret = "It's a Secret to Everybody"
+
+	if err := VerifySignature(secret, body, Sign(secret, body)); err != nil {
+		t.Errorf("Expected valid signature, got %v", err)
+	}
+
+	// Example from the GitHub documentation on validating webhook deliveries
+	const documented = "sha256=757107ea0eb2509fc211221cce984b8a37570b6d7586c22c46f4379c8b043e17"
+	if err := VerifySignature(secret, []byte("Hello, World!"), documented); err != nil {
+		t.Errorf("Expected documented signature to be valid, got %v", err)
+	}
+
+	invalid := []struct {
+		name      string
+		secret    string
+		body      []byte
+		signature string
+	}{
+		{name: "Missing header", secret: secret, body: body, signature: ""},
+		{name: "SHA-1 header", secret: secret, body: body, signature: "sha1=" + Sign(secret, body)[len("sha256="):]},
+		{name: "Not hex", secret: secret, body: body, signature: "sha256=zz"},
+		{name: "Wrong secret", secret: "other", body: body, signature: Sign(secret, body)},
+		{name: "Tampered body", secret: secret, body: append([]byte(" "), body...), signature: Sign(secret, body)},
+	}
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := VerifySignature(tt.secret, tt.body, tt.signature); !errors.Is(err, pkgerrors.ErrInvalidSignature) {
+				t.Errorf("Expected ErrInvalidSignature, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+// Package gitlab parses GitLab webhook deliveries into code host events
+package gitlab
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+
+	"avito-backend-trainee-assignment-autumn-2025/internal/domain/models"
+	pkgerrors "avito-backend-trainee-assignment-autumn-2025/pkg/errors"
+)
+
+// Headers set by GitLab on every webhook delivery
+const (
+	HeaderEvent     = "X-Gitlab-Event"
+	HeaderEventUUID = "X-Gitlab-Event-UUID"
+	HeaderToken     = "X-Gitlab-Token"
+)
+
+// eventMergeRequest is the X-Gitlab-Event value of merge request deliveries
+const eventMergeRequest = "Merge Request Hook"
+
+// mergeRequestPayload is the subset of the merge request event payload used by the service
+type mergeRequestPayload struct {
+	ObjectKind string `json:"object_kind"`
+	// User is the user who triggered the event
+	User struct {
+		Username string `json:"username"`
+	} `json:"user"`
+	Project struct {
+		PathWithNamespace string `json:"path_with_namespace"`
+	} `json:"project"`
+	ObjectAttributes *struct {
+		IID    int    `json:"iid"`
+		Title  string `json:"title"`
+		Action string `json:"action"`
+	} `json:"object_attributes"`
+}
+
+// VerifyToken checks the X-Gitlab-Token header against the configured secret token
+func VerifyToken(secret, token string) error {
+	if subtle.ConstantTimeCompare([]byte(secret), []byte(token)) != 1 {
+		return pkgerrors.ErrInvalidSignature
+	}
+	return nil
+}
+
+// ParseEvent converts a delivery into a pull request event
+// It returns nil for events and actions the service does not handle (push, update, approved...)
+//
+// GitLab payloads carry only the numeric ID of the MR author, so the author login is taken
+// from the user who triggered the event: for open this is always the author
+func ParseEvent(eventName string, body []byte) (*models.CodeHostPREvent, error) {
+	if eventName != eventMergeRequest {
+		return nil, nil
+	}
+
+	var payload mergeRequestPayload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, pkgerrors.NewBadRequestError("invalid merge request payload", err)
+	}
+	if payload.ObjectAttributes == nil {
+		return nil, pkgerrors.NewRequiredFieldError("object_attributes")
+	}
+
+	var action models.PRAction
+	switch payload.ObjectAttributes.Action {
+	case "open":
+		action = models.PRActionOpened
+	case "reopen":
+		action = models.PRActionReopened
+	case "merge":
+		action = models.PRActionMerged
+	case "close":
+		action = models.PRActionClosed
+	default:
+		return nil, nil
+	}
+
+	if payload.Project.PathWithNamespace == "" {
+		return nil, pkgerrors.NewRequiredFieldError("project.path_with_namespace")
+	}
+	if payload.ObjectAttributes.IID <= 0 {
+		return nil, pkgerrors.NewValidationError("object_attributes.iid", fmt.Sprintf("must be positive, got %d", payload.ObjectAttributes.IID))
+	}
+
+	return &models.CodeHostPREvent{
+		Provider:    models.CodeHostGitLab,
+		Action:      action,
+		Repository:  payload.Project.PathWithNamespace,
+		Number:      payload.ObjectAttributes.IID,
+		Title:       payload.ObjectAttributes.Title,
+		AuthorLogin: payload.User.Username,
+	}, nil
+}
@@ -0,0 +1,99 @@
+package gitlab
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"avito-backend-trainee-assignment-autumn-2025/internal/domain/models"
+	pkgerrors "avito-backend-trainee-assignment-autumn-2025/pkg/errors"
+)
+
+// loadFixture reads a stored GitLab delivery from testdata
+func loadFixture(t *testing.T, name string) []byte {
+	t.Helper()
+	body, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatalf("Failed to read fixture %s: %v", name, err)
+	}
+	return body
+}
+
+func TestParseEvent(t *testing.T) {
+	tests := []struct {
+		name           string
+		eventName      string
+		fixture        string
+		expectedAction models.PRAction
+		expectedLogin  string
+		ignored        bool
+	}{
+		{name: "Open", eventName: "Merge Request Hook", fixture: "merge_request_open.json", expectedAction: models.PRActionOpened, expectedLogin: "Dana.Petrova"},
+		{name: "Reopen", eventName: "Merge Request Hook", fixture: "merge_request_reopen.json", expectedAction: models.PRActionReopened, expectedLogin: "Dana.Petrova"},
+		{name: "Merge", eventName: "Merge Request Hook", fixture: "merge_request_merge.json", expectedAction: models.PRActionMerged, expectedLogin: "ismirnov"},
+		{name: "Close", eventName: "Merge Request Hook", fixture: "merge_request_close.json", expectedAction: models.PRActionClosed, expectedLogin: "ismirnov"},
+		{name: "Update is ignored", eventName: "Merge Request Hook", fixture: "merge_request_update.json", ignored: true},
+		{name: "Push is ignored", eventName: "Push Hook", fixture: "push.json", ignored: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event, err := ParseEvent(tt.eventName, loadFixture(t, tt.fixture))
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if tt.ignored {
+				if event != nil {
+					t.Errorf("Expected event to be ignored, got %+v", event)
+				}
+				return
+			}
+
+			if event == nil {
+				t.Fatal("Expected an event, got nil")
+			}
+			if event.Action != tt.expectedAction {
+				t.Errorf("Expected action %s, got %s", tt.expectedAction, event.Action)
+			}
+			if event.Provider != models.CodeHostGitLab {
+				t.Errorf("Expected provider gitlab, got %s", event.Provider)
+			}
+			if event.AuthorLogin != tt.expectedLogin {
+				t.Errorf("Expected login %s, got %s", tt.expectedLogin, event.AuthorLogin)
+			}
+			if event.Title != "Extract reviewer selector" {
+				t.Errorf("Unexpected title %q", event.Title)
+			}
+			if id := event.PullRequestID(); id != "gitlab:platform/reviewer-api!7" {
+				t.Errorf("Unexpected pull request ID %s", id)
+			}
+		})
+	}
+}
+
+func TestParseEvent_Malformed(t *testing.T) {
+	_, err := ParseEvent("Merge Request Hook", []byte(`{"object_kind":`))
+	var badRequest *pkgerrors.BadRequestError
+	if !errors.As(err, &badRequest) {
+		t.Errorf("Expected BadRequestError, got %v", err)
+	}
+
+	_, err = ParseEvent("Merge Request Hook", []byte(`{"object_kind":"merge_request","project":{"path_with_namespace":"platform/api"}}`))
+	var validation *pkgerrors.ValidationError
+	if !errors.As(err, &validation) || validation.Field != "object_attributes" {
+		t.Errorf("Expected validation error on object_attributes, got %v", err)
+	}
+}
+
+func TestVerifyToken(t *testing.T) {
+	if err := VerifyToken("s3cr3t", "s3cr3t"); err != nil {
+		t.Errorf("Expected valid token, got %v", err)
+	}
+	for _, token := range []string{"", "s3cr3", "s3cr3t ", "S3CR3T"} {
+		if err := VerifyToken("s3cr3t", token); !errors.Is(err, pkgerrors.ErrInvalidSignature) {
+			t.Errorf("Token %q: expected ErrInvalidSignature, got %v", token, err)
+		}
+	}
+}
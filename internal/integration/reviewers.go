@@ -0,0 +1,265 @@
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"avito-backend-trainee-assignment-autumn-2025/internal/domain/models"
+	"avito-backend-trainee-assignment-autumn-2025/internal/repository"
+	pkgerrors "avito-backend-trainee-assignment-autumn-2025/pkg/errors"
+	"avito-backend-trainee-assignment-autumn-2025/pkg/logger"
+)
+
+// Config controls reviewer sync timing
+type Config struct {
+	// MaxAttempts is the number of attempts before a sync is given up
+	MaxAttempts int
+	// InitialBackoff is the delay before the first retry; it doubles on every further retry
+	InitialBackoff time.Duration
+	// MaxBackoff caps the retry delay
+	MaxBackoff time.Duration
+	// PollInterval is how often due syncs are looked up
+	PollInterval time.Duration
+	// BatchSize is the maximum number of syncs attempted per poll
+	BatchSize int
+	// Lease is how long claimed syncs are reserved for this syncer; other replicas
+	// skip them until it ends, and no new attempt of the batch is started after it
+	Lease time.Duration
+}
+
+// defaultLease is used when Config.Lease is not set
+const defaultLease = 5 * time.Minute
+
+// ReviewerSyncer mirrors reviewer assignments to the code hosts the PRs came from
+// As an outbox sink it turns reviewer events into pending syncs; Run performs them
+// outside of any transaction, retrying with backoff. The syncs of a PR are performed one
+// at a time in event order, so a retried request never lands after a later removal
+type ReviewerSyncer struct {
+	syncRepo    repository.ReviewerSyncRepository
+	accountRepo repository.CodeHostAccountRepository
+	clients     map[models.CodeHost]CodeHostClient
+	config      Config
+
+	// now is the clock used for scheduling; replaced in tests
+	now func() time.Time
+}
+
+// NewReviewerSyncer creates a new reviewer syncer for the code hosts in clients
+func NewReviewerSyncer(
+	syncRepo repository.ReviewerSyncRepository,
+	accountRepo repository.CodeHostAccountRepository,
+	clients map[models.CodeHost]CodeHostClient,
+	config Config,
+) *ReviewerSyncer {
+	if config.BatchSize <= 0 {
+		config.BatchSize = 100
+	}
+	if config.Lease <= 0 {
+		config.Lease = defaultLease
+	}
+
+	return &ReviewerSyncer{
+		syncRepo:    syncRepo,
+		accountRepo: accountRepo,
+		clients:     clients,
+		config:      config,
+		now:         time.Now,
+	}
+}
+
+// Name identifies the syncer as an outbox sink
+func (s *ReviewerSyncer) Name() string {
+	return "codehost"
+}
+
+// reviewerEvent is an outbox payload of reviewer.assigned or reviewer.unassigned
+type reviewerEvent struct {
+	Data models.ReviewerEventData `json:"data"`
+}
+
+// Handle schedules a sync for a reviewer event on a PR that came from a code host
+// Other events, manually created PRs and reviewers without a linked login are skipped
+func (s *ReviewerSyncer) Handle(ctx context.Context, msg *models.OutboxMessage) error {
+	var action models.ReviewerSyncAction
+	switch msg.EventType {
+	case models.EventReviewerAssigned:
+		action = models.ReviewerSyncRequest
+	case models.EventReviewerUnassigned:
+		action = models.ReviewerSyncRemove
+	default:
+		return nil
+	}
+
+	var event reviewerEvent
+	if err := json.Unmarshal(msg.Payload, &event); err != nil {
+		// A malformed message will never decode; retrying it would block nothing but the log
+		logger.Error("Skipping outbox message %s: invalid payload: %v", msg.ID, err)
+		return nil
+	}
+
+	pr, ok := models.ParseCodeHostPR(event.Data.PullRequestID)
+	if !ok {
+		return nil
+	}
+	if _, ok := s.clients[pr.Provider]; !ok {
+		logger.Debug("Skipping reviewer sync for %s: no %s client configured", pr.ID(), pr.Provider)
+		return nil
+	}
+
+	login, err := s.accountRepo.GetLogin(ctx, pr.Provider, event.Data.ReviewerID)
+	if errors.Is(err, pkgerrors.ErrAccountNotLinked) {
+		logger.Warn("Skipping reviewer sync for %s: user %s has no linked %s account", pr.ID(), event.Data.ReviewerID, pr.Provider)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get login of reviewer %s: %w", event.Data.ReviewerID, err)
+	}
+
+	now := s.now()
+	sync := &models.ReviewerSync{
+		ID:            models.NewID("rsync"),
+		EventID:       msg.ID,
+		PullRequestID: pr.ID(),
+		Action:        action,
+		Login:         login,
+		Status:        models.DeliveryStatusPending,
+		NextAttemptAt: now,
+		CreatedAt:     now,
+	}
+	if err := s.syncRepo.Create(ctx, sync); err != nil {
+		return fmt.Errorf("failed to schedule reviewer sync: %w", err)
+	}
+	return nil
+}
+
+// Run performs due syncs until ctx is cancelled
+func (s *ReviewerSyncer) Run(ctx context.Context) {
+	logger.Info("Reviewer syncer started (code hosts: %d)", len(s.clients))
+
+	ticker := time.NewTicker(s.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		s.processDue(ctx)
+
+		select {
+		case <-ctx.Done():
+			logger.Info("Reviewer syncer stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// processDue performs due syncs and returns how many were claimed
+// A PR's next sync can only be claimed once the previous one is done, so claiming is repeated
+// until a round claims nothing, the batch is full or a lease runs out
+func (s *ReviewerSyncer) processDue(ctx context.Context) int {
+	total := 0
+	for total < s.config.BatchSize && ctx.Err() == nil {
+		claimed, finished := s.processBatch(ctx, s.config.BatchSize-total)
+		total += claimed
+		if claimed == 0 || !finished {
+			break
+		}
+	}
+	return total
+}
+
+// processBatch claims up to limit due syncs and attempts each once
+// It returns how many were claimed and whether all of them were attempted
+func (s *ReviewerSyncer) processBatch(ctx context.Context, limit int) (int, bool) {
+	now := s.now()
+	lockedUntil := now.Add(s.config.Lease)
+	syncs, err := s.syncRepo.ClaimDue(ctx, now, lockedUntil, limit)
+	if err != nil {
+		if ctx.Err() == nil {
+			logger.Error("Failed to claim due reviewer syncs: %v", err)
+		}
+		return 0, false
+	}
+
+	for i := range syncs {
+		if ctx.Err() != nil {
+			return len(syncs), false
+		}
+		// Another replica may claim the rest once the lease is over
+		if !s.now().Before(lockedUntil) {
+			logger.Warn("Reviewer sync lease expired, %d sync(s) left to other syncers", len(syncs)-i)
+			return len(syncs), false
+		}
+		s.attempt(ctx, &syncs[i])
+	}
+
+	return len(syncs), true
+}
+
+// attempt performs a sync once and records the outcome
+func (s *ReviewerSyncer) attempt(ctx context.Context, sync *models.ReviewerSync) {
+	err := s.perform(ctx, sync)
+	if ctx.Err() != nil {
+		// Shutting down: the sync stays pending and is claimed again once the lease ends
+		return
+	}
+
+	now := s.now()
+	sync.Attempts++
+
+	switch {
+	case err == nil:
+		sync.Status = models.DeliveryStatusDelivered
+		sync.LastError = ""
+		sync.CompletedAt = &now
+		logger.Info("Synced reviewer %s (%s) on %s", sync.Login, sync.Action, sync.PullRequestID)
+
+	case errors.Is(err, ErrRejected) || sync.Attempts >= s.config.MaxAttempts:
+		sync.Status = models.DeliveryStatusFailed
+		sync.LastError = err.Error()
+		logger.Warn("Reviewer sync %s (%s %s on %s) failed after %d attempt(s): %v",
+			sync.ID, sync.Action, sync.Login, sync.PullRequestID, sync.Attempts, err)
+
+	default:
+		delay := s.backoff(sync.Attempts)
+		sync.LastError = err.Error()
+		sync.NextAttemptAt = now.Add(delay)
+		logger.Warn("Reviewer sync %s (%s %s on %s) failed (attempt %d/%d), retrying in %v: %v",
+			sync.ID, sync.Action, sync.Login, sync.PullRequestID, sync.Attempts, s.config.MaxAttempts, delay, err)
+	}
+
+	if err := s.syncRepo.Update(ctx, sync); err != nil {
+		logger.Error("Failed to save reviewer sync %s: %v", sync.ID, err)
+	}
+}
+
+// perform calls the code host client for a sync
+func (s *ReviewerSyncer) perform(ctx context.Context, sync *models.ReviewerSync) error {
+	pr, ok := models.ParseCodeHostPR(sync.PullRequestID)
+	if !ok {
+		return fmt.Errorf("%w: %s is not a code host pull request", ErrRejected, sync.PullRequestID)
+	}
+	client, ok := s.clients[pr.Provider]
+	if !ok {
+		return fmt.Errorf("no %s client configured", pr.Provider)
+	}
+
+	if sync.Action == models.ReviewerSyncRemove {
+		return client.RemoveReviewers(ctx, pr, []string{sync.Login})
+	}
+	return client.RequestReviewers(ctx, pr, []string{sync.Login})
+}
+
+// backoff returns the delay after the given number of failed attempts:
+// InitialBackoff, then doubled each time, capped at MaxBackoff
+func (s *ReviewerSyncer) backoff(attempts int) time.Duration {
+	delay := s.config.InitialBackoff
+	for i := 1; i < attempts && delay < s.config.MaxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > s.config.MaxBackoff {
+		delay = s.config.MaxBackoff
+	}
+	return delay
+}
@@ -0,0 +1,383 @@
+package integration
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"avito-backend-trainee-assignment-autumn-2025/internal/domain/models"
+	"avito-backend-trainee-assignment-autumn-2025/internal/repository/memory"
+	"avito-backend-trainee-assignment-autumn-2025/internal/service"
+	"avito-backend-trainee-assignment-autumn-2025/pkg/dto/request"
+)
+
+const testPRID = "github:octo-org/api#42"
+
+// call is a reviewer change received by the fake code host
+type call struct {
+	action models.ReviewerSyncAction
+	pr     models.CodeHostPR
+	logins []string
+}
+
+// fakeCodeHost records calls and fails them with queued errors (nil once they run out)
+type fakeCodeHost struct {
+	mu    sync.Mutex
+	errs  []error
+	calls []call
+}
+
+func (h *fakeCodeHost) record(action models.ReviewerSyncAction, pr models.CodeHostPR, logins []string) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.calls = append(h.calls, call{action: action, pr: pr, logins: logins})
+
+	var err error
+	if len(h.errs) > 0 {
+		err, h.errs = h.errs[0], h.errs[1:]
+	}
+	return err
+}
+
+func (h *fakeCodeHost) RequestReviewers(_ context.Context, pr models.CodeHostPR, logins []string) error {
+	return h.record(models.ReviewerSyncRequest, pr, logins)
+}
+
+func (h *fakeCodeHost) RemoveReviewers(_ context.Context, pr models.CodeHostPR, logins []string) error {
+	return h.record(models.ReviewerSyncRemove, pr, logins)
+}
+
+func (h *fakeCodeHost) received() []call {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return append([]call(nil), h.calls...)
+}
+
+type fixture struct {
+	outboxRepo  *memory.OutboxRepository
+	accountRepo *memory.CodeHostAccountRepository
+	syncRepo    *memory.ReviewerSyncRepository
+	prService   *service.PRServiceImpl
+	syncer      *ReviewerSyncer
+	codeHost    *fakeCodeHost
+	clock       time.Time
+}
+
+// newFixture creates team "backend" with author u1 and reviewers u2..u4;
+// every user except the unlinked ones has a GitHub login "gh-<id>"
+func newFixture(t *testing.T, unlinked ...string) *fixture {
+	t.Helper()
+	ctx := context.Background()
+
+	store := memory.NewStore()
+	userRepo := memory.NewUserRepository(store)
+	teamRepo := memory.NewTeamRepository(store)
+	absenceRepo := memory.NewAbsenceRepository(store)
+	affinityRepo := memory.NewAffinityRepository(store)
+	txManager := memory.NewTransactionManager(store)
+
+	f := &fixture{
+		outboxRepo:  memory.NewOutboxRepository(store),
+		accountRepo: memory.NewCodeHostAccountRepository(store),
+		syncRepo:    memory.NewReviewerSyncRepository(store),
+		codeHost:    &fakeCodeHost{},
+		clock:       time.Date(2025, 11, 1, 12, 0, 0, 0, time.UTC),
+	}
+	f.prService = service.NewPRService(memory.NewPRRepository(store), userRepo, teamRepo, absenceRepo, affinityRepo, txManager, f.outboxRepo)
+	f.syncer = NewReviewerSyncer(f.syncRepo, f.accountRepo, map[models.CodeHost]CodeHostClient{
+		models.CodeHostGitHub: f.codeHost,
+	}, Config{
+		MaxAttempts:    3,
+		InitialBackoff: time.Second,
+		MaxBackoff:     time.Minute,
+		PollInterval:   time.Hour,
+	})
+	f.syncer.now = func() time.Time { return f.clock }
+
+	teamService := service.NewTeamService(teamRepo, userRepo, absenceRepo, txManager, f.outboxRepo)
+	members := make([]request.TeamMemberRequest, 0, 4)
+	for _, id := range []string{"u1", "u2", "u3", "u4"} {
+		members = append(members, request.TeamMemberRequest{UserID: id, Username: id, IsActive: true})
+	}
+	if _, err := teamService.CreateTeam(ctx, &request.CreateTeamRequest{TeamName: "backend", Members: members}); err != nil {
+		t.Fatalf("CreateTeam: %v", err)
+	}
+
+	skip := make(map[string]bool, len(unlinked))
+	for _, id := range unlinked {
+		skip[id] = true
+	}
+	for _, member := range members {
+		if skip[member.UserID] {
+			continue
+		}
+		err := f.accountRepo.Link(ctx, &models.CodeHostAccount{
+			Provider:  models.CodeHostGitHub,
+			Login:     "gh-" + member.UserID,
+			UserID:    member.UserID,
+			CreatedAt: f.clock,
+		})
+		if err != nil {
+			t.Fatalf("Link: %v", err)
+		}
+	}
+
+	return f
+}
+
+// publish hands pending outbox messages to the syncer, as the outbox dispatcher does
+func (f *fixture) publish(t *testing.T) {
+	t.Helper()
+	ctx := context.Background()
+
+	messages, err := f.outboxRepo.LockPending(ctx, time.Now(), 0)
+	if err != nil {
+		t.Fatalf("LockPending: %v", err)
+	}
+	for i := range messages {
+		if err := f.syncer.Handle(ctx, &messages[i]); err != nil {
+			t.Fatalf("Handle: %v", err)
+		}
+		if err := f.outboxRepo.MarkPublished(ctx, messages[i].ID, f.clock); err != nil {
+			t.Fatalf("MarkPublished: %v", err)
+		}
+	}
+}
+
+func (f *fixture) createPR(t *testing.T, id string) []string {
+	t.Helper()
+	resp, err := f.prService.CreatePR(context.Background(), &request.CreatePRRequest{
+		PullRequestID:   id,
+		PullRequestName: "Add feature",
+		AuthorID:        "u1",
+	})
+	if err != nil {
+		t.Fatalf("CreatePR: %v", err)
+	}
+	return resp.PR.AssignedReviewers
+}
+
+func (f *fixture) due(t *testing.T) []models.ReviewerSync {
+	t.Helper()
+	// A lease ending now leaves the syncs due for the syncer
+	syncs, err := f.syncRepo.ClaimDue(context.Background(), f.clock, f.clock, 100)
+	if err != nil {
+		t.Fatalf("ClaimDue: %v", err)
+	}
+	return syncs
+}
+
+func logins(ids []string) []string {
+	result := make([]string, len(ids))
+	for i, id := range ids {
+		result[i] = "gh-" + id
+	}
+	sort.Strings(result)
+	return result
+}
+
+func TestReviewersRequestedOnGitHub(t *testing.T) {
+	f := newFixture(t)
+	reviewers := f.createPR(t, testPRID)
+	f.publish(t)
+
+	if n := f.syncer.processDue(context.Background()); n != len(reviewers) {
+		t.Fatalf("processDue attempted %d syncs, want %d", n, len(reviewers))
+	}
+
+	var requested []string
+	for _, c := range f.codeHost.received() {
+		if c.action != models.ReviewerSyncRequest || c.pr.ID() != testPRID {
+			t.Errorf("unexpected call %s on %s", c.action, c.pr.ID())
+		}
+		requested = append(requested, c.logins...)
+	}
+	sort.Strings(requested)
+	if strings.Join(requested, ",") != strings.Join(logins(reviewers), ",") {
+		t.Errorf("requested %v, want %v", requested, logins(reviewers))
+	}
+	if due := f.due(t); len(due) != 0 {
+		t.Errorf("%d syncs still due after success", len(due))
+	}
+}
+
+func TestClaimedSyncsAreSkippedUntilLeaseEnds(t *testing.T) {
+	f := newFixture(t)
+	reviewers := f.createPR(t, testPRID)
+	f.publish(t)
+	ctx := context.Background()
+
+	// Another replica claimed the first sync of the PR and stopped before performing it;
+	// the later syncs wait for it
+	claimed, err := f.syncRepo.ClaimDue(ctx, f.clock, f.clock.Add(time.Minute), 100)
+	if err != nil || len(claimed) != 1 {
+		t.Fatalf("ClaimDue = %+v, %v; want only the first sync of the PR", claimed, err)
+	}
+
+	if n := f.syncer.processDue(ctx); n != 0 || len(f.codeHost.received()) != 0 {
+		t.Fatalf("processDue attempted %d syncs while they are leased", n)
+	}
+
+	f.clock = f.clock.Add(time.Minute)
+	if n := f.syncer.processDue(ctx); n != len(reviewers) {
+		t.Fatalf("processDue attempted %d syncs after the lease, want %d", n, len(reviewers))
+	}
+	if calls := f.codeHost.received(); len(calls) != len(reviewers) {
+		t.Fatalf("GitHub received %d calls, want %d", len(calls), len(reviewers))
+	}
+}
+
+func TestReassignRemovesOldAndRequestsNewReviewer(t *testing.T) {
+	f := newFixture(t)
+	reviewers := f.createPR(t, testPRID)
+	f.publish(t)
+	f.syncer.processDue(context.Background())
+	before := len(f.codeHost.received())
+
+	resp, err := f.prService.ReassignReviewer(context.Background(), &request.ReassignReviewerRequest{
+		PullRequestID: testPRID,
+		OldUserID:     reviewers[0],
+	})
+	if err != nil {
+		t.Fatalf("ReassignReviewer: %v", err)
+	}
+	f.publish(t)
+	f.syncer.processDue(context.Background())
+
+	calls := f.codeHost.received()[before:]
+	got := map[models.ReviewerSyncAction][]string{}
+	for _, c := range calls {
+		got[c.action] = append(got[c.action], c.logins...)
+	}
+	if want := []string{"gh-" + reviewers[0]}; strings.Join(got[models.ReviewerSyncRemove], ",") != strings.Join(want, ",") {
+		t.Errorf("removed %v, want %v", got[models.ReviewerSyncRemove], want)
+	}
+	if want := []string{"gh-" + resp.ReplacedBy}; strings.Join(got[models.ReviewerSyncRequest], ",") != strings.Join(want, ",") {
+		t.Errorf("requested %v, want %v", got[models.ReviewerSyncRequest], want)
+	}
+}
+
+func TestSyncsOfAPRRunInEventOrder(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+	reviewers := f.createPR(t, testPRID)
+	f.publish(t)
+
+	// The first request fails, and the reviewer is replaced before it is retried
+	f.codeHost.errs = []error{errors.New("unexpected status 502")}
+	f.syncer.processDue(ctx)
+	failed := f.codeHost.received()[0].logins[0]
+	var old string
+	for _, id := range reviewers {
+		if "gh-"+id == failed {
+			old = id
+		}
+	}
+	resp, err := f.prService.ReassignReviewer(ctx, &request.ReassignReviewerRequest{PullRequestID: testPRID, OldUserID: old})
+	if err != nil {
+		t.Fatalf("ReassignReviewer: %v", err)
+	}
+	f.publish(t)
+
+	if n := f.syncer.processDue(ctx); n != 0 {
+		t.Fatalf("processDue attempted %d syncs while the first one waits for its retry", n)
+	}
+
+	f.clock = f.clock.Add(time.Second)
+	f.syncer.processDue(ctx)
+
+	var got []string
+	for _, c := range f.codeHost.received() {
+		got = append(got, string(c.action)+" "+c.logins[0])
+	}
+	// The retried request still comes before the removal that followed it
+	var retried, removed, requested int
+	for i, c := range got {
+		switch c {
+		case string(models.ReviewerSyncRequest) + " " + failed:
+			retried = i
+		case string(models.ReviewerSyncRemove) + " " + failed:
+			removed = i
+		case string(models.ReviewerSyncRequest) + " gh-" + resp.ReplacedBy:
+			requested = i
+		}
+	}
+	if len(got) != len(reviewers)+3 || retried == 0 || removed < retried || requested < removed {
+		t.Fatalf("GitHub received %v, want the retry of %s before its removal and the request of gh-%s",
+			got, failed, resp.ReplacedBy)
+	}
+}
+
+func TestSyncRetriedWithBackoff(t *testing.T) {
+	f := newFixture(t, "u2", "u3", "u4")
+	f.codeHost.errs = []error{errors.New("unexpected status 502")}
+	// Only the author is linked, so link one reviewer to get exactly one sync
+	reviewers := f.createPR(t, testPRID)
+	if err := f.accountRepo.Link(context.Background(), &models.CodeHostAccount{
+		Provider: models.CodeHostGitHub, Login: "gh-" + reviewers[0], UserID: reviewers[0], CreatedAt: f.clock,
+	}); err != nil {
+		t.Fatalf("Link: %v", err)
+	}
+	f.publish(t)
+
+	f.syncer.processDue(context.Background())
+	if due := f.due(t); len(due) != 0 {
+		t.Fatalf("sync due right after a failed attempt")
+	}
+
+	f.clock = f.clock.Add(time.Second)
+	due := f.due(t)
+	if len(due) != 1 || due[0].Attempts != 1 || due[0].LastError == "" {
+		t.Fatalf("due after backoff = %+v, want one sync with 1 attempt and an error", due)
+	}
+
+	f.syncer.processDue(context.Background())
+	if calls := f.codeHost.received(); len(calls) != 2 {
+		t.Fatalf("GitHub received %d calls, want 2", len(calls))
+	}
+	if due := f.due(t); len(due) != 0 {
+		t.Errorf("%d syncs still due after the retry succeeded", len(due))
+	}
+}
+
+func TestSyncNotRetriedWhenRejected(t *testing.T) {
+	f := newFixture(t)
+	f.codeHost.errs = []error{ErrRejected, ErrRejected}
+	f.createPR(t, testPRID)
+	f.publish(t)
+
+	f.syncer.processDue(context.Background())
+
+	f.clock = f.clock.Add(time.Hour)
+	if due := f.due(t); len(due) != 0 {
+		t.Errorf("%d rejected syncs are still due", len(due))
+	}
+}
+
+func TestSyncSkipped(t *testing.T) {
+	tests := []struct {
+		name     string
+		prID     string
+		unlinked []string
+	}{
+		{name: "manually created PR", prID: "pr-1001"},
+		{name: "provider without client", prID: "gitlab:platform/api!7"},
+		{name: "reviewers without linked login", prID: testPRID, unlinked: []string{"u2", "u3", "u4"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFixture(t, tt.unlinked...)
+			f.createPR(t, tt.prID)
+			f.publish(t)
+
+			if due := f.due(t); len(due) != 0 {
+				t.Errorf("%d syncs scheduled, want none", len(due))
+			}
+		})
+	}
+}
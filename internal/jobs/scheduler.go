@@ -0,0 +1,135 @@
+// Package jobs runs periodic background jobs on a single elected replica
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"avito-backend-trainee-assignment-autumn-2025/pkg/logger"
+)
+
+// resignTimeout bounds giving up leadership during shutdown, when the run context is already cancelled
+const resignTimeout = 5 * time.Second
+
+// Job is a task run every Interval by the leader
+type Job struct {
+	// Name identifies the job in logs
+	Name string
+	// Interval is the time between two runs; a run that takes longer delays the next one
+	Interval time.Duration
+	// Run does the work; it must return promptly once ctx is cancelled
+	Run func(ctx context.Context) error
+}
+
+// Elector decides which replica runs the jobs
+type Elector interface {
+	// TryLead reports whether this replica leads, taking leadership if nobody holds it;
+	// a leader calls it again to confirm it still leads
+	TryLead(ctx context.Context) (bool, error)
+	// Resign gives leadership up
+	Resign(ctx context.Context)
+}
+
+// LocalElector always leads; it suits storages that serve a single replica (memory, SQLite)
+type LocalElector struct{}
+
+// TryLead always reports leadership
+func (LocalElector) TryLead(context.Context) (bool, error) {
+	return true, nil
+}
+
+// Resign does nothing
+func (LocalElector) Resign(context.Context) {}
+
+// Config controls the scheduler
+type Config struct {
+	// ElectionInterval is how often leadership is checked or sought and due jobs are looked up
+	ElectionInterval time.Duration
+}
+
+// Scheduler runs jobs while this replica is the leader
+// Jobs run one at a time, so a job never overlaps with itself or another job
+type Scheduler struct {
+	elector Elector
+	jobs    []Job
+	config  Config
+
+	// now is the clock used to schedule runs; replaced in tests
+	now func() time.Time
+}
+
+// NewScheduler creates a new job scheduler
+func NewScheduler(elector Elector, jobs []Job, config Config) *Scheduler {
+	return &Scheduler{
+		elector: elector,
+		jobs:    jobs,
+		config:  config,
+		now:     time.Now,
+	}
+}
+
+// Run elects a leader and runs due jobs until ctx is cancelled, then resigns
+func (s *Scheduler) Run(ctx context.Context) {
+	logger.Info("Job scheduler started (%d jobs, election interval: %v)", len(s.jobs), s.config.ElectionInterval)
+
+	ticker := time.NewTicker(s.config.ElectionInterval)
+	defer ticker.Stop()
+
+	// nextRun is zero until the first run, so a new leader runs every job right away
+	nextRun := make([]time.Time, len(s.jobs))
+	leading := false
+	for {
+		leading = s.elect(ctx, leading)
+		if leading {
+			s.runDue(ctx, nextRun)
+		}
+
+		select {
+		case <-ctx.Done():
+			if leading {
+				resignCtx, cancel := context.WithTimeout(context.Background(), resignTimeout)
+				s.elector.Resign(resignCtx)
+				cancel()
+			}
+			logger.Info("Job scheduler stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// elect asks the elector for leadership and logs changes
+func (s *Scheduler) elect(ctx context.Context, wasLeading bool) bool {
+	leading, err := s.elector.TryLead(ctx)
+	if err != nil && ctx.Err() == nil {
+		logger.Error("Job leader election failed: %v", err)
+	}
+
+	switch {
+	case leading && !wasLeading:
+		logger.Info("This replica is now the job leader")
+	case !leading && wasLeading:
+		logger.Warn("This replica lost job leadership")
+	}
+	return leading
+}
+
+// runDue runs every job whose next run is due and schedules the following one
+func (s *Scheduler) runDue(ctx context.Context, nextRun []time.Time) {
+	for i, job := range s.jobs {
+		if ctx.Err() != nil {
+			return
+		}
+		if s.now().Before(nextRun[i]) {
+			continue
+		}
+
+		started := s.now()
+		if err := job.Run(ctx); err != nil && ctx.Err() == nil {
+			logger.Error("Job %s failed: %v", job.Name, err)
+		} else {
+			logger.Debug("Job %s finished in %v", job.Name, s.now().Sub(started))
+		}
+		nextRun[i] = started.Add(job.Interval)
+	}
+}
@@ -0,0 +1,120 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// fakeElector leads while leading is set and counts resignations
+type fakeElector struct {
+	leading bool
+	err     error
+	resigns int
+
+	// onTry runs on every election, e.g. to stop the scheduler
+	onTry func()
+}
+
+func (e *fakeElector) TryLead(context.Context) (bool, error) {
+	if e.onTry != nil {
+		e.onTry()
+	}
+	return e.leading, e.err
+}
+
+func (e *fakeElector) Resign(context.Context) {
+	e.resigns++
+}
+
+func TestLeaderRunsJobsAndResignsOnShutdown(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	elector := &fakeElector{leading: true}
+	runs := 0
+	scheduler := NewScheduler(elector, []Job{{
+		Name:     "test",
+		Interval: time.Hour,
+		Run: func(context.Context) error {
+			runs++
+			cancel()
+			return nil
+		},
+	}}, Config{ElectionInterval: time.Hour})
+
+	scheduler.Run(ctx)
+
+	if runs != 1 {
+		t.Errorf("job ran %d times, want 1", runs)
+	}
+	if elector.resigns != 1 {
+		t.Errorf("resigned %d times, want 1", elector.resigns)
+	}
+}
+
+func TestFollowerDoesNotRunJobs(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	for _, elector := range []*fakeElector{
+		{leading: false, onTry: cancel},
+		{leading: false, err: errors.New("database is down"), onTry: cancel},
+	} {
+		runs := 0
+		scheduler := NewScheduler(elector, []Job{{
+			Name:     "test",
+			Interval: time.Hour,
+			Run:      func(context.Context) error { runs++; return nil },
+		}}, Config{ElectionInterval: time.Hour})
+
+		scheduler.Run(ctx)
+
+		if runs != 0 {
+			t.Errorf("follower ran the job %d times", runs)
+		}
+		if elector.resigns != 0 {
+			t.Errorf("follower resigned %d times", elector.resigns)
+		}
+	}
+}
+
+func TestJobsRunOncePerInterval(t *testing.T) {
+	now := time.Date(2025, 11, 3, 9, 0, 0, 0, time.UTC)
+	var hourly, daily int
+	scheduler := NewScheduler(LocalElector{}, []Job{
+		{Name: "hourly", Interval: time.Hour, Run: func(context.Context) error { hourly++; return nil }},
+		{Name: "daily", Interval: 24 * time.Hour, Run: func(context.Context) error { daily++; return errors.New("failed") }},
+	}, Config{ElectionInterval: time.Minute})
+	scheduler.now = func() time.Time { return now }
+
+	nextRun := make([]time.Time, 2)
+	for _, step := range []time.Duration{0, 30 * time.Minute, 30 * time.Minute, time.Hour} {
+		now = now.Add(step)
+		scheduler.runDue(context.Background(), nextRun)
+	}
+
+	// A failed run is not retried before its interval passes either
+	if hourly != 3 || daily != 1 {
+		t.Errorf("runs = %d hourly, %d daily; want 3 and 1", hourly, daily)
+	}
+}
+
+func TestCancelledSchedulerSkipsDueJobs(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	runs := 0
+	scheduler := NewScheduler(LocalElector{}, []Job{{
+		Name:     "test",
+		Interval: time.Hour,
+		Run:      func(context.Context) error { runs++; return nil },
+	}}, Config{ElectionInterval: time.Hour})
+
+	scheduler.Run(ctx)
+
+	if runs != 0 {
+		t.Errorf("job ran %d times after cancellation", runs)
+	}
+}
@@ -0,0 +1,19 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+
+	pkgerrors "avito-backend-trainee-assignment-autumn-2025/pkg/errors"
+	"avito-backend-trainee-assignment-autumn-2025/pkg/logger"
+)
+
+// writeError writes an error response in the standard API format
+func writeError(w http.ResponseWriter, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(pkgerrors.MapErrorToHTTPStatus(err))
+
+	if encodeErr := json.NewEncoder(w).Encode(pkgerrors.MapErrorToResponse(err)); encodeErr != nil {
+		logger.Error("Failed to encode error response: %v", encodeErr)
+	}
+}
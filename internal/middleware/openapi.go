@@ -0,0 +1,196 @@
+package middleware
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+
+	pkgerrors "avito-backend-trainee-assignment-autumn-2025/pkg/errors"
+	"avito-backend-trainee-assignment-autumn-2025/pkg/logger"
+)
+
+// ValidationMode controls how strictly traffic is checked against the OpenAPI spec
+type ValidationMode string
+
+const (
+	// ValidationModeOff disables validation
+	ValidationModeOff ValidationMode = "off"
+	// ValidationModeRequest validates incoming requests only
+	ValidationModeRequest ValidationMode = "request"
+	// ValidationModeFull validates both requests and responses
+	// A response that does not match the spec is replaced with an INTERNAL error
+	ValidationModeFull ValidationMode = "full"
+)
+
+// ParseValidationMode converts a string to ValidationMode
+func ParseValidationMode(value string) (ValidationMode, error) {
+	switch mode := ValidationMode(strings.ToLower(value)); mode {
+	case ValidationModeOff, ValidationModeRequest, ValidationModeFull:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("unknown OpenAPI validation mode: %q", value)
+	}
+}
+
+// maxValidatedBodySize limits how much of a request body is buffered for validation,
+// matching the largest limit the handlers accept
+const maxValidatedBodySize = 1 << 20
+
+func init() {
+	// Calendar uploads are validated as plain text; the service parses them
+	openapi3filter.RegisterBodyDecoder("text/calendar", openapi3filter.PlainBodyDecoder)
+}
+
+// OpenAPIValidator returns a middleware that validates requests (and, in full mode,
+// responses) against the given OpenAPI document.
+// Requests to routes that are not described in the spec are passed through unchanged.
+func OpenAPIValidator(doc *openapi3.T, mode ValidationMode) (func(http.Handler) http.Handler, error) {
+	if mode == ValidationModeOff {
+		return func(next http.Handler) http.Handler { return next }, nil
+	}
+
+	router, err := gorillamux.NewRouter(doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build OpenAPI router: %w", err)
+	}
+
+	options := &openapi3filter.Options{
+		AuthenticationFunc:  openapi3filter.NoopAuthenticationFunc,
+		SkipSettingDefaults: true,
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			route, pathParams, err := router.FindRoute(r)
+			if err != nil {
+				// Not described in the spec - let the router decide
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			// The validator buffers the body before any handler (or webhook signature check) sees it
+			r.Body = http.MaxBytesReader(w, r.Body, maxValidatedBodySize)
+
+			requestInput := &openapi3filter.RequestValidationInput{
+				Request:    r,
+				PathParams: pathParams,
+				Route:      route,
+				Options:    options,
+			}
+
+			if err := openapi3filter.ValidateRequest(r.Context(), requestInput); err != nil {
+				logger.Debug("Request %s %s does not match OpenAPI spec: %v", r.Method, r.URL.Path, err)
+				writeError(w, requestValidationError(err))
+				return
+			}
+
+			if mode != ValidationModeFull {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			recorder := newBufferedResponseWriter()
+			next.ServeHTTP(recorder, r)
+
+			responseInput := &openapi3filter.ResponseValidationInput{
+				RequestValidationInput: requestInput,
+				Status:                 recorder.statusCode,
+				Header:                 recorder.header,
+				Options:                options,
+			}
+			responseInput.SetBodyBytes(recorder.body.Bytes())
+
+			if err := openapi3filter.ValidateResponse(r.Context(), responseInput); err != nil {
+				logger.Error("Response for %s %s does not match OpenAPI spec: %v", r.Method, r.URL.Path, err)
+				writeError(w, fmt.Errorf("response does not match API contract: %w", err))
+				return
+			}
+
+			recorder.flushTo(w)
+		})
+	}, nil
+}
+
+// requestValidationError converts a kin-openapi validation error into a typed API error
+func requestValidationError(err error) error {
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		return pkgerrors.NewBadRequestError(fmt.Sprintf("request body exceeds %d bytes", maxBytesErr.Limit), err)
+	}
+
+	var requestErr *openapi3filter.RequestError
+	if !errors.As(err, &requestErr) {
+		return pkgerrors.NewBadRequestError("invalid request", err)
+	}
+
+	var parseErr *openapi3filter.ParseError
+	if errors.As(requestErr.Err, &parseErr) {
+		return pkgerrors.NewBadRequestError("invalid request body", parseErr)
+	}
+
+	field := ""
+	if requestErr.Parameter != nil {
+		field = requestErr.Parameter.Name
+	}
+
+	var schemaErr *openapi3.SchemaError
+	if errors.As(requestErr.Err, &schemaErr) {
+		if pointer := schemaErr.JSONPointer(); len(pointer) > 0 && field == "" {
+			field = strings.Join(pointer, ".")
+		}
+		return pkgerrors.NewValidationError(field, schemaErr.Reason)
+	}
+
+	if field == "" {
+		return pkgerrors.NewBadRequestError("invalid request", requestErr)
+	}
+	return pkgerrors.NewValidationError(field, requestErr.Reason)
+}
+
+// bufferedResponseWriter holds the whole response in memory so it can be validated
+// before being sent to the client
+type bufferedResponseWriter struct {
+	header     http.Header
+	statusCode int
+	body       bytes.Buffer
+}
+
+// newBufferedResponseWriter creates a new bufferedResponseWriter
+func newBufferedResponseWriter() *bufferedResponseWriter {
+	return &bufferedResponseWriter{
+		header:     make(http.Header),
+		statusCode: http.StatusOK,
+	}
+}
+
+// Header returns the buffered response headers
+func (bw *bufferedResponseWriter) Header() http.Header {
+	return bw.header
+}
+
+// WriteHeader captures the status code
+func (bw *bufferedResponseWriter) WriteHeader(statusCode int) {
+	bw.statusCode = statusCode
+}
+
+// Write appends data to the buffered body
+func (bw *bufferedResponseWriter) Write(b []byte) (int, error) {
+	return bw.body.Write(b)
+}
+
+// flushTo copies the buffered response to the real writer
+func (bw *bufferedResponseWriter) flushTo(w http.ResponseWriter) {
+	for key, values := range bw.header {
+		w.Header()[key] = values
+	}
+	w.WriteHeader(bw.statusCode)
+	if _, err := w.Write(bw.body.Bytes()); err != nil {
+		logger.Error("Failed to write validated response: %v", err)
+	}
+}
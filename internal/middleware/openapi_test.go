@@ -0,0 +1,183 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"avito-backend-trainee-assignment-autumn-2025/api"
+	"avito-backend-trainee-assignment-autumn-2025/pkg/dto/response"
+)
+
+func newValidatedHandler(t *testing.T, mode ValidationMode, next http.HandlerFunc) http.Handler {
+	t.Helper()
+
+	doc, err := api.LoadOpenAPI()
+	if err != nil {
+		t.Fatalf("Failed to load OpenAPI spec: %v", err)
+	}
+
+	validator, err := OpenAPIValidator(doc, mode)
+	if err != nil {
+		t.Fatalf("Failed to create validator: %v", err)
+	}
+
+	return validator(next)
+}
+
+func decodeError(t *testing.T, rec *httptest.ResponseRecorder) response.ErrorDetail {
+	t.Helper()
+
+	var errResp response.ErrorResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &errResp); err != nil {
+		t.Fatalf("Failed to decode error response %q: %v", rec.Body.String(), err)
+	}
+	return errResp.Error
+}
+
+func TestOpenAPIValidator_Request(t *testing.T) {
+	handlerCalled := false
+	handler := newValidatedHandler(t, ValidationModeRequest, func(w http.ResponseWriter, r *http.Request) {
+		handlerCalled = true
+		w.WriteHeader(http.StatusOK)
+	})
+
+	tests := []struct {
+		name          string
+		method        string
+		target        string
+		body          string
+		expectedCode  response.ErrorCode
+		expectedField string
+		expectPass    bool
+	}{
+		{
+			name:       "Valid request passes through",
+			method:     http.MethodPost,
+			target:     "/pullRequest/merge",
+			body:       `{"pull_request_id": "pr-1"}`,
+			expectPass: true,
+		},
+		{
+			name:          "Missing required body field",
+			method:        http.MethodPost,
+			target:        "/pullRequest/create",
+			body:          `{"pull_request_name": "Feature", "author_id": "u1"}`,
+			expectedCode:  response.ErrorCodeValidation,
+			expectedField: "pull_request_id",
+		},
+		{
+			name:          "Wrong field type",
+			method:        http.MethodPost,
+			target:        "/users/setIsActive",
+			body:          `{"user_id": "u1", "is_active": "yes"}`,
+			expectedCode:  response.ErrorCodeValidation,
+			expectedField: "is_active",
+		},
+		{
+			name:          "Missing query parameter",
+			method:        http.MethodGet,
+			target:        "/team/get",
+			expectedCode:  response.ErrorCodeValidation,
+			expectedField: "team_name",
+		},
+		{
+			name:         "Malformed JSON",
+			method:       http.MethodPost,
+			target:       "/team/add",
+			body:         `{"team_name":`,
+			expectedCode: response.ErrorCodeBadRequest,
+		},
+		{
+			name:         "Oversized body",
+			method:       http.MethodPost,
+			target:       "/team/add",
+			body:         `{"team_name": "` + strings.Repeat("a", maxValidatedBodySize) + `", "members": []}`,
+			expectedCode: response.ErrorCodeBadRequest,
+		},
+		{
+			name:       "Undocumented route passes through",
+			method:     http.MethodGet,
+			target:     "/unknown",
+			expectPass: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handlerCalled = false
+			req := httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body))
+			if tt.body != "" {
+				req.Header.Set("Content-Type", "application/json")
+			}
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, req)
+
+			if tt.expectPass {
+				if !handlerCalled || rec.Code != http.StatusOK {
+					t.Fatalf("Expected request to pass validation, got status %d: %s", rec.Code, rec.Body.String())
+				}
+				return
+			}
+
+			if handlerCalled {
+				t.Fatal("Handler should not be called for invalid request")
+			}
+			if rec.Code != http.StatusBadRequest {
+				t.Errorf("Expected status 400, got %d", rec.Code)
+			}
+
+			detail := decodeError(t, rec)
+			if detail.Code != tt.expectedCode {
+				t.Errorf("Expected code %s, got %s", tt.expectedCode, detail.Code)
+			}
+			if detail.Field != tt.expectedField {
+				t.Errorf("Expected field %q, got %q (message: %s)", tt.expectedField, detail.Field, detail.Message)
+			}
+		})
+	}
+}
+
+func TestOpenAPIValidator_Response(t *testing.T) {
+	t.Run("Valid response is forwarded", func(t *testing.T) {
+		handler := newValidatedHandler(t, ValidationModeFull, func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(`{"pr": {"pull_request_id": "pr-1", "pull_request_name": "Feature", "author_id": "u1", "status": "MERGED", "assigned_reviewers": []}}`))
+		})
+
+		req := httptest.NewRequest(http.MethodPost, "/pullRequest/merge", strings.NewReader(`{"pull_request_id": "pr-1"}`))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+
+		handler.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+	})
+
+	t.Run("Invalid response is replaced with an error", func(t *testing.T) {
+		handler := newValidatedHandler(t, ValidationModeFull, func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(`{"pr": {"pull_request_id": "pr-1", "status": "CLOSED"}}`))
+		})
+
+		req := httptest.NewRequest(http.MethodPost, "/pullRequest/merge", strings.NewReader(`{"pull_request_id": "pr-1"}`))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+
+		handler.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusInternalServerError {
+			t.Fatalf("Expected status 500, got %d", rec.Code)
+		}
+		if detail := decodeError(t, rec); detail.Code != response.ErrorCodeInternal {
+			t.Errorf("Expected code %s, got %s", response.ErrorCodeInternal, detail.Code)
+		}
+	})
+}
@@ -21,12 +21,8 @@ func Recovery(next http.Handler) http.Handler {
 					string(stackTrace),
 				)
 
-				// Return 500 Internal Server Error
-				http.Error(
-					w,
-					fmt.Sprintf("Internal Server Error: %v", err),
-					http.StatusInternalServerError,
-				)
+				// Return 500 Internal Server Error without exposing panic details
+				writeError(w, fmt.Errorf("panic: %v", err))
 			}
 		}()
 
@@ -0,0 +1,233 @@
+package migrator
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/pgx/v5/stdlib"
+	"github.com/pressly/goose/v3"
+	"github.com/pressly/goose/v3/lock"
+
+	"avito-backend-trainee-assignment-autumn-2025/migrations"
+	"avito-backend-trainee-assignment-autumn-2025/pkg/logger"
+)
+
+// ErrSchemaBehind is returned when the database has pending migrations
+var ErrSchemaBehind = errors.New("database schema is behind")
+
+// Migrator applies the embedded migrations to PostgreSQL or SQLite
+// On PostgreSQL every command that changes the schema holds an advisory lock, so several
+// instances starting at once apply each migration exactly once
+type Migrator struct {
+	provider *goose.Provider
+
+	// unlocked runs migrations without taking the session lock; used by commands that
+	// hold the lock themselves across several steps
+	unlocked *goose.Provider
+
+	// lock takes the session lock other instances wait for and returns its release function
+	lock func(ctx context.Context) (func(), error)
+
+	// close releases the database handle opened for the migrator, if any
+	close func() error
+}
+
+// New creates a PostgreSQL migrator working through the given connection pool
+func New(pool *pgxpool.Pool) (*Migrator, error) {
+	locker, err := lock.NewPostgresSessionLocker()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create migration lock: %w", err)
+	}
+
+	db := stdlib.OpenDBFromPool(pool)
+
+	provider, err := goose.NewProvider(goose.DialectPostgres, db, migrations.FS,
+		goose.WithSessionLocker(locker),
+	)
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to load migrations: %w", err)
+	}
+
+	unlocked, err := goose.NewProvider(goose.DialectPostgres, db, migrations.FS)
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to load migrations: %w", err)
+	}
+
+	return &Migrator{
+		provider: provider,
+		unlocked: unlocked,
+		lock:     sessionLock(db, locker),
+		close:    db.Close,
+	}, nil
+}
+
+// sessionLock returns a lock function that holds the migration lock on a dedicated connection
+func sessionLock(db *sql.DB, locker lock.SessionLocker) func(ctx context.Context) (func(), error) {
+	return func(ctx context.Context) (func(), error) {
+		conn, err := db.Conn(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to acquire connection: %w", err)
+		}
+		if err := locker.SessionLock(ctx, conn); err != nil {
+			conn.Close()
+			return nil, err
+		}
+
+		return func() {
+			// The lock is released with the session even if unlocking fails
+			if err := locker.SessionUnlock(context.WithoutCancel(ctx), conn); err != nil {
+				logger.Warn("Failed to release migration lock: %v", err)
+			}
+			conn.Close()
+		}, nil
+	}
+}
+
+// NewSQLite creates an SQLite migrator working through the given database handle
+// SQLite serializes writers itself, so no session lock is needed
+func NewSQLite(db *sql.DB) (*Migrator, error) {
+	provider, err := goose.NewProvider(goose.DialectSQLite3, db, migrations.SQLiteFS)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load migrations: %w", err)
+	}
+
+	return &Migrator{
+		provider: provider,
+		unlocked: provider,
+		lock:     func(context.Context) (func(), error) { return func() {}, nil },
+		close:    func() error { return nil },
+	}, nil
+}
+
+// Close releases the migrator's database handle (the underlying pool or database stays open)
+func (m *Migrator) Close() error {
+	return m.close()
+}
+
+// Up applies all pending migrations
+func (m *Migrator) Up(ctx context.Context) ([]*goose.MigrationResult, error) {
+	results, err := m.provider.Up(ctx)
+	if err != nil {
+		return results, fmt.Errorf("failed to apply migrations: %w", err)
+	}
+	return results, nil
+}
+
+// Down rolls back the latest applied migration
+func (m *Migrator) Down(ctx context.Context) (*goose.MigrationResult, error) {
+	result, err := m.provider.Down(ctx)
+	if err != nil {
+		return result, fmt.Errorf("failed to roll back migration: %w", err)
+	}
+	return result, nil
+}
+
+// Redo rolls back the latest applied migration and applies it again
+// Both steps run under one lock, so no other instance migrates in between; Redo is refused
+// while newer migrations are pending, as it would re-apply a migration that is not the latest
+func (m *Migrator) Redo(ctx context.Context) ([]*goose.MigrationResult, error) {
+	unlock, err := m.lock(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to take migration lock: %w", err)
+	}
+	defer unlock()
+
+	current, target, err := m.unlocked.GetVersions(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get schema version: %w", err)
+	}
+	pending, err := m.unlocked.HasPending(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check pending migrations: %w", err)
+	}
+	if pending {
+		return nil, fmt.Errorf("%w: current version %d, latest version %d (run `api migrate up` before redo)",
+			ErrSchemaBehind, current, target)
+	}
+
+	down, err := m.unlocked.Down(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to roll back migration: %w", err)
+	}
+
+	up, err := m.unlocked.UpByOne(ctx)
+	if err != nil {
+		return []*goose.MigrationResult{down}, fmt.Errorf(
+			"failed to re-apply migration %d, schema left at the previous version: %w", down.Source.Version, err)
+	}
+
+	return []*goose.MigrationResult{down, up}, nil
+}
+
+// Status returns the state of every known migration
+func (m *Migrator) Status(ctx context.Context) ([]*goose.MigrationStatus, error) {
+	statuses, err := m.provider.Status(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get migration status: %w", err)
+	}
+	return statuses, nil
+}
+
+// CheckUpToDate returns ErrSchemaBehind if there are migrations that are not applied yet
+func (m *Migrator) CheckUpToDate(ctx context.Context) error {
+	current, target, err := m.provider.GetVersions(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get schema version: %w", err)
+	}
+
+	pending, err := m.provider.HasPending(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to check pending migrations: %w", err)
+	}
+
+	if pending {
+		return fmt.Errorf("%w: current version %d, latest version %d", ErrSchemaBehind, current, target)
+	}
+
+	return nil
+}
+
+// EnsureSchema makes sure the schema is up to date before the service starts
+// With autoMigrate pending migrations are applied, otherwise startup is refused
+func EnsureSchema(ctx context.Context, m *Migrator, autoMigrate bool) error {
+	if !autoMigrate {
+		if err := m.CheckUpToDate(ctx); err != nil {
+			if errors.Is(err, ErrSchemaBehind) {
+				return fmt.Errorf("%w (run `api migrate up` or set AUTO_MIGRATE=true)", err)
+			}
+			return err
+		}
+		logger.Info("Database schema is up to date")
+		return nil
+	}
+
+	results, err := m.Up(ctx)
+	for _, result := range results {
+		logger.Info("Migration %s", result)
+	}
+	if err != nil {
+		return err
+	}
+
+	logger.Info("Database schema is up to date (%d migration(s) applied)", len(results))
+	return nil
+}
+
+// PrintStatus writes a human-readable migration status table
+func PrintStatus(w io.Writer, statuses []*goose.MigrationStatus) {
+	fmt.Fprintf(w, "%-8s %-25s %s\n", "STATE", "APPLIED AT", "MIGRATION")
+	for _, status := range statuses {
+		appliedAt := "-"
+		if !status.AppliedAt.IsZero() {
+			appliedAt = status.AppliedAt.UTC().Format("2006-01-02 15:04:05 MST")
+		}
+		fmt.Fprintf(w, "%-8s %-25s %s\n", status.State, appliedAt, path.Base(status.Source.Path))
+	}
+}
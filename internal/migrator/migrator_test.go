@@ -0,0 +1,140 @@
+package migrator
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/pressly/goose/v3"
+
+	"avito-backend-trainee-assignment-autumn-2025/pkg/database"
+)
+
+func openSQLite(t *testing.T) *sql.DB {
+	t.Helper()
+
+	db, err := database.NewSQLiteDB(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func newSQLiteMigrator(t *testing.T) *Migrator {
+	t.Helper()
+
+	m, err := NewSQLite(openSQLite(t))
+	if err != nil {
+		t.Fatalf("migrator: %v", err)
+	}
+	return m
+}
+
+func version(t *testing.T, m *Migrator) int64 {
+	t.Helper()
+
+	current, err := m.provider.GetDBVersion(context.Background())
+	if err != nil {
+		t.Fatalf("version: %v", err)
+	}
+	return current
+}
+
+func TestEnsureSchemaRefusesPendingMigrationsWithoutAutoMigrate(t *testing.T) {
+	m := newSQLiteMigrator(t)
+
+	err := EnsureSchema(context.Background(), m, false)
+	if !errors.Is(err, ErrSchemaBehind) {
+		t.Fatalf("got %v, want ErrSchemaBehind", err)
+	}
+	if v := version(t, m); v != 0 {
+		t.Errorf("version = %d, want nothing applied", v)
+	}
+}
+
+func TestEnsureSchemaAppliesPendingMigrationsWithAutoMigrate(t *testing.T) {
+	ctx := context.Background()
+	m := newSQLiteMigrator(t)
+
+	if err := EnsureSchema(ctx, m, true); err != nil {
+		t.Fatalf("auto migrate: %v", err)
+	}
+	if err := EnsureSchema(ctx, m, false); err != nil {
+		t.Fatalf("check after auto migrate: %v", err)
+	}
+}
+
+func TestRedoReappliesLatestMigration(t *testing.T) {
+	ctx := context.Background()
+	m := newSQLiteMigrator(t)
+
+	if _, err := m.Up(ctx); err != nil {
+		t.Fatalf("up: %v", err)
+	}
+	latest := version(t, m)
+
+	results, err := m.Redo(ctx)
+	if err != nil {
+		t.Fatalf("redo: %v", err)
+	}
+	if len(results) != 2 || results[0].Source.Version != latest || results[1].Source.Version != latest {
+		t.Fatalf("results = %v, want down and up of %d", results, latest)
+	}
+	if err := m.CheckUpToDate(ctx); err != nil {
+		t.Errorf("check: %v", err)
+	}
+}
+
+func TestRedoRefusedWithPendingMigrations(t *testing.T) {
+	ctx := context.Background()
+	m := newSQLiteMigrator(t)
+
+	if _, err := m.provider.UpTo(ctx, 3); err != nil {
+		t.Fatalf("up to 3: %v", err)
+	}
+
+	if _, err := m.Redo(ctx); !errors.Is(err, ErrSchemaBehind) {
+		t.Fatalf("got %v, want ErrSchemaBehind", err)
+	}
+	if v := version(t, m); v != 3 {
+		t.Errorf("version = %d, want 3 (nothing rolled back)", v)
+	}
+}
+
+func TestRedoReportsFailedReapply(t *testing.T) {
+	ctx := context.Background()
+
+	// The second migration cannot be applied twice, and its rollback does not undo it
+	fsys := fstest.MapFS{
+		"00001_create.sql": {Data: []byte("-- +goose Up\nCREATE TABLE t (id INTEGER PRIMARY KEY);\n-- +goose Down\nDROP TABLE t;\n")},
+		"00002_insert.sql": {Data: []byte("-- +goose Up\nINSERT INTO t (id) VALUES (1);\n-- +goose Down\nSELECT 1;\n")},
+	}
+	provider, err := goose.NewProvider(goose.DialectSQLite3, openSQLite(t), fsys)
+	if err != nil {
+		t.Fatalf("provider: %v", err)
+	}
+	m := &Migrator{
+		provider: provider,
+		unlocked: provider,
+		lock:     func(context.Context) (func(), error) { return func() {}, nil },
+		close:    func() error { return nil },
+	}
+	if _, err := m.Up(ctx); err != nil {
+		t.Fatalf("up: %v", err)
+	}
+
+	results, err := m.Redo(ctx)
+	if err == nil {
+		t.Fatal("redo succeeded, want re-apply error")
+	}
+	if len(results) != 1 || results[0].Source.Version != 2 {
+		t.Errorf("results = %v, want only the rollback of 2", results)
+	}
+	if v := version(t, m); v != 1 {
+		t.Errorf("version = %d, want 1", v)
+	}
+}
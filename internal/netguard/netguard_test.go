@@ -0,0 +1,60 @@
+package netguard
+
+import (
+	"context"
+	"errors"
+	"net/netip"
+	"testing"
+)
+
+func TestIsPublic(t *testing.T) {
+	tests := []struct {
+		addr   string
+		public bool
+	}{
+		{"93.184.215.14", true},
+		{"2606:2800:21f:cb07:6820:80da:af6b:8b2c", true},
+		{"127.0.0.1", false},
+		{"::1", false},
+		{"10.1.2.3", false},
+		{"172.16.0.1", false},
+		{"192.168.1.1", false},
+		{"169.254.169.254", false},
+		{"fe80::1", false},
+		{"fd00::1", false},
+		{"0.0.0.0", false},
+		{"0.1.2.3", false},
+		{"100.64.0.1", false},
+		{"224.0.0.1", false},
+		{"255.255.255.255", false},
+		{"::ffff:127.0.0.1", false},
+		{"64:ff9b::a00:1", false},
+	}
+	for _, tt := range tests {
+		if got := IsPublic(netip.MustParseAddr(tt.addr)); got != tt.public {
+			t.Errorf("IsPublic(%s) = %v, want %v", tt.addr, got, tt.public)
+		}
+	}
+}
+
+func TestCheckHost(t *testing.T) {
+	ctx := context.Background()
+
+	if err := CheckHost(ctx, "93.184.215.14"); err != nil {
+		t.Errorf("public address: %v", err)
+	}
+	for _, host := range []string{"169.254.169.254", "::1", "localhost"} {
+		if err := CheckHost(ctx, host); !errors.Is(err, ErrNonPublicAddress) {
+			t.Errorf("CheckHost(%s) = %v, want ErrNonPublicAddress", host, err)
+		}
+	}
+}
+
+func TestControl(t *testing.T) {
+	if err := Control("tcp4", "93.184.215.14:443", nil); err != nil {
+		t.Errorf("public address: %v", err)
+	}
+	if err := Control("tcp4", "10.0.0.1:80", nil); !errors.Is(err, ErrNonPublicAddress) {
+		t.Errorf("private address: got %v, want ErrNonPublicAddress", err)
+	}
+}
@@ -0,0 +1,414 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"embed"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	htmltemplate "html/template"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"strings"
+	texttemplate "text/template"
+	"time"
+
+	"avito-backend-trainee-assignment-autumn-2025/internal/domain/models"
+	"avito-backend-trainee-assignment-autumn-2025/internal/repository"
+	"avito-backend-trainee-assignment-autumn-2025/pkg/logger"
+)
+
+// Email kinds; each has a <kind>.txt and a <kind>.html template
+const (
+	emailAssigned = "assigned"
+	emailReplaced = "replaced"
+	emailMerged   = "merged"
+)
+
+//go:embed templates/*.txt templates/*.html
+var templateFS embed.FS
+
+// emailTemplates holds the plain-text (with a "subject" block) and HTML templates of one email kind
+type emailTemplates struct {
+	text *texttemplate.Template
+	html *htmltemplate.Template
+}
+
+// templates are parsed once; a broken template is a build defect, so parsing panics
+var templates = func() map[string]emailTemplates {
+	funcs := map[string]any{"join": strings.Join}
+	result := make(map[string]emailTemplates)
+	for _, kind := range []string{emailAssigned, emailReplaced, emailMerged} {
+		result[kind] = emailTemplates{
+			text: texttemplate.Must(texttemplate.New(kind+".txt").Funcs(funcs).ParseFS(templateFS, "templates/"+kind+".txt")),
+			html: htmltemplate.Must(htmltemplate.New(kind+".html").Funcs(funcs).ParseFS(templateFS, "templates/"+kind+".html")),
+		}
+	}
+	return result
+}()
+
+// EmailConfig controls email sending
+type EmailConfig struct {
+	// Addr is the SMTP server as host:port
+	Addr string
+	// Username and Password enable PLAIN authentication; it is only used over TLS or to localhost
+	Username string
+	Password string
+	// From is the sender, either bare or with a display name ("PR Reviewer <noreply@example.com>")
+	From string
+	// QueueSize bounds the emails waiting to be sent; new emails are dropped while it is full
+	QueueSize int
+	// Senders is the number of SMTP sessions run in parallel; a recipient is served by one at a time
+	Senders int
+	// Timeout bounds a whole SMTP session
+	Timeout time.Duration
+}
+
+// emailData is what the templates render
+type emailData struct {
+	Recipient       string
+	PullRequestID   string
+	PullRequestName string
+	Author          string
+	// Previous is the reviewer replaced by the recipient (assigned)
+	Previous string
+	// Successor is the reviewer who took over from the recipient (replaced)
+	Successor string
+	// Reviewers are the reviewers of a merged PR (merged)
+	Reviewers []string
+}
+
+// emailMessage is a rendered email waiting to be sent
+type emailMessage struct {
+	to      mail.Address
+	subject string
+	text    string
+	html    string
+}
+
+// EmailNotifier emails reviewers about assignment and reassignment, and authors and reviewers about merges
+// Like SlackNotifier, as an outbox sink it only renders and queues emails and Run sends them, so a
+// slow session for one recipient does not hold back emails to the others;
+// users without an email are skipped and a failed send is logged, never retried through the outbox
+type EmailNotifier struct {
+	userRepo repository.UserRepository
+	config   EmailConfig
+	from     *mail.Address
+	// queue holds the emails by recipient address
+	queue *keyedQueue[emailMessage]
+}
+
+// NewEmailNotifier creates a new email notifier
+func NewEmailNotifier(userRepo repository.UserRepository, config EmailConfig) (*EmailNotifier, error) {
+	from, err := mail.ParseAddress(config.From)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sender address %q: %w", config.From, err)
+	}
+	if config.QueueSize <= 0 {
+		config.QueueSize = 1000
+	}
+	if config.Senders <= 0 {
+		config.Senders = 1
+	}
+
+	return &EmailNotifier{
+		userRepo: userRepo,
+		config:   config,
+		from:     from,
+		queue:    newKeyedQueue[emailMessage](config.QueueSize),
+	}, nil
+}
+
+// Name identifies the notifier as an outbox sink
+func (n *EmailNotifier) Name() string {
+	return "email"
+}
+
+// Handle queues emails for assignment, reassignment and merge events
+// It never fails: a notification must not hold back the other sinks
+func (n *EmailNotifier) Handle(ctx context.Context, msg *models.OutboxMessage) error {
+	var event outboxEvent
+	if err := json.Unmarshal(msg.Payload, &event); err != nil {
+		logger.Error("Skipping email notification for outbox message %s: invalid payload: %v", msg.ID, err)
+		return nil
+	}
+
+	var err error
+	switch msg.EventType {
+	case models.EventReviewerAssigned:
+		var data models.ReviewerEventData
+		if err = json.Unmarshal(event.Data, &data); err == nil {
+			err = n.reviewerAssigned(ctx, data)
+		}
+	case models.EventPRMerged:
+		var data models.PRMergedEventData
+		if err = json.Unmarshal(event.Data, &data); err == nil {
+			err = n.prMerged(ctx, data)
+		}
+	default:
+		// reviewer.unassigned is reported together with the reviewer who took over
+		return nil
+	}
+
+	if err != nil {
+		logger.Error("Skipping email notification for event %s (%s): %v", msg.ID, msg.EventType, err)
+	}
+	return nil
+}
+
+// reviewerAssigned emails the new reviewer and, on reassignment, the reviewer who was replaced
+func (n *EmailNotifier) reviewerAssigned(ctx context.Context, data models.ReviewerEventData) error {
+	reviewer, err := n.userRepo.GetByID(ctx, data.ReviewerID)
+	if err != nil {
+		return fmt.Errorf("failed to get reviewer %s: %w", data.ReviewerID, err)
+	}
+
+	base := emailData{
+		PullRequestID:   data.PullRequestID,
+		PullRequestName: data.PullRequestName,
+		Author:          n.name(ctx, data.AuthorID),
+	}
+
+	assigned := base
+	if data.ReplacedReviewerID != "" {
+		assigned.Previous = n.name(ctx, data.ReplacedReviewerID)
+	}
+	if err := n.enqueue(emailAssigned, reviewer, assigned); err != nil {
+		return err
+	}
+
+	if data.ReplacedReviewerID == "" {
+		return nil
+	}
+	previous, err := n.userRepo.GetByID(ctx, data.ReplacedReviewerID)
+	if err != nil {
+		return fmt.Errorf("failed to get replaced reviewer %s: %w", data.ReplacedReviewerID, err)
+	}
+	replaced := base
+	replaced.Successor = reviewer.Username
+	return n.enqueue(emailReplaced, previous, replaced)
+}
+
+// prMerged emails the author and the reviewers of a merged PR
+func (n *EmailNotifier) prMerged(ctx context.Context, data models.PRMergedEventData) error {
+	author, err := n.userRepo.GetByID(ctx, data.AuthorID)
+	if err != nil {
+		return fmt.Errorf("failed to get author %s: %w", data.AuthorID, err)
+	}
+
+	base := emailData{
+		PullRequestID:   data.PullRequestID,
+		PullRequestName: data.PullRequestName,
+		Author:          author.Username,
+	}
+	recipients := []*models.User{author}
+	for _, id := range data.AssignedReviewers {
+		reviewer, err := n.userRepo.GetByID(ctx, id)
+		if err != nil {
+			base.Reviewers = append(base.Reviewers, id)
+			continue
+		}
+		base.Reviewers = append(base.Reviewers, reviewer.Username)
+		recipients = append(recipients, reviewer)
+	}
+
+	for _, recipient := range recipients {
+		if err := n.enqueue(emailMerged, recipient, base); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// enqueue renders an email of the given kind for the user; users without an email are skipped
+func (n *EmailNotifier) enqueue(kind string, user *models.User, data emailData) error {
+	if user.Email == "" {
+		return nil
+	}
+
+	data.Recipient = user.Username
+	msg, err := render(kind, data)
+	if err != nil {
+		return fmt.Errorf("failed to render %s email: %w", kind, err)
+	}
+	msg.to = mail.Address{Name: user.Username, Address: user.Email}
+
+	if !n.queue.push(msg.to.Address, msg) {
+		logger.Warn("Email queue is full, dropping %s email for user %s", kind, user.ID)
+	}
+	return nil
+}
+
+// name returns the username of a user who is mentioned in passing, or the ID if the user is gone
+func (n *EmailNotifier) name(ctx context.Context, userID string) string {
+	user, err := n.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return userID
+	}
+	return user.Username
+}
+
+// Run sends queued emails over up to Senders sessions until ctx is cancelled
+// Emails to one recipient are sent one after another, in the order they were queued
+func (n *EmailNotifier) Run(ctx context.Context) {
+	logger.Info("Email notifier started (SMTP server: %s, senders: %d)", n.config.Addr, n.config.Senders)
+
+	n.queue.serve(ctx, n.config.Senders, time.Now, func(ctx context.Context, _ string, msg emailMessage) time.Time {
+		if err := n.send(ctx, msg); err != nil {
+			if ctx.Err() == nil {
+				logger.Warn("Failed to send email %q to %s: %v", msg.subject, msg.to.Address, err)
+			}
+			return time.Time{}
+		}
+		logger.Debug("Sent email %q to %s", msg.subject, msg.to.Address)
+		return time.Time{}
+	})
+	logger.Info("Email notifier stopped")
+}
+
+// send delivers one email in its own SMTP session
+// STARTTLS is used whenever the server offers it
+func (n *EmailNotifier) send(ctx context.Context, msg emailMessage) error {
+	body, err := n.compose(msg)
+	if err != nil {
+		return err
+	}
+
+	host, _, err := net.SplitHostPort(n.config.Addr)
+	if err != nil {
+		return fmt.Errorf("invalid SMTP address: %w", err)
+	}
+
+	dialer := net.Dialer{Timeout: n.config.Timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", n.config.Addr)
+	if err != nil {
+		return err
+	}
+	if n.config.Timeout > 0 {
+		_ = conn.SetDeadline(time.Now().Add(n.config.Timeout))
+	}
+
+	client, err := smtp.NewClient(conn, host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: host}); err != nil {
+			return fmt.Errorf("STARTTLS failed: %w", err)
+		}
+	}
+	if n.config.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", n.config.Username, n.config.Password, host)); err != nil {
+			return fmt.Errorf("authentication failed: %w", err)
+		}
+	}
+
+	if err := client.Mail(n.from.Address); err != nil {
+		return err
+	}
+	if err := client.Rcpt(msg.to.Address); err != nil {
+		return err
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(body); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+// compose builds a multipart/alternative message with the plain-text and HTML bodies
+func (n *EmailNotifier) compose(msg emailMessage) ([]byte, error) {
+	var buf bytes.Buffer
+	parts := multipart.NewWriter(&buf)
+
+	for _, part := range []struct{ contentType, body string }{
+		{"text/plain; charset=utf-8", msg.text},
+		{"text/html; charset=utf-8", msg.html},
+	} {
+		w, err := parts.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		qp := quotedprintable.NewWriter(w)
+		if _, err := qp.Write([]byte(part.body)); err != nil {
+			return nil, err
+		}
+		if err := qp.Close(); err != nil {
+			return nil, err
+		}
+	}
+	if err := parts.Close(); err != nil {
+		return nil, err
+	}
+
+	var out bytes.Buffer
+	for _, field := range [][2]string{
+		{"From", n.from.String()},
+		{"To", msg.to.String()},
+		{"Subject", mime.QEncoding.Encode("utf-8", msg.subject)},
+		{"Date", time.Now().Format(time.RFC1123Z)},
+		{"Message-ID", messageID(n.from.Address)},
+		{"MIME-Version", "1.0"},
+		{"Content-Type", "multipart/alternative; boundary=" + parts.Boundary()},
+	} {
+		fmt.Fprintf(&out, "%s: %s\r\n", field[0], field[1])
+	}
+	out.WriteString("\r\n")
+	out.Write(buf.Bytes())
+	return out.Bytes(), nil
+}
+
+// render executes the templates of an email kind
+func render(kind string, data emailData) (emailMessage, error) {
+	tmpl := templates[kind]
+
+	var subject, text, html bytes.Buffer
+	if err := tmpl.text.ExecuteTemplate(&subject, "subject", data); err != nil {
+		return emailMessage{}, err
+	}
+	if err := tmpl.text.Execute(&text, data); err != nil {
+		return emailMessage{}, err
+	}
+	if err := tmpl.html.Execute(&html, data); err != nil {
+		return emailMessage{}, err
+	}
+
+	return emailMessage{
+		// PR names come from users; a line break in the subject would start a new header
+		subject: strings.Join(strings.Fields(subject.String()), " "),
+		text:    text.String(),
+		html:    html.String(),
+	}, nil
+}
+
+// messageID generates a unique Message-ID in the sender's domain
+func messageID(from string) string {
+	domain := "localhost"
+	if at := strings.LastIndexByte(from, '@'); at >= 0 {
+		domain = from[at+1:]
+	}
+
+	var random [16]byte
+	_, _ = rand.Read(random[:])
+	return "<" + hex.EncodeToString(random[:]) + "@" + domain + ">"
+}
@@ -0,0 +1,550 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"avito-backend-trainee-assignment-autumn-2025/internal/repository/memory"
+	"avito-backend-trainee-assignment-autumn-2025/internal/service"
+	"avito-backend-trainee-assignment-autumn-2025/pkg/dto/request"
+)
+
+// smtpServer is an in-process SMTP stand-in that accepts every message, except for rejected recipients
+type smtpServer struct {
+	listener net.Listener
+	reject   map[string]bool
+	// stall holds the answer to RCPT for a recipient until the channel is closed
+	stall    map[string]chan struct{}
+	received chan receivedMail
+}
+
+// receivedMail is one message accepted by smtpServer
+type receivedMail struct {
+	from string
+	to   []string
+	data []byte
+}
+
+func newSMTPServer(t *testing.T, reject ...string) *smtpServer {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	s := &smtpServer{
+		listener: listener,
+		reject:   make(map[string]bool),
+		stall:    make(map[string]chan struct{}),
+		received: make(chan receivedMail, 100),
+	}
+	for _, addr := range reject {
+		s.reject[addr] = true
+	}
+
+	var wg sync.WaitGroup
+	t.Cleanup(func() {
+		listener.Close()
+		wg.Wait()
+	})
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				s.serve(conn)
+			}()
+		}
+	}()
+
+	return s
+}
+
+func (s *smtpServer) addr() string {
+	return s.listener.Addr().String()
+}
+
+// serve speaks just enough SMTP for net/smtp: no extensions, so no STARTTLS or AUTH
+func (s *smtpServer) serve(conn net.Conn) {
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(5 * time.Second))
+	tp := textproto.NewConn(conn)
+
+	var mail receivedMail
+	_ = tp.PrintfLine("220 localhost ESMTP test")
+	for {
+		line, err := tp.ReadLine()
+		if err != nil {
+			return
+		}
+		verb, arg, _ := strings.Cut(line, " ")
+		switch strings.ToUpper(verb) {
+		case "EHLO", "HELO":
+			_ = tp.PrintfLine("250 localhost")
+		case "MAIL":
+			mail = receivedMail{from: trimPath(arg)}
+			_ = tp.PrintfLine("250 OK")
+		case "RCPT":
+			to := trimPath(arg)
+			if stall, ok := s.stall[to]; ok {
+				<-stall
+			}
+			if s.reject[to] {
+				_ = tp.PrintfLine("550 no such user")
+				continue
+			}
+			mail.to = append(mail.to, to)
+			_ = tp.PrintfLine("250 OK")
+		case "DATA":
+			_ = tp.PrintfLine("354 go ahead")
+			data, err := tp.ReadDotBytes()
+			if err != nil {
+				return
+			}
+			mail.data = data
+			s.received <- mail
+			_ = tp.PrintfLine("250 OK")
+		case "RSET", "NOOP":
+			_ = tp.PrintfLine("250 OK")
+		case "QUIT":
+			_ = tp.PrintfLine("221 bye")
+			return
+		default:
+			_ = tp.PrintfLine("502 not implemented")
+		}
+	}
+}
+
+// trimPath extracts the address from "FROM:<a@b>" / "TO:<a@b>"
+func trimPath(arg string) string {
+	_, path, _ := strings.Cut(arg, ":")
+	path, _, _ = strings.Cut(path, " ")
+	return strings.Trim(path, "<>")
+}
+
+// parsedMail is a received message with its headers and bodies decoded
+type parsedMail struct {
+	from    string
+	to      []string
+	header  mail.Header
+	subject string
+	text    string
+	html    string
+}
+
+// parse decodes a received multipart/alternative message
+func (m receivedMail) parse(t *testing.T) parsedMail {
+	t.Helper()
+
+	msg, err := mail.ReadMessage(strings.NewReader(string(m.data)))
+	if err != nil {
+		t.Fatalf("ReadMessage: %v", err)
+	}
+	subject, err := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	if err != nil {
+		t.Fatalf("decode subject: %v", err)
+	}
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/alternative" {
+		t.Fatalf("Content-Type = %q, want multipart/alternative", msg.Header.Get("Content-Type"))
+	}
+
+	parsed := parsedMail{from: m.from, to: m.to, header: msg.Header, subject: subject}
+	reader := multipart.NewReader(msg.Body, params["boundary"])
+	for {
+		part, err := reader.NextPart()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("NextPart: %v", err)
+		}
+		// NextPart decodes quoted-printable transparently
+		body, err := io.ReadAll(part)
+		if err != nil {
+			t.Fatalf("read part: %v", err)
+		}
+		switch contentType := part.Header.Get("Content-Type"); {
+		case strings.HasPrefix(contentType, "text/plain"):
+			parsed.text = string(body)
+		case strings.HasPrefix(contentType, "text/html"):
+			parsed.html = string(body)
+		default:
+			t.Fatalf("unexpected part %q", contentType)
+		}
+	}
+	return parsed
+}
+
+type emailFixture struct {
+	outbox   *memory.OutboxRepository
+	users    *service.UserServiceImpl
+	prs      *service.PRServiceImpl
+	notifier *EmailNotifier
+	server   *smtpServer
+}
+
+// newEmailFixture creates team "backend" with author u1, reviewers u2, u3 and inactive u4;
+// everyone but u3 has an email address
+func newEmailFixture(t *testing.T, server *smtpServer) *emailFixture {
+	t.Helper()
+	ctx := context.Background()
+
+	store := memory.NewStore()
+	teamRepo := memory.NewTeamRepository(store)
+	userRepo := memory.NewUserRepository(store)
+	prRepo := memory.NewPRRepository(store)
+	absenceRepo := memory.NewAbsenceRepository(store)
+	affinityRepo := memory.NewAffinityRepository(store)
+	txManager := memory.NewTransactionManager(store)
+
+	notifier, err := NewEmailNotifier(userRepo, EmailConfig{
+		Addr:      server.addr(),
+		From:      "PR Reviewer <noreply@example.com>",
+		QueueSize: 10,
+		Senders:   2,
+		Timeout:   5 * time.Second,
+	})
+	if err != nil {
+		t.Fatalf("NewEmailNotifier: %v", err)
+	}
+
+	f := &emailFixture{
+		outbox:   memory.NewOutboxRepository(store),
+		notifier: notifier,
+		server:   server,
+	}
+	f.users = service.NewUserService(userRepo, teamRepo, prRepo, absenceRepo, affinityRepo, txManager, f.outbox)
+	f.prs = service.NewPRService(prRepo, userRepo, teamRepo, absenceRepo, affinityRepo, txManager, f.outbox)
+
+	_, err = service.NewTeamService(teamRepo, userRepo, absenceRepo, txManager, f.outbox).CreateTeam(ctx, &request.CreateTeamRequest{
+		TeamName: "backend",
+		Members: []request.TeamMemberRequest{
+			{UserID: "u1", Username: "alice", IsActive: true, Email: "alice@example.com"},
+			{UserID: "u2", Username: "bob", IsActive: true, Email: "bob@example.com"},
+			{UserID: "u3", Username: "carol", IsActive: true},
+			{UserID: "u4", Username: "dave", Email: "dave@example.com"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("CreateTeam: %v", err)
+	}
+
+	return f
+}
+
+// publish hands pending outbox messages to the notifier, as the outbox dispatcher does
+func (f *emailFixture) publish(t *testing.T) {
+	t.Helper()
+	ctx := context.Background()
+
+	messages, err := f.outbox.LockPending(ctx, time.Now(), 0)
+	if err != nil {
+		t.Fatalf("LockPending: %v", err)
+	}
+	for i := range messages {
+		if err := f.notifier.Handle(ctx, &messages[i]); err != nil {
+			t.Fatalf("Handle returned %v; notifications must never fail the outbox", err)
+		}
+		if err := f.outbox.MarkPublished(ctx, messages[i].ID, time.Now()); err != nil {
+			t.Fatalf("MarkPublished: %v", err)
+		}
+	}
+}
+
+// deliver runs the notifier until the queue is empty and returns the mails the server accepted, by recipient
+func (f *emailFixture) deliver(t *testing.T) map[string]parsedMail {
+	t.Helper()
+
+	for {
+		key, msg, _, ok := f.notifier.queue.take(time.Now())
+		if !ok {
+			break
+		}
+		if err := f.notifier.send(context.Background(), msg); err != nil {
+			t.Logf("send to %s: %v", msg.to.Address, err)
+		}
+		f.notifier.queue.release(key, time.Time{})
+	}
+
+	mails := make(map[string]parsedMail)
+	for {
+		select {
+		case m := <-f.server.received:
+			parsed := m.parse(t)
+			if len(parsed.to) != 1 {
+				t.Fatalf("recipients = %v, want one per email", parsed.to)
+			}
+			if _, dup := mails[parsed.to[0]]; dup {
+				t.Fatalf("second email to %s", parsed.to[0])
+			}
+			mails[parsed.to[0]] = parsed
+		default:
+			return mails
+		}
+	}
+}
+
+func (f *emailFixture) createPR(t *testing.T, id, name string) []string {
+	t.Helper()
+	resp, err := f.prs.CreatePR(context.Background(), &request.CreatePRRequest{
+		PullRequestID: id, PullRequestName: name, AuthorID: "u1",
+	})
+	if err != nil {
+		t.Fatalf("CreatePR: %v", err)
+	}
+	return resp.PR.AssignedReviewers
+}
+
+func TestAssignmentEmail(t *testing.T) {
+	f := newEmailFixture(t, newSMTPServer(t))
+
+	f.createPR(t, "pr-1", "Add <search> & filters")
+	f.publish(t)
+	mails := f.deliver(t)
+
+	// Both bob and carol are assigned, but only bob has an email
+	if len(mails) != 1 {
+		t.Fatalf("emails to %v, want only bob@example.com", keys(mails))
+	}
+	m, ok := mails["bob@example.com"]
+	if !ok {
+		t.Fatalf("no email to bob@example.com; got %v", keys(mails))
+	}
+
+	if m.from != "noreply@example.com" {
+		t.Errorf("MAIL FROM = %s, want noreply@example.com", m.from)
+	}
+	if got := m.header.Get("From"); got != `"PR Reviewer" <noreply@example.com>` {
+		t.Errorf("From = %q", got)
+	}
+	if got := m.header.Get("To"); got != `"bob" <bob@example.com>` {
+		t.Errorf("To = %q", got)
+	}
+	if m.header.Get("Message-ID") == "" || m.header.Get("Date") == "" {
+		t.Errorf("missing Message-ID or Date: %v", m.header)
+	}
+	if m.subject != "Review requested: Add <search> & filters" {
+		t.Errorf("subject = %q", m.subject)
+	}
+	if want := `alice would like you to review "Add <search> & filters" (pr-1).`; !strings.Contains(m.text, want) {
+		t.Errorf("text body %q does not contain %q", m.text, want)
+	}
+	if want := "<strong>Add &lt;search&gt; &amp; filters</strong>"; !strings.Contains(m.html, want) {
+		t.Errorf("HTML body %q does not contain escaped %q", m.html, want)
+	}
+	if !strings.Contains(m.text, "Hi bob,") || !strings.Contains(m.html, "Hi bob,") {
+		t.Errorf("bodies do not greet the recipient: %q / %q", m.text, m.html)
+	}
+}
+
+func TestReassignmentEmails(t *testing.T) {
+	f := newEmailFixture(t, newSMTPServer(t))
+	ctx := context.Background()
+
+	f.createPR(t, "pr-1", "Add search")
+	f.publish(t)
+	f.deliver(t)
+
+	// dave becomes the only spare reviewer for bob
+	if _, err := f.users.SetUserActive(ctx, &request.SetUserActiveRequest{UserID: "u4", IsActive: true}); err != nil {
+		t.Fatalf("SetUserActive: %v", err)
+	}
+	if _, err := f.prs.ReassignReviewer(ctx, &request.ReassignReviewerRequest{PullRequestID: "pr-1", OldUserID: "u2"}); err != nil {
+		t.Fatalf("ReassignReviewer: %v", err)
+	}
+	f.publish(t)
+	mails := f.deliver(t)
+
+	if len(mails) != 2 {
+		t.Fatalf("emails to %v, want dave@example.com and bob@example.com", keys(mails))
+	}
+	assigned := mails["dave@example.com"]
+	if assigned.subject != "Review requested: Add search" {
+		t.Errorf("new reviewer subject = %q", assigned.subject)
+	}
+	if want := `You now review "Add search" (pr-1) by alice instead of bob.`; !strings.Contains(assigned.text, want) {
+		t.Errorf("new reviewer text %q does not contain %q", assigned.text, want)
+	}
+
+	replaced := mails["bob@example.com"]
+	if replaced.subject != "Review reassigned: Add search" {
+		t.Errorf("replaced reviewer subject = %q", replaced.subject)
+	}
+	if want := "dave took it over"; !strings.Contains(replaced.text, want) || !strings.Contains(replaced.html, want) {
+		t.Errorf("replaced reviewer bodies %q / %q do not contain %q", replaced.text, replaced.html, want)
+	}
+}
+
+func TestMergeEmails(t *testing.T) {
+	f := newEmailFixture(t, newSMTPServer(t))
+
+	f.createPR(t, "pr-1", "Add search")
+	f.publish(t)
+	f.deliver(t)
+
+	if _, err := f.prs.MergePR(context.Background(), &request.MergePRRequest{PullRequestID: "pr-1"}); err != nil {
+		t.Fatalf("MergePR: %v", err)
+	}
+	f.publish(t)
+	mails := f.deliver(t)
+
+	// The author and bob get the email; carol has no address
+	if len(mails) != 2 {
+		t.Fatalf("emails to %v, want alice@example.com and bob@example.com", keys(mails))
+	}
+	for _, addr := range []string{"alice@example.com", "bob@example.com"} {
+		m := mails[addr]
+		if m.subject != "Merged: Add search" {
+			t.Errorf("%s: subject = %q", addr, m.subject)
+		}
+		if !strings.Contains(m.text, `"Add search" (pr-1) by alice was merged.`) || !strings.Contains(m.text, "Reviewed by ") {
+			t.Errorf("%s: text body %q", addr, m.text)
+		}
+	}
+	if !strings.Contains(mails["alice@example.com"].text, "carol") {
+		t.Errorf("reviewers without an email are still listed: %q", mails["alice@example.com"].text)
+	}
+}
+
+func TestSubjectCannotInjectHeaders(t *testing.T) {
+	f := newEmailFixture(t, newSMTPServer(t))
+
+	f.createPR(t, "pr-1", "Fix\r\nBcc: everyone@example.com")
+	f.publish(t)
+	mails := f.deliver(t)
+
+	m, ok := mails["bob@example.com"]
+	if !ok {
+		t.Fatalf("no email to bob@example.com; got %v", keys(mails))
+	}
+	if got := m.header.Get("Bcc"); got != "" {
+		t.Fatalf("PR name injected a Bcc header: %q", got)
+	}
+	if m.subject != "Review requested: Fix Bcc: everyone@example.com" {
+		t.Errorf("subject = %q", m.subject)
+	}
+}
+
+func TestEmailFailuresDoNotAffectPROperations(t *testing.T) {
+	server := newSMTPServer(t, "bob@example.com")
+	f := newEmailFixture(t, server)
+	ctx := context.Background()
+
+	f.createPR(t, "pr-1", "Add search")
+	f.publish(t)
+	if mails := f.deliver(t); len(mails) != 0 {
+		t.Fatalf("rejected recipient still received %v", keys(mails))
+	}
+
+	// With the SMTP server gone, events are still published and queued emails are dropped
+	server.listener.Close()
+	if _, err := f.prs.MergePR(ctx, &request.MergePRRequest{PullRequestID: "pr-1"}); err != nil {
+		t.Fatalf("MergePR: %v", err)
+	}
+	f.publish(t)
+
+	runCtx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		f.notifier.Run(runCtx)
+		close(done)
+	}()
+	deadline := time.Now().Add(5 * time.Second)
+	for f.notifier.queue.len() > 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	cancel()
+	<-done
+
+	if n := f.notifier.queue.len(); n != 0 {
+		t.Fatalf("queue still holds %d emails", n)
+	}
+	pending, err := f.outbox.LockPending(ctx, time.Now(), 0)
+	if err != nil || len(pending) != 0 {
+		t.Fatalf("pending outbox messages = %d, %v; want none", len(pending), err)
+	}
+}
+
+func TestSlowRecipientDoesNotBlockOthers(t *testing.T) {
+	server := newSMTPServer(t)
+	stall := make(chan struct{})
+	server.stall["bob@example.com"] = stall
+	release := sync.OnceFunc(func() { close(stall) })
+	// Runs before the server waits for its connections to end
+	t.Cleanup(release)
+	f := newEmailFixture(t, server)
+	ctx := context.Background()
+
+	// bob is asked to review first, then bob and alice are told about the merge
+	f.createPR(t, "pr-1", "Add search")
+	if _, err := f.prs.MergePR(ctx, &request.MergePRRequest{PullRequestID: "pr-1"}); err != nil {
+		t.Fatalf("MergePR: %v", err)
+	}
+	f.publish(t)
+
+	runCtx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		f.notifier.Run(runCtx)
+		close(done)
+	}()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	receive := func() parsedMail {
+		t.Helper()
+		select {
+		case m := <-server.received:
+			return m.parse(t)
+		case <-time.After(5 * time.Second):
+			t.Fatal("no email received")
+			return parsedMail{}
+		}
+	}
+
+	// alice's email goes out while bob's session hangs
+	if m := receive(); m.to[0] != "alice@example.com" {
+		t.Fatalf("first email went to %v, want alice", m.to)
+	}
+
+	// bob's emails follow in the order they were queued
+	release()
+	for _, want := range []string{"Review requested", "Merged"} {
+		if m := receive(); m.to[0] != "bob@example.com" || !strings.Contains(m.subject, want) {
+			t.Fatalf("email to %v %q, want %q to bob", m.to, m.subject, want)
+		}
+	}
+}
+
+func TestNewEmailNotifierRejectsInvalidSender(t *testing.T) {
+	if _, err := NewEmailNotifier(nil, EmailConfig{From: "not an address"}); err == nil {
+		t.Fatal("NewEmailNotifier accepted an invalid sender")
+	}
+}
+
+func keys(mails map[string]parsedMail) []string {
+	result := make([]string, 0, len(mails))
+	for addr := range mails {
+		result = append(result, addr)
+	}
+	return result
+}
@@ -0,0 +1,11 @@
+// Package notifier sends review notifications to team chats and by email
+package notifier
+
+import (
+	"encoding/json"
+)
+
+// outboxEvent is an outbox payload with the event data left undecoded
+type outboxEvent struct {
+	Data json.RawMessage `json:"data"`
+}
@@ -0,0 +1,172 @@
+package notifier
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// keyedQueue holds messages waiting to be sent to several destinations, such as webhook URLs
+// or email recipients. Each destination keeps its own FIFO order, and one that is being sent to
+// or must not be sent to yet does not hold back the others
+type keyedQueue[T any] struct {
+	mu       sync.Mutex
+	capacity int
+	size     int
+	seq      int64
+	pending  map[string][]queuedMessage[T]
+	// readyAt is the earliest time the next message may go to a destination
+	readyAt map[string]time.Time
+	// busy marks destinations a message is being sent to
+	busy map[string]bool
+	// wake is signalled when a message is pushed or a destination is released
+	wake chan struct{}
+}
+
+// queuedMessage is a message with its position in the queue, so the oldest ready one goes first
+type queuedMessage[T any] struct {
+	seq int64
+	msg T
+}
+
+func newKeyedQueue[T any](capacity int) *keyedQueue[T] {
+	return &keyedQueue[T]{
+		capacity: capacity,
+		pending:  make(map[string][]queuedMessage[T]),
+		readyAt:  make(map[string]time.Time),
+		busy:     make(map[string]bool),
+		wake:     make(chan struct{}, 1),
+	}
+}
+
+// push adds a message for the destination and reports false if the queue is full
+func (q *keyedQueue[T]) push(key string, msg T) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.size >= q.capacity {
+		return false
+	}
+	q.seq++
+	q.size++
+	q.pending[key] = append(q.pending[key], queuedMessage[T]{seq: q.seq, msg: msg})
+	q.signal()
+	return true
+}
+
+// take removes the oldest message whose destination is ready at now and marks the destination
+// busy until release. If none is ready, wait is how long until one is, or zero if every
+// queued message waits for a busy destination or the queue is empty
+func (q *keyedQueue[T]) take(now time.Time) (key string, msg T, wait time.Duration, ok bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for k, readyAt := range q.readyAt {
+		if _, queued := q.pending[k]; !queued && !readyAt.After(now) {
+			delete(q.readyAt, k)
+		}
+	}
+
+	var oldest int64
+	for k, messages := range q.pending {
+		if q.busy[k] {
+			continue
+		}
+		if readyAt := q.readyAt[k]; readyAt.After(now) {
+			if d := readyAt.Sub(now); wait == 0 || d < wait {
+				wait = d
+			}
+			continue
+		}
+		if !ok || messages[0].seq < oldest {
+			key, oldest, ok = k, messages[0].seq, true
+		}
+	}
+	if !ok {
+		return "", msg, wait, false
+	}
+
+	msg = q.pending[key][0].msg
+	if rest := q.pending[key][1:]; len(rest) > 0 {
+		q.pending[key] = rest
+	} else {
+		delete(q.pending, key)
+	}
+	q.size--
+	q.busy[key] = true
+	// Another sender may pick up the next ready destination
+	q.signal()
+	return key, msg, 0, true
+}
+
+// release marks the destination as no longer busy; its next message is held back until readyAt
+func (q *keyedQueue[T]) release(key string, readyAt time.Time) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	delete(q.busy, key)
+	if readyAt.IsZero() {
+		delete(q.readyAt, key)
+	} else {
+		q.readyAt[key] = readyAt
+	}
+	q.signal()
+}
+
+// len returns the number of queued messages
+func (q *keyedQueue[T]) len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.size
+}
+
+// signal wakes up one waiting sender without blocking
+func (q *keyedQueue[T]) signal() {
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+}
+
+// serve runs senders that take ready messages and hand them to send until ctx is cancelled
+// send returns the earliest time the next message may go to the same destination
+func (q *keyedQueue[T]) serve(ctx context.Context, senders int, now func() time.Time,
+	send func(ctx context.Context, key string, msg T) time.Time) {
+	var wg sync.WaitGroup
+	for i := 0; i < senders; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for ctx.Err() == nil {
+				key, msg, wait, ok := q.take(now())
+				if ok {
+					q.release(key, send(ctx, key, msg))
+					continue
+				}
+				if !q.wait(ctx, wait) {
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// wait blocks until the queue changes, wait passes (unless it is zero) or ctx is cancelled,
+// and reports false in the last case
+func (q *keyedQueue[T]) wait(ctx context.Context, wait time.Duration) bool {
+	var timeout <-chan time.Time
+	if wait > 0 {
+		timer := time.NewTimer(wait)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-q.wake:
+	case <-timeout:
+	}
+	return true
+}
@@ -0,0 +1,274 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"avito-backend-trainee-assignment-autumn-2025/internal/domain/models"
+	"avito-backend-trainee-assignment-autumn-2025/internal/repository"
+	"avito-backend-trainee-assignment-autumn-2025/pkg/logger"
+)
+
+// maxErrorBodySize limits how much of a failed response ends up in the log
+const maxErrorBodySize = 256
+
+// SlackConfig controls chat message sending
+type SlackConfig struct {
+	// QueueSize bounds the messages waiting to be sent; new messages are dropped while it is full
+	QueueSize int
+	// MinInterval is the minimum time between two messages to the same webhook URL
+	MinInterval time.Duration
+	// Timeout bounds a single HTTP request
+	Timeout time.Duration
+}
+
+// slackMessage is a message waiting to be posted to a team webhook
+type slackMessage struct {
+	team string
+	url  string
+	text string
+}
+
+// SlackNotifier posts assignment, reassignment and merge messages to the incoming webhook of the team
+// As an outbox sink it only formats and queues messages; Run posts them, at most one per
+// MinInterval per webhook. A webhook that is rate-limited does not hold back the others.
+// Notifications are best effort: a full queue or a failed post is logged and the message is
+// dropped, never retried through the outbox
+type SlackNotifier struct {
+	teamRepo repository.TeamRepository
+	userRepo repository.UserRepository
+	client   *http.Client
+	config   SlackConfig
+	// queue holds the messages by webhook URL
+	queue *keyedQueue[slackMessage]
+
+	// now is the clock used for rate limiting; replaced in tests
+	now func() time.Time
+}
+
+// NewSlackNotifier creates a new Slack notifier
+func NewSlackNotifier(teamRepo repository.TeamRepository, userRepo repository.UserRepository, config SlackConfig) *SlackNotifier {
+	if config.QueueSize <= 0 {
+		config.QueueSize = 1000
+	}
+
+	return &SlackNotifier{
+		teamRepo: teamRepo,
+		userRepo: userRepo,
+		client:   &http.Client{Timeout: config.Timeout},
+		config:   config,
+		queue:    newKeyedQueue[slackMessage](config.QueueSize),
+		now:      time.Now,
+	}
+}
+
+// Name identifies the notifier as an outbox sink
+func (n *SlackNotifier) Name() string {
+	return "slack"
+}
+
+// Handle queues a chat message for assignment, reassignment and merge events
+// It never fails: a notification must not hold back the other sinks
+func (n *SlackNotifier) Handle(ctx context.Context, msg *models.OutboxMessage) error {
+	var event outboxEvent
+	if err := json.Unmarshal(msg.Payload, &event); err != nil {
+		logger.Error("Skipping chat notification for outbox message %s: invalid payload: %v", msg.ID, err)
+		return nil
+	}
+
+	var err error
+	switch msg.EventType {
+	case models.EventReviewerAssigned:
+		var data models.ReviewerEventData
+		if err = json.Unmarshal(event.Data, &data); err == nil {
+			err = n.reviewerAssigned(ctx, data)
+		}
+	case models.EventPRMerged:
+		var data models.PRMergedEventData
+		if err = json.Unmarshal(event.Data, &data); err == nil {
+			err = n.prMerged(ctx, data)
+		}
+	default:
+		// reviewer.unassigned is reported together with the reviewer who took over
+		return nil
+	}
+
+	if err != nil {
+		logger.Error("Skipping chat notification for event %s (%s): %v", msg.ID, msg.EventType, err)
+	}
+	return nil
+}
+
+// reviewerAssigned notifies the reviewer's team about a new or replaced reviewer
+func (n *SlackNotifier) reviewerAssigned(ctx context.Context, data models.ReviewerEventData) error {
+	reviewer, err := n.userRepo.GetByID(ctx, data.ReviewerID)
+	if err != nil {
+		return fmt.Errorf("failed to get reviewer %s: %w", data.ReviewerID, err)
+	}
+
+	pr := formatPR(data.PullRequestName, data.PullRequestID)
+	var text string
+	if data.ReplacedReviewerID != "" {
+		text = fmt.Sprintf(":arrows_counterclockwise: %s, you now review %s instead of %s",
+			mention(reviewer), pr, n.name(ctx, data.ReplacedReviewerID))
+	} else {
+		text = fmt.Sprintf(":eyes: %s, please review %s by %s",
+			mention(reviewer), pr, n.name(ctx, data.AuthorID))
+	}
+
+	return n.enqueue(ctx, reviewer.TeamName, text)
+}
+
+// prMerged notifies the author's team that a PR was merged
+func (n *SlackNotifier) prMerged(ctx context.Context, data models.PRMergedEventData) error {
+	author, err := n.userRepo.GetByID(ctx, data.AuthorID)
+	if err != nil {
+		return fmt.Errorf("failed to get author %s: %w", data.AuthorID, err)
+	}
+
+	text := fmt.Sprintf(":white_check_mark: %s by %s was merged",
+		formatPR(data.PullRequestName, data.PullRequestID), mention(author))
+	if len(data.AssignedReviewers) > 0 {
+		reviewers := make([]string, 0, len(data.AssignedReviewers))
+		for _, id := range data.AssignedReviewers {
+			reviewers = append(reviewers, n.name(ctx, id))
+		}
+		text += ". Reviewed by " + strings.Join(reviewers, ", ")
+	}
+
+	return n.enqueue(ctx, author.TeamName, text)
+}
+
+// enqueue queues text for the team's webhook; teams without a webhook are skipped
+func (n *SlackNotifier) enqueue(ctx context.Context, teamName, text string) error {
+	team, err := n.teamRepo.GetByName(ctx, teamName)
+	if err != nil {
+		return fmt.Errorf("failed to get team %s: %w", teamName, err)
+	}
+	if team.ChatWebhookURL == "" {
+		return nil
+	}
+
+	if !n.queue.push(team.ChatWebhookURL, slackMessage{team: teamName, url: team.ChatWebhookURL, text: text}) {
+		logger.Warn("Chat notification queue is full, dropping message for team %s", teamName)
+	}
+	return nil
+}
+
+// name returns the username of a user who is mentioned in passing, or the ID if the user is gone
+func (n *SlackNotifier) name(ctx context.Context, userID string) string {
+	user, err := n.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return escape(userID)
+	}
+	return escape(user.Username)
+}
+
+// Run posts queued messages until ctx is cancelled
+// Messages to a webhook that is not ready yet are held back while the others are posted
+func (n *SlackNotifier) Run(ctx context.Context) {
+	logger.Info("Slack notifier started (min interval per webhook: %v)", n.config.MinInterval)
+
+	n.queue.serve(ctx, 1, n.now, func(ctx context.Context, _ string, msg slackMessage) time.Time {
+		return n.send(ctx, msg)
+	})
+	logger.Info("Slack notifier stopped")
+}
+
+// send posts a message and returns the earliest time the next one may go to the same webhook
+// Failures are logged and the message is dropped
+func (n *SlackNotifier) send(ctx context.Context, msg slackMessage) time.Time {
+	nextSend := n.now().Add(n.config.MinInterval)
+
+	retryAfter, err := n.post(ctx, msg)
+	if retryAfter > 0 {
+		// Slack answers 429 with the number of seconds to back off
+		nextSend = n.now().Add(retryAfter)
+	}
+	if err != nil {
+		if ctx.Err() == nil {
+			logger.Warn("Failed to post chat notification for team %s: %v", msg.team, err)
+		}
+		return nextSend
+	}
+
+	logger.Debug("Posted chat notification for team %s", msg.team)
+	return nextSend
+}
+
+// post sends the message as a Slack incoming webhook payload
+// For 429 responses it also returns the Retry-After delay
+func (n *SlackNotifier) post(ctx context.Context, msg slackMessage) (time.Duration, error) {
+	body, err := json.Marshal(map[string]string{"text": msg.text})
+	if err != nil {
+		return 0, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, msg.url, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("invalid request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "pr-reviewer-notifier")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		// The error text contains the URL, which embeds the webhook token
+		return 0, fmt.Errorf("request failed: %w", unwrapURLError(err))
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		// Drain the body so the connection can be reused
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return 0, nil
+	}
+
+	var retryAfter time.Duration
+	if resp.StatusCode == http.StatusTooManyRequests {
+		if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds > 0 {
+			retryAfter = time.Duration(seconds) * time.Second
+		}
+	}
+	text, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+	return retryAfter, fmt.Errorf("unexpected status %d: %s", resp.StatusCode, bytes.TrimSpace(text))
+}
+
+// formatPR renders a PR as its bold name followed by the ID
+func formatPR(name, id string) string {
+	return fmt.Sprintf("*%s* (`%s`)", escape(name), escape(id))
+}
+
+// mention renders a Slack mention of the user, or the username when no handle is set
+func mention(user *models.User) string {
+	if user.MentionHandle == "" {
+		return escape(user.Username)
+	}
+	return "<@" + user.MentionHandle + ">"
+}
+
+// slackEscaper escapes the characters that have a meaning in Slack message text
+var slackEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// escape makes user-provided text safe to embed in a Slack message
+func escape(s string) string {
+	return slackEscaper.Replace(s)
+}
+
+// unwrapURLError strips the request URL from a transport error
+func unwrapURLError(err error) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return urlErr.Err
+	}
+	return err
+}
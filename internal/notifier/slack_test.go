@@ -0,0 +1,343 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"avito-backend-trainee-assignment-autumn-2025/internal/repository/memory"
+	"avito-backend-trainee-assignment-autumn-2025/internal/service"
+	"avito-backend-trainee-assignment-autumn-2025/pkg/dto/request"
+)
+
+// receiver is an incoming webhook that answers with queued status codes (200 once they run out)
+type receiver struct {
+	mu       sync.Mutex
+	statuses []int
+	paths    []string
+	texts    []string
+}
+
+func (rc *receiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Text string `json:"text"`
+	}
+	_ = json.NewDecoder(r.Body).Decode(&body)
+
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	rc.paths = append(rc.paths, r.URL.Path)
+	rc.texts = append(rc.texts, body.Text)
+
+	status := http.StatusOK
+	if len(rc.statuses) > 0 {
+		status, rc.statuses = rc.statuses[0], rc.statuses[1:]
+	}
+	if status == http.StatusTooManyRequests {
+		w.Header().Set("Retry-After", "30")
+	}
+	w.WriteHeader(status)
+}
+
+func (rc *receiver) received() ([]string, []string) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	return append([]string(nil), rc.paths...), append([]string(nil), rc.texts...)
+}
+
+type fixture struct {
+	outbox   *memory.OutboxRepository
+	teams    *service.TeamServiceImpl
+	users    *service.UserServiceImpl
+	prs      *service.PRServiceImpl
+	notifier *SlackNotifier
+	receiver *receiver
+	url      string
+	clock    time.Time
+	waits    []time.Duration
+}
+
+// newFixture creates team "backend" with author u1, reviewers u2, u3 and inactive u4;
+// everyone but u3 has a mention handle and the team posts to <server>/backend
+func newFixture(t *testing.T, config SlackConfig) *fixture {
+	t.Helper()
+	ctx := context.Background()
+
+	rc := &receiver{}
+	server := httptest.NewServer(rc)
+	t.Cleanup(server.Close)
+
+	store := memory.NewStore()
+	teamRepo := memory.NewTeamRepository(store)
+	userRepo := memory.NewUserRepository(store)
+	absenceRepo := memory.NewAbsenceRepository(store)
+	affinityRepo := memory.NewAffinityRepository(store)
+	txManager := memory.NewTransactionManager(store)
+	outboxRepo := memory.NewOutboxRepository(store)
+
+	f := &fixture{
+		outbox:   outboxRepo,
+		teams:    service.NewTeamService(teamRepo, userRepo, absenceRepo, txManager, outboxRepo),
+		receiver: rc,
+		url:      server.URL,
+		clock:    time.Date(2025, 11, 1, 12, 0, 0, 0, time.UTC),
+	}
+	prRepo := memory.NewPRRepository(store)
+	f.users = service.NewUserService(userRepo, teamRepo, prRepo, absenceRepo, affinityRepo, txManager, f.outbox)
+	f.prs = service.NewPRService(prRepo, userRepo, teamRepo, absenceRepo, affinityRepo, txManager, f.outbox)
+	f.notifier = NewSlackNotifier(teamRepo, userRepo, config)
+	f.notifier.now = func() time.Time { return f.clock }
+
+	_, err := f.teams.CreateTeam(ctx, &request.CreateTeamRequest{
+		TeamName: "backend",
+		Members: []request.TeamMemberRequest{
+			{UserID: "u1", Username: "alice", IsActive: true, MentionHandle: "U0ALICE"},
+			{UserID: "u2", Username: "bob", IsActive: true, MentionHandle: "U0BOB"},
+			{UserID: "u3", Username: "carol", IsActive: true},
+			{UserID: "u4", Username: "dave", MentionHandle: "U0DAVE"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("CreateTeam: %v", err)
+	}
+	f.setWebhook(t, "backend", server.URL+"/backend")
+
+	return f
+}
+
+func (f *fixture) setWebhook(t *testing.T, team, url string) {
+	t.Helper()
+	_, err := f.teams.SetChatWebhook(context.Background(), &request.SetChatWebhookRequest{TeamName: team, WebhookURL: url})
+	if err != nil {
+		t.Fatalf("SetChatWebhook: %v", err)
+	}
+}
+
+// publish hands pending outbox messages to the notifier, as the outbox dispatcher does
+func (f *fixture) publish(t *testing.T) {
+	t.Helper()
+	ctx := context.Background()
+
+	messages, err := f.outbox.LockPending(ctx, time.Now(), 0)
+	if err != nil {
+		t.Fatalf("LockPending: %v", err)
+	}
+	for i := range messages {
+		if err := f.notifier.Handle(ctx, &messages[i]); err != nil {
+			t.Fatalf("Handle returned %v; notifications must never fail the outbox", err)
+		}
+		if err := f.outbox.MarkPublished(ctx, messages[i].ID, f.clock); err != nil {
+			t.Fatalf("MarkPublished: %v", err)
+		}
+	}
+}
+
+// drain sends every queued message as Run does, moving the clock on while no webhook is ready
+func (f *fixture) drain() {
+	for {
+		key, msg, wait, ok := f.notifier.queue.take(f.clock)
+		if ok {
+			f.notifier.queue.release(key, f.notifier.send(context.Background(), msg))
+			continue
+		}
+		if wait == 0 {
+			return
+		}
+		f.waits = append(f.waits, wait)
+		f.clock = f.clock.Add(wait)
+	}
+}
+
+func (f *fixture) createPR(t *testing.T, id, name string) {
+	t.Helper()
+	_, err := f.prs.CreatePR(context.Background(), &request.CreatePRRequest{PullRequestID: id, PullRequestName: name, AuthorID: "u1"})
+	if err != nil {
+		t.Fatalf("CreatePR: %v", err)
+	}
+}
+
+func defaultConfig() SlackConfig {
+	return SlackConfig{QueueSize: 10, MinInterval: time.Second, Timeout: 5 * time.Second}
+}
+
+func TestAssignmentMentionsReviewers(t *testing.T) {
+	f := newFixture(t, defaultConfig())
+	f.createPR(t, "pr-1", "Add <search> & filters")
+	f.publish(t)
+	f.drain()
+
+	paths, texts := f.receiver.received()
+	if len(texts) != 2 {
+		t.Fatalf("received %d messages, want one per reviewer: %v", len(texts), texts)
+	}
+	if paths[0] != "/backend" {
+		t.Errorf("posted to %s, want the team webhook", paths[0])
+	}
+
+	all := strings.Join(texts, "\n")
+	for _, want := range []string{
+		"<@U0BOB>, please review *Add &lt;search&gt; &amp; filters* (`pr-1`) by alice",
+		"carol, please review", // no handle: plain username
+	} {
+		if !strings.Contains(all, want) {
+			t.Errorf("messages %q do not contain %q", texts, want)
+		}
+	}
+}
+
+func TestReassignmentMentionsNewReviewer(t *testing.T) {
+	f := newFixture(t, defaultConfig())
+	ctx := context.Background()
+	f.createPR(t, "pr-1", "Add search")
+	f.publish(t)
+	f.drain()
+
+	// dave becomes the only candidate to replace carol
+	if _, err := f.users.SetUserActive(ctx, &request.SetUserActiveRequest{UserID: "u4", IsActive: true}); err != nil {
+		t.Fatalf("SetUserActive: %v", err)
+	}
+	if _, err := f.prs.ReassignReviewer(ctx, &request.ReassignReviewerRequest{PullRequestID: "pr-1", OldUserID: "u3"}); err != nil {
+		t.Fatalf("ReassignReviewer: %v", err)
+	}
+	f.publish(t)
+	f.drain()
+
+	// The unassignment is not announced separately
+	_, texts := f.receiver.received()
+	if len(texts) != 3 {
+		t.Fatalf("received %d messages, want 2 assignments and 1 reassignment: %q", len(texts), texts)
+	}
+	if got, want := texts[2], ":arrows_counterclockwise: <@U0DAVE>, you now review *Add search* (`pr-1`) instead of carol"; got != want {
+		t.Errorf("message = %q, want %q", got, want)
+	}
+}
+
+func TestMergeThanksReviewers(t *testing.T) {
+	f := newFixture(t, defaultConfig())
+	f.createPR(t, "pr-1", "Add search")
+	if _, err := f.prs.MergePR(context.Background(), &request.MergePRRequest{PullRequestID: "pr-1"}); err != nil {
+		t.Fatalf("MergePR: %v", err)
+	}
+	f.publish(t)
+	f.drain()
+
+	_, texts := f.receiver.received()
+	last := texts[len(texts)-1]
+	if !strings.HasPrefix(last, ":white_check_mark: *Add search* (`pr-1`) by <@U0ALICE> was merged. Reviewed by ") ||
+		!strings.Contains(last, "bob") || !strings.Contains(last, "carol") {
+		t.Errorf("merge message = %q", last)
+	}
+}
+
+func TestTeamWithoutWebhookIsSkipped(t *testing.T) {
+	f := newFixture(t, defaultConfig())
+	f.setWebhook(t, "backend", "")
+	f.createPR(t, "pr-1", "Add search")
+	f.publish(t)
+	f.drain()
+
+	if _, texts := f.receiver.received(); len(texts) != 0 {
+		t.Errorf("received %v, want nothing", texts)
+	}
+}
+
+func TestRateLimitedPerWebhook(t *testing.T) {
+	f := newFixture(t, defaultConfig())
+	f.createPR(t, "pr-1", "First")
+	f.createPR(t, "pr-2", "Second")
+	f.publish(t)
+	f.drain()
+
+	if _, texts := f.receiver.received(); len(texts) != 4 {
+		t.Fatalf("received %d messages, want 4", len(texts))
+	}
+	// The first message goes out at once, each next one waits for the interval
+	if len(f.waits) != 3 {
+		t.Fatalf("waits = %v, want 3", f.waits)
+	}
+	for _, wait := range f.waits {
+		if wait != time.Second {
+			t.Errorf("waited %v, want 1s", wait)
+		}
+	}
+}
+
+func TestRetryAfterIsRespected(t *testing.T) {
+	f := newFixture(t, defaultConfig())
+	f.receiver.statuses = []int{http.StatusTooManyRequests}
+	f.createPR(t, "pr-1", "Add search")
+	f.publish(t)
+	f.drain()
+
+	// The throttled message is dropped, the next one waits for Retry-After
+	if _, texts := f.receiver.received(); len(texts) != 2 {
+		t.Fatalf("received %d messages, want 2", len(texts))
+	}
+	if len(f.waits) != 1 || f.waits[0] != 30*time.Second {
+		t.Errorf("waits = %v, want [30s]", f.waits)
+	}
+}
+
+func TestRateLimitedWebhookDoesNotBlockOthers(t *testing.T) {
+	f := newFixture(t, defaultConfig())
+	ctx := context.Background()
+	f.receiver.statuses = []int{http.StatusTooManyRequests}
+
+	_, err := f.teams.CreateTeam(ctx, &request.CreateTeamRequest{
+		TeamName: "frontend",
+		Members: []request.TeamMemberRequest{
+			{UserID: "u5", Username: "erin", IsActive: true},
+			{UserID: "u6", Username: "frank", IsActive: true},
+		},
+	})
+	if err != nil {
+		t.Fatalf("CreateTeam: %v", err)
+	}
+	f.setWebhook(t, "frontend", f.url+"/frontend")
+
+	f.createPR(t, "pr-1", "Add search")
+	if _, err := f.prs.CreatePR(ctx, &request.CreatePRRequest{PullRequestID: "pr-2", PullRequestName: "Fix layout", AuthorID: "u5"}); err != nil {
+		t.Fatalf("CreatePR: %v", err)
+	}
+	f.publish(t)
+	f.drain()
+
+	// The backend webhook answers 429; the frontend message goes out before its Retry-After ends
+	paths, _ := f.receiver.received()
+	if strings.Join(paths, ",") != "/backend,/frontend,/backend" {
+		t.Fatalf("posted to %v, want /backend, /frontend, /backend", paths)
+	}
+	if len(f.waits) != 1 || f.waits[0] != 30*time.Second {
+		t.Errorf("waits = %v, want [30s]", f.waits)
+	}
+}
+
+func TestFailuresDoNotAffectPROperations(t *testing.T) {
+	f := newFixture(t, SlackConfig{QueueSize: 1, MinInterval: time.Second, Timeout: 5 * time.Second})
+	f.receiver.statuses = []int{http.StatusInternalServerError}
+
+	// Two reviewers but room for one message: the second is dropped
+	f.createPR(t, "pr-1", "Add search")
+	f.publish(t)
+	if n := f.notifier.queue.len(); n != 1 {
+		t.Fatalf("queued %d messages, want 1", n)
+	}
+	f.drain()
+
+	// The failed post is not retried and the PR can still be merged
+	if _, err := f.prs.MergePR(context.Background(), &request.MergePRRequest{PullRequestID: "pr-1"}); err != nil {
+		t.Fatalf("MergePR: %v", err)
+	}
+	f.publish(t)
+	f.drain()
+
+	_, texts := f.receiver.received()
+	if len(texts) != 2 || !strings.Contains(texts[1], "was merged") {
+		t.Errorf("received %q, want the failed assignment and the merge", texts)
+	}
+}
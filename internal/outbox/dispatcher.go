@@ -0,0 +1,225 @@
+// Package outbox publishes events recorded in the transactional outbox to sinks
+package outbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"avito-backend-trainee-assignment-autumn-2025/internal/domain/models"
+	"avito-backend-trainee-assignment-autumn-2025/internal/repository"
+	"avito-backend-trainee-assignment-autumn-2025/pkg/logger"
+)
+
+// cleanupInterval is how often published messages older than the retention are removed
+const cleanupInterval = time.Hour
+
+// defaultMaxAttempts is used when Config.MaxAttempts is not set
+const defaultMaxAttempts = 10
+
+// Sink receives outbox messages
+// A message may be handed to a sink more than once (at-least-once delivery), so sinks
+// should be idempotent or let their consumers deduplicate by message ID
+type Sink interface {
+	// Name identifies the sink in logs and configuration
+	Name() string
+	// Handle processes a message; it runs in a savepoint of the transaction that holds the
+	// message lock, so a failed sink rolls back only its own writes
+	Handle(ctx context.Context, msg *models.OutboxMessage) error
+}
+
+// Config controls how the outbox is polled
+type Config struct {
+	// PollInterval is how often pending messages are looked up
+	PollInterval time.Duration
+	// BatchSize is the maximum number of messages handled in one transaction
+	BatchSize int
+	// Retention is how long published messages are kept; zero keeps them forever
+	Retention time.Duration
+	// MaxAttempts is the number of attempts before a message is moved to the dead-letter state
+	MaxAttempts int
+	// InitialBackoff is the delay before the first retry; it doubles on every further retry
+	InitialBackoff time.Duration
+	// MaxBackoff caps the retry delay
+	MaxBackoff time.Duration
+}
+
+// Dispatcher reads pending outbox messages and hands them to every sink
+type Dispatcher struct {
+	repo      repository.OutboxRepository
+	txManager repository.TransactionManager
+	sinks     []Sink
+	config    Config
+
+	// now is the clock used for published_at and retries; replaced in tests
+	now func() time.Time
+
+	lastCleanup time.Time
+}
+
+// NewDispatcher creates a new outbox dispatcher
+func NewDispatcher(repo repository.OutboxRepository, txManager repository.TransactionManager, sinks []Sink, config Config) *Dispatcher {
+	if config.BatchSize <= 0 {
+		config.BatchSize = 100
+	}
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = defaultMaxAttempts
+	}
+
+	return &Dispatcher{
+		repo:      repo,
+		txManager: txManager,
+		sinks:     sinks,
+		config:    config,
+		now:       time.Now,
+	}
+}
+
+// Run publishes pending messages until ctx is cancelled
+func (d *Dispatcher) Run(ctx context.Context) {
+	logger.Info("Outbox dispatcher started (sinks: %v, max attempts: %d)", d.sinkNames(), d.config.MaxAttempts)
+
+	ticker := time.NewTicker(d.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		// Drain the backlog before waiting for the next tick; failed messages wait for their next attempt
+		for {
+			published, err := d.processBatch(ctx)
+			if err != nil || published < d.config.BatchSize || ctx.Err() != nil {
+				break
+			}
+		}
+		d.cleanup(ctx)
+
+		select {
+		case <-ctx.Done():
+			logger.Info("Outbox dispatcher stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// processBatch locks a batch of due messages, hands each to the sinks and records the outcome,
+// all in one transaction; it returns the number of messages published
+func (d *Dispatcher) processBatch(ctx context.Context) (int, error) {
+	var published int
+	err := d.txManager.WithTransaction(ctx, func(txCtx context.Context) error {
+		published = 0
+		messages, err := d.repo.LockPending(txCtx, d.now(), d.config.BatchSize)
+		if err != nil {
+			return err
+		}
+
+		for i := range messages {
+			ok, err := d.publish(txCtx, &messages[i])
+			if err != nil {
+				return err
+			}
+			if ok {
+				published++
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		if ctx.Err() == nil {
+			logger.Error("Failed to process outbox batch: %v", err)
+		}
+		return 0, err
+	}
+
+	return published, nil
+}
+
+// publish hands a message to every sink that has not handled it yet and reports whether it was published
+// Each sink runs in its own savepoint, so a failing sink neither undoes the work of the others nor
+// aborts the batch transaction. The message is marked published once all sinks succeed; otherwise
+// the succeeded sinks are remembered and the rest are retried with backoff until MaxAttempts,
+// after which the message is dead-lettered
+func (d *Dispatcher) publish(ctx context.Context, msg *models.OutboxMessage) (bool, error) {
+	var errs []error
+	for _, sink := range d.sinks {
+		if msg.HasPublishedTo(sink.Name()) {
+			continue
+		}
+		err := d.txManager.WithTransaction(ctx, func(sinkCtx context.Context) error {
+			return sink.Handle(sinkCtx, msg)
+		})
+		if err != nil {
+			logger.Warn("Outbox sink %s failed for message %s (%s): %v", sink.Name(), msg.ID, msg.EventType, err)
+			errs = append(errs, fmt.Errorf("%s: %w", sink.Name(), err))
+			continue
+		}
+		msg.PublishedSinks = append(msg.PublishedSinks, sink.Name())
+	}
+	if ctx.Err() != nil {
+		// Shutting down: the rollback leaves the message pending for the next start
+		return false, ctx.Err()
+	}
+
+	if len(errs) == 0 {
+		logger.Debug("Published outbox message %s (%s)", msg.ID, msg.EventType)
+		return true, d.repo.MarkPublished(ctx, msg.ID, d.now())
+	}
+
+	msg.Attempts++
+	msg.LastError = errors.Join(errs...).Error()
+	if msg.Attempts >= d.config.MaxAttempts {
+		msg.Status = models.OutboxStatusFailed
+		logger.Warn("Outbox message %s (%s) failed after %d attempts, moved to dead letters: %s",
+			msg.ID, msg.EventType, msg.Attempts, msg.LastError)
+	} else {
+		delay := d.backoff(msg.Attempts)
+		msg.NextAttemptAt = d.now().Add(delay)
+		logger.Warn("Outbox message %s (%s) failed (attempt %d/%d), retrying in %v",
+			msg.ID, msg.EventType, msg.Attempts, d.config.MaxAttempts, delay)
+	}
+
+	return false, d.repo.MarkFailed(ctx, msg)
+}
+
+// backoff returns the delay after the given number of failed attempts:
+// InitialBackoff, then doubled each time, capped at MaxBackoff
+func (d *Dispatcher) backoff(attempts int) time.Duration {
+	delay := d.config.InitialBackoff
+	for i := 1; i < attempts && delay < d.config.MaxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > d.config.MaxBackoff {
+		delay = d.config.MaxBackoff
+	}
+	return delay
+}
+
+// cleanup removes old published messages at most once per cleanupInterval
+func (d *Dispatcher) cleanup(ctx context.Context) {
+	if d.config.Retention <= 0 || ctx.Err() != nil {
+		return
+	}
+	now := d.now()
+	if now.Sub(d.lastCleanup) < cleanupInterval {
+		return
+	}
+	d.lastCleanup = now
+
+	deleted, err := d.repo.DeletePublishedBefore(ctx, now.Add(-d.config.Retention))
+	if err != nil {
+		logger.Error("Failed to clean up the outbox: %v", err)
+		return
+	}
+	if deleted > 0 {
+		logger.Info("Removed %d published outbox message(s)", deleted)
+	}
+}
+
+// sinkNames returns the names of the configured sinks
+func (d *Dispatcher) sinkNames() []string {
+	names := make([]string, len(d.sinks))
+	for i, sink := range d.sinks {
+		names[i] = sink.Name()
+	}
+	return names
+}
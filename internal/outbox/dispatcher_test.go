@@ -0,0 +1,334 @@
+package outbox
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"avito-backend-trainee-assignment-autumn-2025/internal/domain/models"
+	"avito-backend-trainee-assignment-autumn-2025/internal/repository/memory"
+)
+
+// recordingSink remembers handled message IDs and fails while fail is set
+type recordingSink struct {
+	name string
+	mu   sync.Mutex
+	ids  []string
+	fail bool
+}
+
+func (s *recordingSink) Name() string {
+	if s.name == "" {
+		return "recording"
+	}
+	return s.name
+}
+
+func (s *recordingSink) Handle(_ context.Context, msg *models.OutboxMessage) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.fail {
+		return errors.New("sink is down")
+	}
+	s.ids = append(s.ids, msg.ID)
+	return nil
+}
+
+func (s *recordingSink) handled() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.ids...)
+}
+
+type fixture struct {
+	repo *memory.OutboxRepository
+	tx   *memory.TransactionManager
+}
+
+func newFixture() fixture {
+	store := memory.NewStore()
+	return fixture{
+		repo: memory.NewOutboxRepository(store),
+		tx:   memory.NewTransactionManager(store),
+	}
+}
+
+func (f fixture) dispatcher(sinks ...Sink) *Dispatcher {
+	return NewDispatcher(f.repo, f.tx, sinks, Config{
+		PollInterval:   time.Hour,
+		BatchSize:      2,
+		Retention:      time.Hour,
+		MaxAttempts:    3,
+		InitialBackoff: time.Minute,
+		MaxBackoff:     90 * time.Second,
+	})
+}
+
+// add records n events and returns their IDs in order
+func (f fixture) add(t *testing.T, n int) []string {
+	t.Helper()
+	ids := make([]string, n)
+	for i := range ids {
+		msg, err := models.NewOutboxMessage(models.NewEvent(models.EventPRMerged, models.PRMergedEventData{PullRequestID: "pr"}))
+		if err != nil {
+			t.Fatalf("NewOutboxMessage: %v", err)
+		}
+		if err := f.repo.Add(context.Background(), msg); err != nil {
+			t.Fatalf("Add: %v", err)
+		}
+		ids[i] = msg.ID
+	}
+	return ids
+}
+
+// pending returns all pending messages, including those whose next attempt is not due yet
+func (f fixture) pending(t *testing.T) []models.OutboxMessage {
+	t.Helper()
+	messages, err := f.repo.LockPending(context.Background(), time.Now().Add(24*time.Hour), 0)
+	if err != nil {
+		t.Fatalf("LockPending: %v", err)
+	}
+	return messages
+}
+
+func TestDispatcherPublishesInOrder(t *testing.T) {
+	f := newFixture()
+	ids := f.add(t, 3)
+	sink := &recordingSink{}
+	d := f.dispatcher(sink)
+	ctx := context.Background()
+
+	// The batch size is 2
+	if published, err := d.processBatch(ctx); err != nil || published != 2 {
+		t.Fatalf("first batch = %d, %v", published, err)
+	}
+	if published, err := d.processBatch(ctx); err != nil || published != 1 {
+		t.Fatalf("second batch = %d, %v", published, err)
+	}
+
+	if got := sink.handled(); strings.Join(got, ",") != strings.Join(ids, ",") {
+		t.Fatalf("handled = %v, want %v", got, ids)
+	}
+	if pending := f.pending(t); len(pending) != 0 {
+		t.Fatalf("pending = %+v, want none", pending)
+	}
+}
+
+func TestDispatcherRetriesOnlyFailedSinks(t *testing.T) {
+	f := newFixture()
+	ids := f.add(t, 1)
+	healthy := &recordingSink{name: "healthy"}
+	flaky := &recordingSink{name: "flaky", fail: true}
+	d := f.dispatcher(healthy, flaky)
+	ctx := context.Background()
+
+	now := time.Now()
+	d.now = func() time.Time { return now }
+
+	if published, err := d.processBatch(ctx); err != nil || published != 0 {
+		t.Fatalf("batch = %d, %v", published, err)
+	}
+	pending := f.pending(t)
+	if len(pending) != 1 || pending[0].Attempts != 1 || !strings.Contains(pending[0].LastError, "sink is down") {
+		t.Fatalf("pending = %+v, want one failed attempt", pending)
+	}
+	if got := pending[0].PublishedSinks; len(got) != 1 || got[0] != "healthy" {
+		t.Fatalf("published sinks = %v, want [healthy]", got)
+	}
+	if want := now.Add(time.Minute); !pending[0].NextAttemptAt.Equal(want) {
+		t.Fatalf("next attempt at %v, want %v", pending[0].NextAttemptAt, want)
+	}
+
+	// The retry is not due yet
+	flaky.fail = false
+	if published, err := d.processBatch(ctx); err != nil || published != 0 {
+		t.Fatalf("batch before backoff = %d, %v", published, err)
+	}
+
+	now = now.Add(time.Minute)
+	if published, err := d.processBatch(ctx); err != nil || published != 1 {
+		t.Fatalf("batch = %d, %v", published, err)
+	}
+
+	// The healthy sink is not handed the message again
+	if got := healthy.handled(); len(got) != 1 || got[0] != ids[0] {
+		t.Fatalf("healthy sink handled %v", got)
+	}
+	if got := flaky.handled(); len(got) != 1 || got[0] != ids[0] {
+		t.Fatalf("flaky sink handled %v", got)
+	}
+	if pending := f.pending(t); len(pending) != 0 {
+		t.Fatalf("pending = %+v, want none", pending)
+	}
+}
+
+func TestDispatcherDeadLettersAfterMaxAttempts(t *testing.T) {
+	f := newFixture()
+	f.add(t, 1)
+	d := f.dispatcher(&recordingSink{fail: true})
+	ctx := context.Background()
+
+	now := time.Now()
+	d.now = func() time.Time { return now }
+
+	delays := []time.Duration{time.Minute, 90 * time.Second}
+	for attempt, delay := range delays {
+		if _, err := d.processBatch(ctx); err != nil {
+			t.Fatalf("attempt %d: %v", attempt+1, err)
+		}
+		pending := f.pending(t)
+		if len(pending) != 1 || !pending[0].NextAttemptAt.Equal(now.Add(delay)) {
+			t.Fatalf("after attempt %d pending = %+v, want retry in %v", attempt+1, pending, delay)
+		}
+		now = now.Add(delay)
+	}
+
+	// The third attempt is the last one
+	if _, err := d.processBatch(ctx); err != nil {
+		t.Fatalf("last attempt: %v", err)
+	}
+	if pending := f.pending(t); len(pending) != 0 {
+		t.Fatalf("pending = %+v, want the message dead-lettered", pending)
+	}
+}
+
+// failingWriteSink adds a message to the outbox and then fails
+type failingWriteSink struct {
+	repo *memory.OutboxRepository
+}
+
+func (s *failingWriteSink) Name() string { return "failing-write" }
+
+func (s *failingWriteSink) Handle(ctx context.Context, msg *models.OutboxMessage) error {
+	written := &models.OutboxMessage{ID: "written-by-" + msg.ID, EventType: msg.EventType, Payload: msg.Payload}
+	if err := s.repo.Add(ctx, written); err != nil {
+		return err
+	}
+	return errors.New("sink failed after writing")
+}
+
+func TestFailedSinkWritesAreRolledBack(t *testing.T) {
+	f := newFixture()
+	ids := f.add(t, 2)
+	healthy := &recordingSink{}
+	d := f.dispatcher(&failingWriteSink{repo: f.repo}, healthy)
+
+	if published, err := d.processBatch(context.Background()); err != nil || published != 0 {
+		t.Fatalf("batch = %d, %v", published, err)
+	}
+
+	// Both messages are processed in the batch; only the failed sink's writes are undone
+	if got := healthy.handled(); strings.Join(got, ",") != strings.Join(ids, ",") {
+		t.Fatalf("healthy sink handled %v, want %v", got, ids)
+	}
+	pending := f.pending(t)
+	if len(pending) != 2 {
+		t.Fatalf("pending = %+v, want the two failed messages only", pending)
+	}
+	for _, msg := range pending {
+		if msg.Attempts != 1 {
+			t.Fatalf("message %s has %d attempts, want 1", msg.ID, msg.Attempts)
+		}
+	}
+}
+
+func TestConcurrentDispatchersPublishOnce(t *testing.T) {
+	f := newFixture()
+	ids := f.add(t, 20)
+	sink := &recordingSink{}
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	for range 4 {
+		d := f.dispatcher(sink)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				if published, err := d.processBatch(ctx); err != nil || published == 0 {
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	seen := make(map[string]int)
+	for _, id := range sink.handled() {
+		seen[id]++
+	}
+	for _, id := range ids {
+		if seen[id] != 1 {
+			t.Fatalf("message %s handled %d times", id, seen[id])
+		}
+	}
+}
+
+func TestDispatcherCleanup(t *testing.T) {
+	f := newFixture()
+	f.add(t, 1)
+	d := f.dispatcher(&recordingSink{})
+	ctx := context.Background()
+
+	now := time.Now()
+	d.now = func() time.Time { return now }
+	if _, err := d.processBatch(ctx); err != nil {
+		t.Fatalf("processBatch: %v", err)
+	}
+
+	// Still within the retention
+	d.cleanup(ctx)
+	if deleted, _ := f.repo.DeletePublishedBefore(ctx, now.Add(-time.Second)); deleted != 0 {
+		t.Fatalf("deleted = %d before the retention elapsed", deleted)
+	}
+
+	now = now.Add(2 * time.Hour)
+	d.cleanup(ctx)
+	if deleted, _ := f.repo.DeletePublishedBefore(ctx, now); deleted != 0 {
+		t.Fatalf("cleanup left %d published message(s)", deleted)
+	}
+}
+
+func TestWriterAndFileSinks(t *testing.T) {
+	msg, err := models.NewOutboxMessage(models.NewEvent(models.EventUserDeactivated, models.UserEventData{UserID: "u1"}))
+	if err != nil {
+		t.Fatalf("NewOutboxMessage: %v", err)
+	}
+	ctx := context.Background()
+
+	var buf bytes.Buffer
+	if err := NewWriterSink("buffer", &buf).Handle(ctx, msg); err != nil {
+		t.Fatalf("WriterSink: %v", err)
+	}
+	if buf.String() != string(msg.Payload)+"\n" {
+		t.Fatalf("written = %q", buf.String())
+	}
+
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+	fileSink, err := NewFileSink(path)
+	if err != nil {
+		t.Fatalf("NewFileSink: %v", err)
+	}
+	for range 2 {
+		if err := fileSink.Handle(ctx, msg); err != nil {
+			t.Fatalf("FileSink: %v", err)
+		}
+	}
+	if err := fileSink.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	if lines := strings.Split(strings.TrimSpace(string(data)), "\n"); len(lines) != 2 || lines[0] != string(msg.Payload) {
+		t.Fatalf("file = %q", data)
+	}
+}
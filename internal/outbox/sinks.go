@@ -0,0 +1,77 @@
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"avito-backend-trainee-assignment-autumn-2025/internal/domain/models"
+)
+
+// WriterSink writes every message as one JSON line
+type WriterSink struct {
+	name string
+
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewStdoutSink creates a sink that prints messages to standard output
+func NewStdoutSink() *WriterSink {
+	return NewWriterSink("stdout", os.Stdout)
+}
+
+// NewWriterSink creates a sink that writes messages to w
+func NewWriterSink(name string, w io.Writer) *WriterSink {
+	return &WriterSink{name: name, w: w}
+}
+
+// Name returns the sink name
+func (s *WriterSink) Name() string {
+	return s.name
+}
+
+// Handle writes the event JSON followed by a newline
+func (s *WriterSink) Handle(_ context.Context, msg *models.OutboxMessage) error {
+	line := make([]byte, 0, len(msg.Payload)+1)
+	line = append(append(line, msg.Payload...), '\n')
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err := s.w.Write(line)
+	return err
+}
+
+// FileSink appends messages as JSON lines to a file and syncs it after every write
+type FileSink struct {
+	*WriterSink
+	file *os.File
+}
+
+// NewFileSink opens (creating if needed) the file messages are appended to
+func NewFileSink(path string) (*FileSink, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open outbox file %s: %w", path, err)
+	}
+
+	return &FileSink{
+		WriterSink: NewWriterSink("file", file),
+		file:       file,
+	}, nil
+}
+
+// Handle appends the message and flushes it to disk before the message is marked published
+func (s *FileSink) Handle(ctx context.Context, msg *models.OutboxMessage) error {
+	if err := s.WriterSink.Handle(ctx, msg); err != nil {
+		return err
+	}
+	return s.file.Sync()
+}
+
+// Close closes the file
+func (s *FileSink) Close() error {
+	return s.file.Close()
+}
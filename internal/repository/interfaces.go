@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"avito-backend-trainee-assignment-autumn-2025/internal/domain/models"
 )
@@ -11,6 +12,25 @@ type TeamRepository interface {
 	Create(ctx context.Context, team *models.Team) error
 	GetByName(ctx context.Context, name string) (*models.Team, error)
 	Exists(ctx context.Context, name string) (bool, error)
+	// SetChatWebhook sets the incoming webhook URL for team notifications; an empty URL disables them
+	SetChatWebhook(ctx context.Context, name, url string) error
+	// SetReviewSLA sets the minutes reviewers have (0 means the default) and what happens once they run out
+	SetReviewSLA(ctx context.Context, name string, minutes int, action models.SLAAction) error
+	// SetLead sets the team lead; an empty leadID removes the lead
+	SetLead(ctx context.Context, name, leadID string) error
+	// SetCalendar sets the working calendar used to count the review SLA
+	SetCalendar(ctx context.Context, name string, calendar models.TeamCalendar) error
+	// SetWorkingHoursPreference sets whether reviewers inside their working hours are picked first
+	// and how many hours ahead a working day may start to count
+	SetWorkingHoursPreference(ctx context.Context, name string, enabled bool, withinHours int) error
+	// SetSeniorityPolicy sets the level at least one reviewer of a PR must have; an empty level removes the policy
+	SetSeniorityPolicy(ctx context.Context, name string, level models.Seniority) error
+	// ReplaceCodeOwners replaces the CODEOWNERS rules of the team, keeping their order
+	ReplaceCodeOwners(ctx context.Context, name string, rules []models.CodeOwnerRule) error
+	// ListCodeOwners returns the CODEOWNERS rules of the team in file order
+	ListCodeOwners(ctx context.Context, name string) ([]models.CodeOwnerRule, error)
+	// ListNames returns the names of all teams in alphabetical order
+	ListNames(ctx context.Context) ([]string, error)
 }
 
 // UserRepository defines methods for working with users
@@ -20,6 +40,31 @@ type UserRepository interface {
 	GetByID(ctx context.Context, id string) (*models.User, error)
 	GetByTeamName(ctx context.Context, teamName string) ([]models.User, error)
 	SetActive(ctx context.Context, userID string, isActive bool) error
+	SetMentionHandle(ctx context.Context, userID, handle string) error
+	SetEmail(ctx context.Context, userID, email string) error
+	// SetWorkingHours sets the user's timezone and working hours; an empty timezone means the team's
+	SetWorkingHours(ctx context.Context, userID, timezone string, hours models.WorkingHours) error
+	// SetMaxOpenReviews sets how many open reviews the user may have at once; 0 removes the limit
+	SetMaxOpenReviews(ctx context.Context, userID string, limit int) error
+	// SetSkillTags replaces the user's skill tags
+	SetSkillTags(ctx context.Context, userID string, tags []string) error
+	// SetSeniority sets the user's reviewer level; an empty level clears it
+	SetSeniority(ctx context.Context, userID string, level models.Seniority) error
+	// LockForUpdate locks the given users until the surrounding transaction ends, so that
+	// transactions assigning reviews to them run one after another; unknown IDs are ignored
+	LockForUpdate(ctx context.Context, userIDs []string) error
+}
+
+// AbsenceRepository defines methods for working with user absences
+type AbsenceRepository interface {
+	Create(ctx context.Context, absence *models.Absence) error
+	Delete(ctx context.Context, id string) error
+	// DeleteImported removes the user's absences imported from a calendar and returns how many were removed
+	DeleteImported(ctx context.Context, userID string) (int, error)
+	// ListByUser returns all absences of a user ordered by start date
+	ListByUser(ctx context.Context, userID string) ([]models.Absence, error)
+	// ListByTeam returns the absences of the team's members that end on or after from, ordered by start date
+	ListByTeam(ctx context.Context, teamName string, from time.Time) ([]models.Absence, error)
 }
 
 // PRRepository defines methods for working with pull requests
@@ -32,4 +77,81 @@ type PRRepository interface {
 	AddReviewer(ctx context.Context, prID, reviewerID string) error
 	RemoveReviewer(ctx context.Context, prID, reviewerID string) error
 	GetPRsByReviewerID(ctx context.Context, reviewerID string) ([]models.PullRequest, error)
+	// CountOpenReviews returns how many open PRs each of the given users reviews; users without any are omitted
+	CountOpenReviews(ctx context.Context, reviewerIDs []string) (map[string]int, error)
+	// ListStaleReviews returns active members of the team reviewing open PRs who were assigned
+	// (or last reminded) no later than before; oldest assignments first
+	ListStaleReviews(ctx context.Context, teamName string, before time.Time, limit int) ([]models.StaleReview, error)
+	// MarkReviewReminded records when a reviewer was reminded about a PR
+	MarkReviewReminded(ctx context.Context, prID, reviewerID string, at time.Time) error
+	// AddReassignment records a reviewer replacement in the PR history
+	AddReassignment(ctx context.Context, reassignment *models.Reassignment) error
+	// ListReassignments returns the reviewer replacements of a PR, oldest first
+	ListReassignments(ctx context.Context, prID string) ([]models.Reassignment, error)
+}
+
+// WebhookRepository defines methods for working with webhooks and their deliveries
+type WebhookRepository interface {
+	Create(ctx context.Context, webhook *models.Webhook) error
+	GetByID(ctx context.Context, id string) (*models.Webhook, error)
+	List(ctx context.Context) ([]models.Webhook, error)
+
+	CreateDelivery(ctx context.Context, delivery *models.WebhookDelivery) error
+	GetDelivery(ctx context.Context, id string) (*models.WebhookDelivery, error)
+	// ListDeliveries returns the newest deliveries first; an empty status matches all
+	ListDeliveries(ctx context.Context, status models.DeliveryStatus, limit int) ([]models.WebhookDelivery, error)
+	// ClaimDueDeliveries returns up to limit pending deliveries whose next attempt is not after now,
+	// oldest first, and leases them until lockedUntil; deliveries leased by someone else are skipped
+	ClaimDueDeliveries(ctx context.Context, now, lockedUntil time.Time, limit int) ([]models.WebhookDelivery, error)
+	// UpdateDelivery stores the delivery state and releases its lease
+	UpdateDelivery(ctx context.Context, delivery *models.WebhookDelivery) error
+}
+
+// OutboxRepository defines methods for the transactional outbox
+type OutboxRepository interface {
+	// Add stores a message; pass the context of the transaction that made the change
+	Add(ctx context.Context, msg *models.OutboxMessage) error
+	// LockPending returns up to limit pending messages whose next attempt is due at now, in insertion
+	// order, and locks them until the surrounding transaction ends; messages locked by another
+	// transaction are skipped, dead-lettered ones are never returned
+	LockPending(ctx context.Context, now time.Time, limit int) ([]models.OutboxMessage, error)
+	// MarkPublished sets the published status and time of a message
+	MarkPublished(ctx context.Context, id string, publishedAt time.Time) error
+	// MarkFailed stores the state of a message after a failed attempt: attempts, last error,
+	// sinks that already published it, next attempt time and status (pending or failed)
+	MarkFailed(ctx context.Context, msg *models.OutboxMessage) error
+	// DeletePublishedBefore removes messages published before the given time and returns how many were removed
+	DeletePublishedBefore(ctx context.Context, before time.Time) (int64, error)
+}
+
+// CodeHostAccountRepository defines methods for mapping code host logins to users
+type CodeHostAccountRepository interface {
+	// Link creates or replaces the mapping of a login
+	Link(ctx context.Context, account *models.CodeHostAccount) error
+	GetUserID(ctx context.Context, provider models.CodeHost, login string) (string, error)
+	// GetLogin returns the most recently linked login of a user
+	GetLogin(ctx context.Context, provider models.CodeHost, userID string) (string, error)
+	// List returns mappings ordered by provider and login; an empty provider matches all
+	List(ctx context.Context, provider models.CodeHost) ([]models.CodeHostAccount, error)
+}
+
+// AffinityRepository defines methods for author/reviewer affinities
+type AffinityRepository interface {
+	// Set creates or replaces the affinity of a pair
+	Set(ctx context.Context, affinity *models.Affinity) error
+	Delete(ctx context.Context, authorID, reviewerID string) error
+	// ListByUser returns the affinities where the user is the author or the reviewer,
+	// ordered by author and reviewer
+	ListByUser(ctx context.Context, userID string) ([]models.Affinity, error)
+}
+
+// ReviewerSyncRepository defines methods for reviewer changes pending on code hosts
+type ReviewerSyncRepository interface {
+	Create(ctx context.Context, sync *models.ReviewerSync) error
+	// ClaimDue returns up to limit pending syncs whose next attempt is not after now, oldest first,
+	// and leases them until lockedUntil; syncs leased by someone else are skipped, and a sync is
+	// only returned once no earlier sync of the same PR is pending
+	ClaimDue(ctx context.Context, now, lockedUntil time.Time, limit int) ([]models.ReviewerSync, error)
+	// Update stores the sync state and releases its lease
+	Update(ctx context.Context, sync *models.ReviewerSync) error
 }
@@ -0,0 +1,120 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"avito-backend-trainee-assignment-autumn-2025/internal/domain/models"
+	pkgerrors "avito-backend-trainee-assignment-autumn-2025/pkg/errors"
+	"avito-backend-trainee-assignment-autumn-2025/pkg/logger"
+)
+
+// AbsenceRepository implements repository.AbsenceRepository in memory
+type AbsenceRepository struct {
+	store *Store
+}
+
+// NewAbsenceRepository creates a new absence repository
+func NewAbsenceRepository(store *Store) *AbsenceRepository {
+	return &AbsenceRepository{store: store}
+}
+
+// Create stores a new absence
+func (r *AbsenceRepository) Create(ctx context.Context, absence *models.Absence) error {
+	err := r.store.write(ctx, func(st *state) error {
+		if _, exists := st.users[absence.UserID]; !exists {
+			return pkgerrors.ErrUserNotFound
+		}
+		st.absences[absence.ID] = *absence
+		return nil
+	})
+	if err != nil {
+		logger.Error("Failed to create absence for user %s: %v", absence.UserID, err)
+		return err
+	}
+
+	logger.Info("Created absence %s for user %s", absence.ID, absence.UserID)
+	return nil
+}
+
+// Delete removes an absence
+func (r *AbsenceRepository) Delete(ctx context.Context, id string) error {
+	err := r.store.write(ctx, func(st *state) error {
+		if _, exists := st.absences[id]; !exists {
+			return pkgerrors.ErrAbsenceNotFound
+		}
+		delete(st.absences, id)
+		return nil
+	})
+	if err != nil {
+		logger.Error("Failed to delete absence %s: %v", id, err)
+		return err
+	}
+
+	logger.Info("Deleted absence %s", id)
+	return nil
+}
+
+// DeleteImported removes the user's absences imported from a calendar
+func (r *AbsenceRepository) DeleteImported(ctx context.Context, userID string) (int, error) {
+	deleted := 0
+	err := r.store.write(ctx, func(st *state) error {
+		for id, absence := range st.absences {
+			if absence.UserID == userID && absence.SourceUID != "" {
+				delete(st.absences, id)
+				deleted++
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		logger.Error("Failed to delete imported absences of user %s: %v", userID, err)
+		return 0, err
+	}
+
+	logger.Info("Deleted %d imported absences of user %s", deleted, userID)
+	return deleted, nil
+}
+
+// ListByUser returns all absences of a user ordered by start date
+func (r *AbsenceRepository) ListByUser(ctx context.Context, userID string) ([]models.Absence, error) {
+	return r.list(ctx, func(st *state, absence models.Absence) bool {
+		return absence.UserID == userID
+	})
+}
+
+// ListByTeam returns the absences of the team's members that end on or after from, ordered by start date
+func (r *AbsenceRepository) ListByTeam(ctx context.Context, teamName string, from time.Time) ([]models.Absence, error) {
+	return r.list(ctx, func(st *state, absence models.Absence) bool {
+		return st.users[absence.UserID].TeamName == teamName && !absence.EndDate.Before(from)
+	})
+}
+
+// list returns the absences matching the filter ordered by dates and ID
+func (r *AbsenceRepository) list(ctx context.Context, match func(st *state, absence models.Absence) bool) ([]models.Absence, error) {
+	absences := make([]models.Absence, 0)
+	err := r.store.read(ctx, func(st *state) error {
+		for _, absence := range st.absences {
+			if match(st, absence) {
+				absences = append(absences, absence)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(absences, func(i, j int) bool {
+		a, b := absences[i], absences[j]
+		if !a.StartDate.Equal(b.StartDate) {
+			return a.StartDate.Before(b.StartDate)
+		}
+		if !a.EndDate.Equal(b.EndDate) {
+			return a.EndDate.Before(b.EndDate)
+		}
+		return a.ID < b.ID
+	})
+	return absences, nil
+}
@@ -0,0 +1,83 @@
+package memory
+
+import (
+	"context"
+	"sort"
+
+	"avito-backend-trainee-assignment-autumn-2025/internal/domain/models"
+	pkgerrors "avito-backend-trainee-assignment-autumn-2025/pkg/errors"
+	"avito-backend-trainee-assignment-autumn-2025/pkg/logger"
+)
+
+// AffinityRepository implements repository.AffinityRepository in memory
+type AffinityRepository struct {
+	store *Store
+}
+
+// NewAffinityRepository creates a new affinity repository
+func NewAffinityRepository(store *Store) *AffinityRepository {
+	return &AffinityRepository{store: store}
+}
+
+// Set creates or replaces the affinity of a pair
+func (r *AffinityRepository) Set(ctx context.Context, affinity *models.Affinity) error {
+	err := r.store.write(ctx, func(st *state) error {
+		for _, userID := range []string{affinity.AuthorID, affinity.ReviewerID} {
+			if _, exists := st.users[userID]; !exists {
+				return pkgerrors.ErrUserNotFound
+			}
+		}
+		st.affinities[affinityKey{authorID: affinity.AuthorID, reviewerID: affinity.ReviewerID}] = *affinity
+		return nil
+	})
+	if err != nil {
+		logger.Error("Failed to set affinity of %s to %s: %v", affinity.AuthorID, affinity.ReviewerID, err)
+		return err
+	}
+
+	logger.Info("Set affinity of %s to %s to %d (conflict: %t)", affinity.AuthorID, affinity.ReviewerID, affinity.Weight, affinity.Conflict)
+	return nil
+}
+
+// Delete removes the affinity of a pair
+func (r *AffinityRepository) Delete(ctx context.Context, authorID, reviewerID string) error {
+	key := affinityKey{authorID: authorID, reviewerID: reviewerID}
+	err := r.store.write(ctx, func(st *state) error {
+		if _, exists := st.affinities[key]; !exists {
+			return pkgerrors.ErrAffinityNotFound
+		}
+		delete(st.affinities, key)
+		return nil
+	})
+	if err != nil {
+		logger.Error("Failed to delete affinity of %s to %s: %v", authorID, reviewerID, err)
+		return err
+	}
+
+	logger.Info("Deleted affinity of %s to %s", authorID, reviewerID)
+	return nil
+}
+
+// ListByUser returns the affinities where the user is the author or the reviewer
+func (r *AffinityRepository) ListByUser(ctx context.Context, userID string) ([]models.Affinity, error) {
+	affinities := make([]models.Affinity, 0)
+	err := r.store.read(ctx, func(st *state) error {
+		for key, affinity := range st.affinities {
+			if key.authorID == userID || key.reviewerID == userID {
+				affinities = append(affinities, affinity)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(affinities, func(i, j int) bool {
+		if affinities[i].AuthorID != affinities[j].AuthorID {
+			return affinities[i].AuthorID < affinities[j].AuthorID
+		}
+		return affinities[i].ReviewerID < affinities[j].ReviewerID
+	})
+	return affinities, nil
+}
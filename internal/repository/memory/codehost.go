@@ -0,0 +1,100 @@
+package memory
+
+import (
+	"context"
+	"sort"
+
+	"avito-backend-trainee-assignment-autumn-2025/internal/domain/models"
+	pkgerrors "avito-backend-trainee-assignment-autumn-2025/pkg/errors"
+	"avito-backend-trainee-assignment-autumn-2025/pkg/logger"
+)
+
+// CodeHostAccountRepository implements repository.CodeHostAccountRepository in memory
+type CodeHostAccountRepository struct {
+	store *Store
+}
+
+// NewCodeHostAccountRepository creates a new code host account repository
+func NewCodeHostAccountRepository(store *Store) *CodeHostAccountRepository {
+	return &CodeHostAccountRepository{store: store}
+}
+
+// Link creates or replaces the mapping of a login
+func (r *CodeHostAccountRepository) Link(ctx context.Context, account *models.CodeHostAccount) error {
+	err := r.store.write(ctx, func(st *state) error {
+		if _, exists := st.users[account.UserID]; !exists {
+			return pkgerrors.ErrUserNotFound
+		}
+		st.accounts[accountKey{provider: account.Provider, login: account.Login}] = *account
+		return nil
+	})
+	if err != nil {
+		logger.Error("Failed to link %s account %s: %v", account.Provider, account.Login, err)
+		return err
+	}
+
+	logger.Info("Linked %s account %s to user %s", account.Provider, account.Login, account.UserID)
+	return nil
+}
+
+// GetUserID returns the user linked to a login
+func (r *CodeHostAccountRepository) GetUserID(ctx context.Context, provider models.CodeHost, login string) (string, error) {
+	var userID string
+	err := r.store.read(ctx, func(st *state) error {
+		account, exists := st.accounts[accountKey{provider: provider, login: login}]
+		if !exists {
+			return pkgerrors.ErrAccountNotLinked
+		}
+		userID = account.UserID
+		return nil
+	})
+	return userID, err
+}
+
+// GetLogin returns the most recently linked login of a user
+func (r *CodeHostAccountRepository) GetLogin(ctx context.Context, provider models.CodeHost, userID string) (string, error) {
+	var found *models.CodeHostAccount
+	err := r.store.read(ctx, func(st *state) error {
+		for key, account := range st.accounts {
+			if key.provider != provider || account.UserID != userID {
+				continue
+			}
+			if found == nil || account.CreatedAt.After(found.CreatedAt) ||
+				(account.CreatedAt.Equal(found.CreatedAt) && account.Login < found.Login) {
+				found = &account
+			}
+		}
+		if found == nil {
+			return pkgerrors.ErrAccountNotLinked
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return found.Login, nil
+}
+
+// List returns mappings ordered by provider and login
+func (r *CodeHostAccountRepository) List(ctx context.Context, provider models.CodeHost) ([]models.CodeHostAccount, error) {
+	accounts := make([]models.CodeHostAccount, 0)
+	err := r.store.read(ctx, func(st *state) error {
+		for key, account := range st.accounts {
+			if provider == "" || key.provider == provider {
+				accounts = append(accounts, account)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(accounts, func(i, j int) bool {
+		if accounts[i].Provider != accounts[j].Provider {
+			return accounts[i].Provider < accounts[j].Provider
+		}
+		return accounts[i].Login < accounts[j].Login
+	})
+	return accounts, nil
+}
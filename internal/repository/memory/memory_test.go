@@ -0,0 +1,1098 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"avito-backend-trainee-assignment-autumn-2025/internal/domain/models"
+	"avito-backend-trainee-assignment-autumn-2025/internal/repository"
+	pkgerrors "avito-backend-trainee-assignment-autumn-2025/pkg/errors"
+)
+
+var (
+	_ repository.TeamRepository     = (*TeamRepository)(nil)
+	_ repository.UserRepository     = (*UserRepository)(nil)
+	_ repository.PRRepository       = (*PRRepository)(nil)
+	_ repository.WebhookRepository  = (*WebhookRepository)(nil)
+	_ repository.OutboxRepository   = (*OutboxRepository)(nil)
+	_ repository.TransactionManager = (*TransactionManager)(nil)
+
+	_ repository.CodeHostAccountRepository = (*CodeHostAccountRepository)(nil)
+	_ repository.ReviewerSyncRepository    = (*ReviewerSyncRepository)(nil)
+	_ repository.AbsenceRepository         = (*AbsenceRepository)(nil)
+)
+
+type repos struct {
+	teams      *TeamRepository
+	users      *UserRepository
+	prs        *PRRepository
+	webhooks   *WebhookRepository
+	outbox     *OutboxRepository
+	accounts   *CodeHostAccountRepository
+	syncs      *ReviewerSyncRepository
+	absences   *AbsenceRepository
+	affinities *AffinityRepository
+	tx         *TransactionManager
+}
+
+func newRepos() repos {
+	store := NewStore()
+	return repos{
+		teams:      NewTeamRepository(store),
+		users:      NewUserRepository(store),
+		prs:        NewPRRepository(store),
+		webhooks:   NewWebhookRepository(store),
+		outbox:     NewOutboxRepository(store),
+		accounts:   NewCodeHostAccountRepository(store),
+		syncs:      NewReviewerSyncRepository(store),
+		absences:   NewAbsenceRepository(store),
+		affinities: NewAffinityRepository(store),
+		tx:         NewTransactionManager(store),
+	}
+}
+
+func seedTeam(t *testing.T, r repos, team string, userIDs ...string) {
+	t.Helper()
+	ctx := context.Background()
+	if err := r.teams.Create(ctx, &models.Team{Name: team}); err != nil {
+		t.Fatalf("create team: %v", err)
+	}
+	for _, id := range userIDs {
+		if err := r.users.Create(ctx, &models.User{ID: id, Username: "name-" + id, TeamName: team, IsActive: true}); err != nil {
+			t.Fatalf("create user %s: %v", id, err)
+		}
+	}
+}
+
+func TestConstraints(t *testing.T) {
+	ctx := context.Background()
+	r := newRepos()
+	seedTeam(t, r, "backend", "u1", "u2")
+
+	cases := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{"duplicate team", r.teams.Create(ctx, &models.Team{Name: "backend"}), pkgerrors.ErrTeamExists},
+		{"duplicate user", r.users.Create(ctx, &models.User{ID: "u1", TeamName: "backend"}), pkgerrors.ErrUserAlreadyExists},
+		{"user without team", r.users.Create(ctx, &models.User{ID: "u9", TeamName: "nope"}), pkgerrors.ErrTeamNotFound},
+		{"PR without author", r.prs.Create(ctx, &models.PullRequest{ID: "pr-x", AuthorID: "nope", Status: models.PRStatusOpen}), pkgerrors.ErrUserNotFound},
+		{"set active unknown user", r.users.SetActive(ctx, "nope", false), pkgerrors.ErrUserNotFound},
+		{"remove unassigned reviewer", r.prs.RemoveReviewer(ctx, "pr-x", "u2"), pkgerrors.ErrReviewerNotAssigned},
+	}
+	for _, tc := range cases {
+		if !errors.Is(tc.err, tc.want) {
+			t.Errorf("%s: got %v, want %v", tc.name, tc.err, tc.want)
+		}
+	}
+
+	var validationErr *pkgerrors.ValidationError
+	err := r.prs.Create(ctx, &models.PullRequest{ID: "pr-x", AuthorID: "u1", Status: "DRAFT"})
+	if !errors.As(err, &validationErr) || validationErr.Field != "status" {
+		t.Errorf("invalid status: got %v", err)
+	}
+}
+
+func TestPullRequestLifecycle(t *testing.T) {
+	ctx := context.Background()
+	r := newRepos()
+	seedTeam(t, r, "backend", "u1", "u2", "u3")
+
+	for _, id := range []string{"pr-1", "pr-2"} {
+		if err := r.prs.Create(ctx, &models.PullRequest{ID: id, AuthorID: "u1", Status: models.PRStatusOpen}); err != nil {
+			t.Fatalf("create %s: %v", id, err)
+		}
+		if err := r.prs.AddReviewer(ctx, id, "u2"); err != nil {
+			t.Fatalf("add reviewer: %v", err)
+		}
+	}
+	if err := r.prs.AddReviewer(ctx, "pr-1", "u3"); err != nil {
+		t.Fatalf("add reviewer: %v", err)
+	}
+	if err := r.prs.AddReviewer(ctx, "pr-1", "u3"); err == nil {
+		t.Error("expected error for duplicate reviewer")
+	}
+
+	pr, err := r.prs.GetByID(ctx, "pr-1")
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if fmt.Sprint(pr.AssignedReviewers) != "[u2 u3]" {
+		t.Errorf("reviewers = %v", pr.AssignedReviewers)
+	}
+
+	// Returned values must not alias stored data
+	pr.AssignedReviewers[0] = "mutated"
+	if again, _ := r.prs.GetByID(ctx, "pr-1"); again.AssignedReviewers[0] != "u2" {
+		t.Error("GetByID result aliases stored state")
+	}
+
+	prs, err := r.prs.GetPRsByReviewerID(ctx, "u2")
+	if err != nil {
+		t.Fatalf("by reviewer: %v", err)
+	}
+	if len(prs) != 2 || prs[0].ID != "pr-2" {
+		t.Errorf("expected newest first, got %v", prs)
+	}
+
+	merged, err := r.prs.Merge(ctx, "pr-1")
+	if err != nil {
+		t.Fatalf("merge: %v", err)
+	}
+	if !merged.IsMerged() || merged.MergedAt == nil {
+		t.Errorf("unexpected merged PR: %v", merged)
+	}
+	if _, err := r.prs.Merge(ctx, "pr-1"); !errors.Is(err, pkgerrors.ErrPRMerged) {
+		t.Errorf("second merge: got %v", err)
+	}
+}
+
+func TestTransactionRollback(t *testing.T) {
+	ctx := context.Background()
+	r := newRepos()
+	seedTeam(t, r, "backend", "u1")
+
+	boom := errors.New("boom")
+	err := r.tx.WithTransaction(ctx, func(txCtx context.Context) error {
+		if err := r.teams.Create(txCtx, &models.Team{Name: "frontend"}); err != nil {
+			return err
+		}
+		if err := r.users.SetActive(txCtx, "u1", false); err != nil {
+			return err
+		}
+
+		// Reads inside the transaction see its own writes
+		if exists, _ := r.teams.Exists(txCtx, "frontend"); !exists {
+			t.Error("transaction does not see its own write")
+		}
+
+		// A failed nested transaction propagates its error to the outer one
+		return r.tx.WithTransaction(txCtx, func(nestedCtx context.Context) error {
+			if err := r.users.Create(nestedCtx, &models.User{ID: "u2", TeamName: "frontend"}); err != nil {
+				return err
+			}
+			return boom
+		})
+	})
+	if !errors.Is(err, boom) {
+		t.Fatalf("expected boom, got %v", err)
+	}
+
+	if exists, _ := r.teams.Exists(ctx, "frontend"); exists {
+		t.Error("team created in rolled back transaction is visible")
+	}
+	if _, err := r.users.GetByID(ctx, "u2"); !errors.Is(err, pkgerrors.ErrUserNotFound) {
+		t.Error("user created in rolled back transaction is visible")
+	}
+	if user, _ := r.users.GetByID(ctx, "u1"); !user.IsActive {
+		t.Error("update made in rolled back transaction is visible")
+	}
+}
+
+func TestNestedTransactionRollsBackToSavepoint(t *testing.T) {
+	ctx := context.Background()
+	r := newRepos()
+
+	boom := errors.New("boom")
+	err := r.tx.WithTransaction(ctx, func(txCtx context.Context) error {
+		if err := r.teams.Create(txCtx, &models.Team{Name: "backend"}); err != nil {
+			return err
+		}
+
+		nestedErr := r.tx.WithTransaction(txCtx, func(nestedCtx context.Context) error {
+			if err := r.teams.Create(nestedCtx, &models.Team{Name: "frontend"}); err != nil {
+				return err
+			}
+			return boom
+		})
+		if !errors.Is(nestedErr, boom) {
+			t.Errorf("expected boom from nested transaction, got %v", nestedErr)
+		}
+
+		// The outer transaction handles the error and goes on
+		return r.users.Create(txCtx, &models.User{ID: "u1", TeamName: "backend"})
+	})
+	if err != nil {
+		t.Fatalf("transaction: %v", err)
+	}
+
+	if exists, _ := r.teams.Exists(ctx, "backend"); !exists {
+		t.Error("outer write before the savepoint is lost")
+	}
+	if _, err := r.users.GetByID(ctx, "u1"); err != nil {
+		t.Errorf("outer write after the savepoint is lost: %v", err)
+	}
+	if exists, _ := r.teams.Exists(ctx, "frontend"); exists {
+		t.Error("write of the failed nested transaction is visible")
+	}
+}
+
+func TestTransactionRollbackOnPanic(t *testing.T) {
+	ctx := context.Background()
+	r := newRepos()
+
+	func() {
+		defer func() { _ = recover() }()
+		_ = r.tx.WithTransaction(ctx, func(txCtx context.Context) error {
+			_ = r.teams.Create(txCtx, &models.Team{Name: "backend"})
+			panic("boom")
+		})
+	}()
+
+	if exists, _ := r.teams.Exists(ctx, "backend"); exists {
+		t.Error("team created before panic is visible")
+	}
+	// The lock must have been released
+	if err := r.teams.Create(ctx, &models.Team{Name: "backend"}); err != nil {
+		t.Errorf("store is unusable after panic: %v", err)
+	}
+}
+
+func TestConcurrentTransactions(t *testing.T) {
+	ctx := context.Background()
+	r := newRepos()
+	seedTeam(t, r, "backend", "author")
+
+	const workers = 20
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			prID := fmt.Sprintf("pr-%d", i)
+			_ = r.tx.WithTransaction(ctx, func(txCtx context.Context) error {
+				if err := r.prs.Create(txCtx, &models.PullRequest{ID: prID, AuthorID: "author", Status: models.PRStatusOpen}); err != nil {
+					return err
+				}
+				if i%2 == 1 {
+					return errors.New("rollback odd PRs")
+				}
+				return nil
+			})
+			_, _ = r.prs.GetByID(ctx, prID)
+		}(i)
+	}
+	wg.Wait()
+
+	for i := 0; i < workers; i++ {
+		_, err := r.prs.GetByID(ctx, fmt.Sprintf("pr-%d", i))
+		if committed := i%2 == 0; committed != (err == nil) {
+			t.Errorf("pr-%d: committed=%v, err=%v", i, committed, err)
+		}
+	}
+}
+
+func TestWebhookDeliveries(t *testing.T) {
+	r := newRepos()
+	ctx := context.Background()
+	now := time.Date(2025, 11, 1, 12, 0, 0, 0, time.UTC)
+
+	webhook := &models.Webhook{
+		ID:        "wh-1",
+		URL:       "http://example.com/hook",
+		Secret:    "secret",
+		Events:    []models.EventType{models.EventPRMerged, models.EventReviewerAssigned},
+		CreatedAt: now,
+	}
+	if err := r.webhooks.Create(ctx, webhook); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	got, err := r.webhooks.GetByID(ctx, "wh-1")
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	if got.Secret != "secret" || !got.Subscribes(models.EventPRMerged) || got.Subscribes(models.EventUserDeactivated) {
+		t.Fatalf("webhook = %+v", got)
+	}
+	if _, err := r.webhooks.GetByID(ctx, "missing"); !errors.Is(err, pkgerrors.ErrWebhookNotFound) {
+		t.Fatalf("missing webhook error = %v, want ErrWebhookNotFound", err)
+	}
+
+	for i, next := range []time.Time{now, now.Add(time.Minute)} {
+		delivery := &models.WebhookDelivery{
+			ID:            fmt.Sprintf("dlv-%d", i),
+			WebhookID:     "wh-1",
+			EventID:       fmt.Sprintf("evt-%d", i),
+			EventType:     models.EventPRMerged,
+			Payload:       []byte(`{"id":"evt"}`),
+			Status:        models.DeliveryStatusPending,
+			NextAttemptAt: next,
+			CreatedAt:     now.Add(time.Duration(i) * time.Second),
+		}
+		if err := r.webhooks.CreateDelivery(ctx, delivery); err != nil {
+			t.Fatalf("CreateDelivery: %v", err)
+		}
+	}
+	err = r.webhooks.CreateDelivery(ctx, &models.WebhookDelivery{
+		ID: "dlv-x", WebhookID: "missing", EventID: "evt", EventType: models.EventPRMerged,
+		Payload: []byte(`{}`), Status: models.DeliveryStatusPending, NextAttemptAt: now, CreatedAt: now,
+	})
+	if !errors.Is(err, pkgerrors.ErrWebhookNotFound) {
+		t.Fatalf("delivery for missing webhook error = %v, want ErrWebhookNotFound", err)
+	}
+
+	due, err := r.webhooks.ClaimDueDeliveries(ctx, now, now.Add(time.Minute), 10)
+	if err != nil {
+		t.Fatalf("ClaimDueDeliveries: %v", err)
+	}
+	if len(due) != 1 || due[0].ID != "dlv-0" || string(due[0].Payload) != `{"id":"evt"}` {
+		t.Fatalf("due = %+v, want dlv-0", due)
+	}
+	if again, _ := r.webhooks.ClaimDueDeliveries(ctx, now, now.Add(time.Minute), 10); len(again) != 0 {
+		t.Fatalf("claimed again = %+v, want none while leased", again)
+	}
+
+	delivered := due[0]
+	delivered.Status = models.DeliveryStatusDelivered
+	delivered.Attempts = 1
+	delivered.DeliveredAt = &now
+	if err := r.webhooks.UpdateDelivery(ctx, &delivered); err != nil {
+		t.Fatalf("UpdateDelivery: %v", err)
+	}
+	if err := r.webhooks.UpdateDelivery(ctx, &models.WebhookDelivery{ID: "missing", Status: models.DeliveryStatusFailed}); !errors.Is(err, pkgerrors.ErrDeliveryNotFound) {
+		t.Fatalf("missing delivery error = %v, want ErrDeliveryNotFound", err)
+	}
+
+	if due, _ := r.webhooks.ClaimDueDeliveries(ctx, now.Add(time.Hour), now.Add(2*time.Hour), 10); len(due) != 1 || due[0].ID != "dlv-1" {
+		t.Fatalf("due = %+v, want dlv-1 only", due)
+	}
+	// An expired lease is claimed again
+	if due, _ := r.webhooks.ClaimDueDeliveries(ctx, now.Add(2*time.Hour), now.Add(3*time.Hour), 10); len(due) != 1 || due[0].ID != "dlv-1" {
+		t.Fatalf("due after the lease = %+v, want dlv-1", due)
+	}
+
+	all, err := r.webhooks.ListDeliveries(ctx, "", 10)
+	if err != nil {
+		t.Fatalf("ListDeliveries: %v", err)
+	}
+	if len(all) != 2 || all[0].ID != "dlv-1" {
+		t.Fatalf("deliveries = %+v, want newest first", all)
+	}
+	done, _ := r.webhooks.ListDeliveries(ctx, models.DeliveryStatusDelivered, 10)
+	if len(done) != 1 || done[0].Attempts != 1 || done[0].DeliveredAt == nil || !done[0].DeliveredAt.Equal(now) {
+		t.Fatalf("delivered = %+v", done)
+	}
+}
+
+func TestOutbox(t *testing.T) {
+	r := newRepos()
+	ctx := context.Background()
+
+	add := func(ctx context.Context, id string) error {
+		return r.outbox.Add(ctx, &models.OutboxMessage{
+			ID:        id,
+			EventType: models.EventPRMerged,
+			Payload:   []byte(`{"id":"` + id + `"}`),
+			CreatedAt: time.Now(),
+		})
+	}
+
+	// A message added in a rolled back transaction is discarded with the change
+	errRollback := errors.New("rollback")
+	err := r.tx.WithTransaction(ctx, func(txCtx context.Context) error {
+		if err := add(txCtx, "evt-rolled-back"); err != nil {
+			return err
+		}
+		return errRollback
+	})
+	if !errors.Is(err, errRollback) {
+		t.Fatalf("WithTransaction error = %v", err)
+	}
+
+	for _, id := range []string{"evt-3", "evt-1", "evt-2"} {
+		if err := add(ctx, id); err != nil {
+			t.Fatalf("Add(%s): %v", id, err)
+		}
+	}
+
+	now := time.Now()
+	pending, err := r.outbox.LockPending(ctx, now, 2)
+	if err != nil {
+		t.Fatalf("LockPending: %v", err)
+	}
+	if len(pending) != 2 || pending[0].ID != "evt-3" || pending[1].ID != "evt-1" || string(pending[0].Payload) != `{"id":"evt-3"}` {
+		t.Fatalf("pending = %+v, want evt-3, evt-1 in insertion order", pending)
+	}
+
+	publishedAt := time.Now().Add(-time.Hour)
+	if err := r.outbox.MarkPublished(ctx, "evt-3", publishedAt); err != nil {
+		t.Fatalf("MarkPublished: %v", err)
+	}
+	failed := pending[1]
+	failed.Attempts = 1
+	failed.LastError = "sink is down"
+	failed.NextAttemptAt = now.Add(time.Minute)
+	failed.PublishedSinks = []string{"webhook"}
+	if err := r.outbox.MarkFailed(ctx, &failed); err != nil {
+		t.Fatalf("MarkFailed: %v", err)
+	}
+	if err := r.outbox.MarkPublished(ctx, "missing", publishedAt); !errors.Is(err, pkgerrors.ErrOutboxMessageNotFound) {
+		t.Fatalf("missing message error = %v, want ErrOutboxMessageNotFound", err)
+	}
+
+	// evt-1 is not due until its next attempt
+	pending, _ = r.outbox.LockPending(ctx, now, 10)
+	if len(pending) != 1 || pending[0].ID != "evt-2" {
+		t.Fatalf("pending = %+v, want evt-2 only", pending)
+	}
+
+	pending, _ = r.outbox.LockPending(ctx, now.Add(time.Minute), 10)
+	if len(pending) != 2 || pending[0].ID != "evt-1" || pending[0].Attempts != 1 || pending[0].LastError != "sink is down" ||
+		len(pending[0].PublishedSinks) != 1 || pending[0].PublishedSinks[0] != "webhook" {
+		t.Fatalf("pending = %+v, want evt-1 with one failed attempt, then evt-2", pending)
+	}
+
+	// A dead-lettered message is never returned
+	deadLetter := pending[1]
+	deadLetter.Status = models.OutboxStatusFailed
+	deadLetter.Attempts = 1
+	if err := r.outbox.MarkFailed(ctx, &deadLetter); err != nil {
+		t.Fatalf("MarkFailed: %v", err)
+	}
+	pending, _ = r.outbox.LockPending(ctx, now.Add(time.Hour), 10)
+	if len(pending) != 1 || pending[0].ID != "evt-1" {
+		t.Fatalf("pending = %+v, want evt-1 only", pending)
+	}
+
+	deleted, err := r.outbox.DeletePublishedBefore(ctx, time.Now())
+	if err != nil || deleted != 1 {
+		t.Fatalf("DeletePublishedBefore = %d, %v; want 1", deleted, err)
+	}
+}
+
+func TestCodeHostAccounts(t *testing.T) {
+	r := newRepos()
+	ctx := context.Background()
+
+	seedTeam(t, r, "backend", "u1", "u2")
+
+	link := func(provider models.CodeHost, login, userID string) error {
+		return r.accounts.Link(ctx, &models.CodeHostAccount{Provider: provider, Login: login, UserID: userID, CreatedAt: time.Now()})
+	}
+
+	if err := link(models.CodeHostGitHub, "octo-dev", "u1"); err != nil {
+		t.Fatalf("Link: %v", err)
+	}
+	if err := link(models.CodeHostGitLab, "octo-dev", "u2"); err != nil {
+		t.Fatalf("Link (gitlab): %v", err)
+	}
+	if err := link(models.CodeHostGitHub, "ghost", "missing"); !errors.Is(err, pkgerrors.ErrUserNotFound) {
+		t.Fatalf("unknown user error = %v, want ErrUserNotFound", err)
+	}
+
+	// Linking again replaces the user
+	if err := link(models.CodeHostGitHub, "octo-dev", "u2"); err != nil {
+		t.Fatalf("Link (relink): %v", err)
+	}
+	userID, err := r.accounts.GetUserID(ctx, models.CodeHostGitHub, "octo-dev")
+	if err != nil || userID != "u2" {
+		t.Fatalf("GetUserID = %s, %v; want u2", userID, err)
+	}
+	if _, err := r.accounts.GetUserID(ctx, models.CodeHostGitHub, "nobody"); !errors.Is(err, pkgerrors.ErrAccountNotLinked) {
+		t.Fatalf("unlinked login error = %v, want ErrAccountNotLinked", err)
+	}
+
+	if err := link(models.CodeHostGitHub, "alice", "u1"); err != nil {
+		t.Fatalf("Link (alice): %v", err)
+	}
+	all, err := r.accounts.List(ctx, "")
+	if err != nil || len(all) != 3 || all[0].Login != "alice" || all[2].Provider != models.CodeHostGitLab {
+		t.Fatalf("List = %+v, %v; want 3 accounts ordered by provider and login", all, err)
+	}
+	gitlab, err := r.accounts.List(ctx, models.CodeHostGitLab)
+	if err != nil || len(gitlab) != 1 || gitlab[0].UserID != "u2" {
+		t.Fatalf("List(gitlab) = %+v, %v", gitlab, err)
+	}
+
+	login, err := r.accounts.GetLogin(ctx, models.CodeHostGitHub, "u1")
+	if err != nil || login != "alice" {
+		t.Fatalf("GetLogin = %s, %v; want alice", login, err)
+	}
+	if _, err := r.accounts.GetLogin(ctx, models.CodeHostGitLab, "u1"); !errors.Is(err, pkgerrors.ErrAccountNotLinked) {
+		t.Fatalf("GetLogin without account error = %v, want ErrAccountNotLinked", err)
+	}
+}
+
+func TestReviewerSyncs(t *testing.T) {
+	r := newRepos()
+	ctx := context.Background()
+	now := time.Date(2025, 11, 1, 12, 0, 0, 0, time.UTC)
+
+	create := func(id, prID string, action models.ReviewerSyncAction, nextAttemptAt time.Time) {
+		t.Helper()
+		err := r.syncs.Create(ctx, &models.ReviewerSync{
+			ID:            id,
+			EventID:       "evt-" + id,
+			PullRequestID: prID,
+			Action:        action,
+			Login:         "octo-dev",
+			Status:        models.DeliveryStatusPending,
+			NextAttemptAt: nextAttemptAt,
+			CreatedAt:     now,
+		})
+		if err != nil {
+			t.Fatalf("Create %s: %v", id, err)
+		}
+	}
+	create("s1", "github:octo-org/api#1", models.ReviewerSyncRequest, now)
+	create("s2", "github:octo-org/api#2", models.ReviewerSyncRemove, now.Add(-time.Minute))
+	create("s3", "github:octo-org/api#3", models.ReviewerSyncRequest, now.Add(time.Minute))
+	// s4 is due first but waits for s2, the earlier sync of the same PR
+	create("s4", "github:octo-org/api#2", models.ReviewerSyncRequest, now.Add(-2*time.Minute))
+
+	due, err := r.syncs.ClaimDue(ctx, now, now.Add(time.Minute), 1)
+	if err != nil || len(due) != 1 || due[0].ID != "s2" {
+		t.Fatalf("ClaimDue = %+v, %v; want s2", due, err)
+	}
+	if due[0].Action != models.ReviewerSyncRemove || due[0].EventID != "evt-s2" || due[0].Login != "octo-dev" {
+		t.Fatalf("ClaimDue returned %+v", due[0])
+	}
+	// s2 is leased, so the next claim gets s1 only
+	next, err := r.syncs.ClaimDue(ctx, now, now.Add(time.Minute), 10)
+	if err != nil || len(next) != 1 || next[0].ID != "s1" {
+		t.Fatalf("ClaimDue while s2 is leased = %+v, %v; want s1", next, err)
+	}
+	due = append(due, next...)
+
+	// A retry is rescheduled, a success leaves the due list
+	retry := due[0]
+	retry.Attempts = 1
+	retry.LastError = "unexpected status 502"
+	retry.NextAttemptAt = now.Add(time.Hour)
+	if err := r.syncs.Update(ctx, &retry); err != nil {
+		t.Fatalf("Update (retry): %v", err)
+	}
+	done := due[1]
+	done.Attempts = 1
+	done.Status = models.DeliveryStatusDelivered
+	done.CompletedAt = &now
+	if err := r.syncs.Update(ctx, &done); err != nil {
+		t.Fatalf("Update (delivered): %v", err)
+	}
+
+	due, err = r.syncs.ClaimDue(ctx, now.Add(time.Hour), now.Add(2*time.Hour), 10)
+	if err != nil || len(due) != 2 || due[0].ID != "s3" || due[1].ID != "s2" {
+		t.Fatalf("ClaimDue later = %+v, %v; want s3, s2", due, err)
+	}
+	if due[1].Attempts != 1 || due[1].LastError != "unexpected status 502" {
+		t.Fatalf("retried sync = %+v", due[1])
+	}
+
+	// Once s2 is done, s4 is next on its PR
+	done = due[1]
+	done.Status = models.DeliveryStatusDelivered
+	if err := r.syncs.Update(ctx, &done); err != nil {
+		t.Fatalf("Update (delivered): %v", err)
+	}
+	due, err = r.syncs.ClaimDue(ctx, now.Add(time.Hour), now.Add(2*time.Hour), 10)
+	if err != nil || len(due) != 1 || due[0].ID != "s4" {
+		t.Fatalf("ClaimDue after s2 = %+v, %v; want s4", due, err)
+	}
+
+	if err := r.syncs.Update(ctx, &models.ReviewerSync{ID: "missing", Status: models.DeliveryStatusFailed}); !errors.Is(err, pkgerrors.ErrReviewerSyncNotFound) {
+		t.Fatalf("Update unknown sync error = %v, want ErrReviewerSyncNotFound", err)
+	}
+}
+
+func TestNotificationSettings(t *testing.T) {
+	r := newRepos()
+	ctx := context.Background()
+
+	seedTeam(t, r, "backend", "u1")
+
+	if err := r.users.SetMentionHandle(ctx, "u1", "U012AB3CD"); err != nil {
+		t.Fatalf("SetMentionHandle: %v", err)
+	}
+	if err := r.users.SetMentionHandle(ctx, "ghost", "U012AB3CD"); !errors.Is(err, pkgerrors.ErrUserNotFound) {
+		t.Fatalf("unknown user error = %v, want ErrUserNotFound", err)
+	}
+	if err := r.users.SetEmail(ctx, "u1", "alice@example.com"); err != nil {
+		t.Fatalf("SetEmail: %v", err)
+	}
+	if err := r.users.SetEmail(ctx, "ghost", "ghost@example.com"); !errors.Is(err, pkgerrors.ErrUserNotFound) {
+		t.Fatalf("unknown user error = %v, want ErrUserNotFound", err)
+	}
+	user, err := r.users.GetByID(ctx, "u1")
+	if err != nil || user.MentionHandle != "U012AB3CD" || user.Email != "alice@example.com" {
+		t.Fatalf("GetByID = %+v, %v; want the mention handle and email", user, err)
+	}
+
+	if err := r.teams.SetChatWebhook(ctx, "backend", "https://hooks.example.com/T0/B0"); err != nil {
+		t.Fatalf("SetChatWebhook: %v", err)
+	}
+	if err := r.teams.SetChatWebhook(ctx, "missing", "https://hooks.example.com/T0/B0"); !errors.Is(err, pkgerrors.ErrTeamNotFound) {
+		t.Fatalf("unknown team error = %v, want ErrTeamNotFound", err)
+	}
+	team, err := r.teams.GetByName(ctx, "backend")
+	if err != nil || team.ChatWebhookURL != "https://hooks.example.com/T0/B0" {
+		t.Fatalf("GetByName = %+v, %v; want the webhook URL", team, err)
+	}
+	if len(team.Members) != 1 || team.Members[0].MentionHandle != "U012AB3CD" || team.Members[0].Email != "alice@example.com" {
+		t.Fatalf("members = %+v, want u1 with its mention handle and email", team.Members)
+	}
+}
+
+func TestWorkingHours(t *testing.T) {
+	r := newRepos()
+	ctx := context.Background()
+
+	seedTeam(t, r, "backend", "u1", "u2")
+
+	hours := models.WorkingHours{Start: 22 * 60, End: 6*60 + 30}
+	if err := r.users.SetWorkingHours(ctx, "u1", "Asia/Tokyo", hours); err != nil {
+		t.Fatalf("SetWorkingHours: %v", err)
+	}
+	if err := r.users.SetWorkingHours(ctx, "ghost", "", hours); !errors.Is(err, pkgerrors.ErrUserNotFound) {
+		t.Fatalf("unknown user error = %v, want ErrUserNotFound", err)
+	}
+	user, err := r.users.GetByID(ctx, "u1")
+	if err != nil || user.Timezone != "Asia/Tokyo" || user.WorkingHours != hours {
+		t.Fatalf("GetByID = %+v, %v; want the working hours", user, err)
+	}
+
+	team, err := r.teams.GetByName(ctx, "backend")
+	if err != nil || team.PreferWorkingHours || team.PreferWithinHours != 0 {
+		t.Fatalf("GetByName = %+v, %v; want no preference", team, err)
+	}
+	if team.Members[0].WorkingHours != hours || team.Members[1].WorkingHours.IsSet() {
+		t.Fatalf("members = %+v, want working hours on u1 only", team.Members)
+	}
+
+	if err := r.teams.SetWorkingHoursPreference(ctx, "backend", true, 4); err != nil {
+		t.Fatalf("SetWorkingHoursPreference: %v", err)
+	}
+	if err := r.teams.SetWorkingHoursPreference(ctx, "missing", true, 4); !errors.Is(err, pkgerrors.ErrTeamNotFound) {
+		t.Fatalf("unknown team error = %v, want ErrTeamNotFound", err)
+	}
+	if team, err = r.teams.GetByName(ctx, "backend"); err != nil || !team.PreferWorkingHours || team.PreferWithinHours != 4 {
+		t.Fatalf("GetByName = %+v, %v; want the preference", team, err)
+	}
+}
+
+func TestCodeOwners(t *testing.T) {
+	r := newRepos()
+	ctx := context.Background()
+
+	seedTeam(t, r, "backend", "u1", "u2")
+
+	if rules, err := r.teams.ListCodeOwners(ctx, "backend"); err != nil || len(rules) != 0 {
+		t.Fatalf("ListCodeOwners = %v, %v; want none", rules, err)
+	}
+
+	rules := []models.CodeOwnerRule{
+		{Line: 1, Pattern: "*", Owners: []models.CodeOwner{{TeamName: "backend"}}},
+		{Line: 3, Pattern: "/api/", Owners: []models.CodeOwner{{UserID: "u1"}, {UserID: "u2"}}},
+		{Line: 4, Pattern: "/api/gen/", Owners: []models.CodeOwner{}},
+	}
+	if err := r.teams.ReplaceCodeOwners(ctx, "backend", rules); err != nil {
+		t.Fatalf("ReplaceCodeOwners: %v", err)
+	}
+	got, err := r.teams.ListCodeOwners(ctx, "backend")
+	if err != nil || fmt.Sprint(got) != "[{1 * [@team/backend]} {3 /api/ [@u1 @u2]} {4 /api/gen/ []}]" {
+		t.Fatalf("ListCodeOwners = %v, %v; want the rules in order", got, err)
+	}
+
+	// Replacing drops the previous rules
+	if err := r.teams.ReplaceCodeOwners(ctx, "backend", rules[1:2]); err != nil {
+		t.Fatalf("ReplaceCodeOwners: %v", err)
+	}
+	if got, err := r.teams.ListCodeOwners(ctx, "backend"); err != nil || fmt.Sprint(got) != "[{3 /api/ [@u1 @u2]}]" {
+		t.Fatalf("ListCodeOwners = %v, %v; want the replaced rule", got, err)
+	}
+	if err := r.teams.ReplaceCodeOwners(ctx, "missing", rules); !errors.Is(err, pkgerrors.ErrTeamNotFound) {
+		t.Fatalf("unknown team error = %v, want ErrTeamNotFound", err)
+	}
+}
+
+func TestSkillTags(t *testing.T) {
+	r := newRepos()
+	ctx := context.Background()
+
+	seedTeam(t, r, "backend", "u1")
+	if err := r.users.Create(ctx, &models.User{ID: "u2", Username: "name-u2", TeamName: "backend", SkillTags: []string{"go", "sql"}}); err != nil {
+		t.Fatalf("create user u2: %v", err)
+	}
+	if err := r.users.SetSkillTags(ctx, "u1", []string{"frontend"}); err != nil {
+		t.Fatalf("SetSkillTags: %v", err)
+	}
+	if err := r.users.SetSkillTags(ctx, "ghost", []string{"go"}); !errors.Is(err, pkgerrors.ErrUserNotFound) {
+		t.Fatalf("unknown user error = %v, want ErrUserNotFound", err)
+	}
+
+	team, err := r.teams.GetByName(ctx, "backend")
+	if err != nil || fmt.Sprint(team.Members[0].SkillTags, team.Members[1].SkillTags) != "[frontend] [go sql]" {
+		t.Fatalf("GetByName = %+v, %v; want the skill tags of both members", team, err)
+	}
+	if err := r.users.SetSkillTags(ctx, "u2", nil); err != nil {
+		t.Fatalf("SetSkillTags(nil): %v", err)
+	}
+	if user, err := r.users.GetByID(ctx, "u2"); err != nil || len(user.SkillTags) != 0 {
+		t.Fatalf("GetByID = %+v, %v; want no skill tags", user, err)
+	}
+
+	if err := r.prs.Create(ctx, &models.PullRequest{ID: "pr-1", AuthorID: "u1", Status: models.PRStatusOpen, RequiredTags: []string{"go"}}); err != nil {
+		t.Fatalf("create pr-1: %v", err)
+	}
+	if err := r.prs.Create(ctx, &models.PullRequest{ID: "pr-2", AuthorID: "u1", Status: models.PRStatusOpen}); err != nil {
+		t.Fatalf("create pr-2: %v", err)
+	}
+	if pr, err := r.prs.GetByID(ctx, "pr-1"); err != nil || fmt.Sprint(pr.RequiredTags) != "[go]" {
+		t.Fatalf("GetByID(pr-1) = %+v, %v; want required tag go", pr, err)
+	}
+	if pr, err := r.prs.GetByID(ctx, "pr-2"); err != nil || len(pr.RequiredTags) != 0 {
+		t.Fatalf("GetByID(pr-2) = %+v, %v; want no required tags", pr, err)
+	}
+}
+
+func TestSeniority(t *testing.T) {
+	r := newRepos()
+	ctx := context.Background()
+
+	seedTeam(t, r, "backend", "u1")
+	if err := r.users.Create(ctx, &models.User{ID: "u2", Username: "name-u2", TeamName: "backend", Seniority: models.SenioritySenior}); err != nil {
+		t.Fatalf("create user u2: %v", err)
+	}
+	if err := r.users.SetSeniority(ctx, "u1", models.SeniorityJunior); err != nil {
+		t.Fatalf("SetSeniority: %v", err)
+	}
+	if err := r.users.SetSeniority(ctx, "ghost", models.SeniorityJunior); !errors.Is(err, pkgerrors.ErrUserNotFound) {
+		t.Fatalf("unknown user error = %v, want ErrUserNotFound", err)
+	}
+	if err := r.teams.SetSeniorityPolicy(ctx, "backend", models.SeniorityMiddle); err != nil {
+		t.Fatalf("SetSeniorityPolicy: %v", err)
+	}
+	if err := r.teams.SetSeniorityPolicy(ctx, "ghost", models.SeniorityMiddle); !errors.Is(err, pkgerrors.ErrTeamNotFound) {
+		t.Fatalf("unknown team error = %v, want ErrTeamNotFound", err)
+	}
+
+	team, err := r.teams.GetByName(ctx, "backend")
+	if err != nil || team.MinReviewerSeniority != models.SeniorityMiddle ||
+		team.Members[0].Seniority != models.SeniorityJunior || team.Members[1].Seniority != models.SenioritySenior {
+		t.Fatalf("GetByName = %+v, %v; want the policy and both levels", team, err)
+	}
+
+	if err := r.users.SetSeniority(ctx, "u2", ""); err != nil {
+		t.Fatalf("SetSeniority(empty): %v", err)
+	}
+	if err := r.teams.SetSeniorityPolicy(ctx, "backend", ""); err != nil {
+		t.Fatalf("SetSeniorityPolicy(empty): %v", err)
+	}
+	team, err = r.teams.GetByName(ctx, "backend")
+	if err != nil || team.MinReviewerSeniority != "" || team.Members[1].Seniority != "" {
+		t.Fatalf("GetByName = %+v, %v; want the policy and the level of u2 cleared", team, err)
+	}
+}
+
+func TestAffinities(t *testing.T) {
+	r := newRepos()
+	ctx := context.Background()
+
+	seedTeam(t, r, "backend", "u1", "u2", "u3")
+	for _, affinity := range []models.Affinity{
+		{AuthorID: "u1", ReviewerID: "u2", Weight: 3},
+		{AuthorID: "u3", ReviewerID: "u1", Conflict: true, Reason: "same household"},
+		{AuthorID: "u2", ReviewerID: "u3", Weight: 0},
+	} {
+		if err := r.affinities.Set(ctx, &affinity); err != nil {
+			t.Fatalf("Set(%s, %s): %v", affinity.AuthorID, affinity.ReviewerID, err)
+		}
+	}
+	if err := r.affinities.Set(ctx, &models.Affinity{AuthorID: "u1", ReviewerID: "ghost", Weight: 1}); !errors.Is(err, pkgerrors.ErrUserNotFound) {
+		t.Fatalf("unknown reviewer error = %v, want ErrUserNotFound", err)
+	}
+
+	// Setting the pair again replaces its weight and reason
+	if err := r.affinities.Set(ctx, &models.Affinity{AuthorID: "u1", ReviewerID: "u2", Weight: 5, Reason: "mentor"}); err != nil {
+		t.Fatalf("Set(replace): %v", err)
+	}
+
+	affinities, err := r.affinities.ListByUser(ctx, "u1")
+	if err != nil {
+		t.Fatalf("ListByUser: %v", err)
+	}
+	want := []models.Affinity{
+		{AuthorID: "u1", ReviewerID: "u2", Weight: 5, Reason: "mentor"},
+		{AuthorID: "u3", ReviewerID: "u1", Conflict: true, Reason: "same household"},
+	}
+	if len(affinities) != len(want) {
+		t.Fatalf("ListByUser = %+v, want %+v", affinities, want)
+	}
+	for i := range want {
+		if affinities[i] != want[i] {
+			t.Fatalf("ListByUser[%d] = %+v, want %+v", i, affinities[i], want[i])
+		}
+	}
+
+	if err := r.affinities.Delete(ctx, "u1", "u2"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if err := r.affinities.Delete(ctx, "u1", "u2"); !errors.Is(err, pkgerrors.ErrAffinityNotFound) {
+		t.Fatalf("second Delete error = %v, want ErrAffinityNotFound", err)
+	}
+	if affinities, err := r.affinities.ListByUser(ctx, "u2"); err != nil || len(affinities) != 1 || affinities[0].ReviewerID != "u3" {
+		t.Fatalf("ListByUser(u2) = %+v, %v; want only the pair with u3", affinities, err)
+	}
+}
+
+func TestOpenReviewCounts(t *testing.T) {
+	r := newRepos()
+	ctx := context.Background()
+
+	seedTeam(t, r, "backend", "u1", "u2", "u3")
+	if err := r.users.Create(ctx, &models.User{ID: "u4", Username: "name-u4", TeamName: "backend", MaxOpenReviews: 3}); err != nil {
+		t.Fatalf("create user u4: %v", err)
+	}
+	if err := r.users.SetMaxOpenReviews(ctx, "u1", 2); err != nil {
+		t.Fatalf("SetMaxOpenReviews: %v", err)
+	}
+	if err := r.users.SetMaxOpenReviews(ctx, "ghost", 2); !errors.Is(err, pkgerrors.ErrUserNotFound) {
+		t.Fatalf("unknown user error = %v, want ErrUserNotFound", err)
+	}
+	team, err := r.teams.GetByName(ctx, "backend")
+	if err != nil || team.Members[0].MaxOpenReviews != 2 || team.Members[1].MaxOpenReviews != 0 || team.Members[3].MaxOpenReviews != 3 {
+		t.Fatalf("GetByName = %+v, %v; want the limits of u1 and u4", team, err)
+	}
+
+	for _, id := range []string{"pr-1", "pr-2", "pr-3"} {
+		if err := r.prs.Create(ctx, &models.PullRequest{ID: id, AuthorID: "u3", Status: models.PRStatusOpen}); err != nil {
+			t.Fatalf("create %s: %v", id, err)
+		}
+		if err := r.prs.AddReviewer(ctx, id, "u1"); err != nil {
+			t.Fatalf("add reviewer: %v", err)
+		}
+	}
+	if err := r.prs.AddReviewer(ctx, "pr-1", "u2"); err != nil {
+		t.Fatalf("add reviewer: %v", err)
+	}
+	if _, err := r.prs.Merge(ctx, "pr-3"); err != nil {
+		t.Fatalf("merge: %v", err)
+	}
+
+	// Merged PRs do not count, users without open reviews and users not asked for are omitted
+	counts, err := r.prs.CountOpenReviews(ctx, []string{"u1", "u2", "u3"})
+	if err != nil || fmt.Sprint(counts) != "map[u1:2 u2:1]" {
+		t.Fatalf("CountOpenReviews = %v, %v; want u1:2 u2:1", counts, err)
+	}
+	if counts, err := r.prs.CountOpenReviews(ctx, []string{"u2"}); err != nil || fmt.Sprint(counts) != "map[u2:1]" {
+		t.Fatalf("CountOpenReviews(u2) = %v, %v", counts, err)
+	}
+	if counts, err := r.prs.CountOpenReviews(ctx, nil); err != nil || len(counts) != 0 {
+		t.Fatalf("CountOpenReviews(nil) = %v, %v; want none", counts, err)
+	}
+}
+
+func TestStaleReviews(t *testing.T) {
+	ctx := context.Background()
+	r := newRepos()
+	seedTeam(t, r, "backend", "u1", "u2", "u3")
+	seedTeam(t, r, "frontend", "f1", "f2")
+
+	if err := r.teams.SetReviewSLA(ctx, "frontend", 60, models.SLAActionReassign); err != nil {
+		t.Fatalf("SetReviewSLA: %v", err)
+	}
+	if err := r.teams.SetReviewSLA(ctx, "missing", 60, models.SLAActionRemind); !errors.Is(err, pkgerrors.ErrTeamNotFound) {
+		t.Fatalf("unknown team error = %v, want ErrTeamNotFound", err)
+	}
+	team, err := r.teams.GetByName(ctx, "frontend")
+	if err != nil || team.ReviewSLAMinutes != 60 || team.SLAAction != models.SLAActionReassign {
+		t.Fatalf("GetByName = %+v, %v; want a 60 minute SLA with reassignment", team, err)
+	}
+
+	for _, pr := range []struct{ id, author, reviewer string }{
+		{"pr-1", "u1", "u2"}, {"pr-2", "u1", "f2"}, {"pr-3", "f1", "u3"},
+	} {
+		if err := r.prs.Create(ctx, &models.PullRequest{ID: pr.id, Name: "PR " + pr.id, AuthorID: pr.author, Status: models.PRStatusOpen}); err != nil {
+			t.Fatalf("create %s: %v", pr.id, err)
+		}
+		if err := r.prs.AddReviewer(ctx, pr.id, pr.reviewer); err != nil {
+			t.Fatalf("add reviewer: %v", err)
+		}
+	}
+	if _, err := r.prs.Merge(ctx, "pr-3"); err != nil {
+		t.Fatalf("merge: %v", err)
+	}
+
+	stale := func(teamName string, before time.Time) []string {
+		t.Helper()
+		reviews, err := r.prs.ListStaleReviews(ctx, teamName, before, 10)
+		if err != nil {
+			t.Fatalf("ListStaleReviews: %v", err)
+		}
+		result := make([]string, len(reviews))
+		for i, review := range reviews {
+			result[i] = review.PullRequestID + "/" + review.ReviewerID
+		}
+		return result
+	}
+
+	// Reviews are listed by the reviewer's team; merged PRs are skipped
+	start := time.Now()
+	if got := stale("backend", start.Add(-time.Minute)); len(got) != 0 {
+		t.Errorf("stale before the assignment = %v, want none", got)
+	}
+	if got := fmt.Sprint(stale("backend", start.Add(time.Minute))); got != "[pr-1/u2]" {
+		t.Errorf("stale backend reviews = %v, want [pr-1/u2]", got)
+	}
+	if got := fmt.Sprint(stale("frontend", start.Add(time.Minute))); got != "[pr-2/f2]" {
+		t.Errorf("stale frontend reviews = %v, want [pr-2/f2]", got)
+	}
+
+	// A reminder restarts the SLA
+	if err := r.prs.MarkReviewReminded(ctx, "pr-2", "f2", start.Add(2*time.Hour)); err != nil {
+		t.Fatalf("MarkReviewReminded: %v", err)
+	}
+	if err := r.prs.MarkReviewReminded(ctx, "pr-2", "u2", start); !errors.Is(err, pkgerrors.ErrReviewerNotAssigned) {
+		t.Fatalf("unassigned reviewer error = %v, want ErrReviewerNotAssigned", err)
+	}
+	if got := stale("frontend", start.Add(time.Hour)); len(got) != 0 {
+		t.Errorf("stale before the reminder = %v, want none", got)
+	}
+	if got := fmt.Sprint(stale("frontend", start.Add(2*time.Hour))); got != "[pr-2/f2]" {
+		t.Errorf("stale at the reminder = %v, want [pr-2/f2]", got)
+	}
+}
+
+func TestTeamEscalationSettings(t *testing.T) {
+	ctx := context.Background()
+	r := newRepos()
+	seedTeam(t, r, "frontend", "f1")
+	seedTeam(t, r, "backend", "u1", "u2")
+
+	team, err := r.teams.GetByName(ctx, "backend")
+	if err != nil || team.SLAAction != models.SLAActionRemind || team.LeadID != "" || team.Calendar != models.DefaultCalendar() {
+		t.Fatalf("GetByName = %+v, %v; want the defaults", team, err)
+	}
+
+	if err := r.teams.SetLead(ctx, "backend", "u1"); err != nil {
+		t.Fatalf("SetLead: %v", err)
+	}
+	if err := r.teams.SetLead(ctx, "backend", "nobody"); !errors.Is(err, pkgerrors.ErrUserNotFound) {
+		t.Fatalf("unknown lead error = %v, want ErrUserNotFound", err)
+	}
+	if err := r.teams.SetLead(ctx, "missing", "u1"); !errors.Is(err, pkgerrors.ErrTeamNotFound) {
+		t.Fatalf("unknown team error = %v, want ErrTeamNotFound", err)
+	}
+	calendar := models.TeamCalendar{Timezone: "Asia/Dubai", Weekend: models.NewWeekdaySet(time.Friday, time.Saturday)}
+	if err := r.teams.SetCalendar(ctx, "backend", calendar); err != nil {
+		t.Fatalf("SetCalendar: %v", err)
+	}
+	if err := r.teams.SetCalendar(ctx, "missing", calendar); !errors.Is(err, pkgerrors.ErrTeamNotFound) {
+		t.Fatalf("unknown team error = %v, want ErrTeamNotFound", err)
+	}
+
+	team, err = r.teams.GetByName(ctx, "backend")
+	if err != nil || team.LeadID != "u1" || team.Calendar != calendar {
+		t.Fatalf("GetByName = %+v, %v; want the lead and calendar", team, err)
+	}
+	if err := r.teams.SetLead(ctx, "backend", ""); err != nil {
+		t.Fatalf("clear lead: %v", err)
+	}
+	if team, err = r.teams.GetByName(ctx, "backend"); err != nil || team.LeadID != "" {
+		t.Fatalf("GetByName = %+v, %v; want no lead", team, err)
+	}
+
+	names, err := r.teams.ListNames(ctx)
+	if err != nil || fmt.Sprint(names) != "[backend frontend]" {
+		t.Fatalf("ListNames = %v, %v; want [backend frontend]", names, err)
+	}
+}
+
+func TestReassignments(t *testing.T) {
+	ctx := context.Background()
+	r := newRepos()
+	seedTeam(t, r, "backend", "u1", "u2", "u3")
+	if err := r.prs.Create(ctx, &models.PullRequest{ID: "pr-1", Name: "PR 1", AuthorID: "u1", Status: models.PRStatusOpen}); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+
+	start := time.Now().UTC().Truncate(time.Second)
+	for i, reassignment := range []models.Reassignment{
+		{ID: "rsg-1", PullRequestID: "pr-1", OldReviewerID: "u2", NewReviewerID: "u3", Reason: models.ReassignReasonManual, CreatedAt: start},
+		{ID: "rsg-2", PullRequestID: "pr-1", OldReviewerID: "u3", NewReviewerID: "u2", Reason: models.ReassignReasonSLATimeout, CreatedAt: start.Add(time.Hour)},
+	} {
+		if err := r.prs.AddReassignment(ctx, &reassignment); err != nil {
+			t.Fatalf("AddReassignment #%d: %v", i, err)
+		}
+	}
+	missing := &models.Reassignment{ID: "rsg-3", PullRequestID: "missing", OldReviewerID: "u2", NewReviewerID: "u3", Reason: models.ReassignReasonManual, CreatedAt: start}
+	if err := r.prs.AddReassignment(ctx, missing); !errors.Is(err, pkgerrors.ErrPRNotFound) {
+		t.Fatalf("unknown PR error = %v, want ErrPRNotFound", err)
+	}
+
+	history, err := r.prs.ListReassignments(ctx, "pr-1")
+	if err != nil || len(history) != 2 {
+		t.Fatalf("ListReassignments = %+v, %v; want two reassignments", history, err)
+	}
+	if got := history[1]; got.OldReviewerID != "u3" || got.Reason != models.ReassignReasonSLATimeout || !got.CreatedAt.Equal(start.Add(time.Hour)) {
+		t.Errorf("second reassignment = %+v, want u3 timing out an hour later", got)
+	}
+	if history, err := r.prs.ListReassignments(ctx, "missing"); err != nil || len(history) != 0 {
+		t.Errorf("ListReassignments(missing) = %+v, %v; want none", history, err)
+	}
+}
+
+func TestAbsences(t *testing.T) {
+	ctx := context.Background()
+	r := newRepos()
+	seedTeam(t, r, "backend", "u1", "u2")
+	seedTeam(t, r, "frontend", "f1")
+
+	date := func(day int) time.Time { return time.Date(2025, 11, day, 0, 0, 0, 0, time.UTC) }
+	created := time.Now().UTC().Truncate(time.Second)
+	for i, absence := range []models.Absence{
+		{ID: "abs-1", UserID: "u1", StartDate: date(10), EndDate: date(14), Reason: "vacation", CreatedAt: created},
+		{ID: "abs-2", UserID: "u1", StartDate: date(3), EndDate: date(3), CreatedAt: created},
+		{ID: "abs-3", UserID: "u2", StartDate: date(1), EndDate: date(2), CreatedAt: created},
+		{ID: "abs-4", UserID: "f1", StartDate: date(5), EndDate: date(6), CreatedAt: created},
+		{ID: "abs-5", UserID: "u1", StartDate: date(20), EndDate: date(20), SourceUID: "event@example.com", CreatedAt: created},
+	} {
+		if err := r.absences.Create(ctx, &absence); err != nil {
+			t.Fatalf("Create #%d: %v", i, err)
+		}
+	}
+	unknown := &models.Absence{ID: "abs-6", UserID: "ghost", StartDate: date(1), EndDate: date(1), CreatedAt: created}
+	if err := r.absences.Create(ctx, unknown); !errors.Is(err, pkgerrors.ErrUserNotFound) {
+		t.Fatalf("unknown user error = %v, want ErrUserNotFound", err)
+	}
+
+	absences, err := r.absences.ListByUser(ctx, "u1")
+	if err != nil || len(absences) != 3 || absences[0].ID != "abs-2" || absences[1].ID != "abs-1" {
+		t.Fatalf("ListByUser = %+v, %v; want abs-2, abs-1 and abs-5", absences, err)
+	}
+	if got := absences[2]; got.SourceUID != "event@example.com" {
+		t.Errorf("imported absence = %+v, want its source UID", got)
+	}
+	if got := absences[1]; !got.StartDate.Equal(date(10)) || !got.EndDate.Equal(date(14)) || got.Reason != "vacation" {
+		t.Errorf("absence = %+v, want the vacation from the 10th to the 14th", got)
+	}
+
+	// Absences that ended before the date are left out
+	absences, err = r.absences.ListByTeam(ctx, "backend", date(3))
+	if err != nil || len(absences) != 3 || absences[0].ID != "abs-2" || absences[2].ID != "abs-5" {
+		t.Fatalf("ListByTeam = %+v, %v; want abs-2, abs-1 and abs-5", absences, err)
+	}
+
+	// Only imported absences are removed, and only those of the user
+	if deleted, err := r.absences.DeleteImported(ctx, "u1"); err != nil || deleted != 1 {
+		t.Fatalf("DeleteImported = %d, %v; want 1", deleted, err)
+	}
+	if deleted, err := r.absences.DeleteImported(ctx, "u1"); err != nil || deleted != 0 {
+		t.Fatalf("second DeleteImported = %d, %v; want 0", deleted, err)
+	}
+
+	if err := r.absences.Delete(ctx, "abs-2"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if err := r.absences.Delete(ctx, "abs-2"); !errors.Is(err, pkgerrors.ErrAbsenceNotFound) {
+		t.Fatalf("second Delete error = %v, want ErrAbsenceNotFound", err)
+	}
+	if absences, err := r.absences.ListByUser(ctx, "u1"); err != nil || len(absences) != 1 {
+		t.Errorf("ListByUser = %+v, %v; want one absence left", absences, err)
+	}
+}
@@ -0,0 +1,120 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"avito-backend-trainee-assignment-autumn-2025/internal/domain/models"
+	pkgerrors "avito-backend-trainee-assignment-autumn-2025/pkg/errors"
+	"avito-backend-trainee-assignment-autumn-2025/pkg/logger"
+)
+
+// OutboxRepository implements repository.OutboxRepository in memory
+type OutboxRepository struct {
+	store *Store
+}
+
+// NewOutboxRepository creates a new outbox repository
+func NewOutboxRepository(store *Store) *OutboxRepository {
+	return &OutboxRepository{store: store}
+}
+
+// Add inserts a message into the outbox
+func (r *OutboxRepository) Add(ctx context.Context, msg *models.OutboxMessage) error {
+	err := r.store.write(ctx, func(st *state) error {
+		stored := copyOutboxMessage(*msg)
+		stored.Status = models.OutboxStatusPending
+		stored.Attempts = 0
+		stored.LastError = ""
+		stored.NextAttemptAt = msg.CreatedAt
+		stored.PublishedSinks = nil
+		stored.PublishedAt = nil
+		st.outbox[msg.ID] = outboxRecord{msg: stored, seq: st.nextSeq()}
+		return nil
+	})
+	if err != nil {
+		logger.Error("Failed to add outbox message %s: %v", msg.ID, err)
+		return err
+	}
+
+	logger.Debug("Added outbox message %s (%s)", msg.ID, msg.EventType)
+	return nil
+}
+
+// LockPending selects due pending messages in insertion order
+// Transactions hold the store's write lock, so messages read inside one are not
+// visible to concurrent dispatchers until it ends
+func (r *OutboxRepository) LockPending(ctx context.Context, now time.Time, limit int) ([]models.OutboxMessage, error) {
+	var records []outboxRecord
+	err := r.store.read(ctx, func(st *state) error {
+		for _, record := range st.outbox {
+			if record.msg.Status == models.OutboxStatusPending && !record.msg.NextAttemptAt.After(now) {
+				record.msg = copyOutboxMessage(record.msg)
+				records = append(records, record)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(records, func(i, j int) bool { return records[i].seq < records[j].seq })
+	if limit > 0 && len(records) > limit {
+		records = records[:limit]
+	}
+
+	messages := make([]models.OutboxMessage, 0, len(records))
+	for _, record := range records {
+		messages = append(messages, record.msg)
+	}
+	return messages, nil
+}
+
+// MarkPublished sets the published status and published_at of a message
+func (r *OutboxRepository) MarkPublished(ctx context.Context, id string, publishedAt time.Time) error {
+	return r.update(ctx, id, func(msg *models.OutboxMessage) {
+		msg.Status = models.OutboxStatusPublished
+		msg.PublishedAt = &publishedAt
+	})
+}
+
+// MarkFailed records a failed publish attempt
+func (r *OutboxRepository) MarkFailed(ctx context.Context, failed *models.OutboxMessage) error {
+	return r.update(ctx, failed.ID, func(msg *models.OutboxMessage) {
+		msg.Status = failed.Status
+		msg.Attempts = failed.Attempts
+		msg.LastError = failed.LastError
+		msg.NextAttemptAt = failed.NextAttemptAt
+		msg.PublishedSinks = append([]string(nil), failed.PublishedSinks...)
+	})
+}
+
+// DeletePublishedBefore removes messages published before the given time
+func (r *OutboxRepository) DeletePublishedBefore(ctx context.Context, before time.Time) (int64, error) {
+	var deleted int64
+	err := r.store.write(ctx, func(st *state) error {
+		for id, record := range st.outbox {
+			if record.msg.PublishedAt != nil && record.msg.PublishedAt.Before(before) {
+				delete(st.outbox, id)
+				deleted++
+			}
+		}
+		return nil
+	})
+	return deleted, err
+}
+
+// update applies fn to a stored message
+func (r *OutboxRepository) update(ctx context.Context, id string, fn func(msg *models.OutboxMessage)) error {
+	return r.store.write(ctx, func(st *state) error {
+		record, exists := st.outbox[id]
+		if !exists {
+			return pkgerrors.ErrOutboxMessageNotFound
+		}
+		fn(&record.msg)
+		st.outbox[id] = record
+		return nil
+	})
+}
@@ -0,0 +1,386 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"avito-backend-trainee-assignment-autumn-2025/internal/domain/models"
+	pkgerrors "avito-backend-trainee-assignment-autumn-2025/pkg/errors"
+	"avito-backend-trainee-assignment-autumn-2025/pkg/logger"
+)
+
+// PRRepository implements repository.PRRepository in memory
+type PRRepository struct {
+	store *Store
+}
+
+// NewPRRepository creates a new pull request repository
+func NewPRRepository(store *Store) *PRRepository {
+	return &PRRepository{store: store}
+}
+
+// Create creates a new pull request
+func (r *PRRepository) Create(ctx context.Context, pr *models.PullRequest) error {
+	err := r.store.write(ctx, func(st *state) error {
+		if _, exists := st.prs[pr.ID]; exists {
+			return pkgerrors.ErrPRExists
+		}
+		if _, exists := st.users[pr.AuthorID]; !exists {
+			return pkgerrors.ErrUserNotFound
+		}
+		if !pr.Status.IsValid() {
+			return pkgerrors.NewValidationError("status", fmt.Sprintf("is invalid: %s", pr.Status))
+		}
+
+		// Like the SQL implementation, only the columns of pull_requests are stored;
+		// created_at is set by the storage and reviewers are added separately
+		st.prs[pr.ID] = &prRecord{
+			pr: models.PullRequest{
+				ID:           pr.ID,
+				Name:         pr.Name,
+				AuthorID:     pr.AuthorID,
+				Status:       pr.Status,
+				CreatedAt:    r.store.now(),
+				RequiredTags: append([]string(nil), pr.RequiredTags...),
+			},
+			seq: st.nextSeq(),
+		}
+		return nil
+	})
+	if err != nil {
+		logger.Error("Failed to create PR %s: %v", pr.ID, err)
+		return err
+	}
+
+	logger.Info("Created PR: %s (name: %s, author: %s)", pr.ID, pr.Name, pr.AuthorID)
+	return nil
+}
+
+// GetByID retrieves a pull request by ID with all reviewers
+func (r *PRRepository) GetByID(ctx context.Context, id string) (*models.PullRequest, error) {
+	var pr models.PullRequest
+	err := r.store.read(ctx, func(st *state) error {
+		record, exists := st.prs[id]
+		if !exists {
+			return pkgerrors.ErrPRNotFound
+		}
+		pr = withReviewers(st, record)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Debug("Retrieved PR %s with %d reviewers", pr.ID, len(pr.AssignedReviewers))
+	return &pr, nil
+}
+
+// Update updates an existing pull request
+func (r *PRRepository) Update(ctx context.Context, pr *models.PullRequest) error {
+	err := r.store.write(ctx, func(st *state) error {
+		record, exists := st.prs[pr.ID]
+		if !exists {
+			return pkgerrors.ErrPRNotFound
+		}
+		if _, exists := st.users[pr.AuthorID]; !exists {
+			return pkgerrors.ErrUserNotFound
+		}
+		if !pr.Status.IsValid() {
+			return pkgerrors.NewValidationError("status", fmt.Sprintf("is invalid: %s", pr.Status))
+		}
+
+		record.pr.Name = pr.Name
+		record.pr.AuthorID = pr.AuthorID
+		record.pr.Status = pr.Status
+		return nil
+	})
+	if err != nil {
+		logger.Error("Failed to update PR %s: %v", pr.ID, err)
+		return err
+	}
+
+	logger.Info("Updated PR: %s", pr.ID)
+	return nil
+}
+
+// Merge merges a pull request (sets status to MERGED and merged_at timestamp)
+func (r *PRRepository) Merge(ctx context.Context, prID string) (*models.PullRequest, error) {
+	var merged models.PullRequest
+	err := r.store.write(ctx, func(st *state) error {
+		record, exists := st.prs[prID]
+		if !exists {
+			return pkgerrors.ErrPRNotFound
+		}
+		if record.pr.Status == models.PRStatusMerged {
+			return pkgerrors.ErrPRMerged
+		}
+
+		mergedAt := r.store.now()
+		record.pr.Status = models.PRStatusMerged
+		record.pr.MergedAt = &mergedAt
+
+		merged = withReviewers(st, record)
+		return nil
+	})
+	if err != nil {
+		logger.Error("Failed to merge PR %s: %v", prID, err)
+		return nil, err
+	}
+
+	logger.Info("Merged PR: %s", prID)
+	return &merged, nil
+}
+
+// GetReviewersByPRID retrieves all reviewer IDs for a pull request
+func (r *PRRepository) GetReviewersByPRID(ctx context.Context, prID string) ([]string, error) {
+	var reviewers []string
+	err := r.store.read(ctx, func(st *state) error {
+		reviewers = reviewerIDs(st, prID)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Debug("Retrieved %d reviewers for PR %s", len(reviewers), prID)
+	return reviewers, nil
+}
+
+// AddReviewer adds a reviewer to a pull request
+func (r *PRRepository) AddReviewer(ctx context.Context, prID, reviewerID string) error {
+	err := r.store.write(ctx, func(st *state) error {
+		if _, exists := st.prs[prID]; !exists {
+			return pkgerrors.ErrPRNotFound
+		}
+		if _, exists := st.users[reviewerID]; !exists {
+			return pkgerrors.ErrPRNotFound
+		}
+		for _, reviewer := range st.reviewers[prID] {
+			if reviewer.reviewerID == reviewerID {
+				return fmt.Errorf("reviewer already assigned to this PR")
+			}
+		}
+
+		st.reviewers[prID] = append(st.reviewers[prID], reviewerRecord{
+			reviewerID: reviewerID,
+			assignedAt: r.store.now(),
+			seq:        st.nextSeq(),
+		})
+		return nil
+	})
+	if err != nil {
+		logger.Error("Failed to add reviewer %s to PR %s: %v", reviewerID, prID, err)
+		return err
+	}
+
+	logger.Info("Added reviewer %s to PR %s", reviewerID, prID)
+	return nil
+}
+
+// RemoveReviewer removes a reviewer from a pull request
+func (r *PRRepository) RemoveReviewer(ctx context.Context, prID, reviewerID string) error {
+	err := r.store.write(ctx, func(st *state) error {
+		reviewers := st.reviewers[prID]
+		for i, reviewer := range reviewers {
+			if reviewer.reviewerID == reviewerID {
+				st.reviewers[prID] = append(reviewers[:i:i], reviewers[i+1:]...)
+				return nil
+			}
+		}
+		return pkgerrors.ErrReviewerNotAssigned
+	})
+	if err != nil {
+		logger.Error("Failed to remove reviewer %s from PR %s: %v", reviewerID, prID, err)
+		return err
+	}
+
+	logger.Info("Removed reviewer %s from PR %s", reviewerID, prID)
+	return nil
+}
+
+// GetPRsByReviewerID retrieves all pull requests assigned to a reviewer
+func (r *PRRepository) GetPRsByReviewerID(ctx context.Context, reviewerID string) ([]models.PullRequest, error) {
+	var prs []models.PullRequest
+	err := r.store.read(ctx, func(st *state) error {
+		var records []*prRecord
+		for prID, reviewers := range st.reviewers {
+			for _, reviewer := range reviewers {
+				if reviewer.reviewerID == reviewerID {
+					records = append(records, st.prs[prID])
+					break
+				}
+			}
+		}
+
+		// Newest first, like ORDER BY created_at DESC
+		sort.Slice(records, func(i, j int) bool {
+			return records[i].seq > records[j].seq
+		})
+
+		prs = make([]models.PullRequest, 0, len(records))
+		for _, record := range records {
+			prs = append(prs, withReviewers(st, record))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Debug("Retrieved %d PRs for reviewer %s", len(prs), reviewerID)
+	return prs, nil
+}
+
+// CountOpenReviews returns how many open PRs each of the given users reviews; users without any are omitted
+func (r *PRRepository) CountOpenReviews(ctx context.Context, reviewerIDs []string) (map[string]int, error) {
+	wanted := make(map[string]bool, len(reviewerIDs))
+	for _, id := range reviewerIDs {
+		wanted[id] = true
+	}
+
+	counts := make(map[string]int)
+	err := r.store.read(ctx, func(st *state) error {
+		for prID, reviewers := range st.reviewers {
+			if record := st.prs[prID]; record == nil || record.pr.Status != models.PRStatusOpen {
+				continue
+			}
+			for _, reviewer := range reviewers {
+				if wanted[reviewer.reviewerID] {
+					counts[reviewer.reviewerID]++
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Debug("Counted open reviews of %d users", len(reviewerIDs))
+	return counts, nil
+}
+
+// ListStaleReviews returns active members of the team reviewing open PRs who were assigned
+// (or last reminded) no later than before; oldest assignments first
+func (r *PRRepository) ListStaleReviews(ctx context.Context, teamName string, before time.Time, limit int) ([]models.StaleReview, error) {
+	var reviews []models.StaleReview
+	err := r.store.read(ctx, func(st *state) error {
+		type candidate struct {
+			review models.StaleReview
+			seq    int64
+		}
+		var candidates []candidate
+		for prID, reviewers := range st.reviewers {
+			record := st.prs[prID]
+			if record == nil || record.pr.Status != models.PRStatusOpen {
+				continue
+			}
+			for _, reviewer := range reviewers {
+				user, exists := st.users[reviewer.reviewerID]
+				if !exists || !user.IsActive || user.TeamName != teamName {
+					continue
+				}
+				since := reviewer.assignedAt
+				if !reviewer.remindedAt.IsZero() {
+					since = reviewer.remindedAt
+				}
+				if since.After(before) {
+					continue
+				}
+
+				review := models.StaleReview{
+					PullRequestID:   prID,
+					PullRequestName: record.pr.Name,
+					AuthorID:        record.pr.AuthorID,
+					ReviewerID:      reviewer.reviewerID,
+					AssignedAt:      reviewer.assignedAt,
+				}
+				if !reviewer.remindedAt.IsZero() {
+					remindedAt := reviewer.remindedAt
+					review.RemindedAt = &remindedAt
+				}
+				candidates = append(candidates, candidate{review: review, seq: reviewer.seq})
+			}
+		}
+
+		// Oldest assignment first, like ORDER BY assigned_at
+		sort.Slice(candidates, func(i, j int) bool {
+			return candidates[i].seq < candidates[j].seq
+		})
+		if limit > 0 && len(candidates) > limit {
+			candidates = candidates[:limit]
+		}
+
+		reviews = make([]models.StaleReview, 0, len(candidates))
+		for _, c := range candidates {
+			reviews = append(reviews, c.review)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Debug("Found %d stale reviews in team %s", len(reviews), teamName)
+	return reviews, nil
+}
+
+// MarkReviewReminded records when a reviewer was reminded about a PR
+func (r *PRRepository) MarkReviewReminded(ctx context.Context, prID, reviewerID string, at time.Time) error {
+	return r.store.write(ctx, func(st *state) error {
+		for i, reviewer := range st.reviewers[prID] {
+			if reviewer.reviewerID == reviewerID {
+				st.reviewers[prID][i].remindedAt = at
+				return nil
+			}
+		}
+		return pkgerrors.ErrReviewerNotAssigned
+	})
+}
+
+// withReviewers returns a copy of the stored pull request with its reviewers
+func withReviewers(st *state, record *prRecord) models.PullRequest {
+	pr := copyPR(record.pr)
+	pr.AssignedReviewers = reviewerIDs(st, pr.ID)
+	return pr
+}
+
+// reviewerIDs returns reviewer IDs of a pull request in assignment order
+func reviewerIDs(st *state, prID string) []string {
+	reviewers := make([]string, 0, len(st.reviewers[prID]))
+	for _, reviewer := range st.reviewers[prID] {
+		reviewers = append(reviewers, reviewer.reviewerID)
+	}
+	return reviewers
+}
+
+// AddReassignment records a reviewer replacement in the PR history
+func (r *PRRepository) AddReassignment(ctx context.Context, reassignment *models.Reassignment) error {
+	err := r.store.write(ctx, func(st *state) error {
+		if _, exists := st.prs[reassignment.PullRequestID]; !exists {
+			return pkgerrors.ErrPRNotFound
+		}
+		st.reassignments[reassignment.PullRequestID] = append(st.reassignments[reassignment.PullRequestID], *reassignment)
+		return nil
+	})
+	if err != nil {
+		logger.Error("Failed to record reassignment on PR %s: %v", reassignment.PullRequestID, err)
+		return err
+	}
+	return nil
+}
+
+// ListReassignments returns the reviewer replacements of a PR, oldest first
+func (r *PRRepository) ListReassignments(ctx context.Context, prID string) ([]models.Reassignment, error) {
+	var reassignments []models.Reassignment
+	err := r.store.read(ctx, func(st *state) error {
+		reassignments = append(make([]models.Reassignment, 0, len(st.reassignments[prID])), st.reassignments[prID]...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return reassignments, nil
+}
@@ -0,0 +1,108 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"avito-backend-trainee-assignment-autumn-2025/internal/domain/models"
+	pkgerrors "avito-backend-trainee-assignment-autumn-2025/pkg/errors"
+	"avito-backend-trainee-assignment-autumn-2025/pkg/logger"
+)
+
+// ReviewerSyncRepository implements repository.ReviewerSyncRepository in memory
+type ReviewerSyncRepository struct {
+	store *Store
+}
+
+// NewReviewerSyncRepository creates a new reviewer sync repository
+func NewReviewerSyncRepository(store *Store) *ReviewerSyncRepository {
+	return &ReviewerSyncRepository{store: store}
+}
+
+// Create stores a new reviewer sync
+func (r *ReviewerSyncRepository) Create(ctx context.Context, sync *models.ReviewerSync) error {
+	err := r.store.write(ctx, func(st *state) error {
+		st.reviewerSyncs[sync.ID] = reviewerSyncRecord{
+			sync: copyReviewerSync(*sync),
+			seq:  st.nextSeq(),
+		}
+		return nil
+	})
+	if err != nil {
+		logger.Error("Failed to create reviewer sync %s: %v", sync.ID, err)
+		return err
+	}
+
+	logger.Debug("Created reviewer sync %s (%s %s on %s)", sync.ID, sync.Action, sync.Login, sync.PullRequestID)
+	return nil
+}
+
+// ClaimDue leases pending syncs whose next attempt is due
+// Only the earliest pending sync of a PR can be claimed, so the syncs of a PR run in event order
+func (r *ReviewerSyncRepository) ClaimDue(ctx context.Context, now, lockedUntil time.Time, limit int) ([]models.ReviewerSync, error) {
+	var syncs []models.ReviewerSync
+	err := r.store.write(ctx, func(st *state) error {
+		heads := make(map[string]int64)
+		for _, record := range st.reviewerSyncs {
+			if record.sync.Status != models.DeliveryStatusPending {
+				continue
+			}
+			if seq, ok := heads[record.sync.PullRequestID]; !ok || record.seq < seq {
+				heads[record.sync.PullRequestID] = record.seq
+			}
+		}
+
+		var records []reviewerSyncRecord
+		for _, record := range st.reviewerSyncs {
+			if record.sync.Status == models.DeliveryStatusPending && heads[record.sync.PullRequestID] == record.seq &&
+				!record.sync.NextAttemptAt.After(now) && !record.lockedUntil.After(now) {
+				records = append(records, record)
+			}
+		}
+
+		sort.Slice(records, func(i, j int) bool {
+			a, b := records[i], records[j]
+			if !a.sync.NextAttemptAt.Equal(b.sync.NextAttemptAt) {
+				return a.sync.NextAttemptAt.Before(b.sync.NextAttemptAt)
+			}
+			return a.seq < b.seq
+		})
+
+		syncs = make([]models.ReviewerSync, 0, len(records))
+		for _, record := range records {
+			if limit > 0 && len(syncs) == limit {
+				break
+			}
+			record.lockedUntil = lockedUntil
+			st.reviewerSyncs[record.sync.ID] = record
+			syncs = append(syncs, copyReviewerSync(record.sync))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return syncs, nil
+}
+
+// Update stores the sync state after an attempt and releases its lease
+func (r *ReviewerSyncRepository) Update(ctx context.Context, sync *models.ReviewerSync) error {
+	return r.store.write(ctx, func(st *state) error {
+		record, exists := st.reviewerSyncs[sync.ID]
+		if !exists {
+			return pkgerrors.ErrReviewerSyncNotFound
+		}
+
+		// Only the mutable columns are updated, like in the SQL implementations
+		updated := copyReviewerSync(*sync)
+		record.sync.Status = updated.Status
+		record.sync.Attempts = updated.Attempts
+		record.sync.LastError = updated.LastError
+		record.sync.NextAttemptAt = updated.NextAttemptAt
+		record.sync.CompletedAt = updated.CompletedAt
+		record.lockedUntil = time.Time{}
+		st.reviewerSyncs[sync.ID] = record
+		return nil
+	})
+}
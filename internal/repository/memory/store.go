@@ -0,0 +1,311 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"avito-backend-trainee-assignment-autumn-2025/internal/domain/models"
+)
+
+// Store keeps all data in process memory
+// It is safe for concurrent use: reads share a read lock, writes and transactions take
+// the write lock, so transactions are fully serialized
+type Store struct {
+	mu    sync.RWMutex
+	state *state
+
+	// now is the clock used for created_at/merged_at/assigned_at
+	now func() time.Time
+}
+
+// NewStore creates an empty in-memory store
+func NewStore() *Store {
+	return &Store{
+		state: newState(),
+		now:   time.Now,
+	}
+}
+
+// NewStoreWithClock creates an empty in-memory store that timestamps records with now
+func NewStoreWithClock(now func() time.Time) *Store {
+	store := NewStore()
+	store.now = now
+	return store
+}
+
+// state is the whole dataset; it is cloned to support transaction rollback
+type state struct {
+	teams     map[string]teamRecord
+	users     map[string]models.User
+	absences  map[string]models.Absence
+	prs       map[string]*prRecord
+	reviewers map[string][]reviewerRecord
+	// reassignments is the reviewer replacement history by PR, oldest first
+	reassignments map[string][]models.Reassignment
+	// affinities are the author/reviewer affinities by pair
+	affinities map[affinityKey]models.Affinity
+
+	webhooks   map[string]webhookRecord
+	deliveries map[string]deliveryRecord
+
+	outbox map[string]outboxRecord
+
+	accounts      map[accountKey]models.CodeHostAccount
+	reviewerSyncs map[string]reviewerSyncRecord
+
+	// seq orders records created within the same clock tick
+	seq int64
+}
+
+// teamRecord is a stored team without members
+type teamRecord struct {
+	chatWebhookURL   string
+	reviewSLAMinutes int
+	slaAction        models.SLAAction
+	leadID           string
+	calendar         models.TeamCalendar
+	// preferWorkingHours and preferWithinHours mirror Team.PreferWorkingHours and Team.PreferWithinHours
+	preferWorkingHours bool
+	preferWithinHours  int
+	// minReviewerSeniority mirrors Team.MinReviewerSeniority
+	minReviewerSeniority models.Seniority
+	// codeOwners are the CODEOWNERS rules in file order; replaced as a whole, never modified in place
+	codeOwners []models.CodeOwnerRule
+	createdAt  time.Time
+}
+
+// prRecord is a stored pull request without reviewers
+type prRecord struct {
+	pr  models.PullRequest
+	seq int64
+}
+
+// reviewerRecord is a single pr_reviewers row
+type reviewerRecord struct {
+	reviewerID string
+	assignedAt time.Time
+	// remindedAt is when the reviewer was last reminded; zero if never
+	remindedAt time.Time
+	seq        int64
+}
+
+// webhookRecord is a stored webhook
+type webhookRecord struct {
+	webhook models.Webhook
+	seq     int64
+}
+
+// deliveryRecord is a stored webhook delivery
+type deliveryRecord struct {
+	delivery models.WebhookDelivery
+	seq      int64
+	// lockedUntil is the end of the lease taken by ClaimDueDeliveries
+	lockedUntil time.Time
+}
+
+// outboxRecord is a stored outbox message
+type outboxRecord struct {
+	msg models.OutboxMessage
+	seq int64
+}
+
+// reviewerSyncRecord is a stored reviewer sync
+type reviewerSyncRecord struct {
+	sync models.ReviewerSync
+	seq  int64
+	// lockedUntil is the end of the lease taken by ClaimDue
+	lockedUntil time.Time
+}
+
+// affinityKey is the primary key of reviewer_affinities
+type affinityKey struct {
+	authorID   string
+	reviewerID string
+}
+
+// accountKey is the primary key of code_host_accounts
+type accountKey struct {
+	provider models.CodeHost
+	login    string
+}
+
+// newState creates an empty state
+func newState() *state {
+	return &state{
+		teams:     make(map[string]teamRecord),
+		users:     make(map[string]models.User),
+		absences:  make(map[string]models.Absence),
+		prs:       make(map[string]*prRecord),
+		reviewers: make(map[string][]reviewerRecord),
+
+		reassignments: make(map[string][]models.Reassignment),
+		affinities:    make(map[affinityKey]models.Affinity),
+
+		webhooks:   make(map[string]webhookRecord),
+		deliveries: make(map[string]deliveryRecord),
+
+		outbox: make(map[string]outboxRecord),
+
+		accounts:      make(map[accountKey]models.CodeHostAccount),
+		reviewerSyncs: make(map[string]reviewerSyncRecord),
+	}
+}
+
+// clone returns a deep copy of the state
+func (st *state) clone() *state {
+	c := newState()
+	c.seq = st.seq
+
+	for name, record := range st.teams {
+		c.teams[name] = record
+	}
+	for id, user := range st.users {
+		c.users[id] = copyUser(user)
+	}
+	for id, absence := range st.absences {
+		c.absences[id] = absence
+	}
+	for key, affinity := range st.affinities {
+		c.affinities[key] = affinity
+	}
+	for id, record := range st.prs {
+		copied := *record
+		copied.pr = copyPR(record.pr)
+		c.prs[id] = &copied
+	}
+	for prID, reviewers := range st.reviewers {
+		c.reviewers[prID] = append([]reviewerRecord(nil), reviewers...)
+	}
+	for prID, reassignments := range st.reassignments {
+		c.reassignments[prID] = append([]models.Reassignment(nil), reassignments...)
+	}
+	for id, record := range st.webhooks {
+		record.webhook = copyWebhook(record.webhook)
+		c.webhooks[id] = record
+	}
+	for id, record := range st.deliveries {
+		record.delivery = copyDelivery(record.delivery)
+		c.deliveries[id] = record
+	}
+	for id, record := range st.outbox {
+		record.msg = copyOutboxMessage(record.msg)
+		c.outbox[id] = record
+	}
+	for key, account := range st.accounts {
+		c.accounts[key] = account
+	}
+	for id, record := range st.reviewerSyncs {
+		record.sync = copyReviewerSync(record.sync)
+		c.reviewerSyncs[id] = record
+	}
+
+	return c
+}
+
+// nextSeq returns the next sequence number
+func (st *state) nextSeq() int64 {
+	st.seq++
+	return st.seq
+}
+
+// copyPR returns a copy of pr that shares no memory with it
+func copyPR(pr models.PullRequest) models.PullRequest {
+	if pr.MergedAt != nil {
+		mergedAt := *pr.MergedAt
+		pr.MergedAt = &mergedAt
+	}
+	pr.AssignedReviewers = append([]string(nil), pr.AssignedReviewers...)
+	pr.RequiredTags = append([]string(nil), pr.RequiredTags...)
+	return pr
+}
+
+// copyUser returns a copy of user that shares no memory with it
+func copyUser(user models.User) models.User {
+	user.SkillTags = append([]string(nil), user.SkillTags...)
+	return user
+}
+
+// copyCodeOwnerRules returns a copy of rules that shares no memory with them
+func copyCodeOwnerRules(rules []models.CodeOwnerRule) []models.CodeOwnerRule {
+	copied := make([]models.CodeOwnerRule, len(rules))
+	for i, rule := range rules {
+		rule.Owners = append([]models.CodeOwner(nil), rule.Owners...)
+		copied[i] = rule
+	}
+	return copied
+}
+
+// copyWebhook returns a copy of webhook that shares no memory with it
+func copyWebhook(webhook models.Webhook) models.Webhook {
+	webhook.Events = append([]models.EventType(nil), webhook.Events...)
+	return webhook
+}
+
+// copyDelivery returns a copy of delivery that shares no memory with it
+func copyDelivery(delivery models.WebhookDelivery) models.WebhookDelivery {
+	delivery.Payload = append([]byte(nil), delivery.Payload...)
+	if delivery.DeliveredAt != nil {
+		deliveredAt := *delivery.DeliveredAt
+		delivery.DeliveredAt = &deliveredAt
+	}
+	return delivery
+}
+
+// copyOutboxMessage returns a copy of msg that shares no memory with it
+func copyOutboxMessage(msg models.OutboxMessage) models.OutboxMessage {
+	msg.Payload = append([]byte(nil), msg.Payload...)
+	msg.PublishedSinks = append([]string(nil), msg.PublishedSinks...)
+	if msg.PublishedAt != nil {
+		publishedAt := *msg.PublishedAt
+		msg.PublishedAt = &publishedAt
+	}
+	return msg
+}
+
+// copyReviewerSync returns a copy of sync that shares no memory with it
+func copyReviewerSync(sync models.ReviewerSync) models.ReviewerSync {
+	if sync.CompletedAt != nil {
+		completedAt := *sync.CompletedAt
+		sync.CompletedAt = &completedAt
+	}
+	return sync
+}
+
+// txKey is a context key marking that the store's write lock is held by a transaction
+type txKey struct{}
+
+// inTx reports whether ctx belongs to a transaction of this store
+func (s *Store) inTx(ctx context.Context) bool {
+	store, ok := ctx.Value(txKey{}).(*Store)
+	return ok && store == s
+}
+
+// read runs fn under the read lock (or inside the current transaction)
+func (s *Store) read(ctx context.Context, fn func(st *state) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if s.inTx(ctx) {
+		return fn(s.state)
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return fn(s.state)
+}
+
+// write runs fn under the write lock (or inside the current transaction)
+// Outside a transaction nothing is rolled back, so fn must finish its checks before mutating st
+func (s *Store) write(ctx context.Context, fn func(st *state) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if s.inTx(ctx) {
+		return fn(s.state)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return fn(s.state)
+}
@@ -0,0 +1,276 @@
+package memory
+
+import (
+	"context"
+	"sort"
+
+	"avito-backend-trainee-assignment-autumn-2025/internal/domain/models"
+	pkgerrors "avito-backend-trainee-assignment-autumn-2025/pkg/errors"
+	"avito-backend-trainee-assignment-autumn-2025/pkg/logger"
+)
+
+// TeamRepository implements repository.TeamRepository in memory
+type TeamRepository struct {
+	store *Store
+}
+
+// NewTeamRepository creates a new team repository
+func NewTeamRepository(store *Store) *TeamRepository {
+	return &TeamRepository{store: store}
+}
+
+// Create creates a new team
+func (r *TeamRepository) Create(ctx context.Context, team *models.Team) error {
+	err := r.store.write(ctx, func(st *state) error {
+		if _, exists := st.teams[team.Name]; exists {
+			return pkgerrors.ErrTeamExists
+		}
+		st.teams[team.Name] = teamRecord{
+			chatWebhookURL: team.ChatWebhookURL,
+			slaAction:      models.SLAActionRemind,
+			calendar:       models.DefaultCalendar(),
+			createdAt:      r.store.now(),
+		}
+		return nil
+	})
+	if err != nil {
+		logger.Error("Failed to create team %s: %v", team.Name, err)
+		return err
+	}
+
+	logger.Info("Created team: %s", team.Name)
+	return nil
+}
+
+// GetByName retrieves a team by name with all members
+func (r *TeamRepository) GetByName(ctx context.Context, name string) (*models.Team, error) {
+	var team *models.Team
+	err := r.store.read(ctx, func(st *state) error {
+		record, exists := st.teams[name]
+		if !exists {
+			return pkgerrors.ErrTeamNotFound
+		}
+		team = &models.Team{
+			Name:                 name,
+			Members:              usersByTeam(st, name),
+			ChatWebhookURL:       record.chatWebhookURL,
+			ReviewSLAMinutes:     record.reviewSLAMinutes,
+			SLAAction:            record.slaAction,
+			LeadID:               record.leadID,
+			Calendar:             record.calendar,
+			PreferWorkingHours:   record.preferWorkingHours,
+			PreferWithinHours:    record.preferWithinHours,
+			MinReviewerSeniority: record.minReviewerSeniority,
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Debug("Retrieved team %s with %d members", name, len(team.Members))
+	return team, nil
+}
+
+// Exists checks if a team exists
+func (r *TeamRepository) Exists(ctx context.Context, name string) (bool, error) {
+	var exists bool
+	err := r.store.read(ctx, func(st *state) error {
+		_, exists = st.teams[name]
+		return nil
+	})
+	return exists, err
+}
+
+// SetChatWebhook sets the incoming webhook URL for team notifications; an empty URL disables them
+func (r *TeamRepository) SetChatWebhook(ctx context.Context, name, url string) error {
+	err := r.store.write(ctx, func(st *state) error {
+		record, exists := st.teams[name]
+		if !exists {
+			return pkgerrors.ErrTeamNotFound
+		}
+		record.chatWebhookURL = url
+		st.teams[name] = record
+		return nil
+	})
+	if err != nil {
+		logger.Error("Failed to set chat webhook for team %s: %v", name, err)
+		return err
+	}
+
+	logger.Info("Set chat webhook for team %s (enabled: %t)", name, url != "")
+	return nil
+}
+
+// SetReviewSLA sets how long reviewers of the team have (0 means the default) and what happens once they run out
+func (r *TeamRepository) SetReviewSLA(ctx context.Context, name string, minutes int, action models.SLAAction) error {
+	err := r.store.write(ctx, func(st *state) error {
+		record, exists := st.teams[name]
+		if !exists {
+			return pkgerrors.ErrTeamNotFound
+		}
+		record.reviewSLAMinutes = minutes
+		record.slaAction = action
+		st.teams[name] = record
+		return nil
+	})
+	if err != nil {
+		logger.Error("Failed to set review SLA for team %s: %v", name, err)
+		return err
+	}
+
+	logger.Info("Set review SLA for team %s to %d minutes (action: %s)", name, minutes, action)
+	return nil
+}
+
+// SetLead sets the team lead; an empty leadID removes the lead
+func (r *TeamRepository) SetLead(ctx context.Context, name, leadID string) error {
+	err := r.store.write(ctx, func(st *state) error {
+		record, exists := st.teams[name]
+		if !exists {
+			return pkgerrors.ErrTeamNotFound
+		}
+		if _, exists := st.users[leadID]; leadID != "" && !exists {
+			return pkgerrors.ErrUserNotFound
+		}
+		record.leadID = leadID
+		st.teams[name] = record
+		return nil
+	})
+	if err != nil {
+		logger.Error("Failed to set lead for team %s: %v", name, err)
+		return err
+	}
+
+	logger.Info("Set lead for team %s to %q", name, leadID)
+	return nil
+}
+
+// SetCalendar sets the working calendar used to count the review SLA
+func (r *TeamRepository) SetCalendar(ctx context.Context, name string, calendar models.TeamCalendar) error {
+	err := r.store.write(ctx, func(st *state) error {
+		record, exists := st.teams[name]
+		if !exists {
+			return pkgerrors.ErrTeamNotFound
+		}
+		record.calendar = calendar
+		st.teams[name] = record
+		return nil
+	})
+	if err != nil {
+		logger.Error("Failed to set calendar for team %s: %v", name, err)
+		return err
+	}
+
+	logger.Info("Set calendar for team %s (timezone: %s, weekend: %v)", name, calendar.Timezone, calendar.Weekend.Days())
+	return nil
+}
+
+// SetWorkingHoursPreference sets whether reviewers inside their working hours are picked first
+func (r *TeamRepository) SetWorkingHoursPreference(ctx context.Context, name string, enabled bool, withinHours int) error {
+	err := r.store.write(ctx, func(st *state) error {
+		record, exists := st.teams[name]
+		if !exists {
+			return pkgerrors.ErrTeamNotFound
+		}
+		record.preferWorkingHours = enabled
+		record.preferWithinHours = withinHours
+		st.teams[name] = record
+		return nil
+	})
+	if err != nil {
+		logger.Error("Failed to set working hours preference for team %s: %v", name, err)
+		return err
+	}
+
+	logger.Info("Set working hours preference for team %s (enabled: %t, within: %dh)", name, enabled, withinHours)
+	return nil
+}
+
+// SetSeniorityPolicy sets the level at least one reviewer of a PR must have; an empty level removes the policy
+func (r *TeamRepository) SetSeniorityPolicy(ctx context.Context, name string, level models.Seniority) error {
+	err := r.store.write(ctx, func(st *state) error {
+		record, exists := st.teams[name]
+		if !exists {
+			return pkgerrors.ErrTeamNotFound
+		}
+		record.minReviewerSeniority = level
+		st.teams[name] = record
+		return nil
+	})
+	if err != nil {
+		logger.Error("Failed to set seniority policy for team %s: %v", name, err)
+		return err
+	}
+
+	logger.Info("Set seniority policy for team %s to %q", name, level)
+	return nil
+}
+
+// ReplaceCodeOwners replaces the CODEOWNERS rules of the team, keeping their order
+func (r *TeamRepository) ReplaceCodeOwners(ctx context.Context, name string, rules []models.CodeOwnerRule) error {
+	err := r.store.write(ctx, func(st *state) error {
+		record, exists := st.teams[name]
+		if !exists {
+			return pkgerrors.ErrTeamNotFound
+		}
+		record.codeOwners = copyCodeOwnerRules(rules)
+		st.teams[name] = record
+		return nil
+	})
+	if err != nil {
+		logger.Error("Failed to replace code owners of team %s: %v", name, err)
+		return err
+	}
+
+	logger.Info("Replaced code owners of team %s (%d rules)", name, len(rules))
+	return nil
+}
+
+// ListCodeOwners returns the CODEOWNERS rules of the team in file order
+func (r *TeamRepository) ListCodeOwners(ctx context.Context, name string) ([]models.CodeOwnerRule, error) {
+	var rules []models.CodeOwnerRule
+	err := r.store.read(ctx, func(st *state) error {
+		rules = copyCodeOwnerRules(st.teams[name].codeOwners)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// ListNames returns the names of all teams in alphabetical order
+func (r *TeamRepository) ListNames(ctx context.Context) ([]string, error) {
+	var names []string
+	err := r.store.read(ctx, func(st *state) error {
+		names = make([]string, 0, len(st.teams))
+		for name := range st.teams {
+			names = append(names, name)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Strings(names)
+	return names, nil
+}
+
+// usersByTeam returns team members ordered by username, like the SQL implementation
+func usersByTeam(st *state, teamName string) []models.User {
+	users := make([]models.User, 0)
+	for _, user := range st.users {
+		if user.TeamName == teamName {
+			users = append(users, copyUser(user))
+		}
+	}
+	sort.Slice(users, func(i, j int) bool {
+		if users[i].Username != users[j].Username {
+			return users[i].Username < users[j].Username
+		}
+		return users[i].ID < users[j].ID
+	})
+	return users
+}
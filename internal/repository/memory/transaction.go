@@ -0,0 +1,54 @@
+package memory
+
+import (
+	"context"
+)
+
+// TransactionManager implements repository.TransactionManager for the in-memory store
+type TransactionManager struct {
+	store *Store
+}
+
+// NewTransactionManager creates a new transaction manager
+func NewTransactionManager(store *Store) *TransactionManager {
+	return &TransactionManager{store: store}
+}
+
+// WithTransaction executes a function within a transaction
+// The store's write lock is held for the whole transaction; if the function
+// returns an error (or panics), all changes made by it are rolled back.
+// Nested calls act as savepoints of the outer transaction: a failed nested call
+// rolls back only its own changes.
+func (tm *TransactionManager) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) (err error) {
+	if tm.store.inTx(ctx) {
+		savepoint := tm.store.state.clone()
+		if err := fn(ctx); err != nil {
+			tm.store.state = savepoint
+			return err
+		}
+		return nil
+	}
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	tm.store.mu.Lock()
+	defer tm.store.mu.Unlock()
+
+	snapshot := tm.store.state.clone()
+	committed := false
+	defer func() {
+		if !committed {
+			tm.store.state = snapshot
+		}
+	}()
+
+	txCtx := context.WithValue(ctx, txKey{}, tm.store)
+	if err := fn(txCtx); err != nil {
+		return err
+	}
+
+	committed = true
+	return nil
+}
@@ -0,0 +1,242 @@
+package memory
+
+import (
+	"context"
+
+	"avito-backend-trainee-assignment-autumn-2025/internal/domain/models"
+	pkgerrors "avito-backend-trainee-assignment-autumn-2025/pkg/errors"
+	"avito-backend-trainee-assignment-autumn-2025/pkg/logger"
+)
+
+// UserRepository implements repository.UserRepository in memory
+type UserRepository struct {
+	store *Store
+}
+
+// NewUserRepository creates a new user repository
+func NewUserRepository(store *Store) *UserRepository {
+	return &UserRepository{store: store}
+}
+
+// Create creates a new user
+func (r *UserRepository) Create(ctx context.Context, user *models.User) error {
+	err := r.store.write(ctx, func(st *state) error {
+		if _, exists := st.users[user.ID]; exists {
+			return pkgerrors.ErrUserAlreadyExists
+		}
+		if _, exists := st.teams[user.TeamName]; !exists {
+			return pkgerrors.ErrTeamNotFound
+		}
+		st.users[user.ID] = copyUser(*user)
+		return nil
+	})
+	if err != nil {
+		logger.Error("Failed to create user %s: %v", user.ID, err)
+		return err
+	}
+
+	logger.Info("Created user: %s (username: %s, team: %s)", user.ID, user.Username, user.TeamName)
+	return nil
+}
+
+// Update updates an existing user
+func (r *UserRepository) Update(ctx context.Context, user *models.User) error {
+	err := r.store.write(ctx, func(st *state) error {
+		if _, exists := st.users[user.ID]; !exists {
+			return pkgerrors.ErrUserNotFound
+		}
+		if _, exists := st.teams[user.TeamName]; !exists {
+			return pkgerrors.ErrTeamNotFound
+		}
+		st.users[user.ID] = copyUser(*user)
+		return nil
+	})
+	if err != nil {
+		logger.Error("Failed to update user %s: %v", user.ID, err)
+		return err
+	}
+
+	logger.Info("Updated user: %s", user.ID)
+	return nil
+}
+
+// GetByID retrieves a user by ID
+func (r *UserRepository) GetByID(ctx context.Context, id string) (*models.User, error) {
+	var user models.User
+	err := r.store.read(ctx, func(st *state) error {
+		found, exists := st.users[id]
+		if !exists {
+			return pkgerrors.ErrUserNotFound
+		}
+		user = copyUser(found)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Debug("Retrieved user: %s", user.ID)
+	return &user, nil
+}
+
+// GetByTeamName retrieves all users in a team
+func (r *UserRepository) GetByTeamName(ctx context.Context, teamName string) ([]models.User, error) {
+	var users []models.User
+	err := r.store.read(ctx, func(st *state) error {
+		users = usersByTeam(st, teamName)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Debug("Retrieved %d users for team %s", len(users), teamName)
+	return users, nil
+}
+
+// SetActive sets the active status of a user
+func (r *UserRepository) SetActive(ctx context.Context, userID string, isActive bool) error {
+	err := r.store.write(ctx, func(st *state) error {
+		user, exists := st.users[userID]
+		if !exists {
+			return pkgerrors.ErrUserNotFound
+		}
+		user.IsActive = isActive
+		st.users[userID] = user
+		return nil
+	})
+	if err != nil {
+		logger.Error("Failed to set active status for user %s: %v", userID, err)
+		return err
+	}
+
+	logger.Info("Set user %s active status to %t", userID, isActive)
+	return nil
+}
+
+// SetMentionHandle sets the chat handle used to mention a user; an empty handle disables mentions
+func (r *UserRepository) SetMentionHandle(ctx context.Context, userID, handle string) error {
+	err := r.store.write(ctx, func(st *state) error {
+		user, exists := st.users[userID]
+		if !exists {
+			return pkgerrors.ErrUserNotFound
+		}
+		user.MentionHandle = handle
+		st.users[userID] = user
+		return nil
+	})
+	if err != nil {
+		logger.Error("Failed to set mention handle for user %s: %v", userID, err)
+		return err
+	}
+
+	logger.Info("Set user %s mention handle to %q", userID, handle)
+	return nil
+}
+
+// SetEmail sets the address for email notifications; an empty address disables them
+func (r *UserRepository) SetEmail(ctx context.Context, userID, email string) error {
+	err := r.store.write(ctx, func(st *state) error {
+		user, exists := st.users[userID]
+		if !exists {
+			return pkgerrors.ErrUserNotFound
+		}
+		user.Email = email
+		st.users[userID] = user
+		return nil
+	})
+	if err != nil {
+		logger.Error("Failed to set email for user %s: %v", userID, err)
+		return err
+	}
+
+	logger.Info("Set user %s email (enabled: %t)", userID, email != "")
+	return nil
+}
+
+// SetWorkingHours sets the user's timezone and working hours; an empty timezone means the team's
+func (r *UserRepository) SetWorkingHours(ctx context.Context, userID, timezone string, hours models.WorkingHours) error {
+	err := r.store.write(ctx, func(st *state) error {
+		user, exists := st.users[userID]
+		if !exists {
+			return pkgerrors.ErrUserNotFound
+		}
+		user.Timezone = timezone
+		user.WorkingHours = hours
+		st.users[userID] = user
+		return nil
+	})
+	if err != nil {
+		logger.Error("Failed to set working hours for user %s: %v", userID, err)
+		return err
+	}
+
+	logger.Info("Set user %s working hours to %d-%d (timezone: %q)", userID, hours.Start, hours.End, timezone)
+	return nil
+}
+
+// SetMaxOpenReviews sets how many open reviews the user may have at once; 0 removes the limit
+func (r *UserRepository) SetMaxOpenReviews(ctx context.Context, userID string, limit int) error {
+	err := r.store.write(ctx, func(st *state) error {
+		user, exists := st.users[userID]
+		if !exists {
+			return pkgerrors.ErrUserNotFound
+		}
+		user.MaxOpenReviews = limit
+		st.users[userID] = user
+		return nil
+	})
+	if err != nil {
+		logger.Error("Failed to set max open reviews for user %s: %v", userID, err)
+		return err
+	}
+
+	logger.Info("Set user %s max open reviews to %d", userID, limit)
+	return nil
+}
+
+// SetSkillTags replaces the user's skill tags
+func (r *UserRepository) SetSkillTags(ctx context.Context, userID string, tags []string) error {
+	err := r.store.write(ctx, func(st *state) error {
+		user, exists := st.users[userID]
+		if !exists {
+			return pkgerrors.ErrUserNotFound
+		}
+		user.SkillTags = append([]string(nil), tags...)
+		st.users[userID] = user
+		return nil
+	})
+	if err != nil {
+		logger.Error("Failed to set skill tags for user %s: %v", userID, err)
+		return err
+	}
+
+	logger.Info("Set user %s skill tags to %v", userID, tags)
+	return nil
+}
+
+// SetSeniority sets the user's reviewer level; an empty level clears it
+func (r *UserRepository) SetSeniority(ctx context.Context, userID string, level models.Seniority) error {
+	err := r.store.write(ctx, func(st *state) error {
+		user, exists := st.users[userID]
+		if !exists {
+			return pkgerrors.ErrUserNotFound
+		}
+		user.Seniority = level
+		st.users[userID] = user
+		return nil
+	})
+	if err != nil {
+		logger.Error("Failed to set seniority for user %s: %v", userID, err)
+		return err
+	}
+
+	logger.Info("Set user %s seniority to %q", userID, level)
+	return nil
+}
+
+// LockForUpdate is a no-op: transactions hold the store's write lock, so they already
+// run one after another
+func (r *UserRepository) LockForUpdate(ctx context.Context, userIDs []string) error {
+	return ctx.Err()
+}
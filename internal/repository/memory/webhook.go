@@ -0,0 +1,214 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"avito-backend-trainee-assignment-autumn-2025/internal/domain/models"
+	pkgerrors "avito-backend-trainee-assignment-autumn-2025/pkg/errors"
+	"avito-backend-trainee-assignment-autumn-2025/pkg/logger"
+)
+
+// WebhookRepository implements repository.WebhookRepository in memory
+type WebhookRepository struct {
+	store *Store
+}
+
+// NewWebhookRepository creates a new webhook repository
+func NewWebhookRepository(store *Store) *WebhookRepository {
+	return &WebhookRepository{store: store}
+}
+
+// Create creates a new webhook
+func (r *WebhookRepository) Create(ctx context.Context, webhook *models.Webhook) error {
+	err := r.store.write(ctx, func(st *state) error {
+		st.webhooks[webhook.ID] = webhookRecord{
+			webhook: copyWebhook(*webhook),
+			seq:     st.nextSeq(),
+		}
+		return nil
+	})
+	if err != nil {
+		logger.Error("Failed to create webhook %s: %v", webhook.ID, err)
+		return err
+	}
+
+	logger.Info("Created webhook: %s (url: %s)", webhook.ID, webhook.URL)
+	return nil
+}
+
+// GetByID retrieves a webhook by ID
+func (r *WebhookRepository) GetByID(ctx context.Context, id string) (*models.Webhook, error) {
+	var webhook models.Webhook
+	err := r.store.read(ctx, func(st *state) error {
+		record, exists := st.webhooks[id]
+		if !exists {
+			return pkgerrors.ErrWebhookNotFound
+		}
+		webhook = copyWebhook(record.webhook)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &webhook, nil
+}
+
+// List retrieves all webhooks in registration order
+func (r *WebhookRepository) List(ctx context.Context) ([]models.Webhook, error) {
+	var records []webhookRecord
+	err := r.store.read(ctx, func(st *state) error {
+		for _, record := range st.webhooks {
+			record.webhook = copyWebhook(record.webhook)
+			records = append(records, record)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(records, func(i, j int) bool { return records[i].seq < records[j].seq })
+
+	webhooks := make([]models.Webhook, 0, len(records))
+	for _, record := range records {
+		webhooks = append(webhooks, record.webhook)
+	}
+	return webhooks, nil
+}
+
+// CreateDelivery creates a new webhook delivery
+func (r *WebhookRepository) CreateDelivery(ctx context.Context, delivery *models.WebhookDelivery) error {
+	err := r.store.write(ctx, func(st *state) error {
+		if _, exists := st.webhooks[delivery.WebhookID]; !exists {
+			return pkgerrors.ErrWebhookNotFound
+		}
+		st.deliveries[delivery.ID] = deliveryRecord{
+			delivery: copyDelivery(*delivery),
+			seq:      st.nextSeq(),
+		}
+		return nil
+	})
+	if err != nil {
+		logger.Error("Failed to create delivery %s: %v", delivery.ID, err)
+		return err
+	}
+
+	logger.Debug("Created delivery %s of event %s to webhook %s", delivery.ID, delivery.EventID, delivery.WebhookID)
+	return nil
+}
+
+// GetDelivery retrieves a webhook delivery by ID
+func (r *WebhookRepository) GetDelivery(ctx context.Context, id string) (*models.WebhookDelivery, error) {
+	var delivery models.WebhookDelivery
+	err := r.store.read(ctx, func(st *state) error {
+		record, exists := st.deliveries[id]
+		if !exists {
+			return pkgerrors.ErrDeliveryNotFound
+		}
+		delivery = copyDelivery(record.delivery)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &delivery, nil
+}
+
+// ListDeliveries retrieves the newest deliveries, optionally filtered by status
+func (r *WebhookRepository) ListDeliveries(ctx context.Context, status models.DeliveryStatus, limit int) ([]models.WebhookDelivery, error) {
+	records, err := r.selectDeliveries(ctx, func(d *models.WebhookDelivery) bool {
+		return status == "" || d.Status == status
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(records, func(i, j int) bool { return records[i].seq > records[j].seq })
+	return limitDeliveries(records, limit), nil
+}
+
+// ClaimDueDeliveries leases pending deliveries whose next attempt is due
+func (r *WebhookRepository) ClaimDueDeliveries(ctx context.Context, now, lockedUntil time.Time, limit int) ([]models.WebhookDelivery, error) {
+	var deliveries []models.WebhookDelivery
+	err := r.store.write(ctx, func(st *state) error {
+		var records []deliveryRecord
+		for _, record := range st.deliveries {
+			if record.delivery.Status == models.DeliveryStatusPending && !record.delivery.NextAttemptAt.After(now) &&
+				!record.lockedUntil.After(now) {
+				records = append(records, record)
+			}
+		}
+
+		sort.Slice(records, func(i, j int) bool {
+			a, b := records[i], records[j]
+			if !a.delivery.NextAttemptAt.Equal(b.delivery.NextAttemptAt) {
+				return a.delivery.NextAttemptAt.Before(b.delivery.NextAttemptAt)
+			}
+			return a.seq < b.seq
+		})
+		deliveries = limitDeliveries(records, limit)
+
+		for i, delivery := range deliveries {
+			record := st.deliveries[delivery.ID]
+			record.lockedUntil = lockedUntil
+			st.deliveries[delivery.ID] = record
+			deliveries[i] = copyDelivery(delivery)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+// UpdateDelivery stores the delivery state after an attempt or a redelivery request and releases its lease
+func (r *WebhookRepository) UpdateDelivery(ctx context.Context, delivery *models.WebhookDelivery) error {
+	return r.store.write(ctx, func(st *state) error {
+		record, exists := st.deliveries[delivery.ID]
+		if !exists {
+			return pkgerrors.ErrDeliveryNotFound
+		}
+
+		// Only the mutable columns are updated, like in the SQL implementations
+		updated := copyDelivery(*delivery)
+		record.delivery.Status = updated.Status
+		record.delivery.Attempts = updated.Attempts
+		record.delivery.LastError = updated.LastError
+		record.delivery.NextAttemptAt = updated.NextAttemptAt
+		record.delivery.DeliveredAt = updated.DeliveredAt
+		record.lockedUntil = time.Time{}
+		st.deliveries[delivery.ID] = record
+		return nil
+	})
+}
+
+// selectDeliveries returns copies of the deliveries matching the filter
+func (r *WebhookRepository) selectDeliveries(ctx context.Context, match func(d *models.WebhookDelivery) bool) ([]deliveryRecord, error) {
+	var records []deliveryRecord
+	err := r.store.read(ctx, func(st *state) error {
+		for _, record := range st.deliveries {
+			if match(&record.delivery) {
+				record.delivery = copyDelivery(record.delivery)
+				records = append(records, record)
+			}
+		}
+		return nil
+	})
+	return records, err
+}
+
+// limitDeliveries returns at most limit deliveries from the sorted records
+func limitDeliveries(records []deliveryRecord, limit int) []models.WebhookDelivery {
+	if limit > 0 && len(records) > limit {
+		records = records[:limit]
+	}
+
+	deliveries := make([]models.WebhookDelivery, 0, len(records))
+	for _, record := range records {
+		deliveries = append(deliveries, record.delivery)
+	}
+	return deliveries
+}
@@ -0,0 +1,144 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"avito-backend-trainee-assignment-autumn-2025/internal/domain/models"
+	"avito-backend-trainee-assignment-autumn-2025/internal/repository"
+	pkgerrors "avito-backend-trainee-assignment-autumn-2025/pkg/errors"
+	"avito-backend-trainee-assignment-autumn-2025/pkg/logger"
+)
+
+// AbsenceRepository implements repository.AbsenceRepository for PostgreSQL
+type AbsenceRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewAbsenceRepository creates a new absence repository
+func NewAbsenceRepository(pool *pgxpool.Pool) *AbsenceRepository {
+	return &AbsenceRepository{pool: pool}
+}
+
+// Create stores a new absence
+func (r *AbsenceRepository) Create(ctx context.Context, absence *models.Absence) error {
+	executor := repository.GetTx(ctx, r.pool)
+
+	query := `
+		INSERT INTO user_absences (id, user_id, start_date, end_date, reason, source_uid, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+
+	_, err := executor.Exec(ctx, query,
+		absence.ID, absence.UserID, absence.StartDate, absence.EndDate, absence.Reason, absence.SourceUID, absence.CreatedAt,
+	)
+	if err != nil {
+		logger.Error("Failed to create absence for user %s: %v", absence.UserID, err)
+		// Check for foreign key violation (user doesn't exist)
+		if isPgForeignKeyViolation(err) {
+			return pkgerrors.ErrUserNotFound
+		}
+		return fmt.Errorf("failed to create absence: %w", err)
+	}
+
+	logger.Info("Created absence %s for user %s", absence.ID, absence.UserID)
+	return nil
+}
+
+// Delete removes an absence
+func (r *AbsenceRepository) Delete(ctx context.Context, id string) error {
+	executor := repository.GetTx(ctx, r.pool)
+
+	commandTag, err := executor.Exec(ctx, `DELETE FROM user_absences WHERE id = $1`, id)
+	if err != nil {
+		logger.Error("Failed to delete absence %s: %v", id, err)
+		return fmt.Errorf("failed to delete absence: %w", err)
+	}
+
+	if commandTag.RowsAffected() == 0 {
+		return pkgerrors.ErrAbsenceNotFound
+	}
+
+	logger.Info("Deleted absence %s", id)
+	return nil
+}
+
+// DeleteImported removes the user's absences imported from a calendar
+func (r *AbsenceRepository) DeleteImported(ctx context.Context, userID string) (int, error) {
+	executor := repository.GetTx(ctx, r.pool)
+
+	commandTag, err := executor.Exec(ctx, `DELETE FROM user_absences WHERE user_id = $1 AND source_uid <> ''`, userID)
+	if err != nil {
+		logger.Error("Failed to delete imported absences of user %s: %v", userID, err)
+		return 0, fmt.Errorf("failed to delete imported absences: %w", err)
+	}
+
+	logger.Info("Deleted %d imported absences of user %s", commandTag.RowsAffected(), userID)
+	return int(commandTag.RowsAffected()), nil
+}
+
+// ListByUser returns all absences of a user ordered by start date
+func (r *AbsenceRepository) ListByUser(ctx context.Context, userID string) ([]models.Absence, error) {
+	executor := repository.GetTx(ctx, r.pool)
+
+	query := `
+		SELECT id, user_id, start_date, end_date, reason, source_uid, created_at
+		FROM user_absences
+		WHERE user_id = $1
+		ORDER BY start_date, end_date, id
+	`
+
+	rows, err := executor.Query(ctx, query, userID)
+	if err != nil {
+		logger.Error("Failed to list absences of user %s: %v", userID, err)
+		return nil, fmt.Errorf("failed to list absences: %w", err)
+	}
+	return scanAbsences(rows)
+}
+
+// ListByTeam returns the absences of the team's members that end on or after from, ordered by start date
+func (r *AbsenceRepository) ListByTeam(ctx context.Context, teamName string, from time.Time) ([]models.Absence, error) {
+	executor := repository.GetTx(ctx, r.pool)
+
+	query := `
+		SELECT a.id, a.user_id, a.start_date, a.end_date, a.reason, a.source_uid, a.created_at
+		FROM user_absences a
+		INNER JOIN users u ON u.id = a.user_id
+		WHERE u.team_name = $1 AND a.end_date >= $2
+		ORDER BY a.start_date, a.end_date, a.id
+	`
+
+	rows, err := executor.Query(ctx, query, teamName, from)
+	if err != nil {
+		logger.Error("Failed to list absences of team %s: %v", teamName, err)
+		return nil, fmt.Errorf("failed to list absences: %w", err)
+	}
+	return scanAbsences(rows)
+}
+
+// scanAbsences reads absences from rows and closes them
+func scanAbsences(rows pgx.Rows) ([]models.Absence, error) {
+	defer rows.Close()
+
+	absences := make([]models.Absence, 0)
+	for rows.Next() {
+		var absence models.Absence
+		if err := rows.Scan(
+			&absence.ID, &absence.UserID, &absence.StartDate, &absence.EndDate, &absence.Reason, &absence.SourceUID, &absence.CreatedAt,
+		); err != nil {
+			logger.Error("Failed to scan absence: %v", err)
+			return nil, fmt.Errorf("failed to scan absence: %w", err)
+		}
+		absences = append(absences, absence)
+	}
+
+	if err := rows.Err(); err != nil {
+		logger.Error("Error iterating absences: %v", err)
+		return nil, fmt.Errorf("error iterating absences: %w", err)
+	}
+	return absences, nil
+}
@@ -0,0 +1,80 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"avito-backend-trainee-assignment-autumn-2025/pkg/logger"
+)
+
+// AdvisoryLock elects a single leader among replicas with a session-level advisory lock
+// The leader keeps one pool connection checked out: the lock lives as long as that session,
+// so it is released even if the process dies without resigning
+type AdvisoryLock struct {
+	pool *pgxpool.Pool
+	key  int64
+
+	// conn holds the lock while this replica leads; owned by the caller's goroutine
+	conn *pgxpool.Conn
+}
+
+// NewAdvisoryLock creates an advisory lock identified by key
+func NewAdvisoryLock(pool *pgxpool.Pool, key int64) *AdvisoryLock {
+	return &AdvisoryLock{pool: pool, key: key}
+}
+
+// TryLead reports whether this replica holds the lock, taking it if it is free
+func (l *AdvisoryLock) TryLead(ctx context.Context) (bool, error) {
+	if l.conn != nil {
+		// The lock is held for as long as the session is alive
+		if _, err := l.conn.Exec(ctx, "SELECT 1"); err == nil {
+			return true, nil
+		}
+		logger.Warn("Advisory lock %d session was lost", l.key)
+		l.drop(ctx)
+	}
+
+	conn, err := l.pool.Acquire(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to acquire connection: %w", err)
+	}
+
+	var locked bool
+	if err := conn.QueryRow(ctx, "SELECT pg_try_advisory_lock($1)", l.key).Scan(&locked); err != nil {
+		conn.Release()
+		return false, fmt.Errorf("failed to try advisory lock: %w", err)
+	}
+	if !locked {
+		conn.Release()
+		return false, nil
+	}
+
+	l.conn = conn
+	return true, nil
+}
+
+// Resign releases the lock and returns the connection to the pool
+func (l *AdvisoryLock) Resign(ctx context.Context) {
+	if l.conn == nil {
+		return
+	}
+
+	if _, err := l.conn.Exec(ctx, "SELECT pg_advisory_unlock($1)", l.key); err != nil {
+		logger.Warn("Failed to release advisory lock %d, closing its session: %v", l.key, err)
+		l.drop(ctx)
+		return
+	}
+
+	l.conn.Release()
+	l.conn = nil
+}
+
+// drop closes the lock's session, which releases the lock if the server still holds it
+func (l *AdvisoryLock) drop(ctx context.Context) {
+	_ = l.conn.Conn().Close(ctx)
+	// The pool discards closed connections on release
+	l.conn.Release()
+	l.conn = nil
+}
@@ -0,0 +1,103 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"avito-backend-trainee-assignment-autumn-2025/internal/domain/models"
+	"avito-backend-trainee-assignment-autumn-2025/internal/repository"
+	pkgerrors "avito-backend-trainee-assignment-autumn-2025/pkg/errors"
+	"avito-backend-trainee-assignment-autumn-2025/pkg/logger"
+)
+
+// AffinityRepository implements repository.AffinityRepository for PostgreSQL
+type AffinityRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewAffinityRepository creates a new affinity repository
+func NewAffinityRepository(pool *pgxpool.Pool) *AffinityRepository {
+	return &AffinityRepository{pool: pool}
+}
+
+// Set creates or replaces the affinity of a pair
+func (r *AffinityRepository) Set(ctx context.Context, affinity *models.Affinity) error {
+	executor := repository.GetTx(ctx, r.pool)
+
+	query := `
+		INSERT INTO reviewer_affinities (author_id, reviewer_id, weight, conflict, reason)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (author_id, reviewer_id) DO UPDATE
+		SET weight = EXCLUDED.weight, conflict = EXCLUDED.conflict, reason = EXCLUDED.reason, updated_at = NOW()
+	`
+
+	_, err := executor.Exec(ctx, query, affinity.AuthorID, affinity.ReviewerID, affinity.Weight, affinity.Conflict, affinity.Reason)
+	if err != nil {
+		logger.Error("Failed to set affinity of %s to %s: %v", affinity.AuthorID, affinity.ReviewerID, err)
+		// Check for foreign key violation (either user doesn't exist)
+		if isPgForeignKeyViolation(err) {
+			return pkgerrors.ErrUserNotFound
+		}
+		return fmt.Errorf("failed to set affinity: %w", err)
+	}
+
+	logger.Info("Set affinity of %s to %s to %d (conflict: %t)", affinity.AuthorID, affinity.ReviewerID, affinity.Weight, affinity.Conflict)
+	return nil
+}
+
+// Delete removes the affinity of a pair
+func (r *AffinityRepository) Delete(ctx context.Context, authorID, reviewerID string) error {
+	executor := repository.GetTx(ctx, r.pool)
+
+	commandTag, err := executor.Exec(ctx,
+		`DELETE FROM reviewer_affinities WHERE author_id = $1 AND reviewer_id = $2`, authorID, reviewerID,
+	)
+	if err != nil {
+		logger.Error("Failed to delete affinity of %s to %s: %v", authorID, reviewerID, err)
+		return fmt.Errorf("failed to delete affinity: %w", err)
+	}
+
+	if commandTag.RowsAffected() == 0 {
+		return pkgerrors.ErrAffinityNotFound
+	}
+
+	logger.Info("Deleted affinity of %s to %s", authorID, reviewerID)
+	return nil
+}
+
+// ListByUser returns the affinities where the user is the author or the reviewer
+func (r *AffinityRepository) ListByUser(ctx context.Context, userID string) ([]models.Affinity, error) {
+	executor := repository.GetTx(ctx, r.pool)
+
+	query := `
+		SELECT author_id, reviewer_id, weight, conflict, reason
+		FROM reviewer_affinities
+		WHERE author_id = $1 OR reviewer_id = $1
+		ORDER BY author_id, reviewer_id
+	`
+
+	rows, err := executor.Query(ctx, query, userID)
+	if err != nil {
+		logger.Error("Failed to list affinities of user %s: %v", userID, err)
+		return nil, fmt.Errorf("failed to list affinities: %w", err)
+	}
+	defer rows.Close()
+
+	affinities := make([]models.Affinity, 0)
+	for rows.Next() {
+		var affinity models.Affinity
+		if err := rows.Scan(&affinity.AuthorID, &affinity.ReviewerID, &affinity.Weight, &affinity.Conflict, &affinity.Reason); err != nil {
+			logger.Error("Failed to scan affinity: %v", err)
+			return nil, fmt.Errorf("failed to scan affinity: %w", err)
+		}
+		affinities = append(affinities, affinity)
+	}
+
+	if err := rows.Err(); err != nil {
+		logger.Error("Error iterating affinities: %v", err)
+		return nil, fmt.Errorf("error iterating affinities: %w", err)
+	}
+	return affinities, nil
+}
@@ -0,0 +1,124 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"avito-backend-trainee-assignment-autumn-2025/internal/domain/models"
+	"avito-backend-trainee-assignment-autumn-2025/internal/repository"
+	pkgerrors "avito-backend-trainee-assignment-autumn-2025/pkg/errors"
+	"avito-backend-trainee-assignment-autumn-2025/pkg/logger"
+)
+
+// CodeHostAccountRepository implements repository.CodeHostAccountRepository for PostgreSQL
+type CodeHostAccountRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewCodeHostAccountRepository creates a new code host account repository
+func NewCodeHostAccountRepository(pool *pgxpool.Pool) *CodeHostAccountRepository {
+	return &CodeHostAccountRepository{pool: pool}
+}
+
+// Link creates or replaces the mapping of a login
+func (r *CodeHostAccountRepository) Link(ctx context.Context, account *models.CodeHostAccount) error {
+	executor := repository.GetTx(ctx, r.pool)
+
+	query := `
+		INSERT INTO code_host_accounts (provider, login, user_id, created_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (provider, login) DO UPDATE SET user_id = EXCLUDED.user_id, created_at = EXCLUDED.created_at
+	`
+
+	_, err := executor.Exec(ctx, query, account.Provider, account.Login, account.UserID, account.CreatedAt)
+	if err != nil {
+		logger.Error("Failed to link %s account %s: %v", account.Provider, account.Login, err)
+		// Check for foreign key violation (user doesn't exist)
+		if isPgForeignKeyViolation(err) {
+			return pkgerrors.ErrUserNotFound
+		}
+		return fmt.Errorf("failed to link account: %w", err)
+	}
+
+	logger.Info("Linked %s account %s to user %s", account.Provider, account.Login, account.UserID)
+	return nil
+}
+
+// GetUserID returns the user linked to a login
+func (r *CodeHostAccountRepository) GetUserID(ctx context.Context, provider models.CodeHost, login string) (string, error) {
+	executor := repository.GetTx(ctx, r.pool)
+
+	query := `SELECT user_id FROM code_host_accounts WHERE provider = $1 AND login = $2`
+
+	var userID string
+	if err := executor.QueryRow(ctx, query, provider, login).Scan(&userID); err != nil {
+		if isPgNoRows(err) {
+			return "", pkgerrors.ErrAccountNotLinked
+		}
+		logger.Error("Failed to get user of %s account %s: %v", provider, login, err)
+		return "", fmt.Errorf("failed to get linked user: %w", err)
+	}
+
+	return userID, nil
+}
+
+// GetLogin returns the most recently linked login of a user
+func (r *CodeHostAccountRepository) GetLogin(ctx context.Context, provider models.CodeHost, userID string) (string, error) {
+	executor := repository.GetTx(ctx, r.pool)
+
+	query := `
+		SELECT login FROM code_host_accounts
+		WHERE provider = $1 AND user_id = $2
+		ORDER BY created_at DESC, login
+		LIMIT 1
+	`
+
+	var login string
+	if err := executor.QueryRow(ctx, query, provider, userID).Scan(&login); err != nil {
+		if isPgNoRows(err) {
+			return "", pkgerrors.ErrAccountNotLinked
+		}
+		logger.Error("Failed to get %s login of user %s: %v", provider, userID, err)
+		return "", fmt.Errorf("failed to get linked login: %w", err)
+	}
+
+	return login, nil
+}
+
+// List returns mappings ordered by provider and login
+func (r *CodeHostAccountRepository) List(ctx context.Context, provider models.CodeHost) ([]models.CodeHostAccount, error) {
+	executor := repository.GetTx(ctx, r.pool)
+
+	query := `
+		SELECT provider, login, user_id, created_at
+		FROM code_host_accounts
+		WHERE $1 = '' OR provider = $1
+		ORDER BY provider, login
+	`
+
+	rows, err := executor.Query(ctx, query, string(provider))
+	if err != nil {
+		logger.Error("Failed to list accounts: %v", err)
+		return nil, fmt.Errorf("failed to list accounts: %w", err)
+	}
+	defer rows.Close()
+
+	accounts := make([]models.CodeHostAccount, 0)
+	for rows.Next() {
+		var account models.CodeHostAccount
+		if err := rows.Scan(&account.Provider, &account.Login, &account.UserID, &account.CreatedAt); err != nil {
+			logger.Error("Failed to scan account: %v", err)
+			return nil, fmt.Errorf("failed to scan account: %w", err)
+		}
+		accounts = append(accounts, account)
+	}
+
+	if err := rows.Err(); err != nil {
+		logger.Error("Error iterating accounts: %v", err)
+		return nil, fmt.Errorf("error iterating accounts: %w", err)
+	}
+
+	return accounts, nil
+}
@@ -41,3 +41,11 @@ func isPgCheckViolation(err error) bool {
 	}
 	return false
 }
+
+// textArray returns tags for a NOT NULL TEXT[] column; pgx writes a nil slice as NULL
+func textArray(tags []string) []string {
+	if tags == nil {
+		return []string{}
+	}
+	return tags
+}
@@ -0,0 +1,154 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"avito-backend-trainee-assignment-autumn-2025/internal/domain/models"
+	"avito-backend-trainee-assignment-autumn-2025/internal/repository"
+	pkgerrors "avito-backend-trainee-assignment-autumn-2025/pkg/errors"
+	"avito-backend-trainee-assignment-autumn-2025/pkg/logger"
+)
+
+// OutboxRepository implements repository.OutboxRepository for PostgreSQL
+type OutboxRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewOutboxRepository creates a new outbox repository
+func NewOutboxRepository(pool *pgxpool.Pool) *OutboxRepository {
+	return &OutboxRepository{pool: pool}
+}
+
+// Add inserts a message into the outbox
+func (r *OutboxRepository) Add(ctx context.Context, msg *models.OutboxMessage) error {
+	executor := repository.GetTx(ctx, r.pool)
+
+	query := `
+		INSERT INTO outbox (id, event_type, payload, next_attempt_at, created_at)
+		VALUES ($1, $2, $3, $4, $4)
+	`
+
+	_, err := executor.Exec(ctx, query, msg.ID, msg.EventType, msg.Payload, msg.CreatedAt)
+	if err != nil {
+		logger.Error("Failed to add outbox message %s: %v", msg.ID, err)
+		return fmt.Errorf("failed to add outbox message: %w", err)
+	}
+
+	logger.Debug("Added outbox message %s (%s)", msg.ID, msg.EventType)
+	return nil
+}
+
+// LockPending selects due pending messages with FOR UPDATE SKIP LOCKED,
+// so concurrent dispatchers never pick the same message
+func (r *OutboxRepository) LockPending(ctx context.Context, now time.Time, limit int) ([]models.OutboxMessage, error) {
+	executor := repository.GetTx(ctx, r.pool)
+
+	query := `
+		SELECT id, event_type, payload, status, attempts, last_error, next_attempt_at, published_sinks,
+			created_at, published_at
+		FROM outbox
+		WHERE status = 'pending' AND next_attempt_at <= $1
+		ORDER BY seq
+		LIMIT $2
+		FOR UPDATE SKIP LOCKED
+	`
+
+	rows, err := executor.Query(ctx, query, now, limit)
+	if err != nil {
+		logger.Error("Failed to lock pending outbox messages: %v", err)
+		return nil, fmt.Errorf("failed to lock pending outbox messages: %w", err)
+	}
+	defer rows.Close()
+
+	messages := make([]models.OutboxMessage, 0)
+	for rows.Next() {
+		var msg models.OutboxMessage
+		err := rows.Scan(
+			&msg.ID, &msg.EventType, &msg.Payload, &msg.Status, &msg.Attempts, &msg.LastError,
+			&msg.NextAttemptAt, &msg.PublishedSinks, &msg.CreatedAt, &msg.PublishedAt,
+		)
+		if err != nil {
+			logger.Error("Failed to scan outbox message: %v", err)
+			return nil, fmt.Errorf("failed to scan outbox message: %w", err)
+		}
+		messages = append(messages, msg)
+	}
+
+	if err := rows.Err(); err != nil {
+		logger.Error("Error iterating outbox messages: %v", err)
+		return nil, fmt.Errorf("error iterating outbox messages: %w", err)
+	}
+
+	return messages, nil
+}
+
+// MarkPublished sets the published status and published_at of a message
+func (r *OutboxRepository) MarkPublished(ctx context.Context, id string, publishedAt time.Time) error {
+	executor := repository.GetTx(ctx, r.pool)
+
+	query := `UPDATE outbox SET status = 'published', published_at = $2 WHERE id = $1`
+
+	commandTag, err := executor.Exec(ctx, query, id, publishedAt)
+	if err != nil {
+		logger.Error("Failed to mark outbox message %s as published: %v", id, err)
+		return fmt.Errorf("failed to mark outbox message as published: %w", err)
+	}
+
+	if commandTag.RowsAffected() == 0 {
+		return pkgerrors.ErrOutboxMessageNotFound
+	}
+
+	return nil
+}
+
+// MarkFailed records a failed publish attempt
+func (r *OutboxRepository) MarkFailed(ctx context.Context, msg *models.OutboxMessage) error {
+	executor := repository.GetTx(ctx, r.pool)
+
+	query := `
+		UPDATE outbox
+		SET status = $2, attempts = $3, last_error = $4, next_attempt_at = $5, published_sinks = $6
+		WHERE id = $1
+	`
+
+	commandTag, err := executor.Exec(ctx, query,
+		msg.ID, msg.Status, msg.Attempts, msg.LastError, msg.NextAttemptAt, publishedSinks(msg),
+	)
+	if err != nil {
+		logger.Error("Failed to mark outbox message %s as failed: %v", msg.ID, err)
+		return fmt.Errorf("failed to mark outbox message as failed: %w", err)
+	}
+
+	if commandTag.RowsAffected() == 0 {
+		return pkgerrors.ErrOutboxMessageNotFound
+	}
+
+	return nil
+}
+
+// DeletePublishedBefore removes messages published before the given time
+func (r *OutboxRepository) DeletePublishedBefore(ctx context.Context, before time.Time) (int64, error) {
+	executor := repository.GetTx(ctx, r.pool)
+
+	query := `DELETE FROM outbox WHERE published_at < $1`
+
+	commandTag, err := executor.Exec(ctx, query, before)
+	if err != nil {
+		logger.Error("Failed to delete published outbox messages: %v", err)
+		return 0, fmt.Errorf("failed to delete published outbox messages: %w", err)
+	}
+
+	return commandTag.RowsAffected(), nil
+}
+
+// publishedSinks returns the sinks for a NOT NULL TEXT[] column
+func publishedSinks(msg *models.OutboxMessage) []string {
+	if msg.PublishedSinks == nil {
+		return []string{}
+	}
+	return msg.PublishedSinks
+}
@@ -28,11 +28,11 @@ func (r *PRRepository) Create(ctx context.Context, pr *models.PullRequest) error
 	executor := repository.GetTx(ctx, r.pool)
 
 	query := `
-		INSERT INTO pull_requests (id, name, author_id, status)
-		VALUES ($1, $2, $3, $4)
+		INSERT INTO pull_requests (id, name, author_id, status, required_tags)
+		VALUES ($1, $2, $3, $4, $5)
 	`
 
-	_, err := executor.Exec(ctx, query, pr.ID, pr.Name, pr.AuthorID, pr.Status)
+	_, err := executor.Exec(ctx, query, pr.ID, pr.Name, pr.AuthorID, pr.Status, textArray(pr.RequiredTags))
 	if err != nil {
 		logger.Error("Failed to create PR %s: %v", pr.ID, err)
 		// Check for unique violation
@@ -45,7 +45,7 @@ func (r *PRRepository) Create(ctx context.Context, pr *models.PullRequest) error
 		}
 		// Check for check constraint violation (invalid status)
 		if isPgCheckViolation(err) {
-			return fmt.Errorf("invalid PR status: %s", pr.Status)
+			return pkgerrors.NewValidationError("status", fmt.Sprintf("is invalid: %s", pr.Status))
 		}
 		return fmt.Errorf("failed to create PR: %w", err)
 	}
@@ -60,14 +60,14 @@ func (r *PRRepository) GetByID(ctx context.Context, id string) (*models.PullRequ
 
 	// Get PR details
 	query := `
-		SELECT id, name, author_id, status, created_at, merged_at
+		SELECT id, name, author_id, status, created_at, merged_at, required_tags
 		FROM pull_requests
 		WHERE id = $1
 	`
 
 	var pr models.PullRequest
 	err := executor.QueryRow(ctx, query, id).Scan(
-		&pr.ID, &pr.Name, &pr.AuthorID, &pr.Status, &pr.CreatedAt, &pr.MergedAt,
+		&pr.ID, &pr.Name, &pr.AuthorID, &pr.Status, &pr.CreatedAt, &pr.MergedAt, &pr.RequiredTags,
 	)
 	if err != nil {
 		if isPgNoRows(err) {
@@ -109,7 +109,7 @@ func (r *PRRepository) Update(ctx context.Context, pr *models.PullRequest) error
 		}
 		// Check for check constraint violation (invalid status)
 		if isPgCheckViolation(err) {
-			return fmt.Errorf("invalid PR status: %s", pr.Status)
+			return pkgerrors.NewValidationError("status", fmt.Sprintf("is invalid: %s", pr.Status))
 		}
 		return fmt.Errorf("failed to update PR: %w", err)
 	}
@@ -295,3 +295,167 @@ func (r *PRRepository) GetPRsByReviewerID(ctx context.Context, reviewerID string
 	logger.Debug("Retrieved %d PRs for reviewer %s", len(prs), reviewerID)
 	return prs, nil
 }
+
+// CountOpenReviews returns how many open PRs each of the given users reviews; users without any are omitted
+func (r *PRRepository) CountOpenReviews(ctx context.Context, reviewerIDs []string) (map[string]int, error) {
+	executor := repository.GetTx(ctx, r.pool)
+
+	query := `
+		SELECT prr.reviewer_id, COUNT(*)
+		FROM pr_reviewers prr
+		INNER JOIN pull_requests pr ON pr.id = prr.pr_id
+		WHERE prr.reviewer_id = ANY($1) AND pr.status = 'OPEN'
+		GROUP BY prr.reviewer_id
+	`
+
+	rows, err := executor.Query(ctx, query, reviewerIDs)
+	if err != nil {
+		logger.Error("Failed to count open reviews: %v", err)
+		return nil, fmt.Errorf("failed to count open reviews: %w", err)
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int)
+	for rows.Next() {
+		var reviewerID string
+		var count int
+		if err := rows.Scan(&reviewerID, &count); err != nil {
+			logger.Error("Failed to scan open review count: %v", err)
+			return nil, fmt.Errorf("failed to scan open review count: %w", err)
+		}
+		counts[reviewerID] = count
+	}
+
+	if err := rows.Err(); err != nil {
+		logger.Error("Error iterating open review counts: %v", err)
+		return nil, fmt.Errorf("error iterating open review counts: %w", err)
+	}
+
+	logger.Debug("Counted open reviews of %d users", len(reviewerIDs))
+	return counts, nil
+}
+
+// ListStaleReviews returns active members of the team reviewing open PRs who were assigned
+// (or last reminded) no later than before; oldest assignments first
+func (r *PRRepository) ListStaleReviews(ctx context.Context, teamName string, before time.Time, limit int) ([]models.StaleReview, error) {
+	executor := repository.GetTx(ctx, r.pool)
+
+	// assigned_at has no time zone and is compared in the session time zone, like NOW() stored it
+	query := `
+		SELECT pr.id, pr.name, pr.author_id, prr.reviewer_id, prr.assigned_at, prr.reminded_at
+		FROM pr_reviewers prr
+		INNER JOIN pull_requests pr ON pr.id = prr.pr_id
+		INNER JOIN users u ON u.id = prr.reviewer_id
+		WHERE u.team_name = $1 AND u.is_active AND pr.status = 'OPEN'
+			AND COALESCE(prr.reminded_at, prr.assigned_at::timestamptz) <= $2
+		ORDER BY prr.assigned_at, pr.id, prr.reviewer_id
+		LIMIT $3
+	`
+
+	rows, err := executor.Query(ctx, query, teamName, before, limit)
+	if err != nil {
+		logger.Error("Failed to list stale reviews of team %s: %v", teamName, err)
+		return nil, fmt.Errorf("failed to list stale reviews: %w", err)
+	}
+	defer rows.Close()
+
+	reviews := make([]models.StaleReview, 0)
+	for rows.Next() {
+		var review models.StaleReview
+		if err := rows.Scan(
+			&review.PullRequestID, &review.PullRequestName, &review.AuthorID, &review.ReviewerID,
+			&review.AssignedAt, &review.RemindedAt,
+		); err != nil {
+			logger.Error("Failed to scan stale review: %v", err)
+			return nil, fmt.Errorf("failed to scan stale review: %w", err)
+		}
+		reviews = append(reviews, review)
+	}
+
+	if err := rows.Err(); err != nil {
+		logger.Error("Error iterating stale reviews: %v", err)
+		return nil, fmt.Errorf("error iterating stale reviews: %w", err)
+	}
+
+	logger.Debug("Found %d stale reviews in team %s", len(reviews), teamName)
+	return reviews, nil
+}
+
+// MarkReviewReminded records when a reviewer was reminded about a PR
+func (r *PRRepository) MarkReviewReminded(ctx context.Context, prID, reviewerID string, at time.Time) error {
+	executor := repository.GetTx(ctx, r.pool)
+
+	query := `UPDATE pr_reviewers SET reminded_at = $3 WHERE pr_id = $1 AND reviewer_id = $2`
+
+	commandTag, err := executor.Exec(ctx, query, prID, reviewerID, at)
+	if err != nil {
+		logger.Error("Failed to mark reviewer %s of PR %s as reminded: %v", reviewerID, prID, err)
+		return fmt.Errorf("failed to mark review as reminded: %w", err)
+	}
+
+	if commandTag.RowsAffected() == 0 {
+		return pkgerrors.ErrReviewerNotAssigned
+	}
+	return nil
+}
+
+// AddReassignment records a reviewer replacement in the PR history
+func (r *PRRepository) AddReassignment(ctx context.Context, reassignment *models.Reassignment) error {
+	executor := repository.GetTx(ctx, r.pool)
+
+	query := `
+		INSERT INTO reviewer_reassignments (id, pr_id, old_reviewer_id, new_reviewer_id, reason, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+
+	_, err := executor.Exec(ctx, query,
+		reassignment.ID, reassignment.PullRequestID, reassignment.OldReviewerID, reassignment.NewReviewerID,
+		reassignment.Reason, reassignment.CreatedAt,
+	)
+	if err != nil {
+		logger.Error("Failed to record reassignment on PR %s: %v", reassignment.PullRequestID, err)
+		if isPgForeignKeyViolation(err) {
+			return pkgerrors.ErrPRNotFound
+		}
+		return fmt.Errorf("failed to record reassignment: %w", err)
+	}
+	return nil
+}
+
+// ListReassignments returns the reviewer replacements of a PR, oldest first
+func (r *PRRepository) ListReassignments(ctx context.Context, prID string) ([]models.Reassignment, error) {
+	executor := repository.GetTx(ctx, r.pool)
+
+	query := `
+		SELECT id, pr_id, old_reviewer_id, new_reviewer_id, reason, created_at
+		FROM reviewer_reassignments
+		WHERE pr_id = $1
+		ORDER BY created_at, id
+	`
+
+	rows, err := executor.Query(ctx, query, prID)
+	if err != nil {
+		logger.Error("Failed to list reassignments of PR %s: %v", prID, err)
+		return nil, fmt.Errorf("failed to list reassignments: %w", err)
+	}
+	defer rows.Close()
+
+	reassignments := make([]models.Reassignment, 0)
+	for rows.Next() {
+		var reassignment models.Reassignment
+		if err := rows.Scan(
+			&reassignment.ID, &reassignment.PullRequestID, &reassignment.OldReviewerID,
+			&reassignment.NewReviewerID, &reassignment.Reason, &reassignment.CreatedAt,
+		); err != nil {
+			logger.Error("Failed to scan reassignment: %v", err)
+			return nil, fmt.Errorf("failed to scan reassignment: %w", err)
+		}
+		reassignments = append(reassignments, reassignment)
+	}
+
+	if err := rows.Err(); err != nil {
+		logger.Error("Error iterating reassignments of PR %s: %v", prID, err)
+		return nil, fmt.Errorf("error iterating reassignments: %w", err)
+	}
+	return reassignments, nil
+}
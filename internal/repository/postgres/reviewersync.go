@@ -0,0 +1,135 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"avito-backend-trainee-assignment-autumn-2025/internal/domain/models"
+	"avito-backend-trainee-assignment-autumn-2025/internal/repository"
+	pkgerrors "avito-backend-trainee-assignment-autumn-2025/pkg/errors"
+	"avito-backend-trainee-assignment-autumn-2025/pkg/logger"
+)
+
+// ReviewerSyncRepository implements repository.ReviewerSyncRepository for PostgreSQL
+type ReviewerSyncRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewReviewerSyncRepository creates a new reviewer sync repository
+func NewReviewerSyncRepository(pool *pgxpool.Pool) *ReviewerSyncRepository {
+	return &ReviewerSyncRepository{pool: pool}
+}
+
+// Create stores a new reviewer sync
+func (r *ReviewerSyncRepository) Create(ctx context.Context, sync *models.ReviewerSync) error {
+	executor := repository.GetTx(ctx, r.pool)
+
+	query := `
+		INSERT INTO reviewer_syncs (id, event_id, pull_request_id, action, login, status, attempts,
+			last_error, next_attempt_at, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $10)
+	`
+
+	_, err := executor.Exec(ctx, query,
+		sync.ID, sync.EventID, sync.PullRequestID, sync.Action, sync.Login, sync.Status,
+		sync.Attempts, sync.LastError, sync.NextAttemptAt, sync.CreatedAt,
+	)
+	if err != nil {
+		logger.Error("Failed to create reviewer sync %s: %v", sync.ID, err)
+		return fmt.Errorf("failed to create reviewer sync: %w", err)
+	}
+
+	logger.Debug("Created reviewer sync %s (%s %s on %s)", sync.ID, sync.Action, sync.Login, sync.PullRequestID)
+	return nil
+}
+
+// ClaimDue leases pending syncs whose next attempt is due
+// Only the earliest pending sync of a PR can be claimed, so the syncs of a PR run in event order
+// Rows locked by a concurrent claim are skipped, so every sync goes to one syncer
+func (r *ReviewerSyncRepository) ClaimDue(ctx context.Context, now, lockedUntil time.Time, limit int) ([]models.ReviewerSync, error) {
+	executor := repository.GetTx(ctx, r.pool)
+
+	query := `
+		WITH claimed AS (
+			UPDATE reviewer_syncs
+			SET locked_until = $2
+			WHERE id IN (
+				SELECT s.id
+				FROM reviewer_syncs s
+				WHERE s.status = 'pending' AND s.next_attempt_at <= $1 AND (s.locked_until IS NULL OR s.locked_until <= $1)
+					AND NOT EXISTS (
+						SELECT 1
+						FROM reviewer_syncs earlier
+						WHERE earlier.pull_request_id = s.pull_request_id AND earlier.status = 'pending'
+							AND earlier.seq < s.seq
+					)
+				ORDER BY s.next_attempt_at, s.seq
+				LIMIT $3
+				FOR UPDATE SKIP LOCKED
+			)
+			RETURNING id, event_id, pull_request_id, action, login, status, attempts, last_error,
+				next_attempt_at, created_at, completed_at, seq
+		)
+		SELECT id, event_id, pull_request_id, action, login, status, attempts, last_error,
+			next_attempt_at, created_at, completed_at
+		FROM claimed
+		ORDER BY next_attempt_at, seq
+	`
+
+	rows, err := executor.Query(ctx, query, now, lockedUntil, limit)
+	if err != nil {
+		logger.Error("Failed to claim due reviewer syncs: %v", err)
+		return nil, fmt.Errorf("failed to claim due reviewer syncs: %w", err)
+	}
+	defer rows.Close()
+
+	syncs := make([]models.ReviewerSync, 0)
+	for rows.Next() {
+		var sync models.ReviewerSync
+		err := rows.Scan(
+			&sync.ID, &sync.EventID, &sync.PullRequestID, &sync.Action, &sync.Login, &sync.Status,
+			&sync.Attempts, &sync.LastError, &sync.NextAttemptAt, &sync.CreatedAt, &sync.CompletedAt,
+		)
+		if err != nil {
+			logger.Error("Failed to scan reviewer sync: %v", err)
+			return nil, fmt.Errorf("failed to scan reviewer sync: %w", err)
+		}
+		syncs = append(syncs, sync)
+	}
+
+	if err := rows.Err(); err != nil {
+		logger.Error("Error iterating reviewer syncs: %v", err)
+		return nil, fmt.Errorf("error iterating reviewer syncs: %w", err)
+	}
+
+	return syncs, nil
+}
+
+// Update stores the sync state after an attempt and releases its lease
+func (r *ReviewerSyncRepository) Update(ctx context.Context, sync *models.ReviewerSync) error {
+	executor := repository.GetTx(ctx, r.pool)
+
+	query := `
+		UPDATE reviewer_syncs
+		SET status = $2, attempts = $3, last_error = $4, next_attempt_at = $5, completed_at = $6,
+			locked_until = NULL, updated_at = NOW()
+		WHERE id = $1
+	`
+
+	commandTag, err := executor.Exec(ctx, query,
+		sync.ID, sync.Status, sync.Attempts, sync.LastError, sync.NextAttemptAt, sync.CompletedAt,
+	)
+	if err != nil {
+		logger.Error("Failed to update reviewer sync %s: %v", sync.ID, err)
+		return fmt.Errorf("failed to update reviewer sync: %w", err)
+	}
+
+	if commandTag.RowsAffected() == 0 {
+		return pkgerrors.ErrReviewerSyncNotFound
+	}
+
+	return nil
+}
@@ -46,22 +46,34 @@ func (r *TeamRepository) Create(ctx context.Context, team *models.Team) error {
 func (r *TeamRepository) GetByName(ctx context.Context, name string) (*models.Team, error) {
 	executor := repository.GetTx(ctx, r.pool)
 
-	// First, check if team exists
-	var teamExists bool
-	checkQuery := `SELECT EXISTS(SELECT 1 FROM teams WHERE name = $1)`
-	err := executor.QueryRow(ctx, checkQuery, name).Scan(&teamExists)
-	if err != nil {
-		logger.Error("Failed to check team existence %s: %v", name, err)
-		return nil, fmt.Errorf("failed to check team existence: %w", err)
+	// First, get the team itself
+	team := &models.Team{
+		Name:    name,
+		Members: make([]models.User, 0),
 	}
-
-	if !teamExists {
-		return nil, pkgerrors.ErrTeamNotFound
+	teamQuery := `
+		SELECT chat_webhook_url, review_sla_minutes, sla_action, COALESCE(lead_id, ''), timezone, weekend_days,
+			prefer_working_hours, prefer_within_hours, min_reviewer_seniority
+		FROM teams
+		WHERE name = $1
+	`
+	var weekend int16
+	err := executor.QueryRow(ctx, teamQuery, name).Scan(
+		&team.ChatWebhookURL, &team.ReviewSLAMinutes, &team.SLAAction, &team.LeadID, &team.Calendar.Timezone, &weekend,
+		&team.PreferWorkingHours, &team.PreferWithinHours, &team.MinReviewerSeniority,
+	)
+	if err != nil {
+		if isPgNoRows(err) {
+			return nil, pkgerrors.ErrTeamNotFound
+		}
+		logger.Error("Failed to get team %s: %v", name, err)
+		return nil, fmt.Errorf("failed to get team: %w", err)
 	}
+	team.Calendar.Weekend = models.WeekdaySet(weekend)
 
 	// Get team members
 	query := `
-		SELECT id, username, team_name, is_active
+		SELECT ` + userColumns + `
 		FROM users
 		WHERE team_name = $1
 		ORDER BY username
@@ -74,18 +86,13 @@ func (r *TeamRepository) GetByName(ctx context.Context, name string) (*models.Te
 	}
 	defer rows.Close()
 
-	team := &models.Team{
-		Name:    name,
-		Members: make([]models.User, 0),
-	}
-
 	for rows.Next() {
-		var user models.User
-		if err := rows.Scan(&user.ID, &user.Username, &user.TeamName, &user.IsActive); err != nil {
+		user, err := scanUser(rows)
+		if err != nil {
 			logger.Error("Failed to scan user for team %s: %v", name, err)
 			return nil, fmt.Errorf("failed to scan user: %w", err)
 		}
-		team.Members = append(team.Members, user)
+		team.Members = append(team.Members, *user)
 	}
 
 	if err := rows.Err(); err != nil {
@@ -112,3 +119,216 @@ func (r *TeamRepository) Exists(ctx context.Context, name string) (bool, error)
 
 	return exists, nil
 }
+
+// SetChatWebhook sets the incoming webhook URL for team notifications; an empty URL disables them
+func (r *TeamRepository) SetChatWebhook(ctx context.Context, name, url string) error {
+	executor := repository.GetTx(ctx, r.pool)
+
+	query := `UPDATE teams SET chat_webhook_url = $2 WHERE name = $1`
+
+	commandTag, err := executor.Exec(ctx, query, name, url)
+	if err != nil {
+		logger.Error("Failed to set chat webhook for team %s: %v", name, err)
+		return fmt.Errorf("failed to set chat webhook: %w", err)
+	}
+
+	if commandTag.RowsAffected() == 0 {
+		return pkgerrors.ErrTeamNotFound
+	}
+
+	logger.Info("Set chat webhook for team %s (enabled: %t)", name, url != "")
+	return nil
+}
+
+// SetReviewSLA sets how long reviewers of the team have (0 means the default) and what happens once they run out
+func (r *TeamRepository) SetReviewSLA(ctx context.Context, name string, minutes int, action models.SLAAction) error {
+	executor := repository.GetTx(ctx, r.pool)
+
+	query := `UPDATE teams SET review_sla_minutes = $2, sla_action = $3 WHERE name = $1`
+
+	commandTag, err := executor.Exec(ctx, query, name, minutes, action)
+	if err != nil {
+		logger.Error("Failed to set review SLA for team %s: %v", name, err)
+		return fmt.Errorf("failed to set review SLA: %w", err)
+	}
+
+	if commandTag.RowsAffected() == 0 {
+		return pkgerrors.ErrTeamNotFound
+	}
+
+	logger.Info("Set review SLA for team %s to %d minutes (action: %s)", name, minutes, action)
+	return nil
+}
+
+// SetLead sets the team lead; an empty leadID removes the lead
+func (r *TeamRepository) SetLead(ctx context.Context, name, leadID string) error {
+	executor := repository.GetTx(ctx, r.pool)
+
+	query := `UPDATE teams SET lead_id = NULLIF($2, '') WHERE name = $1`
+
+	commandTag, err := executor.Exec(ctx, query, name, leadID)
+	if err != nil {
+		logger.Error("Failed to set lead for team %s: %v", name, err)
+		if isPgForeignKeyViolation(err) {
+			return pkgerrors.ErrUserNotFound
+		}
+		return fmt.Errorf("failed to set team lead: %w", err)
+	}
+
+	if commandTag.RowsAffected() == 0 {
+		return pkgerrors.ErrTeamNotFound
+	}
+
+	logger.Info("Set lead for team %s to %q", name, leadID)
+	return nil
+}
+
+// SetCalendar sets the working calendar used to count the review SLA
+func (r *TeamRepository) SetCalendar(ctx context.Context, name string, calendar models.TeamCalendar) error {
+	executor := repository.GetTx(ctx, r.pool)
+
+	query := `UPDATE teams SET timezone = $2, weekend_days = $3 WHERE name = $1`
+
+	commandTag, err := executor.Exec(ctx, query, name, calendar.Timezone, int16(calendar.Weekend))
+	if err != nil {
+		logger.Error("Failed to set calendar for team %s: %v", name, err)
+		return fmt.Errorf("failed to set team calendar: %w", err)
+	}
+
+	if commandTag.RowsAffected() == 0 {
+		return pkgerrors.ErrTeamNotFound
+	}
+
+	logger.Info("Set calendar for team %s (timezone: %s, weekend: %v)", name, calendar.Timezone, calendar.Weekend.Days())
+	return nil
+}
+
+// SetWorkingHoursPreference sets whether reviewers inside their working hours are picked first
+func (r *TeamRepository) SetWorkingHoursPreference(ctx context.Context, name string, enabled bool, withinHours int) error {
+	executor := repository.GetTx(ctx, r.pool)
+
+	query := `UPDATE teams SET prefer_working_hours = $2, prefer_within_hours = $3 WHERE name = $1`
+
+	commandTag, err := executor.Exec(ctx, query, name, enabled, withinHours)
+	if err != nil {
+		logger.Error("Failed to set working hours preference for team %s: %v", name, err)
+		return fmt.Errorf("failed to set working hours preference: %w", err)
+	}
+
+	if commandTag.RowsAffected() == 0 {
+		return pkgerrors.ErrTeamNotFound
+	}
+
+	logger.Info("Set working hours preference for team %s (enabled: %t, within: %dh)", name, enabled, withinHours)
+	return nil
+}
+
+// SetSeniorityPolicy sets the level at least one reviewer of a PR must have; an empty level removes the policy
+func (r *TeamRepository) SetSeniorityPolicy(ctx context.Context, name string, level models.Seniority) error {
+	executor := repository.GetTx(ctx, r.pool)
+
+	query := `UPDATE teams SET min_reviewer_seniority = $2 WHERE name = $1`
+
+	commandTag, err := executor.Exec(ctx, query, name, string(level))
+	if err != nil {
+		logger.Error("Failed to set seniority policy for team %s: %v", name, err)
+		return fmt.Errorf("failed to set seniority policy: %w", err)
+	}
+
+	if commandTag.RowsAffected() == 0 {
+		return pkgerrors.ErrTeamNotFound
+	}
+
+	logger.Info("Set seniority policy for team %s to %q", name, level)
+	return nil
+}
+
+// ReplaceCodeOwners replaces the CODEOWNERS rules of the team, keeping their order
+func (r *TeamRepository) ReplaceCodeOwners(ctx context.Context, name string, rules []models.CodeOwnerRule) error {
+	executor := repository.GetTx(ctx, r.pool)
+
+	if _, err := executor.Exec(ctx, `DELETE FROM code_owner_rules WHERE team_name = $1`, name); err != nil {
+		logger.Error("Failed to delete code owners of team %s: %v", name, err)
+		return fmt.Errorf("failed to delete code owners: %w", err)
+	}
+
+	query := `
+		INSERT INTO code_owner_rules (team_name, position, line, pattern, owners)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+	for i, rule := range rules {
+		_, err := executor.Exec(ctx, query, name, i, rule.Line, rule.Pattern, models.FormatCodeOwners(rule.Owners))
+		if err != nil {
+			if isPgForeignKeyViolation(err) {
+				return pkgerrors.ErrTeamNotFound
+			}
+			logger.Error("Failed to insert code owner rule for team %s: %v", name, err)
+			return fmt.Errorf("failed to insert code owner rule: %w", err)
+		}
+	}
+
+	logger.Info("Replaced code owners of team %s (%d rules)", name, len(rules))
+	return nil
+}
+
+// ListCodeOwners returns the CODEOWNERS rules of the team in file order
+func (r *TeamRepository) ListCodeOwners(ctx context.Context, name string) ([]models.CodeOwnerRule, error) {
+	executor := repository.GetTx(ctx, r.pool)
+
+	query := `SELECT line, pattern, owners FROM code_owner_rules WHERE team_name = $1 ORDER BY position`
+
+	rows, err := executor.Query(ctx, query, name)
+	if err != nil {
+		logger.Error("Failed to list code owners of team %s: %v", name, err)
+		return nil, fmt.Errorf("failed to list code owners: %w", err)
+	}
+	defer rows.Close()
+
+	rules := make([]models.CodeOwnerRule, 0)
+	for rows.Next() {
+		var rule models.CodeOwnerRule
+		var owners string
+		if err := rows.Scan(&rule.Line, &rule.Pattern, &owners); err != nil {
+			logger.Error("Failed to scan code owner rule: %v", err)
+			return nil, fmt.Errorf("failed to scan code owner rule: %w", err)
+		}
+		if rule.Owners, err = models.ParseCodeOwners(owners); err != nil {
+			return nil, fmt.Errorf("invalid owners of rule at line %d: %w", rule.Line, err)
+		}
+		rules = append(rules, rule)
+	}
+
+	if err := rows.Err(); err != nil {
+		logger.Error("Error iterating code owner rules: %v", err)
+		return nil, fmt.Errorf("error iterating code owner rules: %w", err)
+	}
+	return rules, nil
+}
+
+// ListNames returns the names of all teams in alphabetical order
+func (r *TeamRepository) ListNames(ctx context.Context) ([]string, error) {
+	executor := repository.GetTx(ctx, r.pool)
+
+	rows, err := executor.Query(ctx, `SELECT name FROM teams ORDER BY name`)
+	if err != nil {
+		logger.Error("Failed to list teams: %v", err)
+		return nil, fmt.Errorf("failed to list teams: %w", err)
+	}
+	defer rows.Close()
+
+	names := make([]string, 0)
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			logger.Error("Failed to scan team name: %v", err)
+			return nil, fmt.Errorf("failed to scan team name: %w", err)
+		}
+		names = append(names, name)
+	}
+
+	if err := rows.Err(); err != nil {
+		logger.Error("Error iterating teams: %v", err)
+		return nil, fmt.Errorf("error iterating teams: %w", err)
+	}
+	return names, nil
+}
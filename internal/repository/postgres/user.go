@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 
 	"avito-backend-trainee-assignment-autumn-2025/internal/domain/models"
@@ -22,16 +23,20 @@ func NewUserRepository(pool *pgxpool.Pool) *UserRepository {
 	return &UserRepository{pool: pool}
 }
 
+// userColumns is the column list matching scanUser
+const userColumns = `id, username, team_name, is_active, mention_handle, email, timezone, work_start_minute, work_end_minute, max_open_reviews, skill_tags, seniority`
+
 // Create creates a new user
 func (r *UserRepository) Create(ctx context.Context, user *models.User) error {
 	executor := repository.GetTx(ctx, r.pool)
 
 	query := `
-		INSERT INTO users (id, username, team_name, is_active)
-		VALUES ($1, $2, $3, $4)
+		INSERT INTO users (id, username, team_name, is_active, mention_handle, email, max_open_reviews, skill_tags, seniority)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
 	`
 
-	_, err := executor.Exec(ctx, query, user.ID, user.Username, user.TeamName, user.IsActive)
+	_, err := executor.Exec(ctx, query, user.ID, user.Username, user.TeamName, user.IsActive, user.MentionHandle, user.Email,
+		user.MaxOpenReviews, textArray(user.SkillTags), string(user.Seniority))
 	if err != nil {
 		logger.Error("Failed to create user %s: %v", user.ID, err)
 		// Check for unique violation
@@ -55,11 +60,12 @@ func (r *UserRepository) Update(ctx context.Context, user *models.User) error {
 
 	query := `
 		UPDATE users
-		SET username = $2, team_name = $3, is_active = $4, updated_at = NOW()
+		SET username = $2, team_name = $3, is_active = $4, mention_handle = $5, email = $6, max_open_reviews = $7, skill_tags = $8, seniority = $9, updated_at = NOW()
 		WHERE id = $1
 	`
 
-	commandTag, err := executor.Exec(ctx, query, user.ID, user.Username, user.TeamName, user.IsActive)
+	commandTag, err := executor.Exec(ctx, query, user.ID, user.Username, user.TeamName, user.IsActive, user.MentionHandle, user.Email,
+		user.MaxOpenReviews, textArray(user.SkillTags), string(user.Seniority))
 	if err != nil {
 		logger.Error("Failed to update user %s: %v", user.ID, err)
 		// Check for foreign key violation (team doesn't exist)
@@ -81,14 +87,9 @@ func (r *UserRepository) Update(ctx context.Context, user *models.User) error {
 func (r *UserRepository) GetByID(ctx context.Context, id string) (*models.User, error) {
 	executor := repository.GetTx(ctx, r.pool)
 
-	query := `
-		SELECT id, username, team_name, is_active
-		FROM users
-		WHERE id = $1
-	`
+	query := `SELECT ` + userColumns + ` FROM users WHERE id = $1`
 
-	var user models.User
-	err := executor.QueryRow(ctx, query, id).Scan(&user.ID, &user.Username, &user.TeamName, &user.IsActive)
+	user, err := scanUser(executor.QueryRow(ctx, query, id))
 	if err != nil {
 		if isPgNoRows(err) {
 			return nil, pkgerrors.ErrUserNotFound
@@ -98,7 +99,7 @@ func (r *UserRepository) GetByID(ctx context.Context, id string) (*models.User,
 	}
 
 	logger.Debug("Retrieved user: %s", user.ID)
-	return &user, nil
+	return user, nil
 }
 
 // GetByTeamName retrieves all users in a team
@@ -106,7 +107,7 @@ func (r *UserRepository) GetByTeamName(ctx context.Context, teamName string) ([]
 	executor := repository.GetTx(ctx, r.pool)
 
 	query := `
-		SELECT id, username, team_name, is_active
+		SELECT ` + userColumns + `
 		FROM users
 		WHERE team_name = $1
 		ORDER BY username
@@ -121,12 +122,12 @@ func (r *UserRepository) GetByTeamName(ctx context.Context, teamName string) ([]
 
 	users := make([]models.User, 0)
 	for rows.Next() {
-		var user models.User
-		if err := rows.Scan(&user.ID, &user.Username, &user.TeamName, &user.IsActive); err != nil {
+		user, err := scanUser(rows)
+		if err != nil {
 			logger.Error("Failed to scan user for team %s: %v", teamName, err)
 			return nil, fmt.Errorf("failed to scan user: %w", err)
 		}
-		users = append(users, user)
+		users = append(users, *user)
 	}
 
 	if err := rows.Err(); err != nil {
@@ -161,3 +162,181 @@ func (r *UserRepository) SetActive(ctx context.Context, userID string, isActive
 	logger.Info("Set user %s active status to %t", userID, isActive)
 	return nil
 }
+
+// SetMentionHandle sets the chat handle used to mention a user; an empty handle disables mentions
+func (r *UserRepository) SetMentionHandle(ctx context.Context, userID, handle string) error {
+	executor := repository.GetTx(ctx, r.pool)
+
+	query := `
+		UPDATE users
+		SET mention_handle = $2, updated_at = NOW()
+		WHERE id = $1
+	`
+
+	commandTag, err := executor.Exec(ctx, query, userID, handle)
+	if err != nil {
+		logger.Error("Failed to set mention handle for user %s: %v", userID, err)
+		return fmt.Errorf("failed to set mention handle: %w", err)
+	}
+
+	if commandTag.RowsAffected() == 0 {
+		return pkgerrors.ErrUserNotFound
+	}
+
+	logger.Info("Set user %s mention handle to %q", userID, handle)
+	return nil
+}
+
+// SetEmail sets the address for email notifications; an empty address disables them
+func (r *UserRepository) SetEmail(ctx context.Context, userID, email string) error {
+	executor := repository.GetTx(ctx, r.pool)
+
+	query := `
+		UPDATE users
+		SET email = $2, updated_at = NOW()
+		WHERE id = $1
+	`
+
+	commandTag, err := executor.Exec(ctx, query, userID, email)
+	if err != nil {
+		logger.Error("Failed to set email for user %s: %v", userID, err)
+		return fmt.Errorf("failed to set email: %w", err)
+	}
+
+	if commandTag.RowsAffected() == 0 {
+		return pkgerrors.ErrUserNotFound
+	}
+
+	logger.Info("Set user %s email (enabled: %t)", userID, email != "")
+	return nil
+}
+
+// SetWorkingHours sets the user's timezone and working hours; an empty timezone means the team's
+func (r *UserRepository) SetWorkingHours(ctx context.Context, userID, timezone string, hours models.WorkingHours) error {
+	executor := repository.GetTx(ctx, r.pool)
+
+	query := `
+		UPDATE users
+		SET timezone = $2, work_start_minute = $3, work_end_minute = $4, updated_at = NOW()
+		WHERE id = $1
+	`
+
+	commandTag, err := executor.Exec(ctx, query, userID, timezone, hours.Start, hours.End)
+	if err != nil {
+		logger.Error("Failed to set working hours for user %s: %v", userID, err)
+		return fmt.Errorf("failed to set working hours: %w", err)
+	}
+
+	if commandTag.RowsAffected() == 0 {
+		return pkgerrors.ErrUserNotFound
+	}
+
+	logger.Info("Set user %s working hours to %d-%d (timezone: %q)", userID, hours.Start, hours.End, timezone)
+	return nil
+}
+
+// SetMaxOpenReviews sets how many open reviews the user may have at once; 0 removes the limit
+func (r *UserRepository) SetMaxOpenReviews(ctx context.Context, userID string, limit int) error {
+	executor := repository.GetTx(ctx, r.pool)
+
+	query := `
+		UPDATE users
+		SET max_open_reviews = $2, updated_at = NOW()
+		WHERE id = $1
+	`
+
+	commandTag, err := executor.Exec(ctx, query, userID, limit)
+	if err != nil {
+		logger.Error("Failed to set max open reviews for user %s: %v", userID, err)
+		return fmt.Errorf("failed to set max open reviews: %w", err)
+	}
+
+	if commandTag.RowsAffected() == 0 {
+		return pkgerrors.ErrUserNotFound
+	}
+
+	logger.Info("Set user %s max open reviews to %d", userID, limit)
+	return nil
+}
+
+// SetSkillTags replaces the user's skill tags
+func (r *UserRepository) SetSkillTags(ctx context.Context, userID string, tags []string) error {
+	executor := repository.GetTx(ctx, r.pool)
+
+	query := `
+		UPDATE users
+		SET skill_tags = $2, updated_at = NOW()
+		WHERE id = $1
+	`
+
+	commandTag, err := executor.Exec(ctx, query, userID, textArray(tags))
+	if err != nil {
+		logger.Error("Failed to set skill tags for user %s: %v", userID, err)
+		return fmt.Errorf("failed to set skill tags: %w", err)
+	}
+
+	if commandTag.RowsAffected() == 0 {
+		return pkgerrors.ErrUserNotFound
+	}
+
+	logger.Info("Set user %s skill tags to %v", userID, tags)
+	return nil
+}
+
+// SetSeniority sets the user's reviewer level; an empty level clears it
+func (r *UserRepository) SetSeniority(ctx context.Context, userID string, level models.Seniority) error {
+	executor := repository.GetTx(ctx, r.pool)
+
+	query := `
+		UPDATE users
+		SET seniority = $2, updated_at = NOW()
+		WHERE id = $1
+	`
+
+	commandTag, err := executor.Exec(ctx, query, userID, string(level))
+	if err != nil {
+		logger.Error("Failed to set seniority for user %s: %v", userID, err)
+		return fmt.Errorf("failed to set seniority: %w", err)
+	}
+
+	if commandTag.RowsAffected() == 0 {
+		return pkgerrors.ErrUserNotFound
+	}
+
+	logger.Info("Set user %s seniority to %q", userID, level)
+	return nil
+}
+
+// LockForUpdate locks the rows of the given users with SELECT ... FOR NO KEY UPDATE
+// Rows are locked in ID order, so transactions locking overlapping sets in one call cannot deadlock;
+// the lock mode still lets foreign key checks of other transactions, such as assigning the user, go through
+func (r *UserRepository) LockForUpdate(ctx context.Context, userIDs []string) error {
+	executor := repository.GetTx(ctx, r.pool)
+
+	query := `SELECT id FROM users WHERE id = ANY($1) ORDER BY id FOR NO KEY UPDATE`
+
+	rows, err := executor.Query(ctx, query, userIDs)
+	if err != nil {
+		logger.Error("Failed to lock %d users: %v", len(userIDs), err)
+		return fmt.Errorf("failed to lock users: %w", err)
+	}
+	rows.Close()
+
+	if err := rows.Err(); err != nil {
+		logger.Error("Failed to lock %d users: %v", len(userIDs), err)
+		return fmt.Errorf("failed to lock users: %w", err)
+	}
+
+	return nil
+}
+
+// scanUser scans a users row selected with userColumns
+func scanUser(row pgx.Row) (*models.User, error) {
+	var user models.User
+	if err := row.Scan(&user.ID, &user.Username, &user.TeamName, &user.IsActive, &user.MentionHandle, &user.Email,
+		&user.Timezone, &user.WorkingHours.Start, &user.WorkingHours.End, &user.MaxOpenReviews, &user.SkillTags, &user.Seniority,
+	); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
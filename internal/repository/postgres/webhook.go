@@ -0,0 +1,295 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"avito-backend-trainee-assignment-autumn-2025/internal/domain/models"
+	"avito-backend-trainee-assignment-autumn-2025/internal/repository"
+	pkgerrors "avito-backend-trainee-assignment-autumn-2025/pkg/errors"
+	"avito-backend-trainee-assignment-autumn-2025/pkg/logger"
+)
+
+// WebhookRepository implements repository.WebhookRepository for PostgreSQL
+type WebhookRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewWebhookRepository creates a new webhook repository
+func NewWebhookRepository(pool *pgxpool.Pool) *WebhookRepository {
+	return &WebhookRepository{pool: pool}
+}
+
+// deliveryColumns is the column list matching scanDelivery
+const deliveryColumns = `id, webhook_id, event_id, event_type, payload, status, attempts, last_error,
+	next_attempt_at, created_at, delivered_at`
+
+// Create creates a new webhook
+func (r *WebhookRepository) Create(ctx context.Context, webhook *models.Webhook) error {
+	executor := repository.GetTx(ctx, r.pool)
+
+	query := `
+		INSERT INTO webhooks (id, url, secret, events, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+
+	_, err := executor.Exec(ctx, query, webhook.ID, webhook.URL, webhook.Secret, eventTypesToStrings(webhook.Events), webhook.CreatedAt)
+	if err != nil {
+		logger.Error("Failed to create webhook %s: %v", webhook.ID, err)
+		return fmt.Errorf("failed to create webhook: %w", err)
+	}
+
+	logger.Info("Created webhook: %s (url: %s)", webhook.ID, webhook.URL)
+	return nil
+}
+
+// GetByID retrieves a webhook by ID
+func (r *WebhookRepository) GetByID(ctx context.Context, id string) (*models.Webhook, error) {
+	executor := repository.GetTx(ctx, r.pool)
+
+	query := `
+		SELECT id, url, secret, events, created_at
+		FROM webhooks
+		WHERE id = $1
+	`
+
+	webhook, err := scanWebhook(executor.QueryRow(ctx, query, id))
+	if err != nil {
+		if isPgNoRows(err) {
+			return nil, pkgerrors.ErrWebhookNotFound
+		}
+		logger.Error("Failed to get webhook %s: %v", id, err)
+		return nil, fmt.Errorf("failed to get webhook: %w", err)
+	}
+
+	return webhook, nil
+}
+
+// List retrieves all webhooks in registration order
+func (r *WebhookRepository) List(ctx context.Context) ([]models.Webhook, error) {
+	executor := repository.GetTx(ctx, r.pool)
+
+	query := `
+		SELECT id, url, secret, events, created_at
+		FROM webhooks
+		ORDER BY created_at, id
+	`
+
+	rows, err := executor.Query(ctx, query)
+	if err != nil {
+		logger.Error("Failed to list webhooks: %v", err)
+		return nil, fmt.Errorf("failed to list webhooks: %w", err)
+	}
+	defer rows.Close()
+
+	webhooks := make([]models.Webhook, 0)
+	for rows.Next() {
+		webhook, err := scanWebhook(rows)
+		if err != nil {
+			logger.Error("Failed to scan webhook: %v", err)
+			return nil, fmt.Errorf("failed to scan webhook: %w", err)
+		}
+		webhooks = append(webhooks, *webhook)
+	}
+
+	if err := rows.Err(); err != nil {
+		logger.Error("Error iterating webhooks: %v", err)
+		return nil, fmt.Errorf("error iterating webhooks: %w", err)
+	}
+
+	return webhooks, nil
+}
+
+// CreateDelivery creates a new webhook delivery
+func (r *WebhookRepository) CreateDelivery(ctx context.Context, delivery *models.WebhookDelivery) error {
+	executor := repository.GetTx(ctx, r.pool)
+
+	query := `
+		INSERT INTO webhook_deliveries (id, webhook_id, event_id, event_type, payload, status, attempts,
+			last_error, next_attempt_at, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $10)
+	`
+
+	_, err := executor.Exec(ctx, query,
+		delivery.ID, delivery.WebhookID, delivery.EventID, delivery.EventType, delivery.Payload, delivery.Status,
+		delivery.Attempts, delivery.LastError, delivery.NextAttemptAt, delivery.CreatedAt,
+	)
+	if err != nil {
+		logger.Error("Failed to create delivery %s: %v", delivery.ID, err)
+		// Check for foreign key violation (webhook doesn't exist)
+		if isPgForeignKeyViolation(err) {
+			return pkgerrors.ErrWebhookNotFound
+		}
+		return fmt.Errorf("failed to create delivery: %w", err)
+	}
+
+	logger.Debug("Created delivery %s of event %s to webhook %s", delivery.ID, delivery.EventID, delivery.WebhookID)
+	return nil
+}
+
+// GetDelivery retrieves a webhook delivery by ID
+func (r *WebhookRepository) GetDelivery(ctx context.Context, id string) (*models.WebhookDelivery, error) {
+	executor := repository.GetTx(ctx, r.pool)
+
+	query := `SELECT ` + deliveryColumns + ` FROM webhook_deliveries WHERE id = $1`
+
+	delivery, err := scanDelivery(executor.QueryRow(ctx, query, id))
+	if err != nil {
+		if isPgNoRows(err) {
+			return nil, pkgerrors.ErrDeliveryNotFound
+		}
+		logger.Error("Failed to get delivery %s: %v", id, err)
+		return nil, fmt.Errorf("failed to get delivery: %w", err)
+	}
+
+	return delivery, nil
+}
+
+// ListDeliveries retrieves the newest deliveries, optionally filtered by status
+func (r *WebhookRepository) ListDeliveries(ctx context.Context, status models.DeliveryStatus, limit int) ([]models.WebhookDelivery, error) {
+	executor := repository.GetTx(ctx, r.pool)
+
+	query := `
+		SELECT ` + deliveryColumns + `
+		FROM webhook_deliveries
+		WHERE $1 = '' OR status = $1
+		ORDER BY created_at DESC, id DESC
+		LIMIT $2
+	`
+
+	rows, err := executor.Query(ctx, query, string(status), limit)
+	if err != nil {
+		logger.Error("Failed to list deliveries: %v", err)
+		return nil, fmt.Errorf("failed to list deliveries: %w", err)
+	}
+
+	return collectDeliveries(rows)
+}
+
+// ClaimDueDeliveries leases pending deliveries whose next attempt is due
+// Rows locked by a concurrent claim are skipped, so every delivery goes to one dispatcher
+func (r *WebhookRepository) ClaimDueDeliveries(ctx context.Context, now, lockedUntil time.Time, limit int) ([]models.WebhookDelivery, error) {
+	executor := repository.GetTx(ctx, r.pool)
+
+	query := `
+		WITH claimed AS (
+			UPDATE webhook_deliveries
+			SET locked_until = $2
+			WHERE id IN (
+				SELECT id
+				FROM webhook_deliveries
+				WHERE status = 'pending' AND next_attempt_at <= $1 AND (locked_until IS NULL OR locked_until <= $1)
+				ORDER BY next_attempt_at, id
+				LIMIT $3
+				FOR UPDATE SKIP LOCKED
+			)
+			RETURNING ` + deliveryColumns + `
+		)
+		SELECT ` + deliveryColumns + `
+		FROM claimed
+		ORDER BY next_attempt_at, id
+	`
+
+	rows, err := executor.Query(ctx, query, now, lockedUntil, limit)
+	if err != nil {
+		logger.Error("Failed to claim due deliveries: %v", err)
+		return nil, fmt.Errorf("failed to claim due deliveries: %w", err)
+	}
+
+	return collectDeliveries(rows)
+}
+
+// UpdateDelivery stores the delivery state after an attempt or a redelivery request and releases its lease
+func (r *WebhookRepository) UpdateDelivery(ctx context.Context, delivery *models.WebhookDelivery) error {
+	executor := repository.GetTx(ctx, r.pool)
+
+	query := `
+		UPDATE webhook_deliveries
+		SET status = $2, attempts = $3, last_error = $4, next_attempt_at = $5, delivered_at = $6,
+			locked_until = NULL, updated_at = NOW()
+		WHERE id = $1
+	`
+
+	commandTag, err := executor.Exec(ctx, query,
+		delivery.ID, delivery.Status, delivery.Attempts, delivery.LastError, delivery.NextAttemptAt, delivery.DeliveredAt,
+	)
+	if err != nil {
+		logger.Error("Failed to update delivery %s: %v", delivery.ID, err)
+		return fmt.Errorf("failed to update delivery: %w", err)
+	}
+
+	if commandTag.RowsAffected() == 0 {
+		return pkgerrors.ErrDeliveryNotFound
+	}
+
+	return nil
+}
+
+// scanWebhook scans a webhooks row
+func scanWebhook(row pgx.Row) (*models.Webhook, error) {
+	var webhook models.Webhook
+	var events []string
+	if err := row.Scan(&webhook.ID, &webhook.URL, &webhook.Secret, &events, &webhook.CreatedAt); err != nil {
+		return nil, err
+	}
+	webhook.Events = stringsToEventTypes(events)
+	return &webhook, nil
+}
+
+// scanDelivery scans a webhook_deliveries row selected with deliveryColumns
+func scanDelivery(row pgx.Row) (*models.WebhookDelivery, error) {
+	var delivery models.WebhookDelivery
+	err := row.Scan(
+		&delivery.ID, &delivery.WebhookID, &delivery.EventID, &delivery.EventType, &delivery.Payload,
+		&delivery.Status, &delivery.Attempts, &delivery.LastError, &delivery.NextAttemptAt,
+		&delivery.CreatedAt, &delivery.DeliveredAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &delivery, nil
+}
+
+// collectDeliveries scans all rows and closes them
+func collectDeliveries(rows pgx.Rows) ([]models.WebhookDelivery, error) {
+	defer rows.Close()
+
+	deliveries := make([]models.WebhookDelivery, 0)
+	for rows.Next() {
+		delivery, err := scanDelivery(rows)
+		if err != nil {
+			logger.Error("Failed to scan delivery: %v", err)
+			return nil, fmt.Errorf("failed to scan delivery: %w", err)
+		}
+		deliveries = append(deliveries, *delivery)
+	}
+
+	if err := rows.Err(); err != nil {
+		logger.Error("Error iterating deliveries: %v", err)
+		return nil, fmt.Errorf("error iterating deliveries: %w", err)
+	}
+
+	return deliveries, nil
+}
+
+// eventTypesToStrings converts event types for a TEXT[] column
+func eventTypesToStrings(types []models.EventType) []string {
+	result := make([]string, len(types))
+	for i, t := range types {
+		result[i] = string(t)
+	}
+	return result
+}
+
+// stringsToEventTypes converts a TEXT[] column to event types
+func stringsToEventTypes(values []string) []models.EventType {
+	result := make([]models.EventType, len(values))
+	for i, v := range values {
+		result[i] = models.EventType(v)
+	}
+	return result
+}
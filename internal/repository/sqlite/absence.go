@@ -0,0 +1,154 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"avito-backend-trainee-assignment-autumn-2025/internal/domain/models"
+	pkgerrors "avito-backend-trainee-assignment-autumn-2025/pkg/errors"
+	"avito-backend-trainee-assignment-autumn-2025/pkg/logger"
+)
+
+// AbsenceRepository implements repository.AbsenceRepository for SQLite
+type AbsenceRepository struct {
+	db *sql.DB
+}
+
+// NewAbsenceRepository creates a new absence repository
+func NewAbsenceRepository(db *sql.DB) *AbsenceRepository {
+	return &AbsenceRepository{db: db}
+}
+
+// Create stores a new absence
+func (r *AbsenceRepository) Create(ctx context.Context, absence *models.Absence) error {
+	executor := getExecutor(ctx, r.db)
+
+	query := `
+		INSERT INTO user_absences (id, user_id, start_date, end_date, reason, source_uid, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`
+
+	_, err := executor.ExecContext(ctx, query,
+		absence.ID, absence.UserID, absence.StartDate.Format(models.DateLayout), absence.EndDate.Format(models.DateLayout),
+		absence.Reason, absence.SourceUID, absence.CreatedAt.UTC(),
+	)
+	if err != nil {
+		logger.Error("Failed to create absence for user %s: %v", absence.UserID, err)
+		// Check for foreign key violation (user doesn't exist)
+		if isForeignKeyViolation(err) {
+			return pkgerrors.ErrUserNotFound
+		}
+		return fmt.Errorf("failed to create absence: %w", err)
+	}
+
+	logger.Info("Created absence %s for user %s", absence.ID, absence.UserID)
+	return nil
+}
+
+// Delete removes an absence
+func (r *AbsenceRepository) Delete(ctx context.Context, id string) error {
+	executor := getExecutor(ctx, r.db)
+
+	result, err := executor.ExecContext(ctx, `DELETE FROM user_absences WHERE id = ?`, id)
+	if err != nil {
+		logger.Error("Failed to delete absence %s: %v", id, err)
+		return fmt.Errorf("failed to delete absence: %w", err)
+	}
+
+	if err := expectAffected(result, pkgerrors.ErrAbsenceNotFound); err != nil {
+		return err
+	}
+
+	logger.Info("Deleted absence %s", id)
+	return nil
+}
+
+// DeleteImported removes the user's absences imported from a calendar
+func (r *AbsenceRepository) DeleteImported(ctx context.Context, userID string) (int, error) {
+	executor := getExecutor(ctx, r.db)
+
+	result, err := executor.ExecContext(ctx, `DELETE FROM user_absences WHERE user_id = ? AND source_uid <> ''`, userID)
+	if err != nil {
+		logger.Error("Failed to delete imported absences of user %s: %v", userID, err)
+		return 0, fmt.Errorf("failed to delete imported absences: %w", err)
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	logger.Info("Deleted %d imported absences of user %s", deleted, userID)
+	return int(deleted), nil
+}
+
+// ListByUser returns all absences of a user ordered by start date
+func (r *AbsenceRepository) ListByUser(ctx context.Context, userID string) ([]models.Absence, error) {
+	executor := getExecutor(ctx, r.db)
+
+	query := `
+		SELECT id, user_id, start_date, end_date, reason, source_uid, created_at
+		FROM user_absences
+		WHERE user_id = ?
+		ORDER BY start_date, end_date, id
+	`
+
+	rows, err := executor.QueryContext(ctx, query, userID)
+	if err != nil {
+		logger.Error("Failed to list absences of user %s: %v", userID, err)
+		return nil, fmt.Errorf("failed to list absences: %w", err)
+	}
+	return scanAbsences(rows)
+}
+
+// ListByTeam returns the absences of the team's members that end on or after from, ordered by start date
+func (r *AbsenceRepository) ListByTeam(ctx context.Context, teamName string, from time.Time) ([]models.Absence, error) {
+	executor := getExecutor(ctx, r.db)
+
+	query := `
+		SELECT a.id, a.user_id, a.start_date, a.end_date, a.reason, a.source_uid, a.created_at
+		FROM user_absences a
+		INNER JOIN users u ON u.id = a.user_id
+		WHERE u.team_name = ? AND a.end_date >= ?
+		ORDER BY a.start_date, a.end_date, a.id
+	`
+
+	rows, err := executor.QueryContext(ctx, query, teamName, from.Format(models.DateLayout))
+	if err != nil {
+		logger.Error("Failed to list absences of team %s: %v", teamName, err)
+		return nil, fmt.Errorf("failed to list absences: %w", err)
+	}
+	return scanAbsences(rows)
+}
+
+// scanAbsences reads absences from rows and closes them
+func scanAbsences(rows *sql.Rows) ([]models.Absence, error) {
+	defer rows.Close()
+
+	absences := make([]models.Absence, 0)
+	for rows.Next() {
+		var absence models.Absence
+		var startDate, endDate string
+		if err := rows.Scan(&absence.ID, &absence.UserID, &startDate, &endDate, &absence.Reason, &absence.SourceUID, &absence.CreatedAt); err != nil {
+			logger.Error("Failed to scan absence: %v", err)
+			return nil, fmt.Errorf("failed to scan absence: %w", err)
+		}
+
+		var err error
+		if absence.StartDate, err = time.Parse(models.DateLayout, startDate); err != nil {
+			return nil, fmt.Errorf("failed to parse absence start date %q: %w", startDate, err)
+		}
+		if absence.EndDate, err = time.Parse(models.DateLayout, endDate); err != nil {
+			return nil, fmt.Errorf("failed to parse absence end date %q: %w", endDate, err)
+		}
+		absences = append(absences, absence)
+	}
+
+	if err := rows.Err(); err != nil {
+		logger.Error("Error iterating absences: %v", err)
+		return nil, fmt.Errorf("error iterating absences: %w", err)
+	}
+	return absences, nil
+}
@@ -0,0 +1,101 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"avito-backend-trainee-assignment-autumn-2025/internal/domain/models"
+	pkgerrors "avito-backend-trainee-assignment-autumn-2025/pkg/errors"
+	"avito-backend-trainee-assignment-autumn-2025/pkg/logger"
+)
+
+// AffinityRepository implements repository.AffinityRepository for SQLite
+type AffinityRepository struct {
+	db *sql.DB
+}
+
+// NewAffinityRepository creates a new affinity repository
+func NewAffinityRepository(db *sql.DB) *AffinityRepository {
+	return &AffinityRepository{db: db}
+}
+
+// Set creates or replaces the affinity of a pair
+func (r *AffinityRepository) Set(ctx context.Context, affinity *models.Affinity) error {
+	executor := getExecutor(ctx, r.db)
+
+	query := `
+		INSERT INTO reviewer_affinities (author_id, reviewer_id, weight, conflict, reason)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT (author_id, reviewer_id) DO UPDATE
+		SET weight = excluded.weight, conflict = excluded.conflict, reason = excluded.reason, updated_at = strftime('%Y-%m-%d %H:%M:%f', 'now')
+	`
+
+	_, err := executor.ExecContext(ctx, query, affinity.AuthorID, affinity.ReviewerID, affinity.Weight, affinity.Conflict, affinity.Reason)
+	if err != nil {
+		logger.Error("Failed to set affinity of %s to %s: %v", affinity.AuthorID, affinity.ReviewerID, err)
+		// Check for foreign key violation (either user doesn't exist)
+		if isForeignKeyViolation(err) {
+			return pkgerrors.ErrUserNotFound
+		}
+		return fmt.Errorf("failed to set affinity: %w", err)
+	}
+
+	logger.Info("Set affinity of %s to %s to %d (conflict: %t)", affinity.AuthorID, affinity.ReviewerID, affinity.Weight, affinity.Conflict)
+	return nil
+}
+
+// Delete removes the affinity of a pair
+func (r *AffinityRepository) Delete(ctx context.Context, authorID, reviewerID string) error {
+	executor := getExecutor(ctx, r.db)
+
+	result, err := executor.ExecContext(ctx,
+		`DELETE FROM reviewer_affinities WHERE author_id = ? AND reviewer_id = ?`, authorID, reviewerID,
+	)
+	if err != nil {
+		logger.Error("Failed to delete affinity of %s to %s: %v", authorID, reviewerID, err)
+		return fmt.Errorf("failed to delete affinity: %w", err)
+	}
+
+	if err := expectAffected(result, pkgerrors.ErrAffinityNotFound); err != nil {
+		return err
+	}
+
+	logger.Info("Deleted affinity of %s to %s", authorID, reviewerID)
+	return nil
+}
+
+// ListByUser returns the affinities where the user is the author or the reviewer
+func (r *AffinityRepository) ListByUser(ctx context.Context, userID string) ([]models.Affinity, error) {
+	executor := getExecutor(ctx, r.db)
+
+	query := `
+		SELECT author_id, reviewer_id, weight, conflict, reason
+		FROM reviewer_affinities
+		WHERE author_id = ? OR reviewer_id = ?
+		ORDER BY author_id, reviewer_id
+	`
+
+	rows, err := executor.QueryContext(ctx, query, userID, userID)
+	if err != nil {
+		logger.Error("Failed to list affinities of user %s: %v", userID, err)
+		return nil, fmt.Errorf("failed to list affinities: %w", err)
+	}
+	defer rows.Close()
+
+	affinities := make([]models.Affinity, 0)
+	for rows.Next() {
+		var affinity models.Affinity
+		if err := rows.Scan(&affinity.AuthorID, &affinity.ReviewerID, &affinity.Weight, &affinity.Conflict, &affinity.Reason); err != nil {
+			logger.Error("Failed to scan affinity: %v", err)
+			return nil, fmt.Errorf("failed to scan affinity: %w", err)
+		}
+		affinities = append(affinities, affinity)
+	}
+
+	if err := rows.Err(); err != nil {
+		logger.Error("Error iterating affinities: %v", err)
+		return nil, fmt.Errorf("error iterating affinities: %w", err)
+	}
+	return affinities, nil
+}
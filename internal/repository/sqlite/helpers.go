@@ -0,0 +1,71 @@
+package sqlite
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+// sqliteErrorCode returns the extended SQLite result code of err, or 0 if err is not an SQLite error
+func sqliteErrorCode(err error) int {
+	var sqliteErr *sqlite.Error
+	if errors.As(err, &sqliteErr) {
+		return sqliteErr.Code()
+	}
+	return 0
+}
+
+// isUniqueViolation checks if error is an SQLite unique or primary key violation
+func isUniqueViolation(err error) bool {
+	code := sqliteErrorCode(err)
+	return code == sqlite3.SQLITE_CONSTRAINT_UNIQUE || code == sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY
+}
+
+// isForeignKeyViolation checks if error is an SQLite foreign key violation
+func isForeignKeyViolation(err error) bool {
+	return sqliteErrorCode(err) == sqlite3.SQLITE_CONSTRAINT_FOREIGNKEY
+}
+
+// isCheckViolation checks if error is an SQLite check constraint violation
+func isCheckViolation(err error) bool {
+	return sqliteErrorCode(err) == sqlite3.SQLITE_CONSTRAINT_CHECK
+}
+
+// isNoRows checks if error is sql.ErrNoRows (no rows in result set)
+func isNoRows(err error) bool {
+	return errors.Is(err, sql.ErrNoRows)
+}
+
+// expectAffected returns notFound if the statement changed no rows
+func expectAffected(result sql.Result, notFound error) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	if affected == 0 {
+		return notFound
+	}
+	return nil
+}
+
+// encodeTags returns tags as a JSON array for a TEXT column
+func encodeTags(tags []string) string {
+	if len(tags) == 0 {
+		return "[]"
+	}
+	encoded, _ := json.Marshal(tags)
+	return string(encoded)
+}
+
+// decodeTags reads a JSON array written by encodeTags
+func decodeTags(encoded string) ([]string, error) {
+	var tags []string
+	if err := json.Unmarshal([]byte(encoded), &tags); err != nil {
+		return nil, fmt.Errorf("failed to decode tags: %w", err)
+	}
+	return tags, nil
+}
@@ -0,0 +1,156 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"avito-backend-trainee-assignment-autumn-2025/internal/domain/models"
+	pkgerrors "avito-backend-trainee-assignment-autumn-2025/pkg/errors"
+	"avito-backend-trainee-assignment-autumn-2025/pkg/logger"
+)
+
+// OutboxRepository implements repository.OutboxRepository for SQLite
+type OutboxRepository struct {
+	db *sql.DB
+}
+
+// NewOutboxRepository creates a new outbox repository
+func NewOutboxRepository(db *sql.DB) *OutboxRepository {
+	return &OutboxRepository{db: db}
+}
+
+// Add inserts a message into the outbox
+func (r *OutboxRepository) Add(ctx context.Context, msg *models.OutboxMessage) error {
+	executor := getExecutor(ctx, r.db)
+
+	query := `
+		INSERT INTO outbox (id, event_type, payload, next_attempt_at, created_at)
+		VALUES (?, ?, ?, ?, ?)
+	`
+
+	createdAt := msg.CreatedAt.UTC()
+	_, err := executor.ExecContext(ctx, query, msg.ID, msg.EventType, string(msg.Payload), createdAt, createdAt)
+	if err != nil {
+		logger.Error("Failed to add outbox message %s: %v", msg.ID, err)
+		return fmt.Errorf("failed to add outbox message: %w", err)
+	}
+
+	logger.Debug("Added outbox message %s (%s)", msg.ID, msg.EventType)
+	return nil
+}
+
+// LockPending selects due pending messages in insertion order
+// SQLite has no row locks: transactions take the database write lock up front
+// (_txlock=immediate), so concurrent dispatchers are serialized instead of skipping rows
+func (r *OutboxRepository) LockPending(ctx context.Context, now time.Time, limit int) ([]models.OutboxMessage, error) {
+	executor := getExecutor(ctx, r.db)
+
+	query := `
+		SELECT id, event_type, payload, status, attempts, last_error, next_attempt_at, published_sinks,
+			created_at, published_at
+		FROM outbox
+		WHERE status = 'pending' AND next_attempt_at <= ?
+		ORDER BY rowid
+		LIMIT ?
+	`
+
+	rows, err := executor.QueryContext(ctx, query, now.UTC(), limit)
+	if err != nil {
+		logger.Error("Failed to lock pending outbox messages: %v", err)
+		return nil, fmt.Errorf("failed to lock pending outbox messages: %w", err)
+	}
+	defer rows.Close()
+
+	messages := make([]models.OutboxMessage, 0)
+	for rows.Next() {
+		var msg models.OutboxMessage
+		var payload, sinks string
+		err := rows.Scan(
+			&msg.ID, &msg.EventType, &payload, &msg.Status, &msg.Attempts, &msg.LastError,
+			&msg.NextAttemptAt, &sinks, &msg.CreatedAt, &msg.PublishedAt,
+		)
+		if err != nil {
+			logger.Error("Failed to scan outbox message: %v", err)
+			return nil, fmt.Errorf("failed to scan outbox message: %w", err)
+		}
+		msg.Payload = []byte(payload)
+		if err := json.Unmarshal([]byte(sinks), &msg.PublishedSinks); err != nil {
+			return nil, fmt.Errorf("failed to decode published sinks of outbox message %s: %w", msg.ID, err)
+		}
+		messages = append(messages, msg)
+	}
+
+	if err := rows.Err(); err != nil {
+		logger.Error("Error iterating outbox messages: %v", err)
+		return nil, fmt.Errorf("error iterating outbox messages: %w", err)
+	}
+
+	return messages, nil
+}
+
+// MarkPublished sets the published status and published_at of a message
+func (r *OutboxRepository) MarkPublished(ctx context.Context, id string, publishedAt time.Time) error {
+	executor := getExecutor(ctx, r.db)
+
+	query := `UPDATE outbox SET status = 'published', published_at = ? WHERE id = ?`
+
+	result, err := executor.ExecContext(ctx, query, publishedAt.UTC(), id)
+	if err != nil {
+		logger.Error("Failed to mark outbox message %s as published: %v", id, err)
+		return fmt.Errorf("failed to mark outbox message as published: %w", err)
+	}
+
+	return expectAffected(result, pkgerrors.ErrOutboxMessageNotFound)
+}
+
+// MarkFailed records a failed publish attempt
+func (r *OutboxRepository) MarkFailed(ctx context.Context, msg *models.OutboxMessage) error {
+	executor := getExecutor(ctx, r.db)
+
+	sinks := msg.PublishedSinks
+	if sinks == nil {
+		sinks = []string{}
+	}
+	encoded, err := json.Marshal(sinks)
+	if err != nil {
+		return fmt.Errorf("failed to encode published sinks: %w", err)
+	}
+
+	query := `
+		UPDATE outbox
+		SET status = ?, attempts = ?, last_error = ?, next_attempt_at = ?, published_sinks = ?
+		WHERE id = ?
+	`
+
+	result, err := executor.ExecContext(ctx, query,
+		msg.Status, msg.Attempts, msg.LastError, msg.NextAttemptAt.UTC(), string(encoded), msg.ID,
+	)
+	if err != nil {
+		logger.Error("Failed to mark outbox message %s as failed: %v", msg.ID, err)
+		return fmt.Errorf("failed to mark outbox message as failed: %w", err)
+	}
+
+	return expectAffected(result, pkgerrors.ErrOutboxMessageNotFound)
+}
+
+// DeletePublishedBefore removes messages published before the given time
+func (r *OutboxRepository) DeletePublishedBefore(ctx context.Context, before time.Time) (int64, error) {
+	executor := getExecutor(ctx, r.db)
+
+	query := `DELETE FROM outbox WHERE published_at IS NOT NULL AND published_at < ?`
+
+	result, err := executor.ExecContext(ctx, query, before.UTC())
+	if err != nil {
+		logger.Error("Failed to delete published outbox messages: %v", err)
+		return 0, fmt.Errorf("failed to delete published outbox messages: %w", err)
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	return deleted, nil
+}
@@ -0,0 +1,475 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"avito-backend-trainee-assignment-autumn-2025/internal/domain/models"
+	pkgerrors "avito-backend-trainee-assignment-autumn-2025/pkg/errors"
+	"avito-backend-trainee-assignment-autumn-2025/pkg/logger"
+)
+
+// PRRepository implements repository.PRRepository for SQLite
+type PRRepository struct {
+	db *sql.DB
+}
+
+// NewPRRepository creates a new pull request repository
+func NewPRRepository(db *sql.DB) *PRRepository {
+	return &PRRepository{db: db}
+}
+
+// Create creates a new pull request
+func (r *PRRepository) Create(ctx context.Context, pr *models.PullRequest) error {
+	executor := getExecutor(ctx, r.db)
+
+	query := `
+		INSERT INTO pull_requests (id, name, author_id, status, required_tags)
+		VALUES (?, ?, ?, ?, ?)
+	`
+
+	_, err := executor.ExecContext(ctx, query, pr.ID, pr.Name, pr.AuthorID, pr.Status, encodeTags(pr.RequiredTags))
+	if err != nil {
+		logger.Error("Failed to create PR %s: %v", pr.ID, err)
+		// Check for unique violation
+		if isUniqueViolation(err) {
+			return pkgerrors.ErrPRExists
+		}
+		// Check for foreign key violation (author doesn't exist)
+		if isForeignKeyViolation(err) {
+			return pkgerrors.ErrUserNotFound
+		}
+		// Check for check constraint violation (invalid status)
+		if isCheckViolation(err) {
+			return pkgerrors.NewValidationError("status", fmt.Sprintf("is invalid: %s", pr.Status))
+		}
+		return fmt.Errorf("failed to create PR: %w", err)
+	}
+
+	logger.Info("Created PR: %s (name: %s, author: %s)", pr.ID, pr.Name, pr.AuthorID)
+	return nil
+}
+
+// GetByID retrieves a pull request by ID with all reviewers
+func (r *PRRepository) GetByID(ctx context.Context, id string) (*models.PullRequest, error) {
+	executor := getExecutor(ctx, r.db)
+
+	// Get PR details
+	query := `
+		SELECT id, name, author_id, status, created_at, merged_at, required_tags
+		FROM pull_requests
+		WHERE id = ?
+	`
+
+	var pr models.PullRequest
+	var requiredTags string
+	err := executor.QueryRowContext(ctx, query, id).Scan(
+		&pr.ID, &pr.Name, &pr.AuthorID, &pr.Status, &pr.CreatedAt, &pr.MergedAt, &requiredTags,
+	)
+	if err != nil {
+		if isNoRows(err) {
+			return nil, pkgerrors.ErrPRNotFound
+		}
+		logger.Error("Failed to get PR %s: %v", id, err)
+		return nil, fmt.Errorf("failed to get PR: %w", err)
+	}
+	if pr.RequiredTags, err = decodeTags(requiredTags); err != nil {
+		return nil, err
+	}
+
+	// Get reviewers
+	reviewers, err := r.GetReviewersByPRID(ctx, id)
+	if err != nil {
+		logger.Error("Failed to get reviewers for PR %s: %v", id, err)
+		return nil, fmt.Errorf("failed to get reviewers: %w", err)
+	}
+
+	pr.AssignedReviewers = reviewers
+
+	logger.Debug("Retrieved PR %s with %d reviewers", pr.ID, len(pr.AssignedReviewers))
+	return &pr, nil
+}
+
+// Update updates an existing pull request
+func (r *PRRepository) Update(ctx context.Context, pr *models.PullRequest) error {
+	executor := getExecutor(ctx, r.db)
+
+	query := `
+		UPDATE pull_requests
+		SET name = ?, author_id = ?, status = ?, updated_at = strftime('%Y-%m-%d %H:%M:%f', 'now')
+		WHERE id = ?
+	`
+
+	result, err := executor.ExecContext(ctx, query, pr.Name, pr.AuthorID, pr.Status, pr.ID)
+	if err != nil {
+		logger.Error("Failed to update PR %s: %v", pr.ID, err)
+		// Check for foreign key violation (author doesn't exist)
+		if isForeignKeyViolation(err) {
+			return pkgerrors.ErrUserNotFound
+		}
+		// Check for check constraint violation (invalid status)
+		if isCheckViolation(err) {
+			return pkgerrors.NewValidationError("status", fmt.Sprintf("is invalid: %s", pr.Status))
+		}
+		return fmt.Errorf("failed to update PR: %w", err)
+	}
+
+	if err := expectAffected(result, pkgerrors.ErrPRNotFound); err != nil {
+		return err
+	}
+
+	logger.Info("Updated PR: %s", pr.ID)
+	return nil
+}
+
+// Merge merges a pull request (sets status to MERGED and merged_at timestamp)
+func (r *PRRepository) Merge(ctx context.Context, prID string) (*models.PullRequest, error) {
+	executor := getExecutor(ctx, r.db)
+
+	// First check if PR exists and is not already merged
+	var currentStatus models.PRStatus
+	checkQuery := `SELECT status FROM pull_requests WHERE id = ?`
+	err := executor.QueryRowContext(ctx, checkQuery, prID).Scan(&currentStatus)
+	if err != nil {
+		if isNoRows(err) {
+			return nil, pkgerrors.ErrPRNotFound
+		}
+		logger.Error("Failed to check PR status %s: %v", prID, err)
+		return nil, fmt.Errorf("failed to check PR status: %w", err)
+	}
+
+	if currentStatus == models.PRStatusMerged {
+		return nil, pkgerrors.ErrPRMerged
+	}
+
+	// Update PR to merged status
+	query := `
+		UPDATE pull_requests
+		SET status = ?, merged_at = ?, updated_at = strftime('%Y-%m-%d %H:%M:%f', 'now')
+		WHERE id = ?
+	`
+
+	mergedAt := time.Now().UTC()
+	_, err = executor.ExecContext(ctx, query, models.PRStatusMerged, mergedAt, prID)
+	if err != nil {
+		logger.Error("Failed to merge PR %s: %v", prID, err)
+		return nil, fmt.Errorf("failed to merge PR: %w", err)
+	}
+
+	logger.Info("Merged PR: %s", prID)
+
+	// Return updated PR
+	return r.GetByID(ctx, prID)
+}
+
+// GetReviewersByPRID retrieves all reviewer IDs for a pull request
+func (r *PRRepository) GetReviewersByPRID(ctx context.Context, prID string) ([]string, error) {
+	executor := getExecutor(ctx, r.db)
+
+	// rowid breaks ties between reviewers assigned within the same millisecond
+	query := `
+		SELECT reviewer_id
+		FROM pr_reviewers
+		WHERE pr_id = ?
+		ORDER BY assigned_at, rowid
+	`
+
+	rows, err := executor.QueryContext(ctx, query, prID)
+	if err != nil {
+		logger.Error("Failed to get reviewers for PR %s: %v", prID, err)
+		return nil, fmt.Errorf("failed to get reviewers: %w", err)
+	}
+	defer rows.Close()
+
+	reviewers := make([]string, 0)
+	for rows.Next() {
+		var reviewerID string
+		if err := rows.Scan(&reviewerID); err != nil {
+			logger.Error("Failed to scan reviewer for PR %s: %v", prID, err)
+			return nil, fmt.Errorf("failed to scan reviewer: %w", err)
+		}
+		reviewers = append(reviewers, reviewerID)
+	}
+
+	if err := rows.Err(); err != nil {
+		logger.Error("Error iterating reviewers for PR %s: %v", prID, err)
+		return nil, fmt.Errorf("error iterating reviewers: %w", err)
+	}
+
+	logger.Debug("Retrieved %d reviewers for PR %s", len(reviewers), prID)
+	return reviewers, nil
+}
+
+// AddReviewer adds a reviewer to a pull request
+func (r *PRRepository) AddReviewer(ctx context.Context, prID, reviewerID string) error {
+	executor := getExecutor(ctx, r.db)
+
+	query := `
+		INSERT INTO pr_reviewers (pr_id, reviewer_id)
+		VALUES (?, ?)
+	`
+
+	_, err := executor.ExecContext(ctx, query, prID, reviewerID)
+	if err != nil {
+		logger.Error("Failed to add reviewer %s to PR %s: %v", reviewerID, prID, err)
+		// Check for unique violation (reviewer already assigned)
+		if isUniqueViolation(err) {
+			return fmt.Errorf("reviewer already assigned to this PR")
+		}
+		// Check for foreign key violation (PR or reviewer doesn't exist)
+		if isForeignKeyViolation(err) {
+			return pkgerrors.ErrPRNotFound
+		}
+		return fmt.Errorf("failed to add reviewer: %w", err)
+	}
+
+	logger.Info("Added reviewer %s to PR %s", reviewerID, prID)
+	return nil
+}
+
+// RemoveReviewer removes a reviewer from a pull request
+func (r *PRRepository) RemoveReviewer(ctx context.Context, prID, reviewerID string) error {
+	executor := getExecutor(ctx, r.db)
+
+	query := `
+		DELETE FROM pr_reviewers
+		WHERE pr_id = ? AND reviewer_id = ?
+	`
+
+	result, err := executor.ExecContext(ctx, query, prID, reviewerID)
+	if err != nil {
+		logger.Error("Failed to remove reviewer %s from PR %s: %v", reviewerID, prID, err)
+		return fmt.Errorf("failed to remove reviewer: %w", err)
+	}
+
+	if err := expectAffected(result, pkgerrors.ErrReviewerNotAssigned); err != nil {
+		return err
+	}
+
+	logger.Info("Removed reviewer %s from PR %s", reviewerID, prID)
+	return nil
+}
+
+// GetPRsByReviewerID retrieves all pull requests assigned to a reviewer
+func (r *PRRepository) GetPRsByReviewerID(ctx context.Context, reviewerID string) ([]models.PullRequest, error) {
+	executor := getExecutor(ctx, r.db)
+
+	query := `
+		SELECT pr.id, pr.name, pr.author_id, pr.status, pr.created_at, pr.merged_at
+		FROM pull_requests pr
+		INNER JOIN pr_reviewers prr ON pr.id = prr.pr_id
+		WHERE prr.reviewer_id = ?
+		ORDER BY pr.created_at DESC, pr.rowid DESC
+	`
+
+	rows, err := executor.QueryContext(ctx, query, reviewerID)
+	if err != nil {
+		logger.Error("Failed to get PRs for reviewer %s: %v", reviewerID, err)
+		return nil, fmt.Errorf("failed to get PRs by reviewer: %w", err)
+	}
+	defer rows.Close()
+
+	prs := make([]models.PullRequest, 0)
+	for rows.Next() {
+		var pr models.PullRequest
+		if err := rows.Scan(&pr.ID, &pr.Name, &pr.AuthorID, &pr.Status, &pr.CreatedAt, &pr.MergedAt); err != nil {
+			logger.Error("Failed to scan PR for reviewer %s: %v", reviewerID, err)
+			return nil, fmt.Errorf("failed to scan PR: %w", err)
+		}
+		prs = append(prs, pr)
+	}
+
+	if err := rows.Err(); err != nil {
+		logger.Error("Error iterating PRs for reviewer %s: %v", reviewerID, err)
+		return nil, fmt.Errorf("error iterating PRs: %w", err)
+	}
+	rows.Close()
+
+	// Load reviewers once the result set is closed: a transaction has a single connection
+	for i := range prs {
+		reviewers, err := r.GetReviewersByPRID(ctx, prs[i].ID)
+		if err != nil {
+			logger.Error("Failed to get reviewers for PR %s: %v", prs[i].ID, err)
+			return nil, fmt.Errorf("failed to get reviewers: %w", err)
+		}
+		prs[i].AssignedReviewers = reviewers
+	}
+
+	logger.Debug("Retrieved %d PRs for reviewer %s", len(prs), reviewerID)
+	return prs, nil
+}
+
+// CountOpenReviews returns how many open PRs each of the given users reviews; users without any are omitted
+func (r *PRRepository) CountOpenReviews(ctx context.Context, reviewerIDs []string) (map[string]int, error) {
+	counts := make(map[string]int)
+	if len(reviewerIDs) == 0 {
+		return counts, nil
+	}
+
+	executor := getExecutor(ctx, r.db)
+
+	args := make([]interface{}, len(reviewerIDs))
+	for i, id := range reviewerIDs {
+		args[i] = id
+	}
+	query := `
+		SELECT prr.reviewer_id, COUNT(*)
+		FROM pr_reviewers prr
+		INNER JOIN pull_requests pr ON pr.id = prr.pr_id
+		WHERE prr.reviewer_id IN (?` + strings.Repeat(", ?", len(reviewerIDs)-1) + `) AND pr.status = 'OPEN'
+		GROUP BY prr.reviewer_id
+	`
+
+	rows, err := executor.QueryContext(ctx, query, args...)
+	if err != nil {
+		logger.Error("Failed to count open reviews: %v", err)
+		return nil, fmt.Errorf("failed to count open reviews: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var reviewerID string
+		var count int
+		if err := rows.Scan(&reviewerID, &count); err != nil {
+			logger.Error("Failed to scan open review count: %v", err)
+			return nil, fmt.Errorf("failed to scan open review count: %w", err)
+		}
+		counts[reviewerID] = count
+	}
+
+	if err := rows.Err(); err != nil {
+		logger.Error("Error iterating open review counts: %v", err)
+		return nil, fmt.Errorf("error iterating open review counts: %w", err)
+	}
+
+	logger.Debug("Counted open reviews of %d users", len(reviewerIDs))
+	return counts, nil
+}
+
+// ListStaleReviews returns active members of the team reviewing open PRs who were assigned
+// (or last reminded) no later than before; oldest assignments first
+func (r *PRRepository) ListStaleReviews(ctx context.Context, teamName string, before time.Time, limit int) ([]models.StaleReview, error) {
+	executor := getExecutor(ctx, r.db)
+
+	// assigned_at (strftime default) and reminded_at (bound by the driver) have different text
+	// formats, so both are compared as Julian days
+	query := `
+		SELECT pr.id, pr.name, pr.author_id, prr.reviewer_id, prr.assigned_at, prr.reminded_at
+		FROM pr_reviewers prr
+		INNER JOIN pull_requests pr ON pr.id = prr.pr_id
+		INNER JOIN users u ON u.id = prr.reviewer_id
+		WHERE u.team_name = ? AND u.is_active AND pr.status = 'OPEN'
+			AND julianday(COALESCE(prr.reminded_at, prr.assigned_at)) <= julianday(?)
+		ORDER BY julianday(prr.assigned_at), pr.id, prr.reviewer_id
+		LIMIT ?
+	`
+
+	rows, err := executor.QueryContext(ctx, query, teamName, before.UTC(), limit)
+	if err != nil {
+		logger.Error("Failed to list stale reviews of team %s: %v", teamName, err)
+		return nil, fmt.Errorf("failed to list stale reviews: %w", err)
+	}
+	defer rows.Close()
+
+	reviews := make([]models.StaleReview, 0)
+	for rows.Next() {
+		var review models.StaleReview
+		if err := rows.Scan(
+			&review.PullRequestID, &review.PullRequestName, &review.AuthorID, &review.ReviewerID,
+			&review.AssignedAt, &review.RemindedAt,
+		); err != nil {
+			logger.Error("Failed to scan stale review: %v", err)
+			return nil, fmt.Errorf("failed to scan stale review: %w", err)
+		}
+		reviews = append(reviews, review)
+	}
+
+	if err := rows.Err(); err != nil {
+		logger.Error("Error iterating stale reviews: %v", err)
+		return nil, fmt.Errorf("error iterating stale reviews: %w", err)
+	}
+
+	logger.Debug("Found %d stale reviews in team %s", len(reviews), teamName)
+	return reviews, nil
+}
+
+// MarkReviewReminded records when a reviewer was reminded about a PR
+func (r *PRRepository) MarkReviewReminded(ctx context.Context, prID, reviewerID string, at time.Time) error {
+	executor := getExecutor(ctx, r.db)
+
+	query := `UPDATE pr_reviewers SET reminded_at = ? WHERE pr_id = ? AND reviewer_id = ?`
+
+	result, err := executor.ExecContext(ctx, query, at.UTC(), prID, reviewerID)
+	if err != nil {
+		logger.Error("Failed to mark reviewer %s of PR %s as reminded: %v", reviewerID, prID, err)
+		return fmt.Errorf("failed to mark review as reminded: %w", err)
+	}
+
+	return expectAffected(result, pkgerrors.ErrReviewerNotAssigned)
+}
+
+// AddReassignment records a reviewer replacement in the PR history
+func (r *PRRepository) AddReassignment(ctx context.Context, reassignment *models.Reassignment) error {
+	executor := getExecutor(ctx, r.db)
+
+	query := `
+		INSERT INTO reviewer_reassignments (id, pr_id, old_reviewer_id, new_reviewer_id, reason, created_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`
+
+	_, err := executor.ExecContext(ctx, query,
+		reassignment.ID, reassignment.PullRequestID, reassignment.OldReviewerID, reassignment.NewReviewerID,
+		string(reassignment.Reason), reassignment.CreatedAt.UTC(),
+	)
+	if err != nil {
+		logger.Error("Failed to record reassignment on PR %s: %v", reassignment.PullRequestID, err)
+		if isForeignKeyViolation(err) {
+			return pkgerrors.ErrPRNotFound
+		}
+		return fmt.Errorf("failed to record reassignment: %w", err)
+	}
+	return nil
+}
+
+// ListReassignments returns the reviewer replacements of a PR, oldest first
+func (r *PRRepository) ListReassignments(ctx context.Context, prID string) ([]models.Reassignment, error) {
+	executor := getExecutor(ctx, r.db)
+
+	query := `
+		SELECT id, pr_id, old_reviewer_id, new_reviewer_id, reason, created_at
+		FROM reviewer_reassignments
+		WHERE pr_id = ?
+		ORDER BY julianday(created_at), id
+	`
+
+	rows, err := executor.QueryContext(ctx, query, prID)
+	if err != nil {
+		logger.Error("Failed to list reassignments of PR %s: %v", prID, err)
+		return nil, fmt.Errorf("failed to list reassignments: %w", err)
+	}
+	defer rows.Close()
+
+	reassignments := make([]models.Reassignment, 0)
+	for rows.Next() {
+		var reassignment models.Reassignment
+		var reason string
+		if err := rows.Scan(
+			&reassignment.ID, &reassignment.PullRequestID, &reassignment.OldReviewerID,
+			&reassignment.NewReviewerID, &reason, &reassignment.CreatedAt,
+		); err != nil {
+			logger.Error("Failed to scan reassignment: %v", err)
+			return nil, fmt.Errorf("failed to scan reassignment: %w", err)
+		}
+		reassignment.Reason = models.ReassignReason(reason)
+		reassignments = append(reassignments, reassignment)
+	}
+
+	if err := rows.Err(); err != nil {
+		logger.Error("Error iterating reassignments of PR %s: %v", prID, err)
+		return nil, fmt.Errorf("error iterating reassignments: %w", err)
+	}
+	return reassignments, nil
+}
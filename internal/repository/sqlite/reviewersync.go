@@ -0,0 +1,132 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"time"
+
+	"avito-backend-trainee-assignment-autumn-2025/internal/domain/models"
+	pkgerrors "avito-backend-trainee-assignment-autumn-2025/pkg/errors"
+	"avito-backend-trainee-assignment-autumn-2025/pkg/logger"
+)
+
+// ReviewerSyncRepository implements repository.ReviewerSyncRepository for SQLite
+type ReviewerSyncRepository struct {
+	db *sql.DB
+}
+
+// NewReviewerSyncRepository creates a new reviewer sync repository
+func NewReviewerSyncRepository(db *sql.DB) *ReviewerSyncRepository {
+	return &ReviewerSyncRepository{db: db}
+}
+
+// Create stores a new reviewer sync
+func (r *ReviewerSyncRepository) Create(ctx context.Context, sync *models.ReviewerSync) error {
+	executor := getExecutor(ctx, r.db)
+
+	query := `
+		INSERT INTO reviewer_syncs (id, event_id, pull_request_id, action, login, status, attempts,
+			last_error, next_attempt_at, created_at, updated_at)
+		VALUES (?1, ?2, ?3, ?4, ?5, ?6, ?7, ?8, ?9, ?10, ?10)
+	`
+
+	_, err := executor.ExecContext(ctx, query,
+		sync.ID, sync.EventID, sync.PullRequestID, string(sync.Action), sync.Login, string(sync.Status),
+		sync.Attempts, sync.LastError, sync.NextAttemptAt.UTC(), sync.CreatedAt.UTC(),
+	)
+	if err != nil {
+		logger.Error("Failed to create reviewer sync %s: %v", sync.ID, err)
+		return fmt.Errorf("failed to create reviewer sync: %w", err)
+	}
+
+	logger.Debug("Created reviewer sync %s (%s %s on %s)", sync.ID, sync.Action, sync.Login, sync.PullRequestID)
+	return nil
+}
+
+// ClaimDue leases pending syncs whose next attempt is due
+// Only the earliest pending sync of a PR can be claimed, so the syncs of a PR run in event order
+// SQLite serializes writes, so a single UPDATE claims the rows atomically
+func (r *ReviewerSyncRepository) ClaimDue(ctx context.Context, now, lockedUntil time.Time, limit int) ([]models.ReviewerSync, error) {
+	executor := getExecutor(ctx, r.db)
+
+	query := `
+		UPDATE reviewer_syncs
+		SET locked_until = ?2
+		WHERE rowid IN (
+			SELECT s.rowid
+			FROM reviewer_syncs s
+			WHERE s.status = 'pending' AND s.next_attempt_at <= ?1 AND (s.locked_until IS NULL OR s.locked_until <= ?1)
+				AND NOT EXISTS (
+					SELECT 1
+					FROM reviewer_syncs earlier
+					WHERE earlier.pull_request_id = s.pull_request_id AND earlier.status = 'pending'
+						AND earlier.rowid < s.rowid
+				)
+			ORDER BY s.next_attempt_at, s.rowid
+			LIMIT ?3
+		)
+		RETURNING id, event_id, pull_request_id, action, login, status, attempts, last_error,
+			next_attempt_at, created_at, completed_at
+	`
+
+	rows, err := executor.QueryContext(ctx, query, now.UTC(), lockedUntil.UTC(), limit)
+	if err != nil {
+		logger.Error("Failed to claim due reviewer syncs: %v", err)
+		return nil, fmt.Errorf("failed to claim due reviewer syncs: %w", err)
+	}
+	defer rows.Close()
+
+	syncs := make([]models.ReviewerSync, 0)
+	for rows.Next() {
+		var sync models.ReviewerSync
+		err := rows.Scan(
+			&sync.ID, &sync.EventID, &sync.PullRequestID, &sync.Action, &sync.Login, &sync.Status,
+			&sync.Attempts, &sync.LastError, &sync.NextAttemptAt, &sync.CreatedAt, &sync.CompletedAt,
+		)
+		if err != nil {
+			logger.Error("Failed to scan reviewer sync: %v", err)
+			return nil, fmt.Errorf("failed to scan reviewer sync: %w", err)
+		}
+		syncs = append(syncs, sync)
+	}
+
+	if err := rows.Err(); err != nil {
+		logger.Error("Error iterating reviewer syncs: %v", err)
+		return nil, fmt.Errorf("error iterating reviewer syncs: %w", err)
+	}
+
+	// RETURNING does not keep the order of the subquery
+	sort.SliceStable(syncs, func(i, j int) bool {
+		return syncs[i].NextAttemptAt.Before(syncs[j].NextAttemptAt)
+	})
+	return syncs, nil
+}
+
+// Update stores the sync state after an attempt and releases its lease
+func (r *ReviewerSyncRepository) Update(ctx context.Context, sync *models.ReviewerSync) error {
+	executor := getExecutor(ctx, r.db)
+
+	query := `
+		UPDATE reviewer_syncs
+		SET status = ?, attempts = ?, last_error = ?, next_attempt_at = ?, completed_at = ?,
+			locked_until = NULL, updated_at = strftime('%Y-%m-%d %H:%M:%f', 'now')
+		WHERE id = ?
+	`
+
+	var completedAt any
+	if sync.CompletedAt != nil {
+		completedAt = sync.CompletedAt.UTC()
+	}
+
+	result, err := executor.ExecContext(ctx, query,
+		string(sync.Status), sync.Attempts, sync.LastError, sync.NextAttemptAt.UTC(), completedAt, sync.ID,
+	)
+	if err != nil {
+		logger.Error("Failed to update reviewer sync %s: %v", sync.ID, err)
+		return fmt.Errorf("failed to update reviewer sync: %w", err)
+	}
+
+	return expectAffected(result, pkgerrors.ErrReviewerSyncNotFound)
+}
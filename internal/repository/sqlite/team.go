@@ -0,0 +1,344 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"avito-backend-trainee-assignment-autumn-2025/internal/domain/models"
+	pkgerrors "avito-backend-trainee-assignment-autumn-2025/pkg/errors"
+	"avito-backend-trainee-assignment-autumn-2025/pkg/logger"
+)
+
+// TeamRepository implements repository.TeamRepository for SQLite
+type TeamRepository struct {
+	db *sql.DB
+}
+
+// NewTeamRepository creates a new team repository
+func NewTeamRepository(db *sql.DB) *TeamRepository {
+	return &TeamRepository{db: db}
+}
+
+// Create creates a new team
+func (r *TeamRepository) Create(ctx context.Context, team *models.Team) error {
+	executor := getExecutor(ctx, r.db)
+
+	query := `INSERT INTO teams (name) VALUES (?)`
+
+	_, err := executor.ExecContext(ctx, query, team.Name)
+	if err != nil {
+		logger.Error("Failed to create team %s: %v", team.Name, err)
+		// Check for unique violation
+		if isUniqueViolation(err) {
+			return pkgerrors.ErrTeamExists
+		}
+		return fmt.Errorf("failed to create team: %w", err)
+	}
+
+	logger.Info("Created team: %s", team.Name)
+	return nil
+}
+
+// GetByName retrieves a team by name with all members
+func (r *TeamRepository) GetByName(ctx context.Context, name string) (*models.Team, error) {
+	executor := getExecutor(ctx, r.db)
+
+	// First, get the team itself
+	team := &models.Team{
+		Name:    name,
+		Members: make([]models.User, 0),
+	}
+	teamQuery := `
+		SELECT chat_webhook_url, review_sla_minutes, sla_action, COALESCE(lead_id, ''), timezone, weekend_days,
+			prefer_working_hours, prefer_within_hours, min_reviewer_seniority
+		FROM teams
+		WHERE name = ?
+	`
+	var action, seniority string
+	var weekend int
+	err := executor.QueryRowContext(ctx, teamQuery, name).Scan(
+		&team.ChatWebhookURL, &team.ReviewSLAMinutes, &action, &team.LeadID, &team.Calendar.Timezone, &weekend,
+		&team.PreferWorkingHours, &team.PreferWithinHours, &seniority,
+	)
+	if err != nil {
+		if isNoRows(err) {
+			return nil, pkgerrors.ErrTeamNotFound
+		}
+		logger.Error("Failed to get team %s: %v", name, err)
+		return nil, fmt.Errorf("failed to get team: %w", err)
+	}
+	team.SLAAction = models.SLAAction(action)
+	team.MinReviewerSeniority = models.Seniority(seniority)
+	team.Calendar.Weekend = models.WeekdaySet(weekend)
+
+	// Get team members
+	query := `
+		SELECT ` + userColumns + `
+		FROM users
+		WHERE team_name = ?
+		ORDER BY username
+	`
+
+	rows, err := executor.QueryContext(ctx, query, name)
+	if err != nil {
+		logger.Error("Failed to get team members for %s: %v", name, err)
+		return nil, fmt.Errorf("failed to get team members: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			logger.Error("Failed to scan user for team %s: %v", name, err)
+			return nil, fmt.Errorf("failed to scan user: %w", err)
+		}
+		team.Members = append(team.Members, *user)
+	}
+
+	if err := rows.Err(); err != nil {
+		logger.Error("Error iterating team members for %s: %v", name, err)
+		return nil, fmt.Errorf("error iterating team members: %w", err)
+	}
+
+	logger.Debug("Retrieved team %s with %d members", name, len(team.Members))
+	return team, nil
+}
+
+// Exists checks if a team exists
+func (r *TeamRepository) Exists(ctx context.Context, name string) (bool, error) {
+	executor := getExecutor(ctx, r.db)
+
+	query := `SELECT EXISTS(SELECT 1 FROM teams WHERE name = ?)`
+
+	var exists bool
+	err := executor.QueryRowContext(ctx, query, name).Scan(&exists)
+	if err != nil {
+		logger.Error("Failed to check team existence %s: %v", name, err)
+		return false, fmt.Errorf("failed to check team existence: %w", err)
+	}
+
+	return exists, nil
+}
+
+// SetChatWebhook sets the incoming webhook URL for team notifications; an empty URL disables them
+func (r *TeamRepository) SetChatWebhook(ctx context.Context, name, url string) error {
+	executor := getExecutor(ctx, r.db)
+
+	query := `UPDATE teams SET chat_webhook_url = ? WHERE name = ?`
+
+	result, err := executor.ExecContext(ctx, query, url, name)
+	if err != nil {
+		logger.Error("Failed to set chat webhook for team %s: %v", name, err)
+		return fmt.Errorf("failed to set chat webhook: %w", err)
+	}
+
+	if err := expectAffected(result, pkgerrors.ErrTeamNotFound); err != nil {
+		return err
+	}
+
+	logger.Info("Set chat webhook for team %s (enabled: %t)", name, url != "")
+	return nil
+}
+
+// SetReviewSLA sets how long reviewers of the team have (0 means the default) and what happens once they run out
+func (r *TeamRepository) SetReviewSLA(ctx context.Context, name string, minutes int, action models.SLAAction) error {
+	executor := getExecutor(ctx, r.db)
+
+	query := `UPDATE teams SET review_sla_minutes = ?, sla_action = ? WHERE name = ?`
+
+	result, err := executor.ExecContext(ctx, query, minutes, string(action), name)
+	if err != nil {
+		logger.Error("Failed to set review SLA for team %s: %v", name, err)
+		return fmt.Errorf("failed to set review SLA: %w", err)
+	}
+
+	if err := expectAffected(result, pkgerrors.ErrTeamNotFound); err != nil {
+		return err
+	}
+
+	logger.Info("Set review SLA for team %s to %d minutes (action: %s)", name, minutes, action)
+	return nil
+}
+
+// SetLead sets the team lead; an empty leadID removes the lead
+func (r *TeamRepository) SetLead(ctx context.Context, name, leadID string) error {
+	executor := getExecutor(ctx, r.db)
+
+	// lead_id has no foreign key in SQLite (see migration 00013), so the user is checked here
+	if leadID != "" {
+		var exists bool
+		if err := executor.QueryRowContext(ctx, `SELECT EXISTS(SELECT 1 FROM users WHERE id = ?)`, leadID).Scan(&exists); err != nil {
+			logger.Error("Failed to check user existence %s: %v", leadID, err)
+			return fmt.Errorf("failed to check user existence: %w", err)
+		}
+		if !exists {
+			return pkgerrors.ErrUserNotFound
+		}
+	}
+
+	query := `UPDATE teams SET lead_id = NULLIF(?, '') WHERE name = ?`
+
+	result, err := executor.ExecContext(ctx, query, leadID, name)
+	if err != nil {
+		logger.Error("Failed to set lead for team %s: %v", name, err)
+		return fmt.Errorf("failed to set team lead: %w", err)
+	}
+
+	if err := expectAffected(result, pkgerrors.ErrTeamNotFound); err != nil {
+		return err
+	}
+
+	logger.Info("Set lead for team %s to %q", name, leadID)
+	return nil
+}
+
+// SetCalendar sets the working calendar used to count the review SLA
+func (r *TeamRepository) SetCalendar(ctx context.Context, name string, calendar models.TeamCalendar) error {
+	executor := getExecutor(ctx, r.db)
+
+	query := `UPDATE teams SET timezone = ?, weekend_days = ? WHERE name = ?`
+
+	result, err := executor.ExecContext(ctx, query, calendar.Timezone, int(calendar.Weekend), name)
+	if err != nil {
+		logger.Error("Failed to set calendar for team %s: %v", name, err)
+		return fmt.Errorf("failed to set team calendar: %w", err)
+	}
+
+	if err := expectAffected(result, pkgerrors.ErrTeamNotFound); err != nil {
+		return err
+	}
+
+	logger.Info("Set calendar for team %s (timezone: %s, weekend: %v)", name, calendar.Timezone, calendar.Weekend.Days())
+	return nil
+}
+
+// SetWorkingHoursPreference sets whether reviewers inside their working hours are picked first
+func (r *TeamRepository) SetWorkingHoursPreference(ctx context.Context, name string, enabled bool, withinHours int) error {
+	executor := getExecutor(ctx, r.db)
+
+	query := `UPDATE teams SET prefer_working_hours = ?, prefer_within_hours = ? WHERE name = ?`
+
+	result, err := executor.ExecContext(ctx, query, enabled, withinHours, name)
+	if err != nil {
+		logger.Error("Failed to set working hours preference for team %s: %v", name, err)
+		return fmt.Errorf("failed to set working hours preference: %w", err)
+	}
+
+	if err := expectAffected(result, pkgerrors.ErrTeamNotFound); err != nil {
+		return err
+	}
+
+	logger.Info("Set working hours preference for team %s (enabled: %t, within: %dh)", name, enabled, withinHours)
+	return nil
+}
+
+// SetSeniorityPolicy sets the level at least one reviewer of a PR must have; an empty level removes the policy
+func (r *TeamRepository) SetSeniorityPolicy(ctx context.Context, name string, level models.Seniority) error {
+	executor := getExecutor(ctx, r.db)
+
+	query := `UPDATE teams SET min_reviewer_seniority = ? WHERE name = ?`
+
+	result, err := executor.ExecContext(ctx, query, string(level), name)
+	if err != nil {
+		logger.Error("Failed to set seniority policy for team %s: %v", name, err)
+		return fmt.Errorf("failed to set seniority policy: %w", err)
+	}
+
+	if err := expectAffected(result, pkgerrors.ErrTeamNotFound); err != nil {
+		return err
+	}
+
+	logger.Info("Set seniority policy for team %s to %q", name, level)
+	return nil
+}
+
+// ReplaceCodeOwners replaces the CODEOWNERS rules of the team, keeping their order
+func (r *TeamRepository) ReplaceCodeOwners(ctx context.Context, name string, rules []models.CodeOwnerRule) error {
+	executor := getExecutor(ctx, r.db)
+
+	if _, err := executor.ExecContext(ctx, `DELETE FROM code_owner_rules WHERE team_name = ?`, name); err != nil {
+		logger.Error("Failed to delete code owners of team %s: %v", name, err)
+		return fmt.Errorf("failed to delete code owners: %w", err)
+	}
+
+	query := `
+		INSERT INTO code_owner_rules (team_name, position, line, pattern, owners)
+		VALUES (?, ?, ?, ?, ?)
+	`
+	for i, rule := range rules {
+		_, err := executor.ExecContext(ctx, query, name, i, rule.Line, rule.Pattern, models.FormatCodeOwners(rule.Owners))
+		if err != nil {
+			if isForeignKeyViolation(err) {
+				return pkgerrors.ErrTeamNotFound
+			}
+			logger.Error("Failed to insert code owner rule for team %s: %v", name, err)
+			return fmt.Errorf("failed to insert code owner rule: %w", err)
+		}
+	}
+
+	logger.Info("Replaced code owners of team %s (%d rules)", name, len(rules))
+	return nil
+}
+
+// ListCodeOwners returns the CODEOWNERS rules of the team in file order
+func (r *TeamRepository) ListCodeOwners(ctx context.Context, name string) ([]models.CodeOwnerRule, error) {
+	executor := getExecutor(ctx, r.db)
+
+	query := `SELECT line, pattern, owners FROM code_owner_rules WHERE team_name = ? ORDER BY position`
+
+	rows, err := executor.QueryContext(ctx, query, name)
+	if err != nil {
+		logger.Error("Failed to list code owners of team %s: %v", name, err)
+		return nil, fmt.Errorf("failed to list code owners: %w", err)
+	}
+	defer rows.Close()
+
+	rules := make([]models.CodeOwnerRule, 0)
+	for rows.Next() {
+		var rule models.CodeOwnerRule
+		var owners string
+		if err := rows.Scan(&rule.Line, &rule.Pattern, &owners); err != nil {
+			logger.Error("Failed to scan code owner rule: %v", err)
+			return nil, fmt.Errorf("failed to scan code owner rule: %w", err)
+		}
+		if rule.Owners, err = models.ParseCodeOwners(owners); err != nil {
+			return nil, fmt.Errorf("invalid owners of rule at line %d: %w", rule.Line, err)
+		}
+		rules = append(rules, rule)
+	}
+
+	if err := rows.Err(); err != nil {
+		logger.Error("Error iterating code owner rules: %v", err)
+		return nil, fmt.Errorf("error iterating code owner rules: %w", err)
+	}
+	return rules, nil
+}
+
+// ListNames returns the names of all teams in alphabetical order
+func (r *TeamRepository) ListNames(ctx context.Context) ([]string, error) {
+	executor := getExecutor(ctx, r.db)
+
+	rows, err := executor.QueryContext(ctx, `SELECT name FROM teams ORDER BY name`)
+	if err != nil {
+		logger.Error("Failed to list teams: %v", err)
+		return nil, fmt.Errorf("failed to list teams: %w", err)
+	}
+	defer rows.Close()
+
+	names := make([]string, 0)
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			logger.Error("Failed to scan team name: %v", err)
+			return nil, fmt.Errorf("failed to scan team name: %w", err)
+		}
+		names = append(names, name)
+	}
+
+	if err := rows.Err(); err != nil {
+		logger.Error("Error iterating teams: %v", err)
+		return nil, fmt.Errorf("error iterating teams: %w", err)
+	}
+	return names, nil
+}
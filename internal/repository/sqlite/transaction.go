@@ -0,0 +1,102 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// TransactionManager implements repository.TransactionManager for SQLite
+type TransactionManager struct {
+	db *sql.DB
+}
+
+// NewTransactionManager creates a new transaction manager
+func NewTransactionManager(db *sql.DB) *TransactionManager {
+	return &TransactionManager{db: db}
+}
+
+// WithTransaction executes a function within a database transaction
+// If the function returns an error or panics, the transaction is rolled back
+// Otherwise, the transaction is committed
+// A nested call runs in a savepoint of the outer transaction (SQLite allows only one
+// writer at a time): its error rolls back only the nested changes
+func (tm *TransactionManager) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if tx, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return withSavepoint(ctx, tx, fn)
+	}
+
+	tx, err := tm.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	// Release the write lock even if fn panics
+	defer func() {
+		if p := recover(); p != nil {
+			_ = tx.Rollback()
+			panic(p)
+		}
+	}()
+
+	// Create a context with the transaction
+	txCtx := context.WithValue(ctx, txKey{}, tx)
+
+	// Execute the function
+	if err := fn(txCtx); err != nil {
+		// Rollback on error
+		if rbErr := tx.Rollback(); rbErr != nil {
+			return fmt.Errorf("failed to rollback transaction: %v (original error: %w)", rbErr, err)
+		}
+		return err
+	}
+
+	// Commit on success
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// withSavepoint executes a function within a savepoint of tx
+// Savepoints nest, so one name is enough: ROLLBACK TO and RELEASE act on the innermost one
+func withSavepoint(ctx context.Context, tx *sql.Tx, fn func(ctx context.Context) error) error {
+	if _, err := tx.ExecContext(ctx, "SAVEPOINT nested_tx"); err != nil {
+		return fmt.Errorf("failed to create savepoint: %w", err)
+	}
+
+	if err := fn(ctx); err != nil {
+		if _, rbErr := tx.ExecContext(ctx, "ROLLBACK TO nested_tx"); rbErr != nil {
+			return fmt.Errorf("failed to rollback to savepoint: %v (original error: %w)", rbErr, err)
+		}
+		if _, relErr := tx.ExecContext(ctx, "RELEASE nested_tx"); relErr != nil {
+			return fmt.Errorf("failed to release savepoint: %v (original error: %w)", relErr, err)
+		}
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, "RELEASE nested_tx"); err != nil {
+		return fmt.Errorf("failed to release savepoint: %w", err)
+	}
+
+	return nil
+}
+
+// txKey is a context key for storing transaction
+type txKey struct{}
+
+// executor is a common interface for database and transaction
+type executor interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// getExecutor retrieves a transaction from context, or returns the database if no transaction exists
+func getExecutor(ctx context.Context, db *sql.DB) executor {
+	if tx, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return tx
+	}
+	return db
+}
@@ -0,0 +1,329 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"avito-backend-trainee-assignment-autumn-2025/internal/domain/models"
+	pkgerrors "avito-backend-trainee-assignment-autumn-2025/pkg/errors"
+	"avito-backend-trainee-assignment-autumn-2025/pkg/logger"
+)
+
+// UserRepository implements repository.UserRepository for SQLite
+type UserRepository struct {
+	db *sql.DB
+}
+
+// NewUserRepository creates a new user repository
+func NewUserRepository(db *sql.DB) *UserRepository {
+	return &UserRepository{db: db}
+}
+
+// userColumns is the column list matching scanUser
+const userColumns = `id, username, team_name, is_active, mention_handle, email, timezone, work_start_minute, work_end_minute, max_open_reviews, skill_tags, seniority`
+
+// Create creates a new user
+func (r *UserRepository) Create(ctx context.Context, user *models.User) error {
+	executor := getExecutor(ctx, r.db)
+
+	query := `
+		INSERT INTO users (id, username, team_name, is_active, mention_handle, email, max_open_reviews, skill_tags, seniority)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	_, err := executor.ExecContext(ctx, query, user.ID, user.Username, user.TeamName, user.IsActive, user.MentionHandle, user.Email,
+		user.MaxOpenReviews, encodeTags(user.SkillTags), string(user.Seniority))
+	if err != nil {
+		logger.Error("Failed to create user %s: %v", user.ID, err)
+		// Check for unique violation
+		if isUniqueViolation(err) {
+			return pkgerrors.ErrUserAlreadyExists
+		}
+		// Check for foreign key violation (team doesn't exist)
+		if isForeignKeyViolation(err) {
+			return pkgerrors.ErrTeamNotFound
+		}
+		return fmt.Errorf("failed to create user: %w", err)
+	}
+
+	logger.Info("Created user: %s (username: %s, team: %s)", user.ID, user.Username, user.TeamName)
+	return nil
+}
+
+// Update updates an existing user
+func (r *UserRepository) Update(ctx context.Context, user *models.User) error {
+	executor := getExecutor(ctx, r.db)
+
+	query := `
+		UPDATE users
+		SET username = ?, team_name = ?, is_active = ?, mention_handle = ?, email = ?, max_open_reviews = ?, skill_tags = ?, seniority = ?, updated_at = strftime('%Y-%m-%d %H:%M:%f', 'now')
+		WHERE id = ?
+	`
+
+	result, err := executor.ExecContext(ctx, query, user.Username, user.TeamName, user.IsActive, user.MentionHandle, user.Email,
+		user.MaxOpenReviews, encodeTags(user.SkillTags), string(user.Seniority), user.ID)
+	if err != nil {
+		logger.Error("Failed to update user %s: %v", user.ID, err)
+		// Check for foreign key violation (team doesn't exist)
+		if isForeignKeyViolation(err) {
+			return pkgerrors.ErrTeamNotFound
+		}
+		return fmt.Errorf("failed to update user: %w", err)
+	}
+
+	if err := expectAffected(result, pkgerrors.ErrUserNotFound); err != nil {
+		return err
+	}
+
+	logger.Info("Updated user: %s", user.ID)
+	return nil
+}
+
+// GetByID retrieves a user by ID
+func (r *UserRepository) GetByID(ctx context.Context, id string) (*models.User, error) {
+	executor := getExecutor(ctx, r.db)
+
+	query := `SELECT ` + userColumns + ` FROM users WHERE id = ?`
+
+	user, err := scanUser(executor.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if isNoRows(err) {
+			return nil, pkgerrors.ErrUserNotFound
+		}
+		logger.Error("Failed to get user %s: %v", id, err)
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	logger.Debug("Retrieved user: %s", user.ID)
+	return user, nil
+}
+
+// GetByTeamName retrieves all users in a team
+func (r *UserRepository) GetByTeamName(ctx context.Context, teamName string) ([]models.User, error) {
+	executor := getExecutor(ctx, r.db)
+
+	query := `
+		SELECT ` + userColumns + `
+		FROM users
+		WHERE team_name = ?
+		ORDER BY username
+	`
+
+	rows, err := executor.QueryContext(ctx, query, teamName)
+	if err != nil {
+		logger.Error("Failed to get users for team %s: %v", teamName, err)
+		return nil, fmt.Errorf("failed to get users by team: %w", err)
+	}
+	defer rows.Close()
+
+	users := make([]models.User, 0)
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			logger.Error("Failed to scan user for team %s: %v", teamName, err)
+			return nil, fmt.Errorf("failed to scan user: %w", err)
+		}
+		users = append(users, *user)
+	}
+
+	if err := rows.Err(); err != nil {
+		logger.Error("Error iterating users for team %s: %v", teamName, err)
+		return nil, fmt.Errorf("error iterating users: %w", err)
+	}
+
+	logger.Debug("Retrieved %d users for team %s", len(users), teamName)
+	return users, nil
+}
+
+// SetActive sets the active status of a user
+func (r *UserRepository) SetActive(ctx context.Context, userID string, isActive bool) error {
+	executor := getExecutor(ctx, r.db)
+
+	query := `
+		UPDATE users
+		SET is_active = ?, updated_at = strftime('%Y-%m-%d %H:%M:%f', 'now')
+		WHERE id = ?
+	`
+
+	result, err := executor.ExecContext(ctx, query, isActive, userID)
+	if err != nil {
+		logger.Error("Failed to set active status for user %s: %v", userID, err)
+		return fmt.Errorf("failed to set active status: %w", err)
+	}
+
+	if err := expectAffected(result, pkgerrors.ErrUserNotFound); err != nil {
+		return err
+	}
+
+	logger.Info("Set user %s active status to %t", userID, isActive)
+	return nil
+}
+
+// SetMentionHandle sets the chat handle used to mention a user; an empty handle disables mentions
+func (r *UserRepository) SetMentionHandle(ctx context.Context, userID, handle string) error {
+	executor := getExecutor(ctx, r.db)
+
+	query := `
+		UPDATE users
+		SET mention_handle = ?, updated_at = strftime('%Y-%m-%d %H:%M:%f', 'now')
+		WHERE id = ?
+	`
+
+	result, err := executor.ExecContext(ctx, query, handle, userID)
+	if err != nil {
+		logger.Error("Failed to set mention handle for user %s: %v", userID, err)
+		return fmt.Errorf("failed to set mention handle: %w", err)
+	}
+
+	if err := expectAffected(result, pkgerrors.ErrUserNotFound); err != nil {
+		return err
+	}
+
+	logger.Info("Set user %s mention handle to %q", userID, handle)
+	return nil
+}
+
+// SetEmail sets the address for email notifications; an empty address disables them
+func (r *UserRepository) SetEmail(ctx context.Context, userID, email string) error {
+	executor := getExecutor(ctx, r.db)
+
+	query := `
+		UPDATE users
+		SET email = ?, updated_at = strftime('%Y-%m-%d %H:%M:%f', 'now')
+		WHERE id = ?
+	`
+
+	result, err := executor.ExecContext(ctx, query, email, userID)
+	if err != nil {
+		logger.Error("Failed to set email for user %s: %v", userID, err)
+		return fmt.Errorf("failed to set email: %w", err)
+	}
+
+	if err := expectAffected(result, pkgerrors.ErrUserNotFound); err != nil {
+		return err
+	}
+
+	logger.Info("Set user %s email (enabled: %t)", userID, email != "")
+	return nil
+}
+
+// SetWorkingHours sets the user's timezone and working hours; an empty timezone means the team's
+func (r *UserRepository) SetWorkingHours(ctx context.Context, userID, timezone string, hours models.WorkingHours) error {
+	executor := getExecutor(ctx, r.db)
+
+	query := `
+		UPDATE users
+		SET timezone = ?, work_start_minute = ?, work_end_minute = ?, updated_at = strftime('%Y-%m-%d %H:%M:%f', 'now')
+		WHERE id = ?
+	`
+
+	result, err := executor.ExecContext(ctx, query, timezone, hours.Start, hours.End, userID)
+	if err != nil {
+		logger.Error("Failed to set working hours for user %s: %v", userID, err)
+		return fmt.Errorf("failed to set working hours: %w", err)
+	}
+
+	if err := expectAffected(result, pkgerrors.ErrUserNotFound); err != nil {
+		return err
+	}
+
+	logger.Info("Set user %s working hours to %d-%d (timezone: %q)", userID, hours.Start, hours.End, timezone)
+	return nil
+}
+
+// SetMaxOpenReviews sets how many open reviews the user may have at once; 0 removes the limit
+func (r *UserRepository) SetMaxOpenReviews(ctx context.Context, userID string, limit int) error {
+	executor := getExecutor(ctx, r.db)
+
+	query := `
+		UPDATE users
+		SET max_open_reviews = ?, updated_at = strftime('%Y-%m-%d %H:%M:%f', 'now')
+		WHERE id = ?
+	`
+
+	result, err := executor.ExecContext(ctx, query, limit, userID)
+	if err != nil {
+		logger.Error("Failed to set max open reviews for user %s: %v", userID, err)
+		return fmt.Errorf("failed to set max open reviews: %w", err)
+	}
+
+	if err := expectAffected(result, pkgerrors.ErrUserNotFound); err != nil {
+		return err
+	}
+
+	logger.Info("Set user %s max open reviews to %d", userID, limit)
+	return nil
+}
+
+// SetSkillTags replaces the user's skill tags
+func (r *UserRepository) SetSkillTags(ctx context.Context, userID string, tags []string) error {
+	executor := getExecutor(ctx, r.db)
+
+	query := `
+		UPDATE users
+		SET skill_tags = ?, updated_at = strftime('%Y-%m-%d %H:%M:%f', 'now')
+		WHERE id = ?
+	`
+
+	result, err := executor.ExecContext(ctx, query, encodeTags(tags), userID)
+	if err != nil {
+		logger.Error("Failed to set skill tags for user %s: %v", userID, err)
+		return fmt.Errorf("failed to set skill tags: %w", err)
+	}
+
+	if err := expectAffected(result, pkgerrors.ErrUserNotFound); err != nil {
+		return err
+	}
+
+	logger.Info("Set user %s skill tags to %v", userID, tags)
+	return nil
+}
+
+// SetSeniority sets the user's reviewer level; an empty level clears it
+func (r *UserRepository) SetSeniority(ctx context.Context, userID string, level models.Seniority) error {
+	executor := getExecutor(ctx, r.db)
+
+	query := `
+		UPDATE users
+		SET seniority = ?, updated_at = strftime('%Y-%m-%d %H:%M:%f', 'now')
+		WHERE id = ?
+	`
+
+	result, err := executor.ExecContext(ctx, query, string(level), userID)
+	if err != nil {
+		logger.Error("Failed to set seniority for user %s: %v", userID, err)
+		return fmt.Errorf("failed to set seniority: %w", err)
+	}
+
+	if err := expectAffected(result, pkgerrors.ErrUserNotFound); err != nil {
+		return err
+	}
+
+	logger.Info("Set user %s seniority to %q", userID, level)
+	return nil
+}
+
+// LockForUpdate is a no-op: SQLite has no row locks, and transactions take the database
+// write lock up front (_txlock=immediate), so they already run one after another
+func (r *UserRepository) LockForUpdate(ctx context.Context, userIDs []string) error {
+	return ctx.Err()
+}
+
+// scanUser scans a users row selected with userColumns
+func scanUser(row rowScanner) (*models.User, error) {
+	var user models.User
+	var skillTags, seniority string
+	if err := row.Scan(&user.ID, &user.Username, &user.TeamName, &user.IsActive, &user.MentionHandle, &user.Email,
+		&user.Timezone, &user.WorkingHours.Start, &user.WorkingHours.End, &user.MaxOpenReviews, &skillTags, &seniority,
+	); err != nil {
+		return nil, err
+	}
+	tags, err := decodeTags(skillTags)
+	if err != nil {
+		return nil, err
+	}
+	user.SkillTags = tags
+	user.Seniority = models.Seniority(seniority)
+	return &user, nil
+}
@@ -0,0 +1,295 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"avito-backend-trainee-assignment-autumn-2025/internal/domain/models"
+	pkgerrors "avito-backend-trainee-assignment-autumn-2025/pkg/errors"
+	"avito-backend-trainee-assignment-autumn-2025/pkg/logger"
+)
+
+// WebhookRepository implements repository.WebhookRepository for SQLite
+type WebhookRepository struct {
+	db *sql.DB
+}
+
+// NewWebhookRepository creates a new webhook repository
+func NewWebhookRepository(db *sql.DB) *WebhookRepository {
+	return &WebhookRepository{db: db}
+}
+
+// deliveryColumns is the column list matching scanDelivery
+const deliveryColumns = `id, webhook_id, event_id, event_type, payload, status, attempts, last_error,
+	next_attempt_at, created_at, delivered_at`
+
+// rowScanner is implemented by *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// Create creates a new webhook
+func (r *WebhookRepository) Create(ctx context.Context, webhook *models.Webhook) error {
+	executor := getExecutor(ctx, r.db)
+
+	// Event types are stored as a JSON array
+	events, err := json.Marshal(webhook.Events)
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook events: %w", err)
+	}
+
+	query := `
+		INSERT INTO webhooks (id, url, secret, events, created_at)
+		VALUES (?, ?, ?, ?, ?)
+	`
+
+	_, err = executor.ExecContext(ctx, query, webhook.ID, webhook.URL, webhook.Secret, string(events), webhook.CreatedAt.UTC())
+	if err != nil {
+		logger.Error("Failed to create webhook %s: %v", webhook.ID, err)
+		return fmt.Errorf("failed to create webhook: %w", err)
+	}
+
+	logger.Info("Created webhook: %s (url: %s)", webhook.ID, webhook.URL)
+	return nil
+}
+
+// GetByID retrieves a webhook by ID
+func (r *WebhookRepository) GetByID(ctx context.Context, id string) (*models.Webhook, error) {
+	executor := getExecutor(ctx, r.db)
+
+	query := `
+		SELECT id, url, secret, events, created_at
+		FROM webhooks
+		WHERE id = ?
+	`
+
+	webhook, err := scanWebhook(executor.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if isNoRows(err) {
+			return nil, pkgerrors.ErrWebhookNotFound
+		}
+		logger.Error("Failed to get webhook %s: %v", id, err)
+		return nil, fmt.Errorf("failed to get webhook: %w", err)
+	}
+
+	return webhook, nil
+}
+
+// List retrieves all webhooks in registration order
+func (r *WebhookRepository) List(ctx context.Context) ([]models.Webhook, error) {
+	executor := getExecutor(ctx, r.db)
+
+	query := `
+		SELECT id, url, secret, events, created_at
+		FROM webhooks
+		ORDER BY created_at, rowid
+	`
+
+	rows, err := executor.QueryContext(ctx, query)
+	if err != nil {
+		logger.Error("Failed to list webhooks: %v", err)
+		return nil, fmt.Errorf("failed to list webhooks: %w", err)
+	}
+	defer rows.Close()
+
+	webhooks := make([]models.Webhook, 0)
+	for rows.Next() {
+		webhook, err := scanWebhook(rows)
+		if err != nil {
+			logger.Error("Failed to scan webhook: %v", err)
+			return nil, fmt.Errorf("failed to scan webhook: %w", err)
+		}
+		webhooks = append(webhooks, *webhook)
+	}
+
+	if err := rows.Err(); err != nil {
+		logger.Error("Error iterating webhooks: %v", err)
+		return nil, fmt.Errorf("error iterating webhooks: %w", err)
+	}
+
+	return webhooks, nil
+}
+
+// CreateDelivery creates a new webhook delivery
+func (r *WebhookRepository) CreateDelivery(ctx context.Context, delivery *models.WebhookDelivery) error {
+	executor := getExecutor(ctx, r.db)
+
+	query := `
+		INSERT INTO webhook_deliveries (id, webhook_id, event_id, event_type, payload, status, attempts,
+			last_error, next_attempt_at, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	_, err := executor.ExecContext(ctx, query,
+		delivery.ID, delivery.WebhookID, delivery.EventID, delivery.EventType, string(delivery.Payload), delivery.Status,
+		delivery.Attempts, delivery.LastError, delivery.NextAttemptAt.UTC(), delivery.CreatedAt.UTC(), delivery.CreatedAt.UTC(),
+	)
+	if err != nil {
+		logger.Error("Failed to create delivery %s: %v", delivery.ID, err)
+		// Check for foreign key violation (webhook doesn't exist)
+		if isForeignKeyViolation(err) {
+			return pkgerrors.ErrWebhookNotFound
+		}
+		return fmt.Errorf("failed to create delivery: %w", err)
+	}
+
+	logger.Debug("Created delivery %s of event %s to webhook %s", delivery.ID, delivery.EventID, delivery.WebhookID)
+	return nil
+}
+
+// GetDelivery retrieves a webhook delivery by ID
+func (r *WebhookRepository) GetDelivery(ctx context.Context, id string) (*models.WebhookDelivery, error) {
+	executor := getExecutor(ctx, r.db)
+
+	query := `SELECT ` + deliveryColumns + ` FROM webhook_deliveries WHERE id = ?`
+
+	delivery, err := scanDelivery(executor.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if isNoRows(err) {
+			return nil, pkgerrors.ErrDeliveryNotFound
+		}
+		logger.Error("Failed to get delivery %s: %v", id, err)
+		return nil, fmt.Errorf("failed to get delivery: %w", err)
+	}
+
+	return delivery, nil
+}
+
+// ListDeliveries retrieves the newest deliveries, optionally filtered by status
+func (r *WebhookRepository) ListDeliveries(ctx context.Context, status models.DeliveryStatus, limit int) ([]models.WebhookDelivery, error) {
+	executor := getExecutor(ctx, r.db)
+
+	query := `
+		SELECT ` + deliveryColumns + `
+		FROM webhook_deliveries
+		WHERE ?1 = '' OR status = ?1
+		ORDER BY created_at DESC, rowid DESC
+		LIMIT ?2
+	`
+
+	rows, err := executor.QueryContext(ctx, query, string(status), limit)
+	if err != nil {
+		logger.Error("Failed to list deliveries: %v", err)
+		return nil, fmt.Errorf("failed to list deliveries: %w", err)
+	}
+
+	return collectDeliveries(rows)
+}
+
+// ClaimDueDeliveries leases pending deliveries whose next attempt is due
+// SQLite serializes writes, so a single UPDATE claims the rows atomically
+func (r *WebhookRepository) ClaimDueDeliveries(ctx context.Context, now, lockedUntil time.Time, limit int) ([]models.WebhookDelivery, error) {
+	executor := getExecutor(ctx, r.db)
+
+	query := `
+		UPDATE webhook_deliveries
+		SET locked_until = ?2
+		WHERE rowid IN (
+			SELECT rowid
+			FROM webhook_deliveries
+			WHERE status = 'pending' AND next_attempt_at <= ?1 AND (locked_until IS NULL OR locked_until <= ?1)
+			ORDER BY next_attempt_at, rowid
+			LIMIT ?3
+		)
+		RETURNING ` + deliveryColumns + `
+	`
+
+	rows, err := executor.QueryContext(ctx, query, now.UTC(), lockedUntil.UTC(), limit)
+	if err != nil {
+		logger.Error("Failed to claim due deliveries: %v", err)
+		return nil, fmt.Errorf("failed to claim due deliveries: %w", err)
+	}
+
+	deliveries, err := collectDeliveries(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	// RETURNING does not keep the order of the subquery
+	sort.SliceStable(deliveries, func(i, j int) bool {
+		return deliveries[i].NextAttemptAt.Before(deliveries[j].NextAttemptAt)
+	})
+	return deliveries, nil
+}
+
+// UpdateDelivery stores the delivery state after an attempt or a redelivery request and releases its lease
+func (r *WebhookRepository) UpdateDelivery(ctx context.Context, delivery *models.WebhookDelivery) error {
+	executor := getExecutor(ctx, r.db)
+
+	query := `
+		UPDATE webhook_deliveries
+		SET status = ?, attempts = ?, last_error = ?, next_attempt_at = ?, delivered_at = ?,
+			locked_until = NULL, updated_at = strftime('%Y-%m-%d %H:%M:%f', 'now')
+		WHERE id = ?
+	`
+
+	var deliveredAt any
+	if delivery.DeliveredAt != nil {
+		deliveredAt = delivery.DeliveredAt.UTC()
+	}
+
+	result, err := executor.ExecContext(ctx, query,
+		delivery.Status, delivery.Attempts, delivery.LastError, delivery.NextAttemptAt.UTC(), deliveredAt, delivery.ID,
+	)
+	if err != nil {
+		logger.Error("Failed to update delivery %s: %v", delivery.ID, err)
+		return fmt.Errorf("failed to update delivery: %w", err)
+	}
+
+	return expectAffected(result, pkgerrors.ErrDeliveryNotFound)
+}
+
+// scanWebhook scans a webhooks row
+func scanWebhook(row rowScanner) (*models.Webhook, error) {
+	var webhook models.Webhook
+	var events string
+	if err := row.Scan(&webhook.ID, &webhook.URL, &webhook.Secret, &events, &webhook.CreatedAt); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(events), &webhook.Events); err != nil {
+		return nil, fmt.Errorf("failed to decode webhook events: %w", err)
+	}
+	return &webhook, nil
+}
+
+// scanDelivery scans a webhook_deliveries row selected with deliveryColumns
+func scanDelivery(row rowScanner) (*models.WebhookDelivery, error) {
+	var delivery models.WebhookDelivery
+	var payload string
+	err := row.Scan(
+		&delivery.ID, &delivery.WebhookID, &delivery.EventID, &delivery.EventType, &payload,
+		&delivery.Status, &delivery.Attempts, &delivery.LastError, &delivery.NextAttemptAt,
+		&delivery.CreatedAt, &delivery.DeliveredAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	delivery.Payload = []byte(payload)
+	return &delivery, nil
+}
+
+// collectDeliveries scans all rows and closes them
+func collectDeliveries(rows *sql.Rows) ([]models.WebhookDelivery, error) {
+	defer rows.Close()
+
+	deliveries := make([]models.WebhookDelivery, 0)
+	for rows.Next() {
+		delivery, err := scanDelivery(rows)
+		if err != nil {
+			logger.Error("Failed to scan delivery: %v", err)
+			return nil, fmt.Errorf("failed to scan delivery: %w", err)
+		}
+		deliveries = append(deliveries, *delivery)
+	}
+
+	if err := rows.Err(); err != nil {
+		logger.Error("Error iterating deliveries: %v", err)
+		return nil, fmt.Errorf("error iterating deliveries: %w", err)
+	}
+
+	return deliveries, nil
+}
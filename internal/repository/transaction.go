@@ -27,8 +27,16 @@ func NewPgxTransactionManager(pool *pgxpool.Pool) *PgxTransactionManager {
 // WithTransaction executes a function within a database transaction
 // If the function returns an error, the transaction is rolled back
 // Otherwise, the transaction is committed
+// A nested call runs in a savepoint of the outer transaction: its error rolls back
+// only the nested changes, and the outer function may handle it and continue
 func (tm *PgxTransactionManager) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
-	tx, err := tm.pool.BeginTx(ctx, pgx.TxOptions{})
+	var tx pgx.Tx
+	var err error
+	if outer, ok := ctx.Value(txKey{}).(pgx.Tx); ok {
+		tx, err = outer.Begin(ctx)
+	} else {
+		tx, err = tm.pool.BeginTx(ctx, pgx.TxOptions{})
+	}
 	if err != nil {
 		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
@@ -0,0 +1,26 @@
+package service
+
+import (
+	"context"
+
+	"avito-backend-trainee-assignment-autumn-2025/internal/domain/models"
+	"avito-backend-trainee-assignment-autumn-2025/internal/repository"
+	"avito-backend-trainee-assignment-autumn-2025/pkg/logger"
+)
+
+// recordEvent writes a domain event to the outbox
+// ctx must carry the transaction that made the change, so the event is stored only if the change is committed
+func recordEvent(ctx context.Context, outboxRepo repository.OutboxRepository, eventType models.EventType, data any) error {
+	msg, err := models.NewOutboxMessage(models.NewEvent(eventType, data))
+	if err != nil {
+		logger.Error("Failed to encode %s event: %v", eventType, err)
+		return err
+	}
+
+	if err := outboxRepo.Add(ctx, msg); err != nil {
+		logger.Error("Failed to record %s event: %v", eventType, err)
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,169 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"avito-backend-trainee-assignment-autumn-2025/internal/domain/models"
+	"avito-backend-trainee-assignment-autumn-2025/internal/repository"
+	"avito-backend-trainee-assignment-autumn-2025/pkg/dto/request"
+	"avito-backend-trainee-assignment-autumn-2025/pkg/dto/response"
+	pkgerrors "avito-backend-trainee-assignment-autumn-2025/pkg/errors"
+	"avito-backend-trainee-assignment-autumn-2025/pkg/logger"
+)
+
+// IntegrationServiceImpl implements IntegrationService
+type IntegrationServiceImpl struct {
+	accountRepo repository.CodeHostAccountRepository
+	prService   PRService
+}
+
+// NewIntegrationService creates a new integration service
+func NewIntegrationService(accountRepo repository.CodeHostAccountRepository, prService PRService) *IntegrationServiceImpl {
+	return &IntegrationServiceImpl{
+		accountRepo: accountRepo,
+		prService:   prService,
+	}
+}
+
+// LinkAccount maps a code host login to a user
+func (s *IntegrationServiceImpl) LinkAccount(ctx context.Context, req *request.LinkAccountRequest) (*response.LinkAccountResponse, error) {
+	// Validate input
+	provider := models.CodeHost(req.Provider)
+	if req.Provider == "" {
+		return nil, pkgerrors.NewRequiredFieldError("provider")
+	}
+	if !provider.IsValid() {
+		return nil, pkgerrors.NewValidationError("provider", fmt.Sprintf("is not a known code host: %s", req.Provider))
+	}
+	if req.Login == "" {
+		return nil, pkgerrors.NewRequiredFieldError("login")
+	}
+	if req.UserID == "" {
+		return nil, pkgerrors.NewRequiredFieldError("user_id")
+	}
+
+	logger.Info("Linking %s account %s to user %s", provider, req.Login, req.UserID)
+
+	account := &models.CodeHostAccount{
+		Provider:  provider,
+		Login:     normalizeLogin(req.Login),
+		UserID:    req.UserID,
+		CreatedAt: time.Now().UTC(),
+	}
+
+	if err := s.accountRepo.Link(ctx, account); err != nil {
+		logger.Error("Failed to link %s account %s: %v", provider, req.Login, err)
+		return nil, err
+	}
+
+	// Convert to response DTO
+	return &response.LinkAccountResponse{
+		Account: convertAccountToResponse(account),
+	}, nil
+}
+
+// ListAccounts returns linked accounts, optionally filtered by provider
+func (s *IntegrationServiceImpl) ListAccounts(ctx context.Context, provider string) (*response.ListAccountsResponse, error) {
+	// Validate input
+	codeHost := models.CodeHost(provider)
+	if provider != "" && !codeHost.IsValid() {
+		return nil, pkgerrors.NewValidationError("provider", fmt.Sprintf("is not a known code host: %s", provider))
+	}
+
+	accounts, err := s.accountRepo.List(ctx, codeHost)
+	if err != nil {
+		logger.Error("Failed to list accounts: %v", err)
+		return nil, fmt.Errorf("failed to list accounts: %w", err)
+	}
+
+	// Convert to response DTO
+	result := make([]response.CodeHostAccountResponse, 0, len(accounts))
+	for i := range accounts {
+		result = append(result, convertAccountToResponse(&accounts[i]))
+	}
+
+	return &response.ListAccountsResponse{
+		Accounts: result,
+	}, nil
+}
+
+// HandlePullRequestEvent applies a code host pull request event through PRService
+func (s *IntegrationServiceImpl) HandlePullRequestEvent(ctx context.Context, event *models.CodeHostPREvent) (*response.PullRequestEventResponse, error) {
+	prID := event.PullRequestID()
+	logger.Info("Handling %s pull request event %s for %s", event.Provider, event.Action, prID)
+
+	switch event.Action {
+	case models.PRActionOpened, models.PRActionReopened:
+		return s.createPR(ctx, event, prID)
+
+	case models.PRActionMerged:
+		resp, err := s.prService.MergePR(ctx, &request.MergePRRequest{PullRequestID: prID})
+		if errors.Is(err, pkgerrors.ErrPRNotFound) {
+			// The PR was opened before the integration was set up
+			logger.Info("Ignoring merge of unknown PR %s", prID)
+			return &response.PullRequestEventResponse{Result: response.EventResultIgnored, PullRequestID: prID}, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		return &response.PullRequestEventResponse{Result: response.EventResultMerged, PullRequestID: prID, PR: &resp.PR}, nil
+
+	default:
+		// PRs closed without merging keep their state: the service has no CLOSED status
+		logger.Info("Ignoring %s event for PR %s", event.Action, prID)
+		return &response.PullRequestEventResponse{Result: response.EventResultIgnored, PullRequestID: prID}, nil
+	}
+}
+
+// createPR creates the PR of an opened or reopened event on behalf of the linked author
+func (s *IntegrationServiceImpl) createPR(ctx context.Context, event *models.CodeHostPREvent, prID string) (*response.PullRequestEventResponse, error) {
+	if event.AuthorLogin == "" {
+		return nil, pkgerrors.NewRequiredFieldError("author login")
+	}
+
+	authorID, err := s.accountRepo.GetUserID(ctx, event.Provider, normalizeLogin(event.AuthorLogin))
+	if err != nil {
+		logger.Error("Failed to resolve %s author %s of PR %s: %v", event.Provider, event.AuthorLogin, prID, err)
+		return nil, fmt.Errorf("%s account %s: %w", event.Provider, event.AuthorLogin, err)
+	}
+
+	name := event.Title
+	if name == "" {
+		name = prID
+	}
+
+	resp, err := s.prService.CreatePR(ctx, &request.CreatePRRequest{
+		PullRequestID:   prID,
+		PullRequestName: name,
+		AuthorID:        authorID,
+	})
+	if errors.Is(err, pkgerrors.ErrPRExists) {
+		// Redelivered or reopened PR that is already tracked
+		logger.Info("PR %s already exists", prID)
+		return &response.PullRequestEventResponse{Result: response.EventResultExists, PullRequestID: prID}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &response.PullRequestEventResponse{Result: response.EventResultCreated, PullRequestID: prID, PR: &resp.PR}, nil
+}
+
+// normalizeLogin lowercases a login: code host logins are case-insensitive
+func normalizeLogin(login string) string {
+	return strings.ToLower(strings.TrimSpace(login))
+}
+
+// convertAccountToResponse converts a linked account to its response DTO
+func convertAccountToResponse(account *models.CodeHostAccount) response.CodeHostAccountResponse {
+	return response.CodeHostAccountResponse{
+		Provider:  string(account.Provider),
+		Login:     account.Login,
+		UserID:    account.UserID,
+		CreatedAt: account.CreatedAt,
+	}
+}
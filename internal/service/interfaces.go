@@ -2,9 +2,11 @@ package service
 
 import (
 	"context"
+	"time"
 
-	"avito-backend-trainee-assignment-autumn-2025/internal/dto/request"
-	"avito-backend-trainee-assignment-autumn-2025/internal/dto/response"
+	"avito-backend-trainee-assignment-autumn-2025/internal/domain/models"
+	"avito-backend-trainee-assignment-autumn-2025/pkg/dto/request"
+	"avito-backend-trainee-assignment-autumn-2025/pkg/dto/response"
 )
 
 // TeamService defines business logic for team operations
@@ -16,6 +18,40 @@ type TeamService interface {
 	// GetTeam retrieves a team with all its members
 	// Returns error if team doesn't exist
 	GetTeam(ctx context.Context, teamName string) (*response.TeamResponse, error)
+
+	// SetChatWebhook sets the incoming webhook URL for the team's chat notifications; an empty URL disables them
+	// Returns error if team doesn't exist or the URL is invalid
+	SetChatWebhook(ctx context.Context, req *request.SetChatWebhookRequest) (*response.SetChatWebhookResponse, error)
+
+	// SetReviewSLA sets how long the team's reviewers have (0 restores the default) and what happens
+	// to a review that stays open longer: a reminder, a reassignment or an escalation to the team lead
+	// Returns error if team doesn't exist, the SLA is out of range or escalation is chosen without a lead
+	SetReviewSLA(ctx context.Context, req *request.SetReviewSLARequest) (*response.SetReviewSLAResponse, error)
+
+	// SetLead sets the team lead who takes over escalated reviews; an empty lead_id removes the lead
+	// Returns error if team or user doesn't exist, or the user is not a member of the team
+	SetLead(ctx context.Context, req *request.SetLeadRequest) (*response.SetLeadResponse, error)
+
+	// SetCalendar sets the timezone and days off; the review SLA does not run on days off
+	// Returns error if team doesn't exist, the timezone is unknown or every day is a day off
+	SetCalendar(ctx context.Context, req *request.SetCalendarRequest) (*response.SetCalendarResponse, error)
+
+	// SetWorkingHoursPreference sets whether reviewers inside their working hours, or starting work within
+	// the given hours, are picked before the rest of the team
+	// Returns error if team doesn't exist or the hours are out of range
+	SetWorkingHoursPreference(ctx context.Context, req *request.SetWorkingHoursPreferenceRequest) (*response.SetWorkingHoursPreferenceResponse, error)
+
+	// SetSeniorityPolicy sets the level at least one reviewer of each PR must have; an empty level removes the policy
+	// Returns error if team doesn't exist or the level is unknown
+	SetSeniorityPolicy(ctx context.Context, req *request.SetSeniorityPolicyRequest) (*response.SetSeniorityPolicyResponse, error)
+
+	// UploadCodeOwners validates a CODEOWNERS file and, unless dryRun is set, replaces the team's rules with it
+	// Returns error if team doesn't exist, the file is malformed or names an unknown user or team
+	UploadCodeOwners(ctx context.Context, teamName string, dryRun bool, file []byte) (*response.UploadCodeOwnersResponse, error)
+
+	// GetCodeOwners returns the team's CODEOWNERS rules in file order
+	// Returns error if team doesn't exist
+	GetCodeOwners(ctx context.Context, teamName string) (*response.GetCodeOwnersResponse, error)
 }
 
 // UserService defines business logic for user operations
@@ -24,17 +60,83 @@ type UserService interface {
 	// Returns error if user doesn't exist
 	SetUserActive(ctx context.Context, req *request.SetUserActiveRequest) (*response.SetUserActiveResponse, error)
 
+	// SetMentionHandle sets the Slack member ID used to mention the user; an empty handle disables mentions
+	// Returns error if user doesn't exist or the handle is invalid
+	SetMentionHandle(ctx context.Context, req *request.SetMentionHandleRequest) (*response.SetMentionHandleResponse, error)
+
+	// SetEmail sets the address for email notifications; an empty address disables them
+	// Returns error if user doesn't exist or the address is invalid
+	SetEmail(ctx context.Context, req *request.SetEmailRequest) (*response.SetEmailResponse, error)
+
+	// SetWorkingHours sets the user's timezone and working hours; empty hours mean the user is always available
+	// Returns error if user doesn't exist, the timezone is unknown or the hours are invalid
+	SetWorkingHours(ctx context.Context, req *request.SetWorkingHoursRequest) (*response.SetWorkingHoursResponse, error)
+
+	// SetMaxOpenReviews sets how many open reviews the user may have at once; 0 removes the limit
+	// Returns error if user doesn't exist or the limit is out of range
+	SetMaxOpenReviews(ctx context.Context, req *request.SetMaxOpenReviewsRequest) (*response.SetMaxOpenReviewsResponse, error)
+
+	// SetSeniority sets the user's reviewer level (junior, middle or senior); an empty level clears it
+	// Returns error if user doesn't exist or the level is unknown
+	SetSeniority(ctx context.Context, req *request.SetSeniorityRequest) (*response.SetSeniorityResponse, error)
+
+	// AddSkillTags adds skill tags to the user; tags are lowercased and the ones the user has are ignored
+	// Returns error if user doesn't exist, a tag is invalid or the user would have too many
+	AddSkillTags(ctx context.Context, req *request.AddSkillTagsRequest) (*response.SkillTagsResponse, error)
+
+	// RemoveSkillTags removes skill tags from the user; tags the user does not have are ignored
+	// Returns error if user doesn't exist or a tag is invalid
+	RemoveSkillTags(ctx context.Context, req *request.RemoveSkillTagsRequest) (*response.SkillTagsResponse, error)
+
+	// SetSkillTags replaces the user's skill tags; an empty list removes them
+	// Returns error if user doesn't exist, a tag is invalid or there are too many
+	SetSkillTags(ctx context.Context, req *request.SetSkillTagsRequest) (*response.SkillTagsResponse, error)
+
+	// GetSkillTags returns the user's skill tags in alphabetical order
+	// Returns error if user doesn't exist
+	GetSkillTags(ctx context.Context, userID string) (*response.GetSkillTagsResponse, error)
+
 	// GetUserReviews retrieves all pull requests where the user is assigned as a reviewer
 	// Returns error if user doesn't exist
 	GetUserReviews(ctx context.Context, userID string) (*response.GetUserReviewsResponse, error)
+
+	// AddAbsence records a period when the user is not assigned to reviews; is_active is not changed
+	// Returns error if user doesn't exist or the dates are invalid
+	AddAbsence(ctx context.Context, req *request.AddAbsenceRequest) (*response.AddAbsenceResponse, error)
+
+	// ListAbsences returns all absences of the user ordered by start date
+	// Returns error if user doesn't exist
+	ListAbsences(ctx context.Context, userID string) (*response.ListAbsencesResponse, error)
+
+	// ImportAbsences replaces the user's absences imported earlier with the events of an iCalendar file
+	// Returns error if user doesn't exist or the file cannot be parsed
+	ImportAbsences(ctx context.Context, userID string, calendar []byte) (*response.ImportAbsencesResponse, error)
+
+	// DeleteAbsence removes an absence
+	// Returns error if absence doesn't exist
+	DeleteAbsence(ctx context.Context, req *request.DeleteAbsenceRequest) (*response.DeleteAbsenceResponse, error)
+
+	// SetAffinity creates or replaces how an author relates to a reviewer
+	// Returns error if either user doesn't exist, they are the same user or the weight is out of range
+	SetAffinity(ctx context.Context, req *request.SetAffinityRequest) (*response.SetAffinityResponse, error)
+
+	// ListAffinities returns the affinities where the user is the author or the reviewer
+	// Returns error if user doesn't exist
+	ListAffinities(ctx context.Context, userID string) (*response.ListAffinitiesResponse, error)
+
+	// DeleteAffinity removes the affinity of a pair, making it neutral
+	// Returns error if the pair has no affinity
+	DeleteAffinity(ctx context.Context, req *request.DeleteAffinityRequest) (*response.DeleteAffinityResponse, error)
 }
 
 // PRService defines business logic for pull request operations
 type PRService interface {
 	// CreatePR creates a new pull request and automatically assigns up to 2 reviewers
 	// Reviewers are selected randomly from the author's team (excluding the author)
-	// Only active users can be assigned as reviewers
-	// Returns error if PR already exists, author doesn't exist, or validation fails
+	// Nobody in a conflict of interest with the author is assigned; preferred reviewers are likelier
+	// Only active users with room under their open review limit can be assigned as reviewers
+	// Returns error if PR already exists, author doesn't exist, validation fails
+	// or every candidate is at their limit (ALL_AT_CAPACITY)
 	CreatePR(ctx context.Context, req *request.CreatePRRequest) (*response.CreatePRResponse, error)
 
 	// MergePR merges a pull request (sets status to MERGED)
@@ -43,11 +145,58 @@ type PRService interface {
 	MergePR(ctx context.Context, req *request.MergePRRequest) (*response.MergePRResponse, error)
 
 	// ReassignReviewer replaces one reviewer with another random active member
-	// The new reviewer is selected from the replaced reviewer's team
+	// The new reviewer is selected from the replaced reviewer's team, skipping conflicts of interest with the author
 	// Returns error if:
 	// - PR doesn't exist
 	// - PR is already merged (PR_MERGED)
 	// - old_user_id is not assigned as reviewer (NOT_ASSIGNED)
 	// - No suitable candidates available (NO_CANDIDATE)
+	// - Every candidate is at their open review limit (ALL_AT_CAPACITY)
+	// - The PR would lose its only reviewer at the team's minimum seniority and nobody can replace them (SENIORITY_REQUIRED)
 	ReassignReviewer(ctx context.Context, req *request.ReassignReviewerRequest) (*response.ReassignReviewerResponse, error)
+
+	// GetPRHistory returns the reviewer replacements of a pull request with their reasons, oldest first
+	// Returns error if PR doesn't exist
+	GetPRHistory(ctx context.Context, prID string) (*response.GetPRHistoryResponse, error)
+}
+
+// WebhookService defines business logic for webhook management
+type WebhookService interface {
+	// RegisterWebhook registers an endpoint for the given event types
+	// A signing secret is generated if none is provided; it is returned only here
+	RegisterWebhook(ctx context.Context, req *request.RegisterWebhookRequest) (*response.RegisterWebhookResponse, error)
+
+	// ListWebhooks returns all registered webhooks without their secrets
+	ListWebhooks(ctx context.Context) (*response.ListWebhooksResponse, error)
+
+	// ListDeliveries returns the latest deliveries, optionally filtered by status
+	// Deliveries with status "failed" form the dead-letter list
+	ListDeliveries(ctx context.Context, status string) (*response.ListWebhookDeliveriesResponse, error)
+
+	// RedeliverDelivery schedules a delivery to be sent again with a fresh attempt budget
+	// Returns error if the delivery doesn't exist
+	RedeliverDelivery(ctx context.Context, req *request.RedeliverWebhookRequest) (*response.RedeliverWebhookResponse, error)
+}
+
+// IntegrationService defines business logic for code host integrations
+type IntegrationService interface {
+	// LinkAccount maps a code host login to a user, replacing any previous mapping of the login
+	// Returns error if the provider is unknown or the user doesn't exist
+	LinkAccount(ctx context.Context, req *request.LinkAccountRequest) (*response.LinkAccountResponse, error)
+
+	// ListAccounts returns linked accounts, optionally filtered by provider
+	ListAccounts(ctx context.Context, provider string) (*response.ListAccountsResponse, error)
+
+	// HandlePullRequestEvent applies a code host pull request event through PRService
+	// Opened and reopened PRs are created, merged PRs are merged, closed PRs are ignored
+	// Returns error if the PR author's login is not linked to a user
+	HandlePullRequestEvent(ctx context.Context, event *models.CodeHostPREvent) (*response.PullRequestEventResponse, error)
+}
+
+// ReviewSLAService defines business logic for reviews that stay open longer than the team SLA
+type ReviewSLAService interface {
+	// EnforceReviewSLA applies each team's SLA action to every review that is overdue at now;
+	// the SLA is counted in working time of the team calendar
+	// Returns the number of reviews handled
+	EnforceReviewSLA(ctx context.Context, now time.Time) (int, error)
 }
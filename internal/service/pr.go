@@ -2,25 +2,34 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math/rand"
+	"sort"
+	"strings"
 	"time"
 
+	"avito-backend-trainee-assignment-autumn-2025/internal/codeowners"
 	"avito-backend-trainee-assignment-autumn-2025/internal/domain/models"
-	"avito-backend-trainee-assignment-autumn-2025/internal/dto/request"
-	"avito-backend-trainee-assignment-autumn-2025/internal/dto/response"
 	"avito-backend-trainee-assignment-autumn-2025/internal/repository"
+	"avito-backend-trainee-assignment-autumn-2025/pkg/dto/request"
+	"avito-backend-trainee-assignment-autumn-2025/pkg/dto/response"
 	pkgerrors "avito-backend-trainee-assignment-autumn-2025/pkg/errors"
 	"avito-backend-trainee-assignment-autumn-2025/pkg/logger"
 )
 
 // PRServiceImpl implements PRService
 type PRServiceImpl struct {
-	prRepo    repository.PRRepository
-	userRepo  repository.UserRepository
-	teamRepo  repository.TeamRepository
-	txManager repository.TransactionManager
-	rand      *rand.Rand
+	prRepo       repository.PRRepository
+	userRepo     repository.UserRepository
+	teamRepo     repository.TeamRepository
+	absenceRepo  repository.AbsenceRepository
+	affinityRepo repository.AffinityRepository
+	txManager    repository.TransactionManager
+	outboxRepo   repository.OutboxRepository
+	rand         *rand.Rand
+	// now is the clock used for reviewer selection and timestamps; replaced in tests
+	now func() time.Time
 }
 
 // NewPRService creates a new PR service
@@ -28,16 +37,23 @@ func NewPRService(
 	prRepo repository.PRRepository,
 	userRepo repository.UserRepository,
 	teamRepo repository.TeamRepository,
+	absenceRepo repository.AbsenceRepository,
+	affinityRepo repository.AffinityRepository,
 	txManager repository.TransactionManager,
+	outboxRepo repository.OutboxRepository,
 ) *PRServiceImpl {
 	// Initialize random number generator with current time as seed
 	source := rand.NewSource(time.Now().UnixNano())
 	return &PRServiceImpl{
-		prRepo:    prRepo,
-		userRepo:  userRepo,
-		teamRepo:  teamRepo,
-		txManager: txManager,
-		rand:      rand.New(source),
+		prRepo:       prRepo,
+		userRepo:     userRepo,
+		teamRepo:     teamRepo,
+		absenceRepo:  absenceRepo,
+		affinityRepo: affinityRepo,
+		txManager:    txManager,
+		outboxRepo:   outboxRepo,
+		rand:         rand.New(source),
+		now:          time.Now,
 	}
 }
 
@@ -45,47 +61,33 @@ func NewPRService(
 func (s *PRServiceImpl) CreatePR(ctx context.Context, req *request.CreatePRRequest) (*response.CreatePRResponse, error) {
 	// Validate input
 	if req.PullRequestID == "" {
-		return nil, fmt.Errorf("pull_request_id is required")
+		return nil, pkgerrors.NewRequiredFieldError("pull_request_id")
 	}
 	if req.PullRequestName == "" {
-		return nil, fmt.Errorf("pull_request_name is required")
+		return nil, pkgerrors.NewRequiredFieldError("pull_request_name")
 	}
 	if req.AuthorID == "" {
-		return nil, fmt.Errorf("author_id is required")
+		return nil, pkgerrors.NewRequiredFieldError("author_id")
 	}
-
-	logger.Info("Creating PR: %s (author: %s)", req.PullRequestID, req.AuthorID)
-
-	// Check if author exists and get their team
-	author, err := s.userRepo.GetByID(ctx, req.AuthorID)
-	if err != nil {
-		logger.Error("Failed to get author %s: %v", req.AuthorID, err)
+	if err := validateChangedFiles(req.ChangedFiles); err != nil {
 		return nil, err
 	}
-
-	// Get author's team
-	team, err := s.teamRepo.GetByName(ctx, author.TeamName)
+	requiredTags, err := normalizeTags("required_tags", req.RequiredTags, maxRequiredTags)
 	if err != nil {
-		logger.Error("Failed to get team %s: %v", author.TeamName, err)
-		return nil, fmt.Errorf("failed to get author's team: %w", err)
-	}
-
-	// Get active members excluding the author
-	candidates := team.GetActiveMembersExcept(req.AuthorID)
-	logger.Debug("Found %d active candidates for PR %s", len(candidates), req.PullRequestID)
-
-	// Select up to 2 random reviewers
-	selectedReviewers := s.selectRandomReviewers(candidates, 2)
-	reviewerIDs := make([]string, len(selectedReviewers))
-	for i, reviewer := range selectedReviewers {
-		reviewerIDs[i] = reviewer.ID
+		return nil, err
 	}
 
-	logger.Info("Selected %d reviewers for PR %s: %v", len(reviewerIDs), req.PullRequestID, reviewerIDs)
+	logger.Info("Creating PR: %s (author: %s)", req.PullRequestID, req.AuthorID)
 
-	// Create PR and assign reviewers in a transaction
+	// Pick the reviewers and create the PR in one transaction, so that the open review counts
+	// checked against the reviewers' limits cannot change before the assignments are stored
 	var createdPR *models.PullRequest
 	err = s.txManager.WithTransaction(ctx, func(txCtx context.Context) error {
+		reviewerIDs, err := s.selectReviewers(txCtx, req, requiredTags)
+		if err != nil {
+			return err
+		}
+
 		// Create PR
 		pr := &models.PullRequest{
 			ID:                req.PullRequestID,
@@ -93,7 +95,8 @@ func (s *PRServiceImpl) CreatePR(ctx context.Context, req *request.CreatePRReque
 			AuthorID:          req.AuthorID,
 			Status:            models.PRStatusOpen,
 			AssignedReviewers: []string{},
-			CreatedAt:         time.Now(),
+			RequiredTags:      requiredTags,
+			CreatedAt:         s.now(),
 		}
 
 		if err := s.prRepo.Create(txCtx, pr); err != nil {
@@ -112,6 +115,23 @@ func (s *PRServiceImpl) CreatePR(ctx context.Context, req *request.CreatePRReque
 		// Get the created PR with reviewers
 		pr.AssignedReviewers = reviewerIDs
 		createdPR = pr
+
+		// Record the new PR and its assignments for subscribers
+		err = recordEvent(txCtx, s.outboxRepo, models.EventPRCreated, models.PRCreatedEventData{
+			PullRequestID:     pr.ID,
+			PullRequestName:   pr.Name,
+			AuthorID:          pr.AuthorID,
+			AssignedReviewers: pr.AssignedReviewers,
+			RequiredTags:      pr.RequiredTags,
+		})
+		if err != nil {
+			return err
+		}
+		for _, reviewerID := range reviewerIDs {
+			if err := s.recordReviewerEvent(txCtx, models.EventReviewerAssigned, pr, reviewerID, "", ""); err != nil {
+				return err
+			}
+		}
 		return nil
 	})
 
@@ -119,7 +139,7 @@ func (s *PRServiceImpl) CreatePR(ctx context.Context, req *request.CreatePRReque
 		return nil, err
 	}
 
-	logger.Info("Successfully created PR %s with %d reviewers", req.PullRequestID, len(reviewerIDs))
+	logger.Info("Successfully created PR %s with %d reviewers", req.PullRequestID, len(createdPR.AssignedReviewers))
 
 	// Convert to response DTO
 	return &response.CreatePRResponse{
@@ -127,11 +147,117 @@ func (s *PRServiceImpl) CreatePR(ctx context.Context, req *request.CreatePRReque
 	}, nil
 }
 
+// selectReviewers picks up to 2 reviewers for a new PR among the author's teammates and the code owners
+// of the changed files; call it in the transaction that assigns them, since the capacity check locks
+// the candidates with a review limit
+func (s *PRServiceImpl) selectReviewers(ctx context.Context, req *request.CreatePRRequest, requiredTags []string) ([]string, error) {
+	// Check if author exists and get their team
+	author, err := s.userRepo.GetByID(ctx, req.AuthorID)
+	if err != nil {
+		logger.Error("Failed to get author %s: %v", req.AuthorID, err)
+		return nil, err
+	}
+
+	// Get author's team
+	team, err := s.teamRepo.GetByName(ctx, author.TeamName)
+	if err != nil {
+		logger.Error("Failed to get team %s: %v", author.TeamName, err)
+		return nil, fmt.Errorf("failed to get author's team: %w", err)
+	}
+
+	// The author's affinities exclude conflicts of interest and make preferred reviewers likelier
+	// and the others less likely
+	affinity, err := s.affinityOf(ctx, req.AuthorID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Get available members excluding the author
+	members, err := s.availableMembers(ctx, team)
+	if err != nil {
+		return nil, err
+	}
+	candidates := make([]models.User, 0, len(members))
+	for _, member := range members {
+		if member.ID != req.AuthorID {
+			candidates = append(candidates, member)
+		}
+	}
+	candidates = affinity.allowed(candidates)
+	logger.Debug("Found %d active candidates for PR %s", len(candidates), req.PullRequestID)
+
+	// Take one reviewer among the code owners of the changed files, if any of them can review;
+	// owners at the team's minimum seniority go first
+	owners, err := s.codeOwnerCandidates(ctx, team, req.AuthorID, req.ChangedFiles)
+	if err != nil {
+		return nil, err
+	}
+	owners = affinity.allowed(owners)
+
+	// Lock the limited owners and teammates in one statement: locking them in two batches
+	// could deadlock with a concurrent PR whose owners and teammates overlap the other way round
+	open, err := s.lockOpenReviews(ctx, append(append([]models.User(nil), owners...), candidates...))
+	if err != nil {
+		return nil, err
+	}
+	if owners, err = filterByCapacity(owners, open); errors.Is(err, pkgerrors.ErrAllAtCapacity) {
+		logger.Info("All code owners of the changed files are at capacity for PR %s", req.PullRequestID)
+		owners = nil
+	}
+
+	selectedReviewers := make([]models.User, 0, 2)
+	for _, group := range seniorityTiers(team.MinReviewerSeniority, owners) {
+		for _, tier := range s.rankedTiers(team, requiredTags, group) {
+			selectedReviewers = append(selectedReviewers, s.selectRandomReviewers(tier, 1-len(selectedReviewers), affinity.weights)...)
+		}
+	}
+	if len(selectedReviewers) > 0 {
+		logger.Debug("Code owner %s reviews PR %s", selectedReviewers[0].ID, req.PullRequestID)
+		candidates = withoutUser(candidates, selectedReviewers[0].ID)
+	}
+
+	// Skip those who already review as many open PRs as they may; a chosen code owner is enough to go on
+	candidates, err = filterByCapacity(candidates, open)
+	if errors.Is(err, pkgerrors.ErrAllAtCapacity) && len(selectedReviewers) > 0 {
+		candidates = nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	// Satisfy the team's seniority policy before filling the rest; without anyone senior enough
+	// the PR is still created, since having fewer reviewers is allowed too
+	if level := team.MinReviewerSeniority; !meetsSeniority(level, selectedReviewers) {
+		senior := make([]models.User, 0, 1)
+		for _, tier := range s.rankedTiers(team, requiredTags, atSeniority(level, candidates)) {
+			senior = append(senior, s.selectRandomReviewers(tier, 1-len(senior), affinity.weights)...)
+		}
+		if len(senior) == 0 {
+			logger.Warn("Nobody at seniority %s can review PR %s", level, req.PullRequestID)
+		} else {
+			selectedReviewers = append(selectedReviewers, senior[0])
+			candidates = withoutUser(candidates, senior[0].ID)
+		}
+	}
+
+	// Fill up to 2 reviewers from the team: those with more of the required tags first,
+	// then those inside their working hours, picked at random among equals by the author's weights
+	for _, tier := range s.rankedTiers(team, requiredTags, candidates) {
+		selectedReviewers = append(selectedReviewers, s.selectRandomReviewers(tier, 2-len(selectedReviewers), affinity.weights)...)
+	}
+	reviewerIDs := make([]string, len(selectedReviewers))
+	for i, reviewer := range selectedReviewers {
+		reviewerIDs[i] = reviewer.ID
+	}
+
+	logger.Info("Selected %d reviewers for PR %s: %v", len(reviewerIDs), req.PullRequestID, reviewerIDs)
+	return reviewerIDs, nil
+}
+
 // MergePR merges a pull request (idempotent operation)
 func (s *PRServiceImpl) MergePR(ctx context.Context, req *request.MergePRRequest) (*response.MergePRResponse, error) {
 	// Validate input
 	if req.PullRequestID == "" {
-		return nil, fmt.Errorf("pull_request_id is required")
+		return nil, pkgerrors.NewRequiredFieldError("pull_request_id")
 	}
 
 	logger.Info("Merging PR: %s", req.PullRequestID)
@@ -151,10 +277,26 @@ func (s *PRServiceImpl) MergePR(ctx context.Context, req *request.MergePRRequest
 		}, nil
 	}
 
-	// Merge PR
-	mergedPR, err := s.prRepo.Merge(ctx, req.PullRequestID)
+	// Merge PR and record the event in a transaction
+	var mergedPR *models.PullRequest
+	err = s.txManager.WithTransaction(ctx, func(txCtx context.Context) error {
+		merged, err := s.prRepo.Merge(txCtx, req.PullRequestID)
+		if err != nil {
+			logger.Error("Failed to merge PR %s: %v", req.PullRequestID, err)
+			return err
+		}
+		mergedPR = merged
+
+		return recordEvent(txCtx, s.outboxRepo, models.EventPRMerged, models.PRMergedEventData{
+			PullRequestID:     merged.ID,
+			PullRequestName:   merged.Name,
+			AuthorID:          merged.AuthorID,
+			AssignedReviewers: merged.AssignedReviewers,
+			MergedAt:          merged.MergedAt,
+		})
+	})
+
 	if err != nil {
-		logger.Error("Failed to merge PR %s: %v", req.PullRequestID, err)
 		return nil, err
 	}
 
@@ -170,125 +312,660 @@ func (s *PRServiceImpl) MergePR(ctx context.Context, req *request.MergePRRequest
 func (s *PRServiceImpl) ReassignReviewer(ctx context.Context, req *request.ReassignReviewerRequest) (*response.ReassignReviewerResponse, error) {
 	// Validate input
 	if req.PullRequestID == "" {
-		return nil, fmt.Errorf("pull_request_id is required")
+		return nil, pkgerrors.NewRequiredFieldError("pull_request_id")
 	}
 	if req.OldUserID == "" {
-		return nil, fmt.Errorf("old_user_id is required")
+		return nil, pkgerrors.NewRequiredFieldError("old_user_id")
 	}
 
 	logger.Info("Reassigning reviewer for PR %s: replacing %s", req.PullRequestID, req.OldUserID)
 
-	// Get PR
-	pr, err := s.prRepo.GetByID(ctx, req.PullRequestID)
+	updatedPR, newReviewerID, err := s.reassign(ctx, req.PullRequestID, req.OldUserID, models.ReassignReasonManual, s.pickRandomCandidate)
 	if err != nil {
-		logger.Error("Failed to get PR %s: %v", req.PullRequestID, err)
 		return nil, err
 	}
 
-	// Check if PR is merged
-	if pr.IsMerged() {
-		logger.Warn("Cannot reassign reviewer for merged PR %s", req.PullRequestID)
-		return nil, pkgerrors.ErrPRMerged
+	// Convert to response DTO
+	return &response.ReassignReviewerResponse{
+		PR:         convertPRToResponse(updatedPR),
+		ReplacedBy: newReviewerID,
+	}, nil
+}
+
+// GetPRHistory returns the reviewer replacements of a pull request, oldest first
+func (s *PRServiceImpl) GetPRHistory(ctx context.Context, prID string) (*response.GetPRHistoryResponse, error) {
+	// Validate input
+	if prID == "" {
+		return nil, pkgerrors.NewRequiredFieldError("pull_request_id")
 	}
 
-	// Check if old_user_id is assigned as reviewer
-	if !pr.IsReviewerAssigned(req.OldUserID) {
-		logger.Warn("User %s is not assigned as reviewer for PR %s", req.OldUserID, req.PullRequestID)
-		return nil, pkgerrors.ErrReviewerNotAssigned
+	// Check that the PR exists: an unknown PR is not the same as a PR without history
+	if _, err := s.prRepo.GetByID(ctx, prID); err != nil {
+		logger.Error("Failed to get PR %s: %v", prID, err)
+		return nil, err
 	}
 
-	// Get the team of the user being replaced
-	oldUser, err := s.userRepo.GetByID(ctx, req.OldUserID)
+	reassignments, err := s.prRepo.ListReassignments(ctx, prID)
 	if err != nil {
-		logger.Error("Failed to get user %s: %v", req.OldUserID, err)
+		logger.Error("Failed to get history of PR %s: %v", prID, err)
 		return nil, err
 	}
 
-	team, err := s.teamRepo.GetByName(ctx, oldUser.TeamName)
+	history := make([]response.ReassignmentResponse, 0, len(reassignments))
+	for _, reassignment := range reassignments {
+		history = append(history, response.ReassignmentResponse{
+			OldReviewerID: reassignment.OldReviewerID,
+			NewReviewerID: reassignment.NewReviewerID,
+			Reason:        string(reassignment.Reason),
+			CreatedAt:     reassignment.CreatedAt,
+		})
+	}
+
+	return &response.GetPRHistoryResponse{
+		PullRequestID: prID,
+		Reassignments: history,
+	}, nil
+}
+
+// availableMembers returns the active members of the team who are not absent today
+// Today is taken in the team's timezone
+func (s *PRServiceImpl) availableMembers(ctx context.Context, team *models.Team) ([]models.User, error) {
+	today := models.DateIn(s.now(), team.Calendar.Location())
+	absences, err := s.absenceRepo.ListByTeam(ctx, team.Name, today)
 	if err != nil {
-		logger.Error("Failed to get team %s: %v", oldUser.TeamName, err)
-		return nil, fmt.Errorf("failed to get team: %w", err)
+		logger.Error("Failed to get absences of team %s: %v", team.Name, err)
+		return nil, fmt.Errorf("failed to get team absences: %w", err)
 	}
 
-	// Get active candidates excluding the old reviewer, the PR author, and other current reviewers
-	candidates := []models.User{}
+	absent := make(map[string]bool)
+	for _, absence := range absences {
+		if absence.Covers(today) {
+			absent[absence.UserID] = true
+		}
+	}
+
+	available := make([]models.User, 0, len(team.Members))
 	for _, member := range team.GetActiveMembers() {
-		// Skip if member is the old reviewer or the PR author
-		if member.ID == req.OldUserID || member.ID == pr.AuthorID {
+		if absent[member.ID] {
+			logger.Debug("Skipping %s: absent on %s", member.ID, today.Format(models.DateLayout))
 			continue
 		}
+		available = append(available, member)
+	}
+	return available, nil
+}
 
-		// Skip if member is already assigned as a reviewer (excluding the one being replaced)
-		isCurrentlyAssigned := false
-		for _, assignedReviewerID := range pr.AssignedReviewers {
-			if member.ID == assignedReviewerID {
-				isCurrentlyAssigned = true
-				break
+// withinCapacity drops the candidates who already review as many open PRs as their limit allows
+// ErrAllAtCapacity means nobody is left. Call it in the transaction that assigns the reviewers,
+// see lockOpenReviews
+func (s *PRServiceImpl) withinCapacity(ctx context.Context, candidates []models.User) ([]models.User, error) {
+	open, err := s.lockOpenReviews(ctx, candidates)
+	if err != nil {
+		return nil, err
+	}
+	return filterByCapacity(candidates, open)
+}
+
+// lockOpenReviews locks the candidates who have a review limit and counts their open reviews in one query
+// Call it in the transaction that assigns the reviewers: a concurrent assignment cannot take the last
+// free slot of a locked candidate until that transaction ends. All candidates a transaction may pick
+// must be locked in a single call, since the rows are only locked in a consistent order within one call
+func (s *PRServiceImpl) lockOpenReviews(ctx context.Context, candidates []models.User) (map[string]int, error) {
+	limited := make([]string, 0, len(candidates))
+	seen := make(map[string]bool, len(candidates))
+	for _, candidate := range candidates {
+		if candidate.MaxOpenReviews > 0 && !seen[candidate.ID] {
+			limited = append(limited, candidate.ID)
+			seen[candidate.ID] = true
+		}
+	}
+	if len(limited) == 0 {
+		return map[string]int{}, nil
+	}
+
+	if err := s.userRepo.LockForUpdate(ctx, limited); err != nil {
+		return nil, err
+	}
+	open, err := s.prRepo.CountOpenReviews(ctx, limited)
+	if err != nil {
+		logger.Error("Failed to count open reviews of %d candidates: %v", len(limited), err)
+		return nil, fmt.Errorf("failed to count open reviews: %w", err)
+	}
+	return open, nil
+}
+
+// filterByCapacity drops the candidates whose open reviews, as counted by lockOpenReviews, reach their limit
+// ErrAllAtCapacity means nobody is left
+func filterByCapacity(candidates []models.User, open map[string]int) ([]models.User, error) {
+	if len(candidates) == 0 {
+		return candidates, nil
+	}
+
+	remaining := make([]models.User, 0, len(candidates))
+	for _, candidate := range candidates {
+		if !candidate.HasCapacity(open[candidate.ID]) {
+			logger.Debug("Skipping %s: %d open reviews of %d", candidate.ID, open[candidate.ID], candidate.MaxOpenReviews)
+			continue
+		}
+		remaining = append(remaining, candidate)
+	}
+
+	if len(remaining) == 0 {
+		logger.Warn("All %d candidates are at their open review limit", len(candidates))
+		return nil, pkgerrors.ErrAllAtCapacity
+	}
+	return remaining, nil
+}
+
+// codeOwnerCandidates returns the code owners of changedFiles who may review a PR of authorID
+// Rules come from the CODEOWNERS file of the author's team; owners may belong to other teams and
+// are checked against their own team's absences. Their review limits are left to the caller
+func (s *PRServiceImpl) codeOwnerCandidates(
+	ctx context.Context,
+	team *models.Team,
+	authorID string,
+	changedFiles []string,
+) ([]models.User, error) {
+	if len(changedFiles) == 0 {
+		return nil, nil
+	}
+
+	rules, err := s.teamRepo.ListCodeOwners(ctx, team.Name)
+	if err != nil {
+		logger.Error("Failed to get code owners of team %s: %v", team.Name, err)
+		return nil, fmt.Errorf("failed to get code owners: %w", err)
+	}
+	if len(rules) == 0 {
+		return nil, nil
+	}
+	matcher, err := codeowners.NewMatcher(rules)
+	if err != nil {
+		logger.Error("Stored code owners of team %s are invalid: %v", team.Name, err)
+		return nil, fmt.Errorf("invalid code owners: %w", err)
+	}
+
+	ownerUsers := make(map[string]bool)
+	ownerTeams := make(map[string]bool)
+	for _, file := range changedFiles {
+		for _, owner := range matcher.Owners(file) {
+			if owner.TeamName != "" {
+				ownerTeams[owner.TeamName] = true
+			} else {
+				ownerUsers[owner.UserID] = true
 			}
 		}
+	}
 
-		if !isCurrentlyAssigned {
-			candidates = append(candidates, member)
+	// Collect the teams to look owners up in
+	teamNames := make([]string, 0, len(ownerTeams)+len(ownerUsers))
+	seen := make(map[string]bool)
+	for name := range ownerTeams {
+		teamNames = append(teamNames, name)
+		seen[name] = true
+	}
+	for userID := range ownerUsers {
+		user, err := s.userRepo.GetByID(ctx, userID)
+		if errors.Is(err, pkgerrors.ErrUserNotFound) {
+			logger.Warn("Code owner %s of team %s does not exist", userID, team.Name)
+			continue
+		}
+		if err != nil {
+			return nil, err
 		}
+		if !seen[user.TeamName] {
+			teamNames = append(teamNames, user.TeamName)
+			seen[user.TeamName] = true
+		}
+	}
+	sort.Strings(teamNames)
+
+	owners := make([]models.User, 0)
+	for _, name := range teamNames {
+		ownerTeam := team
+		if name != team.Name {
+			if ownerTeam, err = s.teamRepo.GetByName(ctx, name); errors.Is(err, pkgerrors.ErrTeamNotFound) {
+				logger.Warn("Code owner team %s of team %s does not exist", name, team.Name)
+				continue
+			} else if err != nil {
+				return nil, fmt.Errorf("failed to get code owner team: %w", err)
+			}
+		}
+		members, err := s.availableMembers(ctx, ownerTeam)
+		if err != nil {
+			return nil, err
+		}
+		for _, member := range members {
+			if member.ID != authorID && (ownerTeams[name] || ownerUsers[member.ID]) {
+				owners = append(owners, member)
+			}
+		}
+	}
+	if len(owners) == 0 {
+		logger.Info("No code owner of the changed files can review a PR of %s", authorID)
 	}
+	return owners, nil
+}
 
-	logger.Debug("Found %d candidates for reassignment in team %s", len(candidates), team.Name)
+// maxChangedFiles caps the number of paths a PR may list
+const maxChangedFiles = 3000
 
-	// Check if there are any candidates
-	if len(candidates) == 0 {
-		logger.Warn("No candidates available for reassignment in team %s", team.Name)
-		return nil, pkgerrors.ErrNoCandidates
+// validateChangedFiles accepts up to maxChangedFiles non-empty paths
+func validateChangedFiles(files []string) error {
+	if len(files) > maxChangedFiles {
+		return pkgerrors.NewValidationError("changed_files", fmt.Sprintf("must list at most %d paths", maxChangedFiles))
 	}
+	for _, file := range files {
+		if strings.TrimSpace(file) == "" {
+			return pkgerrors.NewValidationError("changed_files", "must not contain empty paths")
+		}
+	}
+	return nil
+}
 
-	// Select random candidate
-	selectedReviewers := s.selectRandomReviewers(candidates, 1)
-	if len(selectedReviewers) == 0 {
-		logger.Warn("Failed to select reviewer from %d candidates", len(candidates))
-		return nil, pkgerrors.ErrNoCandidates
+// withoutUser returns users except the one with the given ID
+func withoutUser(users []models.User, userID string) []models.User {
+	remaining := make([]models.User, 0, len(users))
+	for _, user := range users {
+		if user.ID != userID {
+			remaining = append(remaining, user)
+		}
 	}
+	return remaining
+}
 
-	newReviewerID := selectedReviewers[0].ID
-	logger.Info("Selected new reviewer %s to replace %s for PR %s", newReviewerID, req.OldUserID, req.PullRequestID)
+// rankedTiers splits candidates into groups tried in order when picking reviewers: by how many of
+// requiredTags they have, most first, and within that by the team's working hours preference
+func (s *PRServiceImpl) rankedTiers(team *models.Team, requiredTags []string, candidates []models.User) [][]models.User {
+	tiers := make([][]models.User, 0, len(requiredTags)+2)
+	for _, group := range tagTiers(requiredTags, candidates) {
+		tiers = append(tiers, s.preferenceTiers(team, group)...)
+	}
+	return tiers
+}
+
+// tagTiers groups candidates by how many of requiredTags they have, most first; empty groups are kept
+func tagTiers(requiredTags []string, candidates []models.User) [][]models.User {
+	if len(requiredTags) == 0 {
+		return [][]models.User{candidates}
+	}
+
+	groups := make([][]models.User, len(requiredTags)+1)
+	for _, candidate := range candidates {
+		missing := len(requiredTags) - candidate.TagOverlap(requiredTags)
+		groups[missing] = append(groups[missing], candidate)
+	}
+	return groups
+}
+
+// preferenceTiers splits candidates into groups tried in order when picking reviewers
+// With the team's working hours preference on, those who work now or start within PreferWithinHours come first
+func (s *PRServiceImpl) preferenceTiers(team *models.Team, candidates []models.User) [][]models.User {
+	if !team.PreferWorkingHours {
+		return [][]models.User{candidates}
+	}
+
+	now := s.now()
+	within := time.Duration(team.PreferWithinHours) * time.Hour
+	preferred := make([]models.User, 0, len(candidates))
+	others := make([]models.User, 0, len(candidates))
+	for _, candidate := range candidates {
+		if candidate.WorkingWithin(now, within, team.Calendar) {
+			preferred = append(preferred, candidate)
+		} else {
+			others = append(others, candidate)
+		}
+	}
+
+	logger.Debug("%d of %d candidates in team %s are inside their working hours", len(preferred), len(candidates), team.Name)
+	return [][]models.User{preferred, others}
+}
+
+// seniorityTiers puts candidates at level or above before the rest; without a level all of them form one group
+func seniorityTiers(level models.Seniority, candidates []models.User) [][]models.User {
+	if level == "" {
+		return [][]models.User{candidates}
+	}
+
+	senior := make([]models.User, 0, len(candidates))
+	others := make([]models.User, 0, len(candidates))
+	for _, candidate := range candidates {
+		if candidate.AtLeast(level) {
+			senior = append(senior, candidate)
+		} else {
+			others = append(others, candidate)
+		}
+	}
+	return [][]models.User{senior, others}
+}
+
+// atSeniority returns the users at level or above
+func atSeniority(level models.Seniority, users []models.User) []models.User {
+	return seniorityTiers(level, users)[0]
+}
+
+// meetsSeniority reports whether one of reviewers is at level or above; an empty level is always met
+func meetsSeniority(level models.Seniority, reviewers []models.User) bool {
+	for _, reviewer := range reviewers {
+		if reviewer.AtLeast(level) {
+			return true
+		}
+	}
+	return level == ""
+}
+
+// seniorityPolicy returns the minimum reviewer seniority of the PR author's team
+// team is reused when the author belongs to it, which is the usual case
+func (s *PRServiceImpl) seniorityPolicy(ctx context.Context, pr *models.PullRequest, team *models.Team) (models.Seniority, error) {
+	author, err := s.userRepo.GetByID(ctx, pr.AuthorID)
+	if err != nil {
+		logger.Error("Failed to get author %s of PR %s: %v", pr.AuthorID, pr.ID, err)
+		return "", err
+	}
+	if author.TeamName == team.Name {
+		return team.MinReviewerSeniority, nil
+	}
+
+	authorTeam, err := s.teamRepo.GetByName(ctx, author.TeamName)
+	if err != nil {
+		logger.Error("Failed to get team %s: %v", author.TeamName, err)
+		return "", fmt.Errorf("failed to get author's team: %w", err)
+	}
+	return authorTeam.MinReviewerSeniority, nil
+}
+
+// hasReviewerAtSeniority reports whether a reviewer among reviewerIDs, other than exceptID, is at level or above
+func (s *PRServiceImpl) hasReviewerAtSeniority(ctx context.Context, level models.Seniority, reviewerIDs []string, exceptID string) (bool, error) {
+	for _, reviewerID := range reviewerIDs {
+		if reviewerID == exceptID {
+			continue
+		}
+		reviewer, err := s.userRepo.GetByID(ctx, reviewerID)
+		if errors.Is(err, pkgerrors.ErrUserNotFound) {
+			continue
+		}
+		if err != nil {
+			logger.Error("Failed to get reviewer %s: %v", reviewerID, err)
+			return false, err
+		}
+		if reviewer.AtLeast(level) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// reviewerAffinity is what the affinity table says about the reviewers of one author
+type reviewerAffinity struct {
+	// conflicts are the users in a conflict of interest with the author, whichever side of the pair set it
+	conflicts map[string]bool
+	// weights are the author's non-zero weights by reviewer; a negative one makes the reviewer less likely
+	weights map[string]int
+}
+
+// affinityOf loads the affinities of the PR author
+func (s *PRServiceImpl) affinityOf(ctx context.Context, authorID string) (reviewerAffinity, error) {
+	affinities, err := s.affinityRepo.ListByUser(ctx, authorID)
+	if err != nil {
+		logger.Error("Failed to list affinities of user %s: %v", authorID, err)
+		return reviewerAffinity{}, err
+	}
+
+	affinity := reviewerAffinity{conflicts: make(map[string]bool), weights: make(map[string]int)}
+	for _, record := range affinities {
+		switch {
+		case record.IsConflict() && record.AuthorID == authorID:
+			affinity.conflicts[record.ReviewerID] = true
+		case record.IsConflict():
+			affinity.conflicts[record.AuthorID] = true
+		case record.AuthorID == authorID && record.Weight != 0:
+			affinity.weights[record.ReviewerID] = record.Weight
+		}
+	}
+	return affinity, nil
+}
+
+// allowed returns the users who are not in a conflict of interest with the author
+func (a reviewerAffinity) allowed(users []models.User) []models.User {
+	if len(a.conflicts) == 0 {
+		return users
+	}
+	remaining := make([]models.User, 0, len(users))
+	for _, user := range users {
+		if !a.conflicts[user.ID] {
+			remaining = append(remaining, user)
+		}
+	}
+	return remaining
+}
+
+// candidatePicker chooses the new reviewer among the active teammates who may take over the review
+// weights are the PR author's affinity weights by reviewer, see models.SelectionWeight
+type candidatePicker func(candidates []models.User, weights map[string]int) (string, error)
+
+// pickRandomCandidate picks a random candidate, preferred ones being likelier
+func (s *PRServiceImpl) pickRandomCandidate(candidates []models.User, weights map[string]int) (string, error) {
+	selected := s.selectRandomReviewers(candidates, 1, weights)
+	if len(selected) == 0 {
+		return "", pkgerrors.ErrNoCandidates
+	}
+	return selected[0].ID, nil
+}
+
+// pickLead returns a picker that takes only the given user, if they may take over the review
+func pickLead(leadID string) candidatePicker {
+	return func(candidates []models.User, _ map[string]int) (string, error) {
+		for _, candidate := range candidates {
+			if candidate.ID == leadID {
+				return leadID, nil
+			}
+		}
+		return "", pkgerrors.ErrNoCandidates
+	}
+}
+
+// reassign replaces oldReviewerID on an open PR with a member of their team chosen by pick,
+// records the replacement with its reason in the PR history and returns the updated PR
+func (s *PRServiceImpl) reassign(
+	ctx context.Context,
+	prID, oldReviewerID string,
+	reason models.ReassignReason,
+	pick candidatePicker,
+) (*models.PullRequest, string, error) {
+	// Pick the new reviewer and replace the old one in one transaction, so that neither the PR
+	// nor the open review counts checked against the candidates' limits change in between
+	var newReviewerID string
+	err := s.txManager.WithTransaction(ctx, func(txCtx context.Context) error {
+		// Get PR
+		pr, err := s.prRepo.GetByID(txCtx, prID)
+		if err != nil {
+			logger.Error("Failed to get PR %s: %v", prID, err)
+			return err
+		}
+
+		// Check if PR is merged
+		if pr.IsMerged() {
+			logger.Warn("Cannot reassign reviewer for merged PR %s", prID)
+			return pkgerrors.ErrPRMerged
+		}
+
+		// Check if old reviewer is assigned
+		if !pr.IsReviewerAssigned(oldReviewerID) {
+			logger.Warn("User %s is not assigned as reviewer for PR %s", oldReviewerID, prID)
+			return pkgerrors.ErrReviewerNotAssigned
+		}
+
+		newReviewerID, err = s.pickReplacement(txCtx, pr, oldReviewerID, pick)
+		if err != nil {
+			return err
+		}
+
+		logger.Info("Selected new reviewer %s to replace %s for PR %s (reason: %s)", newReviewerID, oldReviewerID, prID, reason)
 
-	// Replace reviewer in a transaction
-	err = s.txManager.WithTransaction(ctx, func(txCtx context.Context) error {
 		// Remove old reviewer
-		if err := s.prRepo.RemoveReviewer(txCtx, req.PullRequestID, req.OldUserID); err != nil {
-			logger.Error("Failed to remove reviewer %s from PR %s: %v", req.OldUserID, req.PullRequestID, err)
+		if err := s.prRepo.RemoveReviewer(txCtx, prID, oldReviewerID); err != nil {
+			logger.Error("Failed to remove reviewer %s from PR %s: %v", oldReviewerID, prID, err)
 			return fmt.Errorf("failed to remove old reviewer: %w", err)
 		}
 
 		// Add new reviewer
-		if err := s.prRepo.AddReviewer(txCtx, req.PullRequestID, newReviewerID); err != nil {
-			logger.Error("Failed to add reviewer %s to PR %s: %v", newReviewerID, req.PullRequestID, err)
+		if err := s.prRepo.AddReviewer(txCtx, prID, newReviewerID); err != nil {
+			logger.Error("Failed to add reviewer %s to PR %s: %v", newReviewerID, prID, err)
 			return fmt.Errorf("failed to add new reviewer: %w", err)
 		}
 
-		return nil
+		// Keep the history of replacements
+		if err := s.prRepo.AddReassignment(txCtx, &models.Reassignment{
+			ID:            models.NewID("rsg"),
+			PullRequestID: prID,
+			OldReviewerID: oldReviewerID,
+			NewReviewerID: newReviewerID,
+			Reason:        reason,
+			CreatedAt:     s.now(),
+		}); err != nil {
+			return err
+		}
+
+		// Record the replacement for subscribers
+		if err := s.recordReviewerEvent(txCtx, models.EventReviewerUnassigned, pr, oldReviewerID, "", ""); err != nil {
+			return err
+		}
+		return s.recordReviewerEvent(txCtx, models.EventReviewerAssigned, pr, newReviewerID, oldReviewerID, reason)
 	})
 
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 
 	// Get updated PR
-	updatedPR, err := s.prRepo.GetByID(ctx, req.PullRequestID)
+	updatedPR, err := s.prRepo.GetByID(ctx, prID)
 	if err != nil {
-		logger.Error("Failed to get updated PR %s: %v", req.PullRequestID, err)
-		return nil, fmt.Errorf("failed to get updated PR: %w", err)
+		logger.Error("Failed to get updated PR %s: %v", prID, err)
+		return nil, "", fmt.Errorf("failed to get updated PR: %w", err)
 	}
 
-	logger.Info("Successfully reassigned reviewer for PR %s: %s -> %s", req.PullRequestID, req.OldUserID, newReviewerID)
+	logger.Info("Successfully reassigned reviewer for PR %s: %s -> %s", prID, oldReviewerID, newReviewerID)
+	return updatedPR, newReviewerID, nil
+}
 
-	// Convert to response DTO
-	return &response.ReassignReviewerResponse{
-		PR:         convertPRToResponse(updatedPR),
-		ReplacedBy: newReviewerID,
-	}, nil
+// pickReplacement chooses who takes over the review of oldReviewerID on pr among the members of their team
+// Call it in the transaction that stores the replacement, since the capacity check locks the candidates
+func (s *PRServiceImpl) pickReplacement(
+	ctx context.Context,
+	pr *models.PullRequest,
+	oldReviewerID string,
+	pick candidatePicker,
+) (string, error) {
+	// Get the team of the user being replaced
+	oldUser, err := s.userRepo.GetByID(ctx, oldReviewerID)
+	if err != nil {
+		logger.Error("Failed to get user %s: %v", oldReviewerID, err)
+		return "", err
+	}
+
+	team, err := s.teamRepo.GetByName(ctx, oldUser.TeamName)
+	if err != nil {
+		logger.Error("Failed to get team %s: %v", oldUser.TeamName, err)
+		return "", fmt.Errorf("failed to get team: %w", err)
+	}
+
+	// Get available candidates excluding the old reviewer, the PR author, other current reviewers
+	// and those in a conflict of interest with the author
+	members, err := s.availableMembers(ctx, team)
+	if err != nil {
+		return "", err
+	}
+	affinity, err := s.affinityOf(ctx, pr.AuthorID)
+	if err != nil {
+		return "", err
+	}
+	candidates := []models.User{}
+	for _, member := range affinity.allowed(members) {
+		if member.ID == oldReviewerID || member.ID == pr.AuthorID || pr.IsReviewerAssigned(member.ID) {
+			continue
+		}
+		candidates = append(candidates, member)
+	}
+
+	logger.Debug("Found %d candidates for reassignment in team %s", len(candidates), team.Name)
+
+	if len(candidates) == 0 {
+		logger.Warn("No candidates available for reassignment in team %s", team.Name)
+		return "", pkgerrors.ErrNoCandidates
+	}
+	candidates, err = s.withinCapacity(ctx, candidates)
+	if err != nil {
+		return "", err
+	}
+
+	// Keep the seniority policy of the author's team: when the replaced reviewer was the only one at the
+	// required level, only candidates at that level may take over; otherwise they are merely tried first
+	groups := [][]models.User{candidates}
+	seniorRequired := false
+	level, err := s.seniorityPolicy(ctx, pr, team)
+	if err != nil {
+		return "", err
+	}
+	if level != "" {
+		kept, err := s.hasReviewerAtSeniority(ctx, level, pr.AssignedReviewers, oldReviewerID)
+		if err != nil {
+			return "", err
+		}
+		if !kept {
+			groups = seniorityTiers(level, candidates)
+			if seniorRequired = oldUser.AtLeast(level); seniorRequired {
+				groups = groups[:1]
+			}
+		}
+	}
+
+	// Within that, candidates with more of the PR's required tags, then those inside their working hours,
+	// are tried first; the picker falls through to the rest and weighs each tier by the author's affinities
+	tiers := make([][]models.User, 0)
+	for _, group := range groups {
+		tiers = append(tiers, s.rankedTiers(team, pr.RequiredTags, group)...)
+	}
+	var newReviewerID string
+	err = pkgerrors.ErrNoCandidates
+	for _, tier := range tiers {
+		newReviewerID, err = pick(tier, affinity.weights)
+		if !errors.Is(err, pkgerrors.ErrNoCandidates) {
+			break
+		}
+	}
+	if errors.Is(err, pkgerrors.ErrNoCandidates) && seniorRequired {
+		logger.Warn("Nobody at seniority %s can replace %s on PR %s", level, oldReviewerID, pr.ID)
+		err = pkgerrors.ErrSeniorityRequired
+	}
+	if err != nil {
+		logger.Warn("No suitable reviewer among %d candidates for PR %s: %v", len(candidates), pr.ID, err)
+		return "", err
+	}
+
+	return newReviewerID, nil
+}
+
+// recordReviewerEvent records a reviewer.assigned or reviewer.unassigned event in the outbox
+// replacedID is the reviewer replaced by reviewerID on reassignment and reason is why; both are empty otherwise
+func (s *PRServiceImpl) recordReviewerEvent(
+	ctx context.Context,
+	eventType models.EventType,
+	pr *models.PullRequest,
+	reviewerID, replacedID string,
+	reason models.ReassignReason,
+) error {
+	return recordEvent(ctx, s.outboxRepo, eventType, models.ReviewerEventData{
+		PullRequestID:      pr.ID,
+		PullRequestName:    pr.Name,
+		AuthorID:           pr.AuthorID,
+		ReviewerID:         reviewerID,
+		ReplacedReviewerID: replacedID,
+		Reason:             reason,
+	})
 }
 
 // selectRandomReviewers selects up to maxCount random reviewers from candidates
-func (s *PRServiceImpl) selectRandomReviewers(candidates []models.User, maxCount int) []models.User {
+// A candidate with weight w in weights is 1+w times as likely to be drawn as one without a weight
+func (s *PRServiceImpl) selectRandomReviewers(candidates []models.User, maxCount int, weights map[string]int) []models.User {
 	if len(candidates) == 0 {
 		return []models.User{}
 	}
@@ -303,11 +980,19 @@ func (s *PRServiceImpl) selectRandomReviewers(candidates []models.User, maxCount
 	available := make([]models.User, len(candidates))
 	copy(available, candidates)
 
-	// Shuffle and select first 'count' elements (Fisher-Yates shuffle)
+	// Shuffle and select first 'count' elements (Fisher-Yates shuffle, weighted)
 	selected := make([]models.User, count)
 	for i := 0; i < count; i++ {
-		// Pick random index from remaining elements
-		j := i + s.rand.Intn(len(available)-i)
+		// Pick random index from remaining elements in proportion to their weights
+		total := 0
+		for _, candidate := range available[i:] {
+			total += models.SelectionWeight(weights[candidate.ID])
+		}
+		j, r := i, s.rand.Intn(total)
+		for r >= models.SelectionWeight(weights[available[j].ID]) {
+			r -= models.SelectionWeight(weights[available[j].ID])
+			j++
+		}
 		// Swap
 		available[i], available[j] = available[j], available[i]
 		// Add to selected
@@ -325,6 +1010,7 @@ func convertPRToResponse(pr *models.PullRequest) response.PullRequestResponse {
 		AuthorID:          pr.AuthorID,
 		Status:            string(pr.Status),
 		AssignedReviewers: pr.AssignedReviewers,
+		RequiredTags:      pr.RequiredTags,
 		CreatedAt:         &pr.CreatedAt,
 		MergedAt:          pr.MergedAt,
 	}
@@ -0,0 +1,131 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"avito-backend-trainee-assignment-autumn-2025/internal/migrator"
+	"avito-backend-trainee-assignment-autumn-2025/internal/repository"
+	"avito-backend-trainee-assignment-autumn-2025/internal/repository/postgres"
+	"avito-backend-trainee-assignment-autumn-2025/pkg/dto/request"
+)
+
+// newPostgresServices migrates a fresh schema in the database at TEST_POSTGRES_URL and returns
+// services on top of it; the schema is dropped when the test ends. Without the variable the test is skipped
+func newPostgresServices(t *testing.T) (*TeamServiceImpl, *PRServiceImpl) {
+	t.Helper()
+	url := os.Getenv("TEST_POSTGRES_URL")
+	if url == "" {
+		t.Skip("TEST_POSTGRES_URL is not set")
+	}
+	ctx := context.Background()
+
+	schema := fmt.Sprintf("test_%d", time.Now().UnixNano())
+	admin, err := pgxpool.New(ctx, url)
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	t.Cleanup(admin.Close)
+	if _, err := admin.Exec(ctx, "CREATE SCHEMA "+schema); err != nil {
+		t.Fatalf("create schema: %v", err)
+	}
+	t.Cleanup(func() {
+		if _, err := admin.Exec(context.Background(), "DROP SCHEMA "+schema+" CASCADE"); err != nil {
+			t.Errorf("drop schema: %v", err)
+		}
+	})
+
+	config, err := pgxpool.ParseConfig(url)
+	if err != nil {
+		t.Fatalf("parse config: %v", err)
+	}
+	config.ConnConfig.RuntimeParams["search_path"] = schema
+	pool, err := pgxpool.NewWithConfig(ctx, config)
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	t.Cleanup(pool.Close)
+
+	m, err := migrator.New(pool)
+	if err != nil {
+		t.Fatalf("migrator: %v", err)
+	}
+	defer m.Close()
+	if _, err := m.Up(ctx); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+
+	teamRepo := postgres.NewTeamRepository(pool)
+	userRepo := postgres.NewUserRepository(pool)
+	absenceRepo := postgres.NewAbsenceRepository(pool)
+	outboxRepo := postgres.NewOutboxRepository(pool)
+	txManager := repository.NewPgxTransactionManager(pool)
+
+	teams := NewTeamService(teamRepo, userRepo, absenceRepo, txManager, outboxRepo)
+	prs := NewPRService(postgres.NewPRRepository(pool), userRepo, teamRepo, absenceRepo,
+		postgres.NewAffinityRepository(pool), txManager, outboxRepo)
+	return teams, prs
+}
+
+func TestConcurrentCreatesWithOverlappingOwnersOnPostgres(t *testing.T) {
+	teams, prs := newPostgresServices(t)
+	ctx := context.Background()
+
+	// Each team's only reviewer owns the other team's code, so a PR of one team and a PR of
+	// the other lock the same two limited reviewers, once as code owner and once as teammate
+	limited := func(id string) request.TeamMemberRequest {
+		member := active(id)
+		member.MaxOpenReviews = 100
+		return member
+	}
+	owners := map[string]string{"backend": "u2", "frontend": "u1"}
+	for name, members := range map[string][]request.TeamMemberRequest{
+		"backend":  {active("author-b"), limited("u1")},
+		"frontend": {active("author-f"), limited("u2")},
+	} {
+		if _, err := teams.CreateTeam(ctx, &request.CreateTeamRequest{TeamName: name, Members: members}); err != nil {
+			t.Fatalf("CreateTeam(%s): %v", name, err)
+		}
+	}
+	for name, owner := range owners {
+		if _, err := teams.UploadCodeOwners(ctx, name, false, []byte("* @"+owner+"\n")); err != nil {
+			t.Fatalf("UploadCodeOwners(%s): %v", name, err)
+		}
+	}
+
+	const rounds = 25
+	errs := make(chan error, 2*rounds)
+	var wg sync.WaitGroup
+	for i := 0; i < rounds; i++ {
+		for _, author := range []string{"author-b", "author-f"} {
+			wg.Add(1)
+			go func(id, author string) {
+				defer wg.Done()
+				resp, err := prs.CreatePR(ctx, &request.CreatePRRequest{
+					PullRequestID:   id,
+					PullRequestName: "PR " + id,
+					AuthorID:        author,
+					ChangedFiles:    []string{"main.go"},
+				})
+				if err == nil && len(resp.PR.AssignedReviewers) != 2 {
+					err = fmt.Errorf("%s: reviewers = %v, want the code owner and the teammate", id, resp.PR.AssignedReviewers)
+				}
+				errs <- err
+			}(fmt.Sprintf("pr-%s-%d", author, i), author)
+		}
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Errorf("CreatePR: %v", err)
+		}
+	}
+}
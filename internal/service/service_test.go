@@ -0,0 +1,1861 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"avito-backend-trainee-assignment-autumn-2025/internal/domain/models"
+	"avito-backend-trainee-assignment-autumn-2025/internal/repository"
+	"avito-backend-trainee-assignment-autumn-2025/internal/repository/memory"
+	"avito-backend-trainee-assignment-autumn-2025/pkg/dto/request"
+	pkgerrors "avito-backend-trainee-assignment-autumn-2025/pkg/errors"
+)
+
+type services struct {
+	teams        *TeamServiceImpl
+	users        *UserServiceImpl
+	prs          *PRServiceImpl
+	integrations *IntegrationServiceImpl
+	sla          *ReviewSLAServiceImpl
+	outbox       *memory.OutboxRepository
+	clock        *testClock
+}
+
+// testClock is the store clock; it starts on a Monday morning and moves only when advanced
+type testClock struct {
+	at time.Time
+}
+
+func (c *testClock) now() time.Time {
+	return c.at
+}
+
+func (c *testClock) advance(d time.Duration) time.Time {
+	c.at = c.at.Add(d)
+	return c.at
+}
+
+func newServices() services {
+	clock := &testClock{at: time.Date(2025, 11, 3, 9, 0, 0, 0, time.UTC)}
+	store := memory.NewStoreWithClock(clock.now)
+	teamRepo := memory.NewTeamRepository(store)
+	userRepo := memory.NewUserRepository(store)
+	prRepo := memory.NewPRRepository(store)
+	outboxRepo := memory.NewOutboxRepository(store)
+	accountRepo := memory.NewCodeHostAccountRepository(store)
+	absenceRepo := memory.NewAbsenceRepository(store)
+	affinityRepo := memory.NewAffinityRepository(store)
+	txManager := memory.NewTransactionManager(store)
+
+	prs := NewPRService(prRepo, userRepo, teamRepo, absenceRepo, affinityRepo, txManager, outboxRepo)
+	return services{
+		teams:        NewTeamService(teamRepo, userRepo, absenceRepo, txManager, outboxRepo),
+		users:        NewUserService(userRepo, teamRepo, prRepo, absenceRepo, affinityRepo, txManager, outboxRepo),
+		prs:          prs,
+		integrations: NewIntegrationService(accountRepo, prs),
+		sla:          NewReviewSLAService(teamRepo, prRepo, txManager, outboxRepo, prs, ReviewSLAConfig{DefaultSLA: 24 * time.Hour, BatchSize: 2}),
+		outbox:       outboxRepo,
+		clock:        clock,
+	}
+}
+
+// events returns the types of the events recorded in the outbox since the last call
+func (s services) events(t *testing.T) []models.EventType {
+	t.Helper()
+	ctx := context.Background()
+
+	messages, err := s.outbox.LockPending(ctx, time.Now(), 0)
+	if err != nil {
+		t.Fatalf("LockPending: %v", err)
+	}
+
+	result := make([]models.EventType, len(messages))
+	for i, msg := range messages {
+		result[i] = msg.EventType
+		if err := s.outbox.MarkPublished(ctx, msg.ID, time.Now()); err != nil {
+			t.Fatalf("MarkPublished: %v", err)
+		}
+	}
+	return result
+}
+
+func mustCreateTeam(t *testing.T, s services, name string, members ...request.TeamMemberRequest) {
+	t.Helper()
+	if _, err := s.teams.CreateTeam(context.Background(), &request.CreateTeamRequest{TeamName: name, Members: members}); err != nil {
+		t.Fatalf("CreateTeam(%s): %v", name, err)
+	}
+}
+
+func mustCreatePR(t *testing.T, s services, id, author string) []string {
+	t.Helper()
+	resp, err := s.prs.CreatePR(context.Background(), &request.CreatePRRequest{
+		PullRequestID:   id,
+		PullRequestName: "PR " + id,
+		AuthorID:        author,
+	})
+	if err != nil {
+		t.Fatalf("CreatePR(%s): %v", id, err)
+	}
+	return resp.PR.AssignedReviewers
+}
+
+func active(id string) request.TeamMemberRequest {
+	return request.TeamMemberRequest{UserID: id, Username: "name-" + id, IsActive: true}
+}
+
+func inactive(id string) request.TeamMemberRequest {
+	return request.TeamMemberRequest{UserID: id, Username: "name-" + id}
+}
+
+func TestCreateTeam(t *testing.T) {
+	s := newServices()
+	ctx := context.Background()
+
+	mustCreateTeam(t, s, "backend", active("u1"), inactive("u2"))
+
+	team, err := s.teams.GetTeam(ctx, "backend")
+	if err != nil {
+		t.Fatalf("GetTeam: %v", err)
+	}
+	if len(team.Members) != 2 {
+		t.Fatalf("members = %d, want 2", len(team.Members))
+	}
+
+	_, err = s.teams.CreateTeam(ctx, &request.CreateTeamRequest{TeamName: "backend"})
+	if !errors.Is(err, pkgerrors.ErrTeamExists) {
+		t.Fatalf("duplicate team error = %v, want ErrTeamExists", err)
+	}
+
+	_, err = s.teams.GetTeam(ctx, "missing")
+	if !errors.Is(err, pkgerrors.ErrTeamNotFound) {
+		t.Fatalf("missing team error = %v, want ErrTeamNotFound", err)
+	}
+}
+
+func TestCreateTeamRollsBackOnDuplicateUser(t *testing.T) {
+	s := newServices()
+	ctx := context.Background()
+
+	mustCreateTeam(t, s, "backend", active("u1"))
+
+	_, err := s.teams.CreateTeam(ctx, &request.CreateTeamRequest{
+		TeamName: "frontend",
+		Members:  []request.TeamMemberRequest{active("u2"), active("u1")},
+	})
+	if !errors.Is(err, pkgerrors.ErrUserAlreadyExists) {
+		t.Fatalf("error = %v, want ErrUserAlreadyExists", err)
+	}
+
+	if _, err := s.teams.GetTeam(ctx, "frontend"); !errors.Is(err, pkgerrors.ErrTeamNotFound) {
+		t.Fatalf("team should be rolled back, got %v", err)
+	}
+	if _, err := s.users.GetUserReviews(ctx, "u2"); !errors.Is(err, pkgerrors.ErrUserNotFound) {
+		t.Fatalf("user u2 should be rolled back, got %v", err)
+	}
+}
+
+func TestCreatePRAssignsActiveTeammates(t *testing.T) {
+	tests := []struct {
+		name    string
+		members []request.TeamMemberRequest
+		want    int
+	}{
+		{"no candidates", []request.TeamMemberRequest{active("author"), inactive("u1")}, 0},
+		{"one candidate", []request.TeamMemberRequest{active("author"), active("u1"), inactive("u2")}, 1},
+		{"capped at two", []request.TeamMemberRequest{active("author"), active("u1"), active("u2"), active("u3")}, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newServices()
+			mustCreateTeam(t, s, "backend", tt.members...)
+
+			reviewers := mustCreatePR(t, s, "pr-1", "author")
+			if len(reviewers) != tt.want {
+				t.Fatalf("reviewers = %v, want %d", reviewers, tt.want)
+			}
+			eligible := make(map[string]bool)
+			for _, m := range tt.members {
+				eligible[m.UserID] = m.IsActive && m.UserID != "author"
+			}
+			for _, id := range reviewers {
+				if !eligible[id] {
+					t.Fatalf("unexpected reviewer %s", id)
+				}
+			}
+		})
+	}
+}
+
+func TestCreatePRErrors(t *testing.T) {
+	s := newServices()
+	ctx := context.Background()
+	mustCreateTeam(t, s, "backend", active("author"))
+	mustCreatePR(t, s, "pr-1", "author")
+
+	_, err := s.prs.CreatePR(ctx, &request.CreatePRRequest{PullRequestID: "pr-1", PullRequestName: "dup", AuthorID: "author"})
+	if !errors.Is(err, pkgerrors.ErrPRExists) {
+		t.Fatalf("duplicate PR error = %v, want ErrPRExists", err)
+	}
+
+	_, err = s.prs.CreatePR(ctx, &request.CreatePRRequest{PullRequestID: "pr-2", PullRequestName: "x", AuthorID: "ghost"})
+	if !errors.Is(err, pkgerrors.ErrUserNotFound) {
+		t.Fatalf("unknown author error = %v, want ErrUserNotFound", err)
+	}
+}
+
+func TestMergePRIsIdempotent(t *testing.T) {
+	s := newServices()
+	ctx := context.Background()
+	mustCreateTeam(t, s, "backend", active("author"), active("u1"))
+	mustCreatePR(t, s, "pr-1", "author")
+
+	first, err := s.prs.MergePR(ctx, &request.MergePRRequest{PullRequestID: "pr-1"})
+	if err != nil {
+		t.Fatalf("MergePR: %v", err)
+	}
+	second, err := s.prs.MergePR(ctx, &request.MergePRRequest{PullRequestID: "pr-1"})
+	if err != nil {
+		t.Fatalf("second MergePR: %v", err)
+	}
+
+	if first.PR.Status != "MERGED" || second.PR.Status != "MERGED" {
+		t.Fatalf("status = %s/%s, want MERGED", first.PR.Status, second.PR.Status)
+	}
+	if first.PR.MergedAt == nil || second.PR.MergedAt == nil || !first.PR.MergedAt.Equal(*second.PR.MergedAt) {
+		t.Fatalf("mergedAt changed between merges: %v -> %v", first.PR.MergedAt, second.PR.MergedAt)
+	}
+
+	_, err = s.prs.MergePR(ctx, &request.MergePRRequest{PullRequestID: "missing"})
+	if !errors.Is(err, pkgerrors.ErrPRNotFound) {
+		t.Fatalf("missing PR error = %v, want ErrPRNotFound", err)
+	}
+}
+
+func TestReassignReviewer(t *testing.T) {
+	s := newServices()
+	ctx := context.Background()
+	mustCreateTeam(t, s, "backend", active("author"), active("u1"), active("u2"), active("u3"))
+	reviewers := mustCreatePR(t, s, "pr-1", "author")
+
+	old := reviewers[0]
+	resp, err := s.prs.ReassignReviewer(ctx, &request.ReassignReviewerRequest{PullRequestID: "pr-1", OldUserID: old})
+	if err != nil {
+		t.Fatalf("ReassignReviewer: %v", err)
+	}
+	if resp.ReplacedBy == old || resp.ReplacedBy == "author" || resp.ReplacedBy == reviewers[1] {
+		t.Fatalf("replaced_by = %s, reviewers were %v", resp.ReplacedBy, reviewers)
+	}
+	for _, id := range resp.PR.AssignedReviewers {
+		if id == old {
+			t.Fatalf("old reviewer %s still assigned: %v", old, resp.PR.AssignedReviewers)
+		}
+	}
+
+	// The replaced reviewer is the only remaining teammate; once inactive nobody is left
+	if _, err := s.users.SetUserActive(ctx, &request.SetUserActiveRequest{UserID: old, IsActive: false}); err != nil {
+		t.Fatalf("SetUserActive: %v", err)
+	}
+	_, err = s.prs.ReassignReviewer(ctx, &request.ReassignReviewerRequest{PullRequestID: "pr-1", OldUserID: resp.ReplacedBy})
+	if !errors.Is(err, pkgerrors.ErrNoCandidates) {
+		t.Fatalf("error = %v, want ErrNoCandidates", err)
+	}
+
+	_, err = s.prs.ReassignReviewer(ctx, &request.ReassignReviewerRequest{PullRequestID: "pr-1", OldUserID: "author"})
+	if !errors.Is(err, pkgerrors.ErrReviewerNotAssigned) {
+		t.Fatalf("error = %v, want ErrReviewerNotAssigned", err)
+	}
+
+	if _, err := s.prs.MergePR(ctx, &request.MergePRRequest{PullRequestID: "pr-1"}); err != nil {
+		t.Fatalf("MergePR: %v", err)
+	}
+	_, err = s.prs.ReassignReviewer(ctx, &request.ReassignReviewerRequest{PullRequestID: "pr-1", OldUserID: resp.ReplacedBy})
+	if !errors.Is(err, pkgerrors.ErrPRMerged) {
+		t.Fatalf("error = %v, want ErrPRMerged", err)
+	}
+}
+
+func TestUserService(t *testing.T) {
+	s := newServices()
+	ctx := context.Background()
+	mustCreateTeam(t, s, "backend", active("author"), active("u1"))
+	mustCreatePR(t, s, "pr-1", "author")
+
+	reviews, err := s.users.GetUserReviews(ctx, "u1")
+	if err != nil {
+		t.Fatalf("GetUserReviews: %v", err)
+	}
+	if len(reviews.PullRequests) != 1 || reviews.PullRequests[0].PullRequestID != "pr-1" {
+		t.Fatalf("reviews = %+v, want pr-1", reviews.PullRequests)
+	}
+
+	resp, err := s.users.SetUserActive(ctx, &request.SetUserActiveRequest{UserID: "u1", IsActive: false})
+	if err != nil {
+		t.Fatalf("SetUserActive: %v", err)
+	}
+	if resp.User.IsActive || resp.User.TeamName != "backend" {
+		t.Fatalf("user = %+v, want inactive member of backend", resp.User)
+	}
+
+	// Inactive users are not assigned to new PRs
+	if reviewers := mustCreatePR(t, s, "pr-2", "author"); len(reviewers) != 0 {
+		t.Fatalf("reviewers = %v, want none", reviewers)
+	}
+
+	_, err = s.users.SetUserActive(ctx, &request.SetUserActiveRequest{UserID: "ghost", IsActive: true})
+	if !errors.Is(err, pkgerrors.ErrUserNotFound) {
+		t.Fatalf("error = %v, want ErrUserNotFound", err)
+	}
+}
+
+func TestSetMentionHandle(t *testing.T) {
+	s := newServices()
+	ctx := context.Background()
+
+	member := active("u1")
+	member.MentionHandle = "U012AB3CD"
+	mustCreateTeam(t, s, "backend", member, active("u2"))
+
+	team, err := s.teams.GetTeam(ctx, "backend")
+	if err != nil || team.Members[0].MentionHandle != "U012AB3CD" || team.Members[1].MentionHandle != "" {
+		t.Fatalf("GetTeam = %+v, %v; want u1 with a mention handle", team, err)
+	}
+
+	resp, err := s.users.SetMentionHandle(ctx, &request.SetMentionHandleRequest{UserID: "u2", MentionHandle: "W0ENTERPRISE"})
+	if err != nil || resp.User.MentionHandle != "W0ENTERPRISE" {
+		t.Fatalf("SetMentionHandle = %+v, %v", resp, err)
+	}
+	resp, err = s.users.SetMentionHandle(ctx, &request.SetMentionHandleRequest{UserID: "u1"})
+	if err != nil || resp.User.MentionHandle != "" {
+		t.Fatalf("clearing the handle = %+v, %v", resp, err)
+	}
+
+	tests := []struct {
+		name string
+		req  request.SetMentionHandleRequest
+		want error
+	}{
+		{name: "unknown user", req: request.SetMentionHandleRequest{UserID: "ghost", MentionHandle: "U1234"}, want: pkgerrors.ErrUserNotFound},
+		{name: "display name", req: request.SetMentionHandleRequest{UserID: "u1", MentionHandle: "@alice"}},
+		{name: "markup", req: request.SetMentionHandleRequest{UserID: "u1", MentionHandle: "U1>|<!channel"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := s.users.SetMentionHandle(ctx, &tt.req)
+			var validationErr *pkgerrors.ValidationError
+			switch {
+			case tt.want != nil && !errors.Is(err, tt.want):
+				t.Fatalf("error = %v, want %v", err, tt.want)
+			case tt.want == nil && !errors.As(err, &validationErr):
+				t.Fatalf("error = %v, want a validation error", err)
+			}
+		})
+	}
+
+	_, err = s.teams.CreateTeam(ctx, &request.CreateTeamRequest{
+		TeamName: "frontend",
+		Members:  []request.TeamMemberRequest{{UserID: "f1", Username: "f1", MentionHandle: "alice"}},
+	})
+	var validationErr *pkgerrors.ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("CreateTeam with invalid handle error = %v, want a validation error", err)
+	}
+}
+
+func TestSetEmail(t *testing.T) {
+	s := newServices()
+	ctx := context.Background()
+
+	member := active("u1")
+	member.Email = "alice@example.com"
+	mustCreateTeam(t, s, "backend", member, active("u2"))
+
+	team, err := s.teams.GetTeam(ctx, "backend")
+	if err != nil || team.Members[0].Email != "alice@example.com" || team.Members[1].Email != "" {
+		t.Fatalf("GetTeam = %+v, %v; want u1 with an email", team, err)
+	}
+
+	resp, err := s.users.SetEmail(ctx, &request.SetEmailRequest{UserID: "u2", Email: "bob@example.com"})
+	if err != nil || resp.User.Email != "bob@example.com" {
+		t.Fatalf("SetEmail = %+v, %v", resp, err)
+	}
+	resp, err = s.users.SetEmail(ctx, &request.SetEmailRequest{UserID: "u1"})
+	if err != nil || resp.User.Email != "" {
+		t.Fatalf("clearing the email = %+v, %v", resp, err)
+	}
+
+	tests := []struct {
+		name string
+		req  request.SetEmailRequest
+		want error
+	}{
+		{name: "unknown user", req: request.SetEmailRequest{UserID: "ghost", Email: "ghost@example.com"}, want: pkgerrors.ErrUserNotFound},
+		{name: "not an address", req: request.SetEmailRequest{UserID: "u1", Email: "alice"}},
+		{name: "display name", req: request.SetEmailRequest{UserID: "u1", Email: "Alice <alice@example.com>"}},
+		{name: "header injection", req: request.SetEmailRequest{UserID: "u1", Email: "alice@example.com\r\nBcc: x@example.com"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := s.users.SetEmail(ctx, &tt.req)
+			var validationErr *pkgerrors.ValidationError
+			switch {
+			case tt.want != nil && !errors.Is(err, tt.want):
+				t.Fatalf("error = %v, want %v", err, tt.want)
+			case tt.want == nil && !errors.As(err, &validationErr):
+				t.Fatalf("error = %v, want a validation error", err)
+			}
+		})
+	}
+
+	_, err = s.teams.CreateTeam(ctx, &request.CreateTeamRequest{
+		TeamName: "frontend",
+		Members:  []request.TeamMemberRequest{{UserID: "f1", Username: "f1", Email: "f1@"}},
+	})
+	var validationErr *pkgerrors.ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("CreateTeam with invalid email error = %v, want a validation error", err)
+	}
+}
+
+func TestSetChatWebhook(t *testing.T) {
+	s := newServices()
+	ctx := context.Background()
+	mustCreateTeam(t, s, "backend", active("u1"))
+
+	resp, err := s.teams.SetChatWebhook(ctx, &request.SetChatWebhookRequest{
+		TeamName:   "backend",
+		WebhookURL: "https://hooks.slack.com/services/T000/B000/XXXX",
+	})
+	if err != nil || !resp.ChatNotifications {
+		t.Fatalf("SetChatWebhook = %+v, %v; want notifications on", resp, err)
+	}
+	resp, err = s.teams.SetChatWebhook(ctx, &request.SetChatWebhookRequest{TeamName: "backend"})
+	if err != nil || resp.ChatNotifications {
+		t.Fatalf("clearing the webhook = %+v, %v; want notifications off", resp, err)
+	}
+
+	_, err = s.teams.SetChatWebhook(ctx, &request.SetChatWebhookRequest{TeamName: "missing", WebhookURL: "https://example.com"})
+	if !errors.Is(err, pkgerrors.ErrTeamNotFound) {
+		t.Fatalf("missing team error = %v, want ErrTeamNotFound", err)
+	}
+	_, err = s.teams.SetChatWebhook(ctx, &request.SetChatWebhookRequest{TeamName: "backend", WebhookURL: "hooks.slack.com/x"})
+	var validationErr *pkgerrors.ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("relative URL error = %v, want a validation error", err)
+	}
+}
+
+func TestRegisterWebhookRejectsInternalURLs(t *testing.T) {
+	ctx := context.Background()
+	webhooks := NewWebhookService(memory.NewWebhookRepository(memory.NewStore()), WebhookServiceConfig{})
+	register := func(url string) error {
+		_, err := webhooks.RegisterWebhook(ctx, &request.RegisterWebhookRequest{URL: url, Events: []string{"pr.merged"}})
+		return err
+	}
+
+	for _, url := range []string{
+		"ftp://93.184.215.14/hook",
+		"file:///etc/passwd",
+		"http://127.0.0.1:8080/hook",
+		"http://localhost/hook",
+		"http://[::1]/hook",
+		"http://169.254.169.254/latest/meta-data",
+		"http://10.0.0.5/hook",
+		"http://192.168.1.10/hook",
+		"http://[::ffff:127.0.0.1]/hook",
+		"http://0.0.0.0/hook",
+	} {
+		var validationErr *pkgerrors.ValidationError
+		if err := register(url); !errors.As(err, &validationErr) {
+			t.Errorf("RegisterWebhook(%s) error = %v, want a validation error", url, err)
+		}
+	}
+
+	if err := register("https://93.184.215.14/hook"); err != nil {
+		t.Errorf("public address: %v", err)
+	}
+
+	local := NewWebhookService(memory.NewWebhookRepository(memory.NewStore()), WebhookServiceConfig{AllowPrivateNetworks: true})
+	_, err := local.RegisterWebhook(ctx, &request.RegisterWebhookRequest{URL: "http://127.0.0.1:8080/hook", Events: []string{"pr.merged"}})
+	if err != nil {
+		t.Errorf("loopback with private networks allowed: %v", err)
+	}
+}
+
+func TestSetReviewSLA(t *testing.T) {
+	s := newServices()
+	ctx := context.Background()
+	mustCreateTeam(t, s, "backend", active("u1"))
+
+	if _, err := s.teams.SetReviewSLA(ctx, &request.SetReviewSLARequest{TeamName: "backend", ReviewSLAMinutes: 90}); err != nil {
+		t.Fatalf("SetReviewSLA: %v", err)
+	}
+	team, err := s.teams.GetTeam(ctx, "backend")
+	if err != nil || team.ReviewSLAMinutes != 90 {
+		t.Fatalf("GetTeam = %+v, %v; want a 90 minute SLA", team, err)
+	}
+
+	_, err = s.teams.SetReviewSLA(ctx, &request.SetReviewSLARequest{TeamName: "missing", ReviewSLAMinutes: 90})
+	if !errors.Is(err, pkgerrors.ErrTeamNotFound) {
+		t.Fatalf("missing team error = %v, want ErrTeamNotFound", err)
+	}
+	for _, minutes := range []int{-1, maxReviewSLAMinutes + 1} {
+		_, err = s.teams.SetReviewSLA(ctx, &request.SetReviewSLARequest{TeamName: "backend", ReviewSLAMinutes: minutes})
+		var validationErr *pkgerrors.ValidationError
+		if !errors.As(err, &validationErr) {
+			t.Fatalf("SLA of %d minutes error = %v, want a validation error", minutes, err)
+		}
+	}
+	_, err = s.teams.SetReviewSLA(ctx, &request.SetReviewSLARequest{TeamName: "backend", ReviewSLAMinutes: 90, SLAAction: "ignore"})
+	var validationErr *pkgerrors.ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("unknown SLA action error = %v, want a validation error", err)
+	}
+}
+
+func TestSetCalendar(t *testing.T) {
+	s := newServices()
+	ctx := context.Background()
+	mustCreateTeam(t, s, "backend", active("u1"))
+
+	team, err := s.teams.GetTeam(ctx, "backend")
+	if err != nil || team.Calendar.Timezone != "UTC" || fmt.Sprint(team.Calendar.WeekendDays) != "[sunday saturday]" {
+		t.Fatalf("GetTeam = %+v, %v; want the default UTC calendar", team, err)
+	}
+
+	invalid := []*request.SetCalendarRequest{
+		{TeamName: "backend", Timezone: "Mars/Olympus"},
+		{TeamName: "backend", WeekendDays: []string{"caturday"}},
+		{TeamName: "backend", WeekendDays: []string{"monday", "tuesday", "wednesday", "thursday", "friday", "saturday", "sunday"}},
+	}
+	for _, req := range invalid {
+		_, err := s.teams.SetCalendar(ctx, req)
+		var validationErr *pkgerrors.ValidationError
+		if !errors.As(err, &validationErr) {
+			t.Errorf("SetCalendar(%+v) error = %v, want a validation error", req, err)
+		}
+	}
+	if _, err := s.teams.SetCalendar(ctx, &request.SetCalendarRequest{TeamName: "missing"}); !errors.Is(err, pkgerrors.ErrTeamNotFound) {
+		t.Errorf("missing team error = %v, want ErrTeamNotFound", err)
+	}
+}
+
+func TestRemindStaleReviews(t *testing.T) {
+	s := newServices()
+	ctx := context.Background()
+	mustCreateTeam(t, s, "backend", active("u1"), active("u2"), active("u3"))
+	mustCreateTeam(t, s, "frontend", active("f1"), active("f2"))
+	for _, team := range []string{"backend", "frontend"} {
+		// Every day is a working day, so the SLA is plain wall-clock time
+		if _, err := s.teams.SetCalendar(ctx, &request.SetCalendarRequest{TeamName: team}); err != nil {
+			t.Fatalf("SetCalendar: %v", err)
+		}
+	}
+	if _, err := s.teams.SetReviewSLA(ctx, &request.SetReviewSLARequest{TeamName: "frontend", ReviewSLAMinutes: 60}); err != nil {
+		t.Fatalf("SetReviewSLA: %v", err)
+	}
+	mustCreatePR(t, s, "pr-1", "u1")
+	mustCreatePR(t, s, "pr-2", "f1")
+	s.events(t)
+
+	start := s.clock.now()
+	remind := func(after time.Duration, want int) {
+		t.Helper()
+		s.clock.at = start.Add(after)
+		sent, err := s.sla.EnforceReviewSLA(ctx, s.clock.now())
+		if err != nil {
+			t.Fatalf("EnforceReviewSLA(+%v): %v", after, err)
+		}
+		if sent != want {
+			t.Fatalf("EnforceReviewSLA(+%v) handled %d reviews, want %d", after, sent, want)
+		}
+	}
+
+	remind(30*time.Minute, 0)
+	// The frontend SLA is an hour; a reminder repeats only after another SLA
+	remind(2*time.Hour, 1)
+	assertEvents(t, s.events(t), models.EventReviewReminder)
+	remind(2*time.Hour+30*time.Minute, 0)
+	remind(3*time.Hour+time.Minute, 1)
+	// The default SLA of a day applies to backend; both reviewers are reminded over two batches
+	remind(25*time.Hour, 3)
+	s.events(t)
+
+	// Merged PRs and inactive reviewers are left alone
+	if _, err := s.prs.MergePR(ctx, &request.MergePRRequest{PullRequestID: "pr-1"}); err != nil {
+		t.Fatalf("MergePR: %v", err)
+	}
+	if _, err := s.users.SetUserActive(ctx, &request.SetUserActiveRequest{UserID: "f2", IsActive: false}); err != nil {
+		t.Fatalf("SetUserActive: %v", err)
+	}
+	remind(100*time.Hour, 0)
+}
+
+func TestReviewSLASkipsWeekends(t *testing.T) {
+	s := newServices()
+	ctx := context.Background()
+	mustCreateTeam(t, s, "backend", active("u1"), active("u2"))
+	if _, err := s.teams.SetReviewSLA(ctx, &request.SetReviewSLARequest{TeamName: "backend", ReviewSLAMinutes: 8 * 60}); err != nil {
+		t.Fatalf("SetReviewSLA: %v", err)
+	}
+
+	// Assigned on Friday at 20:00 in Moscow, 4 hours before the weekend
+	s.clock.at = time.Date(2025, 11, 7, 17, 0, 0, 0, time.UTC)
+	mustCreatePR(t, s, "pr-1", "u1")
+
+	enforce := func(at time.Time, want int) {
+		t.Helper()
+		s.clock.at = at
+		handled, err := s.sla.EnforceReviewSLA(ctx, at)
+		if err != nil {
+			t.Fatalf("EnforceReviewSLA(%v): %v", at, err)
+		}
+		if handled != want {
+			t.Fatalf("EnforceReviewSLA(%v) handled %d reviews, want %d", at, handled, want)
+		}
+	}
+
+	// In UTC the default weekend starts at 00:00 UTC Saturday: 7 hours on Friday, the 8th on Monday
+	enforce(time.Date(2025, 11, 9, 12, 0, 0, 0, time.UTC), 0)
+	enforce(time.Date(2025, 11, 10, 0, 30, 0, 0, time.UTC), 0)
+	enforce(time.Date(2025, 11, 10, 1, 0, 0, 0, time.UTC), 1)
+
+	// Friday 22:00 UTC is already Saturday in Moscow (UTC+3), so all 8 hours fall on Monday there:
+	// the review is overdue at 05:00 UTC instead of 06:00 UTC
+	resp, err := s.teams.SetCalendar(ctx, &request.SetCalendarRequest{
+		TeamName:    "backend",
+		Timezone:    "Europe/Moscow",
+		WeekendDays: []string{"saturday", "Sunday"},
+	})
+	if err != nil || resp.Calendar.Timezone != "Europe/Moscow" || len(resp.Calendar.WeekendDays) != 2 {
+		t.Fatalf("SetCalendar = %+v, %v", resp, err)
+	}
+	s.clock.at = time.Date(2025, 11, 14, 22, 0, 0, 0, time.UTC)
+	mustCreatePR(t, s, "pr-2", "u1")
+	enforce(time.Date(2025, 11, 17, 4, 59, 0, 0, time.UTC), 1) // only the pr-1 reminder is due again
+	enforce(time.Date(2025, 11, 17, 5, 0, 0, 0, time.UTC), 1)
+}
+
+func TestReviewSLAReassignsAndEscalates(t *testing.T) {
+	s := newServices()
+	ctx := context.Background()
+	mustCreateTeam(t, s, "backend", active("a1"), active("a2"), active("a3"), active("a4"))
+	mustCreateTeam(t, s, "frontend", active("b1"), active("b2"), inactive("lead"))
+	for _, team := range []string{"backend", "frontend"} {
+		if _, err := s.teams.SetCalendar(ctx, &request.SetCalendarRequest{TeamName: team}); err != nil {
+			t.Fatalf("SetCalendar: %v", err)
+		}
+	}
+
+	// Escalation needs a lead from the team
+	_, err := s.teams.SetReviewSLA(ctx, &request.SetReviewSLARequest{TeamName: "frontend", ReviewSLAMinutes: 60, SLAAction: "escalate"})
+	var validationErr *pkgerrors.ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("escalation without a lead error = %v, want a validation error", err)
+	}
+	if _, err := s.teams.SetLead(ctx, &request.SetLeadRequest{TeamName: "frontend", LeadID: "a1"}); !errors.As(err, &validationErr) {
+		t.Fatalf("lead from another team error = %v, want a validation error", err)
+	}
+	if _, err := s.teams.SetLead(ctx, &request.SetLeadRequest{TeamName: "frontend", LeadID: "lead"}); err != nil {
+		t.Fatalf("SetLead: %v", err)
+	}
+	for team, action := range map[string]string{"backend": "reassign", "frontend": "escalate"} {
+		if _, err := s.teams.SetReviewSLA(ctx, &request.SetReviewSLARequest{TeamName: team, ReviewSLAMinutes: 60, SLAAction: action}); err != nil {
+			t.Fatalf("SetReviewSLA(%s): %v", team, err)
+		}
+	}
+	team, err := s.teams.GetTeam(ctx, "frontend")
+	if err != nil || team.SLAAction != "escalate" || team.LeadID != "lead" {
+		t.Fatalf("GetTeam = %+v, %v; want escalation to the lead", team, err)
+	}
+
+	// The lead was inactive when the PR was created, so b2 is the only reviewer
+	mustCreatePR(t, s, "pr-a", "a1")
+	if reviewers := mustCreatePR(t, s, "pr-b", "b1"); fmt.Sprint(reviewers) != "[b2]" {
+		t.Fatalf("pr-b reviewers = %v, want [b2]", reviewers)
+	}
+	if _, err := s.users.SetUserActive(ctx, &request.SetUserActiveRequest{UserID: "lead", IsActive: true}); err != nil {
+		t.Fatalf("SetUserActive: %v", err)
+	}
+	s.events(t)
+
+	// pr-a: one reviewer goes to the last free teammate, the other has nobody left and is reminded;
+	// pr-b: b2 hands the review over to the lead
+	handled, err := s.sla.EnforceReviewSLA(ctx, s.clock.advance(61*time.Minute))
+	if err != nil || handled != 3 {
+		t.Fatalf("EnforceReviewSLA = %d, %v; want 3 reviews handled", handled, err)
+	}
+
+	history, err := s.prs.GetPRHistory(ctx, "pr-a")
+	if err != nil || len(history.Reassignments) != 1 || history.Reassignments[0].Reason != "sla_timeout" {
+		t.Fatalf("pr-a history = %+v, %v; want one sla_timeout reassignment", history, err)
+	}
+	history, err = s.prs.GetPRHistory(ctx, "pr-b")
+	if err != nil || len(history.Reassignments) != 1 {
+		t.Fatalf("pr-b history = %+v, %v; want one reassignment", history, err)
+	}
+	if got := history.Reassignments[0]; got.OldReviewerID != "b2" || got.NewReviewerID != "lead" || got.Reason != "sla_timeout" {
+		t.Errorf("pr-b reassignment = %+v, want b2 -> lead for sla_timeout", got)
+	}
+	assertEvents(t, s.events(t),
+		models.EventReviewerUnassigned, models.EventReviewerAssigned, models.EventReviewReminder,
+		models.EventReviewerUnassigned, models.EventReviewerAssigned)
+
+	// The lead cannot escalate to themselves and is reminded
+	handled, err = s.sla.EnforceReviewSLA(ctx, s.clock.advance(61*time.Minute))
+	if err != nil || handled != 3 {
+		t.Fatalf("second EnforceReviewSLA = %d, %v; want 3 reviews handled", handled, err)
+	}
+	history, _ = s.prs.GetPRHistory(ctx, "pr-b")
+	if len(history.Reassignments) != 1 {
+		t.Errorf("pr-b history = %+v, want the lead to keep the review", history.Reassignments)
+	}
+}
+
+func TestReassignReviewerRecordsHistory(t *testing.T) {
+	s := newServices()
+	ctx := context.Background()
+	mustCreateTeam(t, s, "backend", active("u1"), active("u2"), active("u3"), active("u4"))
+	reviewers := mustCreatePR(t, s, "pr-1", "u1")
+
+	resp, err := s.prs.ReassignReviewer(ctx, &request.ReassignReviewerRequest{PullRequestID: "pr-1", OldUserID: reviewers[0]})
+	if err != nil {
+		t.Fatalf("ReassignReviewer: %v", err)
+	}
+	history, err := s.prs.GetPRHistory(ctx, "pr-1")
+	if err != nil || len(history.Reassignments) != 1 {
+		t.Fatalf("GetPRHistory = %+v, %v; want one reassignment", history, err)
+	}
+	if got := history.Reassignments[0]; got.OldReviewerID != reviewers[0] || got.NewReviewerID != resp.ReplacedBy || got.Reason != "manual" {
+		t.Errorf("reassignment = %+v, want %s -> %s for manual", got, reviewers[0], resp.ReplacedBy)
+	}
+
+	if _, err := s.prs.GetPRHistory(ctx, "missing"); !errors.Is(err, pkgerrors.ErrPRNotFound) {
+		t.Errorf("missing PR error = %v, want ErrPRNotFound", err)
+	}
+}
+
+func TestAbsentReviewersAreSkipped(t *testing.T) {
+	s := newServices()
+	ctx := context.Background()
+	mustCreateTeam(t, s, "backend", active("author"), active("u1"), active("u2"))
+
+	today := time.Now().UTC()
+	day := func(offset int) string { return today.AddDate(0, 0, offset).Format(models.DateLayout) }
+	for _, req := range []*request.AddAbsenceRequest{
+		{UserID: "u1", StartDate: day(-1), EndDate: day(1), Reason: "vacation"},
+		{UserID: "u2", StartDate: day(-5), EndDate: day(-2)},
+		{UserID: "u2", StartDate: day(3), EndDate: day(4)},
+	} {
+		if _, err := s.users.AddAbsence(ctx, req); err != nil {
+			t.Fatalf("AddAbsence(%+v): %v", req, err)
+		}
+	}
+
+	// u1 is away today, u2 only in the past and the future
+	if reviewers := mustCreatePR(t, s, "pr-1", "author"); fmt.Sprint(reviewers) != "[u2]" {
+		t.Fatalf("reviewers = %v, want [u2]", reviewers)
+	}
+	_, err := s.prs.ReassignReviewer(ctx, &request.ReassignReviewerRequest{PullRequestID: "pr-1", OldUserID: "u2"})
+	if !errors.Is(err, pkgerrors.ErrNoCandidates) {
+		t.Fatalf("error = %v, want ErrNoCandidates", err)
+	}
+
+	team, err := s.teams.GetTeam(ctx, "backend")
+	if err != nil {
+		t.Fatalf("GetTeam: %v", err)
+	}
+	for _, member := range team.Members {
+		var want []string
+		switch member.UserID {
+		case "u1":
+			want = []string{day(-1)}
+		case "u2":
+			want = []string{day(3)}
+		}
+		var got []string
+		for _, absence := range member.Absences {
+			got = append(got, absence.StartDate)
+		}
+		if fmt.Sprint(got) != fmt.Sprint(want) {
+			t.Errorf("absences of %s start on %v, want %v", member.UserID, got, want)
+		}
+		// Absence does not touch is_active
+		if !member.IsActive {
+			t.Errorf("member %s became inactive", member.UserID)
+		}
+	}
+
+	list, err := s.users.ListAbsences(ctx, "u2")
+	if err != nil || len(list.Absences) != 2 {
+		t.Fatalf("ListAbsences = %+v, %v; want both absences of u2", list, err)
+	}
+	if _, err := s.users.DeleteAbsence(ctx, &request.DeleteAbsenceRequest{AbsenceID: list.Absences[1].AbsenceID}); err != nil {
+		t.Fatalf("DeleteAbsence: %v", err)
+	}
+	_, err = s.users.DeleteAbsence(ctx, &request.DeleteAbsenceRequest{AbsenceID: list.Absences[1].AbsenceID})
+	if !errors.Is(err, pkgerrors.ErrAbsenceNotFound) {
+		t.Fatalf("second DeleteAbsence error = %v, want ErrAbsenceNotFound", err)
+	}
+}
+
+func TestAddAbsenceValidation(t *testing.T) {
+	s := newServices()
+	ctx := context.Background()
+	mustCreateTeam(t, s, "backend", active("u1"))
+
+	invalid := []*request.AddAbsenceRequest{
+		{StartDate: "2025-11-10", EndDate: "2025-11-10"},
+		{UserID: "u1", StartDate: "10.11.2025", EndDate: "2025-11-10"},
+		{UserID: "u1", StartDate: "2025-11-10"},
+		{UserID: "u1", StartDate: "2025-11-10", EndDate: "2025-11-09"},
+		{UserID: "u1", StartDate: "2025-11-10", EndDate: "2025-11-10", Reason: strings.Repeat("x", 256)},
+	}
+	for _, req := range invalid {
+		_, err := s.users.AddAbsence(ctx, req)
+		var validationErr *pkgerrors.ValidationError
+		if !errors.As(err, &validationErr) {
+			t.Errorf("AddAbsence(%+v) error = %v, want a validation error", req, err)
+		}
+	}
+
+	_, err := s.users.AddAbsence(ctx, &request.AddAbsenceRequest{UserID: "ghost", StartDate: "2025-11-10", EndDate: "2025-11-10"})
+	if !errors.Is(err, pkgerrors.ErrUserNotFound) {
+		t.Errorf("unknown user error = %v, want ErrUserNotFound", err)
+	}
+	if _, err := s.users.ListAbsences(ctx, "ghost"); !errors.Is(err, pkgerrors.ErrUserNotFound) {
+		t.Errorf("ListAbsences(ghost) error = %v, want ErrUserNotFound", err)
+	}
+}
+
+func TestImportAbsences(t *testing.T) {
+	s := newServices()
+	ctx := context.Background()
+	mustCreateTeam(t, s, "backend", active("author"), active("u1"), active("u2"))
+
+	today := time.Now().UTC()
+	day := func(offset int) string { return today.AddDate(0, 0, offset).Format("20060102") }
+	vacation := []string{"BEGIN:VEVENT", "UID:vacation", "SUMMARY:Vacation", "DTSTART;VALUE=DATE:" + day(-1), "DTEND;VALUE=DATE:" + day(2), "END:VEVENT"}
+	course := []string{"BEGIN:VEVENT", "UID:course", "SUMMARY:Course", "DTSTART;VALUE=DATE:" + day(7), "RRULE:FREQ=WEEKLY;COUNT=2", "END:VEVENT"}
+	past := []string{"BEGIN:VEVENT", "UID:past", "DTSTART;VALUE=DATE:" + day(-40), "DTEND;VALUE=DATE:" + day(-30), "END:VEVENT"}
+	calendar := func(events ...[]string) []byte {
+		lines := []string{"BEGIN:VCALENDAR", "VERSION:2.0"}
+		for _, event := range events {
+			lines = append(lines, event...)
+		}
+		return []byte(strings.Join(append(lines, "END:VCALENDAR"), "\r\n"))
+	}
+
+	resp, err := s.users.ImportAbsences(ctx, "u1", calendar(vacation, course, past))
+	if err != nil {
+		t.Fatalf("ImportAbsences: %v", err)
+	}
+	if resp.Removed != 0 || len(resp.Absences) != 3 {
+		t.Fatalf("import = %+v, want three new absences", resp)
+	}
+	if got := resp.Absences[0]; got.SourceUID != "vacation" || got.Reason != "Vacation" || got.EndDate != today.AddDate(0, 0, 1).Format(models.DateLayout) {
+		t.Errorf("first absence = %+v, want the vacation ending tomorrow", got)
+	}
+	if _, err := s.users.AddAbsence(ctx, &request.AddAbsenceRequest{UserID: "u1", StartDate: "2099-01-01", EndDate: "2099-01-02"}); err != nil {
+		t.Fatalf("AddAbsence: %v", err)
+	}
+
+	// Imported absences are respected by reviewer selection
+	if reviewers := mustCreatePR(t, s, "pr-1", "author"); fmt.Sprint(reviewers) != "[u2]" {
+		t.Fatalf("reviewers = %v, want [u2]", reviewers)
+	}
+
+	// A new import replaces the previous one and keeps the absence added by hand
+	resp, err = s.users.ImportAbsences(ctx, "u1", calendar(course))
+	if err != nil || resp.Removed != 3 || len(resp.Absences) != 2 {
+		t.Fatalf("second import = %+v, %v; want three replaced by two", resp, err)
+	}
+	list, err := s.users.ListAbsences(ctx, "u1")
+	if err != nil || len(list.Absences) != 3 || list.Absences[2].SourceUID != "" {
+		t.Fatalf("ListAbsences = %+v, %v; want two imported and one manual absence", list, err)
+	}
+
+	// A rejected file leaves the previous import in place
+	_, err = s.users.ImportAbsences(ctx, "u1", calendar([]string{"BEGIN:VEVENT", "UID:broken", "END:VEVENT"}))
+	var validationErr *pkgerrors.ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("error = %v, want a validation error", err)
+	}
+	if list, err := s.users.ListAbsences(ctx, "u1"); err != nil || len(list.Absences) != 3 {
+		t.Fatalf("ListAbsences = %+v, %v; want the previous import kept", list, err)
+	}
+
+	if _, err := s.users.ImportAbsences(ctx, "ghost", calendar(course)); !errors.Is(err, pkgerrors.ErrUserNotFound) {
+		t.Errorf("unknown user error = %v, want ErrUserNotFound", err)
+	}
+}
+
+func TestSetWorkingHours(t *testing.T) {
+	s := newServices()
+	ctx := context.Background()
+	mustCreateTeam(t, s, "backend", active("u1"))
+
+	resp, err := s.users.SetWorkingHours(ctx, &request.SetWorkingHoursRequest{
+		UserID: "u1", Timezone: "Europe/Moscow", WorkStart: "22:00", WorkEnd: "06:30",
+	})
+	if err != nil || resp.User.Timezone != "Europe/Moscow" || resp.User.WorkStart != "22:00" || resp.User.WorkEnd != "06:30" {
+		t.Fatalf("SetWorkingHours = %+v, %v", resp, err)
+	}
+	team, err := s.teams.GetTeam(ctx, "backend")
+	if err != nil || team.Members[0].WorkStart != "22:00" || team.PreferWorkingHours {
+		t.Fatalf("GetTeam = %+v, %v; want the working hours and no preference", team, err)
+	}
+	resp, err = s.users.SetWorkingHours(ctx, &request.SetWorkingHoursRequest{UserID: "u1"})
+	if err != nil || resp.User.Timezone != "" || resp.User.WorkStart != "" || resp.User.WorkEnd != "" {
+		t.Fatalf("clearing the working hours = %+v, %v", resp, err)
+	}
+
+	tests := []struct {
+		name string
+		req  request.SetWorkingHoursRequest
+		want error
+	}{
+		{name: "unknown user", req: request.SetWorkingHoursRequest{UserID: "ghost", WorkStart: "09:00", WorkEnd: "18:00"}, want: pkgerrors.ErrUserNotFound},
+		{name: "unknown timezone", req: request.SetWorkingHoursRequest{UserID: "u1", Timezone: "Mars/Olympus"}},
+		{name: "start only", req: request.SetWorkingHoursRequest{UserID: "u1", WorkStart: "09:00"}},
+		{name: "not a time", req: request.SetWorkingHoursRequest{UserID: "u1", WorkStart: "9am", WorkEnd: "18:00"}},
+		{name: "out of range", req: request.SetWorkingHoursRequest{UserID: "u1", WorkStart: "09:00", WorkEnd: "24:00"}},
+		{name: "empty day", req: request.SetWorkingHoursRequest{UserID: "u1", WorkStart: "09:00", WorkEnd: "09:00"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := s.users.SetWorkingHours(ctx, &tt.req)
+			var validationErr *pkgerrors.ValidationError
+			switch {
+			case tt.want != nil && !errors.Is(err, tt.want):
+				t.Fatalf("error = %v, want %v", err, tt.want)
+			case tt.want == nil && !errors.As(err, &validationErr):
+				t.Fatalf("error = %v, want a validation error", err)
+			}
+		})
+	}
+
+	for _, hours := range []int{-1, 169} {
+		_, err := s.teams.SetWorkingHoursPreference(ctx, &request.SetWorkingHoursPreferenceRequest{TeamName: "backend", PreferWorkingHours: true, WithinHours: hours})
+		var validationErr *pkgerrors.ValidationError
+		if !errors.As(err, &validationErr) {
+			t.Errorf("within_hours %d error = %v, want a validation error", hours, err)
+		}
+	}
+	_, err = s.teams.SetWorkingHoursPreference(ctx, &request.SetWorkingHoursPreferenceRequest{TeamName: "missing", PreferWorkingHours: true})
+	if !errors.Is(err, pkgerrors.ErrTeamNotFound) {
+		t.Errorf("missing team error = %v, want ErrTeamNotFound", err)
+	}
+}
+
+func TestPreferReviewersInWorkingHours(t *testing.T) {
+	s := newServices()
+	s.prs.now = s.clock.now
+	ctx := context.Background()
+	mustCreateTeam(t, s, "backend", active("author"), active("u1"), active("u2"), active("u3"), active("u4"))
+
+	// Monday 09:00 UTC: 12:00 in Moscow, 04:00 in New York, 18:00 in Tokyo; u4 has no working hours
+	for _, req := range []*request.SetWorkingHoursRequest{
+		{UserID: "u1", Timezone: "Europe/Moscow", WorkStart: "10:00", WorkEnd: "19:00"},
+		{UserID: "u2", Timezone: "America/New_York", WorkStart: "09:00", WorkEnd: "18:00"},
+		{UserID: "u3", Timezone: "Asia/Tokyo", WorkStart: "09:00", WorkEnd: "18:00"},
+	} {
+		if _, err := s.users.SetWorkingHours(ctx, req); err != nil {
+			t.Fatalf("SetWorkingHours(%+v): %v", req, err)
+		}
+	}
+	setPreference := func(enabled bool, withinHours int) {
+		t.Helper()
+		_, err := s.teams.SetWorkingHoursPreference(ctx, &request.SetWorkingHoursPreferenceRequest{
+			TeamName: "backend", PreferWorkingHours: enabled, WithinHours: withinHours,
+		})
+		if err != nil {
+			t.Fatalf("SetWorkingHoursPreference: %v", err)
+		}
+	}
+	sorted := func(ids []string) string {
+		ids = append([]string(nil), ids...)
+		sort.Strings(ids)
+		return fmt.Sprint(ids)
+	}
+
+	setPreference(true, 0)
+	for i := 0; i < 5; i++ {
+		if reviewers := mustCreatePR(t, s, fmt.Sprintf("pr-%d", i), "author"); sorted(reviewers) != "[u1 u4]" {
+			t.Fatalf("reviewers = %v, want the two teammates at work", reviewers)
+		}
+	}
+
+	// Within 6 hours u2 counts too, u3 starts only tomorrow
+	setPreference(true, 6)
+	resp, err := s.prs.ReassignReviewer(ctx, &request.ReassignReviewerRequest{PullRequestID: "pr-0", OldUserID: "u1"})
+	if err != nil || resp.ReplacedBy != "u2" {
+		t.Fatalf("ReassignReviewer = %+v, %v; want u2", resp, err)
+	}
+
+	// Nobody but u4 works on Saturday; the rest of the team fills in
+	s.clock.advance(5 * 24 * time.Hour)
+	setPreference(true, 0)
+	for i := 0; i < 5; i++ {
+		reviewers := mustCreatePR(t, s, fmt.Sprintf("pr-sat-%d", i), "author")
+		if len(reviewers) != 2 || !strings.Contains(sorted(reviewers), "u4") {
+			t.Fatalf("reviewers = %v, want u4 and one more", reviewers)
+		}
+	}
+
+	team, err := s.teams.GetTeam(ctx, "backend")
+	if err != nil || !team.PreferWorkingHours || team.PreferWithinHours != 0 {
+		t.Fatalf("GetTeam = %+v, %v; want the preference on", team, err)
+	}
+}
+
+func TestReviewCapacity(t *testing.T) {
+	s := newServices()
+	ctx := context.Background()
+
+	limited := func(member request.TeamMemberRequest) request.TeamMemberRequest {
+		member.MaxOpenReviews = 1
+		return member
+	}
+	mustCreateTeam(t, s, "backend", active("author"), limited(active("u1")), limited(active("u2")), limited(inactive("u3")))
+
+	if reviewers := mustCreatePR(t, s, "pr-1", "author"); len(reviewers) != 2 {
+		t.Fatalf("reviewers = %v, want u1 and u2", reviewers)
+	}
+	_, err := s.prs.CreatePR(ctx, &request.CreatePRRequest{PullRequestID: "pr-2", PullRequestName: "PR pr-2", AuthorID: "author"})
+	if !errors.Is(err, pkgerrors.ErrAllAtCapacity) {
+		t.Fatalf("CreatePR error = %v, want ErrAllAtCapacity", err)
+	}
+
+	// Only the teammate with room is assigned; then nobody can take over from u1
+	if _, err := s.users.SetUserActive(ctx, &request.SetUserActiveRequest{UserID: "u3", IsActive: true}); err != nil {
+		t.Fatalf("SetUserActive: %v", err)
+	}
+	if reviewers := mustCreatePR(t, s, "pr-2", "author"); fmt.Sprint(reviewers) != "[u3]" {
+		t.Fatalf("reviewers = %v, want [u3]", reviewers)
+	}
+	_, err = s.prs.ReassignReviewer(ctx, &request.ReassignReviewerRequest{PullRequestID: "pr-1", OldUserID: "u1"})
+	if !errors.Is(err, pkgerrors.ErrAllAtCapacity) {
+		t.Fatalf("ReassignReviewer error = %v, want ErrAllAtCapacity", err)
+	}
+
+	// Merged PRs free their reviewers
+	if _, err := s.prs.MergePR(ctx, &request.MergePRRequest{PullRequestID: "pr-1"}); err != nil {
+		t.Fatalf("MergePR: %v", err)
+	}
+	resp, err := s.prs.ReassignReviewer(ctx, &request.ReassignReviewerRequest{PullRequestID: "pr-2", OldUserID: "u3"})
+	if err != nil || (resp.ReplacedBy != "u1" && resp.ReplacedBy != "u2") {
+		t.Fatalf("ReassignReviewer = %+v, %v; want u1 or u2", resp, err)
+	}
+
+	// Raising the limit makes room again
+	userResp, err := s.users.SetMaxOpenReviews(ctx, &request.SetMaxOpenReviewsRequest{UserID: resp.ReplacedBy, MaxOpenReviews: 0})
+	if err != nil || userResp.User.MaxOpenReviews != 0 {
+		t.Fatalf("SetMaxOpenReviews = %+v, %v", userResp, err)
+	}
+	if reviewers := mustCreatePR(t, s, "pr-3", "author"); len(reviewers) != 2 {
+		t.Fatalf("reviewers = %v, want two", reviewers)
+	}
+
+	for _, limit := range []int{-1, 101} {
+		_, err := s.users.SetMaxOpenReviews(ctx, &request.SetMaxOpenReviewsRequest{UserID: "u1", MaxOpenReviews: limit})
+		var validationErr *pkgerrors.ValidationError
+		if !errors.As(err, &validationErr) {
+			t.Errorf("limit %d error = %v, want a validation error", limit, err)
+		}
+	}
+	if _, err := s.users.SetMaxOpenReviews(ctx, &request.SetMaxOpenReviewsRequest{UserID: "ghost", MaxOpenReviews: 1}); !errors.Is(err, pkgerrors.ErrUserNotFound) {
+		t.Errorf("unknown user error = %v, want ErrUserNotFound", err)
+	}
+}
+
+// interleavingPRRepo runs during once, right after the first count of open reviews
+type interleavingPRRepo struct {
+	repository.PRRepository
+	started atomic.Bool
+	during  func()
+}
+
+func (r *interleavingPRRepo) CountOpenReviews(ctx context.Context, reviewerIDs []string) (map[string]int, error) {
+	counts, err := r.PRRepository.CountOpenReviews(ctx, reviewerIDs)
+	if err == nil && r.started.CompareAndSwap(false, true) {
+		r.during()
+	}
+	return counts, err
+}
+
+func TestReviewCapacityUnderConcurrentCreates(t *testing.T) {
+	s := newServices()
+	ctx := context.Background()
+
+	reviewer := active("u1")
+	reviewer.MaxOpenReviews = 1
+	mustCreateTeam(t, s, "backend", active("author"), reviewer)
+
+	createPR := func(id string) ([]string, error) {
+		resp, err := s.prs.CreatePR(ctx, &request.CreatePRRequest{PullRequestID: id, PullRequestName: "PR " + id, AuthorID: "author"})
+		if err != nil {
+			return nil, err
+		}
+		return resp.PR.AssignedReviewers, nil
+	}
+
+	// Another PR is created between the count and the assignment of the first one; it has to wait
+	// for the first transaction, so it sees the slot taken
+	second := make(chan error, 1)
+	s.prs.prRepo = &interleavingPRRepo{PRRepository: s.prs.prRepo, during: func() {
+		go func() {
+			_, err := createPR("pr-2")
+			second <- err
+		}()
+		select {
+		case err := <-second:
+			second <- err
+		case <-time.After(100 * time.Millisecond):
+		}
+	}}
+
+	reviewers, err := createPR("pr-1")
+	if err != nil || fmt.Sprint(reviewers) != "[u1]" {
+		t.Fatalf("first CreatePR = %v, %v; want [u1]", reviewers, err)
+	}
+	if err := <-second; !errors.Is(err, pkgerrors.ErrAllAtCapacity) {
+		t.Fatalf("second CreatePR error = %v, want ErrAllAtCapacity", err)
+	}
+}
+
+func TestCodeOwners(t *testing.T) {
+	s := newServices()
+	ctx := context.Background()
+
+	mustCreateTeam(t, s, "backend", active("author"), active("u1"), active("u2"), active("u3"))
+	mustCreateTeam(t, s, "frontend", active("f1"), active("f2"))
+
+	file := []byte("# Owners of the backend repository\n" +
+		"*       @team/backend\n" +
+		"/api/   @u1\n" +
+		"*.tsx   @team/frontend\n" +
+		"/generated/\n")
+
+	// A dry run validates the file without storing it
+	uploaded, err := s.teams.UploadCodeOwners(ctx, "backend", true, file)
+	if err != nil || !uploaded.DryRun || len(uploaded.Rules) != 4 {
+		t.Fatalf("UploadCodeOwners(dry run) = %+v, %v; want 4 rules", uploaded, err)
+	}
+	if stored, err := s.teams.GetCodeOwners(ctx, "backend"); err != nil || len(stored.Rules) != 0 {
+		t.Fatalf("GetCodeOwners = %+v, %v; want no rules after a dry run", stored, err)
+	}
+	if _, err := s.teams.UploadCodeOwners(ctx, "backend", false, file); err != nil {
+		t.Fatalf("UploadCodeOwners: %v", err)
+	}
+	stored, err := s.teams.GetCodeOwners(ctx, "backend")
+	if err != nil || fmt.Sprint(stored.Rules) != "[{2 * [@team/backend]} {3 /api/ [@u1]} {4 *.tsx [@team/frontend]} {5 /generated/ []}]" {
+		t.Fatalf("GetCodeOwners = %+v, %v", stored, err)
+	}
+
+	createPR := func(id, author string, files ...string) []string {
+		t.Helper()
+		resp, err := s.prs.CreatePR(ctx, &request.CreatePRRequest{
+			PullRequestID:   id,
+			PullRequestName: "PR " + id,
+			AuthorID:        author,
+			ChangedFiles:    files,
+		})
+		if err != nil {
+			t.Fatalf("CreatePR(%s): %v", id, err)
+		}
+		return resp.PR.AssignedReviewers
+	}
+	backend := map[string]bool{"author": true, "u1": true, "u2": true, "u3": true}
+	frontend := map[string]bool{"f1": true, "f2": true}
+
+	for i := 0; i < 20; i++ {
+		// The only owner of /api/ is always asked
+		reviewers := createPR(fmt.Sprintf("api-%d", i), "author", "api/handler.go", "api/router.go")
+		if len(reviewers) != 2 || (reviewers[0] != "u1" && reviewers[1] != "u1") {
+			t.Fatalf("api reviewers = %v, want u1 and a teammate", reviewers)
+		}
+
+		// Owners from another team take one seat, the other goes to the author's team
+		reviewers = createPR(fmt.Sprintf("web-%d", i), "author", "web/app.tsx")
+		if len(reviewers) != 2 || !frontend[reviewers[0]] || !backend[reviewers[1]] {
+			t.Fatalf("web reviewers = %v, want a frontend owner and a backend teammate", reviewers)
+		}
+
+		// Unowned paths and PRs without files fall back to the author's team
+		for _, reviewers := range [][]string{
+			createPR(fmt.Sprintf("gen-%d", i), "author", "generated/client.go"),
+			createPR(fmt.Sprintf("plain-%d", i), "author"),
+		} {
+			if len(reviewers) != 2 || !backend[reviewers[0]] || !backend[reviewers[1]] {
+				t.Fatalf("reviewers = %v, want two backend teammates", reviewers)
+			}
+		}
+
+		// The author is never their own code owner reviewer
+		reviewers = createPR(fmt.Sprintf("own-%d", i), "u1", "api/handler.go")
+		if len(reviewers) != 2 || reviewers[0] == "u1" || reviewers[1] == "u1" {
+			t.Fatalf("own reviewers = %v, want two teammates other than u1", reviewers)
+		}
+	}
+
+	// An unavailable owner does not block the PR
+	if _, err := s.users.SetUserActive(ctx, &request.SetUserActiveRequest{UserID: "u1", IsActive: false}); err != nil {
+		t.Fatalf("SetUserActive: %v", err)
+	}
+	if reviewers := createPR("api-inactive", "author", "api/handler.go"); len(reviewers) != 2 || reviewers[0] == "u1" || reviewers[1] == "u1" {
+		t.Fatalf("reviewers = %v, want two teammates other than u1", reviewers)
+	}
+
+	_, err = s.prs.CreatePR(ctx, &request.CreatePRRequest{PullRequestID: "bad", PullRequestName: "bad", AuthorID: "author", ChangedFiles: []string{" "}})
+	var validationErr *pkgerrors.ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Errorf("CreatePR with an empty path error = %v, want a validation error", err)
+	}
+
+	for name, file := range map[string]string{
+		"unknown user":  "* @u1\n/api/ @ghost\n",
+		"unknown team":  "* @team/mobile\n",
+		"email owner":   "* dev@example.com\n",
+		"negated paths": "!vendor/ @u1\n",
+	} {
+		_, err := s.teams.UploadCodeOwners(ctx, "backend", false, []byte(file))
+		if !errors.As(err, &validationErr) {
+			t.Errorf("%s: error = %v, want a validation error", name, err)
+		}
+	}
+	if _, err := s.teams.UploadCodeOwners(ctx, "backend", false, []byte("* @u1\n/api/ @ghost\n")); !strings.Contains(fmt.Sprint(err), "line 2: unknown user @ghost") {
+		t.Errorf("unknown user error = %v, want it to name the line", err)
+	}
+	if _, err := s.teams.UploadCodeOwners(ctx, "mobile", false, file); !errors.Is(err, pkgerrors.ErrTeamNotFound) {
+		t.Errorf("unknown team error = %v, want ErrTeamNotFound", err)
+	}
+	if stored, err := s.teams.GetCodeOwners(ctx, "backend"); err != nil || len(stored.Rules) != 4 {
+		t.Errorf("GetCodeOwners = %+v, %v; want the rules kept after failed uploads", stored, err)
+	}
+}
+
+func TestSkillTags(t *testing.T) {
+	s := newServices()
+	ctx := context.Background()
+
+	tagged := func(member request.TeamMemberRequest, tags ...string) request.TeamMemberRequest {
+		member.SkillTags = tags
+		return member
+	}
+	mustCreateTeam(t, s, "backend", active("author"), tagged(active("g1"), "SQL", "go"), tagged(active("g2"), "go"),
+		tagged(active("g3"), "sql"), tagged(active("f1"), "frontend"), active("n1"))
+
+	team, err := s.teams.GetTeam(ctx, "backend")
+	if err != nil {
+		t.Fatalf("GetTeam: %v", err)
+	}
+	for _, member := range team.Members {
+		if member.UserID == "g1" && fmt.Sprint(member.SkillTags) != "[go sql]" {
+			t.Errorf("g1 skill tags = %v, want [go sql]", member.SkillTags)
+		}
+	}
+
+	for i := 0; i < 20; i++ {
+		// The member with both tags comes first, then one of those with one of them
+		resp, err := s.prs.CreatePR(ctx, &request.CreatePRRequest{
+			PullRequestID:   fmt.Sprintf("pr-%d", i),
+			PullRequestName: "Tagged PR",
+			AuthorID:        "author",
+			RequiredTags:    []string{"sql", "Go", "go"},
+		})
+		if err != nil {
+			t.Fatalf("CreatePR: %v", err)
+		}
+		reviewers := resp.PR.AssignedReviewers
+		if len(reviewers) != 2 || reviewers[0] != "g1" || (reviewers[1] != "g2" && reviewers[1] != "g3") {
+			t.Fatalf("reviewers = %v, want g1 and g2 or g3", reviewers)
+		}
+		if fmt.Sprint(resp.PR.RequiredTags) != "[go sql]" {
+			t.Fatalf("required tags = %v, want [go sql]", resp.PR.RequiredTags)
+		}
+
+		// The replacement keeps the best overlap left: the other one-tag member
+		other := map[string]string{"g2": "g3", "g3": "g2"}[reviewers[1]]
+		reassigned, err := s.prs.ReassignReviewer(ctx, &request.ReassignReviewerRequest{PullRequestID: resp.PR.PullRequestID, OldUserID: "g1"})
+		if err != nil || reassigned.ReplacedBy != other {
+			t.Fatalf("ReassignReviewer = %+v, %v; want %s", reassigned, err, other)
+		}
+	}
+
+	// Tags nobody has do not stop the PR
+	if reviewers := mustCreatePR(t, s, "plain", "author"); len(reviewers) != 2 {
+		t.Fatalf("reviewers = %v, want two", reviewers)
+	}
+	resp, err := s.prs.CreatePR(ctx, &request.CreatePRRequest{PullRequestID: "rust", PullRequestName: "Rust PR", AuthorID: "author", RequiredTags: []string{"rust"}})
+	if err != nil || len(resp.PR.AssignedReviewers) != 2 {
+		t.Fatalf("CreatePR(rust) = %+v, %v; want two reviewers", resp, err)
+	}
+
+	added, err := s.users.AddSkillTags(ctx, &request.AddSkillTagsRequest{UserID: "n1", Tags: []string{"Go", " k8s "}})
+	if err != nil || fmt.Sprint(added.User.SkillTags) != "[go k8s]" {
+		t.Fatalf("AddSkillTags = %+v, %v; want [go k8s]", added, err)
+	}
+	added, err = s.users.AddSkillTags(ctx, &request.AddSkillTagsRequest{UserID: "n1", Tags: []string{"go", "c++"}})
+	if err != nil || fmt.Sprint(added.User.SkillTags) != "[c++ go k8s]" {
+		t.Fatalf("AddSkillTags = %+v, %v; want [c++ go k8s]", added, err)
+	}
+	removed, err := s.users.RemoveSkillTags(ctx, &request.RemoveSkillTagsRequest{UserID: "n1", Tags: []string{"k8s", "rust"}})
+	if err != nil || fmt.Sprint(removed.User.SkillTags) != "[c++ go]" {
+		t.Fatalf("RemoveSkillTags = %+v, %v; want [c++ go]", removed, err)
+	}
+	if list, err := s.users.GetSkillTags(ctx, "n1"); err != nil || fmt.Sprint(list.SkillTags) != "[c++ go]" {
+		t.Errorf("GetSkillTags = %+v, %v; want [c++ go]", list, err)
+	}
+	set, err := s.users.SetSkillTags(ctx, &request.SetSkillTagsRequest{UserID: "n1"})
+	if err != nil || len(set.User.SkillTags) != 0 {
+		t.Fatalf("SetSkillTags(none) = %+v, %v; want no tags", set, err)
+	}
+
+	many := make([]string, maxSkillTags)
+	for i := range many {
+		many[i] = fmt.Sprintf("tag-%d", i)
+	}
+	if _, err := s.users.SetSkillTags(ctx, &request.SetSkillTagsRequest{UserID: "n1", Tags: many}); err != nil {
+		t.Fatalf("SetSkillTags(%d tags): %v", maxSkillTags, err)
+	}
+	_, invalidErr := s.users.AddSkillTags(ctx, &request.AddSkillTagsRequest{UserID: "n1", Tags: []string{"c sharp"}})
+	_, tooManyErr := s.users.AddSkillTags(ctx, &request.AddSkillTagsRequest{UserID: "n1", Tags: []string{"one-more"}})
+	_, requiredErr := s.prs.CreatePR(ctx, &request.CreatePRRequest{PullRequestID: "bad", PullRequestName: "bad", AuthorID: "author", RequiredTags: []string{""}})
+	_, memberErr := s.teams.CreateTeam(ctx, &request.CreateTeamRequest{TeamName: "bad", Members: []request.TeamMemberRequest{tagged(active("bad"), "-go")}})
+	var validationErr *pkgerrors.ValidationError
+	for name, err := range map[string]error{"invalid tag": invalidErr, "too many tags": tooManyErr, "required tags": requiredErr, "member tags": memberErr} {
+		if !errors.As(err, &validationErr) {
+			t.Errorf("%s: error = %v, want a validation error", name, err)
+		}
+	}
+	if _, err := s.users.AddSkillTags(ctx, &request.AddSkillTagsRequest{UserID: "ghost", Tags: []string{"go"}}); !errors.Is(err, pkgerrors.ErrUserNotFound) {
+		t.Errorf("unknown user error = %v, want ErrUserNotFound", err)
+	}
+}
+
+func TestSeniorityPolicy(t *testing.T) {
+	s := newServices()
+	ctx := context.Background()
+
+	leveled := func(member request.TeamMemberRequest, seniority string) request.TeamMemberRequest {
+		member.Seniority = seniority
+		return member
+	}
+	mustCreateTeam(t, s, "backend", leveled(active("author"), "senior"), leveled(active("j1"), "junior"), leveled(active("j2"), "junior"),
+		active("j3"), leveled(active("s1"), "senior"), leveled(active("s2"), "senior"))
+	policy, err := s.teams.SetSeniorityPolicy(ctx, &request.SetSeniorityPolicyRequest{TeamName: "backend", MinReviewerSeniority: "middle"})
+	if err != nil || policy.MinReviewerSeniority != "middle" {
+		t.Fatalf("SetSeniorityPolicy = %+v, %v", policy, err)
+	}
+	team, err := s.teams.GetTeam(ctx, "backend")
+	if err != nil || team.MinReviewerSeniority != "middle" {
+		t.Fatalf("GetTeam = %+v, %v; want the policy", team, err)
+	}
+
+	senior := map[string]bool{"s1": true, "s2": true}
+	for i := 0; i < 20; i++ {
+		prID := fmt.Sprintf("pr-%d", i)
+		reviewers := mustCreatePR(t, s, prID, "author")
+		if len(reviewers) != 2 || (!senior[reviewers[0]] && !senior[reviewers[1]]) {
+			t.Fatalf("reviewers = %v, want a senior among two", reviewers)
+		}
+		if senior[reviewers[0]] && senior[reviewers[1]] {
+			continue
+		}
+
+		// The only senior reviewer is replaced by the other senior, never by a junior
+		seniorID, otherSenior := reviewers[0], "s2"
+		if !senior[seniorID] {
+			seniorID = reviewers[1]
+		}
+		if seniorID == "s2" {
+			otherSenior = "s1"
+		}
+		resp, err := s.prs.ReassignReviewer(ctx, &request.ReassignReviewerRequest{PullRequestID: prID, OldUserID: seniorID})
+		if err != nil || resp.ReplacedBy != otherSenior {
+			t.Fatalf("ReassignReviewer(%s) = %+v, %v; want %s", seniorID, resp, err, otherSenior)
+		}
+	}
+
+	// With s2 away s1 is the only senior left: they can only be replaced while another senior reviews the PR
+	if _, err := s.users.SetUserActive(ctx, &request.SetUserActiveRequest{UserID: "s2", IsActive: false}); err != nil {
+		t.Fatalf("SetUserActive: %v", err)
+	}
+	reviewers := mustCreatePR(t, s, "lonely", "author")
+	if len(reviewers) != 2 || reviewers[0] != "s1" {
+		t.Fatalf("reviewers = %v, want s1 first", reviewers)
+	}
+	_, err = s.prs.ReassignReviewer(ctx, &request.ReassignReviewerRequest{PullRequestID: "lonely", OldUserID: "s1"})
+	if !errors.Is(err, pkgerrors.ErrSeniorityRequired) {
+		t.Fatalf("ReassignReviewer(s1) error = %v, want ErrSeniorityRequired", err)
+	}
+	if resp, err := s.prs.ReassignReviewer(ctx, &request.ReassignReviewerRequest{PullRequestID: "lonely", OldUserID: reviewers[1]}); err != nil || senior[resp.ReplacedBy] {
+		t.Fatalf("ReassignReviewer(%s) = %+v, %v; want a junior", reviewers[1], resp, err)
+	}
+
+	// Without anyone senior enough the PR is still created; a senior who comes back is preferred on reassignment
+	if _, err := s.users.SetUserActive(ctx, &request.SetUserActiveRequest{UserID: "s1", IsActive: false}); err != nil {
+		t.Fatalf("SetUserActive: %v", err)
+	}
+	reviewers = mustCreatePR(t, s, "juniors", "author")
+	if len(reviewers) != 2 || senior[reviewers[0]] || senior[reviewers[1]] {
+		t.Fatalf("reviewers = %v, want two juniors", reviewers)
+	}
+	if _, err := s.users.SetUserActive(ctx, &request.SetUserActiveRequest{UserID: "s1", IsActive: true}); err != nil {
+		t.Fatalf("SetUserActive: %v", err)
+	}
+	if resp, err := s.prs.ReassignReviewer(ctx, &request.ReassignReviewerRequest{PullRequestID: "juniors", OldUserID: reviewers[0]}); err != nil || resp.ReplacedBy != "s1" {
+		t.Fatalf("ReassignReviewer(%s) = %+v, %v; want s1", reviewers[0], resp, err)
+	}
+
+	// Users without a level never meet a policy
+	if _, err := s.teams.SetSeniorityPolicy(ctx, &request.SetSeniorityPolicyRequest{TeamName: "backend", MinReviewerSeniority: "junior"}); err != nil {
+		t.Fatalf("SetSeniorityPolicy: %v", err)
+	}
+	user, err := s.users.SetSeniority(ctx, &request.SetSeniorityRequest{UserID: "j1"})
+	if err != nil || user.User.Seniority != "" {
+		t.Fatalf("SetSeniority(j1) = %+v, %v; want no level", user, err)
+	}
+	for _, id := range []string{"j2", "s1"} {
+		if _, err := s.users.SetUserActive(ctx, &request.SetUserActiveRequest{UserID: id, IsActive: false}); err != nil {
+			t.Fatalf("SetUserActive: %v", err)
+		}
+	}
+	reviewers = mustCreatePR(t, s, "unleveled", "author")
+	if fmt.Sprint(reviewers) != "[j1 j3]" && fmt.Sprint(reviewers) != "[j3 j1]" {
+		t.Fatalf("reviewers = %v, want j1 and j3", reviewers)
+	}
+	user, err = s.users.SetSeniority(ctx, &request.SetSeniorityRequest{UserID: "j3", Seniority: "middle"})
+	if err != nil || user.User.Seniority != "middle" {
+		t.Fatalf("SetSeniority(j3) = %+v, %v; want middle", user, err)
+	}
+
+	var validationErr *pkgerrors.ValidationError
+	if _, err := s.users.SetSeniority(ctx, &request.SetSeniorityRequest{UserID: "j1", Seniority: "lead"}); !errors.As(err, &validationErr) {
+		t.Errorf("SetSeniority(lead) error = %v, want a validation error", err)
+	}
+	if _, err := s.teams.SetSeniorityPolicy(ctx, &request.SetSeniorityPolicyRequest{TeamName: "backend", MinReviewerSeniority: "Senior"}); !errors.As(err, &validationErr) {
+		t.Errorf("SetSeniorityPolicy(Senior) error = %v, want a validation error", err)
+	}
+	if _, err := s.teams.CreateTeam(ctx, &request.CreateTeamRequest{TeamName: "bad", Members: []request.TeamMemberRequest{leveled(active("bad"), "staff")}}); !errors.As(err, &validationErr) {
+		t.Errorf("CreateTeam error = %v, want a validation error", err)
+	}
+	if _, err := s.teams.SetSeniorityPolicy(ctx, &request.SetSeniorityPolicyRequest{TeamName: "ghost", MinReviewerSeniority: "senior"}); !errors.Is(err, pkgerrors.ErrTeamNotFound) {
+		t.Errorf("unknown team error = %v, want ErrTeamNotFound", err)
+	}
+	if _, err := s.users.SetSeniority(ctx, &request.SetSeniorityRequest{UserID: "ghost", Seniority: "senior"}); !errors.Is(err, pkgerrors.ErrUserNotFound) {
+		t.Errorf("unknown user error = %v, want ErrUserNotFound", err)
+	}
+}
+
+func TestAffinities(t *testing.T) {
+	s := newServices()
+	ctx := context.Background()
+
+	mustCreateTeam(t, s, "backend", active("author"), active("c1"), active("c2"), active("fav"), active("n1"), active("n2"), active("n3"))
+	for _, req := range []request.SetAffinityRequest{
+		{AuthorID: "author", ReviewerID: "c1", Conflict: true, Reason: "same household"},
+		{AuthorID: "c2", ReviewerID: "author", Conflict: true},
+		{AuthorID: "author", ReviewerID: "fav", Weight: 10, Reason: "knows the codebase"},
+		{AuthorID: "author", ReviewerID: "n1", Weight: 0},
+	} {
+		if _, err := s.users.SetAffinity(ctx, &req); err != nil {
+			t.Fatalf("SetAffinity(%s, %s): %v", req.AuthorID, req.ReviewerID, err)
+		}
+	}
+
+	// Conflicts are never assigned, whichever side set them; the preferred reviewer is picked
+	// 11 times as often as a neutral one, so they almost always make it into a pair of four
+	picked := 0
+	for i := 0; i < 40; i++ {
+		reviewers := mustCreatePR(t, s, fmt.Sprintf("pr-%d", i), "author")
+		for _, id := range reviewers {
+			if id == "c1" || id == "c2" {
+				t.Fatalf("reviewers = %v, want no conflict of interest", reviewers)
+			}
+			if id == "fav" {
+				picked++
+			}
+		}
+	}
+	if picked < 30 {
+		t.Errorf("fav reviewed %d of 40 PRs, want at least 30", picked)
+	}
+	for i := 0; i < 10; i++ {
+		reviewers := mustCreatePR(t, s, fmt.Sprintf("by-c2-%d", i), "c2")
+		if len(reviewers) != 2 || reviewers[0] == "author" || reviewers[1] == "author" {
+			t.Fatalf("reviewers = %v, want two reviewers other than author", reviewers)
+		}
+	}
+
+	// Reassignment skips conflicts too
+	for _, id := range []string{"n2", "n3"} {
+		if _, err := s.users.SetUserActive(ctx, &request.SetUserActiveRequest{UserID: id, IsActive: false}); err != nil {
+			t.Fatalf("SetUserActive: %v", err)
+		}
+	}
+	reviewers := mustCreatePR(t, s, "pair", "author")
+	if fmt.Sprint(reviewers) != "[fav n1]" && fmt.Sprint(reviewers) != "[n1 fav]" {
+		t.Fatalf("reviewers = %v, want fav and n1", reviewers)
+	}
+	_, err := s.prs.ReassignReviewer(ctx, &request.ReassignReviewerRequest{PullRequestID: "pair", OldUserID: "fav"})
+	if !errors.Is(err, pkgerrors.ErrNoCandidates) {
+		t.Fatalf("ReassignReviewer error = %v, want ErrNoCandidates", err)
+	}
+	if _, err := s.users.DeleteAffinity(ctx, &request.DeleteAffinityRequest{AuthorID: "author", ReviewerID: "c1"}); err != nil {
+		t.Fatalf("DeleteAffinity: %v", err)
+	}
+	if resp, err := s.prs.ReassignReviewer(ctx, &request.ReassignReviewerRequest{PullRequestID: "pair", OldUserID: "fav"}); err != nil || resp.ReplacedBy != "c1" {
+		t.Fatalf("ReassignReviewer = %+v, %v; want c1 once the conflict is gone", resp, err)
+	}
+
+	list, err := s.users.ListAffinities(ctx, "author")
+	if err != nil || len(list.Affinities) != 3 {
+		t.Fatalf("ListAffinities = %+v, %v; want 3 affinities", list, err)
+	}
+	if first := list.Affinities[0]; first.AuthorID != "author" || first.ReviewerID != "fav" || first.Weight != 10 || first.Reason != "knows the codebase" {
+		t.Errorf("first affinity = %+v, want author to fav", first)
+	}
+	if last := list.Affinities[2]; last.AuthorID != "c2" || !last.Conflict {
+		t.Errorf("last affinity = %+v, want c2 to author", last)
+	}
+
+	var validationErr *pkgerrors.ValidationError
+	if _, err := s.users.SetAffinity(ctx, &request.SetAffinityRequest{AuthorID: "author", ReviewerID: "author", Weight: 1}); !errors.As(err, &validationErr) {
+		t.Errorf("SetAffinity(self) error = %v, want a validation error", err)
+	}
+	if _, err := s.users.SetAffinity(ctx, &request.SetAffinityRequest{AuthorID: "author", ReviewerID: "n2", Weight: 11}); !errors.As(err, &validationErr) {
+		t.Errorf("SetAffinity(11) error = %v, want a validation error", err)
+	}
+	if _, err := s.users.SetAffinity(ctx, &request.SetAffinityRequest{AuthorID: "author", ReviewerID: "n2", Weight: -3, Conflict: true}); !errors.As(err, &validationErr) {
+		t.Errorf("SetAffinity(conflict with weight) error = %v, want a validation error", err)
+	}
+	if _, err := s.users.SetAffinity(ctx, &request.SetAffinityRequest{AuthorID: "author", ReviewerID: "ghost", Weight: 1}); !errors.Is(err, pkgerrors.ErrUserNotFound) {
+		t.Errorf("unknown reviewer error = %v, want ErrUserNotFound", err)
+	}
+	if _, err := s.users.DeleteAffinity(ctx, &request.DeleteAffinityRequest{AuthorID: "author", ReviewerID: "c1"}); !errors.Is(err, pkgerrors.ErrAffinityNotFound) {
+		t.Errorf("second DeleteAffinity error = %v, want ErrAffinityNotFound", err)
+	}
+	if _, err := s.users.ListAffinities(ctx, "ghost"); !errors.Is(err, pkgerrors.ErrUserNotFound) {
+		t.Errorf("unknown user error = %v, want ErrUserNotFound", err)
+	}
+}
+
+func TestNegativeAffinityMakesReviewerLessLikely(t *testing.T) {
+	s := newServices()
+	ctx := context.Background()
+
+	mustCreateTeam(t, s, "backend", active("author"), active("low"), active("n1"), active("n2"), active("n3"))
+	if _, err := s.users.SetAffinity(ctx, &request.SetAffinityRequest{AuthorID: "author", ReviewerID: "low", Weight: -10}); err != nil {
+		t.Fatalf("SetAffinity: %v", err)
+	}
+
+	// The down-weighted reviewer is 11 times less likely than a neutral one, which makes about
+	// 7 of 100 PRs, but is not excluded the way a conflict of interest is
+	picked := 0
+	for i := 0; i < 100; i++ {
+		for _, id := range mustCreatePR(t, s, fmt.Sprintf("pr-%d", i), "author") {
+			if id == "low" {
+				picked++
+			}
+		}
+	}
+	if picked == 0 || picked > 25 {
+		t.Errorf("low reviewed %d of 100 PRs, want a few", picked)
+	}
+}
+
+func TestServicesRecordEvents(t *testing.T) {
+	s := newServices()
+	ctx := context.Background()
+	mustCreateTeam(t, s, "backend", active("author"), active("u1"), active("u2"), active("u3"))
+	assertEvents(t, s.events(t), models.EventUserCreated, models.EventUserCreated, models.EventUserCreated, models.EventUserCreated)
+
+	reviewers := mustCreatePR(t, s, "pr-1", "author")
+	assertEvents(t, s.events(t), models.EventPRCreated, models.EventReviewerAssigned, models.EventReviewerAssigned)
+
+	if _, err := s.users.SetUserActive(ctx, &request.SetUserActiveRequest{UserID: reviewers[0], IsActive: false}); err != nil {
+		t.Fatalf("SetUserActive: %v", err)
+	}
+	assertEvents(t, s.events(t), models.EventUserDeactivated)
+
+	// Deactivating an inactive user is not a transition
+	if _, err := s.users.SetUserActive(ctx, &request.SetUserActiveRequest{UserID: reviewers[0], IsActive: false}); err != nil {
+		t.Fatalf("SetUserActive: %v", err)
+	}
+	assertEvents(t, s.events(t))
+
+	if _, err := s.users.SetUserActive(ctx, &request.SetUserActiveRequest{UserID: reviewers[0], IsActive: true}); err != nil {
+		t.Fatalf("SetUserActive: %v", err)
+	}
+	assertEvents(t, s.events(t), models.EventUserActivated)
+
+	if _, err := s.prs.ReassignReviewer(ctx, &request.ReassignReviewerRequest{PullRequestID: "pr-1", OldUserID: reviewers[1]}); err != nil {
+		t.Fatalf("ReassignReviewer: %v", err)
+	}
+	assertEvents(t, s.events(t), models.EventReviewerUnassigned, models.EventReviewerAssigned)
+
+	for range 2 {
+		if _, err := s.prs.MergePR(ctx, &request.MergePRRequest{PullRequestID: "pr-1"}); err != nil {
+			t.Fatalf("MergePR: %v", err)
+		}
+	}
+	assertEvents(t, s.events(t), models.EventPRMerged)
+}
+
+func TestUserUpdatesRecordEvents(t *testing.T) {
+	s := newServices()
+	ctx := context.Background()
+	mustCreateTeam(t, s, "backend", active("u1"))
+	s.events(t)
+
+	updates := []struct {
+		fields []string
+		update func() error
+	}{
+		{[]string{"mention_handle"}, func() error {
+			_, err := s.users.SetMentionHandle(ctx, &request.SetMentionHandleRequest{UserID: "u1", MentionHandle: "U0ALICE"})
+			return err
+		}},
+		{[]string{"email"}, func() error {
+			_, err := s.users.SetEmail(ctx, &request.SetEmailRequest{UserID: "u1", Email: "alice@example.com"})
+			return err
+		}},
+		{[]string{"timezone", "work_start", "work_end"}, func() error {
+			_, err := s.users.SetWorkingHours(ctx, &request.SetWorkingHoursRequest{UserID: "u1", Timezone: "Europe/Moscow", WorkStart: "10:00", WorkEnd: "19:00"})
+			return err
+		}},
+		{[]string{"max_open_reviews"}, func() error {
+			_, err := s.users.SetMaxOpenReviews(ctx, &request.SetMaxOpenReviewsRequest{UserID: "u1", MaxOpenReviews: 3})
+			return err
+		}},
+		{[]string{"seniority"}, func() error {
+			_, err := s.users.SetSeniority(ctx, &request.SetSeniorityRequest{UserID: "u1", Seniority: "senior"})
+			return err
+		}},
+		{[]string{"skill_tags"}, func() error {
+			_, err := s.users.AddSkillTags(ctx, &request.AddSkillTagsRequest{UserID: "u1", Tags: []string{"go"}})
+			return err
+		}},
+		{[]string{"skill_tags"}, func() error {
+			_, err := s.users.RemoveSkillTags(ctx, &request.RemoveSkillTagsRequest{UserID: "u1", Tags: []string{"go"}})
+			return err
+		}},
+		{[]string{"skill_tags"}, func() error {
+			_, err := s.users.SetSkillTags(ctx, &request.SetSkillTagsRequest{UserID: "u1", Tags: []string{"sql"}})
+			return err
+		}},
+	}
+	for _, u := range updates {
+		if err := u.update(); err != nil {
+			t.Fatalf("update of %v: %v", u.fields, err)
+		}
+
+		messages, err := s.outbox.LockPending(ctx, time.Now(), 0)
+		if err != nil {
+			t.Fatalf("LockPending: %v", err)
+		}
+		if len(messages) != 1 || messages[0].EventType != models.EventUserUpdated {
+			t.Fatalf("update of %v recorded %d message(s), want one user.updated", u.fields, len(messages))
+		}
+		var event struct {
+			Data models.UserEventData `json:"data"`
+		}
+		if err := json.Unmarshal(messages[0].Payload, &event); err != nil {
+			t.Fatalf("payload: %v", err)
+		}
+		if event.Data.UserID != "u1" || event.Data.TeamName != "backend" || fmt.Sprint(event.Data.Fields) != fmt.Sprint(u.fields) {
+			t.Errorf("user.updated data = %+v, want u1 with fields %v", event.Data, u.fields)
+		}
+		if err := s.outbox.MarkPublished(ctx, messages[0].ID, time.Now()); err != nil {
+			t.Fatalf("MarkPublished: %v", err)
+		}
+	}
+
+	// A rejected update changes nothing and records nothing
+	if _, err := s.users.SetEmail(ctx, &request.SetEmailRequest{UserID: "ghost", Email: "ghost@example.com"}); !errors.Is(err, pkgerrors.ErrUserNotFound) {
+		t.Fatalf("SetEmail(ghost) error = %v, want ErrUserNotFound", err)
+	}
+	assertEvents(t, s.events(t))
+}
+
+func assertEvents(t *testing.T, got []models.EventType, want ...models.EventType) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("events = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("events = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestFailedOperationRecordsNoEvents(t *testing.T) {
+	s := newServices()
+	ctx := context.Background()
+	mustCreateTeam(t, s, "backend", active("author"), active("u1"))
+	mustCreatePR(t, s, "pr-1", "author")
+	s.events(t)
+
+	// The duplicate is rejected after reviewers were selected; nothing may leak into the outbox
+	_, err := s.prs.CreatePR(ctx, &request.CreatePRRequest{PullRequestID: "pr-1", PullRequestName: "dup", AuthorID: "author"})
+	if !errors.Is(err, pkgerrors.ErrPRExists) {
+		t.Fatalf("error = %v, want ErrPRExists", err)
+	}
+	assertEvents(t, s.events(t))
+}
+
+func TestLinkAccount(t *testing.T) {
+	s := newServices()
+	ctx := context.Background()
+
+	mustCreateTeam(t, s, "backend", active("u1"), active("u2"))
+
+	resp, err := s.integrations.LinkAccount(ctx, &request.LinkAccountRequest{Provider: "github", Login: "Octo-Dev", UserID: "u1"})
+	if err != nil {
+		t.Fatalf("LinkAccount: %v", err)
+	}
+	if resp.Account.Login != "octo-dev" {
+		t.Errorf("login = %s, want lowercased octo-dev", resp.Account.Login)
+	}
+
+	// Relinking moves the login to another user
+	if _, err := s.integrations.LinkAccount(ctx, &request.LinkAccountRequest{Provider: "github", Login: "octo-dev", UserID: "u2"}); err != nil {
+		t.Fatalf("LinkAccount (relink): %v", err)
+	}
+	list, err := s.integrations.ListAccounts(ctx, "github")
+	if err != nil {
+		t.Fatalf("ListAccounts: %v", err)
+	}
+	if len(list.Accounts) != 1 || list.Accounts[0].UserID != "u2" {
+		t.Errorf("accounts = %+v, want octo-dev linked to u2", list.Accounts)
+	}
+
+	_, err = s.integrations.LinkAccount(ctx, &request.LinkAccountRequest{Provider: "github", Login: "ghost", UserID: "missing"})
+	if !errors.Is(err, pkgerrors.ErrUserNotFound) {
+		t.Errorf("unknown user error = %v, want ErrUserNotFound", err)
+	}
+
+	_, err = s.integrations.LinkAccount(ctx, &request.LinkAccountRequest{Provider: "bitbucket", Login: "x", UserID: "u1"})
+	var validation *pkgerrors.ValidationError
+	if !errors.As(err, &validation) || validation.Field != "provider" {
+		t.Errorf("unknown provider error = %v, want validation error on provider", err)
+	}
+}
+
+func TestHandlePullRequestEvent(t *testing.T) {
+	s := newServices()
+	ctx := context.Background()
+
+	mustCreateTeam(t, s, "backend", active("u1"), active("u2"), active("u3"))
+	if _, err := s.integrations.LinkAccount(ctx, &request.LinkAccountRequest{Provider: "github", Login: "octo-dev", UserID: "u1"}); err != nil {
+		t.Fatalf("LinkAccount: %v", err)
+	}
+
+	event := func(action models.PRAction) *models.CodeHostPREvent {
+		return &models.CodeHostPREvent{
+			Provider:    models.CodeHostGitHub,
+			Action:      action,
+			Repository:  "octo-org/api",
+			Number:      7,
+			Title:       "Add caching",
+			AuthorLogin: "Octo-Dev",
+		}
+	}
+
+	steps := []struct {
+		action models.PRAction
+		result string
+	}{
+		{models.PRActionOpened, "created"},
+		// Redelivery of the same event
+		{models.PRActionOpened, "exists"},
+		{models.PRActionClosed, "ignored"},
+		{models.PRActionReopened, "exists"},
+		{models.PRActionMerged, "merged"},
+	}
+	for _, step := range steps {
+		resp, err := s.integrations.HandlePullRequestEvent(ctx, event(step.action))
+		if err != nil {
+			t.Fatalf("%s: %v", step.action, err)
+		}
+		if resp.Result != step.result {
+			t.Errorf("%s: result = %s, want %s", step.action, resp.Result, step.result)
+		}
+		if resp.PullRequestID != "github:octo-org/api#7" {
+			t.Errorf("%s: pull_request_id = %s", step.action, resp.PullRequestID)
+		}
+	}
+
+	reviews, err := s.users.GetUserReviews(ctx, "u2")
+	if err != nil {
+		t.Fatalf("GetUserReviews: %v", err)
+	}
+	if len(reviews.PullRequests) != 1 || reviews.PullRequests[0].Status != "MERGED" {
+		t.Errorf("reviews of u2 = %+v, want the merged PR", reviews.PullRequests)
+	}
+
+	// Merge of a PR opened before the integration was set up
+	unknown := event(models.PRActionMerged)
+	unknown.Number = 8
+	resp, err := s.integrations.HandlePullRequestEvent(ctx, unknown)
+	if err != nil || resp.Result != "ignored" {
+		t.Errorf("merge of unknown PR = %+v, %v; want ignored", resp, err)
+	}
+
+	// Author without a linked account
+	unlinked := event(models.PRActionOpened)
+	unlinked.Number = 9
+	unlinked.AuthorLogin = "stranger"
+	_, err = s.integrations.HandlePullRequestEvent(ctx, unlinked)
+	if !errors.Is(err, pkgerrors.ErrAccountNotLinked) {
+		t.Errorf("unlinked author error = %v, want ErrAccountNotLinked", err)
+	}
+}
+
+func TestHandlePullRequestEvent_GitLab(t *testing.T) {
+	s := newServices()
+	ctx := context.Background()
+
+	mustCreateTeam(t, s, "backend", active("u1"), active("u2"), active("u3"))
+	// The same login may belong to different people on different code hosts
+	for _, link := range []request.LinkAccountRequest{
+		{Provider: "github", Login: "dana", UserID: "u2"},
+		{Provider: "gitlab", Login: "Dana", UserID: "u1"},
+	} {
+		if _, err := s.integrations.LinkAccount(ctx, &link); err != nil {
+			t.Fatalf("LinkAccount(%s): %v", link.Provider, err)
+		}
+	}
+
+	event := &models.CodeHostPREvent{
+		Provider:    models.CodeHostGitLab,
+		Action:      models.PRActionOpened,
+		Repository:  "platform/api",
+		Number:      3,
+		Title:       "Extract selector",
+		AuthorLogin: "dana",
+	}
+	resp, err := s.integrations.HandlePullRequestEvent(ctx, event)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	if resp.Result != "created" || resp.PR.PullRequestID != "gitlab:platform/api!3" || resp.PR.AuthorID != "u1" {
+		t.Fatalf("open = %+v, want gitlab:platform/api!3 created by u1", resp.PR)
+	}
+
+	event.Action = models.PRActionMerged
+	resp, err = s.integrations.HandlePullRequestEvent(ctx, event)
+	if err != nil || resp.Result != "merged" || resp.PR.Status != "MERGED" {
+		t.Fatalf("merge = %+v, %v; want merged", resp, err)
+	}
+}
@@ -0,0 +1,216 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"avito-backend-trainee-assignment-autumn-2025/internal/domain/models"
+	"avito-backend-trainee-assignment-autumn-2025/internal/repository"
+	pkgerrors "avito-backend-trainee-assignment-autumn-2025/pkg/errors"
+	"avito-backend-trainee-assignment-autumn-2025/pkg/logger"
+)
+
+// ReviewSLAConfig controls the handling of overdue reviews
+type ReviewSLAConfig struct {
+	// DefaultSLA applies to teams without their own review SLA
+	DefaultSLA time.Duration
+	// BatchSize bounds the stale reviews loaded at once
+	BatchSize int
+}
+
+// ReviewSLAServiceImpl implements ReviewSLAService
+type ReviewSLAServiceImpl struct {
+	teamRepo   repository.TeamRepository
+	prRepo     repository.PRRepository
+	txManager  repository.TransactionManager
+	outboxRepo repository.OutboxRepository
+	// prService reassigns overdue reviews with the same rules as /pullRequest/reassign
+	prService *PRServiceImpl
+	config    ReviewSLAConfig
+}
+
+// NewReviewSLAService creates a new review SLA service
+func NewReviewSLAService(
+	teamRepo repository.TeamRepository,
+	prRepo repository.PRRepository,
+	txManager repository.TransactionManager,
+	outboxRepo repository.OutboxRepository,
+	prService *PRServiceImpl,
+	config ReviewSLAConfig,
+) *ReviewSLAServiceImpl {
+	if config.BatchSize <= 0 {
+		config.BatchSize = 100
+	}
+
+	return &ReviewSLAServiceImpl{
+		teamRepo:   teamRepo,
+		prRepo:     prRepo,
+		txManager:  txManager,
+		outboxRepo: outboxRepo,
+		prService:  prService,
+		config:     config,
+	}
+}
+
+// EnforceReviewSLA handles every active reviewer of an open PR who was assigned (or last reminded)
+// longer ago than their team's SLA, counted in working time of the team calendar. Depending on the
+// team's SLA action the reviewer is reminded, replaced by a random teammate or replaced by the team lead;
+// when nobody can take over, the reviewer is reminded instead
+func (s *ReviewSLAServiceImpl) EnforceReviewSLA(ctx context.Context, now time.Time) (int, error) {
+	teamNames, err := s.teamRepo.ListNames(ctx)
+	if err != nil {
+		logger.Error("Failed to list teams: %v", err)
+		return 0, err
+	}
+
+	handled := 0
+	for _, teamName := range teamNames {
+		team, err := s.teamRepo.GetByName(ctx, teamName)
+		if errors.Is(err, pkgerrors.ErrTeamNotFound) {
+			continue
+		}
+		if err != nil {
+			logger.Error("Failed to get team %s: %v", teamName, err)
+			return handled, err
+		}
+
+		n, err := s.enforceTeam(ctx, team, now)
+		handled += n
+		if err != nil {
+			return handled, err
+		}
+	}
+
+	if handled > 0 {
+		logger.Info("Handled %d overdue reviews", handled)
+	}
+	return handled, nil
+}
+
+// enforceTeam handles the overdue reviews of one team's members
+func (s *ReviewSLAServiceImpl) enforceTeam(ctx context.Context, team *models.Team, now time.Time) (int, error) {
+	sla := s.config.DefaultSLA
+	if team.ReviewSLAMinutes > 0 {
+		sla = time.Duration(team.ReviewSLAMinutes) * time.Minute
+	}
+	// Reviews assigned before this moment have spent at least the SLA in working time
+	overdueBefore := team.Calendar.SubtractWorkingTime(now, sla)
+
+	handled := 0
+	for {
+		reviews, err := s.prRepo.ListStaleReviews(ctx, team.Name, overdueBefore, s.config.BatchSize)
+		if err != nil {
+			logger.Error("Failed to list stale reviews of team %s: %v", team.Name, err)
+			return handled, err
+		}
+
+		for _, review := range reviews {
+			if err := ctx.Err(); err != nil {
+				return handled, err
+			}
+
+			err := s.handle(ctx, team, review, sla, now)
+			switch {
+			case err == nil:
+				handled++
+			case errors.Is(err, pkgerrors.ErrReviewerNotAssigned), errors.Is(err, pkgerrors.ErrPRMerged):
+				// Reassigned or merged since the list was loaded
+				logger.Debug("Review of PR %s by %s changed before the SLA was enforced", review.PullRequestID, review.ReviewerID)
+			default:
+				return handled, err
+			}
+		}
+
+		// Handled reviews drop out of the list, so a short batch means there are no more
+		if len(reviews) < s.config.BatchSize {
+			return handled, nil
+		}
+	}
+}
+
+// handle applies the team's SLA action to an overdue review
+func (s *ReviewSLAServiceImpl) handle(
+	ctx context.Context,
+	team *models.Team,
+	review models.StaleReview,
+	sla time.Duration,
+	now time.Time,
+) error {
+	var pick candidatePicker
+	switch team.SLAAction {
+	case models.SLAActionReassign:
+		timedOut, err := s.timedOutReviewers(ctx, review.PullRequestID)
+		if err != nil {
+			return err
+		}
+		pick = skipCandidates(timedOut, s.prService.pickRandomCandidate)
+	case models.SLAActionEscalate:
+		pick = pickLead(team.LeadID)
+	}
+
+	if pick != nil {
+		_, newReviewerID, err := s.prService.reassign(ctx, review.PullRequestID, review.ReviewerID, models.ReassignReasonSLATimeout, pick)
+		if err == nil {
+			logger.Info("Review of PR %s passed from %s to %s after the SLA of team %s (action: %s)",
+				review.PullRequestID, review.ReviewerID, newReviewerID, team.Name, team.SLAAction)
+			return nil
+		}
+		if !errors.Is(err, pkgerrors.ErrNoCandidates) && !errors.Is(err, pkgerrors.ErrAllAtCapacity) &&
+			!errors.Is(err, pkgerrors.ErrSeniorityRequired) {
+			return err
+		}
+		logger.Warn("Nobody can take over the review of PR %s from %s (action: %s), reminding instead",
+			review.PullRequestID, review.ReviewerID, team.SLAAction)
+	}
+
+	return s.remind(ctx, review, sla, now)
+}
+
+// timedOutReviewers returns the reviewers who already let the SLA of the PR run out,
+// so that a review is not handed back to someone who ignored it
+func (s *ReviewSLAServiceImpl) timedOutReviewers(ctx context.Context, prID string) (map[string]bool, error) {
+	reassignments, err := s.prRepo.ListReassignments(ctx, prID)
+	if err != nil {
+		logger.Error("Failed to list reassignments of PR %s: %v", prID, err)
+		return nil, err
+	}
+
+	timedOut := make(map[string]bool)
+	for _, reassignment := range reassignments {
+		if reassignment.Reason == models.ReassignReasonSLATimeout {
+			timedOut[reassignment.OldReviewerID] = true
+		}
+	}
+	return timedOut, nil
+}
+
+// skipCandidates returns a picker that chooses among the candidates not in skip
+func skipCandidates(skip map[string]bool, pick candidatePicker) candidatePicker {
+	return func(candidates []models.User, weights map[string]int) (string, error) {
+		remaining := make([]models.User, 0, len(candidates))
+		for _, candidate := range candidates {
+			if !skip[candidate.ID] {
+				remaining = append(remaining, candidate)
+			}
+		}
+		return pick(remaining, weights)
+	}
+}
+
+// remind records the reminder and its time in one transaction
+func (s *ReviewSLAServiceImpl) remind(ctx context.Context, review models.StaleReview, sla time.Duration, now time.Time) error {
+	return s.txManager.WithTransaction(ctx, func(txCtx context.Context) error {
+		if err := s.prRepo.MarkReviewReminded(txCtx, review.PullRequestID, review.ReviewerID, now); err != nil {
+			return err
+		}
+		return recordEvent(txCtx, s.outboxRepo, models.EventReviewReminder, models.ReviewReminderEventData{
+			PullRequestID:    review.PullRequestID,
+			PullRequestName:  review.PullRequestName,
+			AuthorID:         review.AuthorID,
+			ReviewerID:       review.ReviewerID,
+			AssignedAt:       review.AssignedAt,
+			ReviewSLAMinutes: int(sla / time.Minute),
+		})
+	})
+}
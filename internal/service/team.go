@@ -2,41 +2,53 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/url"
+	"strings"
+	"time"
 
+	"avito-backend-trainee-assignment-autumn-2025/internal/codeowners"
 	"avito-backend-trainee-assignment-autumn-2025/internal/domain/models"
-	"avito-backend-trainee-assignment-autumn-2025/internal/dto/request"
-	"avito-backend-trainee-assignment-autumn-2025/internal/dto/response"
 	"avito-backend-trainee-assignment-autumn-2025/internal/repository"
+	"avito-backend-trainee-assignment-autumn-2025/pkg/dto/request"
+	"avito-backend-trainee-assignment-autumn-2025/pkg/dto/response"
 	pkgerrors "avito-backend-trainee-assignment-autumn-2025/pkg/errors"
 	"avito-backend-trainee-assignment-autumn-2025/pkg/logger"
 )
 
 // TeamServiceImpl implements TeamService
 type TeamServiceImpl struct {
-	teamRepo  repository.TeamRepository
-	userRepo  repository.UserRepository
-	txManager repository.TransactionManager
+	teamRepo    repository.TeamRepository
+	userRepo    repository.UserRepository
+	absenceRepo repository.AbsenceRepository
+	txManager   repository.TransactionManager
+	outboxRepo  repository.OutboxRepository
 }
 
 // NewTeamService creates a new team service
 func NewTeamService(
 	teamRepo repository.TeamRepository,
 	userRepo repository.UserRepository,
+	absenceRepo repository.AbsenceRepository,
 	txManager repository.TransactionManager,
+	outboxRepo repository.OutboxRepository,
 ) *TeamServiceImpl {
 	return &TeamServiceImpl{
-		teamRepo:  teamRepo,
-		userRepo:  userRepo,
-		txManager: txManager,
+		teamRepo:    teamRepo,
+		userRepo:    userRepo,
+		absenceRepo: absenceRepo,
+		txManager:   txManager,
+		outboxRepo:  outboxRepo,
 	}
 }
 
 // CreateTeam creates a new team with members atomically
+// A user.created event is recorded for every member in the same transaction
 func (s *TeamServiceImpl) CreateTeam(ctx context.Context, req *request.CreateTeamRequest) (*response.CreateTeamResponse, error) {
 	// Validate input
 	if req.TeamName == "" {
-		return nil, fmt.Errorf("team name is required")
+		return nil, pkgerrors.NewRequiredFieldError("team_name")
 	}
 
 	logger.Info("Creating team: %s with %d members", req.TeamName, len(req.Members))
@@ -57,8 +69,10 @@ func (s *TeamServiceImpl) CreateTeam(ctx context.Context, req *request.CreateTea
 	err = s.txManager.WithTransaction(ctx, func(txCtx context.Context) error {
 		// Create team
 		team := &models.Team{
-			Name:    req.TeamName,
-			Members: []models.User{},
+			Name:      req.TeamName,
+			Members:   []models.User{},
+			SLAAction: models.SLAActionRemind,
+			Calendar:  models.DefaultCalendar(),
 		}
 
 		if err := s.teamRepo.Create(txCtx, team); err != nil {
@@ -67,26 +81,51 @@ func (s *TeamServiceImpl) CreateTeam(ctx context.Context, req *request.CreateTea
 		}
 
 		// Create all members
-		for _, memberReq := range req.Members {
+		for i, memberReq := range req.Members {
 			// Validate member
 			if memberReq.UserID == "" {
-				return fmt.Errorf("user_id is required for all members")
+				return pkgerrors.NewRequiredFieldError(fmt.Sprintf("members[%d].user_id", i))
 			}
 			if memberReq.Username == "" {
-				return fmt.Errorf("username is required for all members")
+				return pkgerrors.NewRequiredFieldError(fmt.Sprintf("members[%d].username", i))
+			}
+			if err := validateMentionHandle(fmt.Sprintf("members[%d].mention_handle", i), memberReq.MentionHandle); err != nil {
+				return err
+			}
+			if err := validateEmail(fmt.Sprintf("members[%d].email", i), memberReq.Email); err != nil {
+				return err
+			}
+			if err := validateMaxOpenReviews(fmt.Sprintf("members[%d].max_open_reviews", i), memberReq.MaxOpenReviews); err != nil {
+				return err
+			}
+			skillTags, err := normalizeTags(fmt.Sprintf("members[%d].skill_tags", i), memberReq.SkillTags, maxSkillTags)
+			if err != nil {
+				return err
+			}
+			seniority, err := parseSeniority(fmt.Sprintf("members[%d].seniority", i), memberReq.Seniority)
+			if err != nil {
+				return err
 			}
 
 			user := &models.User{
-				ID:       memberReq.UserID,
-				Username: memberReq.Username,
-				TeamName: req.TeamName,
-				IsActive: memberReq.IsActive,
+				ID:             memberReq.UserID,
+				Username:       memberReq.Username,
+				TeamName:       req.TeamName,
+				IsActive:       memberReq.IsActive,
+				MentionHandle:  memberReq.MentionHandle,
+				Email:          memberReq.Email,
+				MaxOpenReviews: memberReq.MaxOpenReviews,
+				SkillTags:      skillTags,
+				Seniority:      seniority,
 			}
 
 			if err := s.userRepo.Create(txCtx, user); err != nil {
 				logger.Error("Failed to create user %s for team %s: %v", user.ID, req.TeamName, err)
 				return fmt.Errorf("failed to create user %s: %w", user.ID, err)
 			}
+			if err := recordEvent(txCtx, s.outboxRepo, models.EventUserCreated, newUserEventData(user)); err != nil {
+				return err
+			}
 
 			team.Members = append(team.Members, *user)
 		}
@@ -111,7 +150,7 @@ func (s *TeamServiceImpl) CreateTeam(ctx context.Context, req *request.CreateTea
 func (s *TeamServiceImpl) GetTeam(ctx context.Context, teamName string) (*response.TeamResponse, error) {
 	// Validate input
 	if teamName == "" {
-		return nil, fmt.Errorf("team name is required")
+		return nil, pkgerrors.NewRequiredFieldError("team_name")
 	}
 
 	logger.Info("Retrieving team: %s", teamName)
@@ -123,26 +162,405 @@ func (s *TeamServiceImpl) GetTeam(ctx context.Context, teamName string) (*respon
 		return nil, err
 	}
 
+	// Current and upcoming absences; past ones are of no interest here
+	today := models.DateIn(time.Now(), team.Calendar.Location())
+	absences, err := s.absenceRepo.ListByTeam(ctx, teamName, today)
+	if err != nil {
+		logger.Error("Failed to get absences of team %s: %v", teamName, err)
+		return nil, fmt.Errorf("failed to get team absences: %w", err)
+	}
+
 	logger.Info("Successfully retrieved team %s with %d members", teamName, len(team.Members))
 
 	// Convert to response DTO
-	return convertTeamToResponsePtr(team), nil
+	resp := convertTeamToResponsePtr(team)
+	byUser := make(map[string][]response.AbsenceResponse)
+	for _, absence := range absences {
+		byUser[absence.UserID] = append(byUser[absence.UserID], convertAbsenceToResponse(&absence))
+	}
+	for i := range resp.Members {
+		resp.Members[i].Absences = byUser[resp.Members[i].UserID]
+	}
+	return resp, nil
+}
+
+// SetChatWebhook sets the Slack-compatible incoming webhook that receives the team's review notifications
+func (s *TeamServiceImpl) SetChatWebhook(ctx context.Context, req *request.SetChatWebhookRequest) (*response.SetChatWebhookResponse, error) {
+	// Validate input
+	if req.TeamName == "" {
+		return nil, pkgerrors.NewRequiredFieldError("team_name")
+	}
+	if req.WebhookURL != "" {
+		parsed, err := url.Parse(req.WebhookURL)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return nil, pkgerrors.NewValidationError("webhook_url", "must be an absolute http or https URL")
+		}
+	}
+
+	// The URL embeds a secret token, so only whether it is set gets logged
+	logger.Info("Setting chat webhook for team %s (enabled: %t)", req.TeamName, req.WebhookURL != "")
+
+	if err := s.teamRepo.SetChatWebhook(ctx, req.TeamName, req.WebhookURL); err != nil {
+		logger.Error("Failed to set chat webhook for team %s: %v", req.TeamName, err)
+		return nil, err
+	}
+
+	return &response.SetChatWebhookResponse{
+		TeamName:          req.TeamName,
+		ChatNotifications: req.WebhookURL != "",
+	}, nil
+}
+
+// maxReviewSLAMinutes caps a team's review SLA at 30 days
+const maxReviewSLAMinutes = 30 * 24 * 60
+
+// SetReviewSLA sets how long the team's reviewers have and what happens to a review that stays open longer
+func (s *TeamServiceImpl) SetReviewSLA(ctx context.Context, req *request.SetReviewSLARequest) (*response.SetReviewSLAResponse, error) {
+	// Validate input
+	if req.TeamName == "" {
+		return nil, pkgerrors.NewRequiredFieldError("team_name")
+	}
+	if req.ReviewSLAMinutes < 0 || req.ReviewSLAMinutes > maxReviewSLAMinutes {
+		return nil, pkgerrors.NewValidationError("review_sla_minutes", fmt.Sprintf("must be between 0 and %d", maxReviewSLAMinutes))
+	}
+	action := models.SLAAction(req.SLAAction)
+	if action == "" {
+		action = models.SLAActionRemind
+	}
+	if !action.IsValid() {
+		return nil, pkgerrors.NewValidationError("sla_action", "must be one of: remind, reassign, escalate")
+	}
+
+	// Escalation needs someone to escalate to
+	if action == models.SLAActionEscalate {
+		team, err := s.teamRepo.GetByName(ctx, req.TeamName)
+		if err != nil {
+			logger.Error("Failed to get team %s: %v", req.TeamName, err)
+			return nil, err
+		}
+		if team.LeadID == "" {
+			return nil, pkgerrors.NewValidationError("sla_action", "escalate requires a team lead, set it with /team/setLead")
+		}
+	}
+
+	logger.Info("Setting review SLA for team %s to %d minutes (action: %s)", req.TeamName, req.ReviewSLAMinutes, action)
+
+	if err := s.teamRepo.SetReviewSLA(ctx, req.TeamName, req.ReviewSLAMinutes, action); err != nil {
+		logger.Error("Failed to set review SLA for team %s: %v", req.TeamName, err)
+		return nil, err
+	}
+
+	return &response.SetReviewSLAResponse{
+		TeamName:         req.TeamName,
+		ReviewSLAMinutes: req.ReviewSLAMinutes,
+		SLAAction:        string(action),
+	}, nil
+}
+
+// SetLead sets the team lead who takes over escalated reviews; an empty lead_id removes the lead
+func (s *TeamServiceImpl) SetLead(ctx context.Context, req *request.SetLeadRequest) (*response.SetLeadResponse, error) {
+	// Validate input
+	if req.TeamName == "" {
+		return nil, pkgerrors.NewRequiredFieldError("team_name")
+	}
+
+	if req.LeadID != "" {
+		lead, err := s.userRepo.GetByID(ctx, req.LeadID)
+		if err != nil {
+			logger.Error("Failed to get user %s: %v", req.LeadID, err)
+			return nil, err
+		}
+		if lead.TeamName != req.TeamName {
+			// The team may not exist at all; report that first
+			if _, err := s.teamRepo.GetByName(ctx, req.TeamName); err != nil {
+				return nil, err
+			}
+			return nil, pkgerrors.NewValidationError("lead_id", "must be a member of the team")
+		}
+	}
+
+	logger.Info("Setting lead for team %s to %q", req.TeamName, req.LeadID)
+
+	if err := s.teamRepo.SetLead(ctx, req.TeamName, req.LeadID); err != nil {
+		logger.Error("Failed to set lead for team %s: %v", req.TeamName, err)
+		return nil, err
+	}
+
+	return &response.SetLeadResponse{
+		TeamName: req.TeamName,
+		LeadID:   req.LeadID,
+	}, nil
+}
+
+// SetCalendar sets the timezone and days off the team's review SLA is counted in
+func (s *TeamServiceImpl) SetCalendar(ctx context.Context, req *request.SetCalendarRequest) (*response.SetCalendarResponse, error) {
+	// Validate input
+	if req.TeamName == "" {
+		return nil, pkgerrors.NewRequiredFieldError("team_name")
+	}
+	calendar := models.TeamCalendar{Timezone: req.Timezone}
+	if calendar.Timezone == "" {
+		calendar.Timezone = "UTC"
+	}
+	if _, err := time.LoadLocation(calendar.Timezone); err != nil {
+		return nil, pkgerrors.NewValidationError("timezone", "must be an IANA time zone name, e.g. Europe/Moscow")
+	}
+	for i, name := range req.WeekendDays {
+		day, ok := parseWeekday(name)
+		if !ok {
+			return nil, pkgerrors.NewValidationError(fmt.Sprintf("weekend_days[%d]", i), "must be a day name, e.g. saturday")
+		}
+		calendar.Weekend |= models.NewWeekdaySet(day)
+	}
+	if !calendar.IsValid() {
+		return nil, pkgerrors.NewValidationError("weekend_days", "at least one day of the week must be a working day")
+	}
+
+	logger.Info("Setting calendar for team %s (timezone: %s, weekend: %v)", req.TeamName, calendar.Timezone, calendar.Weekend.Days())
+
+	if err := s.teamRepo.SetCalendar(ctx, req.TeamName, calendar); err != nil {
+		logger.Error("Failed to set calendar for team %s: %v", req.TeamName, err)
+		return nil, err
+	}
+
+	return &response.SetCalendarResponse{
+		TeamName: req.TeamName,
+		Calendar: convertCalendarToResponse(calendar),
+	}, nil
+}
+
+// maxPreferWithinHours caps how far ahead a working day may start to count as inside working hours
+const maxPreferWithinHours = 7 * 24
+
+// SetWorkingHoursPreference sets whether reviewers inside their working hours are picked first
+func (s *TeamServiceImpl) SetWorkingHoursPreference(
+	ctx context.Context,
+	req *request.SetWorkingHoursPreferenceRequest,
+) (*response.SetWorkingHoursPreferenceResponse, error) {
+	// Validate input
+	if req.TeamName == "" {
+		return nil, pkgerrors.NewRequiredFieldError("team_name")
+	}
+	if req.WithinHours < 0 || req.WithinHours > maxPreferWithinHours {
+		return nil, pkgerrors.NewValidationError("within_hours", fmt.Sprintf("must be between 0 and %d", maxPreferWithinHours))
+	}
+
+	logger.Info("Setting working hours preference for team %s (enabled: %t, within: %dh)",
+		req.TeamName, req.PreferWorkingHours, req.WithinHours)
+
+	if err := s.teamRepo.SetWorkingHoursPreference(ctx, req.TeamName, req.PreferWorkingHours, req.WithinHours); err != nil {
+		logger.Error("Failed to set working hours preference for team %s: %v", req.TeamName, err)
+		return nil, err
+	}
+
+	return &response.SetWorkingHoursPreferenceResponse{
+		TeamName:           req.TeamName,
+		PreferWorkingHours: req.PreferWorkingHours,
+		PreferWithinHours:  req.WithinHours,
+	}, nil
+}
+
+// SetSeniorityPolicy sets the level at least one reviewer of each PR must have; an empty level removes the policy
+func (s *TeamServiceImpl) SetSeniorityPolicy(
+	ctx context.Context,
+	req *request.SetSeniorityPolicyRequest,
+) (*response.SetSeniorityPolicyResponse, error) {
+	// Validate input
+	if req.TeamName == "" {
+		return nil, pkgerrors.NewRequiredFieldError("team_name")
+	}
+	level, err := parseSeniority("min_reviewer_seniority", req.MinReviewerSeniority)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("Setting seniority policy for team %s to %q", req.TeamName, level)
+
+	if err := s.teamRepo.SetSeniorityPolicy(ctx, req.TeamName, level); err != nil {
+		logger.Error("Failed to set seniority policy for team %s: %v", req.TeamName, err)
+		return nil, err
+	}
+
+	return &response.SetSeniorityPolicyResponse{
+		TeamName:             req.TeamName,
+		MinReviewerSeniority: string(level),
+	}, nil
+}
+
+// UploadCodeOwners validates a CODEOWNERS file and, unless dryRun is set, replaces the team's rules with it
+func (s *TeamServiceImpl) UploadCodeOwners(
+	ctx context.Context,
+	teamName string,
+	dryRun bool,
+	file []byte,
+) (*response.UploadCodeOwnersResponse, error) {
+	// Validate input
+	if teamName == "" {
+		return nil, pkgerrors.NewRequiredFieldError("team_name")
+	}
+
+	exists, err := s.teamRepo.Exists(ctx, teamName)
+	if err != nil {
+		logger.Error("Failed to check team existence %s: %v", teamName, err)
+		return nil, err
+	}
+	if !exists {
+		return nil, pkgerrors.ErrTeamNotFound
+	}
+
+	rules, err := codeowners.Parse(file)
+	if err != nil {
+		logger.Warn("Rejected CODEOWNERS of team %s: %v", teamName, err)
+		return nil, err
+	}
+	if err := s.checkCodeOwners(ctx, rules); err != nil {
+		logger.Warn("Rejected CODEOWNERS of team %s: %v", teamName, err)
+		return nil, err
+	}
+
+	if dryRun {
+		logger.Info("Validated CODEOWNERS of team %s (%d rules)", teamName, len(rules))
+	} else {
+		// Replace the rules in a transaction so a failed upload keeps the previous ones
+		err = s.txManager.WithTransaction(ctx, func(txCtx context.Context) error {
+			return s.teamRepo.ReplaceCodeOwners(txCtx, teamName, rules)
+		})
+		if err != nil {
+			logger.Error("Failed to store CODEOWNERS of team %s: %v", teamName, err)
+			return nil, err
+		}
+	}
+
+	return &response.UploadCodeOwnersResponse{
+		TeamName: teamName,
+		DryRun:   dryRun,
+		Rules:    convertCodeOwnerRulesToResponse(rules),
+	}, nil
+}
+
+// checkCodeOwners makes sure every owner named in rules exists
+func (s *TeamServiceImpl) checkCodeOwners(ctx context.Context, rules []models.CodeOwnerRule) error {
+	checked := make(map[models.CodeOwner]bool)
+	for _, rule := range rules {
+		for _, owner := range rule.Owners {
+			if checked[owner] {
+				continue
+			}
+			checked[owner] = true
+
+			if owner.TeamName != "" {
+				exists, err := s.teamRepo.Exists(ctx, owner.TeamName)
+				if err != nil {
+					return err
+				}
+				if !exists {
+					return pkgerrors.NewValidationError("codeowners", fmt.Sprintf("line %d: unknown team %s", rule.Line, owner))
+				}
+				continue
+			}
+			if _, err := s.userRepo.GetByID(ctx, owner.UserID); errors.Is(err, pkgerrors.ErrUserNotFound) {
+				return pkgerrors.NewValidationError("codeowners", fmt.Sprintf("line %d: unknown user %s", rule.Line, owner))
+			} else if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// GetCodeOwners returns the team's CODEOWNERS rules in file order
+func (s *TeamServiceImpl) GetCodeOwners(ctx context.Context, teamName string) (*response.GetCodeOwnersResponse, error) {
+	// Validate input
+	if teamName == "" {
+		return nil, pkgerrors.NewRequiredFieldError("team_name")
+	}
+
+	exists, err := s.teamRepo.Exists(ctx, teamName)
+	if err != nil {
+		logger.Error("Failed to check team existence %s: %v", teamName, err)
+		return nil, err
+	}
+	if !exists {
+		return nil, pkgerrors.ErrTeamNotFound
+	}
+
+	rules, err := s.teamRepo.ListCodeOwners(ctx, teamName)
+	if err != nil {
+		logger.Error("Failed to get CODEOWNERS of team %s: %v", teamName, err)
+		return nil, err
+	}
+
+	return &response.GetCodeOwnersResponse{
+		TeamName: teamName,
+		Rules:    convertCodeOwnerRulesToResponse(rules),
+	}, nil
+}
+
+// convertCodeOwnerRulesToResponse converts CODEOWNERS rules to their DTOs, owners written as in the file
+func convertCodeOwnerRulesToResponse(rules []models.CodeOwnerRule) []response.CodeOwnerRuleResponse {
+	result := make([]response.CodeOwnerRuleResponse, len(rules))
+	for i, rule := range rules {
+		owners := make([]string, len(rule.Owners))
+		for j, owner := range rule.Owners {
+			owners[j] = owner.String()
+		}
+		result[i] = response.CodeOwnerRuleResponse{Line: rule.Line, Pattern: rule.Pattern, Owners: owners}
+	}
+	return result
+}
+
+// parseWeekday parses an English day name, case-insensitively
+func parseWeekday(name string) (time.Weekday, bool) {
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		if strings.EqualFold(name, day.String()) {
+			return day, true
+		}
+	}
+	return 0, false
+}
+
+// convertCalendarToResponse converts a TeamCalendar model to CalendarResponse DTO
+func convertCalendarToResponse(calendar models.TeamCalendar) response.CalendarResponse {
+	days := make([]string, 0, 7)
+	for _, day := range calendar.Weekend.Days() {
+		days = append(days, strings.ToLower(day.String()))
+	}
+	return response.CalendarResponse{
+		Timezone:    calendar.Timezone,
+		WeekendDays: days,
+	}
 }
 
 // convertTeamToResponse converts a Team model to TeamResponse DTO
 func convertTeamToResponse(team *models.Team) response.TeamResponse {
 	members := make([]response.TeamMemberResponse, 0, len(team.Members))
 	for _, member := range team.Members {
+		workStart, workEnd := formatWorkingHours(member.WorkingHours)
 		members = append(members, response.TeamMemberResponse{
-			UserID:   member.ID,
-			Username: member.Username,
-			IsActive: member.IsActive,
+			UserID:         member.ID,
+			Username:       member.Username,
+			IsActive:       member.IsActive,
+			MentionHandle:  member.MentionHandle,
+			Email:          member.Email,
+			Timezone:       member.Timezone,
+			WorkStart:      workStart,
+			WorkEnd:        workEnd,
+			MaxOpenReviews: member.MaxOpenReviews,
+			SkillTags:      member.SkillTags,
+			Seniority:      string(member.Seniority),
 		})
 	}
 
 	return response.TeamResponse{
-		TeamName: team.Name,
-		Members:  members,
+		TeamName:             team.Name,
+		Members:              members,
+		ReviewSLAMinutes:     team.ReviewSLAMinutes,
+		SLAAction:            string(team.SLAAction),
+		LeadID:               team.LeadID,
+		Calendar:             convertCalendarToResponse(team.Calendar),
+		PreferWorkingHours:   team.PreferWorkingHours,
+		PreferWithinHours:    team.PreferWithinHours,
+		MinReviewerSeniority: string(team.MinReviewerSeniority),
 	}
 }
 
@@ -3,28 +3,50 @@ package service
 import (
 	"context"
 	"fmt"
+	"net/mail"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
 
 	"avito-backend-trainee-assignment-autumn-2025/internal/domain/models"
-	"avito-backend-trainee-assignment-autumn-2025/internal/dto/request"
-	"avito-backend-trainee-assignment-autumn-2025/internal/dto/response"
+	"avito-backend-trainee-assignment-autumn-2025/internal/ical"
 	"avito-backend-trainee-assignment-autumn-2025/internal/repository"
+	"avito-backend-trainee-assignment-autumn-2025/pkg/dto/request"
+	"avito-backend-trainee-assignment-autumn-2025/pkg/dto/response"
+	pkgerrors "avito-backend-trainee-assignment-autumn-2025/pkg/errors"
 	"avito-backend-trainee-assignment-autumn-2025/pkg/logger"
 )
 
 // UserServiceImpl implements UserService
 type UserServiceImpl struct {
-	userRepo repository.UserRepository
-	prRepo   repository.PRRepository
+	userRepo     repository.UserRepository
+	teamRepo     repository.TeamRepository
+	prRepo       repository.PRRepository
+	absenceRepo  repository.AbsenceRepository
+	affinityRepo repository.AffinityRepository
+	txManager    repository.TransactionManager
+	outboxRepo   repository.OutboxRepository
 }
 
 // NewUserService creates a new user service
 func NewUserService(
 	userRepo repository.UserRepository,
+	teamRepo repository.TeamRepository,
 	prRepo repository.PRRepository,
+	absenceRepo repository.AbsenceRepository,
+	affinityRepo repository.AffinityRepository,
+	txManager repository.TransactionManager,
+	outboxRepo repository.OutboxRepository,
 ) *UserServiceImpl {
 	return &UserServiceImpl{
-		userRepo: userRepo,
-		prRepo:   prRepo,
+		userRepo:     userRepo,
+		teamRepo:     teamRepo,
+		prRepo:       prRepo,
+		absenceRepo:  absenceRepo,
+		affinityRepo: affinityRepo,
+		txManager:    txManager,
+		outboxRepo:   outboxRepo,
 	}
 }
 
@@ -32,22 +54,46 @@ func NewUserService(
 func (s *UserServiceImpl) SetUserActive(ctx context.Context, req *request.SetUserActiveRequest) (*response.SetUserActiveResponse, error) {
 	// Validate input
 	if req.UserID == "" {
-		return nil, fmt.Errorf("user_id is required")
+		return nil, pkgerrors.NewRequiredFieldError("user_id")
 	}
 
 	logger.Info("Setting user %s active status to %t", req.UserID, req.IsActive)
 
-	// Update user active status
-	err := s.userRepo.SetActive(ctx, req.UserID, req.IsActive)
-	if err != nil {
-		logger.Error("Failed to set active status for user %s: %v", req.UserID, err)
-		return nil, err
-	}
+	// Update the status and record the transition in a transaction
+	var user *models.User
+	err := s.txManager.WithTransaction(ctx, func(txCtx context.Context) error {
+		// Get current state to detect deactivation
+		previous, err := s.userRepo.GetByID(txCtx, req.UserID)
+		if err != nil {
+			logger.Error("Failed to get user %s: %v", req.UserID, err)
+			return err
+		}
+
+		// Update user active status
+		if err := s.userRepo.SetActive(txCtx, req.UserID, req.IsActive); err != nil {
+			logger.Error("Failed to set active status for user %s: %v", req.UserID, err)
+			return err
+		}
+
+		// Get updated user to return in response
+		user, err = s.userRepo.GetByID(txCtx, req.UserID)
+		if err != nil {
+			logger.Error("Failed to get user %s after updating: %v", req.UserID, err)
+			return err
+		}
+
+		// Subscribers are notified only when the status actually changes
+		switch {
+		case previous.IsActive && !user.IsActive:
+			return recordEvent(txCtx, s.outboxRepo, models.EventUserDeactivated, newUserEventData(user))
+		case !previous.IsActive && user.IsActive:
+			return recordEvent(txCtx, s.outboxRepo, models.EventUserActivated, newUserEventData(user))
+		default:
+			return nil
+		}
+	})
 
-	// Get updated user to return in response
-	user, err := s.userRepo.GetByID(ctx, req.UserID)
 	if err != nil {
-		logger.Error("Failed to get user %s after updating: %v", req.UserID, err)
 		return nil, err
 	}
 
@@ -59,11 +105,295 @@ func (s *UserServiceImpl) SetUserActive(ctx context.Context, req *request.SetUse
 	}, nil
 }
 
+// SetMentionHandle sets the Slack member ID used to mention the user in chat notifications
+func (s *UserServiceImpl) SetMentionHandle(ctx context.Context, req *request.SetMentionHandleRequest) (*response.SetMentionHandleResponse, error) {
+	// Validate input
+	if req.UserID == "" {
+		return nil, pkgerrors.NewRequiredFieldError("user_id")
+	}
+	if err := validateMentionHandle("mention_handle", req.MentionHandle); err != nil {
+		return nil, err
+	}
+
+	logger.Info("Setting mention handle of user %s to %q", req.UserID, req.MentionHandle)
+
+	user, err := s.updateUser(ctx, req.UserID, []string{"mention_handle"}, func(txCtx context.Context) error {
+		if err := s.userRepo.SetMentionHandle(txCtx, req.UserID, req.MentionHandle); err != nil {
+			logger.Error("Failed to set mention handle for user %s: %v", req.UserID, err)
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &response.SetMentionHandleResponse{
+		User: convertUserToResponse(user),
+	}, nil
+}
+
+// SetEmail sets the address that receives the user's email notifications
+func (s *UserServiceImpl) SetEmail(ctx context.Context, req *request.SetEmailRequest) (*response.SetEmailResponse, error) {
+	// Validate input
+	if req.UserID == "" {
+		return nil, pkgerrors.NewRequiredFieldError("user_id")
+	}
+	if err := validateEmail("email", req.Email); err != nil {
+		return nil, err
+	}
+
+	logger.Info("Setting email of user %s (enabled: %t)", req.UserID, req.Email != "")
+
+	user, err := s.updateUser(ctx, req.UserID, []string{"email"}, func(txCtx context.Context) error {
+		if err := s.userRepo.SetEmail(txCtx, req.UserID, req.Email); err != nil {
+			logger.Error("Failed to set email for user %s: %v", req.UserID, err)
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &response.SetEmailResponse{
+		User: convertUserToResponse(user),
+	}, nil
+}
+
+// SetWorkingHours sets the user's timezone and working hours used to prefer reviewers who are at work
+func (s *UserServiceImpl) SetWorkingHours(ctx context.Context, req *request.SetWorkingHoursRequest) (*response.SetWorkingHoursResponse, error) {
+	// Validate input
+	if req.UserID == "" {
+		return nil, pkgerrors.NewRequiredFieldError("user_id")
+	}
+	if req.Timezone != "" {
+		if _, err := time.LoadLocation(req.Timezone); err != nil {
+			return nil, pkgerrors.NewValidationError("timezone", "must be an IANA time zone name, e.g. Europe/Moscow")
+		}
+	}
+	if (req.WorkStart == "") != (req.WorkEnd == "") {
+		return nil, pkgerrors.NewValidationError("work_end", "work_start and work_end must be set together")
+	}
+	var hours models.WorkingHours
+	if req.WorkStart != "" {
+		var err error
+		if hours.Start, err = parseClock("work_start", req.WorkStart); err != nil {
+			return nil, err
+		}
+		if hours.End, err = parseClock("work_end", req.WorkEnd); err != nil {
+			return nil, err
+		}
+		if !hours.IsSet() {
+			return nil, pkgerrors.NewValidationError("work_end", "must differ from work_start")
+		}
+	}
+
+	logger.Info("Setting working hours of user %s to %q-%q (timezone: %q)", req.UserID, req.WorkStart, req.WorkEnd, req.Timezone)
+
+	user, err := s.updateUser(ctx, req.UserID, []string{"timezone", "work_start", "work_end"}, func(txCtx context.Context) error {
+		if err := s.userRepo.SetWorkingHours(txCtx, req.UserID, req.Timezone, hours); err != nil {
+			logger.Error("Failed to set working hours for user %s: %v", req.UserID, err)
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &response.SetWorkingHoursResponse{
+		User: convertUserToResponse(user),
+	}, nil
+}
+
+// SetMaxOpenReviews sets how many open reviews the user may have at once; 0 removes the limit
+func (s *UserServiceImpl) SetMaxOpenReviews(ctx context.Context, req *request.SetMaxOpenReviewsRequest) (*response.SetMaxOpenReviewsResponse, error) {
+	// Validate input
+	if req.UserID == "" {
+		return nil, pkgerrors.NewRequiredFieldError("user_id")
+	}
+	if err := validateMaxOpenReviews("max_open_reviews", req.MaxOpenReviews); err != nil {
+		return nil, err
+	}
+
+	logger.Info("Setting max open reviews of user %s to %d", req.UserID, req.MaxOpenReviews)
+
+	user, err := s.updateUser(ctx, req.UserID, []string{"max_open_reviews"}, func(txCtx context.Context) error {
+		if err := s.userRepo.SetMaxOpenReviews(txCtx, req.UserID, req.MaxOpenReviews); err != nil {
+			logger.Error("Failed to set max open reviews for user %s: %v", req.UserID, err)
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &response.SetMaxOpenReviewsResponse{
+		User: convertUserToResponse(user),
+	}, nil
+}
+
+// SetSeniority sets the user's reviewer level; an empty level clears it
+func (s *UserServiceImpl) SetSeniority(ctx context.Context, req *request.SetSeniorityRequest) (*response.SetSeniorityResponse, error) {
+	// Validate input
+	if req.UserID == "" {
+		return nil, pkgerrors.NewRequiredFieldError("user_id")
+	}
+	level, err := parseSeniority("seniority", req.Seniority)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("Setting seniority of user %s to %q", req.UserID, level)
+
+	user, err := s.updateUser(ctx, req.UserID, []string{"seniority"}, func(txCtx context.Context) error {
+		if err := s.userRepo.SetSeniority(txCtx, req.UserID, level); err != nil {
+			logger.Error("Failed to set seniority for user %s: %v", req.UserID, err)
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &response.SetSeniorityResponse{
+		User: convertUserToResponse(user),
+	}, nil
+}
+
+// AddSkillTags adds skill tags to the user; tags the user already has are ignored
+func (s *UserServiceImpl) AddSkillTags(ctx context.Context, req *request.AddSkillTagsRequest) (*response.SkillTagsResponse, error) {
+	return s.updateSkillTags(ctx, req.UserID, req.Tags, func(current, tags []string) []string {
+		return append(current, tags...)
+	})
+}
+
+// RemoveSkillTags removes skill tags from the user; tags the user does not have are ignored
+func (s *UserServiceImpl) RemoveSkillTags(ctx context.Context, req *request.RemoveSkillTagsRequest) (*response.SkillTagsResponse, error) {
+	return s.updateSkillTags(ctx, req.UserID, req.Tags, func(current, tags []string) []string {
+		removed := make(map[string]bool, len(tags))
+		for _, tag := range tags {
+			removed[tag] = true
+		}
+		remaining := make([]string, 0, len(current))
+		for _, tag := range current {
+			if !removed[tag] {
+				remaining = append(remaining, tag)
+			}
+		}
+		return remaining
+	})
+}
+
+// SetSkillTags replaces the user's skill tags
+func (s *UserServiceImpl) SetSkillTags(ctx context.Context, req *request.SetSkillTagsRequest) (*response.SkillTagsResponse, error) {
+	return s.updateSkillTags(ctx, req.UserID, req.Tags, func(_, tags []string) []string {
+		return tags
+	})
+}
+
+// updateSkillTags validates tags and stores the result of apply to the user's current tags and them
+// The user is read and updated in one transaction so concurrent changes are not lost, and the
+// user.updated event is recorded in it
+func (s *UserServiceImpl) updateSkillTags(
+	ctx context.Context,
+	userID string,
+	tags []string,
+	apply func(current, tags []string) []string,
+) (*response.SkillTagsResponse, error) {
+	// Validate input
+	if userID == "" {
+		return nil, pkgerrors.NewRequiredFieldError("user_id")
+	}
+	tags, err := normalizeTags("tags", tags, maxSkillTags)
+	if err != nil {
+		return nil, err
+	}
+
+	var user *models.User
+	err = s.txManager.WithTransaction(ctx, func(txCtx context.Context) error {
+		current, err := s.userRepo.GetByID(txCtx, userID)
+		if err != nil {
+			return err
+		}
+		updated, err := normalizeTags("tags", apply(append([]string(nil), current.SkillTags...), tags), maxSkillTags)
+		if err != nil {
+			return err
+		}
+		if err := s.userRepo.SetSkillTags(txCtx, userID, updated); err != nil {
+			return err
+		}
+		current.SkillTags = updated
+		user = current
+		return recordEvent(txCtx, s.outboxRepo, models.EventUserUpdated, newUserEventData(user, "skill_tags"))
+	})
+	if err != nil {
+		logger.Error("Failed to update skill tags of user %s: %v", userID, err)
+		return nil, err
+	}
+
+	logger.Info("User %s now has skill tags %v", userID, user.SkillTags)
+
+	return &response.SkillTagsResponse{
+		User: convertUserToResponse(user),
+	}, nil
+}
+
+// updateUser runs set in a transaction together with recording a user.updated event naming fields
+// It returns the user as stored after the update
+func (s *UserServiceImpl) updateUser(ctx context.Context, userID string, fields []string, set func(txCtx context.Context) error) (*models.User, error) {
+	var user *models.User
+	err := s.txManager.WithTransaction(ctx, func(txCtx context.Context) error {
+		if err := set(txCtx); err != nil {
+			return err
+		}
+
+		var err error
+		user, err = s.userRepo.GetByID(txCtx, userID)
+		if err != nil {
+			logger.Error("Failed to get user %s after updating: %v", userID, err)
+			return err
+		}
+
+		return recordEvent(txCtx, s.outboxRepo, models.EventUserUpdated, newUserEventData(user, fields...))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// GetSkillTags returns the user's skill tags in alphabetical order
+func (s *UserServiceImpl) GetSkillTags(ctx context.Context, userID string) (*response.GetSkillTagsResponse, error) {
+	// Validate input
+	if userID == "" {
+		return nil, pkgerrors.NewRequiredFieldError("user_id")
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		logger.Error("Failed to get user %s: %v", userID, err)
+		return nil, err
+	}
+
+	tags := user.SkillTags
+	if tags == nil {
+		tags = []string{}
+	}
+	return &response.GetSkillTagsResponse{
+		UserID:    userID,
+		SkillTags: tags,
+	}, nil
+}
+
 // GetUserReviews retrieves all pull requests where the user is assigned as a reviewer
 func (s *UserServiceImpl) GetUserReviews(ctx context.Context, userID string) (*response.GetUserReviewsResponse, error) {
 	// Validate input
 	if userID == "" {
-		return nil, fmt.Errorf("user_id is required")
+		return nil, pkgerrors.NewRequiredFieldError("user_id")
 	}
 
 	logger.Info("Retrieving reviews for user: %s", userID)
@@ -101,12 +431,441 @@ func (s *UserServiceImpl) GetUserReviews(ctx context.Context, userID string) (*r
 	}, nil
 }
 
-// convertUserToResponse converts a User model to UserResponse DTO
-func convertUserToResponse(user *models.User) response.UserResponse {
-	return response.UserResponse{
+// maxAbsenceReasonLength caps the free-text reason of an absence
+const maxAbsenceReasonLength = 255
+
+// absenceImportHorizonDays bounds how far ahead recurring calendar events are expanded on import
+const absenceImportHorizonDays = 366
+
+// AddAbsence records a period when the user is not assigned to reviews
+func (s *UserServiceImpl) AddAbsence(ctx context.Context, req *request.AddAbsenceRequest) (*response.AddAbsenceResponse, error) {
+	// Validate input
+	if req.UserID == "" {
+		return nil, pkgerrors.NewRequiredFieldError("user_id")
+	}
+	startDate, err := parseDate("start_date", req.StartDate)
+	if err != nil {
+		return nil, err
+	}
+	endDate, err := parseDate("end_date", req.EndDate)
+	if err != nil {
+		return nil, err
+	}
+	if endDate.Before(startDate) {
+		return nil, pkgerrors.NewValidationError("end_date", "must not be before start_date")
+	}
+	if len(req.Reason) > maxAbsenceReasonLength {
+		return nil, pkgerrors.NewValidationError("reason", fmt.Sprintf("must be at most %d characters", maxAbsenceReasonLength))
+	}
+
+	absence := &models.Absence{
+		ID:        models.NewID("abs"),
+		UserID:    req.UserID,
+		StartDate: startDate,
+		EndDate:   endDate,
+		Reason:    req.Reason,
+		CreatedAt: time.Now(),
+	}
+
+	logger.Info("Adding absence of user %s from %s to %s", req.UserID, req.StartDate, req.EndDate)
+
+	if err := s.absenceRepo.Create(ctx, absence); err != nil {
+		logger.Error("Failed to add absence for user %s: %v", req.UserID, err)
+		return nil, err
+	}
+
+	return &response.AddAbsenceResponse{
+		Absence: convertAbsenceToResponse(absence),
+	}, nil
+}
+
+// ListAbsences returns all absences of the user, past ones included
+func (s *UserServiceImpl) ListAbsences(ctx context.Context, userID string) (*response.ListAbsencesResponse, error) {
+	// Validate input
+	if userID == "" {
+		return nil, pkgerrors.NewRequiredFieldError("user_id")
+	}
+
+	// Check if user exists: an unknown user is not the same as a user who is never away
+	if _, err := s.userRepo.GetByID(ctx, userID); err != nil {
+		logger.Error("Failed to get user %s: %v", userID, err)
+		return nil, err
+	}
+
+	absences, err := s.absenceRepo.ListByUser(ctx, userID)
+	if err != nil {
+		logger.Error("Failed to list absences of user %s: %v", userID, err)
+		return nil, err
+	}
+
+	absenceResponses := make([]response.AbsenceResponse, 0, len(absences))
+	for _, absence := range absences {
+		absenceResponses = append(absenceResponses, convertAbsenceToResponse(&absence))
+	}
+
+	return &response.ListAbsencesResponse{
+		UserID:   userID,
+		Absences: absenceResponses,
+	}, nil
+}
+
+// ImportAbsences replaces the user's imported absences with the events of an iCalendar file
+// Recurring events are expanded up to absenceImportHorizonDays ahead; absences added by hand are kept
+func (s *UserServiceImpl) ImportAbsences(ctx context.Context, userID string, calendar []byte) (*response.ImportAbsencesResponse, error) {
+	// Validate input
+	if userID == "" {
+		return nil, pkgerrors.NewRequiredFieldError("user_id")
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		logger.Error("Failed to get user %s: %v", userID, err)
+		return nil, err
+	}
+	team, err := s.teamRepo.GetByName(ctx, user.TeamName)
+	if err != nil {
+		logger.Error("Failed to get team %s: %v", user.TeamName, err)
+		return nil, err
+	}
+
+	// Dates are read in the team's timezone, the same one reviewer selection uses
+	loc := team.Calendar.Location()
+	today := models.DateIn(time.Now(), loc)
+	periods, err := ical.Parse(calendar, ical.Window{From: today, Until: today.AddDate(0, 0, absenceImportHorizonDays), Location: loc})
+	if err != nil {
+		logger.Warn("Rejected calendar of user %s: %v", userID, err)
+		return nil, err
+	}
+
+	logger.Info("Importing %d absences of user %s", len(periods), userID)
+
+	absences := make([]models.Absence, len(periods))
+	for i, period := range periods {
+		absences[i] = models.Absence{
+			ID:        models.NewID("abs"),
+			UserID:    userID,
+			StartDate: period.StartDate,
+			EndDate:   period.EndDate,
+			Reason:    truncate(period.Summary, maxAbsenceReasonLength),
+			SourceUID: period.UID,
+			CreatedAt: time.Now(),
+		}
+	}
+
+	// Replace the previous import in a transaction so a failed import keeps it
+	var removed int
+	err = s.txManager.WithTransaction(ctx, func(txCtx context.Context) error {
+		var err error
+		if removed, err = s.absenceRepo.DeleteImported(txCtx, userID); err != nil {
+			return err
+		}
+		for i := range absences {
+			if err := s.absenceRepo.Create(txCtx, &absences[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		logger.Error("Failed to import absences of user %s: %v", userID, err)
+		return nil, err
+	}
+
+	absenceResponses := make([]response.AbsenceResponse, 0, len(absences))
+	for _, absence := range absences {
+		absenceResponses = append(absenceResponses, convertAbsenceToResponse(&absence))
+	}
+
+	return &response.ImportAbsencesResponse{
+		UserID:   userID,
+		Removed:  removed,
+		Absences: absenceResponses,
+	}, nil
+}
+
+// DeleteAbsence removes an absence, e.g. a vacation that was cancelled
+func (s *UserServiceImpl) DeleteAbsence(ctx context.Context, req *request.DeleteAbsenceRequest) (*response.DeleteAbsenceResponse, error) {
+	// Validate input
+	if req.AbsenceID == "" {
+		return nil, pkgerrors.NewRequiredFieldError("absence_id")
+	}
+
+	logger.Info("Deleting absence %s", req.AbsenceID)
+
+	if err := s.absenceRepo.Delete(ctx, req.AbsenceID); err != nil {
+		logger.Error("Failed to delete absence %s: %v", req.AbsenceID, err)
+		return nil, err
+	}
+
+	return &response.DeleteAbsenceResponse{
+		AbsenceID: req.AbsenceID,
+	}, nil
+}
+
+// maxAffinityReasonLength caps the free-text reason of an affinity
+const maxAffinityReasonLength = 255
+
+// SetAffinity creates or replaces how an author relates to a reviewer
+func (s *UserServiceImpl) SetAffinity(ctx context.Context, req *request.SetAffinityRequest) (*response.SetAffinityResponse, error) {
+	// Validate input
+	if req.AuthorID == "" {
+		return nil, pkgerrors.NewRequiredFieldError("author_id")
+	}
+	if req.ReviewerID == "" {
+		return nil, pkgerrors.NewRequiredFieldError("reviewer_id")
+	}
+	if req.ReviewerID == req.AuthorID {
+		return nil, pkgerrors.NewValidationError("reviewer_id", "must differ from author_id")
+	}
+	if req.Weight < models.MinAffinityWeight || req.Weight > models.MaxAffinityWeight {
+		return nil, pkgerrors.NewValidationError("weight",
+			fmt.Sprintf("must be between %d and %d", models.MinAffinityWeight, models.MaxAffinityWeight))
+	}
+	if req.Conflict && req.Weight != 0 {
+		return nil, pkgerrors.NewValidationError("weight", "must be 0 for a conflict of interest")
+	}
+	if len(req.Reason) > maxAffinityReasonLength {
+		return nil, pkgerrors.NewValidationError("reason", fmt.Sprintf("must be at most %d characters", maxAffinityReasonLength))
+	}
+
+	affinity := &models.Affinity{
+		AuthorID:   req.AuthorID,
+		ReviewerID: req.ReviewerID,
+		Weight:     req.Weight,
+		Conflict:   req.Conflict,
+		Reason:     req.Reason,
+	}
+
+	logger.Info("Setting affinity of %s to %s to %d (conflict: %t)", req.AuthorID, req.ReviewerID, req.Weight, req.Conflict)
+
+	if err := s.affinityRepo.Set(ctx, affinity); err != nil {
+		logger.Error("Failed to set affinity of %s to %s: %v", req.AuthorID, req.ReviewerID, err)
+		return nil, err
+	}
+
+	return &response.SetAffinityResponse{
+		Affinity: convertAffinityToResponse(affinity),
+	}, nil
+}
+
+// ListAffinities returns the affinities where the user is the author or the reviewer
+func (s *UserServiceImpl) ListAffinities(ctx context.Context, userID string) (*response.ListAffinitiesResponse, error) {
+	// Validate input
+	if userID == "" {
+		return nil, pkgerrors.NewRequiredFieldError("user_id")
+	}
+
+	// Check if user exists: an unknown user is not the same as a user without affinities
+	if _, err := s.userRepo.GetByID(ctx, userID); err != nil {
+		logger.Error("Failed to get user %s: %v", userID, err)
+		return nil, err
+	}
+
+	affinities, err := s.affinityRepo.ListByUser(ctx, userID)
+	if err != nil {
+		logger.Error("Failed to list affinities of user %s: %v", userID, err)
+		return nil, err
+	}
+
+	affinityResponses := make([]response.AffinityResponse, 0, len(affinities))
+	for _, affinity := range affinities {
+		affinityResponses = append(affinityResponses, convertAffinityToResponse(&affinity))
+	}
+
+	return &response.ListAffinitiesResponse{
+		UserID:     userID,
+		Affinities: affinityResponses,
+	}, nil
+}
+
+// DeleteAffinity removes the affinity of a pair, making it neutral
+func (s *UserServiceImpl) DeleteAffinity(ctx context.Context, req *request.DeleteAffinityRequest) (*response.DeleteAffinityResponse, error) {
+	// Validate input
+	if req.AuthorID == "" {
+		return nil, pkgerrors.NewRequiredFieldError("author_id")
+	}
+	if req.ReviewerID == "" {
+		return nil, pkgerrors.NewRequiredFieldError("reviewer_id")
+	}
+
+	logger.Info("Deleting affinity of %s to %s", req.AuthorID, req.ReviewerID)
+
+	if err := s.affinityRepo.Delete(ctx, req.AuthorID, req.ReviewerID); err != nil {
+		logger.Error("Failed to delete affinity of %s to %s: %v", req.AuthorID, req.ReviewerID, err)
+		return nil, err
+	}
+
+	return &response.DeleteAffinityResponse{
+		AuthorID:   req.AuthorID,
+		ReviewerID: req.ReviewerID,
+	}, nil
+}
+
+// truncate shortens s to at most limit characters
+func truncate(s string, limit int) string {
+	runes := []rune(s)
+	if len(runes) <= limit {
+		return s
+	}
+	return string(runes[:limit])
+}
+
+// parseDate parses a required YYYY-MM-DD date field
+func parseDate(field, value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, pkgerrors.NewRequiredFieldError(field)
+	}
+	date, err := time.Parse(models.DateLayout, value)
+	if err != nil {
+		return time.Time{}, pkgerrors.NewValidationError(field, "must be a date in YYYY-MM-DD format")
+	}
+	return date, nil
+}
+
+// convertAbsenceToResponse converts an Absence model to AbsenceResponse DTO
+func convertAbsenceToResponse(absence *models.Absence) response.AbsenceResponse {
+	return response.AbsenceResponse{
+		AbsenceID: absence.ID,
+		UserID:    absence.UserID,
+		StartDate: absence.StartDate.Format(models.DateLayout),
+		EndDate:   absence.EndDate.Format(models.DateLayout),
+		Reason:    absence.Reason,
+		SourceUID: absence.SourceUID,
+	}
+}
+
+// convertAffinityToResponse converts an Affinity model to AffinityResponse DTO
+func convertAffinityToResponse(affinity *models.Affinity) response.AffinityResponse {
+	return response.AffinityResponse{
+		AuthorID:   affinity.AuthorID,
+		ReviewerID: affinity.ReviewerID,
+		Weight:     affinity.Weight,
+		Conflict:   affinity.Conflict,
+		Reason:     affinity.Reason,
+	}
+}
+
+// newUserEventData builds the payload of user events; fields name what a user.updated event changed
+func newUserEventData(user *models.User, fields ...string) models.UserEventData {
+	return models.UserEventData{
 		UserID:   user.ID,
 		Username: user.Username,
 		TeamName: user.TeamName,
 		IsActive: user.IsActive,
+		Fields:   fields,
+	}
+}
+
+// convertUserToResponse converts a User model to UserResponse DTO
+func convertUserToResponse(user *models.User) response.UserResponse {
+	workStart, workEnd := formatWorkingHours(user.WorkingHours)
+	return response.UserResponse{
+		UserID:         user.ID,
+		Username:       user.Username,
+		TeamName:       user.TeamName,
+		IsActive:       user.IsActive,
+		MentionHandle:  user.MentionHandle,
+		Email:          user.Email,
+		Timezone:       user.Timezone,
+		WorkStart:      workStart,
+		WorkEnd:        workEnd,
+		MaxOpenReviews: user.MaxOpenReviews,
+		SkillTags:      user.SkillTags,
+		Seniority:      string(user.Seniority),
+	}
+}
+
+// maxOpenReviewsLimit caps the per-user open review limit
+const maxOpenReviewsLimit = 100
+
+// validateMaxOpenReviews accepts 0 (no limit) up to maxOpenReviewsLimit
+func validateMaxOpenReviews(field string, limit int) error {
+	if limit < 0 || limit > maxOpenReviewsLimit {
+		return pkgerrors.NewValidationError(field, fmt.Sprintf("must be between 0 and %d", maxOpenReviewsLimit))
+	}
+	return nil
+}
+
+// parseSeniority accepts an empty level or one of junior, middle and senior
+func parseSeniority(field, value string) (models.Seniority, error) {
+	level := models.Seniority(value)
+	if !level.IsValid() {
+		return "", pkgerrors.NewValidationError(field, "must be junior, middle or senior")
+	}
+	return level, nil
+}
+
+// maxSkillTags caps the number of skill tags of a user
+const maxSkillTags = 20
+
+// maxRequiredTags caps the number of tags a PR may require
+const maxRequiredTags = 10
+
+// skillTagPattern matches a lowercase tag such as go, sql, c++ or k8s
+var skillTagPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9+#._-]{0,31}$`)
+
+// normalizeTags lowercases tags, drops duplicates and sorts them; limit caps the number of distinct tags
+func normalizeTags(field string, tags []string, limit int) ([]string, error) {
+	seen := make(map[string]bool, len(tags))
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if !skillTagPattern.MatchString(tag) {
+			return nil, pkgerrors.NewValidationError(field,
+				fmt.Sprintf("tag %q must be 1-32 lowercase letters, digits or +#._- starting with a letter or digit", tag))
+		}
+		if !seen[tag] {
+			seen[tag] = true
+			normalized = append(normalized, tag)
+		}
+	}
+	if len(normalized) > limit {
+		return nil, pkgerrors.NewValidationError(field, fmt.Sprintf("must have at most %d tags", limit))
+	}
+	sort.Strings(normalized)
+	return normalized, nil
+}
+
+// parseClock parses a time of day as HH:MM into minutes from midnight
+func parseClock(field, value string) (int, error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, pkgerrors.NewValidationError(field, "must be a time of day as HH:MM, e.g. 09:30")
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// formatWorkingHours formats working hours as HH:MM; both are empty when the hours are not set
+func formatWorkingHours(hours models.WorkingHours) (string, string) {
+	if !hours.IsSet() {
+		return "", ""
+	}
+	format := func(minutes int) string {
+		return fmt.Sprintf("%02d:%02d", minutes/60, minutes%60)
+	}
+	return format(hours.Start), format(hours.End)
+}
+
+// mentionHandlePattern matches Slack member IDs (U012AB3CD, or W... in Enterprise Grid)
+var mentionHandlePattern = regexp.MustCompile(`^[UW][A-Z0-9]{2,31}$`)
+
+// validateMentionHandle accepts an empty handle (no mentions) or a Slack member ID
+func validateMentionHandle(field, handle string) error {
+	if handle != "" && !mentionHandlePattern.MatchString(handle) {
+		return pkgerrors.NewValidationError(field, "must be a Slack member ID such as U012AB3CD")
+	}
+	return nil
+}
+
+// validateEmail accepts an empty address (no email notifications) or a bare address such as alice@example.com
+func validateEmail(field, email string) error {
+	if email == "" {
+		return nil
+	}
+	// Display names ("Alice <alice@example.com>") are rejected: the stored value is used as is in RCPT TO
+	addr, err := mail.ParseAddress(email)
+	if err != nil || addr.Address != email || addr.Name != "" {
+		return pkgerrors.NewValidationError(field, "must be an email address such as alice@example.com")
 	}
+	return nil
 }
@@ -0,0 +1,237 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"time"
+
+	"avito-backend-trainee-assignment-autumn-2025/internal/domain/models"
+	"avito-backend-trainee-assignment-autumn-2025/internal/netguard"
+	"avito-backend-trainee-assignment-autumn-2025/internal/repository"
+	"avito-backend-trainee-assignment-autumn-2025/pkg/dto/request"
+	"avito-backend-trainee-assignment-autumn-2025/pkg/dto/response"
+	pkgerrors "avito-backend-trainee-assignment-autumn-2025/pkg/errors"
+	"avito-backend-trainee-assignment-autumn-2025/pkg/logger"
+)
+
+// deliveriesListLimit caps the number of deliveries returned by ListDeliveries
+const deliveriesListLimit = 100
+
+// WebhookServiceConfig controls webhook registration
+type WebhookServiceConfig struct {
+	// AllowPrivateNetworks accepts URLs pointing to loopback, private and link-local addresses
+	AllowPrivateNetworks bool
+}
+
+// WebhookServiceImpl implements WebhookService
+type WebhookServiceImpl struct {
+	webhookRepo repository.WebhookRepository
+	config      WebhookServiceConfig
+}
+
+// NewWebhookService creates a new webhook service
+func NewWebhookService(webhookRepo repository.WebhookRepository, config WebhookServiceConfig) *WebhookServiceImpl {
+	return &WebhookServiceImpl{
+		webhookRepo: webhookRepo,
+		config:      config,
+	}
+}
+
+// RegisterWebhook registers an endpoint for the given event types
+func (s *WebhookServiceImpl) RegisterWebhook(ctx context.Context, req *request.RegisterWebhookRequest) (*response.RegisterWebhookResponse, error) {
+	// Validate input
+	if req.URL == "" {
+		return nil, pkgerrors.NewRequiredFieldError("url")
+	}
+	parsed, err := url.Parse(req.URL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Hostname() == "" {
+		return nil, pkgerrors.NewValidationError("url", "must be an absolute http or https URL")
+	}
+	// Deliveries are signed POSTs sent from inside the deployment, so internal hosts are refused;
+	// the dispatcher checks the address again when it connects
+	if !s.config.AllowPrivateNetworks {
+		if err := netguard.CheckHost(ctx, parsed.Hostname()); err != nil {
+			logger.Warn("Rejected webhook URL %s: %v", req.URL, err)
+			return nil, pkgerrors.NewValidationError("url", "must resolve to a public address")
+		}
+	}
+	if len(req.Events) == 0 {
+		return nil, pkgerrors.NewRequiredFieldError("events")
+	}
+
+	events := make([]models.EventType, 0, len(req.Events))
+	seen := make(map[models.EventType]bool)
+	for i, name := range req.Events {
+		eventType := models.EventType(name)
+		if !eventType.IsValid() {
+			return nil, pkgerrors.NewValidationError(fmt.Sprintf("events[%d]", i), fmt.Sprintf("is not a known event type: %s", name))
+		}
+		if !seen[eventType] {
+			seen[eventType] = true
+			events = append(events, eventType)
+		}
+	}
+
+	secret := req.Secret
+	if secret == "" {
+		generated, err := generateSecret()
+		if err != nil {
+			logger.Error("Failed to generate webhook secret: %v", err)
+			return nil, fmt.Errorf("failed to generate webhook secret: %w", err)
+		}
+		secret = generated
+	}
+
+	logger.Info("Registering webhook %s for events %v", req.URL, events)
+
+	webhook := &models.Webhook{
+		ID:        models.NewID("wh"),
+		URL:       req.URL,
+		Secret:    secret,
+		Events:    events,
+		CreatedAt: time.Now().UTC(),
+	}
+
+	if err := s.webhookRepo.Create(ctx, webhook); err != nil {
+		logger.Error("Failed to register webhook %s: %v", req.URL, err)
+		return nil, err
+	}
+
+	logger.Info("Successfully registered webhook %s", webhook.ID)
+
+	// Convert to response DTO
+	return &response.RegisterWebhookResponse{
+		Webhook: convertWebhookToResponse(webhook),
+		Secret:  webhook.Secret,
+	}, nil
+}
+
+// ListWebhooks returns all registered webhooks
+func (s *WebhookServiceImpl) ListWebhooks(ctx context.Context) (*response.ListWebhooksResponse, error) {
+	webhooks, err := s.webhookRepo.List(ctx)
+	if err != nil {
+		logger.Error("Failed to list webhooks: %v", err)
+		return nil, fmt.Errorf("failed to list webhooks: %w", err)
+	}
+
+	// Convert to response DTO
+	result := make([]response.WebhookResponse, 0, len(webhooks))
+	for i := range webhooks {
+		result = append(result, convertWebhookToResponse(&webhooks[i]))
+	}
+
+	return &response.ListWebhooksResponse{
+		Webhooks: result,
+	}, nil
+}
+
+// ListDeliveries returns the latest deliveries, optionally filtered by status
+func (s *WebhookServiceImpl) ListDeliveries(ctx context.Context, status string) (*response.ListWebhookDeliveriesResponse, error) {
+	// Validate input
+	deliveryStatus := models.DeliveryStatus(status)
+	if status != "" && !deliveryStatus.IsValid() {
+		return nil, pkgerrors.NewValidationError("status", fmt.Sprintf("is invalid: %s", status))
+	}
+
+	deliveries, err := s.webhookRepo.ListDeliveries(ctx, deliveryStatus, deliveriesListLimit)
+	if err != nil {
+		logger.Error("Failed to list webhook deliveries: %v", err)
+		return nil, fmt.Errorf("failed to list webhook deliveries: %w", err)
+	}
+
+	// Convert to response DTO
+	result := make([]response.WebhookDeliveryResponse, 0, len(deliveries))
+	for i := range deliveries {
+		result = append(result, convertDeliveryToResponse(&deliveries[i]))
+	}
+
+	return &response.ListWebhookDeliveriesResponse{
+		Deliveries: result,
+	}, nil
+}
+
+// RedeliverDelivery schedules a delivery to be sent again
+// A delivery that is still pending is left as is
+func (s *WebhookServiceImpl) RedeliverDelivery(ctx context.Context, req *request.RedeliverWebhookRequest) (*response.RedeliverWebhookResponse, error) {
+	// Validate input
+	if req.DeliveryID == "" {
+		return nil, pkgerrors.NewRequiredFieldError("delivery_id")
+	}
+
+	logger.Info("Redelivering webhook delivery: %s", req.DeliveryID)
+
+	delivery, err := s.webhookRepo.GetDelivery(ctx, req.DeliveryID)
+	if err != nil {
+		logger.Error("Failed to get delivery %s: %v", req.DeliveryID, err)
+		return nil, err
+	}
+
+	if delivery.Status != models.DeliveryStatusPending {
+		delivery.Status = models.DeliveryStatusPending
+		delivery.Attempts = 0
+		delivery.NextAttemptAt = time.Now().UTC()
+		delivery.DeliveredAt = nil
+
+		if err := s.webhookRepo.UpdateDelivery(ctx, delivery); err != nil {
+			logger.Error("Failed to reschedule delivery %s: %v", req.DeliveryID, err)
+			return nil, err
+		}
+	}
+
+	logger.Info("Delivery %s scheduled for redelivery", req.DeliveryID)
+
+	// Convert to response DTO
+	return &response.RedeliverWebhookResponse{
+		Delivery: convertDeliveryToResponse(delivery),
+	}, nil
+}
+
+// generateSecret returns a random 256-bit hex secret
+func generateSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// convertWebhookToResponse converts a Webhook model to WebhookResponse DTO
+func convertWebhookToResponse(webhook *models.Webhook) response.WebhookResponse {
+	events := make([]string, len(webhook.Events))
+	for i, eventType := range webhook.Events {
+		events[i] = string(eventType)
+	}
+
+	return response.WebhookResponse{
+		WebhookID: webhook.ID,
+		URL:       webhook.URL,
+		Events:    events,
+		CreatedAt: webhook.CreatedAt,
+	}
+}
+
+// convertDeliveryToResponse converts a WebhookDelivery model to WebhookDeliveryResponse DTO
+func convertDeliveryToResponse(delivery *models.WebhookDelivery) response.WebhookDeliveryResponse {
+	resp := response.WebhookDeliveryResponse{
+		DeliveryID:  delivery.ID,
+		WebhookID:   delivery.WebhookID,
+		EventID:     delivery.EventID,
+		EventType:   string(delivery.EventType),
+		Status:      string(delivery.Status),
+		Attempts:    delivery.Attempts,
+		LastError:   delivery.LastError,
+		CreatedAt:   delivery.CreatedAt,
+		DeliveredAt: delivery.DeliveredAt,
+	}
+
+	// The next attempt time is meaningful only while the delivery is pending
+	if delivery.Status == models.DeliveryStatusPending {
+		nextAttemptAt := delivery.NextAttemptAt
+		resp.NextAttemptAt = &nextAttemptAt
+	}
+
+	return resp
+}
@@ -0,0 +1,308 @@
+// Package webhook delivers domain events to registered webhook endpoints
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"avito-backend-trainee-assignment-autumn-2025/internal/domain/models"
+	"avito-backend-trainee-assignment-autumn-2025/internal/netguard"
+	"avito-backend-trainee-assignment-autumn-2025/internal/repository"
+	"avito-backend-trainee-assignment-autumn-2025/pkg/logger"
+)
+
+// Headers sent with every delivery
+const (
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderDelivery  = "X-Webhook-Delivery"
+	HeaderSignature = "X-Webhook-Signature"
+	// HeaderTimestamp carries the Unix time of the attempt; it is covered by the signature
+	HeaderTimestamp = "X-Webhook-Timestamp"
+)
+
+// signaturePrefix names the HMAC algorithm in the signature header
+const signaturePrefix = "sha256="
+
+// SignatureTolerance is how far the signed timestamp may be from the receiver's clock;
+// older requests are rejected by Verify as replays
+const SignatureTolerance = 5 * time.Minute
+
+// maxErrorBodySize limits how much of a failed response is kept in last_error
+const maxErrorBodySize = 256
+
+// defaultLease is used when Config.Lease is not set
+const defaultLease = 5 * time.Minute
+
+// Config controls delivery timing
+type Config struct {
+	// MaxAttempts is the number of attempts before a delivery is moved to the dead-letter list
+	MaxAttempts int
+	// InitialBackoff is the delay before the first retry; it doubles on every further retry
+	InitialBackoff time.Duration
+	// MaxBackoff caps the retry delay
+	MaxBackoff time.Duration
+	// Timeout bounds a single HTTP request
+	Timeout time.Duration
+	// PollInterval is how often due retries are looked up
+	PollInterval time.Duration
+	// BatchSize is the maximum number of deliveries attempted per poll
+	BatchSize int
+	// Lease is how long claimed deliveries are reserved for this dispatcher; other replicas
+	// skip them until it ends, and no new attempt of the batch is started after it
+	Lease time.Duration
+	// AllowPrivateNetworks lets deliveries connect to loopback, private and link-local addresses
+	AllowPrivateNetworks bool
+}
+
+// Dispatcher stores deliveries for published events and sends them with retries
+type Dispatcher struct {
+	repo   repository.WebhookRepository
+	client *http.Client
+	config Config
+
+	// now is the clock used for scheduling; replaced in tests
+	now func() time.Time
+}
+
+// NewDispatcher creates a new webhook dispatcher
+func NewDispatcher(repo repository.WebhookRepository, config Config) *Dispatcher {
+	if config.BatchSize <= 0 {
+		config.BatchSize = 100
+	}
+	if config.Lease <= 0 {
+		config.Lease = defaultLease
+	}
+
+	return &Dispatcher{
+		repo:   repo,
+		client: &http.Client{Timeout: config.Timeout, Transport: newTransport(config.AllowPrivateNetworks)},
+		config: config,
+		now:    time.Now,
+	}
+}
+
+// newTransport returns the HTTP transport for deliveries
+// Unless private networks are allowed, every connection is checked after name resolution,
+// so a registered host cannot be pointed at an internal address later, and proxies are not
+// used, since the check would apply to the proxy instead of the webhook host
+func newTransport(allowPrivateNetworks bool) *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if allowPrivateNetworks {
+		return transport
+	}
+
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Control:   netguard.Control,
+	}
+	transport.DialContext = dialer.DialContext
+	transport.Proxy = nil
+	return transport
+}
+
+// Name identifies the dispatcher as an outbox sink
+func (d *Dispatcher) Name() string {
+	return "webhook"
+}
+
+// Handle schedules a delivery of the outbox message to every subscribed webhook
+// It runs in the outbox transaction, so deliveries are stored together with the
+// message being marked published
+func (d *Dispatcher) Handle(ctx context.Context, msg *models.OutboxMessage) error {
+	webhooks, err := d.repo.List(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list webhooks: %w", err)
+	}
+
+	now := d.now()
+	scheduled := 0
+	for _, webhook := range webhooks {
+		if !webhook.Subscribes(msg.EventType) {
+			continue
+		}
+
+		delivery := &models.WebhookDelivery{
+			ID:            models.NewID("dlv"),
+			WebhookID:     webhook.ID,
+			EventID:       msg.ID,
+			EventType:     msg.EventType,
+			Payload:       msg.Payload,
+			Status:        models.DeliveryStatusPending,
+			NextAttemptAt: now,
+			CreatedAt:     now,
+		}
+		if err := d.repo.CreateDelivery(ctx, delivery); err != nil {
+			return fmt.Errorf("failed to schedule delivery to webhook %s: %w", webhook.ID, err)
+		}
+		scheduled++
+	}
+
+	if scheduled > 0 {
+		logger.Debug("Scheduled %d delivery(ies) of event %s (%s)", scheduled, msg.ID, msg.EventType)
+	}
+	return nil
+}
+
+// Run sends due deliveries until ctx is cancelled
+func (d *Dispatcher) Run(ctx context.Context) {
+	logger.Info("Webhook dispatcher started (max attempts: %d)", d.config.MaxAttempts)
+
+	ticker := time.NewTicker(d.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		d.processDue(ctx)
+
+		select {
+		case <-ctx.Done():
+			logger.Info("Webhook dispatcher stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// processDue claims due deliveries, attempts each once and returns how many were claimed
+func (d *Dispatcher) processDue(ctx context.Context) int {
+	now := d.now()
+	lockedUntil := now.Add(d.config.Lease)
+	deliveries, err := d.repo.ClaimDueDeliveries(ctx, now, lockedUntil, d.config.BatchSize)
+	if err != nil {
+		if ctx.Err() == nil {
+			logger.Error("Failed to claim due webhook deliveries: %v", err)
+		}
+		return 0
+	}
+
+	for i := range deliveries {
+		if ctx.Err() != nil {
+			break
+		}
+		// Another replica may claim the rest once the lease is over
+		if !d.now().Before(lockedUntil) {
+			logger.Warn("Webhook delivery lease expired, %d delivery(ies) left to other dispatchers", len(deliveries)-i)
+			break
+		}
+		d.attempt(ctx, &deliveries[i])
+	}
+
+	return len(deliveries)
+}
+
+// attempt sends a delivery once and records the outcome
+func (d *Dispatcher) attempt(ctx context.Context, delivery *models.WebhookDelivery) {
+	webhook, err := d.repo.GetByID(ctx, delivery.WebhookID)
+	if err == nil {
+		err = d.send(ctx, webhook, delivery)
+	}
+	if ctx.Err() != nil {
+		// Shutting down: the delivery stays pending and is claimed again once the lease ends
+		return
+	}
+
+	now := d.now()
+	delivery.Attempts++
+
+	switch {
+	case err == nil:
+		delivery.Status = models.DeliveryStatusDelivered
+		delivery.LastError = ""
+		delivery.DeliveredAt = &now
+		logger.Info("Delivered event %s (%s) to webhook %s", delivery.EventID, delivery.EventType, delivery.WebhookID)
+
+	case delivery.Attempts >= d.config.MaxAttempts:
+		delivery.Status = models.DeliveryStatusFailed
+		delivery.LastError = err.Error()
+		logger.Warn("Delivery %s to webhook %s failed after %d attempts, moved to dead letters: %v",
+			delivery.ID, delivery.WebhookID, delivery.Attempts, err)
+
+	default:
+		delay := d.backoff(delivery.Attempts)
+		delivery.LastError = err.Error()
+		delivery.NextAttemptAt = now.Add(delay)
+		logger.Warn("Delivery %s to webhook %s failed (attempt %d/%d), retrying in %v: %v",
+			delivery.ID, delivery.WebhookID, delivery.Attempts, d.config.MaxAttempts, delay, err)
+	}
+
+	if err := d.repo.UpdateDelivery(ctx, delivery); err != nil {
+		logger.Error("Failed to save delivery %s: %v", delivery.ID, err)
+	}
+}
+
+// send posts the signed payload to the webhook URL
+// Any response other than 2xx is an error
+func (d *Dispatcher) send(ctx context.Context, webhook *models.Webhook, delivery *models.WebhookDelivery) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return fmt.Errorf("invalid request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "pr-reviewer-webhooks")
+	req.Header.Set(HeaderEvent, string(delivery.EventType))
+	req.Header.Set(HeaderDelivery, delivery.ID)
+	timestamp := strconv.FormatInt(d.now().Unix(), 10)
+	req.Header.Set(HeaderTimestamp, timestamp)
+	req.Header.Set(HeaderSignature, Sign(webhook.Secret, timestamp, delivery.Payload))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+		return fmt.Errorf("unexpected status %d: %s", resp.StatusCode, bytes.TrimSpace(body))
+	}
+
+	// Drain the body so the connection can be reused
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return nil
+}
+
+// backoff returns the delay after the given number of failed attempts:
+// InitialBackoff, then doubled each time, capped at MaxBackoff
+func (d *Dispatcher) backoff(attempts int) time.Duration {
+	delay := d.config.InitialBackoff
+	for i := 1; i < attempts && delay < d.config.MaxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > d.config.MaxBackoff {
+		delay = d.config.MaxBackoff
+	}
+	return delay
+}
+
+// Sign returns the signature header value for a payload sent at timestamp (Unix seconds):
+// "sha256=" + hex HMAC-SHA256 of "<timestamp>.<payload>"
+func Sign(secret, timestamp string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks a signature header value produced by Sign in constant time and rejects
+// timestamps more than SignatureTolerance away from now, so captured requests cannot be replayed
+func Verify(secret, timestamp string, payload []byte, signature string, now time.Time) bool {
+	sentAt, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return false
+	}
+	if age := now.Sub(time.Unix(sentAt, 0)); age > SignatureTolerance || age < -SignatureTolerance {
+		return false
+	}
+	return hmac.Equal([]byte(Sign(secret, timestamp, payload)), []byte(signature))
+}
@@ -0,0 +1,288 @@
+package webhook
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"avito-backend-trainee-assignment-autumn-2025/internal/domain/models"
+	"avito-backend-trainee-assignment-autumn-2025/internal/repository/memory"
+	"avito-backend-trainee-assignment-autumn-2025/internal/service"
+	"avito-backend-trainee-assignment-autumn-2025/pkg/dto/request"
+)
+
+// receiver is a webhook endpoint that answers with queued status codes (200 once they run out)
+type receiver struct {
+	mu       sync.Mutex
+	statuses []int
+	requests []*http.Request
+	bodies   [][]byte
+}
+
+func (rc *receiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	rc.requests = append(rc.requests, r)
+	rc.bodies = append(rc.bodies, body)
+
+	status := http.StatusOK
+	if len(rc.statuses) > 0 {
+		status, rc.statuses = rc.statuses[0], rc.statuses[1:]
+	}
+	w.WriteHeader(status)
+}
+
+func (rc *receiver) count() int {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	return len(rc.requests)
+}
+
+type fixture struct {
+	repo       *memory.WebhookRepository
+	service    *service.WebhookServiceImpl
+	dispatcher *Dispatcher
+	receiver   *receiver
+	url        string
+	clock      time.Time
+}
+
+func newFixture(t *testing.T, statuses ...int) *fixture {
+	t.Helper()
+
+	rc := &receiver{statuses: statuses}
+	server := httptest.NewServer(rc)
+	t.Cleanup(server.Close)
+
+	repo := memory.NewWebhookRepository(memory.NewStore())
+	f := &fixture{
+		repo:     repo,
+		service:  service.NewWebhookService(repo, service.WebhookServiceConfig{AllowPrivateNetworks: true}),
+		receiver: rc,
+		url:      server.URL,
+		clock:    time.Date(2025, 11, 1, 12, 0, 0, 0, time.UTC),
+	}
+	f.dispatcher = NewDispatcher(repo, Config{
+		MaxAttempts:    3,
+		InitialBackoff: time.Second,
+		MaxBackoff:     time.Minute,
+		Timeout:        5 * time.Second,
+		PollInterval:   time.Hour,
+		// The receiver listens on loopback
+		AllowPrivateNetworks: true,
+	})
+	f.dispatcher.now = func() time.Time { return f.clock }
+	return f
+}
+
+func (f *fixture) register(t *testing.T, events ...string) string {
+	t.Helper()
+	resp, err := f.service.RegisterWebhook(context.Background(), &request.RegisterWebhookRequest{
+		URL:    f.url,
+		Events: events,
+		Secret: "s3cret",
+	})
+	if err != nil {
+		t.Fatalf("RegisterWebhook: %v", err)
+	}
+	return resp.Webhook.WebhookID
+}
+
+// publishMerged hands a pr.merged outbox message to the dispatcher
+func (f *fixture) publishMerged(t *testing.T) *models.OutboxMessage {
+	t.Helper()
+	msg, err := models.NewOutboxMessage(models.NewEvent(models.EventPRMerged, models.PRMergedEventData{PullRequestID: "pr-1"}))
+	if err != nil {
+		t.Fatalf("NewOutboxMessage: %v", err)
+	}
+	if err := f.dispatcher.Handle(context.Background(), msg); err != nil {
+		t.Fatalf("Handle: %v", err)
+	}
+	return msg
+}
+
+func (f *fixture) deliveries(t *testing.T, status models.DeliveryStatus) []models.WebhookDelivery {
+	t.Helper()
+	deliveries, err := f.repo.ListDeliveries(context.Background(), status, 100)
+	if err != nil {
+		t.Fatalf("ListDeliveries: %v", err)
+	}
+	return deliveries
+}
+
+func TestDeliverySignedAndMarkedDelivered(t *testing.T) {
+	f := newFixture(t)
+	f.register(t, string(models.EventPRMerged))
+	// Another subscriber that does not care about merges
+	f.register(t, string(models.EventUserDeactivated))
+
+	msg := f.publishMerged(t)
+	if n := f.dispatcher.processDue(context.Background()); n != 1 {
+		t.Fatalf("processed = %d, want 1", n)
+	}
+
+	if f.receiver.count() != 1 {
+		t.Fatalf("requests = %d, want 1", f.receiver.count())
+	}
+	req, body := f.receiver.requests[0], f.receiver.bodies[0]
+	if req.Header.Get(HeaderEvent) != string(models.EventPRMerged) {
+		t.Fatalf("%s = %q", HeaderEvent, req.Header.Get(HeaderEvent))
+	}
+	timestamp, signature := req.Header.Get(HeaderTimestamp), req.Header.Get(HeaderSignature)
+	if timestamp != strconv.FormatInt(f.clock.Unix(), 10) {
+		t.Fatalf("%s = %q, want the attempt time", HeaderTimestamp, timestamp)
+	}
+	if !Verify("s3cret", timestamp, body, signature, f.clock) {
+		t.Fatalf("signature %q does not match body %s", signature, body)
+	}
+	if Verify("other", timestamp, body, signature, f.clock) {
+		t.Fatal("signature verified with a wrong secret")
+	}
+	if Verify("s3cret", strconv.FormatInt(f.clock.Unix()+1, 10), body, signature, f.clock) {
+		t.Fatal("signature verified with a different timestamp")
+	}
+	if Verify("s3cret", timestamp, body, signature, f.clock.Add(SignatureTolerance+time.Second)) {
+		t.Fatal("replayed request verified after the tolerance")
+	}
+
+	delivered := f.deliveries(t, models.DeliveryStatusDelivered)
+	if len(delivered) != 1 || delivered[0].EventID != msg.ID || delivered[0].ID != req.Header.Get(HeaderDelivery) {
+		t.Fatalf("delivered = %+v", delivered)
+	}
+	if delivered[0].Attempts != 1 || delivered[0].DeliveredAt == nil {
+		t.Fatalf("delivered = %+v, want one attempt and a delivery time", delivered[0])
+	}
+}
+
+func TestDeliveryToPrivateAddressRefused(t *testing.T) {
+	f := newFixture(t)
+	f.register(t, string(models.EventPRMerged))
+	f.publishMerged(t)
+	// The host was accepted at registration but now resolves to an internal address
+	f.dispatcher.client.Transport = newTransport(false)
+
+	f.dispatcher.processDue(context.Background())
+	if f.receiver.count() != 0 {
+		t.Fatalf("requests = %d, want the connection refused", f.receiver.count())
+	}
+	pending := f.deliveries(t, models.DeliveryStatusPending)
+	if len(pending) != 1 || !strings.Contains(pending[0].LastError, "not public") {
+		t.Fatalf("pending = %+v, want a failed attempt to a non-public address", pending)
+	}
+}
+
+func TestDeliveryRetriesWithBackoff(t *testing.T) {
+	f := newFixture(t, http.StatusInternalServerError, http.StatusBadGateway)
+	f.register(t, string(models.EventPRMerged))
+	f.publishMerged(t)
+	ctx := context.Background()
+
+	f.dispatcher.processDue(ctx)
+	pending := f.deliveries(t, models.DeliveryStatusPending)
+	if len(pending) != 1 || pending[0].Attempts != 1 || !pending[0].NextAttemptAt.Equal(f.clock.Add(time.Second)) {
+		t.Fatalf("after first failure = %+v", pending)
+	}
+	if pending[0].LastError == "" {
+		t.Fatal("last error is not recorded")
+	}
+
+	// Not due yet
+	if n := f.dispatcher.processDue(ctx); n != 0 {
+		t.Fatalf("processed = %d before the backoff elapsed", n)
+	}
+
+	f.clock = f.clock.Add(time.Second)
+	f.dispatcher.processDue(ctx)
+	pending = f.deliveries(t, models.DeliveryStatusPending)
+	if len(pending) != 1 || !pending[0].NextAttemptAt.Equal(f.clock.Add(2*time.Second)) {
+		t.Fatalf("after second failure = %+v, want backoff doubled", pending)
+	}
+
+	f.clock = f.clock.Add(2 * time.Second)
+	f.dispatcher.processDue(ctx)
+	if delivered := f.deliveries(t, models.DeliveryStatusDelivered); len(delivered) != 1 || delivered[0].Attempts != 3 {
+		t.Fatalf("delivered = %+v, want success on the third attempt", delivered)
+	}
+}
+
+func TestDeliveryDeadLetterAndRedeliver(t *testing.T) {
+	f := newFixture(t, http.StatusInternalServerError, http.StatusInternalServerError, http.StatusInternalServerError)
+	f.register(t, string(models.EventPRMerged))
+	f.publishMerged(t)
+	ctx := context.Background()
+
+	for range 3 {
+		f.dispatcher.processDue(ctx)
+		f.clock = f.clock.Add(time.Hour)
+	}
+
+	failed := f.deliveries(t, models.DeliveryStatusFailed)
+	if len(failed) != 1 || failed[0].Attempts != 3 {
+		t.Fatalf("failed = %+v, want one dead letter after 3 attempts", failed)
+	}
+	if n := f.dispatcher.processDue(ctx); n != 0 {
+		t.Fatalf("dead letter was retried automatically (%d)", n)
+	}
+
+	resp, err := f.service.RedeliverDelivery(ctx, &request.RedeliverWebhookRequest{DeliveryID: failed[0].ID})
+	if err != nil {
+		t.Fatalf("RedeliverDelivery: %v", err)
+	}
+	if resp.Delivery.Status != string(models.DeliveryStatusPending) || resp.Delivery.Attempts != 0 {
+		t.Fatalf("redelivery = %+v", resp.Delivery)
+	}
+
+	// The service schedules redelivery on the real clock
+	f.clock = time.Now().Add(time.Second)
+	f.dispatcher.processDue(ctx)
+	if delivered := f.deliveries(t, models.DeliveryStatusDelivered); len(delivered) != 1 {
+		t.Fatalf("delivered = %+v after redelivery", delivered)
+	}
+	if f.receiver.count() != 4 {
+		t.Fatalf("requests = %d, want 4", f.receiver.count())
+	}
+}
+
+func TestClaimedDeliveryIsSkippedUntilLeaseEnds(t *testing.T) {
+	f := newFixture(t)
+	f.register(t, string(models.EventPRMerged))
+	f.publishMerged(t)
+	ctx := context.Background()
+
+	// Another replica claimed the delivery and stopped before finishing it
+	claimed, err := f.repo.ClaimDueDeliveries(ctx, f.clock, f.clock.Add(time.Minute), 10)
+	if err != nil || len(claimed) != 1 {
+		t.Fatalf("ClaimDueDeliveries = %+v, %v", claimed, err)
+	}
+
+	if n := f.dispatcher.processDue(ctx); n != 0 || f.receiver.count() != 0 {
+		t.Fatalf("processed = %d, requests = %d while the delivery is leased", n, f.receiver.count())
+	}
+
+	f.clock = f.clock.Add(time.Minute)
+	if n := f.dispatcher.processDue(ctx); n != 1 {
+		t.Fatalf("processed = %d after the lease, want 1", n)
+	}
+	if delivered := f.deliveries(t, models.DeliveryStatusDelivered); len(delivered) != 1 || f.receiver.count() != 1 {
+		t.Fatalf("delivered = %+v, requests = %d", delivered, f.receiver.count())
+	}
+}
+
+func TestBackoffIsCapped(t *testing.T) {
+	d := NewDispatcher(nil, Config{InitialBackoff: time.Second, MaxBackoff: 10 * time.Second})
+
+	want := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 10 * time.Second, 10 * time.Second}
+	for i, w := range want {
+		if got := d.backoff(i + 1); got != w {
+			t.Fatalf("backoff(%d) = %v, want %v", i+1, got, w)
+		}
+	}
+}
@@ -0,0 +1,15 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS code_host_accounts (
+    provider VARCHAR(20) NOT NULL,
+    login VARCHAR(255) NOT NULL,
+    user_id VARCHAR(255) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (provider, login),
+    CONSTRAINT fk_code_host_accounts_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT chk_code_host_accounts_provider CHECK (provider IN ('github', 'gitlab'))
+);
+
+CREATE INDEX idx_code_host_accounts_user ON code_host_accounts(user_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS code_host_accounts CASCADE;
@@ -0,0 +1,7 @@
+-- +goose Up
+ALTER TABLE teams ADD COLUMN IF NOT EXISTS chat_webhook_url TEXT NOT NULL DEFAULT '';
+ALTER TABLE users ADD COLUMN IF NOT EXISTS mention_handle VARCHAR(255) NOT NULL DEFAULT '';
+
+-- +goose Down
+ALTER TABLE users DROP COLUMN IF EXISTS mention_handle;
+ALTER TABLE teams DROP COLUMN IF EXISTS chat_webhook_url;
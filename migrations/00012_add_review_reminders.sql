@@ -0,0 +1,7 @@
+-- +goose Up
+ALTER TABLE teams ADD COLUMN IF NOT EXISTS review_sla_minutes INTEGER NOT NULL DEFAULT 0 CHECK (review_sla_minutes >= 0);
+ALTER TABLE pr_reviewers ADD COLUMN IF NOT EXISTS reminded_at TIMESTAMPTZ;
+
+-- +goose Down
+ALTER TABLE pr_reviewers DROP COLUMN IF EXISTS reminded_at;
+ALTER TABLE teams DROP COLUMN IF EXISTS review_sla_minutes;
@@ -0,0 +1,29 @@
+-- +goose Up
+ALTER TABLE teams ADD COLUMN IF NOT EXISTS sla_action VARCHAR(16) NOT NULL DEFAULT 'remind'
+    CONSTRAINT chk_teams_sla_action CHECK (sla_action IN ('remind', 'reassign', 'escalate'));
+ALTER TABLE teams ADD COLUMN IF NOT EXISTS lead_id VARCHAR(255)
+    CONSTRAINT fk_teams_lead REFERENCES users(id) ON DELETE SET NULL;
+ALTER TABLE teams ADD COLUMN IF NOT EXISTS timezone VARCHAR(64) NOT NULL DEFAULT 'UTC';
+-- weekend_days is a bit set of days off: bit i is time.Weekday i (Sunday = 0); 65 = Saturday and Sunday
+ALTER TABLE teams ADD COLUMN IF NOT EXISTS weekend_days SMALLINT NOT NULL DEFAULT 65
+    CONSTRAINT chk_teams_weekend_days CHECK (weekend_days >= 0 AND weekend_days < 127);
+
+CREATE TABLE IF NOT EXISTS reviewer_reassignments (
+    id VARCHAR(64) PRIMARY KEY,
+    pr_id VARCHAR(255) NOT NULL,
+    old_reviewer_id VARCHAR(255) NOT NULL,
+    new_reviewer_id VARCHAR(255) NOT NULL,
+    reason VARCHAR(20) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT fk_reviewer_reassignments_pr FOREIGN KEY (pr_id) REFERENCES pull_requests(id) ON DELETE CASCADE,
+    CONSTRAINT chk_reviewer_reassignments_reason CHECK (reason IN ('manual', 'sla_timeout'))
+);
+
+CREATE INDEX idx_reviewer_reassignments_pr ON reviewer_reassignments(pr_id, created_at);
+
+-- +goose Down
+DROP TABLE IF EXISTS reviewer_reassignments CASCADE;
+ALTER TABLE teams DROP COLUMN IF EXISTS weekend_days;
+ALTER TABLE teams DROP COLUMN IF EXISTS timezone;
+ALTER TABLE teams DROP COLUMN IF EXISTS lead_id;
+ALTER TABLE teams DROP COLUMN IF EXISTS sla_action;
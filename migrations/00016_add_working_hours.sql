@@ -0,0 +1,18 @@
+-- +goose Up
+ALTER TABLE users ADD COLUMN IF NOT EXISTS timezone VARCHAR(64) NOT NULL DEFAULT '';
+-- Working hours in minutes from midnight in the user's timezone; equal values mean not set
+ALTER TABLE users ADD COLUMN IF NOT EXISTS work_start_minute SMALLINT NOT NULL DEFAULT 0
+    CONSTRAINT chk_users_work_start_minute CHECK (work_start_minute >= 0 AND work_start_minute < 1440);
+ALTER TABLE users ADD COLUMN IF NOT EXISTS work_end_minute SMALLINT NOT NULL DEFAULT 0
+    CONSTRAINT chk_users_work_end_minute CHECK (work_end_minute >= 0 AND work_end_minute < 1440);
+
+ALTER TABLE teams ADD COLUMN IF NOT EXISTS prefer_working_hours BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE teams ADD COLUMN IF NOT EXISTS prefer_within_hours INTEGER NOT NULL DEFAULT 0
+    CONSTRAINT chk_teams_prefer_within_hours CHECK (prefer_within_hours >= 0);
+
+-- +goose Down
+ALTER TABLE teams DROP COLUMN IF EXISTS prefer_within_hours;
+ALTER TABLE teams DROP COLUMN IF EXISTS prefer_working_hours;
+ALTER TABLE users DROP COLUMN IF EXISTS work_end_minute;
+ALTER TABLE users DROP COLUMN IF EXISTS work_start_minute;
+ALTER TABLE users DROP COLUMN IF EXISTS timezone;
@@ -0,0 +1,14 @@
+-- +goose Up
+-- Rules of the team CODEOWNERS file in file order; owners are stored as written, separated by spaces
+CREATE TABLE IF NOT EXISTS code_owner_rules (
+    team_name VARCHAR(255) NOT NULL,
+    position INTEGER NOT NULL,
+    line INTEGER NOT NULL,
+    pattern TEXT NOT NULL,
+    owners TEXT NOT NULL DEFAULT '',
+    PRIMARY KEY (team_name, position),
+    CONSTRAINT fk_code_owner_rules_team FOREIGN KEY (team_name) REFERENCES teams(name) ON DELETE CASCADE
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS code_owner_rules CASCADE;
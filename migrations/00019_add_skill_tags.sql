@@ -0,0 +1,8 @@
+-- +goose Up
+-- Skills of a user and skills a pull request needs from its reviewers, lowercase and sorted
+ALTER TABLE users ADD COLUMN IF NOT EXISTS skill_tags TEXT[] NOT NULL DEFAULT '{}';
+ALTER TABLE pull_requests ADD COLUMN IF NOT EXISTS required_tags TEXT[] NOT NULL DEFAULT '{}';
+
+-- +goose Down
+ALTER TABLE pull_requests DROP COLUMN IF EXISTS required_tags;
+ALTER TABLE users DROP COLUMN IF EXISTS skill_tags;
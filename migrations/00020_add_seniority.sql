@@ -0,0 +1,11 @@
+-- +goose Up
+-- Reviewer levels in order junior < middle < senior; an empty level is below all of them
+ALTER TABLE users ADD COLUMN IF NOT EXISTS seniority VARCHAR(16) NOT NULL DEFAULT ''
+    CONSTRAINT chk_users_seniority CHECK (seniority IN ('', 'junior', 'middle', 'senior'));
+-- At least one reviewer of a PR must be at or above this level; empty means no policy
+ALTER TABLE teams ADD COLUMN IF NOT EXISTS min_reviewer_seniority VARCHAR(16) NOT NULL DEFAULT ''
+    CONSTRAINT chk_teams_min_reviewer_seniority CHECK (min_reviewer_seniority IN ('', 'junior', 'middle', 'senior'));
+
+-- +goose Down
+ALTER TABLE teams DROP COLUMN IF EXISTS min_reviewer_seniority;
+ALTER TABLE users DROP COLUMN IF EXISTS seniority;
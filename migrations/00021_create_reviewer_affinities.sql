@@ -0,0 +1,21 @@
+-- +goose Up
+-- How an author relates to a possible reviewer: a negative weight forbids the pair in both
+-- directions, a positive one makes the reviewer more likely to be picked, zero is neutral
+CREATE TABLE IF NOT EXISTS reviewer_affinities (
+    author_id VARCHAR(255) NOT NULL,
+    reviewer_id VARCHAR(255) NOT NULL,
+    weight INTEGER NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (author_id, reviewer_id),
+    CONSTRAINT fk_reviewer_affinities_author FOREIGN KEY (author_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT fk_reviewer_affinities_reviewer FOREIGN KEY (reviewer_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT chk_reviewer_affinities_pair CHECK (author_id <> reviewer_id),
+    CONSTRAINT chk_reviewer_affinities_weight CHECK (weight BETWEEN -10 AND 10)
+);
+
+CREATE INDEX idx_reviewer_affinities_reviewer ON reviewer_affinities(reviewer_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS reviewer_affinities CASCADE;
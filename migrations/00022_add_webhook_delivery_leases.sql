@@ -0,0 +1,6 @@
+-- +goose Up
+-- A delivery claimed by a dispatcher is leased until locked_until so other replicas skip it
+ALTER TABLE webhook_deliveries ADD COLUMN IF NOT EXISTS locked_until TIMESTAMPTZ;
+
+-- +goose Down
+ALTER TABLE webhook_deliveries DROP COLUMN IF EXISTS locked_until;
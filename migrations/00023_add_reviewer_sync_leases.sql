@@ -0,0 +1,6 @@
+-- +goose Up
+-- A sync claimed by a syncer is leased until locked_until so other replicas skip it
+ALTER TABLE reviewer_syncs ADD COLUMN IF NOT EXISTS locked_until TIMESTAMPTZ;
+
+-- +goose Down
+ALTER TABLE reviewer_syncs DROP COLUMN IF EXISTS locked_until;
@@ -0,0 +1,25 @@
+-- +goose Up
+-- A failed message is retried at next_attempt_at by the sinks not listed in published_sinks;
+-- after the last attempt it is dead-lettered with status 'failed'
+ALTER TABLE outbox
+    ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    ADD COLUMN IF NOT EXISTS next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    ADD COLUMN IF NOT EXISTS published_sinks TEXT[] NOT NULL DEFAULT '{}';
+
+UPDATE outbox SET status = 'published' WHERE published_at IS NOT NULL;
+UPDATE outbox SET next_attempt_at = created_at;
+
+ALTER TABLE outbox ADD CONSTRAINT chk_outbox_status CHECK (status IN ('pending', 'published', 'failed'));
+
+DROP INDEX IF EXISTS idx_outbox_pending;
+CREATE INDEX idx_outbox_pending ON outbox(seq) WHERE status = 'pending';
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_outbox_pending;
+CREATE INDEX idx_outbox_pending ON outbox(seq) WHERE published_at IS NULL;
+
+ALTER TABLE outbox DROP CONSTRAINT IF EXISTS chk_outbox_status;
+ALTER TABLE outbox
+    DROP COLUMN IF EXISTS published_sinks,
+    DROP COLUMN IF EXISTS next_attempt_at,
+    DROP COLUMN IF EXISTS status;
@@ -0,0 +1,9 @@
+-- +goose Up
+-- Syncs of a PR are performed in the order of their events; seq keeps that order
+ALTER TABLE reviewer_syncs ADD COLUMN IF NOT EXISTS seq BIGSERIAL;
+
+CREATE INDEX idx_reviewer_syncs_pr_pending ON reviewer_syncs(pull_request_id, seq) WHERE status = 'pending';
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_reviewer_syncs_pr_pending;
+ALTER TABLE reviewer_syncs DROP COLUMN IF EXISTS seq;
@@ -0,0 +1,28 @@
+// Package migrations embeds the SQL migrations so the service binary can apply them itself
+package migrations
+
+import (
+	"embed"
+	"io/fs"
+)
+
+// FS contains all goose SQL migrations of the service (PostgreSQL)
+//
+//go:embed *.sql
+var FS embed.FS
+
+//go:embed sqlite/*.sql
+var sqliteFS embed.FS
+
+// SQLiteFS contains the SQLite variant of the migrations
+// Versions mirror FS one-to-one, so both backends report the same schema version
+var SQLiteFS = mustSub(sqliteFS, "sqlite")
+
+// mustSub returns the subtree of an embedded FS rooted at dir
+func mustSub(fsys fs.FS, dir string) fs.FS {
+	sub, err := fs.Sub(fsys, dir)
+	if err != nil {
+		panic(err)
+	}
+	return sub
+}
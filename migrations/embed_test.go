@@ -0,0 +1,60 @@
+package migrations
+
+import (
+	"fmt"
+	"io/fs"
+	"strings"
+	"testing"
+)
+
+func TestEmbeddedMigrations(t *testing.T) {
+	for name, fsys := range map[string]fs.FS{"postgres": FS, "sqlite": SQLiteFS} {
+		t.Run(name, func(t *testing.T) {
+			checkMigrations(t, fsys)
+		})
+	}
+}
+
+func TestSQLiteMirrorsPostgres(t *testing.T) {
+	postgres, err := fs.Glob(FS, "*.sql")
+	if err != nil {
+		t.Fatalf("glob: %v", err)
+	}
+	sqlite, err := fs.Glob(SQLiteFS, "*.sql")
+	if err != nil {
+		t.Fatalf("glob: %v", err)
+	}
+
+	if strings.Join(postgres, ",") != strings.Join(sqlite, ",") {
+		t.Errorf("sqlite migrations %v do not mirror postgres migrations %v", sqlite, postgres)
+	}
+}
+
+func checkMigrations(t *testing.T, fsys fs.FS) {
+	t.Helper()
+
+	names, err := fs.Glob(fsys, "*.sql")
+	if err != nil {
+		t.Fatalf("glob: %v", err)
+	}
+	if len(names) == 0 {
+		t.Fatal("no migrations embedded")
+	}
+
+	for i, name := range names {
+		// Versions must be consecutive so `migrate down` never skips a gap
+		if prefix := fmt.Sprintf("%05d_", i+1); !strings.HasPrefix(name, prefix) {
+			t.Errorf("migration %s: expected prefix %s", name, prefix)
+		}
+
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			t.Fatalf("read %s: %v", name, err)
+		}
+		for _, annotation := range []string{"-- +goose Up", "-- +goose Down"} {
+			if !strings.Contains(string(data), annotation) {
+				t.Errorf("migration %s: missing %q", name, annotation)
+			}
+		}
+	}
+}
@@ -0,0 +1,7 @@
+-- +goose Up
+
+-- Initial schema setup (SQLite)
+-- Versions mirror the PostgreSQL migrations one-to-one
+
+-- +goose Down
+-- Rollback initial schema
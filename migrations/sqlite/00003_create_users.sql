@@ -0,0 +1,17 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS users (
+    id TEXT PRIMARY KEY,
+    username TEXT NOT NULL,
+    team_name TEXT NOT NULL,
+    is_active BOOLEAN NOT NULL DEFAULT 1,
+    created_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now')),
+    updated_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now')),
+    CONSTRAINT fk_users_team FOREIGN KEY (team_name) REFERENCES teams(name) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_users_team_name ON users (team_name);
+CREATE INDEX idx_users_is_active ON users (is_active);
+CREATE INDEX idx_users_team_active ON users (team_name, is_active);
+
+-- +goose Down
+DROP TABLE IF EXISTS users;
@@ -0,0 +1,15 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS pr_reviewers (
+    pr_id TEXT NOT NULL,
+    reviewer_id TEXT NOT NULL,
+    assigned_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now')),
+    PRIMARY KEY (pr_id, reviewer_id),
+    CONSTRAINT fk_pr_reviewers_pr FOREIGN KEY (pr_id) REFERENCES pull_requests(id) ON DELETE CASCADE,
+    CONSTRAINT fk_pr_reviewers_user FOREIGN KEY (reviewer_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_pr_reviewers_reviewer_id ON pr_reviewers(reviewer_id);
+CREATE INDEX idx_pr_reviewers_assigned_at ON pr_reviewers(assigned_at);
+
+-- +goose Down
+DROP TABLE IF EXISTS pr_reviewers;
@@ -0,0 +1,16 @@
+-- +goose Up
+-- Insertion order is kept by rowid
+CREATE TABLE IF NOT EXISTS outbox (
+    id TEXT PRIMARY KEY,
+    event_type TEXT NOT NULL,
+    payload TEXT NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now')),
+    published_at TIMESTAMP
+);
+
+CREATE INDEX idx_outbox_pending ON outbox(published_at);
+
+-- +goose Down
+DROP TABLE IF EXISTS outbox;
@@ -0,0 +1,15 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS code_host_accounts (
+    provider TEXT NOT NULL,
+    login TEXT NOT NULL,
+    user_id TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now')),
+    PRIMARY KEY (provider, login),
+    CONSTRAINT fk_code_host_accounts_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT chk_code_host_accounts_provider CHECK (provider IN ('github', 'gitlab'))
+);
+
+CREATE INDEX idx_code_host_accounts_user ON code_host_accounts(user_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS code_host_accounts;
@@ -0,0 +1,22 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS reviewer_syncs (
+    id TEXT PRIMARY KEY,
+    event_id TEXT NOT NULL,
+    pull_request_id TEXT NOT NULL,
+    action TEXT NOT NULL,
+    login TEXT NOT NULL,
+    status TEXT NOT NULL DEFAULT 'pending',
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT NOT NULL DEFAULT '',
+    next_attempt_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now')),
+    created_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now')),
+    updated_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now')),
+    completed_at TIMESTAMP,
+    CONSTRAINT chk_reviewer_syncs_action CHECK (action IN ('request', 'remove')),
+    CONSTRAINT chk_reviewer_syncs_status CHECK (status IN ('pending', 'delivered', 'failed'))
+);
+
+CREATE INDEX idx_reviewer_syncs_due ON reviewer_syncs(next_attempt_at) WHERE status = 'pending';
+
+-- +goose Down
+DROP TABLE IF EXISTS reviewer_syncs;
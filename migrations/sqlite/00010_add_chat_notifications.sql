@@ -0,0 +1,7 @@
+-- +goose Up
+ALTER TABLE teams ADD COLUMN chat_webhook_url TEXT NOT NULL DEFAULT '';
+ALTER TABLE users ADD COLUMN mention_handle TEXT NOT NULL DEFAULT '';
+
+-- +goose Down
+ALTER TABLE users DROP COLUMN mention_handle;
+ALTER TABLE teams DROP COLUMN chat_webhook_url;
@@ -0,0 +1,7 @@
+-- +goose Up
+ALTER TABLE teams ADD COLUMN review_sla_minutes INTEGER NOT NULL DEFAULT 0 CHECK (review_sla_minutes >= 0);
+ALTER TABLE pr_reviewers ADD COLUMN reminded_at TIMESTAMP;
+
+-- +goose Down
+ALTER TABLE pr_reviewers DROP COLUMN reminded_at;
+ALTER TABLE teams DROP COLUMN review_sla_minutes;
@@ -0,0 +1,29 @@
+-- +goose Up
+ALTER TABLE teams ADD COLUMN sla_action TEXT NOT NULL DEFAULT 'remind'
+    CONSTRAINT chk_teams_sla_action CHECK (sla_action IN ('remind', 'reassign', 'escalate'));
+-- No foreign key: SQLite cannot drop a column that has one; the service checks that the lead is a member
+ALTER TABLE teams ADD COLUMN lead_id TEXT;
+ALTER TABLE teams ADD COLUMN timezone TEXT NOT NULL DEFAULT 'UTC';
+-- weekend_days is a bit set of days off: bit i is time.Weekday i (Sunday = 0); 65 = Saturday and Sunday
+ALTER TABLE teams ADD COLUMN weekend_days INTEGER NOT NULL DEFAULT 65
+    CONSTRAINT chk_teams_weekend_days CHECK (weekend_days >= 0 AND weekend_days < 127);
+
+CREATE TABLE IF NOT EXISTS reviewer_reassignments (
+    id TEXT PRIMARY KEY,
+    pr_id TEXT NOT NULL,
+    old_reviewer_id TEXT NOT NULL,
+    new_reviewer_id TEXT NOT NULL,
+    reason TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now')),
+    CONSTRAINT fk_reviewer_reassignments_pr FOREIGN KEY (pr_id) REFERENCES pull_requests(id) ON DELETE CASCADE,
+    CONSTRAINT chk_reviewer_reassignments_reason CHECK (reason IN ('manual', 'sla_timeout'))
+);
+
+CREATE INDEX idx_reviewer_reassignments_pr ON reviewer_reassignments(pr_id, created_at);
+
+-- +goose Down
+DROP TABLE IF EXISTS reviewer_reassignments;
+ALTER TABLE teams DROP COLUMN weekend_days;
+ALTER TABLE teams DROP COLUMN timezone;
+ALTER TABLE teams DROP COLUMN lead_id;
+ALTER TABLE teams DROP COLUMN sla_action;
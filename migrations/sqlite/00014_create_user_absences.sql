@@ -0,0 +1,17 @@
+-- +goose Up
+-- Dates are stored as YYYY-MM-DD text, so they compare correctly as strings
+CREATE TABLE IF NOT EXISTS user_absences (
+    id TEXT PRIMARY KEY,
+    user_id TEXT NOT NULL,
+    start_date TEXT NOT NULL,
+    end_date TEXT NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now')),
+    CONSTRAINT fk_user_absences_user FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    CONSTRAINT chk_user_absences_dates CHECK (end_date >= start_date)
+);
+
+CREATE INDEX idx_user_absences_user ON user_absences(user_id, end_date);
+
+-- +goose Down
+DROP TABLE IF EXISTS user_absences;
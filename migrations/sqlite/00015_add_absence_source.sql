@@ -0,0 +1,8 @@
+-- +goose Up
+ALTER TABLE user_absences ADD COLUMN source_uid TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX idx_user_absences_source ON user_absences(user_id, source_uid) WHERE source_uid <> '';
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_user_absences_source;
+ALTER TABLE user_absences DROP COLUMN source_uid;
@@ -0,0 +1,19 @@
+-- +goose Up
+ALTER TABLE users ADD COLUMN timezone TEXT NOT NULL DEFAULT '';
+-- Working hours in minutes from midnight in the user's timezone; equal values mean not set
+ALTER TABLE users ADD COLUMN work_start_minute INTEGER NOT NULL DEFAULT 0
+    CONSTRAINT chk_users_work_start_minute CHECK (work_start_minute >= 0 AND work_start_minute < 1440);
+ALTER TABLE users ADD COLUMN work_end_minute INTEGER NOT NULL DEFAULT 0
+    CONSTRAINT chk_users_work_end_minute CHECK (work_end_minute >= 0 AND work_end_minute < 1440);
+
+-- prefer_working_hours is 0 or 1
+ALTER TABLE teams ADD COLUMN prefer_working_hours INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE teams ADD COLUMN prefer_within_hours INTEGER NOT NULL DEFAULT 0
+    CONSTRAINT chk_teams_prefer_within_hours CHECK (prefer_within_hours >= 0);
+
+-- +goose Down
+ALTER TABLE teams DROP COLUMN prefer_within_hours;
+ALTER TABLE teams DROP COLUMN prefer_working_hours;
+ALTER TABLE users DROP COLUMN work_end_minute;
+ALTER TABLE users DROP COLUMN work_start_minute;
+ALTER TABLE users DROP COLUMN timezone;
@@ -0,0 +1,7 @@
+-- +goose Up
+-- Open reviews a user may have at once; 0 means no limit
+ALTER TABLE users ADD COLUMN max_open_reviews INTEGER NOT NULL DEFAULT 0
+    CONSTRAINT chk_users_max_open_reviews CHECK (max_open_reviews >= 0);
+
+-- +goose Down
+ALTER TABLE users DROP COLUMN max_open_reviews;
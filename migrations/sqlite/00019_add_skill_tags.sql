@@ -0,0 +1,9 @@
+-- +goose Up
+-- Skills of a user and skills a pull request needs from its reviewers, lowercase and sorted
+-- Tags are stored as JSON arrays
+ALTER TABLE users ADD COLUMN skill_tags TEXT NOT NULL DEFAULT '[]';
+ALTER TABLE pull_requests ADD COLUMN required_tags TEXT NOT NULL DEFAULT '[]';
+
+-- +goose Down
+ALTER TABLE pull_requests DROP COLUMN required_tags;
+ALTER TABLE users DROP COLUMN skill_tags;
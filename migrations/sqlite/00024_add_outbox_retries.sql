@@ -0,0 +1,20 @@
+-- +goose Up
+-- A failed message is retried at next_attempt_at by the sinks not listed in published_sinks
+-- (a JSON array); after the last attempt it is dead-lettered with status 'failed'
+ALTER TABLE outbox ADD COLUMN status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'published', 'failed'));
+ALTER TABLE outbox ADD COLUMN next_attempt_at TIMESTAMP NOT NULL DEFAULT '1970-01-01 00:00:00.000';
+ALTER TABLE outbox ADD COLUMN published_sinks TEXT NOT NULL DEFAULT '[]';
+
+UPDATE outbox SET status = 'published' WHERE published_at IS NOT NULL;
+UPDATE outbox SET next_attempt_at = created_at;
+
+DROP INDEX IF EXISTS idx_outbox_pending;
+CREATE INDEX idx_outbox_pending ON outbox(status, next_attempt_at);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_outbox_pending;
+CREATE INDEX idx_outbox_pending ON outbox(published_at);
+
+ALTER TABLE outbox DROP COLUMN published_sinks;
+ALTER TABLE outbox DROP COLUMN next_attempt_at;
+ALTER TABLE outbox DROP COLUMN status;
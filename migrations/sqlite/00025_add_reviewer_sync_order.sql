@@ -0,0 +1,6 @@
+-- +goose Up
+-- Syncs of a PR are performed in the order of their events; rowid keeps that order
+CREATE INDEX idx_reviewer_syncs_pr_pending ON reviewer_syncs(pull_request_id) WHERE status = 'pending';
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_reviewer_syncs_pr_pending;
@@ -0,0 +1,11 @@
+-- +goose Up
+-- A conflict of interest is a flag of its own, so a negative weight only makes a reviewer less likely
+ALTER TABLE reviewer_affinities ADD COLUMN conflict BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- Negative weights used to mean a conflict
+UPDATE reviewer_affinities SET conflict = TRUE, weight = 0 WHERE weight < 0;
+
+-- +goose Down
+UPDATE reviewer_affinities SET weight = 0 WHERE weight < 0;
+UPDATE reviewer_affinities SET weight = -1 WHERE conflict;
+ALTER TABLE reviewer_affinities DROP COLUMN conflict;
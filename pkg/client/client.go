@@ -0,0 +1,225 @@
+// Package client is a Go SDK for the PR Reviewer Assignment Service HTTP API
+//
+// Usage:
+//
+//	c, err := client.New("http://localhost:8080", client.WithRetry(3, 200*time.Millisecond))
+//	if err != nil { ... }
+//	resp, err := c.CreatePR(ctx, &request.CreatePRRequest{...})
+//	if client.IsErrorCode(err, response.ErrorCodePRExists) { ... }
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	defaultTimeout      = 10 * time.Second
+	defaultMaxAttempts  = 1
+	defaultRetryBackoff = 200 * time.Millisecond
+	maxRetryBackoff     = 5 * time.Second
+)
+
+// Client is a typed client for the PR Reviewer Assignment Service
+// It is safe for concurrent use
+type Client struct {
+	baseURL      *url.URL
+	httpClient   *http.Client
+	maxAttempts  int
+	retryBackoff time.Duration
+}
+
+// Option configures a Client
+type Option func(*Client)
+
+// WithHTTPClient sets the underlying HTTP client
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithTimeout sets the per-attempt timeout of the default HTTP client
+// Use context deadlines to bound the total time of a call including retries
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.httpClient = &http.Client{Timeout: timeout}
+	}
+}
+
+// WithRetry enables retries of transient failures (network errors, 429, 502, 503, 504)
+// maxAttempts includes the first attempt; the delay between attempts doubles starting from backoff
+//
+// Note that POST operations are retried too: a retried CreatePR whose first attempt
+// actually reached the server fails with PR_EXISTS
+func WithRetry(maxAttempts int, backoff time.Duration) Option {
+	return func(c *Client) {
+		if maxAttempts < 1 {
+			maxAttempts = 1
+		}
+		c.maxAttempts = maxAttempts
+		c.retryBackoff = backoff
+	}
+}
+
+// New creates a new client for the service available at baseURL
+func New(baseURL string, opts ...Option) (*Client, error) {
+	parsed, err := url.Parse(strings.TrimRight(baseURL, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid base URL: %w", err)
+	}
+	if parsed.Scheme == "" || parsed.Host == "" {
+		return nil, fmt.Errorf("invalid base URL %q: scheme and host are required", baseURL)
+	}
+
+	c := &Client{
+		baseURL:      parsed,
+		httpClient:   &http.Client{Timeout: defaultTimeout},
+		maxAttempts:  defaultMaxAttempts,
+		retryBackoff: defaultRetryBackoff,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c, nil
+}
+
+// Health checks that the service is up
+func (c *Client) Health(ctx context.Context) error {
+	return c.do(ctx, http.MethodGet, "/health", nil, nil, nil)
+}
+
+// OpenAPISpec returns the OpenAPI document served by the service
+func (c *Client) OpenAPISpec(ctx context.Context) (json.RawMessage, error) {
+	var spec json.RawMessage
+	if err := c.do(ctx, http.MethodGet, "/openapi.json", nil, nil, &spec); err != nil {
+		return nil, err
+	}
+	return spec, nil
+}
+
+// do sends body as JSON, retrying transient failures, and decodes the JSON response into out
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body, out interface{}) error {
+	var payload []byte
+	if body != nil {
+		var err error
+		payload, err = json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request body: %w", err)
+		}
+	}
+	return c.send(ctx, method, path, query, "application/json", payload, out)
+}
+
+// send performs a request with a raw body of the given content type, retrying transient failures
+func (c *Client) send(ctx context.Context, method, path string, query url.Values, contentType string, payload []byte, out interface{}) error {
+	endpoint := c.baseURL.JoinPath(path)
+	endpoint.RawQuery = query.Encode()
+
+	backoff := c.retryBackoff
+	for attempt := 1; ; attempt++ {
+		err := c.doOnce(ctx, method, endpoint.String(), contentType, payload, out)
+		if err == nil || attempt >= c.maxAttempts || !isRetryable(err) {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+		if backoff > maxRetryBackoff {
+			backoff = maxRetryBackoff
+		}
+	}
+}
+
+// doOnce performs a single HTTP attempt
+func (c *Client) doOnce(ctx context.Context, method, endpoint, contentType string, payload []byte, out interface{}) error {
+	var reqBody io.Reader
+	if payload != nil {
+		reqBody = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, reqBody)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if payload != nil {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return &transportError{err: err}
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return &transportError{err: fmt.Errorf("failed to read response body: %w", err)}
+	}
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return newAPIError(resp.StatusCode, respBody)
+	}
+
+	if out == nil || len(respBody) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(respBody, out); err != nil {
+		return fmt.Errorf("failed to decode response (body: %s): %w", string(respBody), err)
+	}
+
+	return nil
+}
+
+// transportError marks failures that happened before an HTTP response was received
+type transportError struct {
+	err error
+}
+
+func (e *transportError) Error() string {
+	return e.err.Error()
+}
+
+func (e *transportError) Unwrap() error {
+	return e.err
+}
+
+// isRetryable reports whether a failed attempt may succeed if repeated
+func isRetryable(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var transportErr *transportError
+	if errors.As(err, &transportErr) {
+		return true
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		switch apiErr.StatusCode {
+		case http.StatusTooManyRequests,
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout:
+			return true
+		}
+	}
+
+	return false
+}
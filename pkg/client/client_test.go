@@ -0,0 +1,207 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"avito-backend-trainee-assignment-autumn-2025/pkg/dto/request"
+	"avito-backend-trainee-assignment-autumn-2025/pkg/dto/response"
+)
+
+func newTestClient(t *testing.T, handler http.HandlerFunc, opts ...Option) *Client {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	c, err := New(server.URL, opts...)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	return c
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func TestNew_InvalidBaseURL(t *testing.T) {
+	for _, baseURL := range []string{"", "localhost:8080", "://bad"} {
+		if _, err := New(baseURL); err == nil {
+			t.Errorf("expected error for base URL %q", baseURL)
+		}
+	}
+}
+
+func TestCreatePR(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/pullRequest/create" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+
+		var req request.CreatePRRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("decode request: %v", err)
+		}
+
+		writeJSON(w, http.StatusCreated, response.CreatePRResponse{PR: response.PullRequestResponse{
+			PullRequestID:     req.PullRequestID,
+			PullRequestName:   req.PullRequestName,
+			AuthorID:          req.AuthorID,
+			Status:            "OPEN",
+			AssignedReviewers: []string{"u2"},
+		}})
+	})
+
+	resp, err := c.CreatePR(context.Background(), &request.CreatePRRequest{
+		PullRequestID:   "pr-1",
+		PullRequestName: "Feature",
+		AuthorID:        "u1",
+	})
+	if err != nil {
+		t.Fatalf("CreatePR: %v", err)
+	}
+	if resp.PR.PullRequestID != "pr-1" || len(resp.PR.AssignedReviewers) != 1 {
+		t.Errorf("unexpected response: %+v", resp.PR)
+	}
+}
+
+func TestGetTeam_QueryEscaping(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("team_name"); got != "back end&co" {
+			t.Errorf("team_name = %q", got)
+		}
+		writeJSON(w, http.StatusOK, response.TeamResponse{TeamName: "back end&co"})
+	})
+
+	resp, err := c.GetTeam(context.Background(), "back end&co")
+	if err != nil {
+		t.Fatalf("GetTeam: %v", err)
+	}
+	if resp.TeamName != "back end&co" {
+		t.Errorf("TeamName = %q", resp.TeamName)
+	}
+}
+
+func TestAPIError(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		errorResp := response.NewErrorResponse(response.ErrorCodeValidation, "author_id is required")
+		errorResp.Error.Field = "author_id"
+		writeJSON(w, http.StatusBadRequest, errorResp)
+	})
+
+	_, err := c.CreatePR(context.Background(), &request.CreatePRRequest{})
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected *APIError, got %v", err)
+	}
+	if apiErr.StatusCode != http.StatusBadRequest || apiErr.Field != "author_id" {
+		t.Errorf("unexpected error: %+v", apiErr)
+	}
+	if !IsErrorCode(err, response.ErrorCodeValidation) {
+		t.Errorf("IsErrorCode(VALIDATION_ERROR) = false for %v", err)
+	}
+	if IsErrorCode(err, response.ErrorCodeNotFound) {
+		t.Errorf("IsErrorCode(NOT_FOUND) = true for %v", err)
+	}
+}
+
+func TestAPIError_NonJSONBody(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "upstream down", http.StatusBadGateway)
+	})
+
+	err := c.Health(context.Background())
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected *APIError, got %v", err)
+	}
+	if apiErr.StatusCode != http.StatusBadGateway || apiErr.Code != "" {
+		t.Errorf("unexpected error: %+v", apiErr)
+	}
+}
+
+func TestRetry_TransientStatus(t *testing.T) {
+	var calls atomic.Int32
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		writeJSON(w, http.StatusOK, response.MergePRResponse{PR: response.PullRequestResponse{Status: "MERGED"}})
+	}, WithRetry(3, time.Millisecond))
+
+	resp, err := c.MergePR(context.Background(), &request.MergePRRequest{PullRequestID: "pr-1"})
+	if err != nil {
+		t.Fatalf("MergePR: %v", err)
+	}
+	if resp.PR.Status != "MERGED" {
+		t.Errorf("Status = %q", resp.PR.Status)
+	}
+	if got := calls.Load(); got != 3 {
+		t.Errorf("calls = %d, want 3", got)
+	}
+}
+
+func TestRetry_NotForDomainErrors(t *testing.T) {
+	var calls atomic.Int32
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		writeJSON(w, http.StatusConflict, response.NewErrorResponse(response.ErrorCodePRMerged, "merged"))
+	}, WithRetry(5, time.Millisecond))
+
+	_, err := c.ReassignReviewer(context.Background(), &request.ReassignReviewerRequest{PullRequestID: "pr-1", OldUserID: "u2"})
+	if !IsErrorCode(err, response.ErrorCodePRMerged) {
+		t.Fatalf("expected PR_MERGED, got %v", err)
+	}
+	if got := calls.Load(); got != 1 {
+		t.Errorf("calls = %d, want 1", got)
+	}
+}
+
+func TestRetry_StopsOnContextDeadline(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}, WithRetry(100, 50*time.Millisecond))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 80*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err := c.Health(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("retries ignored context deadline: %v", elapsed)
+	}
+}
+
+func TestListWebhookDeliveries_StatusFilter(t *testing.T) {
+	var queries []string
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/webhooks/deliveries" {
+			t.Errorf("path = %s", r.URL.Path)
+		}
+		queries = append(queries, r.URL.RawQuery)
+		writeJSON(w, http.StatusOK, response.ListWebhookDeliveriesResponse{})
+	})
+
+	for _, status := range []string{"failed", ""} {
+		if _, err := c.ListWebhookDeliveries(context.Background(), status); err != nil {
+			t.Fatalf("ListWebhookDeliveries(%q): %v", status, err)
+		}
+	}
+	if len(queries) != 2 || queries[0] != "status=failed" || queries[1] != "" {
+		t.Errorf("queries = %q", queries)
+	}
+}
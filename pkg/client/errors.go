@@ -0,0 +1,52 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"avito-backend-trainee-assignment-autumn-2025/pkg/dto/response"
+)
+
+// APIError is returned when the service responds with an error status
+type APIError struct {
+	StatusCode int
+	Code       response.ErrorCode
+	Message    string
+	Field      string
+}
+
+// Error implements the error interface
+func (e *APIError) Error() string {
+	if e.Field != "" {
+		return fmt.Sprintf("%s (HTTP %d, field %s): %s", e.Code, e.StatusCode, e.Field, e.Message)
+	}
+	return fmt.Sprintf("%s (HTTP %d): %s", e.Code, e.StatusCode, e.Message)
+}
+
+// IsErrorCode reports whether err is an APIError with the given code
+func IsErrorCode(err error, code response.ErrorCode) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.Code == code
+}
+
+// newAPIError builds an APIError from an error response body
+// Bodies that are not in the standard error format are reported with an empty code
+func newAPIError(statusCode int, body []byte) *APIError {
+	apiErr := &APIError{
+		StatusCode: statusCode,
+		Message:    http.StatusText(statusCode),
+	}
+
+	var errorResponse response.ErrorResponse
+	if err := json.Unmarshal(body, &errorResponse); err == nil && errorResponse.Error.Code != "" {
+		apiErr.Code = errorResponse.Error.Code
+		apiErr.Message = errorResponse.Error.Message
+		apiErr.Field = errorResponse.Error.Field
+	} else if len(body) > 0 {
+		apiErr.Message = string(body)
+	}
+
+	return apiErr
+}
@@ -0,0 +1,32 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"avito-backend-trainee-assignment-autumn-2025/pkg/dto/request"
+	"avito-backend-trainee-assignment-autumn-2025/pkg/dto/response"
+)
+
+// LinkAccount calls POST /integrations/accounts/link
+func (c *Client) LinkAccount(ctx context.Context, req *request.LinkAccountRequest) (*response.LinkAccountResponse, error) {
+	var resp response.LinkAccountResponse
+	if err := c.do(ctx, http.MethodPost, "/integrations/accounts/link", nil, req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// ListAccounts calls GET /integrations/accounts/list; an empty provider lists accounts of all code hosts
+func (c *Client) ListAccounts(ctx context.Context, provider string) (*response.ListAccountsResponse, error) {
+	var resp response.ListAccountsResponse
+	var query url.Values
+	if provider != "" {
+		query = url.Values{"provider": {provider}}
+	}
+	if err := c.do(ctx, http.MethodGet, "/integrations/accounts/list", query, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
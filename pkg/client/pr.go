@@ -0,0 +1,47 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"avito-backend-trainee-assignment-autumn-2025/pkg/dto/request"
+	"avito-backend-trainee-assignment-autumn-2025/pkg/dto/response"
+)
+
+// CreatePR calls POST /pullRequest/create
+func (c *Client) CreatePR(ctx context.Context, req *request.CreatePRRequest) (*response.CreatePRResponse, error) {
+	var resp response.CreatePRResponse
+	if err := c.do(ctx, http.MethodPost, "/pullRequest/create", nil, req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// MergePR calls POST /pullRequest/merge
+func (c *Client) MergePR(ctx context.Context, req *request.MergePRRequest) (*response.MergePRResponse, error) {
+	var resp response.MergePRResponse
+	if err := c.do(ctx, http.MethodPost, "/pullRequest/merge", nil, req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// ReassignReviewer calls POST /pullRequest/reassign
+func (c *Client) ReassignReviewer(ctx context.Context, req *request.ReassignReviewerRequest) (*response.ReassignReviewerResponse, error) {
+	var resp response.ReassignReviewerResponse
+	if err := c.do(ctx, http.MethodPost, "/pullRequest/reassign", nil, req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GetPRHistory calls GET /pullRequest/history
+func (c *Client) GetPRHistory(ctx context.Context, prID string) (*response.GetPRHistoryResponse, error) {
+	var resp response.GetPRHistoryResponse
+	query := url.Values{"pull_request_id": {prID}}
+	if err := c.do(ctx, http.MethodGet, "/pullRequest/history", query, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
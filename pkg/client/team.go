@@ -0,0 +1,108 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"avito-backend-trainee-assignment-autumn-2025/pkg/dto/request"
+	"avito-backend-trainee-assignment-autumn-2025/pkg/dto/response"
+)
+
+// CreateTeam calls POST /team/add
+func (c *Client) CreateTeam(ctx context.Context, req *request.CreateTeamRequest) (*response.CreateTeamResponse, error) {
+	var resp response.CreateTeamResponse
+	if err := c.do(ctx, http.MethodPost, "/team/add", nil, req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GetTeam calls GET /team/get
+func (c *Client) GetTeam(ctx context.Context, teamName string) (*response.TeamResponse, error) {
+	var resp response.TeamResponse
+	query := url.Values{"team_name": {teamName}}
+	if err := c.do(ctx, http.MethodGet, "/team/get", query, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// SetChatWebhook calls POST /team/setChatWebhook; an empty URL disables chat notifications
+func (c *Client) SetChatWebhook(ctx context.Context, req *request.SetChatWebhookRequest) (*response.SetChatWebhookResponse, error) {
+	var resp response.SetChatWebhookResponse
+	if err := c.do(ctx, http.MethodPost, "/team/setChatWebhook", nil, req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// SetReviewSLA calls POST /team/setReviewSLA; 0 minutes restores the default SLA
+func (c *Client) SetReviewSLA(ctx context.Context, req *request.SetReviewSLARequest) (*response.SetReviewSLAResponse, error) {
+	var resp response.SetReviewSLAResponse
+	if err := c.do(ctx, http.MethodPost, "/team/setReviewSLA", nil, req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// SetLead calls POST /team/setLead; an empty lead_id removes the lead
+func (c *Client) SetLead(ctx context.Context, req *request.SetLeadRequest) (*response.SetLeadResponse, error) {
+	var resp response.SetLeadResponse
+	if err := c.do(ctx, http.MethodPost, "/team/setLead", nil, req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// SetCalendar calls POST /team/setCalendar
+func (c *Client) SetCalendar(ctx context.Context, req *request.SetCalendarRequest) (*response.SetCalendarResponse, error) {
+	var resp response.SetCalendarResponse
+	if err := c.do(ctx, http.MethodPost, "/team/setCalendar", nil, req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// SetWorkingHoursPreference calls POST /team/setWorkingHoursPreference
+func (c *Client) SetWorkingHoursPreference(
+	ctx context.Context,
+	req *request.SetWorkingHoursPreferenceRequest,
+) (*response.SetWorkingHoursPreferenceResponse, error) {
+	var resp response.SetWorkingHoursPreferenceResponse
+	if err := c.do(ctx, http.MethodPost, "/team/setWorkingHoursPreference", nil, req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// SetSeniorityPolicy calls POST /team/setSeniorityPolicy; an empty level removes the policy
+func (c *Client) SetSeniorityPolicy(ctx context.Context, req *request.SetSeniorityPolicyRequest) (*response.SetSeniorityPolicyResponse, error) {
+	var resp response.SetSeniorityPolicyResponse
+	if err := c.do(ctx, http.MethodPost, "/team/setSeniorityPolicy", nil, req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// UploadCodeOwners calls POST /team/codeowners/upload with the contents of a CODEOWNERS file
+// With dryRun the file is only validated and the stored rules are kept
+func (c *Client) UploadCodeOwners(ctx context.Context, teamName string, file []byte, dryRun bool) (*response.UploadCodeOwnersResponse, error) {
+	var resp response.UploadCodeOwnersResponse
+	query := url.Values{"team_name": {teamName}, "dry_run": {strconv.FormatBool(dryRun)}}
+	if err := c.send(ctx, http.MethodPost, "/team/codeowners/upload", query, "text/plain", file, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GetCodeOwners calls GET /team/codeowners
+func (c *Client) GetCodeOwners(ctx context.Context, teamName string) (*response.GetCodeOwnersResponse, error) {
+	var resp response.GetCodeOwnersResponse
+	query := url.Values{"team_name": {teamName}}
+	if err := c.do(ctx, http.MethodGet, "/team/codeowners", query, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
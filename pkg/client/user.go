@@ -0,0 +1,178 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"avito-backend-trainee-assignment-autumn-2025/pkg/dto/request"
+	"avito-backend-trainee-assignment-autumn-2025/pkg/dto/response"
+)
+
+// SetUserActive calls POST /users/setIsActive
+func (c *Client) SetUserActive(ctx context.Context, req *request.SetUserActiveRequest) (*response.SetUserActiveResponse, error) {
+	var resp response.SetUserActiveResponse
+	if err := c.do(ctx, http.MethodPost, "/users/setIsActive", nil, req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GetUserReviews calls GET /users/getReview
+func (c *Client) GetUserReviews(ctx context.Context, userID string) (*response.GetUserReviewsResponse, error) {
+	var resp response.GetUserReviewsResponse
+	query := url.Values{"user_id": {userID}}
+	if err := c.do(ctx, http.MethodGet, "/users/getReview", query, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// SetMentionHandle calls POST /users/setMentionHandle; an empty handle clears it
+func (c *Client) SetMentionHandle(ctx context.Context, req *request.SetMentionHandleRequest) (*response.SetMentionHandleResponse, error) {
+	var resp response.SetMentionHandleResponse
+	if err := c.do(ctx, http.MethodPost, "/users/setMentionHandle", nil, req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// SetEmail calls POST /users/setEmail; an empty address clears it
+func (c *Client) SetEmail(ctx context.Context, req *request.SetEmailRequest) (*response.SetEmailResponse, error) {
+	var resp response.SetEmailResponse
+	if err := c.do(ctx, http.MethodPost, "/users/setEmail", nil, req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// SetWorkingHours calls POST /users/setWorkingHours; omitting both hours clears them
+func (c *Client) SetWorkingHours(ctx context.Context, req *request.SetWorkingHoursRequest) (*response.SetWorkingHoursResponse, error) {
+	var resp response.SetWorkingHoursResponse
+	if err := c.do(ctx, http.MethodPost, "/users/setWorkingHours", nil, req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// SetMaxOpenReviews calls POST /users/setMaxOpenReviews; zero removes the limit
+func (c *Client) SetMaxOpenReviews(ctx context.Context, req *request.SetMaxOpenReviewsRequest) (*response.SetMaxOpenReviewsResponse, error) {
+	var resp response.SetMaxOpenReviewsResponse
+	if err := c.do(ctx, http.MethodPost, "/users/setMaxOpenReviews", nil, req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// SetSeniority calls POST /users/setSeniority; an empty level clears it
+func (c *Client) SetSeniority(ctx context.Context, req *request.SetSeniorityRequest) (*response.SetSeniorityResponse, error) {
+	var resp response.SetSeniorityResponse
+	if err := c.do(ctx, http.MethodPost, "/users/setSeniority", nil, req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// AddSkillTags calls POST /users/tags/add
+func (c *Client) AddSkillTags(ctx context.Context, req *request.AddSkillTagsRequest) (*response.SkillTagsResponse, error) {
+	var resp response.SkillTagsResponse
+	if err := c.do(ctx, http.MethodPost, "/users/tags/add", nil, req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// RemoveSkillTags calls POST /users/tags/remove
+func (c *Client) RemoveSkillTags(ctx context.Context, req *request.RemoveSkillTagsRequest) (*response.SkillTagsResponse, error) {
+	var resp response.SkillTagsResponse
+	if err := c.do(ctx, http.MethodPost, "/users/tags/remove", nil, req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// SetSkillTags calls POST /users/tags/set; an empty list removes all tags
+func (c *Client) SetSkillTags(ctx context.Context, req *request.SetSkillTagsRequest) (*response.SkillTagsResponse, error) {
+	var resp response.SkillTagsResponse
+	if err := c.do(ctx, http.MethodPost, "/users/tags/set", nil, req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// ListSkillTags calls GET /users/tags/list
+func (c *Client) ListSkillTags(ctx context.Context, userID string) (*response.GetSkillTagsResponse, error) {
+	var resp response.GetSkillTagsResponse
+	query := url.Values{"user_id": {userID}}
+	if err := c.do(ctx, http.MethodGet, "/users/tags/list", query, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// AddAbsence calls POST /users/absences/add
+func (c *Client) AddAbsence(ctx context.Context, req *request.AddAbsenceRequest) (*response.AddAbsenceResponse, error) {
+	var resp response.AddAbsenceResponse
+	if err := c.do(ctx, http.MethodPost, "/users/absences/add", nil, req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// ListAbsences calls GET /users/absences/list
+func (c *Client) ListAbsences(ctx context.Context, userID string) (*response.ListAbsencesResponse, error) {
+	var resp response.ListAbsencesResponse
+	query := url.Values{"user_id": {userID}}
+	if err := c.do(ctx, http.MethodGet, "/users/absences/list", query, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// ImportAbsences calls POST /users/absences/import with the contents of an .ics file
+// Absences imported earlier for the user are replaced
+func (c *Client) ImportAbsences(ctx context.Context, userID string, calendar []byte) (*response.ImportAbsencesResponse, error) {
+	var resp response.ImportAbsencesResponse
+	query := url.Values{"user_id": {userID}}
+	if err := c.send(ctx, http.MethodPost, "/users/absences/import", query, "text/calendar", calendar, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// DeleteAbsence calls POST /users/absences/delete
+func (c *Client) DeleteAbsence(ctx context.Context, req *request.DeleteAbsenceRequest) (*response.DeleteAbsenceResponse, error) {
+	var resp response.DeleteAbsenceResponse
+	if err := c.do(ctx, http.MethodPost, "/users/absences/delete", nil, req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// SetAffinity calls POST /users/affinities/set
+func (c *Client) SetAffinity(ctx context.Context, req *request.SetAffinityRequest) (*response.SetAffinityResponse, error) {
+	var resp response.SetAffinityResponse
+	if err := c.do(ctx, http.MethodPost, "/users/affinities/set", nil, req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// ListAffinities calls GET /users/affinities/list
+func (c *Client) ListAffinities(ctx context.Context, userID string) (*response.ListAffinitiesResponse, error) {
+	var resp response.ListAffinitiesResponse
+	query := url.Values{"user_id": {userID}}
+	if err := c.do(ctx, http.MethodGet, "/users/affinities/list", query, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// DeleteAffinity calls POST /users/affinities/delete
+func (c *Client) DeleteAffinity(ctx context.Context, req *request.DeleteAffinityRequest) (*response.DeleteAffinityResponse, error) {
+	var resp response.DeleteAffinityResponse
+	if err := c.do(ctx, http.MethodPost, "/users/affinities/delete", nil, req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
@@ -0,0 +1,50 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"avito-backend-trainee-assignment-autumn-2025/pkg/dto/request"
+	"avito-backend-trainee-assignment-autumn-2025/pkg/dto/response"
+)
+
+// RegisterWebhook calls POST /webhooks/add
+func (c *Client) RegisterWebhook(ctx context.Context, req *request.RegisterWebhookRequest) (*response.RegisterWebhookResponse, error) {
+	var resp response.RegisterWebhookResponse
+	if err := c.do(ctx, http.MethodPost, "/webhooks/add", nil, req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// ListWebhooks calls GET /webhooks/list
+func (c *Client) ListWebhooks(ctx context.Context) (*response.ListWebhooksResponse, error) {
+	var resp response.ListWebhooksResponse
+	if err := c.do(ctx, http.MethodGet, "/webhooks/list", nil, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// ListWebhookDeliveries calls GET /webhooks/deliveries; an empty status lists all deliveries
+func (c *Client) ListWebhookDeliveries(ctx context.Context, status string) (*response.ListWebhookDeliveriesResponse, error) {
+	var resp response.ListWebhookDeliveriesResponse
+	var query url.Values
+	if status != "" {
+		query = url.Values{"status": {status}}
+	}
+	if err := c.do(ctx, http.MethodGet, "/webhooks/deliveries", query, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// RedeliverWebhook calls POST /webhooks/redeliver
+func (c *Client) RedeliverWebhook(ctx context.Context, req *request.RedeliverWebhookRequest) (*response.RedeliverWebhookResponse, error) {
+	var resp response.RedeliverWebhookResponse
+	if err := c.do(ctx, http.MethodPost, "/webhooks/redeliver", nil, req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
@@ -0,0 +1,58 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/url"
+	"time"
+
+	// Registers the pure-Go "sqlite" database/sql driver
+	_ "modernc.org/sqlite"
+
+	"avito-backend-trainee-assignment-autumn-2025/pkg/logger"
+)
+
+// sqliteBusyTimeout is how long a connection waits for a write lock held by another one
+const sqliteBusyTimeout = 5 * time.Second
+
+// NewSQLiteDB opens (creating if needed) an SQLite database file
+// Foreign keys are enforced, WAL lets readers proceed during writes, and transactions
+// take the write lock up front so concurrent read-then-write transactions wait instead of failing
+func NewSQLiteDB(path string) (*sql.DB, error) {
+	query := url.Values{}
+	query.Add("_pragma", "foreign_keys(1)")
+	query.Add("_pragma", fmt.Sprintf("busy_timeout(%d)", sqliteBusyTimeout.Milliseconds()))
+	query.Add("_pragma", "journal_mode(WAL)")
+	query.Set("_txlock", "immediate")
+	query.Set("_time_format", "sqlite")
+
+	dsn := "file:" + path + "?" + query.Encode()
+
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open sqlite database: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("unable to open sqlite database %s: %w", path, err)
+	}
+
+	logger.Info("Successfully opened SQLite database: %s", path)
+	return db, nil
+}
+
+// CloseSQLite closes the SQLite database handle
+func CloseSQLite(db *sql.DB) {
+	if db != nil {
+		if err := db.Close(); err != nil {
+			logger.Error("Failed to close SQLite database: %v", err)
+			return
+		}
+		logger.Info("SQLite database closed")
+	}
+}
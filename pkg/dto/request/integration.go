@@ -0,0 +1,8 @@
+package request
+
+// LinkAccountRequest for POST /integrations/accounts/link
+type LinkAccountRequest struct {
+	Provider string `json:"provider"`
+	Login    string `json:"login"`
+	UserID   string `json:"user_id"`
+}
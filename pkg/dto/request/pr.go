@@ -5,6 +5,10 @@ type CreatePRRequest struct {
 	PullRequestID   string `json:"pull_request_id"`
 	PullRequestName string `json:"pull_request_name"`
 	AuthorID        string `json:"author_id"`
+	// ChangedFiles are the paths touched by the PR; their code owners are asked to review first
+	ChangedFiles []string `json:"changed_files,omitempty"`
+	// RequiredTags are skills the reviewers should have; candidates with more of them are picked first
+	RequiredTags []string `json:"required_tags,omitempty"`
 }
 
 // MergePRRequest  POST /pullRequest/merge
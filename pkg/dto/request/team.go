@@ -0,0 +1,81 @@
+package request
+
+// TeamMemberRequest ?@54AB02;O5B CG0AB=8:0 :><0=4K 2 70?@>A5
+type TeamMemberRequest struct {
+	UserID   string `json:"user_id"`
+	Username string `json:"username"`
+	IsActive bool   `json:"is_active"`
+	// Slack member ID used to mention the user in chat notifications
+	MentionHandle string `json:"mention_handle,omitempty"`
+	// Address for email notifications
+	Email string `json:"email,omitempty"`
+	// Open reviews the user may have at once; 0 means no limit
+	MaxOpenReviews int `json:"max_open_reviews,omitempty"`
+	// Skills such as go, sql or frontend
+	SkillTags []string `json:"skill_tags,omitempty"`
+	// Reviewer level: junior, middle or senior
+	Seniority string `json:"seniority,omitempty"`
+}
+
+// CreateTeamRequest 4;O POST /team/add
+// 0;840F8O:
+// - Handler: ?@>25@:0 GB> ?>;O =5 ?CABK5, :>@@5:B=K9 JSON
+// - Service: team_name C=8:0;5=, members =5 ?CAB>9 <0AA82
+type CreateTeamRequest struct {
+	TeamName string              `json:"team_name"`
+	Members  []TeamMemberRequest `json:"members"`
+}
+
+// GetTeamRequest 4;O GET /team/get?team_name=...
+// 0;840F8O:
+// - Handler: ?@>25@:0 GB> team_name =5 ?CAB>9
+// - Service: ?@>25@:0 ACI5AB2>20=8O :><0=4K
+type GetTeamRequest struct {
+	TeamName string `json:"team_name"`
+}
+
+// SetChatWebhookRequest POST /team/setChatWebhook
+// An empty webhook_url turns chat notifications off
+type SetChatWebhookRequest struct {
+	TeamName   string `json:"team_name"`
+	WebhookURL string `json:"webhook_url"`
+}
+
+// SetReviewSLARequest POST /team/setReviewSLA
+// review_sla_minutes = 0 falls back to the default SLA
+type SetReviewSLARequest struct {
+	TeamName         string `json:"team_name"`
+	ReviewSLAMinutes int    `json:"review_sla_minutes"`
+	// What happens to an overdue review: remind (default), reassign or escalate
+	SLAAction string `json:"sla_action,omitempty"`
+}
+
+// SetLeadRequest POST /team/setLead
+// An empty lead_id removes the lead
+type SetLeadRequest struct {
+	TeamName string `json:"team_name"`
+	LeadID   string `json:"lead_id"`
+}
+
+// SetCalendarRequest POST /team/setCalendar
+// Timezone defaults to UTC; weekend_days are lowercase English day names
+type SetCalendarRequest struct {
+	TeamName    string   `json:"team_name"`
+	Timezone    string   `json:"timezone,omitempty"`
+	WeekendDays []string `json:"weekend_days"`
+}
+
+// SetWorkingHoursPreferenceRequest POST /team/setWorkingHoursPreference
+// within_hours widens "inside working hours" to a working day starting within that many hours
+type SetWorkingHoursPreferenceRequest struct {
+	TeamName           string `json:"team_name"`
+	PreferWorkingHours bool   `json:"prefer_working_hours"`
+	WithinHours        int    `json:"within_hours,omitempty"`
+}
+
+// SetSeniorityPolicyRequest POST /team/setSeniorityPolicy
+// An empty min_reviewer_seniority removes the policy
+type SetSeniorityPolicyRequest struct {
+	TeamName             string `json:"team_name"`
+	MinReviewerSeniority string `json:"min_reviewer_seniority"`
+}
@@ -0,0 +1,107 @@
+package request
+
+// SetUserActiveRequest 4;O POST /users/setIsActive
+// 0;840F8O:
+// - Handler: ?@>25@:0 GB> ?>;O =5 ?CABK5, is_active MB> bool
+// - Service: ?@>25@:0 GB> ?>;L7>20B5;L ACI5AB2C5B
+type SetUserActiveRequest struct {
+	UserID   string `json:"user_id"`
+	IsActive bool   `json:"is_active"`
+}
+
+// GetUserReviewsRequest 4;O GET /users/getReview?user_id=...
+// 0;840F8O:
+// - Handler: ?@>25@:0 GB> user_id =5 ?CAB>9
+// - Service: ?@>25@:0 GB> ?>;L7>20B5;L ACI5AB2C5B (>?F8>=0;L=>, <>65B 25@=CBL ?CAB>9 <0AA82)
+type GetUserReviewsRequest struct {
+	UserID string `json:"user_id"`
+}
+
+// SetMentionHandleRequest POST /users/setMentionHandle
+// An empty mention_handle turns chat mentions off
+type SetMentionHandleRequest struct {
+	UserID        string `json:"user_id"`
+	MentionHandle string `json:"mention_handle"`
+}
+
+// SetEmailRequest POST /users/setEmail
+// An empty email turns email notifications off
+type SetEmailRequest struct {
+	UserID string `json:"user_id"`
+	Email  string `json:"email"`
+}
+
+// SetWorkingHoursRequest POST /users/setWorkingHours
+// Hours are HH:MM in the user's timezone; both empty clear the working hours, an empty timezone means the team's
+type SetWorkingHoursRequest struct {
+	UserID    string `json:"user_id"`
+	Timezone  string `json:"timezone,omitempty"`
+	WorkStart string `json:"work_start,omitempty"`
+	WorkEnd   string `json:"work_end,omitempty"`
+}
+
+// SetMaxOpenReviewsRequest POST /users/setMaxOpenReviews
+// Zero removes the limit
+type SetMaxOpenReviewsRequest struct {
+	UserID         string `json:"user_id"`
+	MaxOpenReviews int    `json:"max_open_reviews"`
+}
+
+// SetSeniorityRequest POST /users/setSeniority
+// An empty seniority clears the level
+type SetSeniorityRequest struct {
+	UserID    string `json:"user_id"`
+	Seniority string `json:"seniority"`
+}
+
+// AddSkillTagsRequest POST /users/tags/add
+// Tags the user already has are ignored
+type AddSkillTagsRequest struct {
+	UserID string   `json:"user_id"`
+	Tags   []string `json:"tags"`
+}
+
+// RemoveSkillTagsRequest POST /users/tags/remove
+// Tags the user does not have are ignored
+type RemoveSkillTagsRequest struct {
+	UserID string   `json:"user_id"`
+	Tags   []string `json:"tags"`
+}
+
+// SetSkillTagsRequest POST /users/tags/set
+// Replaces all skill tags of the user; an empty list removes them
+type SetSkillTagsRequest struct {
+	UserID string   `json:"user_id"`
+	Tags   []string `json:"tags"`
+}
+
+// AddAbsenceRequest POST /users/absences/add
+// Dates are YYYY-MM-DD and inclusive; a one-day absence has start_date = end_date
+type AddAbsenceRequest struct {
+	UserID    string `json:"user_id"`
+	StartDate string `json:"start_date"`
+	EndDate   string `json:"end_date"`
+	Reason    string `json:"reason,omitempty"`
+}
+
+// DeleteAbsenceRequest POST /users/absences/delete
+type DeleteAbsenceRequest struct {
+	AbsenceID string `json:"absence_id"`
+}
+
+// SetAffinityRequest POST /users/affinities/set
+// A positive weight makes the reviewer likelier and a negative one less likely, zero is neutral
+// Conflict forbids the pair in both directions and requires a zero weight
+type SetAffinityRequest struct {
+	AuthorID   string `json:"author_id"`
+	ReviewerID string `json:"reviewer_id"`
+	Weight     int    `json:"weight"`
+	Conflict   bool   `json:"conflict,omitempty"`
+	Reason     string `json:"reason,omitempty"`
+}
+
+// DeleteAffinityRequest POST /users/affinities/delete
+type DeleteAffinityRequest struct {
+	AuthorID   string `json:"author_id"`
+	ReviewerID string `json:"reviewer_id"`
+}
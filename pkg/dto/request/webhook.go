@@ -0,0 +1,14 @@
+package request
+
+// RegisterWebhookRequest for POST /webhooks/add
+// Secret is optional: a random one is generated when it is empty
+type RegisterWebhookRequest struct {
+	URL    string   `json:"url"`
+	Events []string `json:"events"`
+	Secret string   `json:"secret,omitempty"`
+}
+
+// RedeliverWebhookRequest for POST /webhooks/redeliver
+type RedeliverWebhookRequest struct {
+	DeliveryID string `json:"delivery_id"`
+}
@@ -0,0 +1,38 @@
+package response
+
+type ErrorCode string
+
+const (
+	ErrorCodeTeamExists        ErrorCode = "TEAM_EXISTS"
+	ErrorCodeUserExists        ErrorCode = "USER_ALREADY_EXISTS"
+	ErrorCodePRExists          ErrorCode = "PR_EXISTS"
+	ErrorCodePRMerged          ErrorCode = "PR_MERGED"
+	ErrorCodeNotAssigned       ErrorCode = "NOT_ASSIGNED"
+	ErrorCodeNoCandidate       ErrorCode = "NO_CANDIDATE"
+	ErrorCodeAllAtCapacity     ErrorCode = "ALL_AT_CAPACITY"
+	ErrorCodeSeniorityRequired ErrorCode = "SENIORITY_REQUIRED"
+	ErrorCodeNotFound          ErrorCode = "NOT_FOUND"
+	ErrorCodeValidation        ErrorCode = "VALIDATION_ERROR"
+	ErrorCodeBadRequest        ErrorCode = "BAD_REQUEST"
+	ErrorCodeUnauthorized      ErrorCode = "UNAUTHORIZED"
+	ErrorCodeInternal          ErrorCode = "INTERNAL"
+)
+
+type ErrorDetail struct {
+	Code    ErrorCode `json:"code"`
+	Message string    `json:"message"`
+	Field   string    `json:"field,omitempty"`
+}
+
+type ErrorResponse struct {
+	Error ErrorDetail `json:"error"`
+}
+
+func NewErrorResponse(code ErrorCode, message string) ErrorResponse {
+	return ErrorResponse{
+		Error: ErrorDetail{
+			Code:    code,
+			Message: message,
+		},
+	}
+}
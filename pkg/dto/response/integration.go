@@ -0,0 +1,37 @@
+package response
+
+import "time"
+
+// Results of handling a code host pull request event
+const (
+	EventResultCreated = "created"
+	EventResultMerged  = "merged"
+	EventResultExists  = "exists"
+	EventResultIgnored = "ignored"
+)
+
+// CodeHostAccountResponse describes a code host login linked to a user
+type CodeHostAccountResponse struct {
+	Provider  string    `json:"provider"`
+	Login     string    `json:"login"`
+	UserID    string    `json:"user_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// LinkAccountResponse for POST /integrations/accounts/link
+type LinkAccountResponse struct {
+	Account CodeHostAccountResponse `json:"account"`
+}
+
+// ListAccountsResponse for GET /integrations/accounts/list
+type ListAccountsResponse struct {
+	Accounts []CodeHostAccountResponse `json:"accounts"`
+}
+
+// PullRequestEventResponse is returned to the code host for a webhook delivery
+// PR is set when the event created or merged a pull request
+type PullRequestEventResponse struct {
+	Result        string               `json:"result"`
+	PullRequestID string               `json:"pull_request_id,omitempty"`
+	PR            *PullRequestResponse `json:"pr,omitempty"`
+}
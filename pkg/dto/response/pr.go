@@ -9,6 +9,7 @@ type PullRequestResponse struct {
 	AuthorID          string     `json:"author_id"`
 	Status            string     `json:"status"`
 	AssignedReviewers []string   `json:"assigned_reviewers"`
+	RequiredTags      []string   `json:"required_tags,omitempty"`
 	CreatedAt         *time.Time `json:"createdAt,omitempty"`
 	MergedAt          *time.Time `json:"mergedAt,omitempty"`
 }
@@ -36,3 +37,17 @@ type ReassignReviewerResponse struct {
 	PR         PullRequestResponse `json:"pr"`
 	ReplacedBy string              `json:"replaced_by"`
 }
+
+// ReassignmentResponse a reviewer replacement in the PR history
+type ReassignmentResponse struct {
+	OldReviewerID string    `json:"old_reviewer_id"`
+	NewReviewerID string    `json:"new_reviewer_id"`
+	Reason        string    `json:"reason"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// GetPRHistoryResponse GET /pullRequest/history
+type GetPRHistoryResponse struct {
+	PullRequestID string                 `json:"pull_request_id"`
+	Reassignments []ReassignmentResponse `json:"reassignments"`
+}
@@ -0,0 +1,115 @@
+package response
+
+// TeamMemberResponse ?@54AB02;O5B CG0AB=8:0 :><0=4K 2 >B25B5
+type TeamMemberResponse struct {
+	UserID   string `json:"user_id"`
+	Username string `json:"username"`
+	IsActive bool   `json:"is_active"`
+	// Slack member ID used in chat mentions
+	MentionHandle string `json:"mention_handle,omitempty"`
+	// Address for email notifications
+	Email string `json:"email,omitempty"`
+	// IANA timezone of the working hours; omitted when the team's applies
+	Timezone string `json:"timezone,omitempty"`
+	// Working hours as HH:MM; omitted when not set
+	WorkStart string `json:"work_start,omitempty"`
+	WorkEnd   string `json:"work_end,omitempty"`
+	// Open reviews the user may have at once; omitted when unlimited
+	MaxOpenReviews int `json:"max_open_reviews,omitempty"`
+	// Skills such as go, sql or frontend, sorted; omitted when none
+	SkillTags []string `json:"skill_tags,omitempty"`
+	// Reviewer level; omitted when not set
+	Seniority string `json:"seniority,omitempty"`
+	// Current and upcoming absences (GET /team/get only)
+	Absences []AbsenceResponse `json:"absences,omitempty"`
+}
+
+// TeamResponse 4;O >B25B>2 A :><0=4>9 (GET /team/get, POST /team/add)
+type TeamResponse struct {
+	TeamName string               `json:"team_name"`
+	Members  []TeamMemberResponse `json:"members"`
+	// Minutes before reviewers are reminded; omitted when the default applies
+	ReviewSLAMinutes int `json:"review_sla_minutes,omitempty"`
+	// What happens to an overdue review
+	SLAAction string `json:"sla_action"`
+	LeadID    string `json:"lead_id,omitempty"`
+	// Working calendar the SLA is counted in
+	Calendar CalendarResponse `json:"calendar"`
+	// Whether reviewers inside their working hours are picked first
+	PreferWorkingHours bool `json:"prefer_working_hours"`
+	// Hours ahead a working day may start and still count as inside working hours
+	PreferWithinHours int `json:"prefer_within_hours,omitempty"`
+	// Level at least one reviewer of a PR must have; omitted when there is no policy
+	MinReviewerSeniority string `json:"min_reviewer_seniority,omitempty"`
+}
+
+// CalendarResponse working calendar of a team
+type CalendarResponse struct {
+	Timezone    string   `json:"timezone"`
+	WeekendDays []string `json:"weekend_days"`
+}
+
+// CreateTeamResponse >15@B:0 4;O POST /team/add
+type CreateTeamResponse struct {
+	Team TeamResponse `json:"team"`
+}
+
+// SetChatWebhookResponse POST /team/setChatWebhook
+// The URL itself is a credential and is never returned
+type SetChatWebhookResponse struct {
+	TeamName          string `json:"team_name"`
+	ChatNotifications bool   `json:"chat_notifications"`
+}
+
+// SetReviewSLAResponse POST /team/setReviewSLA
+type SetReviewSLAResponse struct {
+	TeamName         string `json:"team_name"`
+	ReviewSLAMinutes int    `json:"review_sla_minutes"`
+	SLAAction        string `json:"sla_action"`
+}
+
+// SetLeadResponse POST /team/setLead
+type SetLeadResponse struct {
+	TeamName string `json:"team_name"`
+	LeadID   string `json:"lead_id"`
+}
+
+// SetCalendarResponse POST /team/setCalendar
+type SetCalendarResponse struct {
+	TeamName string           `json:"team_name"`
+	Calendar CalendarResponse `json:"calendar"`
+}
+
+// SetWorkingHoursPreferenceResponse POST /team/setWorkingHoursPreference
+type SetWorkingHoursPreferenceResponse struct {
+	TeamName           string `json:"team_name"`
+	PreferWorkingHours bool   `json:"prefer_working_hours"`
+	PreferWithinHours  int    `json:"prefer_within_hours"`
+}
+
+// SetSeniorityPolicyResponse POST /team/setSeniorityPolicy
+type SetSeniorityPolicyResponse struct {
+	TeamName             string `json:"team_name"`
+	MinReviewerSeniority string `json:"min_reviewer_seniority"`
+}
+
+// CodeOwnerRuleResponse is one rule of a team CODEOWNERS file
+type CodeOwnerRuleResponse struct {
+	Line    int      `json:"line"`
+	Pattern string   `json:"pattern"`
+	Owners  []string `json:"owners"`
+}
+
+// UploadCodeOwnersResponse POST /team/codeowners/upload
+type UploadCodeOwnersResponse struct {
+	TeamName string `json:"team_name"`
+	// DryRun is set when the file was only validated and the stored rules were kept
+	DryRun bool                    `json:"dry_run"`
+	Rules  []CodeOwnerRuleResponse `json:"rules"`
+}
+
+// GetCodeOwnersResponse GET /team/codeowners
+type GetCodeOwnersResponse struct {
+	TeamName string                  `json:"team_name"`
+	Rules    []CodeOwnerRuleResponse `json:"rules"`
+}
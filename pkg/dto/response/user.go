@@ -0,0 +1,133 @@
+package response
+
+// UserResponse ?@54AB02;O5B ?>;L7>20B5;O 2 >B25B5
+type UserResponse struct {
+	UserID   string `json:"user_id"`
+	Username string `json:"username"`
+	TeamName string `json:"team_name"`
+	IsActive bool   `json:"is_active"`
+	// Slack member ID used in chat mentions
+	MentionHandle string `json:"mention_handle,omitempty"`
+	// Address for email notifications
+	Email string `json:"email,omitempty"`
+	// IANA timezone of the working hours; omitted when the team's applies
+	Timezone string `json:"timezone,omitempty"`
+	// Working hours as HH:MM; omitted when not set
+	WorkStart string `json:"work_start,omitempty"`
+	WorkEnd   string `json:"work_end,omitempty"`
+	// Open reviews the user may have at once; omitted when unlimited
+	MaxOpenReviews int `json:"max_open_reviews,omitempty"`
+	// Skills such as go, sql or frontend, sorted; omitted when none
+	SkillTags []string `json:"skill_tags,omitempty"`
+	// Reviewer level; omitted when not set
+	Seniority string `json:"seniority,omitempty"`
+}
+
+// SetUserActiveResponse >15@B:0 4;O POST /users/setIsActive
+type SetUserActiveResponse struct {
+	User UserResponse `json:"user"`
+}
+
+// SetMentionHandleResponse POST /users/setMentionHandle
+type SetMentionHandleResponse struct {
+	User UserResponse `json:"user"`
+}
+
+// SetEmailResponse POST /users/setEmail
+type SetEmailResponse struct {
+	User UserResponse `json:"user"`
+}
+
+// SetWorkingHoursResponse POST /users/setWorkingHours
+type SetWorkingHoursResponse struct {
+	User UserResponse `json:"user"`
+}
+
+// SetMaxOpenReviewsResponse POST /users/setMaxOpenReviews
+type SetMaxOpenReviewsResponse struct {
+	User UserResponse `json:"user"`
+}
+
+// SetSeniorityResponse POST /users/setSeniority
+type SetSeniorityResponse struct {
+	User UserResponse `json:"user"`
+}
+
+// SkillTagsResponse POST /users/tags/add, /users/tags/remove and /users/tags/set
+type SkillTagsResponse struct {
+	User UserResponse `json:"user"`
+}
+
+// GetSkillTagsResponse GET /users/tags/list
+type GetSkillTagsResponse struct {
+	UserID    string   `json:"user_id"`
+	SkillTags []string `json:"skill_tags"`
+}
+
+// GetUserReviewsResponse 4;O GET /users/getReview
+type GetUserReviewsResponse struct {
+	UserID       string                     `json:"user_id"`
+	PullRequests []PullRequestShortResponse `json:"pull_requests"`
+}
+
+// AbsenceResponse a period when the user is not assigned to reviews; both dates are inclusive
+type AbsenceResponse struct {
+	AbsenceID string `json:"absence_id"`
+	UserID    string `json:"user_id"`
+	StartDate string `json:"start_date"`
+	EndDate   string `json:"end_date"`
+	Reason    string `json:"reason,omitempty"`
+	// SourceUID is the UID of the calendar event the absence was imported from
+	SourceUID string `json:"source_uid,omitempty"`
+}
+
+// AddAbsenceResponse POST /users/absences/add
+type AddAbsenceResponse struct {
+	Absence AbsenceResponse `json:"absence"`
+}
+
+// ListAbsencesResponse GET /users/absences/list
+type ListAbsencesResponse struct {
+	UserID   string            `json:"user_id"`
+	Absences []AbsenceResponse `json:"absences"`
+}
+
+// ImportAbsencesResponse POST /users/absences/import
+type ImportAbsencesResponse struct {
+	UserID string `json:"user_id"`
+	// Removed is the number of absences from the previous import that were replaced
+	Removed  int               `json:"removed"`
+	Absences []AbsenceResponse `json:"absences"`
+}
+
+// DeleteAbsenceResponse POST /users/absences/delete
+type DeleteAbsenceResponse struct {
+	AbsenceID string `json:"absence_id"`
+}
+
+// AffinityResponse how the author relates to a reviewer
+type AffinityResponse struct {
+	AuthorID   string `json:"author_id"`
+	ReviewerID string `json:"reviewer_id"`
+	Weight     int    `json:"weight"`
+	Conflict   bool   `json:"conflict"`
+	Reason     string `json:"reason,omitempty"`
+}
+
+// SetAffinityResponse POST /users/affinities/set
+type SetAffinityResponse struct {
+	Affinity AffinityResponse `json:"affinity"`
+}
+
+// ListAffinitiesResponse GET /users/affinities/list
+type ListAffinitiesResponse struct {
+	UserID string `json:"user_id"`
+	// Affinities are those where the user is the author or the reviewer
+	Affinities []AffinityResponse `json:"affinities"`
+}
+
+// DeleteAffinityResponse POST /users/affinities/delete
+type DeleteAffinityResponse struct {
+	AuthorID   string `json:"author_id"`
+	ReviewerID string `json:"reviewer_id"`
+}
@@ -0,0 +1,47 @@
+package response
+
+import "time"
+
+// WebhookResponse describes a registered webhook (the secret is never listed)
+type WebhookResponse struct {
+	WebhookID string    `json:"webhook_id"`
+	URL       string    `json:"url"`
+	Events    []string  `json:"events"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// RegisterWebhookResponse for POST /webhooks/add
+// The secret is returned only once, at registration
+type RegisterWebhookResponse struct {
+	Webhook WebhookResponse `json:"webhook"`
+	Secret  string          `json:"secret"`
+}
+
+// ListWebhooksResponse for GET /webhooks/list
+type ListWebhooksResponse struct {
+	Webhooks []WebhookResponse `json:"webhooks"`
+}
+
+// WebhookDeliveryResponse describes a delivery of one event to one webhook
+type WebhookDeliveryResponse struct {
+	DeliveryID    string     `json:"delivery_id"`
+	WebhookID     string     `json:"webhook_id"`
+	EventID       string     `json:"event_id"`
+	EventType     string     `json:"event_type"`
+	Status        string     `json:"status"`
+	Attempts      int        `json:"attempts"`
+	LastError     string     `json:"last_error,omitempty"`
+	NextAttemptAt *time.Time `json:"next_attempt_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	DeliveredAt   *time.Time `json:"delivered_at,omitempty"`
+}
+
+// ListWebhookDeliveriesResponse for GET /webhooks/deliveries
+type ListWebhookDeliveriesResponse struct {
+	Deliveries []WebhookDeliveryResponse `json:"deliveries"`
+}
+
+// RedeliverWebhookResponse for POST /webhooks/redeliver
+type RedeliverWebhookResponse struct {
+	Delivery WebhookDeliveryResponse `json:"delivery"`
+}
@@ -1,8 +1,9 @@
 package errors
 
 import (
-	"avito-backend-trainee-assignment-autumn-2025/internal/dto/response"
+	"avito-backend-trainee-assignment-autumn-2025/pkg/dto/response"
 	"errors"
+	"fmt"
 	"net/http"
 )
 
@@ -15,6 +16,8 @@ var (
 	// User errors
 	ErrUserNotFound      = errors.New("user not found")
 	ErrUserAlreadyExists = errors.New("user already exists")
+	ErrAbsenceNotFound   = errors.New("absence not found")
+	ErrAffinityNotFound  = errors.New("affinity not found")
 
 	// Pull Request errors
 	ErrPRExists   = errors.New("pull request already exists")
@@ -24,11 +27,87 @@ var (
 	// Reviewer errors
 	ErrReviewerNotAssigned = errors.New("reviewer is not assigned to this PR")
 	ErrNoCandidates        = errors.New("no active candidates available for assignment")
+	ErrAllAtCapacity       = errors.New("all candidates have reached their open review limit")
+	ErrSeniorityRequired   = errors.New("no candidate meets the team's reviewer seniority policy")
+
+	// Webhook errors
+	ErrWebhookNotFound  = errors.New("webhook not found")
+	ErrDeliveryNotFound = errors.New("webhook delivery not found")
+
+	// Integration errors
+	ErrAccountNotLinked     = errors.New("code host account is not linked to a user")
+	ErrIntegrationDisabled  = errors.New("integration is not configured")
+	ErrInvalidSignature     = errors.New("invalid webhook signature")
+	ErrReviewerSyncNotFound = errors.New("reviewer sync not found")
+
+	// Outbox errors (internal, never caused by a client request)
+	ErrOutboxMessageNotFound = errors.New("outbox message not found")
 )
 
+// internalErrorMessage is returned to clients instead of the text of unexpected errors
+const internalErrorMessage = "internal server error"
+
+// ValidationError reports a request field that failed validation
+type ValidationError struct {
+	Field   string
+	Message string
+}
+
+// NewValidationError creates a validation error for the given field
+func NewValidationError(field, message string) *ValidationError {
+	return &ValidationError{
+		Field:   field,
+		Message: message,
+	}
+}
+
+// NewRequiredFieldError creates a validation error for a missing required field
+func NewRequiredFieldError(field string) *ValidationError {
+	return NewValidationError(field, "is required")
+}
+
+// Error implements the error interface
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("%s %s", e.Field, e.Message)
+}
+
+// BadRequestError reports a request that could not be parsed at all (e.g. malformed JSON)
+type BadRequestError struct {
+	Message string
+	Err     error
+}
+
+// NewBadRequestError creates a bad request error wrapping the underlying cause
+func NewBadRequestError(message string, err error) *BadRequestError {
+	return &BadRequestError{
+		Message: message,
+		Err:     err,
+	}
+}
+
+// Error implements the error interface
+func (e *BadRequestError) Error() string {
+	if e.Err == nil {
+		return e.Message
+	}
+	return fmt.Sprintf("%s: %v", e.Message, e.Err)
+}
+
+// Unwrap returns the underlying cause
+func (e *BadRequestError) Unwrap() error {
+	return e.Err
+}
+
 // MapErrorToHTTPStatus maps domain errors to HTTP status codes
 func MapErrorToHTTPStatus(err error) int {
+	var validationErr *ValidationError
+	var badRequestErr *BadRequestError
+
 	switch {
+	case errors.As(err, &validationErr),
+		errors.As(err, &badRequestErr):
+		return http.StatusBadRequest
+
 	case errors.Is(err, ErrTeamExists):
 		return http.StatusBadRequest
 
@@ -36,14 +115,25 @@ func MapErrorToHTTPStatus(err error) int {
 		errors.Is(err, ErrPRExists),
 		errors.Is(err, ErrPRMerged),
 		errors.Is(err, ErrReviewerNotAssigned),
-		errors.Is(err, ErrNoCandidates):
+		errors.Is(err, ErrNoCandidates),
+		errors.Is(err, ErrAllAtCapacity),
+		errors.Is(err, ErrSeniorityRequired):
 		return http.StatusConflict
 
 	case errors.Is(err, ErrTeamNotFound),
 		errors.Is(err, ErrUserNotFound),
-		errors.Is(err, ErrPRNotFound):
+		errors.Is(err, ErrPRNotFound),
+		errors.Is(err, ErrAbsenceNotFound),
+		errors.Is(err, ErrAffinityNotFound),
+		errors.Is(err, ErrWebhookNotFound),
+		errors.Is(err, ErrDeliveryNotFound),
+		errors.Is(err, ErrAccountNotLinked),
+		errors.Is(err, ErrIntegrationDisabled):
 		return http.StatusNotFound
 
+	case errors.Is(err, ErrInvalidSignature):
+		return http.StatusUnauthorized
+
 	default:
 		return http.StatusInternalServerError
 	}
@@ -51,9 +141,18 @@ func MapErrorToHTTPStatus(err error) int {
 
 // MapErrorToErrorCode maps domain errors to API error codes
 func MapErrorToErrorCode(err error) response.ErrorCode {
+	var validationErr *ValidationError
+	var badRequestErr *BadRequestError
+
 	switch {
+	case errors.As(err, &validationErr):
+		return response.ErrorCodeValidation
+	case errors.As(err, &badRequestErr):
+		return response.ErrorCodeBadRequest
 	case errors.Is(err, ErrTeamExists):
 		return response.ErrorCodeTeamExists
+	case errors.Is(err, ErrUserAlreadyExists):
+		return response.ErrorCodeUserExists
 	case errors.Is(err, ErrPRExists):
 		return response.ErrorCodePRExists
 	case errors.Is(err, ErrPRMerged):
@@ -62,11 +161,41 @@ func MapErrorToErrorCode(err error) response.ErrorCode {
 		return response.ErrorCodeNotAssigned
 	case errors.Is(err, ErrNoCandidates):
 		return response.ErrorCodeNoCandidate
+	case errors.Is(err, ErrAllAtCapacity):
+		return response.ErrorCodeAllAtCapacity
+	case errors.Is(err, ErrSeniorityRequired):
+		return response.ErrorCodeSeniorityRequired
 	case errors.Is(err, ErrTeamNotFound),
 		errors.Is(err, ErrUserNotFound),
-		errors.Is(err, ErrPRNotFound):
+		errors.Is(err, ErrPRNotFound),
+		errors.Is(err, ErrAbsenceNotFound),
+		errors.Is(err, ErrAffinityNotFound),
+		errors.Is(err, ErrWebhookNotFound),
+		errors.Is(err, ErrDeliveryNotFound),
+		errors.Is(err, ErrAccountNotLinked),
+		errors.Is(err, ErrIntegrationDisabled):
 		return response.ErrorCodeNotFound
+	case errors.Is(err, ErrInvalidSignature):
+		return response.ErrorCodeUnauthorized
 	default:
-		return response.ErrorCodeNotFound
+		return response.ErrorCodeInternal
+	}
+}
+
+// MapErrorToResponse builds the API error body for the given error
+// Messages of internal errors are hidden from clients
+func MapErrorToResponse(err error) response.ErrorResponse {
+	code := MapErrorToErrorCode(err)
+	if code == response.ErrorCodeInternal {
+		return response.NewErrorResponse(code, internalErrorMessage)
 	}
+
+	errorResponse := response.NewErrorResponse(code, err.Error())
+
+	var validationErr *ValidationError
+	if errors.As(err, &validationErr) {
+		errorResponse.Error.Field = validationErr.Field
+	}
+
+	return errorResponse
 }
@@ -0,0 +1,113 @@
+package errors
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"avito-backend-trainee-assignment-autumn-2025/pkg/dto/response"
+)
+
+func TestMapError(t *testing.T) {
+	tests := []struct {
+		name            string
+		err             error
+		expectedStatus  int
+		expectedCode    response.ErrorCode
+		expectedMessage string
+		expectedField   string
+	}{
+		{
+			name:            "Validation error names the field",
+			err:             NewRequiredFieldError("pull_request_id"),
+			expectedStatus:  http.StatusBadRequest,
+			expectedCode:    response.ErrorCodeValidation,
+			expectedMessage: "pull_request_id is required",
+			expectedField:   "pull_request_id",
+		},
+		{
+			name:            "Wrapped validation error keeps the field",
+			err:             fmt.Errorf("failed to create team: %w", NewRequiredFieldError("members[0].user_id")),
+			expectedStatus:  http.StatusBadRequest,
+			expectedCode:    response.ErrorCodeValidation,
+			expectedMessage: "failed to create team: members[0].user_id is required",
+			expectedField:   "members[0].user_id",
+		},
+		{
+			name:            "Malformed body",
+			err:             NewBadRequestError("invalid request body", json.Unmarshal([]byte("{"), &struct{}{})),
+			expectedStatus:  http.StatusBadRequest,
+			expectedCode:    response.ErrorCodeBadRequest,
+			expectedMessage: "invalid request body: unexpected end of JSON input",
+		},
+		{
+			name:            "Domain error",
+			err:             fmt.Errorf("failed to get PR: %w", ErrPRNotFound),
+			expectedStatus:  http.StatusNotFound,
+			expectedCode:    response.ErrorCodeNotFound,
+			expectedMessage: "failed to get PR: pull request not found",
+		},
+		{
+			name:            "Webhook delivery not found",
+			err:             ErrDeliveryNotFound,
+			expectedStatus:  http.StatusNotFound,
+			expectedCode:    response.ErrorCodeNotFound,
+			expectedMessage: "webhook delivery not found",
+		},
+		{
+			name:            "User already exists",
+			err:             ErrUserAlreadyExists,
+			expectedStatus:  http.StatusConflict,
+			expectedCode:    response.ErrorCodeUserExists,
+			expectedMessage: "user already exists",
+		},
+		{
+			name:            "Everyone at capacity differs from no candidates",
+			err:             fmt.Errorf("failed to reassign: %w", ErrAllAtCapacity),
+			expectedStatus:  http.StatusConflict,
+			expectedCode:    response.ErrorCodeAllAtCapacity,
+			expectedMessage: "failed to reassign: all candidates have reached their open review limit",
+		},
+		{
+			name:            "Seniority policy that cannot be kept",
+			err:             ErrSeniorityRequired,
+			expectedStatus:  http.StatusConflict,
+			expectedCode:    response.ErrorCodeSeniorityRequired,
+			expectedMessage: "no candidate meets the team's reviewer seniority policy",
+		},
+		{
+			name:            "Invalid webhook signature",
+			err:             fmt.Errorf("github webhook: %w", ErrInvalidSignature),
+			expectedStatus:  http.StatusUnauthorized,
+			expectedCode:    response.ErrorCodeUnauthorized,
+			expectedMessage: "github webhook: invalid webhook signature",
+		},
+		{
+			name:            "Unknown error is internal and hidden",
+			err:             fmt.Errorf("failed to begin transaction: connection refused"),
+			expectedStatus:  http.StatusInternalServerError,
+			expectedCode:    response.ErrorCodeInternal,
+			expectedMessage: internalErrorMessage,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if status := MapErrorToHTTPStatus(tt.err); status != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, status)
+			}
+
+			resp := MapErrorToResponse(tt.err)
+			if resp.Error.Code != tt.expectedCode {
+				t.Errorf("Expected code %s, got %s", tt.expectedCode, resp.Error.Code)
+			}
+			if resp.Error.Message != tt.expectedMessage {
+				t.Errorf("Expected message %q, got %q", tt.expectedMessage, resp.Error.Message)
+			}
+			if resp.Error.Field != tt.expectedField {
+				t.Errorf("Expected field %q, got %q", tt.expectedField, resp.Error.Field)
+			}
+		})
+	}
+}
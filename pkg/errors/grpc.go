@@ -0,0 +1,64 @@
+package errors
+
+import (
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"avito-backend-trainee-assignment-autumn-2025/pkg/dto/response"
+)
+
+// errorDomain identifies the service in gRPC ErrorInfo details
+const errorDomain = "pr-reviewer-service"
+
+// MapErrorCodeToGRPCCode maps API error codes to gRPC status codes
+func MapErrorCodeToGRPCCode(code response.ErrorCode) codes.Code {
+	switch code {
+	case response.ErrorCodeValidation,
+		response.ErrorCodeBadRequest:
+		return codes.InvalidArgument
+
+	case response.ErrorCodeTeamExists,
+		response.ErrorCodeUserExists,
+		response.ErrorCodePRExists:
+		return codes.AlreadyExists
+
+	case response.ErrorCodePRMerged,
+		response.ErrorCodeNotAssigned,
+		response.ErrorCodeNoCandidate,
+		response.ErrorCodeAllAtCapacity,
+		response.ErrorCodeSeniorityRequired:
+		return codes.FailedPrecondition
+
+	case response.ErrorCodeNotFound:
+		return codes.NotFound
+
+	case response.ErrorCodeUnauthorized:
+		return codes.Unauthenticated
+
+	default:
+		return codes.Internal
+	}
+}
+
+// MapErrorToGRPCStatus converts an error into a gRPC status
+// The API error code (and the failing field, if any) is attached as ErrorInfo details,
+// messages of internal errors are hidden the same way as in HTTP responses
+func MapErrorToGRPCStatus(err error) *status.Status {
+	errorResponse := MapErrorToResponse(err)
+	st := status.New(MapErrorCodeToGRPCCode(errorResponse.Error.Code), errorResponse.Error.Message)
+
+	info := &errdetails.ErrorInfo{
+		Reason: string(errorResponse.Error.Code),
+		Domain: errorDomain,
+	}
+	if errorResponse.Error.Field != "" {
+		info.Metadata = map[string]string{"field": errorResponse.Error.Field}
+	}
+
+	withDetails, detailsErr := st.WithDetails(info)
+	if detailsErr != nil {
+		return st
+	}
+	return withDetails
+}
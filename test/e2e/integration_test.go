@@ -0,0 +1,290 @@
+package e2e
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"avito-backend-trainee-assignment-autumn-2025/internal/integration/github"
+	"avito-backend-trainee-assignment-autumn-2025/internal/integration/gitlab"
+	"avito-backend-trainee-assignment-autumn-2025/pkg/dto/request"
+	"avito-backend-trainee-assignment-autumn-2025/pkg/dto/response"
+)
+
+// Recorded code host deliveries
+const (
+	githubFixtures = "../../internal/integration/github/testdata"
+	gitlabFixtures = "../../internal/integration/gitlab/testdata"
+)
+
+// loadDelivery reads a recorded delivery and lets mutate move it to another repository and author,
+// so repeated runs don't collide on the PR id
+func loadDelivery(t *testing.T, path string, mutate func(payload map[string]any)) []byte {
+	t.Helper()
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read fixture: %v", err)
+	}
+
+	var payload map[string]any
+	if err := json.Unmarshal(raw, &payload); err != nil {
+		t.Fatalf("Failed to decode fixture: %v", err)
+	}
+	mutate(payload)
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		t.Fatalf("Failed to encode fixture: %v", err)
+	}
+	return body
+}
+
+// githubDelivery loads a recorded GitHub delivery for the given repository and author
+func githubDelivery(t *testing.T, fixture, repository, author string) []byte {
+	t.Helper()
+	return loadDelivery(t, filepath.Join(githubFixtures, fixture), func(payload map[string]any) {
+		payload["repository"].(map[string]any)["full_name"] = repository
+		if pr, ok := payload["pull_request"].(map[string]any); ok {
+			pr["user"].(map[string]any)["login"] = author
+		}
+	})
+}
+
+// gitlabDelivery loads a recorded GitLab delivery for the given project and triggering user
+func gitlabDelivery(t *testing.T, fixture, project, username string) []byte {
+	t.Helper()
+	return loadDelivery(t, filepath.Join(gitlabFixtures, fixture), func(payload map[string]any) {
+		payload["project"].(map[string]any)["path_with_namespace"] = project
+		if user, ok := payload["user"].(map[string]any); ok {
+			user["username"] = username
+		}
+	})
+}
+
+// postDelivery sends a delivery to a code host webhook with the given headers
+func postDelivery(t *testing.T, path string, body []byte, headers map[string]string) (int, []byte) {
+	t.Helper()
+
+	req, err := http.NewRequestWithContext(testContext(t), http.MethodPost, baseURL+path, bytes.NewReader(body))
+	if err != nil {
+		t.Fatalf("Failed to create request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var buf bytes.Buffer
+	if _, err := buf.ReadFrom(resp.Body); err != nil {
+		t.Fatalf("Failed to read response: %v", err)
+	}
+	return resp.StatusCode, buf.Bytes()
+}
+
+// postGitHubDelivery sends a delivery to the GitHub webhook the way GitHub does
+func postGitHubDelivery(t *testing.T, event string, body []byte, signature string) (int, []byte) {
+	t.Helper()
+	return postDelivery(t, "/integrations/github/webhook", body, map[string]string{
+		github.HeaderEvent:     event,
+		github.HeaderDelivery:  fmt.Sprintf("delivery-%d", time.Now().UnixNano()),
+		github.HeaderSignature: signature,
+	})
+}
+
+// postGitLabDelivery sends a delivery to the GitLab webhook the way GitLab does
+func postGitLabDelivery(t *testing.T, event string, body []byte, token string) (int, []byte) {
+	t.Helper()
+	return postDelivery(t, "/integrations/gitlab/webhook", body, map[string]string{
+		gitlab.HeaderEvent:     event,
+		gitlab.HeaderEventUUID: fmt.Sprintf("event-%d", time.Now().UnixNano()),
+		gitlab.HeaderToken:     token,
+	})
+}
+
+// TestGitHubWebhook tests POST /integrations/github/webhook with recorded deliveries
+func TestGitHubWebhook(t *testing.T) {
+	secret := os.Getenv("GITHUB_WEBHOOK_SECRET")
+	if secret == "" {
+		t.Skip("GITHUB_WEBHOOK_SECRET is not set")
+	}
+
+	suffix := time.Now().UnixNano()
+	teamName := fmt.Sprintf("github-team-%d", suffix)
+	authorID := fmt.Sprintf("github-author-%d", suffix)
+	login := fmt.Sprintf("Octo-Dev-%d", suffix)
+	repository := fmt.Sprintf("octo-org/e2e-%d", suffix)
+	mustCreateTeam(t, teamName,
+		member(authorID, "Author", true),
+		member(fmt.Sprintf("github-reviewer1-%d", suffix), "Reviewer 1", true),
+		member(fmt.Sprintf("github-reviewer2-%d", suffix), "Reviewer 2", true),
+	)
+
+	send := func(t *testing.T, event, fixture string) (int, response.PullRequestEventResponse) {
+		t.Helper()
+		body := githubDelivery(t, fixture, repository, login)
+		status, raw := postGitHubDelivery(t, event, body, github.Sign(secret, body))
+		var resp response.PullRequestEventResponse
+		_ = json.Unmarshal(raw, &resp)
+		return status, resp
+	}
+
+	t.Run("Error - Author not linked", func(t *testing.T) {
+		status, _ := send(t, "pull_request", "pull_request_opened.json")
+		if status != http.StatusNotFound {
+			t.Errorf("Expected status 404, got %d", status)
+		}
+	})
+
+	t.Run("Success - Link account", func(t *testing.T) {
+		resp, err := apiClient.LinkAccount(testContext(t), &request.LinkAccountRequest{
+			Provider: "github",
+			Login:    login,
+			UserID:   authorID,
+		})
+		if err != nil {
+			t.Fatalf("Failed to link account: %v", err)
+		}
+		if resp.Account.UserID != authorID {
+			t.Errorf("Expected account linked to %s, got %s", authorID, resp.Account.UserID)
+		}
+	})
+
+	prID := fmt.Sprintf("github:%s#42", repository)
+
+	t.Run("Success - Opened creates PR", func(t *testing.T) {
+		status, resp := send(t, "pull_request", "pull_request_opened.json")
+		if status != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d", status)
+		}
+		if resp.Result != response.EventResultCreated || resp.PR == nil {
+			t.Fatalf("Expected created PR, got %+v", resp)
+		}
+		if resp.PR.PullRequestID != prID || resp.PR.AuthorID != authorID {
+			t.Errorf("Unexpected PR %+v", resp.PR)
+		}
+		if len(resp.PR.AssignedReviewers) != 2 {
+			t.Errorf("Expected 2 reviewers, got %v", resp.PR.AssignedReviewers)
+		}
+	})
+
+	t.Run("Success - Redelivery is idempotent", func(t *testing.T) {
+		_, resp := send(t, "pull_request", "pull_request_opened.json")
+		if resp.Result != response.EventResultExists {
+			t.Errorf("Expected result exists, got %+v", resp)
+		}
+	})
+
+	t.Run("Success - Ping is acknowledged", func(t *testing.T) {
+		status, resp := send(t, "ping", "ping.json")
+		if status != http.StatusOK || resp.Result != response.EventResultIgnored {
+			t.Errorf("Expected ignored ping, got %d %+v", status, resp)
+		}
+	})
+
+	t.Run("Error - Invalid signature", func(t *testing.T) {
+		body := githubDelivery(t, "pull_request_closed_merged.json", repository, login)
+		status, raw := postGitHubDelivery(t, "pull_request", body, github.Sign("wrong-secret", body))
+		var errResp response.ErrorResponse
+		_ = json.Unmarshal(raw, &errResp)
+		if status != http.StatusUnauthorized || errResp.Error.Code != response.ErrorCodeUnauthorized {
+			t.Errorf("Expected 401 UNAUTHORIZED, got %d %s", status, errResp.Error.Code)
+		}
+	})
+
+	t.Run("Success - Merged merges PR", func(t *testing.T) {
+		_, resp := send(t, "pull_request", "pull_request_closed_merged.json")
+		if resp.Result != response.EventResultMerged || resp.PR == nil || resp.PR.Status != "MERGED" {
+			t.Errorf("Expected merged PR, got %+v", resp)
+		}
+	})
+}
+
+// TestGitLabWebhook tests POST /integrations/gitlab/webhook with stored deliveries
+func TestGitLabWebhook(t *testing.T) {
+	token := os.Getenv("GITLAB_WEBHOOK_TOKEN")
+	if token == "" {
+		t.Skip("GITLAB_WEBHOOK_TOKEN is not set")
+	}
+
+	suffix := time.Now().UnixNano()
+	teamName := fmt.Sprintf("gitlab-team-%d", suffix)
+	authorID := fmt.Sprintf("gitlab-author-%d", suffix)
+	username := fmt.Sprintf("dana.petrova.%d", suffix)
+	project := fmt.Sprintf("platform/e2e-%d", suffix)
+	mustCreateTeam(t, teamName,
+		member(authorID, "Author", true),
+		member(fmt.Sprintf("gitlab-reviewer1-%d", suffix), "Reviewer 1", true),
+		member(fmt.Sprintf("gitlab-reviewer2-%d", suffix), "Reviewer 2", true),
+	)
+	if _, err := apiClient.LinkAccount(testContext(t), &request.LinkAccountRequest{
+		Provider: "gitlab",
+		Login:    username,
+		UserID:   authorID,
+	}); err != nil {
+		t.Fatalf("Failed to link account: %v", err)
+	}
+
+	send := func(t *testing.T, event, fixture string) (int, response.PullRequestEventResponse) {
+		t.Helper()
+		status, raw := postGitLabDelivery(t, event, gitlabDelivery(t, fixture, project, username), token)
+		var resp response.PullRequestEventResponse
+		_ = json.Unmarshal(raw, &resp)
+		return status, resp
+	}
+
+	t.Run("Error - Invalid token", func(t *testing.T) {
+		body := gitlabDelivery(t, "merge_request_open.json", project, username)
+		status, raw := postGitLabDelivery(t, "Merge Request Hook", body, "wrong-token")
+		var errResp response.ErrorResponse
+		_ = json.Unmarshal(raw, &errResp)
+		if status != http.StatusUnauthorized || errResp.Error.Code != response.ErrorCodeUnauthorized {
+			t.Errorf("Expected 401 UNAUTHORIZED, got %d %s", status, errResp.Error.Code)
+		}
+	})
+
+	t.Run("Success - Open creates PR", func(t *testing.T) {
+		status, resp := send(t, "Merge Request Hook", "merge_request_open.json")
+		if status != http.StatusOK || resp.Result != response.EventResultCreated || resp.PR == nil {
+			t.Fatalf("Expected created PR, got %d %+v", status, resp)
+		}
+		if resp.PR.PullRequestID != fmt.Sprintf("gitlab:%s!7", project) || resp.PR.AuthorID != authorID {
+			t.Errorf("Unexpected PR %+v", resp.PR)
+		}
+	})
+
+	t.Run("Success - Update and push are ignored", func(t *testing.T) {
+		for event, fixture := range map[string]string{"Merge Request Hook": "merge_request_update.json", "Push Hook": "push.json"} {
+			if _, resp := send(t, event, fixture); resp.Result != response.EventResultIgnored {
+				t.Errorf("%s: expected ignored, got %+v", fixture, resp)
+			}
+		}
+	})
+
+	t.Run("Success - Close and reopen keep PR", func(t *testing.T) {
+		if _, resp := send(t, "Merge Request Hook", "merge_request_close.json"); resp.Result != response.EventResultIgnored {
+			t.Errorf("close: expected ignored, got %+v", resp)
+		}
+		if _, resp := send(t, "Merge Request Hook", "merge_request_reopen.json"); resp.Result != response.EventResultExists {
+			t.Errorf("reopen: expected exists, got %+v", resp)
+		}
+	})
+
+	t.Run("Success - Merge merges PR", func(t *testing.T) {
+		_, resp := send(t, "Merge Request Hook", "merge_request_merge.json")
+		if resp.Result != response.EventResultMerged || resp.PR == nil || resp.PR.Status != "MERGED" {
+			t.Errorf("Expected merged PR, got %+v", resp)
+		}
+	})
+}
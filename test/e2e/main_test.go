@@ -2,16 +2,20 @@ package e2e
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
 	"net/http"
-	"net/url"
 	"os"
 	"testing"
 	"time"
 
 	"github.com/joho/godotenv"
+
+	"avito-backend-trainee-assignment-autumn-2025/pkg/client"
+	"avito-backend-trainee-assignment-autumn-2025/pkg/dto/request"
+	"avito-backend-trainee-assignment-autumn-2025/pkg/dto/response"
 )
 
 const (
@@ -21,8 +25,8 @@ const (
 )
 
 var (
-	baseURL    string
-	httpClient *http.Client
+	baseURL   string
+	apiClient *client.Client
 )
 
 func TestMain(m *testing.M) {
@@ -49,9 +53,12 @@ func TestMain(m *testing.M) {
 
 	fmt.Printf("Using base URL: %s\n", baseURL)
 
-	// Create HTTP client with timeout
-	httpClient = &http.Client{
-		Timeout: requestTimeout,
+	// Create API client with timeout
+	var err error
+	apiClient, err = client.New(baseURL, client.WithTimeout(requestTimeout))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to create API client: %v\n", err)
+		os.Exit(1)
 	}
 
 	// Wait for API to be ready
@@ -70,17 +77,10 @@ func TestMain(m *testing.M) {
 
 // waitForAPI waits for the API server to become healthy
 func waitForAPI() error {
-	healthURL := fmt.Sprintf("%s/health", baseURL)
-
 	for i := 0; i < maxRetries; i++ {
-		resp, err := httpClient.Get(healthURL)
-		if err == nil && resp.StatusCode == http.StatusOK {
-			resp.Body.Close()
+		if err := apiClient.Health(context.Background()); err == nil {
 			return nil
 		}
-		if resp != nil {
-			resp.Body.Close()
-		}
 
 		time.Sleep(retryDelay)
 	}
@@ -88,97 +88,90 @@ func waitForAPI() error {
 	return fmt.Errorf("API did not become healthy after %d attempts", maxRetries)
 }
 
-// Helper functions for making HTTP requests
-
-// doRequest performs an HTTP request and returns the response
-func doRequest(method, path string, body interface{}) (*http.Response, error) {
-	var reqBody io.Reader
-	if body != nil {
-		jsonData, err := json.Marshal(body)
-		if err != nil {
-			return nil, fmt.Errorf("failed to marshal request body: %w", err)
-		}
-		reqBody = bytes.NewBuffer(jsonData)
-	}
-
-	req, err := http.NewRequest(method, baseURL+path, reqBody)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
+// Helper functions
 
-	if body != nil {
-		req.Header.Set("Content-Type", "application/json")
-	}
+// testContext returns a context bounded by the request timeout
+func testContext(t *testing.T) context.Context {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	t.Cleanup(cancel)
+	return ctx
+}
 
-	return httpClient.Do(req)
+// member builds a team member for CreateTeamRequest
+func member(userID, username string, isActive bool) request.TeamMemberRequest {
+	return request.TeamMemberRequest{UserID: userID, Username: username, IsActive: isActive}
 }
 
-// doGet performs a GET request with query parameters
-func doGet(path string, queryParams map[string]string) (*http.Response, error) {
-	u, err := url.Parse(baseURL + path)
+// mustCreateTeam creates a team and fails the test on error
+func mustCreateTeam(t *testing.T, teamName string, members ...request.TeamMemberRequest) *response.CreateTeamResponse {
+	t.Helper()
+	resp, err := apiClient.CreateTeam(testContext(t), &request.CreateTeamRequest{
+		TeamName: teamName,
+		Members:  members,
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse URL: %w", err)
-	}
-
-	if len(queryParams) > 0 {
-		q := u.Query()
-		for key, value := range queryParams {
-			q.Set(key, value)
-		}
-		u.RawQuery = q.Encode()
+		t.Fatalf("Failed to create team: %v", err)
 	}
+	return resp
+}
 
-	req, err := http.NewRequest(http.MethodGet, u.String(), nil)
+// mustCreatePR creates a pull request and fails the test on error
+func mustCreatePR(t *testing.T, prID, prName, authorID string) *response.CreatePRResponse {
+	t.Helper()
+	resp, err := apiClient.CreatePR(testContext(t), &request.CreatePRRequest{
+		PullRequestID:   prID,
+		PullRequestName: prName,
+		AuthorID:        authorID,
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		t.Fatalf("Failed to create PR: %v", err)
 	}
-
-	return httpClient.Do(req)
+	return resp
 }
 
-// parseResponse parses JSON response into the given structure
-func parseResponse(resp *http.Response, v interface{}) error {
-	defer resp.Body.Close()
-	bodyBytes, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return fmt.Errorf("failed to read response body: %w", err)
-	}
+// assertAPIError checks that err is an API error with the expected status and error code
+func assertAPIError(t *testing.T, err error, expectedStatus int, expectedCode response.ErrorCode) {
+	t.Helper()
 
-	if err := json.Unmarshal(bodyBytes, v); err != nil {
-		return fmt.Errorf("failed to unmarshal response (body: %s): %w", string(bodyBytes), err)
+	var apiErr *client.APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("Expected API error %s, got %v", expectedCode, err)
 	}
 
-	return nil
-}
+	if apiErr.StatusCode != expectedStatus {
+		t.Errorf("Expected status code %d, got %d (%v)", expectedStatus, apiErr.StatusCode, apiErr)
+	}
 
-// assertStatusCode checks if the response has the expected status code
-func assertStatusCode(t *testing.T, resp *http.Response, expected int) {
-	t.Helper()
-	if resp.StatusCode != expected {
-		bodyBytes, _ := io.ReadAll(resp.Body)
-		t.Errorf("Expected status code %d, got %d. Response body: %s",
-			expected, resp.StatusCode, string(bodyBytes))
+	if apiErr.Code != expectedCode {
+		t.Errorf("Expected error code %s, got %s (message: %s)", expectedCode, apiErr.Code, apiErr.Message)
 	}
 }
 
-// assertErrorCode checks if the error response has the expected error code
-func assertErrorCode(t *testing.T, resp *http.Response, expectedCode string) {
+// doRawRequest sends an arbitrary JSON body, bypassing the typed client
+// Used to check how the API handles requests the client cannot produce
+func doRawRequest(t *testing.T, method, path string, body interface{}) (int, response.ErrorResponse) {
 	t.Helper()
-	defer resp.Body.Close()
 
-	var errorResp struct {
-		Error struct {
-			Code    string `json:"code"`
-			Message string `json:"message"`
-		} `json:"error"`
+	jsonData, err := json.Marshal(body)
+	if err != nil {
+		t.Fatalf("Failed to marshal request body: %v", err)
 	}
 
-	if err := parseResponse(resp, &errorResp); err != nil {
-		t.Fatalf("Failed to parse error response: %v", err)
+	req, err := http.NewRequestWithContext(testContext(t), method, baseURL+path, bytes.NewReader(jsonData))
+	if err != nil {
+		t.Fatalf("Failed to create request: %v", err)
 	}
+	req.Header.Set("Content-Type", "application/json")
 
-	if errorResp.Error.Code != expectedCode {
-		t.Errorf("Expected error code %s, got %s (message: %s)",
-			expectedCode, errorResp.Error.Code, errorResp.Error.Message)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
 	}
+	defer resp.Body.Close()
+
+	var errorResp response.ErrorResponse
+	_ = json.NewDecoder(resp.Body).Decode(&errorResp)
+
+	return resp.StatusCode, errorResp
 }
@@ -0,0 +1,60 @@
+package e2e
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"avito-backend-trainee-assignment-autumn-2025/pkg/dto/request"
+	"avito-backend-trainee-assignment-autumn-2025/pkg/dto/response"
+)
+
+// TestOpenAPI tests GET /openapi.json and request validation against the spec
+func TestOpenAPI(t *testing.T) {
+	t.Run("Success - Get OpenAPI spec", func(t *testing.T) {
+		raw, err := apiClient.OpenAPISpec(testContext(t))
+		if err != nil {
+			t.Fatalf("Failed to get spec: %v", err)
+		}
+
+		var spec struct {
+			OpenAPI string                 `json:"openapi"`
+			Paths   map[string]interface{} `json:"paths"`
+		}
+		if err := json.Unmarshal(raw, &spec); err != nil {
+			t.Fatalf("Failed to parse spec: %v", err)
+		}
+
+		if spec.OpenAPI == "" {
+			t.Error("Expected openapi version to be set")
+		}
+		for _, path := range []string{"/team/add", "/users/setIsActive", "/pullRequest/create"} {
+			if _, ok := spec.Paths[path]; !ok {
+				t.Errorf("Expected path %s in spec", path)
+			}
+		}
+	})
+
+	t.Run("Error - Missing required field", func(t *testing.T) {
+		_, err := apiClient.CreatePR(testContext(t), &request.CreatePRRequest{
+			PullRequestName: "No ID",
+			AuthorID:        "someone",
+		})
+
+		assertAPIError(t, err, http.StatusBadRequest, response.ErrorCodeValidation)
+	})
+
+	t.Run("Error - Unknown field", func(t *testing.T) {
+		status, errorResp := doRawRequest(t, http.MethodPost, "/pullRequest/merge", map[string]interface{}{
+			"pull_request_id": "pr-1",
+			"force":           true,
+		})
+
+		if status != http.StatusBadRequest {
+			t.Errorf("Expected status code %d, got %d", http.StatusBadRequest, status)
+		}
+		if errorResp.Error.Code != response.ErrorCodeValidation {
+			t.Errorf("Expected error code %s, got %s", response.ErrorCodeValidation, errorResp.Error.Code)
+		}
+	})
+}
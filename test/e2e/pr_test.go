@@ -5,6 +5,9 @@ import (
 	"net/http"
 	"testing"
 	"time"
+
+	"avito-backend-trainee-assignment-autumn-2025/pkg/dto/request"
+	"avito-backend-trainee-assignment-autumn-2025/pkg/dto/response"
 )
 
 // TestPRCreate tests POST /pullRequest/create endpoint
@@ -17,66 +20,31 @@ func TestPRCreate(t *testing.T) {
 		user2ID := fmt.Sprintf("user2-%d", time.Now().UnixNano())
 		user3ID := fmt.Sprintf("user3-%d", time.Now().UnixNano())
 
-		createTeamBody := map[string]interface{}{
-			"team_name": teamName,
-			"members": []map[string]interface{}{
-				{"user_id": authorID, "username": "Author", "is_active": true},
-				{"user_id": user1ID, "username": "User1", "is_active": true},
-				{"user_id": user2ID, "username": "User2", "is_active": true},
-				{"user_id": user3ID, "username": "User3", "is_active": true},
-			},
-		}
-
-		resp, err := doRequest(http.MethodPost, "/team/add", createTeamBody)
-		if err != nil {
-			t.Fatalf("Failed to create team: %v", err)
-		}
-		resp.Body.Close()
+		mustCreateTeam(t, teamName,
+			member(authorID, "Author", true),
+			member(user1ID, "User1", true),
+			member(user2ID, "User2", true),
+			member(user3ID, "User3", true),
+		)
 
 		// Create PR
 		prID := fmt.Sprintf("pr-%d", time.Now().UnixNano())
-		createPRBody := map[string]interface{}{
-			"pull_request_id":   prID,
-			"pull_request_name": "Add feature",
-			"author_id":         authorID,
-		}
-
-		resp, err = doRequest(http.MethodPost, "/pullRequest/create", createPRBody)
-		if err != nil {
-			t.Fatalf("Failed to create PR: %v", err)
-		}
-		defer resp.Body.Close()
-
-		assertStatusCode(t, resp, http.StatusCreated)
-
-		var response struct {
-			PR struct {
-				PullRequestID     string   `json:"pull_request_id"`
-				PullRequestName   string   `json:"pull_request_name"`
-				AuthorID          string   `json:"author_id"`
-				Status            string   `json:"status"`
-				AssignedReviewers []string `json:"assigned_reviewers"`
-			} `json:"pr"`
-		}
+		resp := mustCreatePR(t, prID, "Add feature", authorID)
 
-		if err := parseResponse(resp, &response); err != nil {
-			t.Fatalf("Failed to parse response: %v", err)
+		if resp.PR.PullRequestID != prID {
+			t.Errorf("Expected pull_request_id %s, got %s", prID, resp.PR.PullRequestID)
 		}
 
-		if response.PR.PullRequestID != prID {
-			t.Errorf("Expected pull_request_id %s, got %s", prID, response.PR.PullRequestID)
+		if resp.PR.Status != "OPEN" {
+			t.Errorf("Expected status OPEN, got %s", resp.PR.Status)
 		}
 
-		if response.PR.Status != "OPEN" {
-			t.Errorf("Expected status OPEN, got %s", response.PR.Status)
-		}
-
-		if len(response.PR.AssignedReviewers) != 2 {
-			t.Errorf("Expected 2 reviewers, got %d", len(response.PR.AssignedReviewers))
+		if len(resp.PR.AssignedReviewers) != 2 {
+			t.Errorf("Expected 2 reviewers, got %d", len(resp.PR.AssignedReviewers))
 		}
 
 		// Verify author is not in reviewers
-		for _, reviewerID := range response.PR.AssignedReviewers {
+		for _, reviewerID := range resp.PR.AssignedReviewers {
 			if reviewerID == authorID {
 				t.Error("Author should not be assigned as reviewer")
 			}
@@ -88,52 +56,21 @@ func TestPRCreate(t *testing.T) {
 		authorID := fmt.Sprintf("author-%d", time.Now().UnixNano())
 		reviewerID := fmt.Sprintf("reviewer-%d", time.Now().UnixNano())
 
-		createTeamBody := map[string]interface{}{
-			"team_name": teamName,
-			"members": []map[string]interface{}{
-				{"user_id": authorID, "username": "Author", "is_active": true},
-				{"user_id": reviewerID, "username": "Reviewer", "is_active": true},
-			},
-		}
-
-		resp, err := doRequest(http.MethodPost, "/team/add", createTeamBody)
-		if err != nil {
-			t.Fatalf("Failed to create team: %v", err)
-		}
-		resp.Body.Close()
+		mustCreateTeam(t, teamName,
+			member(authorID, "Author", true),
+			member(reviewerID, "Reviewer", true),
+		)
 
 		// Create PR
 		prID := fmt.Sprintf("pr-%d", time.Now().UnixNano())
-		createPRBody := map[string]interface{}{
-			"pull_request_id":   prID,
-			"pull_request_name": "Fix bug",
-			"author_id":         authorID,
-		}
-
-		resp, err = doRequest(http.MethodPost, "/pullRequest/create", createPRBody)
-		if err != nil {
-			t.Fatalf("Failed to create PR: %v", err)
-		}
-		defer resp.Body.Close()
-
-		assertStatusCode(t, resp, http.StatusCreated)
-
-		var response struct {
-			PR struct {
-				AssignedReviewers []string `json:"assigned_reviewers"`
-			} `json:"pr"`
-		}
-
-		if err := parseResponse(resp, &response); err != nil {
-			t.Fatalf("Failed to parse response: %v", err)
-		}
+		resp := mustCreatePR(t, prID, "Fix bug", authorID)
 
-		if len(response.PR.AssignedReviewers) != 1 {
-			t.Errorf("Expected 1 reviewer, got %d", len(response.PR.AssignedReviewers))
+		if len(resp.PR.AssignedReviewers) != 1 {
+			t.Errorf("Expected 1 reviewer, got %d", len(resp.PR.AssignedReviewers))
 		}
 
-		if len(response.PR.AssignedReviewers) > 0 && response.PR.AssignedReviewers[0] != reviewerID {
-			t.Errorf("Expected reviewer %s, got %s", reviewerID, response.PR.AssignedReviewers[0])
+		if len(resp.PR.AssignedReviewers) > 0 && resp.PR.AssignedReviewers[0] != reviewerID {
+			t.Errorf("Expected reviewer %s, got %s", reviewerID, resp.PR.AssignedReviewers[0])
 		}
 	})
 
@@ -142,48 +79,17 @@ func TestPRCreate(t *testing.T) {
 		authorID := fmt.Sprintf("author-%d", time.Now().UnixNano())
 		inactiveID := fmt.Sprintf("inactive-%d", time.Now().UnixNano())
 
-		createTeamBody := map[string]interface{}{
-			"team_name": teamName,
-			"members": []map[string]interface{}{
-				{"user_id": authorID, "username": "Author", "is_active": true},
-				{"user_id": inactiveID, "username": "Inactive", "is_active": false},
-			},
-		}
-
-		resp, err := doRequest(http.MethodPost, "/team/add", createTeamBody)
-		if err != nil {
-			t.Fatalf("Failed to create team: %v", err)
-		}
-		resp.Body.Close()
+		mustCreateTeam(t, teamName,
+			member(authorID, "Author", true),
+			member(inactiveID, "Inactive", false),
+		)
 
 		// Create PR
 		prID := fmt.Sprintf("pr-%d", time.Now().UnixNano())
-		createPRBody := map[string]interface{}{
-			"pull_request_id":   prID,
-			"pull_request_name": "Solo work",
-			"author_id":         authorID,
-		}
-
-		resp, err = doRequest(http.MethodPost, "/pullRequest/create", createPRBody)
-		if err != nil {
-			t.Fatalf("Failed to create PR: %v", err)
-		}
-		defer resp.Body.Close()
-
-		assertStatusCode(t, resp, http.StatusCreated)
+		resp := mustCreatePR(t, prID, "Solo work", authorID)
 
-		var response struct {
-			PR struct {
-				AssignedReviewers []string `json:"assigned_reviewers"`
-			} `json:"pr"`
-		}
-
-		if err := parseResponse(resp, &response); err != nil {
-			t.Fatalf("Failed to parse response: %v", err)
-		}
-
-		if len(response.PR.AssignedReviewers) != 0 {
-			t.Errorf("Expected 0 reviewers, got %d", len(response.PR.AssignedReviewers))
+		if len(resp.PR.AssignedReviewers) != 0 {
+			t.Errorf("Expected 0 reviewers, got %d", len(resp.PR.AssignedReviewers))
 		}
 	})
 
@@ -191,58 +97,32 @@ func TestPRCreate(t *testing.T) {
 		teamName := fmt.Sprintf("dup-team-%d", time.Now().UnixNano())
 		authorID := fmt.Sprintf("author-%d", time.Now().UnixNano())
 
-		createTeamBody := map[string]interface{}{
-			"team_name": teamName,
-			"members": []map[string]interface{}{
-				{"user_id": authorID, "username": "Author", "is_active": true},
-			},
-		}
-
-		resp, err := doRequest(http.MethodPost, "/team/add", createTeamBody)
-		if err != nil {
-			t.Fatalf("Failed to create team: %v", err)
-		}
-		resp.Body.Close()
+		mustCreateTeam(t, teamName, member(authorID, "Author", true))
 
 		// Create PR first time
 		prID := fmt.Sprintf("pr-%d", time.Now().UnixNano())
-		createPRBody := map[string]interface{}{
-			"pull_request_id":   prID,
-			"pull_request_name": "Feature",
-			"author_id":         authorID,
-		}
-
-		resp, err = doRequest(http.MethodPost, "/pullRequest/create", createPRBody)
-		if err != nil {
-			t.Fatalf("Failed to create PR: %v", err)
-		}
-		resp.Body.Close()
+		mustCreatePR(t, prID, "Feature", authorID)
 
 		// Try to create same PR again
-		resp, err = doRequest(http.MethodPost, "/pullRequest/create", createPRBody)
-		if err != nil {
-			t.Fatalf("Failed to make second request: %v", err)
-		}
+		_, err := apiClient.CreatePR(testContext(t), &request.CreatePRRequest{
+			PullRequestID:   prID,
+			PullRequestName: "Feature",
+			AuthorID:        authorID,
+		})
 
-		assertStatusCode(t, resp, http.StatusConflict)
-		assertErrorCode(t, resp, "PR_EXISTS")
+		assertAPIError(t, err, http.StatusConflict, response.ErrorCodePRExists)
 	})
 
 	t.Run("Error - Author not found", func(t *testing.T) {
 		prID := fmt.Sprintf("pr-%d", time.Now().UnixNano())
-		createPRBody := map[string]interface{}{
-			"pull_request_id":   prID,
-			"pull_request_name": "Feature",
-			"author_id":         "nonexistent-author",
-		}
 
-		resp, err := doRequest(http.MethodPost, "/pullRequest/create", createPRBody)
-		if err != nil {
-			t.Fatalf("Failed to make request: %v", err)
-		}
+		_, err := apiClient.CreatePR(testContext(t), &request.CreatePRRequest{
+			PullRequestID:   prID,
+			PullRequestName: "Feature",
+			AuthorID:        "nonexistent-author",
+		})
 
-		assertStatusCode(t, resp, http.StatusNotFound)
-		assertErrorCode(t, resp, "NOT_FOUND")
+		assertAPIError(t, err, http.StatusNotFound, response.ErrorCodeNotFound)
 	})
 }
 
@@ -254,61 +134,20 @@ func TestPRMerge(t *testing.T) {
 		authorID := fmt.Sprintf("author-%d", time.Now().UnixNano())
 		prID := fmt.Sprintf("pr-%d", time.Now().UnixNano())
 
-		createTeamBody := map[string]interface{}{
-			"team_name": teamName,
-			"members": []map[string]interface{}{
-				{"user_id": authorID, "username": "Author", "is_active": true},
-			},
-		}
-
-		resp, err := doRequest(http.MethodPost, "/team/add", createTeamBody)
-		if err != nil {
-			t.Fatalf("Failed to create team: %v", err)
-		}
-		resp.Body.Close()
-
-		createPRBody := map[string]interface{}{
-			"pull_request_id":   prID,
-			"pull_request_name": "Feature",
-			"author_id":         authorID,
-		}
-
-		resp, err = doRequest(http.MethodPost, "/pullRequest/create", createPRBody)
-		if err != nil {
-			t.Fatalf("Failed to create PR: %v", err)
-		}
-		resp.Body.Close()
+		mustCreateTeam(t, teamName, member(authorID, "Author", true))
+		mustCreatePR(t, prID, "Feature", authorID)
 
 		// Merge PR
-		mergeBody := map[string]interface{}{
-			"pull_request_id": prID,
-		}
-
-		resp, err = doRequest(http.MethodPost, "/pullRequest/merge", mergeBody)
+		resp, err := apiClient.MergePR(testContext(t), &request.MergePRRequest{PullRequestID: prID})
 		if err != nil {
 			t.Fatalf("Failed to merge PR: %v", err)
 		}
-		defer resp.Body.Close()
-
-		assertStatusCode(t, resp, http.StatusOK)
-
-		var response struct {
-			PR struct {
-				PullRequestID string `json:"pull_request_id"`
-				Status        string `json:"status"`
-				MergedAt      string `json:"mergedAt"`
-			} `json:"pr"`
-		}
-
-		if err := parseResponse(resp, &response); err != nil {
-			t.Fatalf("Failed to parse response: %v", err)
-		}
 
-		if response.PR.Status != "MERGED" {
-			t.Errorf("Expected status MERGED, got %s", response.PR.Status)
+		if resp.PR.Status != "MERGED" {
+			t.Errorf("Expected status MERGED, got %s", resp.PR.Status)
 		}
 
-		if response.PR.MergedAt == "" {
+		if resp.PR.MergedAt == nil {
 			t.Error("Expected mergedAt to be set")
 		}
 	})
@@ -319,93 +158,39 @@ func TestPRMerge(t *testing.T) {
 		authorID := fmt.Sprintf("author-%d", time.Now().UnixNano())
 		prID := fmt.Sprintf("pr-%d", time.Now().UnixNano())
 
-		createTeamBody := map[string]interface{}{
-			"team_name": teamName,
-			"members": []map[string]interface{}{
-				{"user_id": authorID, "username": "Author", "is_active": true},
-			},
-		}
-
-		resp, err := doRequest(http.MethodPost, "/team/add", createTeamBody)
-		if err != nil {
-			t.Fatalf("Failed to create team: %v", err)
-		}
-		resp.Body.Close()
-
-		createPRBody := map[string]interface{}{
-			"pull_request_id":   prID,
-			"pull_request_name": "Feature",
-			"author_id":         authorID,
-		}
-
-		resp, err = doRequest(http.MethodPost, "/pullRequest/create", createPRBody)
-		if err != nil {
-			t.Fatalf("Failed to create PR: %v", err)
-		}
-		resp.Body.Close()
+		mustCreateTeam(t, teamName, member(authorID, "Author", true))
+		mustCreatePR(t, prID, "Feature", authorID)
 
 		// Merge PR first time
-		mergeBody := map[string]interface{}{
-			"pull_request_id": prID,
-		}
+		mergeReq := &request.MergePRRequest{PullRequestID: prID}
 
-		resp, err = doRequest(http.MethodPost, "/pullRequest/merge", mergeBody)
+		firstResponse, err := apiClient.MergePR(testContext(t), mergeReq)
 		if err != nil {
 			t.Fatalf("Failed to merge PR first time: %v", err)
 		}
 
-		var firstResponse struct {
-			PR struct {
-				MergedAt string `json:"mergedAt"`
-			} `json:"pr"`
-		}
-		if err := parseResponse(resp, &firstResponse); err != nil {
-			t.Fatalf("Failed to parse first response: %v", err)
-		}
-
 		// Merge PR second time (should be idempotent)
-		resp, err = doRequest(http.MethodPost, "/pullRequest/merge", mergeBody)
+		secondResponse, err := apiClient.MergePR(testContext(t), mergeReq)
 		if err != nil {
 			t.Fatalf("Failed to merge PR second time: %v", err)
 		}
-		defer resp.Body.Close()
-
-		assertStatusCode(t, resp, http.StatusOK)
-
-		var secondResponse struct {
-			PR struct {
-				Status   string `json:"status"`
-				MergedAt string `json:"mergedAt"`
-			} `json:"pr"`
-		}
-
-		if err := parseResponse(resp, &secondResponse); err != nil {
-			t.Fatalf("Failed to parse second response: %v", err)
-		}
 
 		if secondResponse.PR.Status != "MERGED" {
 			t.Errorf("Expected status MERGED, got %s", secondResponse.PR.Status)
 		}
 
 		// mergedAt should be the same
-		if firstResponse.PR.MergedAt != secondResponse.PR.MergedAt {
-			t.Errorf("mergedAt changed between calls: %s vs %s",
+		if firstResponse.PR.MergedAt == nil || secondResponse.PR.MergedAt == nil ||
+			!firstResponse.PR.MergedAt.Equal(*secondResponse.PR.MergedAt) {
+			t.Errorf("mergedAt changed between calls: %v vs %v",
 				firstResponse.PR.MergedAt, secondResponse.PR.MergedAt)
 		}
 	})
 
 	t.Run("Error - PR not found", func(t *testing.T) {
-		mergeBody := map[string]interface{}{
-			"pull_request_id": "nonexistent-pr",
-		}
+		_, err := apiClient.MergePR(testContext(t), &request.MergePRRequest{PullRequestID: "nonexistent-pr"})
 
-		resp, err := doRequest(http.MethodPost, "/pullRequest/merge", mergeBody)
-		if err != nil {
-			t.Fatalf("Failed to make request: %v", err)
-		}
-
-		assertStatusCode(t, resp, http.StatusNotFound)
-		assertErrorCode(t, resp, "NOT_FOUND")
+		assertAPIError(t, err, http.StatusNotFound, response.ErrorCodeNotFound)
 	})
 }
 
@@ -419,44 +204,16 @@ func TestPRReassign(t *testing.T) {
 		user2ID := fmt.Sprintf("user2-%d", time.Now().UnixNano())
 		user3ID := fmt.Sprintf("user3-%d", time.Now().UnixNano())
 
-		createTeamBody := map[string]interface{}{
-			"team_name": teamName,
-			"members": []map[string]interface{}{
-				{"user_id": authorID, "username": "Author", "is_active": true},
-				{"user_id": user1ID, "username": "User1", "is_active": true},
-				{"user_id": user2ID, "username": "User2", "is_active": true},
-				{"user_id": user3ID, "username": "User3", "is_active": true},
-			},
-		}
-
-		resp, err := doRequest(http.MethodPost, "/team/add", createTeamBody)
-		if err != nil {
-			t.Fatalf("Failed to create team: %v", err)
-		}
-		resp.Body.Close()
+		mustCreateTeam(t, teamName,
+			member(authorID, "Author", true),
+			member(user1ID, "User1", true),
+			member(user2ID, "User2", true),
+			member(user3ID, "User3", true),
+		)
 
 		// Create PR (will assign 2 reviewers randomly)
 		prID := fmt.Sprintf("pr-%d", time.Now().UnixNano())
-		createPRBody := map[string]interface{}{
-			"pull_request_id":   prID,
-			"pull_request_name": "Feature",
-			"author_id":         authorID,
-		}
-
-		resp, err = doRequest(http.MethodPost, "/pullRequest/create", createPRBody)
-		if err != nil {
-			t.Fatalf("Failed to create PR: %v", err)
-		}
-
-		var createResponse struct {
-			PR struct {
-				AssignedReviewers []string `json:"assigned_reviewers"`
-			} `json:"pr"`
-		}
-
-		if err := parseResponse(resp, &createResponse); err != nil {
-			t.Fatalf("Failed to parse create response: %v", err)
-		}
+		createResponse := mustCreatePR(t, prID, "Feature", authorID)
 
 		if len(createResponse.PR.AssignedReviewers) == 0 {
 			t.Skip("No reviewers assigned, skipping reassign test")
@@ -466,29 +223,13 @@ func TestPRReassign(t *testing.T) {
 		oldReviewerID := createResponse.PR.AssignedReviewers[0]
 
 		// Reassign that reviewer
-		reassignBody := map[string]interface{}{
-			"pull_request_id": prID,
-			"old_user_id":     oldReviewerID,
-		}
-
-		resp, err = doRequest(http.MethodPost, "/pullRequest/reassign", reassignBody)
+		reassignResponse, err := apiClient.ReassignReviewer(testContext(t), &request.ReassignReviewerRequest{
+			PullRequestID: prID,
+			OldUserID:     oldReviewerID,
+		})
 		if err != nil {
 			t.Fatalf("Failed to reassign reviewer: %v", err)
 		}
-		defer resp.Body.Close()
-
-		assertStatusCode(t, resp, http.StatusOK)
-
-		var reassignResponse struct {
-			PR struct {
-				AssignedReviewers []string `json:"assigned_reviewers"`
-			} `json:"pr"`
-			ReplacedBy string `json:"replaced_by"`
-		}
-
-		if err := parseResponse(resp, &reassignResponse); err != nil {
-			t.Fatalf("Failed to parse reassign response: %v", err)
-		}
 
 		// Verify old reviewer is no longer in the list
 		for _, reviewerID := range reassignResponse.PR.AssignedReviewers {
@@ -525,44 +266,16 @@ func TestPRReassign(t *testing.T) {
 
 		allReviewerIDs := []string{reviewer1ID, reviewer2ID, reviewer3ID}
 
-		createTeamBody := map[string]interface{}{
-			"team_name": teamName,
-			"members": []map[string]interface{}{
-				{"user_id": authorID, "username": "Author", "is_active": true},
-				{"user_id": reviewer1ID, "username": "Reviewer1", "is_active": true},
-				{"user_id": reviewer2ID, "username": "Reviewer2", "is_active": true},
-				{"user_id": reviewer3ID, "username": "Reviewer3", "is_active": true},
-			},
-		}
-
-		resp, err := doRequest(http.MethodPost, "/team/add", createTeamBody)
-		if err != nil {
-			t.Fatalf("Failed to create team: %v", err)
-		}
-		resp.Body.Close()
+		mustCreateTeam(t, teamName,
+			member(authorID, "Author", true),
+			member(reviewer1ID, "Reviewer1", true),
+			member(reviewer2ID, "Reviewer2", true),
+			member(reviewer3ID, "Reviewer3", true),
+		)
 
 		// Create PR
 		prID := fmt.Sprintf("pr-%d", time.Now().UnixNano())
-		createPRBody := map[string]interface{}{
-			"pull_request_id":   prID,
-			"pull_request_name": "Feature",
-			"author_id":         authorID,
-		}
-
-		resp, err = doRequest(http.MethodPost, "/pullRequest/create", createPRBody)
-		if err != nil {
-			t.Fatalf("Failed to create PR: %v", err)
-		}
-
-		// Parse response to see who was assigned
-		var createResponse struct {
-			PR struct {
-				AssignedReviewers []string `json:"assigned_reviewers"`
-			} `json:"pr"`
-		}
-		if err := parseResponse(resp, &createResponse); err != nil {
-			t.Fatalf("Failed to parse create response: %v", err)
-		}
+		createResponse := mustCreatePR(t, prID, "Feature", authorID)
 
 		// Find a user who was NOT assigned
 		var notAssignedID string
@@ -585,18 +298,12 @@ func TestPRReassign(t *testing.T) {
 		}
 
 		// Try to reassign a user who is not assigned
-		reassignBody := map[string]interface{}{
-			"pull_request_id": prID,
-			"old_user_id":     notAssignedID,
-		}
-
-		resp, err = doRequest(http.MethodPost, "/pullRequest/reassign", reassignBody)
-		if err != nil {
-			t.Fatalf("Failed to make request: %v", err)
-		}
+		_, err := apiClient.ReassignReviewer(testContext(t), &request.ReassignReviewerRequest{
+			PullRequestID: prID,
+			OldUserID:     notAssignedID,
+		})
 
-		assertStatusCode(t, resp, http.StatusConflict)
-		assertErrorCode(t, resp, "NOT_ASSIGNED")
+		assertAPIError(t, err, http.StatusConflict, response.ErrorCodeNotAssigned)
 	})
 
 	t.Run("Error - Cannot reassign on merged PR", func(t *testing.T) {
@@ -605,58 +312,27 @@ func TestPRReassign(t *testing.T) {
 		authorID := fmt.Sprintf("author-%d", time.Now().UnixNano())
 		reviewerID := fmt.Sprintf("reviewer-%d", time.Now().UnixNano())
 
-		createTeamBody := map[string]interface{}{
-			"team_name": teamName,
-			"members": []map[string]interface{}{
-				{"user_id": authorID, "username": "Author", "is_active": true},
-				{"user_id": reviewerID, "username": "Reviewer", "is_active": true},
-			},
-		}
-
-		resp, err := doRequest(http.MethodPost, "/team/add", createTeamBody)
-		if err != nil {
-			t.Fatalf("Failed to create team: %v", err)
-		}
-		resp.Body.Close()
+		mustCreateTeam(t, teamName,
+			member(authorID, "Author", true),
+			member(reviewerID, "Reviewer", true),
+		)
 
 		// Create PR
 		prID := fmt.Sprintf("pr-%d", time.Now().UnixNano())
-		createPRBody := map[string]interface{}{
-			"pull_request_id":   prID,
-			"pull_request_name": "Feature",
-			"author_id":         authorID,
-		}
-
-		resp, err = doRequest(http.MethodPost, "/pullRequest/create", createPRBody)
-		if err != nil {
-			t.Fatalf("Failed to create PR: %v", err)
-		}
-		resp.Body.Close()
+		mustCreatePR(t, prID, "Feature", authorID)
 
 		// Merge the PR
-		mergeBody := map[string]interface{}{
-			"pull_request_id": prID,
-		}
-
-		resp, err = doRequest(http.MethodPost, "/pullRequest/merge", mergeBody)
-		if err != nil {
+		if _, err := apiClient.MergePR(testContext(t), &request.MergePRRequest{PullRequestID: prID}); err != nil {
 			t.Fatalf("Failed to merge PR: %v", err)
 		}
-		resp.Body.Close()
 
 		// Try to reassign after merge
-		reassignBody := map[string]interface{}{
-			"pull_request_id": prID,
-			"old_user_id":     reviewerID,
-		}
+		_, err := apiClient.ReassignReviewer(testContext(t), &request.ReassignReviewerRequest{
+			PullRequestID: prID,
+			OldUserID:     reviewerID,
+		})
 
-		resp, err = doRequest(http.MethodPost, "/pullRequest/reassign", reassignBody)
-		if err != nil {
-			t.Fatalf("Failed to make request: %v", err)
-		}
-
-		assertStatusCode(t, resp, http.StatusConflict)
-		assertErrorCode(t, resp, "PR_MERGED")
+		assertAPIError(t, err, http.StatusConflict, response.ErrorCodePRMerged)
 	})
 
 	t.Run("Error - No candidate for replacement", func(t *testing.T) {
@@ -665,61 +341,158 @@ func TestPRReassign(t *testing.T) {
 		authorID := fmt.Sprintf("author-%d", time.Now().UnixNano())
 		reviewerID := fmt.Sprintf("reviewer-%d", time.Now().UnixNano())
 
-		createTeamBody := map[string]interface{}{
-			"team_name": teamName,
-			"members": []map[string]interface{}{
-				{"user_id": authorID, "username": "Author", "is_active": true},
-				{"user_id": reviewerID, "username": "Reviewer", "is_active": true},
-			},
-		}
-
-		resp, err := doRequest(http.MethodPost, "/team/add", createTeamBody)
-		if err != nil {
-			t.Fatalf("Failed to create team: %v", err)
-		}
-		resp.Body.Close()
+		mustCreateTeam(t, teamName,
+			member(authorID, "Author", true),
+			member(reviewerID, "Reviewer", true),
+		)
 
 		// Create PR (will assign 1 reviewer)
 		prID := fmt.Sprintf("pr-%d", time.Now().UnixNano())
-		createPRBody := map[string]interface{}{
-			"pull_request_id":   prID,
-			"pull_request_name": "Feature",
-			"author_id":         authorID,
-		}
+		mustCreatePR(t, prID, "Feature", authorID)
+
+		// Try to reassign the only reviewer (no candidates available)
+		_, err := apiClient.ReassignReviewer(testContext(t), &request.ReassignReviewerRequest{
+			PullRequestID: prID,
+			OldUserID:     reviewerID,
+		})
+
+		assertAPIError(t, err, http.StatusConflict, response.ErrorCodeNoCandidate)
+	})
+
+	t.Run("Error - All candidates at capacity", func(t *testing.T) {
+		teamName := fmt.Sprintf("capacity-team-%d", time.Now().UnixNano())
+		authorID := fmt.Sprintf("author-%d", time.Now().UnixNano())
+		reviewerID := fmt.Sprintf("reviewer-%d", time.Now().UnixNano())
+		otherID := fmt.Sprintf("other-%d", time.Now().UnixNano())
+
+		limited := member(otherID, "Other", true)
+		limited.MaxOpenReviews = 1
+		mustCreateTeam(t, teamName,
+			member(authorID, "Author", true),
+			member(reviewerID, "Reviewer", true),
+			limited,
+		)
+
+		// The first PR takes the only open review slot of the limited teammate
+		prID := fmt.Sprintf("pr-%d", time.Now().UnixNano())
+		mustCreatePR(t, prID, "Feature", authorID)
 
-		resp, err = doRequest(http.MethodPost, "/pullRequest/create", createPRBody)
+		resp, err := apiClient.SetMaxOpenReviews(testContext(t), &request.SetMaxOpenReviewsRequest{UserID: reviewerID, MaxOpenReviews: 1})
 		if err != nil {
-			t.Fatalf("Failed to create PR: %v", err)
+			t.Fatalf("Failed to set max open reviews: %v", err)
 		}
-		resp.Body.Close()
-
-		// Try to reassign the only reviewer (no candidates available)
-		reassignBody := map[string]interface{}{
-			"pull_request_id": prID,
-			"old_user_id":     reviewerID,
+		if resp.User.MaxOpenReviews != 1 {
+			t.Errorf("Expected max_open_reviews 1, got %d", resp.User.MaxOpenReviews)
 		}
 
-		resp, err = doRequest(http.MethodPost, "/pullRequest/reassign", reassignBody)
+		_, err = apiClient.CreatePR(testContext(t), &request.CreatePRRequest{
+			PullRequestID:   prID + "-2",
+			PullRequestName: "Another feature",
+			AuthorID:        authorID,
+		})
+
+		assertAPIError(t, err, http.StatusConflict, response.ErrorCodeAllAtCapacity)
+	})
+
+	t.Run("Error - Seniority policy cannot be kept", func(t *testing.T) {
+		teamName := fmt.Sprintf("seniority-team-%d", time.Now().UnixNano())
+		authorID := fmt.Sprintf("author-%d", time.Now().UnixNano())
+		seniorID := fmt.Sprintf("senior-%d", time.Now().UnixNano())
+		juniorID := fmt.Sprintf("junior-%d", time.Now().UnixNano())
+		otherID := fmt.Sprintf("other-%d", time.Now().UnixNano())
+
+		mustCreateTeam(t, teamName,
+			member(authorID, "Author", true),
+			member(seniorID, "Senior", true),
+			member(juniorID, "Junior", true),
+			member(otherID, "Other", true),
+		)
+		user, err := apiClient.SetSeniority(testContext(t), &request.SetSeniorityRequest{UserID: seniorID, Seniority: "senior"})
+		if err != nil {
+			t.Fatalf("Failed to set seniority: %v", err)
+		}
+		if user.User.Seniority != "senior" {
+			t.Errorf("Expected seniority senior, got %q", user.User.Seniority)
+		}
+		policy, err := apiClient.SetSeniorityPolicy(testContext(t), &request.SetSeniorityPolicyRequest{TeamName: teamName, MinReviewerSeniority: "middle"})
 		if err != nil {
-			t.Fatalf("Failed to make request: %v", err)
+			t.Fatalf("Failed to set seniority policy: %v", err)
+		}
+		if policy.MinReviewerSeniority != "middle" {
+			t.Errorf("Expected policy middle, got %q", policy.MinReviewerSeniority)
 		}
 
-		assertStatusCode(t, resp, http.StatusConflict)
-		assertErrorCode(t, resp, "NO_CANDIDATE")
+		// The senior is assigned first; nobody else may take over from them
+		prID := fmt.Sprintf("pr-%d", time.Now().UnixNano())
+		pr := mustCreatePR(t, prID, "Feature", authorID)
+		if len(pr.PR.AssignedReviewers) != 2 || pr.PR.AssignedReviewers[0] != seniorID {
+			t.Fatalf("Expected %s first among two reviewers, got %v", seniorID, pr.PR.AssignedReviewers)
+		}
+
+		_, err = apiClient.ReassignReviewer(testContext(t), &request.ReassignReviewerRequest{
+			PullRequestID: prID,
+			OldUserID:     seniorID,
+		})
+
+		assertAPIError(t, err, http.StatusConflict, response.ErrorCodeSeniorityRequired)
 	})
 
 	t.Run("Error - PR not found", func(t *testing.T) {
-		reassignBody := map[string]interface{}{
-			"pull_request_id": "nonexistent-pr",
-			"old_user_id":     "some-user",
+		_, err := apiClient.ReassignReviewer(testContext(t), &request.ReassignReviewerRequest{
+			PullRequestID: "nonexistent-pr",
+			OldUserID:     "some-user",
+		})
+
+		assertAPIError(t, err, http.StatusNotFound, response.ErrorCodeNotFound)
+	})
+}
+
+func TestPRHistory(t *testing.T) {
+	t.Run("Success - Manual reassignments are recorded", func(t *testing.T) {
+		teamName := fmt.Sprintf("history-team-%d", time.Now().UnixNano())
+		authorID := fmt.Sprintf("author-%d", time.Now().UnixNano())
+		mustCreateTeam(t, teamName,
+			member(authorID, "Author", true),
+			member(fmt.Sprintf("user1-%d", time.Now().UnixNano()), "User1", true),
+			member(fmt.Sprintf("user2-%d", time.Now().UnixNano()), "User2", true),
+			member(fmt.Sprintf("user3-%d", time.Now().UnixNano()), "User3", true),
+		)
+		prID := fmt.Sprintf("pr-%d", time.Now().UnixNano())
+		createResponse := mustCreatePR(t, prID, "Feature", authorID)
+
+		history, err := apiClient.GetPRHistory(testContext(t), prID)
+		if err != nil {
+			t.Fatalf("Failed to get PR history: %v", err)
+		}
+		if history.PullRequestID != prID || len(history.Reassignments) != 0 {
+			t.Fatalf("Expected an empty history for a new PR, got %+v", history)
+		}
+
+		oldReviewerID := createResponse.PR.AssignedReviewers[0]
+		reassignResponse, err := apiClient.ReassignReviewer(testContext(t), &request.ReassignReviewerRequest{
+			PullRequestID: prID,
+			OldUserID:     oldReviewerID,
+		})
+		if err != nil {
+			t.Fatalf("Failed to reassign reviewer: %v", err)
 		}
 
-		resp, err := doRequest(http.MethodPost, "/pullRequest/reassign", reassignBody)
+		history, err = apiClient.GetPRHistory(testContext(t), prID)
 		if err != nil {
-			t.Fatalf("Failed to make request: %v", err)
+			t.Fatalf("Failed to get PR history: %v", err)
 		}
+		if len(history.Reassignments) != 1 {
+			t.Fatalf("Expected one reassignment, got %+v", history.Reassignments)
+		}
+		got := history.Reassignments[0]
+		if got.OldReviewerID != oldReviewerID || got.NewReviewerID != reassignResponse.ReplacedBy || got.Reason != "manual" {
+			t.Errorf("Expected %s -> %s for manual, got %+v", oldReviewerID, reassignResponse.ReplacedBy, got)
+		}
+	})
+
+	t.Run("Error - PR not found", func(t *testing.T) {
+		_, err := apiClient.GetPRHistory(testContext(t), "nonexistent-pr")
 
-		assertStatusCode(t, resp, http.StatusNotFound)
-		assertErrorCode(t, resp, "NOT_FOUND")
+		assertAPIError(t, err, http.StatusNotFound, response.ErrorCodeNotFound)
 	})
 }
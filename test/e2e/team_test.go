@@ -5,6 +5,9 @@ import (
 	"net/http"
 	"testing"
 	"time"
+
+	"avito-backend-trainee-assignment-autumn-2025/pkg/dto/request"
+	"avito-backend-trainee-assignment-autumn-2025/pkg/dto/response"
 )
 
 // TestTeamCreate tests POST /team/add endpoint
@@ -12,105 +15,51 @@ func TestTeamCreate(t *testing.T) {
 	t.Run("Success - Create team with members", func(t *testing.T) {
 		teamName := fmt.Sprintf("test-team-%d", generateID())
 
-		requestBody := map[string]interface{}{
-			"team_name": teamName,
-			"members": []map[string]interface{}{
-				{
-					"user_id":   fmt.Sprintf("user-%d-1", generateID()),
-					"username":  "Alice",
-					"is_active": true,
-				},
-				{
-					"user_id":   fmt.Sprintf("user-%d-2", generateID()),
-					"username":  "Bob",
-					"is_active": true,
-				},
-				{
-					"user_id":   fmt.Sprintf("user-%d-3", generateID()),
-					"username":  "Charlie",
-					"is_active": false,
-				},
-			},
-		}
-
-		resp, err := doRequest(http.MethodPost, "/team/add", requestBody)
-		if err != nil {
-			t.Fatalf("Failed to create team: %v", err)
-		}
-		defer resp.Body.Close()
-
-		assertStatusCode(t, resp, http.StatusCreated)
-
-		var response struct {
-			Team struct {
-				TeamName string `json:"team_name"`
-				Members  []struct {
-					UserID   string `json:"user_id"`
-					Username string `json:"username"`
-					IsActive bool   `json:"is_active"`
-				} `json:"members"`
-			} `json:"team"`
-		}
-
-		if err := parseResponse(resp, &response); err != nil {
-			t.Fatalf("Failed to parse response: %v", err)
-		}
+		resp := mustCreateTeam(t, teamName,
+			member(fmt.Sprintf("user-%d-1", generateID()), "Alice", true),
+			member(fmt.Sprintf("user-%d-2", generateID()), "Bob", true),
+			member(fmt.Sprintf("user-%d-3", generateID()), "Charlie", false),
+		)
 
-		if response.Team.TeamName != teamName {
-			t.Errorf("Expected team_name %s, got %s", teamName, response.Team.TeamName)
+		if resp.Team.TeamName != teamName {
+			t.Errorf("Expected team_name %s, got %s", teamName, resp.Team.TeamName)
 		}
 
-		if len(response.Team.Members) != 3 {
-			t.Errorf("Expected 3 members, got %d", len(response.Team.Members))
+		if len(resp.Team.Members) != 3 {
+			t.Errorf("Expected 3 members, got %d", len(resp.Team.Members))
 		}
 	})
 
 	t.Run("Error - Team already exists", func(t *testing.T) {
 		teamName := fmt.Sprintf("duplicate-team-%d", generateID())
-
-		requestBody := map[string]interface{}{
-			"team_name": teamName,
-			"members": []map[string]interface{}{
-				{
-					"user_id":   fmt.Sprintf("user-%d-1", generateID()),
-					"username":  "Alice",
-					"is_active": true,
-				},
+		req := &request.CreateTeamRequest{
+			TeamName: teamName,
+			Members: []request.TeamMemberRequest{
+				member(fmt.Sprintf("user-%d-1", generateID()), "Alice", true),
 			},
 		}
 
 		// Create team first time
-		resp, err := doRequest(http.MethodPost, "/team/add", requestBody)
-		if err != nil {
+		if _, err := apiClient.CreateTeam(testContext(t), req); err != nil {
 			t.Fatalf("Failed to create team: %v", err)
 		}
-		resp.Body.Close()
 
 		// Try to create same team again
-		resp, err = doRequest(http.MethodPost, "/team/add", requestBody)
-		if err != nil {
-			t.Fatalf("Failed to make second request: %v", err)
-		}
+		_, err := apiClient.CreateTeam(testContext(t), req)
 
-		assertStatusCode(t, resp, http.StatusBadRequest)
-		assertErrorCode(t, resp, "TEAM_EXISTS")
+		assertAPIError(t, err, http.StatusBadRequest, response.ErrorCodeTeamExists)
 	})
 
 	t.Run("Success - Create team with no members", func(t *testing.T) {
 		teamName := fmt.Sprintf("empty-team-%d", generateID())
 
-		requestBody := map[string]interface{}{
-			"team_name": teamName,
-			"members":   []map[string]interface{}{},
-		}
-
-		resp, err := doRequest(http.MethodPost, "/team/add", requestBody)
+		_, err := apiClient.CreateTeam(testContext(t), &request.CreateTeamRequest{
+			TeamName: teamName,
+			Members:  []request.TeamMemberRequest{},
+		})
 		if err != nil {
 			t.Fatalf("Failed to create team: %v", err)
 		}
-		defer resp.Body.Close()
-
-		assertStatusCode(t, resp, http.StatusCreated)
 	})
 }
 
@@ -122,59 +71,219 @@ func TestTeamGet(t *testing.T) {
 		userID1 := fmt.Sprintf("user-%d-1", generateID())
 		userID2 := fmt.Sprintf("user-%d-2", generateID())
 
-		createBody := map[string]interface{}{
-			"team_name": teamName,
-			"members": []map[string]interface{}{
-				{"user_id": userID1, "username": "Alice", "is_active": true},
-				{"user_id": userID2, "username": "Bob", "is_active": false},
-			},
+		mustCreateTeam(t, teamName,
+			member(userID1, "Alice", true),
+			member(userID2, "Bob", false),
+		)
+
+		// Now get the team
+		resp, err := apiClient.GetTeam(testContext(t), teamName)
+		if err != nil {
+			t.Fatalf("Failed to get team: %v", err)
+		}
+
+		if resp.TeamName != teamName {
+			t.Errorf("Expected team_name %s, got %s", teamName, resp.TeamName)
 		}
 
-		resp, err := doRequest(http.MethodPost, "/team/add", createBody)
+		if len(resp.Members) != 2 {
+			t.Errorf("Expected 2 members, got %d", len(resp.Members))
+		}
+	})
+
+	t.Run("Error - Team not found", func(t *testing.T) {
+		_, err := apiClient.GetTeam(testContext(t), "nonexistent-team")
+
+		assertAPIError(t, err, http.StatusNotFound, response.ErrorCodeNotFound)
+	})
+}
+
+// TestTeamSetChatWebhook tests POST /team/setChatWebhook endpoint
+func TestTeamSetChatWebhook(t *testing.T) {
+	t.Run("Success - Enable and disable chat notifications", func(t *testing.T) {
+		teamName := fmt.Sprintf("chat-team-%d", generateID())
+		mustCreateTeam(t, teamName, member(fmt.Sprintf("user-%d", generateID()), "Alice", true))
+
+		resp, err := apiClient.SetChatWebhook(testContext(t), &request.SetChatWebhookRequest{
+			TeamName:   teamName,
+			WebhookURL: "https://hooks.example.com/services/T000/B000/XXXX",
+		})
 		if err != nil {
-			t.Fatalf("Failed to create team: %v", err)
+			t.Fatalf("Failed to set chat webhook: %v", err)
+		}
+		if resp.TeamName != teamName || !resp.ChatNotifications {
+			t.Errorf("Expected chat notifications enabled for %s, got %+v", teamName, resp)
 		}
-		resp.Body.Close()
 
-		// Now get the team
-		resp, err = doGet("/team/get", map[string]string{"team_name": teamName})
+		resp, err = apiClient.SetChatWebhook(testContext(t), &request.SetChatWebhookRequest{TeamName: teamName})
 		if err != nil {
-			t.Fatalf("Failed to get team: %v", err)
+			t.Fatalf("Failed to clear chat webhook: %v", err)
+		}
+		if resp.ChatNotifications {
+			t.Error("Expected chat notifications to be disabled")
 		}
-		defer resp.Body.Close()
+	})
 
-		assertStatusCode(t, resp, http.StatusOK)
+	t.Run("Error - Invalid URL", func(t *testing.T) {
+		_, err := apiClient.SetChatWebhook(testContext(t), &request.SetChatWebhookRequest{
+			TeamName:   "any-team",
+			WebhookURL: "not a url",
+		})
 
-		var response struct {
-			TeamName string `json:"team_name"`
-			Members  []struct {
-				UserID   string `json:"user_id"`
-				Username string `json:"username"`
-				IsActive bool   `json:"is_active"`
-			} `json:"members"`
-		}
+		assertAPIError(t, err, http.StatusBadRequest, response.ErrorCodeValidation)
+	})
+
+	t.Run("Error - Team not found", func(t *testing.T) {
+		_, err := apiClient.SetChatWebhook(testContext(t), &request.SetChatWebhookRequest{
+			TeamName:   "nonexistent-team",
+			WebhookURL: "https://hooks.example.com/services/T000/B000/XXXX",
+		})
+
+		assertAPIError(t, err, http.StatusNotFound, response.ErrorCodeNotFound)
+	})
+}
+
+// TestTeamSetReviewSLA tests POST /team/setReviewSLA endpoint
+func TestTeamSetReviewSLA(t *testing.T) {
+	t.Run("Success - Set and reset the review SLA", func(t *testing.T) {
+		teamName := fmt.Sprintf("sla-team-%d", generateID())
+		mustCreateTeam(t, teamName, member(fmt.Sprintf("user-%d", generateID()), "Alice", true))
 
-		if err := parseResponse(resp, &response); err != nil {
-			t.Fatalf("Failed to parse response: %v", err)
+		resp, err := apiClient.SetReviewSLA(testContext(t), &request.SetReviewSLARequest{
+			TeamName:         teamName,
+			ReviewSLAMinutes: 240,
+		})
+		if err != nil {
+			t.Fatalf("Failed to set review SLA: %v", err)
+		}
+		if resp.TeamName != teamName || resp.ReviewSLAMinutes != 240 {
+			t.Errorf("Expected a 240 minute SLA for %s, got %+v", teamName, resp)
 		}
 
-		if response.TeamName != teamName {
-			t.Errorf("Expected team_name %s, got %s", teamName, response.TeamName)
+		team, err := apiClient.GetTeam(testContext(t), teamName)
+		if err != nil {
+			t.Fatalf("Failed to get team: %v", err)
+		}
+		if team.ReviewSLAMinutes != 240 {
+			t.Errorf("Expected team review SLA 240, got %d", team.ReviewSLAMinutes)
 		}
 
-		if len(response.Members) != 2 {
-			t.Errorf("Expected 2 members, got %d", len(response.Members))
+		if _, err := apiClient.SetReviewSLA(testContext(t), &request.SetReviewSLARequest{TeamName: teamName}); err != nil {
+			t.Fatalf("Failed to reset review SLA: %v", err)
+		}
+		team, err = apiClient.GetTeam(testContext(t), teamName)
+		if err != nil {
+			t.Fatalf("Failed to get team: %v", err)
+		}
+		if team.ReviewSLAMinutes != 0 {
+			t.Errorf("Expected the default review SLA, got %d minutes", team.ReviewSLAMinutes)
 		}
 	})
 
+	t.Run("Error - Negative SLA", func(t *testing.T) {
+		_, err := apiClient.SetReviewSLA(testContext(t), &request.SetReviewSLARequest{
+			TeamName:         "any-team",
+			ReviewSLAMinutes: -1,
+		})
+
+		assertAPIError(t, err, http.StatusBadRequest, response.ErrorCodeValidation)
+	})
+
 	t.Run("Error - Team not found", func(t *testing.T) {
-		resp, err := doGet("/team/get", map[string]string{"team_name": "nonexistent-team"})
+		_, err := apiClient.SetReviewSLA(testContext(t), &request.SetReviewSLARequest{
+			TeamName:         "nonexistent-team",
+			ReviewSLAMinutes: 60,
+		})
+
+		assertAPIError(t, err, http.StatusNotFound, response.ErrorCodeNotFound)
+	})
+}
+
+func TestTeamEscalation(t *testing.T) {
+	t.Run("Success - Escalate overdue reviews to the lead", func(t *testing.T) {
+		teamName := fmt.Sprintf("escalation-team-%d", generateID())
+		leadID := fmt.Sprintf("lead-%d", generateID())
+		mustCreateTeam(t, teamName, member(leadID, "Lead", true), member(fmt.Sprintf("user-%d", generateID()), "Alice", true))
+
+		// Escalation needs a lead
+		_, err := apiClient.SetReviewSLA(testContext(t), &request.SetReviewSLARequest{
+			TeamName:         teamName,
+			ReviewSLAMinutes: 60,
+			SLAAction:        "escalate",
+		})
+		assertAPIError(t, err, http.StatusBadRequest, response.ErrorCodeValidation)
+
+		if _, err := apiClient.SetLead(testContext(t), &request.SetLeadRequest{TeamName: teamName, LeadID: leadID}); err != nil {
+			t.Fatalf("Failed to set team lead: %v", err)
+		}
+		resp, err := apiClient.SetReviewSLA(testContext(t), &request.SetReviewSLARequest{
+			TeamName:         teamName,
+			ReviewSLAMinutes: 60,
+			SLAAction:        "escalate",
+		})
 		if err != nil {
-			t.Fatalf("Failed to make request: %v", err)
+			t.Fatalf("Failed to set review SLA: %v", err)
+		}
+		if resp.SLAAction != "escalate" {
+			t.Errorf("Expected the escalate action, got %+v", resp)
 		}
 
-		assertStatusCode(t, resp, http.StatusNotFound)
-		assertErrorCode(t, resp, "NOT_FOUND")
+		team, err := apiClient.GetTeam(testContext(t), teamName)
+		if err != nil {
+			t.Fatalf("Failed to get team: %v", err)
+		}
+		if team.SLAAction != "escalate" || team.LeadID != leadID {
+			t.Errorf("Expected escalation to %s, got %+v", leadID, team)
+		}
+	})
+
+	t.Run("Success - Set the team calendar", func(t *testing.T) {
+		teamName := fmt.Sprintf("calendar-team-%d", generateID())
+		mustCreateTeam(t, teamName, member(fmt.Sprintf("user-%d", generateID()), "Alice", true))
+
+		team, err := apiClient.GetTeam(testContext(t), teamName)
+		if err != nil {
+			t.Fatalf("Failed to get team: %v", err)
+		}
+		if team.SLAAction != "remind" || team.Calendar.Timezone != "UTC" || fmt.Sprint(team.Calendar.WeekendDays) != "[sunday saturday]" {
+			t.Errorf("Expected reminders on the default calendar, got %+v", team)
+		}
+
+		resp, err := apiClient.SetCalendar(testContext(t), &request.SetCalendarRequest{
+			TeamName:    teamName,
+			Timezone:    "Asia/Dubai",
+			WeekendDays: []string{"friday", "saturday"},
+		})
+		if err != nil {
+			t.Fatalf("Failed to set calendar: %v", err)
+		}
+		if resp.Calendar.Timezone != "Asia/Dubai" || fmt.Sprint(resp.Calendar.WeekendDays) != "[friday saturday]" {
+			t.Errorf("Expected a Friday and Saturday weekend in Dubai, got %+v", resp.Calendar)
+		}
+	})
+
+	t.Run("Error - Lead from another team", func(t *testing.T) {
+		teamName := fmt.Sprintf("lead-team-%d", generateID())
+		otherUserID := fmt.Sprintf("user-%d", generateID())
+		mustCreateTeam(t, teamName, member(fmt.Sprintf("user-%d", generateID()), "Alice", true))
+		mustCreateTeam(t, teamName+"-other", member(otherUserID, "Bob", true))
+
+		_, err := apiClient.SetLead(testContext(t), &request.SetLeadRequest{TeamName: teamName, LeadID: otherUserID})
+
+		assertAPIError(t, err, http.StatusBadRequest, response.ErrorCodeValidation)
+	})
+
+	t.Run("Error - Unknown timezone", func(t *testing.T) {
+		teamName := fmt.Sprintf("calendar-team-%d", generateID())
+		mustCreateTeam(t, teamName, member(fmt.Sprintf("user-%d", generateID()), "Alice", true))
+
+		_, err := apiClient.SetCalendar(testContext(t), &request.SetCalendarRequest{
+			TeamName:    teamName,
+			Timezone:    "Mars/Olympus",
+			WeekendDays: []string{},
+		})
+
+		assertAPIError(t, err, http.StatusBadRequest, response.ErrorCodeValidation)
 	})
 }
 
@@ -182,3 +291,138 @@ func TestTeamGet(t *testing.T) {
 func generateID() int64 {
 	return time.Now().UnixNano()
 }
+
+// TestTeamCodeOwners tests POST /team/codeowners/upload, GET /team/codeowners and
+// code owner selection in POST /pullRequest/create
+func TestTeamCodeOwners(t *testing.T) {
+	t.Run("Success - Owner of changed files reviews", func(t *testing.T) {
+		suffix := generateID()
+		teamName := fmt.Sprintf("owners-team-%d", suffix)
+		otherTeam := fmt.Sprintf("owners-other-%d", suffix)
+		authorID := fmt.Sprintf("owners-author-%d", suffix)
+		ownerID := fmt.Sprintf("owners-owner-%d", suffix)
+		mustCreateTeam(t, teamName,
+			member(authorID, "Author", true),
+			member(ownerID, "Owner", true),
+			member(fmt.Sprintf("owners-mate-%d", suffix), "Mate", true),
+			member(fmt.Sprintf("owners-mate2-%d", suffix), "Mate2", true),
+		)
+		outsiderID := fmt.Sprintf("owners-outsider-%d", suffix)
+		mustCreateTeam(t, otherTeam, member(outsiderID, "Outsider", true))
+
+		file := fmt.Sprintf("# API layer\n/api/ @%s\ndocs/ @team/%s\n", ownerID, otherTeam)
+
+		dryRun, err := apiClient.UploadCodeOwners(testContext(t), teamName, []byte(file), true)
+		if err != nil {
+			t.Fatalf("Failed to validate CODEOWNERS: %v", err)
+		}
+		if !dryRun.DryRun || len(dryRun.Rules) != 2 || dryRun.Rules[0].Line != 2 || dryRun.Rules[0].Owners[0] != "@"+ownerID {
+			t.Fatalf("Unexpected dry run result: %+v", dryRun)
+		}
+		stored, err := apiClient.GetCodeOwners(testContext(t), teamName)
+		if err != nil {
+			t.Fatalf("Failed to get CODEOWNERS: %v", err)
+		}
+		if len(stored.Rules) != 0 {
+			t.Fatalf("Expected no stored rules after a dry run, got %+v", stored.Rules)
+		}
+
+		if _, err := apiClient.UploadCodeOwners(testContext(t), teamName, []byte(file), false); err != nil {
+			t.Fatalf("Failed to upload CODEOWNERS: %v", err)
+		}
+		stored, err = apiClient.GetCodeOwners(testContext(t), teamName)
+		if err != nil {
+			t.Fatalf("Failed to get CODEOWNERS: %v", err)
+		}
+		if len(stored.Rules) != 2 || stored.Rules[1].Pattern != "docs/" || stored.Rules[1].Owners[0] != "@team/"+otherTeam {
+			t.Fatalf("Unexpected stored rules: %+v", stored.Rules)
+		}
+
+		for i, tc := range []struct {
+			files []string
+			owner string
+		}{
+			{files: []string{"api/handler.go"}, owner: ownerID},
+			{files: []string{"docs/intro.md"}, owner: outsiderID},
+		} {
+			resp, err := apiClient.CreatePR(testContext(t), &request.CreatePRRequest{
+				PullRequestID:   fmt.Sprintf("pr-owners-%d-%d", suffix, i),
+				PullRequestName: "Owners PR",
+				AuthorID:        authorID,
+				ChangedFiles:    tc.files,
+			})
+			if err != nil {
+				t.Fatalf("Failed to create PR: %v", err)
+			}
+			reviewers := resp.PR.AssignedReviewers
+			if len(reviewers) != 2 || (reviewers[0] != tc.owner && reviewers[1] != tc.owner) {
+				t.Errorf("Expected %s among the reviewers of %v, got %v", tc.owner, tc.files, reviewers)
+			}
+		}
+	})
+
+	t.Run("Error - Unknown owner", func(t *testing.T) {
+		teamName := fmt.Sprintf("owners-team-%d", generateID())
+		mustCreateTeam(t, teamName, member(fmt.Sprintf("user-%d", generateID()), "Alice", true))
+
+		_, err := apiClient.UploadCodeOwners(testContext(t), teamName, []byte("* @nonexistent-user\n"), false)
+		assertAPIError(t, err, http.StatusBadRequest, response.ErrorCodeValidation)
+
+		_, err = apiClient.UploadCodeOwners(testContext(t), teamName, []byte("!vendor/\n"), true)
+		assertAPIError(t, err, http.StatusBadRequest, response.ErrorCodeValidation)
+	})
+
+	t.Run("Error - Team not found", func(t *testing.T) {
+		_, err := apiClient.UploadCodeOwners(testContext(t), "nonexistent-team", []byte("* @someone\n"), false)
+		assertAPIError(t, err, http.StatusNotFound, response.ErrorCodeNotFound)
+
+		_, err = apiClient.GetCodeOwners(testContext(t), "nonexistent-team")
+		assertAPIError(t, err, http.StatusNotFound, response.ErrorCodeNotFound)
+	})
+}
+
+// TestTeamSetSeniorityPolicy tests POST /team/setSeniorityPolicy endpoint
+func TestTeamSetSeniorityPolicy(t *testing.T) {
+	t.Run("Success - Set and remove the policy", func(t *testing.T) {
+		teamName := fmt.Sprintf("policy-team-%d", time.Now().UnixNano())
+		userID := fmt.Sprintf("user-%d", time.Now().UnixNano())
+		senior := member(userID, "Senior", true)
+		senior.Seniority = "senior"
+		mustCreateTeam(t, teamName, senior)
+
+		if _, err := apiClient.SetSeniorityPolicy(testContext(t), &request.SetSeniorityPolicyRequest{TeamName: teamName, MinReviewerSeniority: "senior"}); err != nil {
+			t.Fatalf("Failed to set seniority policy: %v", err)
+		}
+		team, err := apiClient.GetTeam(testContext(t), teamName)
+		if err != nil {
+			t.Fatalf("Failed to get team: %v", err)
+		}
+		if team.MinReviewerSeniority != "senior" || team.Members[0].Seniority != "senior" {
+			t.Errorf("Expected the policy and the member level, got %+v", team)
+		}
+
+		if _, err := apiClient.SetSeniorityPolicy(testContext(t), &request.SetSeniorityPolicyRequest{TeamName: teamName}); err != nil {
+			t.Fatalf("Failed to remove seniority policy: %v", err)
+		}
+		team, err = apiClient.GetTeam(testContext(t), teamName)
+		if err != nil {
+			t.Fatalf("Failed to get team: %v", err)
+		}
+		if team.MinReviewerSeniority != "" {
+			t.Errorf("Expected no policy, got %q", team.MinReviewerSeniority)
+		}
+	})
+
+	t.Run("Error - Unknown level", func(t *testing.T) {
+		_, err := apiClient.SetSeniorityPolicy(testContext(t), &request.SetSeniorityPolicyRequest{TeamName: "any-team", MinReviewerSeniority: "staff"})
+		assertAPIError(t, err, http.StatusBadRequest, response.ErrorCodeValidation)
+
+		_, err = apiClient.SetSeniority(testContext(t), &request.SetSeniorityRequest{UserID: "any-user", Seniority: "lead"})
+		assertAPIError(t, err, http.StatusBadRequest, response.ErrorCodeValidation)
+	})
+
+	t.Run("Error - Team not found", func(t *testing.T) {
+		_, err := apiClient.SetSeniorityPolicy(testContext(t), &request.SetSeniorityPolicyRequest{TeamName: "nonexistent-team", MinReviewerSeniority: "senior"})
+		assertAPIError(t, err, http.StatusNotFound, response.ErrorCodeNotFound)
+	})
+}
@@ -3,8 +3,12 @@ package e2e
 import (
 	"fmt"
 	"net/http"
+	"strings"
 	"testing"
 	"time"
+
+	"avito-backend-trainee-assignment-autumn-2025/pkg/dto/request"
+	"avito-backend-trainee-assignment-autumn-2025/pkg/dto/response"
 )
 
 // TestUserSetActive tests POST /users/setIsActive endpoint
@@ -14,56 +18,27 @@ func TestUserSetActive(t *testing.T) {
 		teamName := fmt.Sprintf("user-team-%d", time.Now().UnixNano())
 		userID := fmt.Sprintf("user-%d", time.Now().UnixNano())
 
-		createBody := map[string]interface{}{
-			"team_name": teamName,
-			"members": []map[string]interface{}{
-				{"user_id": userID, "username": "TestUser", "is_active": true},
-			},
-		}
-
-		resp, err := doRequest(http.MethodPost, "/team/add", createBody)
-		if err != nil {
-			t.Fatalf("Failed to create team: %v", err)
-		}
-		resp.Body.Close()
+		mustCreateTeam(t, teamName, member(userID, "TestUser", true))
 
 		// Set user to inactive
-		setActiveBody := map[string]interface{}{
-			"user_id":   userID,
-			"is_active": false,
-		}
-
-		resp, err = doRequest(http.MethodPost, "/users/setIsActive", setActiveBody)
+		resp, err := apiClient.SetUserActive(testContext(t), &request.SetUserActiveRequest{
+			UserID:   userID,
+			IsActive: false,
+		})
 		if err != nil {
 			t.Fatalf("Failed to set user active: %v", err)
 		}
-		defer resp.Body.Close()
 
-		assertStatusCode(t, resp, http.StatusOK)
-
-		var response struct {
-			User struct {
-				UserID   string `json:"user_id"`
-				Username string `json:"username"`
-				TeamName string `json:"team_name"`
-				IsActive bool   `json:"is_active"`
-			} `json:"user"`
-		}
-
-		if err := parseResponse(resp, &response); err != nil {
-			t.Fatalf("Failed to parse response: %v", err)
+		if resp.User.UserID != userID {
+			t.Errorf("Expected user_id %s, got %s", userID, resp.User.UserID)
 		}
 
-		if response.User.UserID != userID {
-			t.Errorf("Expected user_id %s, got %s", userID, response.User.UserID)
-		}
-
-		if response.User.IsActive {
+		if resp.User.IsActive {
 			t.Error("Expected is_active to be false")
 		}
 
-		if response.User.TeamName != teamName {
-			t.Errorf("Expected team_name %s, got %s", teamName, response.User.TeamName)
+		if resp.User.TeamName != teamName {
+			t.Errorf("Expected team_name %s, got %s", teamName, resp.User.TeamName)
 		}
 	})
 
@@ -72,204 +47,648 @@ func TestUserSetActive(t *testing.T) {
 		teamName := fmt.Sprintf("user-team-%d", time.Now().UnixNano())
 		userID := fmt.Sprintf("user-%d", time.Now().UnixNano())
 
-		createBody := map[string]interface{}{
-			"team_name": teamName,
-			"members": []map[string]interface{}{
-				{"user_id": userID, "username": "TestUser", "is_active": false},
-			},
-		}
+		mustCreateTeam(t, teamName, member(userID, "TestUser", false))
 
-		resp, err := doRequest(http.MethodPost, "/team/add", createBody)
+		// Set user to active
+		resp, err := apiClient.SetUserActive(testContext(t), &request.SetUserActiveRequest{
+			UserID:   userID,
+			IsActive: true,
+		})
 		if err != nil {
-			t.Fatalf("Failed to create team: %v", err)
+			t.Fatalf("Failed to set user active: %v", err)
 		}
-		resp.Body.Close()
 
-		// Set user to active
-		setActiveBody := map[string]interface{}{
-			"user_id":   userID,
-			"is_active": true,
+		if !resp.User.IsActive {
+			t.Error("Expected is_active to be true")
 		}
+	})
+
+	t.Run("Error - User not found", func(t *testing.T) {
+		_, err := apiClient.SetUserActive(testContext(t), &request.SetUserActiveRequest{
+			UserID:   "nonexistent-user",
+			IsActive: true,
+		})
 
-		resp, err = doRequest(http.MethodPost, "/users/setIsActive", setActiveBody)
+		assertAPIError(t, err, http.StatusNotFound, response.ErrorCodeNotFound)
+	})
+}
+
+// TestUserGetReviews tests GET /users/getReview endpoint
+func TestUserGetReviews(t *testing.T) {
+	t.Run("Success - Get user reviews (no PRs)", func(t *testing.T) {
+		// Create a team with a user
+		teamName := fmt.Sprintf("review-team-%d", time.Now().UnixNano())
+		userID := fmt.Sprintf("user-%d", time.Now().UnixNano())
+
+		mustCreateTeam(t, teamName, member(userID, "Reviewer", true))
+
+		// Get user reviews
+		resp, err := apiClient.GetUserReviews(testContext(t), userID)
 		if err != nil {
-			t.Fatalf("Failed to set user active: %v", err)
+			t.Fatalf("Failed to get user reviews: %v", err)
 		}
-		defer resp.Body.Close()
 
-		assertStatusCode(t, resp, http.StatusOK)
+		if resp.UserID != userID {
+			t.Errorf("Expected user_id %s, got %s", userID, resp.UserID)
+		}
 
-		var response struct {
-			User struct {
-				IsActive bool `json:"is_active"`
-			} `json:"user"`
+		if len(resp.PullRequests) != 0 {
+			t.Errorf("Expected 0 pull requests, got %d", len(resp.PullRequests))
 		}
+	})
 
-		if err := parseResponse(resp, &response); err != nil {
-			t.Fatalf("Failed to parse response: %v", err)
+	t.Run("Success - Get user reviews (with PRs)", func(t *testing.T) {
+		// Create a team with multiple users
+		teamName := fmt.Sprintf("review-team-%d", time.Now().UnixNano())
+		authorID := fmt.Sprintf("author-%d", time.Now().UnixNano())
+		reviewerID := fmt.Sprintf("reviewer-%d", time.Now().UnixNano())
+		reviewer2ID := fmt.Sprintf("reviewer2-%d", time.Now().UnixNano())
+
+		mustCreateTeam(t, teamName,
+			member(authorID, "Author", true),
+			member(reviewerID, "Reviewer", true),
+			member(reviewer2ID, "Reviewer2", true),
+		)
+
+		// Create PRs
+		pr1ID := fmt.Sprintf("pr-%d-1", time.Now().UnixNano())
+		pr2ID := fmt.Sprintf("pr-%d-2", time.Now().UnixNano())
+
+		mustCreatePR(t, pr1ID, "Feature A", authorID)
+		mustCreatePR(t, pr2ID, "Feature B", authorID)
+
+		// Get reviewer's reviews
+		resp, err := apiClient.GetUserReviews(testContext(t), reviewerID)
+		if err != nil {
+			t.Fatalf("Failed to get user reviews: %v", err)
 		}
 
-		if !response.User.IsActive {
-			t.Error("Expected is_active to be true")
+		if resp.UserID != reviewerID {
+			t.Errorf("Expected user_id %s, got %s", reviewerID, resp.UserID)
 		}
+
+		// User should be assigned to at least some PRs (randomized, so can't guarantee exact count)
+		t.Logf("Reviewer %s is assigned to %d PR(s)", reviewerID, len(resp.PullRequests))
 	})
 
 	t.Run("Error - User not found", func(t *testing.T) {
-		setActiveBody := map[string]interface{}{
-			"user_id":   "nonexistent-user",
-			"is_active": true,
+		_, err := apiClient.GetUserReviews(testContext(t), "nonexistent-user")
+
+		assertAPIError(t, err, http.StatusNotFound, response.ErrorCodeNotFound)
+	})
+}
+
+// TestUserSetMentionHandle tests POST /users/setMentionHandle endpoint
+func TestUserSetMentionHandle(t *testing.T) {
+	t.Run("Success - Set and clear mention handle", func(t *testing.T) {
+		teamName := fmt.Sprintf("mention-team-%d", time.Now().UnixNano())
+		userID := fmt.Sprintf("user-%d", time.Now().UnixNano())
+
+		mustCreateTeam(t, teamName, member(userID, "TestUser", true))
+
+		resp, err := apiClient.SetMentionHandle(testContext(t), &request.SetMentionHandleRequest{
+			UserID:        userID,
+			MentionHandle: "U012AB3CD",
+		})
+		if err != nil {
+			t.Fatalf("Failed to set mention handle: %v", err)
+		}
+		if resp.User.MentionHandle != "U012AB3CD" {
+			t.Errorf("Expected mention_handle U012AB3CD, got %q", resp.User.MentionHandle)
 		}
 
-		resp, err := doRequest(http.MethodPost, "/users/setIsActive", setActiveBody)
+		team, err := apiClient.GetTeam(testContext(t), teamName)
 		if err != nil {
-			t.Fatalf("Failed to make request: %v", err)
+			t.Fatalf("Failed to get team: %v", err)
 		}
+		if len(team.Members) != 1 || team.Members[0].MentionHandle != "U012AB3CD" {
+			t.Errorf("Expected team member with mention handle, got %+v", team.Members)
+		}
+
+		resp, err = apiClient.SetMentionHandle(testContext(t), &request.SetMentionHandleRequest{UserID: userID})
+		if err != nil {
+			t.Fatalf("Failed to clear mention handle: %v", err)
+		}
+		if resp.User.MentionHandle != "" {
+			t.Errorf("Expected empty mention_handle, got %q", resp.User.MentionHandle)
+		}
+	})
+
+	t.Run("Error - Invalid handle", func(t *testing.T) {
+		status, body := doRawRequest(t, http.MethodPost, "/users/setMentionHandle", map[string]string{
+			"user_id":        "any-user",
+			"mention_handle": "@alice",
+		})
+
+		if status != http.StatusBadRequest {
+			t.Errorf("Expected status %d, got %d", http.StatusBadRequest, status)
+		}
+		if body.Error.Code != response.ErrorCodeValidation {
+			t.Errorf("Expected error code %s, got %s", response.ErrorCodeValidation, body.Error.Code)
+		}
+	})
+
+	t.Run("Error - User not found", func(t *testing.T) {
+		_, err := apiClient.SetMentionHandle(testContext(t), &request.SetMentionHandleRequest{
+			UserID:        "nonexistent-user",
+			MentionHandle: "U012AB3CD",
+		})
 
-		assertStatusCode(t, resp, http.StatusNotFound)
-		assertErrorCode(t, resp, "NOT_FOUND")
+		assertAPIError(t, err, http.StatusNotFound, response.ErrorCodeNotFound)
 	})
 }
 
-// TestUserGetReviews tests GET /users/getReview endpoint
-func TestUserGetReviews(t *testing.T) {
-	t.Run("Success - Get user reviews (no PRs)", func(t *testing.T) {
-		// Create a team with a user
-		teamName := fmt.Sprintf("review-team-%d", time.Now().UnixNano())
+// TestUserSetEmail tests POST /users/setEmail endpoint
+func TestUserSetEmail(t *testing.T) {
+	t.Run("Success - Set and clear email", func(t *testing.T) {
+		teamName := fmt.Sprintf("email-team-%d", time.Now().UnixNano())
 		userID := fmt.Sprintf("user-%d", time.Now().UnixNano())
 
-		createBody := map[string]interface{}{
-			"team_name": teamName,
-			"members": []map[string]interface{}{
-				{"user_id": userID, "username": "Reviewer", "is_active": true},
-			},
+		mustCreateTeam(t, teamName, member(userID, "TestUser", true))
+
+		resp, err := apiClient.SetEmail(testContext(t), &request.SetEmailRequest{
+			UserID: userID,
+			Email:  "test.user@example.com",
+		})
+		if err != nil {
+			t.Fatalf("Failed to set email: %v", err)
+		}
+		if resp.User.Email != "test.user@example.com" {
+			t.Errorf("Expected email test.user@example.com, got %q", resp.User.Email)
 		}
 
-		resp, err := doRequest(http.MethodPost, "/team/add", createBody)
+		team, err := apiClient.GetTeam(testContext(t), teamName)
 		if err != nil {
-			t.Fatalf("Failed to create team: %v", err)
+			t.Fatalf("Failed to get team: %v", err)
+		}
+		if len(team.Members) != 1 || team.Members[0].Email != "test.user@example.com" {
+			t.Errorf("Expected team member with email, got %+v", team.Members)
 		}
-		resp.Body.Close()
 
-		// Get user reviews
-		resp, err = doGet("/users/getReview", map[string]string{"user_id": userID})
+		resp, err = apiClient.SetEmail(testContext(t), &request.SetEmailRequest{UserID: userID})
 		if err != nil {
-			t.Fatalf("Failed to get user reviews: %v", err)
+			t.Fatalf("Failed to clear email: %v", err)
+		}
+		if resp.User.Email != "" {
+			t.Errorf("Expected empty email, got %q", resp.User.Email)
 		}
-		defer resp.Body.Close()
+	})
 
-		assertStatusCode(t, resp, http.StatusOK)
+	t.Run("Error - Invalid email", func(t *testing.T) {
+		_, err := apiClient.SetEmail(testContext(t), &request.SetEmailRequest{
+			UserID: "any-user",
+			Email:  "not-an-email",
+		})
 
-		var response struct {
-			UserID       string `json:"user_id"`
-			PullRequests []struct {
-				PullRequestID   string `json:"pull_request_id"`
-				PullRequestName string `json:"pull_request_name"`
-				AuthorID        string `json:"author_id"`
-				Status          string `json:"status"`
-			} `json:"pull_requests"`
+		assertAPIError(t, err, http.StatusBadRequest, response.ErrorCodeValidation)
+	})
+
+	t.Run("Error - User not found", func(t *testing.T) {
+		_, err := apiClient.SetEmail(testContext(t), &request.SetEmailRequest{
+			UserID: "nonexistent-user",
+			Email:  "ghost@example.com",
+		})
+
+		assertAPIError(t, err, http.StatusNotFound, response.ErrorCodeNotFound)
+	})
+}
+
+// TestUserSetWorkingHours tests POST /users/setWorkingHours and POST /team/setWorkingHoursPreference
+func TestUserSetWorkingHours(t *testing.T) {
+	t.Run("Success - Set working hours and team preference", func(t *testing.T) {
+		teamName := fmt.Sprintf("hours-team-%d", time.Now().UnixNano())
+		userID := fmt.Sprintf("user-%d", time.Now().UnixNano())
+
+		mustCreateTeam(t, teamName, member(userID, "TestUser", true))
+
+		resp, err := apiClient.SetWorkingHours(testContext(t), &request.SetWorkingHoursRequest{
+			UserID:    userID,
+			Timezone:  "Europe/Moscow",
+			WorkStart: "10:00",
+			WorkEnd:   "19:00",
+		})
+		if err != nil {
+			t.Fatalf("Failed to set working hours: %v", err)
+		}
+		if resp.User.Timezone != "Europe/Moscow" || resp.User.WorkStart != "10:00" || resp.User.WorkEnd != "19:00" {
+			t.Errorf("Expected 10:00-19:00 in Moscow, got %+v", resp.User)
 		}
 
-		if err := parseResponse(resp, &response); err != nil {
-			t.Fatalf("Failed to parse response: %v", err)
+		prefResp, err := apiClient.SetWorkingHoursPreference(testContext(t), &request.SetWorkingHoursPreferenceRequest{
+			TeamName:           teamName,
+			PreferWorkingHours: true,
+			WithinHours:        2,
+		})
+		if err != nil {
+			t.Fatalf("Failed to set working hours preference: %v", err)
+		}
+		if !prefResp.PreferWorkingHours || prefResp.PreferWithinHours != 2 {
+			t.Errorf("Expected the preference within 2 hours, got %+v", prefResp)
 		}
 
-		if response.UserID != userID {
-			t.Errorf("Expected user_id %s, got %s", userID, response.UserID)
+		team, err := apiClient.GetTeam(testContext(t), teamName)
+		if err != nil {
+			t.Fatalf("Failed to get team: %v", err)
+		}
+		if !team.PreferWorkingHours || team.PreferWithinHours != 2 {
+			t.Errorf("Expected the team to prefer working hours, got %+v", team)
+		}
+		if len(team.Members) != 1 || team.Members[0].WorkStart != "10:00" || team.Members[0].Timezone != "Europe/Moscow" {
+			t.Errorf("Expected team member with working hours, got %+v", team.Members)
 		}
 
-		if len(response.PullRequests) != 0 {
-			t.Errorf("Expected 0 pull requests, got %d", len(response.PullRequests))
+		resp, err = apiClient.SetWorkingHours(testContext(t), &request.SetWorkingHoursRequest{UserID: userID})
+		if err != nil {
+			t.Fatalf("Failed to clear working hours: %v", err)
+		}
+		if resp.User.WorkStart != "" || resp.User.WorkEnd != "" {
+			t.Errorf("Expected no working hours, got %+v", resp.User)
 		}
 	})
 
-	t.Run("Success - Get user reviews (with PRs)", func(t *testing.T) {
-		// Create a team with multiple users
-		teamName := fmt.Sprintf("review-team-%d", time.Now().UnixNano())
+	t.Run("Error - Invalid working hours", func(t *testing.T) {
+		_, err := apiClient.SetWorkingHours(testContext(t), &request.SetWorkingHoursRequest{
+			UserID:    "any-user",
+			WorkStart: "09:00",
+			WorkEnd:   "09:00",
+		})
+
+		assertAPIError(t, err, http.StatusBadRequest, response.ErrorCodeValidation)
+	})
+
+	t.Run("Error - User not found", func(t *testing.T) {
+		_, err := apiClient.SetWorkingHours(testContext(t), &request.SetWorkingHoursRequest{
+			UserID:    "nonexistent-user",
+			WorkStart: "09:00",
+			WorkEnd:   "18:00",
+		})
+
+		assertAPIError(t, err, http.StatusNotFound, response.ErrorCodeNotFound)
+	})
+
+	t.Run("Error - Team not found", func(t *testing.T) {
+		_, err := apiClient.SetWorkingHoursPreference(testContext(t), &request.SetWorkingHoursPreferenceRequest{
+			TeamName:           "nonexistent-team",
+			PreferWorkingHours: true,
+		})
+
+		assertAPIError(t, err, http.StatusNotFound, response.ErrorCodeNotFound)
+	})
+}
+
+// TestUserAbsences tests the /users/absences/* endpoints
+func TestUserAbsences(t *testing.T) {
+	t.Run("Success - Absent user is not assigned", func(t *testing.T) {
+		teamName := fmt.Sprintf("absence-team-%d", time.Now().UnixNano())
 		authorID := fmt.Sprintf("author-%d", time.Now().UnixNano())
+		awayID := fmt.Sprintf("away-%d", time.Now().UnixNano())
 		reviewerID := fmt.Sprintf("reviewer-%d", time.Now().UnixNano())
-		reviewer2ID := fmt.Sprintf("reviewer2-%d", time.Now().UnixNano())
 
-		createBody := map[string]interface{}{
-			"team_name": teamName,
-			"members": []map[string]interface{}{
-				{"user_id": authorID, "username": "Author", "is_active": true},
-				{"user_id": reviewerID, "username": "Reviewer", "is_active": true},
-				{"user_id": reviewer2ID, "username": "Reviewer2", "is_active": true},
-			},
+		mustCreateTeam(t, teamName,
+			member(authorID, "Author", true),
+			member(awayID, "Away", true),
+			member(reviewerID, "Reviewer", true),
+		)
+
+		// A day of margin on each side keeps the test independent of the server timezone
+		today := time.Now().UTC()
+		added, err := apiClient.AddAbsence(testContext(t), &request.AddAbsenceRequest{
+			UserID:    awayID,
+			StartDate: today.AddDate(0, 0, -1).Format("2006-01-02"),
+			EndDate:   today.AddDate(0, 0, 1).Format("2006-01-02"),
+			Reason:    "vacation",
+		})
+		if err != nil {
+			t.Fatalf("Failed to add absence: %v", err)
+		}
+		if added.Absence.AbsenceID == "" || added.Absence.UserID != awayID || added.Absence.Reason != "vacation" {
+			t.Errorf("Unexpected absence: %+v", added.Absence)
+		}
+
+		for i := 0; i < 3; i++ {
+			pr := mustCreatePR(t, fmt.Sprintf("pr-absence-%d-%d", time.Now().UnixNano(), i), "Absence PR", authorID)
+			if len(pr.PR.AssignedReviewers) != 1 || pr.PR.AssignedReviewers[0] != reviewerID {
+				t.Fatalf("Expected only %s to be assigned, got %v", reviewerID, pr.PR.AssignedReviewers)
+			}
 		}
 
-		resp, err := doRequest(http.MethodPost, "/team/add", createBody)
+		team, err := apiClient.GetTeam(testContext(t), teamName)
 		if err != nil {
-			t.Fatalf("Failed to create team: %v", err)
+			t.Fatalf("Failed to get team: %v", err)
+		}
+		for _, m := range team.Members {
+			if !m.IsActive {
+				t.Errorf("Expected %s to stay active", m.UserID)
+			}
+			if m.UserID == awayID && (len(m.Absences) != 1 || m.Absences[0].AbsenceID != added.Absence.AbsenceID) {
+				t.Errorf("Expected the absence on the team member, got %+v", m.Absences)
+			}
 		}
-		resp.Body.Close()
 
-		// Create PRs
-		pr1ID := fmt.Sprintf("pr-%d-1", time.Now().UnixNano())
-		pr2ID := fmt.Sprintf("pr-%d-2", time.Now().UnixNano())
+		list, err := apiClient.ListAbsences(testContext(t), awayID)
+		if err != nil {
+			t.Fatalf("Failed to list absences: %v", err)
+		}
+		if len(list.Absences) != 1 {
+			t.Fatalf("Expected 1 absence, got %+v", list.Absences)
+		}
 
-		prBody1 := map[string]interface{}{
-			"pull_request_id":   pr1ID,
-			"pull_request_name": "Feature A",
-			"author_id":         authorID,
+		if _, err := apiClient.DeleteAbsence(testContext(t), &request.DeleteAbsenceRequest{AbsenceID: added.Absence.AbsenceID}); err != nil {
+			t.Fatalf("Failed to delete absence: %v", err)
 		}
+		list, err = apiClient.ListAbsences(testContext(t), awayID)
+		if err != nil {
+			t.Fatalf("Failed to list absences: %v", err)
+		}
+		if len(list.Absences) != 0 {
+			t.Errorf("Expected no absences after delete, got %+v", list.Absences)
+		}
+	})
 
-		resp, err = doRequest(http.MethodPost, "/pullRequest/create", prBody1)
+	t.Run("Success - Import calendar", func(t *testing.T) {
+		teamName := fmt.Sprintf("ics-team-%d", time.Now().UnixNano())
+		authorID := fmt.Sprintf("author-%d", time.Now().UnixNano())
+		awayID := fmt.Sprintf("away-%d", time.Now().UnixNano())
+		reviewerID := fmt.Sprintf("reviewer-%d", time.Now().UnixNano())
+
+		mustCreateTeam(t, teamName,
+			member(authorID, "Author", true),
+			member(awayID, "Away", true),
+			member(reviewerID, "Reviewer", true),
+		)
+
+		today := time.Now().UTC()
+		calendar := strings.Join([]string{
+			"BEGIN:VCALENDAR",
+			"VERSION:2.0",
+			"PRODID:-//e2e//EN",
+			"BEGIN:VEVENT",
+			"UID:ooo@example.com",
+			"SUMMARY:Out of office",
+			"DTSTART;VALUE=DATE:" + today.AddDate(0, 0, -1).Format("20060102"),
+			"DTEND;VALUE=DATE:" + today.AddDate(0, 0, 2).Format("20060102"),
+			"END:VEVENT",
+			"BEGIN:VEVENT",
+			"UID:course@example.com",
+			"SUMMARY:Course",
+			"DTSTART;VALUE=DATE:" + today.AddDate(0, 0, 7).Format("20060102"),
+			"RRULE:FREQ=WEEKLY;COUNT=3",
+			"END:VEVENT",
+			"END:VCALENDAR",
+		}, "\r\n")
+
+		imported, err := apiClient.ImportAbsences(testContext(t), awayID, []byte(calendar))
 		if err != nil {
-			t.Fatalf("Failed to create PR 1: %v", err)
+			t.Fatalf("Failed to import absences: %v", err)
+		}
+		if imported.Removed != 0 || len(imported.Absences) != 4 {
+			t.Fatalf("Expected 4 new absences, got %+v", imported)
+		}
+		if imported.Absences[0].SourceUID != "ooo@example.com" || imported.Absences[0].Reason != "Out of office" {
+			t.Errorf("Unexpected first absence: %+v", imported.Absences[0])
 		}
-		resp.Body.Close()
 
-		prBody2 := map[string]interface{}{
-			"pull_request_id":   pr2ID,
-			"pull_request_name": "Feature B",
-			"author_id":         authorID,
+		pr := mustCreatePR(t, fmt.Sprintf("pr-ics-%d", time.Now().UnixNano()), "ICS PR", authorID)
+		if len(pr.PR.AssignedReviewers) != 1 || pr.PR.AssignedReviewers[0] != reviewerID {
+			t.Fatalf("Expected only %s to be assigned, got %v", reviewerID, pr.PR.AssignedReviewers)
 		}
 
-		resp, err = doRequest(http.MethodPost, "/pullRequest/create", prBody2)
+		// Importing the same file again replaces the absences instead of duplicating them
+		imported, err = apiClient.ImportAbsences(testContext(t), awayID, []byte(calendar))
 		if err != nil {
-			t.Fatalf("Failed to create PR 2: %v", err)
+			t.Fatalf("Failed to re-import absences: %v", err)
 		}
-		resp.Body.Close()
-
-		// Get reviewer's reviews
-		resp, err = doGet("/users/getReview", map[string]string{"user_id": reviewerID})
+		if imported.Removed != 4 || len(imported.Absences) != 4 {
+			t.Errorf("Expected 4 absences to be replaced, got %+v", imported)
+		}
+		list, err := apiClient.ListAbsences(testContext(t), awayID)
 		if err != nil {
-			t.Fatalf("Failed to get user reviews: %v", err)
+			t.Fatalf("Failed to list absences: %v", err)
+		}
+		if len(list.Absences) != 4 {
+			t.Errorf("Expected 4 absences, got %+v", list.Absences)
 		}
-		defer resp.Body.Close()
+	})
 
-		assertStatusCode(t, resp, http.StatusOK)
+	t.Run("Error - Invalid calendar", func(t *testing.T) {
+		teamName := fmt.Sprintf("ics-team-%d", time.Now().UnixNano())
+		userID := fmt.Sprintf("user-%d", time.Now().UnixNano())
+		mustCreateTeam(t, teamName, member(userID, "TestUser", true))
 
-		var response struct {
-			UserID       string `json:"user_id"`
-			PullRequests []struct {
-				PullRequestID   string `json:"pull_request_id"`
-				PullRequestName string `json:"pull_request_name"`
-				AuthorID        string `json:"author_id"`
-				Status          string `json:"status"`
-			} `json:"pull_requests"`
-		}
+		_, err := apiClient.ImportAbsences(testContext(t), userID, []byte("not a calendar"))
+		assertAPIError(t, err, http.StatusBadRequest, response.ErrorCodeBadRequest)
+
+		_, err = apiClient.ImportAbsences(testContext(t), userID, []byte("BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\nUID:a\r\nEND:VEVENT\r\nEND:VCALENDAR\r\n"))
+		assertAPIError(t, err, http.StatusBadRequest, response.ErrorCodeValidation)
 
-		if err := parseResponse(resp, &response); err != nil {
-			t.Fatalf("Failed to parse response: %v", err)
+		_, err = apiClient.ImportAbsences(testContext(t), "nonexistent-user", []byte("BEGIN:VCALENDAR\r\nEND:VCALENDAR\r\n"))
+		assertAPIError(t, err, http.StatusNotFound, response.ErrorCodeNotFound)
+	})
+
+	t.Run("Error - End before start", func(t *testing.T) {
+		_, err := apiClient.AddAbsence(testContext(t), &request.AddAbsenceRequest{
+			UserID:    "any-user",
+			StartDate: "2025-11-10",
+			EndDate:   "2025-11-09",
+		})
+
+		assertAPIError(t, err, http.StatusBadRequest, response.ErrorCodeValidation)
+	})
+
+	t.Run("Error - User not found", func(t *testing.T) {
+		_, err := apiClient.AddAbsence(testContext(t), &request.AddAbsenceRequest{
+			UserID:    "nonexistent-user",
+			StartDate: "2025-11-10",
+			EndDate:   "2025-11-10",
+		})
+		assertAPIError(t, err, http.StatusNotFound, response.ErrorCodeNotFound)
+
+		_, err = apiClient.ListAbsences(testContext(t), "nonexistent-user")
+		assertAPIError(t, err, http.StatusNotFound, response.ErrorCodeNotFound)
+	})
+
+	t.Run("Error - Absence not found", func(t *testing.T) {
+		_, err := apiClient.DeleteAbsence(testContext(t), &request.DeleteAbsenceRequest{AbsenceID: "nonexistent-absence"})
+
+		assertAPIError(t, err, http.StatusNotFound, response.ErrorCodeNotFound)
+	})
+}
+
+// TestUserSkillTags tests the /users/tags endpoints and tag-aware reviewer selection
+func TestUserSkillTags(t *testing.T) {
+	t.Run("Success - Manage tags and rank reviewers", func(t *testing.T) {
+		teamName := fmt.Sprintf("tags-team-%d", time.Now().UnixNano())
+		authorID := fmt.Sprintf("author-%d", time.Now().UnixNano())
+		goID := fmt.Sprintf("go-%d", time.Now().UnixNano())
+		sqlID := fmt.Sprintf("sql-%d", time.Now().UnixNano())
+		otherID := fmt.Sprintf("other-%d", time.Now().UnixNano())
+
+		gopher := member(goID, "Gopher", true)
+		gopher.SkillTags = []string{"Go"}
+		team := mustCreateTeam(t, teamName,
+			member(authorID, "Author", true),
+			gopher,
+			member(sqlID, "SQL", true),
+			member(otherID, "Other", true),
+		)
+		for _, m := range team.Team.Members {
+			if m.UserID == goID && (len(m.SkillTags) != 1 || m.SkillTags[0] != "go") {
+				t.Errorf("Expected normalized skill tags on %s, got %v", goID, m.SkillTags)
+			}
+		}
+
+		added, err := apiClient.AddSkillTags(testContext(t), &request.AddSkillTagsRequest{UserID: sqlID, Tags: []string{"sql", "Go"}})
+		if err != nil {
+			t.Fatalf("Failed to add skill tags: %v", err)
+		}
+		if strings.Join(added.User.SkillTags, ",") != "go,sql" {
+			t.Errorf("Expected tags go,sql, got %v", added.User.SkillTags)
+		}
+
+		for i := 0; i < 3; i++ {
+			pr, err := apiClient.CreatePR(testContext(t), &request.CreatePRRequest{
+				PullRequestID:   fmt.Sprintf("pr-tags-%d-%d", time.Now().UnixNano(), i),
+				PullRequestName: "Tagged PR",
+				AuthorID:        authorID,
+				RequiredTags:    []string{"sql", "go"},
+			})
+			if err != nil {
+				t.Fatalf("Failed to create PR: %v", err)
+			}
+			reviewers := pr.PR.AssignedReviewers
+			if len(reviewers) != 2 || reviewers[0] != sqlID || reviewers[1] != goID {
+				t.Fatalf("Expected reviewers [%s %s], got %v", sqlID, goID, reviewers)
+			}
+			if strings.Join(pr.PR.RequiredTags, ",") != "go,sql" {
+				t.Errorf("Expected required tags go,sql, got %v", pr.PR.RequiredTags)
+			}
+		}
+
+		removed, err := apiClient.RemoveSkillTags(testContext(t), &request.RemoveSkillTagsRequest{UserID: sqlID, Tags: []string{"go"}})
+		if err != nil {
+			t.Fatalf("Failed to remove skill tags: %v", err)
+		}
+		if strings.Join(removed.User.SkillTags, ",") != "sql" {
+			t.Errorf("Expected tags sql, got %v", removed.User.SkillTags)
 		}
 
-		if response.UserID != reviewerID {
-			t.Errorf("Expected user_id %s, got %s", reviewerID, response.UserID)
+		if _, err := apiClient.SetSkillTags(testContext(t), &request.SetSkillTagsRequest{UserID: goID, Tags: []string{"k8s", "docker"}}); err != nil {
+			t.Fatalf("Failed to set skill tags: %v", err)
+		}
+		list, err := apiClient.ListSkillTags(testContext(t), goID)
+		if err != nil {
+			t.Fatalf("Failed to list skill tags: %v", err)
+		}
+		if list.UserID != goID || strings.Join(list.SkillTags, ",") != "docker,k8s" {
+			t.Errorf("Expected tags docker,k8s, got %+v", list)
 		}
+	})
 
-		// User should be assigned to at least some PRs (randomized, so can't guarantee exact count)
-		t.Logf("Reviewer %s is assigned to %d PR(s)", reviewerID, len(response.PullRequests))
+	t.Run("Error - Invalid tag", func(t *testing.T) {
+		teamName := fmt.Sprintf("tags-team-%d", time.Now().UnixNano())
+		userID := fmt.Sprintf("user-%d", time.Now().UnixNano())
+		mustCreateTeam(t, teamName, member(userID, "TestUser", true))
+
+		_, err := apiClient.AddSkillTags(testContext(t), &request.AddSkillTagsRequest{UserID: userID, Tags: []string{"c sharp"}})
+		assertAPIError(t, err, http.StatusBadRequest, response.ErrorCodeValidation)
 	})
 
 	t.Run("Error - User not found", func(t *testing.T) {
-		resp, err := doGet("/users/getReview", map[string]string{"user_id": "nonexistent-user"})
+		_, err := apiClient.SetSkillTags(testContext(t), &request.SetSkillTagsRequest{UserID: "nonexistent-user", Tags: []string{"go"}})
+		assertAPIError(t, err, http.StatusNotFound, response.ErrorCodeNotFound)
+
+		_, err = apiClient.ListSkillTags(testContext(t), "nonexistent-user")
+		assertAPIError(t, err, http.StatusNotFound, response.ErrorCodeNotFound)
+	})
+}
+
+// TestUserAffinities tests the /users/affinities endpoints
+func TestUserAffinities(t *testing.T) {
+	t.Run("Success - Conflicts are never assigned", func(t *testing.T) {
+		teamName := fmt.Sprintf("affinity-team-%d", time.Now().UnixNano())
+		authorID := fmt.Sprintf("author-%d", time.Now().UnixNano())
+		conflictID := fmt.Sprintf("conflict-%d", time.Now().UnixNano())
+		favoriteID := fmt.Sprintf("favorite-%d", time.Now().UnixNano())
+		otherID := fmt.Sprintf("other-%d", time.Now().UnixNano())
+		mustCreateTeam(t, teamName,
+			member(authorID, "Author", true),
+			member(conflictID, "Conflict", true),
+			member(favoriteID, "Favorite", true),
+			member(otherID, "Other", true),
+		)
+
+		// The conflict is set from the reviewer's side and still applies to the author's PRs
+		set, err := apiClient.SetAffinity(testContext(t), &request.SetAffinityRequest{
+			AuthorID: conflictID, ReviewerID: authorID, Conflict: true, Reason: "reports to the author",
+		})
 		if err != nil {
-			t.Fatalf("Failed to make request: %v", err)
+			t.Fatalf("Failed to set affinity: %v", err)
+		}
+		if !set.Affinity.Conflict || set.Affinity.Weight != 0 || set.Affinity.Reason != "reports to the author" {
+			t.Errorf("Expected the conflict back, got %+v", set.Affinity)
+		}
+		if _, err := apiClient.SetAffinity(testContext(t), &request.SetAffinityRequest{
+			AuthorID: authorID, ReviewerID: favoriteID, Weight: 5,
+		}); err != nil {
+			t.Fatalf("Failed to set affinity: %v", err)
+		}
+
+		for i := 0; i < 3; i++ {
+			pr := mustCreatePR(t, fmt.Sprintf("pr-affinity-%d-%d", time.Now().UnixNano(), i), "Affinity PR", authorID)
+			reviewers := pr.PR.AssignedReviewers
+			if len(reviewers) != 2 || reviewers[0] == conflictID || reviewers[1] == conflictID {
+				t.Fatalf("Expected %s and %s, got %v", favoriteID, otherID, reviewers)
+			}
 		}
 
-		assertStatusCode(t, resp, http.StatusNotFound)
-		assertErrorCode(t, resp, "NOT_FOUND")
+		// With the other reviewer taken, nobody but the conflict is left to replace the favorite
+		pr := mustCreatePR(t, fmt.Sprintf("pr-affinity-%d", time.Now().UnixNano()), "Affinity PR", authorID)
+		_, err = apiClient.ReassignReviewer(testContext(t), &request.ReassignReviewerRequest{
+			PullRequestID: pr.PR.PullRequestID, OldUserID: favoriteID,
+		})
+		assertAPIError(t, err, http.StatusConflict, response.ErrorCodeNoCandidate)
+
+		list, err := apiClient.ListAffinities(testContext(t), authorID)
+		if err != nil {
+			t.Fatalf("Failed to list affinities: %v", err)
+		}
+		if list.UserID != authorID || len(list.Affinities) != 2 {
+			t.Fatalf("Expected 2 affinities of %s, got %+v", authorID, list)
+		}
+
+		deleted, err := apiClient.DeleteAffinity(testContext(t), &request.DeleteAffinityRequest{AuthorID: conflictID, ReviewerID: authorID})
+		if err != nil {
+			t.Fatalf("Failed to delete affinity: %v", err)
+		}
+		if deleted.AuthorID != conflictID || deleted.ReviewerID != authorID {
+			t.Errorf("Expected the deleted pair back, got %+v", deleted)
+		}
+		reassigned, err := apiClient.ReassignReviewer(testContext(t), &request.ReassignReviewerRequest{
+			PullRequestID: pr.PR.PullRequestID, OldUserID: favoriteID,
+		})
+		if err != nil {
+			t.Fatalf("Failed to reassign reviewer: %v", err)
+		}
+		if reassigned.ReplacedBy != conflictID {
+			t.Errorf("Expected %s to take over, got %s", conflictID, reassigned.ReplacedBy)
+		}
+	})
+
+	t.Run("Error - Invalid affinity", func(t *testing.T) {
+		teamName := fmt.Sprintf("affinity-team-%d", time.Now().UnixNano())
+		userID := fmt.Sprintf("user-%d", time.Now().UnixNano())
+		mustCreateTeam(t, teamName, member(userID, "TestUser", true))
+
+		_, err := apiClient.SetAffinity(testContext(t), &request.SetAffinityRequest{AuthorID: userID, ReviewerID: userID, Weight: 1})
+		assertAPIError(t, err, http.StatusBadRequest, response.ErrorCodeValidation)
+
+		_, err = apiClient.SetAffinity(testContext(t), &request.SetAffinityRequest{AuthorID: userID, ReviewerID: "nonexistent-user", Weight: 1})
+		assertAPIError(t, err, http.StatusNotFound, response.ErrorCodeNotFound)
+
+		_, err = apiClient.DeleteAffinity(testContext(t), &request.DeleteAffinityRequest{AuthorID: userID, ReviewerID: "nonexistent-user"})
+		assertAPIError(t, err, http.StatusNotFound, response.ErrorCodeNotFound)
+
+		_, err = apiClient.ListAffinities(testContext(t), "nonexistent-user")
+		assertAPIError(t, err, http.StatusNotFound, response.ErrorCodeNotFound)
 	})
 }
@@ -0,0 +1,127 @@
+package e2e
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"avito-backend-trainee-assignment-autumn-2025/pkg/dto/request"
+	"avito-backend-trainee-assignment-autumn-2025/pkg/dto/response"
+)
+
+// unreachableWebhookURL refuses connections, so deliveries to it keep failing
+const unreachableWebhookURL = "http://127.0.0.1:1/hook"
+
+// TestWebhookRegister tests POST /webhooks/add and GET /webhooks/list endpoints
+func TestWebhookRegister(t *testing.T) {
+	t.Run("Success - Secret is generated and not listed", func(t *testing.T) {
+		resp, err := apiClient.RegisterWebhook(testContext(t), &request.RegisterWebhookRequest{
+			URL:    unreachableWebhookURL,
+			Events: []string{"pr.merged", "pr.merged"},
+		})
+		if err != nil {
+			t.Fatalf("Failed to register webhook: %v", err)
+		}
+
+		if resp.Secret == "" {
+			t.Error("Expected a generated secret")
+		}
+		if len(resp.Webhook.Events) != 1 || resp.Webhook.Events[0] != "pr.merged" {
+			t.Errorf("Expected events [pr.merged], got %v", resp.Webhook.Events)
+		}
+
+		list, err := apiClient.ListWebhooks(testContext(t))
+		if err != nil {
+			t.Fatalf("Failed to list webhooks: %v", err)
+		}
+		found := false
+		for _, webhook := range list.Webhooks {
+			found = found || webhook.WebhookID == resp.Webhook.WebhookID
+		}
+		if !found {
+			t.Errorf("Webhook %s is not listed", resp.Webhook.WebhookID)
+		}
+	})
+
+	t.Run("Error - Unknown event type", func(t *testing.T) {
+		status, errResp := doRawRequest(t, http.MethodPost, "/webhooks/add", map[string]interface{}{
+			"url":    unreachableWebhookURL,
+			"events": []string{"pr.exploded"},
+		})
+		if status != http.StatusBadRequest || errResp.Error.Code != response.ErrorCodeValidation {
+			t.Errorf("Expected 400 %s, got %d %s", response.ErrorCodeValidation, status, errResp.Error.Code)
+		}
+	})
+
+	t.Run("Error - Relative URL", func(t *testing.T) {
+		_, err := apiClient.RegisterWebhook(testContext(t), &request.RegisterWebhookRequest{
+			URL:    "/hook",
+			Events: []string{"pr.merged"},
+		})
+		assertAPIError(t, err, http.StatusBadRequest, response.ErrorCodeValidation)
+	})
+}
+
+// TestWebhookDeliveries tests GET /webhooks/deliveries and POST /webhooks/redeliver endpoints
+func TestWebhookDeliveries(t *testing.T) {
+	t.Run("Success - Failed delivery is recorded for retry", func(t *testing.T) {
+		webhook, err := apiClient.RegisterWebhook(testContext(t), &request.RegisterWebhookRequest{
+			URL:    unreachableWebhookURL,
+			Events: []string{"user.deactivated"},
+		})
+		if err != nil {
+			t.Fatalf("Failed to register webhook: %v", err)
+		}
+
+		teamName := fmt.Sprintf("webhook-team-%d", time.Now().UnixNano())
+		userID := fmt.Sprintf("webhook-user-%d", time.Now().UnixNano())
+		mustCreateTeam(t, teamName, member(userID, "WebhookUser", true))
+
+		if _, err := apiClient.SetUserActive(testContext(t), &request.SetUserActiveRequest{UserID: userID}); err != nil {
+			t.Fatalf("Failed to deactivate user: %v", err)
+		}
+
+		// The dispatcher attempts the delivery in the background
+		var delivery *response.WebhookDeliveryResponse
+		deadline := time.Now().Add(10 * time.Second)
+		for delivery == nil && time.Now().Before(deadline) {
+			list, err := apiClient.ListWebhookDeliveries(testContext(t), "pending")
+			if err != nil {
+				t.Fatalf("Failed to list deliveries: %v", err)
+			}
+			for i := range list.Deliveries {
+				d := list.Deliveries[i]
+				if d.WebhookID == webhook.Webhook.WebhookID && d.Attempts > 0 {
+					delivery = &d
+				}
+			}
+			time.Sleep(100 * time.Millisecond)
+		}
+		if delivery == nil {
+			t.Fatal("Expected a failed delivery attempt to be recorded")
+		}
+
+		if delivery.EventType != "user.deactivated" || delivery.LastError == "" || delivery.NextAttemptAt == nil {
+			t.Errorf("Unexpected delivery: %+v", delivery)
+		}
+
+		resp, err := apiClient.RedeliverWebhook(testContext(t), &request.RedeliverWebhookRequest{DeliveryID: delivery.DeliveryID})
+		if err != nil {
+			t.Fatalf("Failed to redeliver: %v", err)
+		}
+		if resp.Delivery.Status != "pending" {
+			t.Errorf("Expected status pending, got %s", resp.Delivery.Status)
+		}
+	})
+
+	t.Run("Error - Invalid status filter", func(t *testing.T) {
+		_, err := apiClient.ListWebhookDeliveries(testContext(t), "lost")
+		assertAPIError(t, err, http.StatusBadRequest, response.ErrorCodeValidation)
+	})
+
+	t.Run("Error - Delivery not found", func(t *testing.T) {
+		_, err := apiClient.RedeliverWebhook(testContext(t), &request.RedeliverWebhookRequest{DeliveryID: "dlv_missing"})
+		assertAPIError(t, err, http.StatusNotFound, response.ErrorCodeNotFound)
+	})
+}